@@ -1,4 +1,4 @@
-import { useMutation } from "@tanstack/react-query";
+import { useMutation, useQuery } from "@tanstack/react-query";
 
 import { api } from "@/lib/api";
 
@@ -14,6 +14,26 @@ type ProxifyLinkResult = {
   url: string;
 };
 
+export type ProxyUsageHistoryItem = {
+  bytes: number;
+  date: string;
+  user: string;
+};
+
+export type ProxyUserUsage = {
+  daily: number;
+  daily_quota: number;
+  max_rate: number;
+  monthly: number;
+  monthly_quota: number;
+  user: string;
+};
+
+type ProxyUsage = {
+  history: ProxyUsageHistoryItem[];
+  users: ProxyUserUsage[];
+};
+
 export function useProxifyLinkMutation() {
   return useMutation({
     mutationFn: proxifyLink,
@@ -23,3 +43,15 @@ export function useProxifyLinkMutation() {
 function proxifyLink(params: ProxifyLinkParams) {
   return api<ProxifyLinkResult>("POST /proxy", { body: params });
 }
+
+export function useProxyUsage() {
+  return useQuery({
+    queryFn: getProxyUsage,
+    queryKey: ["/proxy/usage"],
+  });
+}
+
+async function getProxyUsage() {
+  const { data } = await api<ProxyUsage>("/proxy/usage");
+  return data;
+}
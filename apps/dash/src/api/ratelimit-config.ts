@@ -11,6 +11,17 @@ export type RateLimitConfig = {
   window: string;
 };
 
+export type StoreRateLimit = {
+  created_at: string;
+  max_wait: string;
+  mode: StoreRateLimitMode;
+  rate_limit_config_id: string;
+  store_name: string;
+  updated_at: string;
+};
+
+export type StoreRateLimitMode = "fail" | "wait";
+
 export function useRateLimitConfig(id: null | string): null | RateLimitConfig {
   const { data } = useRateLimitConfigs();
   return data?.find((item) => item.id === id) ?? null;
@@ -60,6 +71,44 @@ export function useRateLimitConfigs() {
   });
 }
 
+export function useStoreRateLimitMutation() {
+  const set = useMutation({
+    mutationFn: async ({
+      store_name,
+      ...params
+    }: Pick<
+      StoreRateLimit,
+      "max_wait" | "mode" | "rate_limit_config_id" | "store_name"
+    >) => {
+      return setStoreRateLimit(store_name, params);
+    },
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/ratelimit/configs/stores"],
+      });
+    },
+  });
+
+  const remove = useMutation({
+    mutationFn: deleteStoreRateLimit,
+    onSuccess: async (_, storeName, __, ctx) => {
+      ctx.client.setQueryData<StoreRateLimit[]>(
+        ["/ratelimit/configs/stores"],
+        (list) => list?.filter((item) => item.store_name !== storeName),
+      );
+    },
+  });
+
+  return { remove, set };
+}
+
+export function useStoreRateLimits() {
+  return useQuery({
+    queryFn: getStoreRateLimits,
+    queryKey: ["/ratelimit/configs/stores"],
+  });
+}
+
 async function createRateLimitConfig(
   params: Pick<RateLimitConfig, "limit" | "name" | "window">,
 ) {
@@ -73,11 +122,20 @@ async function deleteRateLimitConfig(id: string) {
   await api(`DELETE /ratelimit/configs/${id}`);
 }
 
+async function deleteStoreRateLimit(storeName: string) {
+  await api(`DELETE /ratelimit/configs/stores/${storeName}`);
+}
+
 async function getRateLimitConfigs() {
   const { data } = await api<RateLimitConfig[]>("/ratelimit/configs");
   return data;
 }
 
+async function getStoreRateLimits() {
+  const { data } = await api<StoreRateLimit[]>("/ratelimit/configs/stores");
+  return data;
+}
+
 async function updateRateLimitConfig(
   id: string,
   params: Pick<RateLimitConfig, "limit" | "name" | "window">,
@@ -88,3 +146,14 @@ async function updateRateLimitConfig(
   );
   return data;
 }
+
+async function setStoreRateLimit(
+  storeName: string,
+  params: Pick<StoreRateLimit, "max_wait" | "mode" | "rate_limit_config_id">,
+) {
+  const { data } = await api<StoreRateLimit>(
+    `PUT /ratelimit/configs/stores/${storeName}`,
+    { body: params },
+  );
+  return data;
+}
@@ -7,7 +7,15 @@ type IMDBTitleStats = {
 };
 
 type ListsStats = Record<
-  "anilist" | "letterboxd" | "mdblist" | "tmdb" | "trakt" | "tvdb",
+  | "anilist"
+  | "kitsu"
+  | "letterboxd"
+  | "mal"
+  | "mdblist"
+  | "simkl"
+  | "tmdb"
+  | "trakt"
+  | "tvdb",
   {
     total_items: number;
     total_lists: number;
@@ -19,6 +27,7 @@ type ServerStats = {
     vault: boolean;
   };
   integration: {
+    simkl: boolean;
     trakt: boolean;
   };
   started_at: string;
@@ -8,6 +8,48 @@ export type CreateStremioStremioLinkParams = {
   sync_config: SyncConfig;
 };
 
+export type GroupSyncChange = {
+  action: "add" | "remove" | "update";
+  id: string;
+  name: string;
+  source_account_id?: string;
+  type: string;
+  watched?: string[];
+};
+
+export type GroupSyncConfig = {
+  watched: GroupSyncConfigWatched;
+};
+
+export type GroupSyncConfigWatched = {
+  enabled: boolean;
+  ids: string[];
+};
+
+export type GroupSyncPlan = {
+  accounts: Array<{
+    account_id: string;
+    changes: GroupSyncChange[];
+  }>;
+  is_full_sync: boolean;
+};
+
+export type StremioStremioGroup = {
+  account_ids: string[];
+  created_at: string;
+  id: string;
+  name: string;
+  sync_config: GroupSyncConfig;
+  sync_state: SyncState;
+  updated_at: string;
+};
+
+export type StremioStremioGroupParams = {
+  account_ids: string[];
+  name: string;
+  sync_config: GroupSyncConfig;
+};
+
 export type StremioStremioLink = {
   account_a_id: string;
   account_b_id: string;
@@ -40,6 +82,62 @@ export type UpdateStremioStremioLinkParams = {
   sync_config: SyncConfig;
 };
 
+export function useStremioStremioGroupMutation() {
+  const create = useMutation({
+    mutationFn: createStremioStremioGroup,
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/sync/stremio-stremio/groups"],
+      });
+    },
+  });
+
+  const update = useMutation({
+    mutationFn: ({
+      id,
+      ...params
+    }: StremioStremioGroupParams & { id: string }) =>
+      updateStremioStremioGroup(id, params),
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/sync/stremio-stremio/groups"],
+      });
+    },
+  });
+
+  const remove = useMutation({
+    mutationFn: deleteStremioStremioGroup,
+    onSuccess: async (_, id, __, ctx) => {
+      ctx.client.setQueryData<StremioStremioGroup[]>(
+        ["/sync/stremio-stremio/groups"],
+        (list) => list?.filter((item) => item.id !== id),
+      );
+    },
+  });
+
+  const dryRun = useMutation({
+    mutationFn: dryRunStremioStremioGroup,
+  });
+
+  const resetSyncState = useMutation({
+    mutationFn: resetStremioStremioGroupSyncState,
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/sync/stremio-stremio/groups"],
+      });
+    },
+  });
+
+  return { create, dryRun, remove, resetSyncState, update };
+}
+
+export function useStremioStremioGroups() {
+  return useQuery({
+    queryFn: getStremioStremioGroups,
+    queryKey: ["/sync/stremio-stremio/groups"],
+  });
+}
+
 export function useStremioStremioLinkMutation() {
   const create = useMutation({
     mutationFn: createStremioStremioLink,
@@ -124,6 +222,16 @@ export function useStremioStremioLinks() {
   });
 }
 
+async function createStremioStremioGroup(params: StremioStremioGroupParams) {
+  const { data } = await api<StremioStremioGroup>(
+    "POST /sync/stremio-stremio/groups",
+    {
+      body: params,
+    },
+  );
+  return data;
+}
+
 async function createStremioStremioLink(
   params: CreateStremioStremioLinkParams,
 ) {
@@ -136,6 +244,10 @@ async function createStremioStremioLink(
   return data;
 }
 
+async function deleteStremioStremioGroup(id: string) {
+  await api(`DELETE /sync/stremio-stremio/groups/${id}`);
+}
+
 async function deleteStremioStremioLink(
   accountAId: string,
   accountBId: string,
@@ -143,6 +255,20 @@ async function deleteStremioStremioLink(
   await api(`DELETE /sync/stremio-stremio/links/${accountAId}:${accountBId}`);
 }
 
+async function dryRunStremioStremioGroup(id: string) {
+  const { data } = await api<GroupSyncPlan>(
+    `POST /sync/stremio-stremio/groups/${id}/dry-run`,
+  );
+  return data;
+}
+
+async function getStremioStremioGroups() {
+  const { data } = await api<StremioStremioGroup[]>(
+    "/sync/stremio-stremio/groups",
+  );
+  return data;
+}
+
 async function getStremioStremioLinks() {
   const { data } = await api<StremioStremioLink[]>(
     "/sync/stremio-stremio/links",
@@ -150,6 +276,13 @@ async function getStremioStremioLinks() {
   return data;
 }
 
+async function resetStremioStremioGroupSyncState(id: string) {
+  const { data } = await api<StremioStremioGroup>(
+    `POST /sync/stremio-stremio/groups/${id}/reset-sync-state`,
+  );
+  return data;
+}
+
 async function resetStremioStremioLinkSyncState(
   accountAId: string,
   accountBId: string,
@@ -166,6 +299,17 @@ async function syncStremioStremioLink(accountAId: string, accountBId: string) {
   );
 }
 
+async function updateStremioStremioGroup(
+  id: string,
+  params: StremioStremioGroupParams,
+) {
+  const { data } = await api<StremioStremioGroup>(
+    `PATCH /sync/stremio-stremio/groups/${id}`,
+    { body: params },
+  );
+  return data;
+}
+
 async function updateStremioStremioLink(
   accountAId: string,
   accountBId: string,
@@ -18,10 +18,13 @@ export type StremioTraktLink = {
 };
 
 export type SyncConfig = {
-  watched: SyncConfigWatched;
+  progress: SyncConfigSection;
+  ratings: SyncConfigSection;
+  watched: SyncConfigSection;
+  watchlist: SyncConfigSection;
 };
 
-export type SyncConfigWatched = {
+export type SyncConfigSection = {
   dir: SyncDirection;
 };
 
@@ -32,10 +35,13 @@ export type SyncDirection =
   | "trakt_to_stremio";
 
 export type SyncState = {
-  watched: SyncStateWatched;
+  progress: SyncStateSection;
+  ratings: SyncStateSection;
+  watched: SyncStateSection;
+  watchlist: SyncStateSection;
 };
 
-export type SyncStateWatched = {
+export type SyncStateSection = {
   last_synced_at?: string;
 };
 
@@ -0,0 +1,101 @@
+import { useMutation, useQuery } from "@tanstack/react-query";
+
+import { api } from "@/lib/api";
+
+export type CreateSimklAccountParams = {
+  oauth_token_id: string;
+};
+
+export type SimklAccount = {
+  created_at: string;
+  id: string; // simkl user slug
+  is_valid: boolean;
+  updated_at: string;
+  user_name: string;
+};
+
+export type SimklAuthURL = {
+  url: string;
+};
+
+export async function getSimklAuthURL(state: string) {
+  const { data } = await api<SimklAuthURL>(
+    `/vault/simkl/auth/url?state=${state}`,
+  );
+  return data.url;
+}
+
+export function useSimklAccountMutation() {
+  const create = useMutation({
+    mutationFn: createSimklAccount,
+    onSuccess: async (_, __, ___, ctx) => {
+      await ctx.client.invalidateQueries({
+        queryKey: ["/vault/simkl/accounts"],
+      });
+    },
+  });
+
+  const get = useMutation({
+    mutationFn: getSimklAccount,
+    onSuccess: async (data, { id }, __, ctx) => {
+      ctx.client.setQueryData<SimklAccount[]>(
+        ["/vault/simkl/accounts"],
+        (list) =>
+          list?.map((item) => (item.id === id ? { ...item, ...data } : item)),
+      );
+    },
+  });
+
+  const remove = useMutation({
+    mutationFn: deleteSimklAccount,
+    onSuccess: async (_, id, __, ctx) => {
+      const list = ctx.client.getQueryData<SimklAccount[]>([
+        "/vault/simkl/accounts",
+      ]);
+      if (list) {
+        ctx.client.setQueryData(
+          ["/vault/simkl/accounts"],
+          list.filter((item) => item.id !== id),
+        );
+      }
+    },
+  });
+
+  return { create, get, remove };
+}
+
+export function useSimklAccounts() {
+  return useQuery({
+    queryFn: getSimklAccounts,
+    queryKey: ["/vault/simkl/accounts"],
+  });
+}
+
+async function createSimklAccount(params: CreateSimklAccountParams) {
+  const { data } = await api<SimklAccount>("POST /vault/simkl/accounts", {
+    body: params,
+  });
+  return data;
+}
+
+async function deleteSimklAccount(id: string) {
+  await api(`DELETE /vault/simkl/accounts/${id}`);
+}
+
+async function getSimklAccount({
+  id,
+  refresh = false,
+}: {
+  id: string;
+  refresh?: boolean;
+}) {
+  const { data } = await api<SimklAccount>(
+    `GET /vault/simkl/accounts/${id}?refresh=${refresh}`,
+  );
+  return data;
+}
+
+async function getSimklAccounts() {
+  const { data } = await api<SimklAccount[]>("/vault/simkl/accounts");
+  return data;
+}
@@ -21,7 +21,7 @@ type CreateTorznabIndexerParams = {
   url: string;
 };
 
-type TorznabIndexerType = "jackett";
+export type TorznabIndexerType = "jackett" | "prowlarr";
 
 type UpdateTorznabIndexerParams = {
   api_key?: string;
@@ -91,9 +91,10 @@ export function useTorznabIndexers() {
 }
 
 async function createTorznabIndexer(params: CreateTorznabIndexerParams) {
-  const { data } = await api<TorznabIndexer>(`POST /vault/torznab/indexers`, {
-    body: params,
-  });
+  const { data } = await api<TorznabIndexer | TorznabIndexer[]>(
+    `POST /vault/torznab/indexers`,
+    { body: params },
+  );
   return data;
 }
 
@@ -10,10 +10,22 @@ export type TraktAccount = {
   created_at: string;
   id: string; // trakt user slug
   is_valid: boolean;
+  scrobble_config: TraktScrobbleConfig;
   updated_at: string;
   user_name: string;
 };
 
+export type TraktScrobbleConfig = {
+  enabled: boolean;
+  threshold: number;
+  users: string[];
+};
+
+export type UpdateTraktAccountParams = {
+  id: string;
+  scrobble_config: TraktScrobbleConfig;
+};
+
 export type TraktAuthURL = {
   url: string;
 };
@@ -61,7 +73,17 @@ export function useTraktAccountMutation() {
     },
   });
 
-  return { create, get, remove };
+  const update = useMutation({
+    mutationFn: updateTraktAccount,
+    onSuccess: async (data, { id }, __, ctx) => {
+      ctx.client.setQueryData<TraktAccount[]>(
+        ["/vault/trakt/accounts"],
+        (list) => list?.map((item) => (item.id === id ? data : item)),
+      );
+    },
+  });
+
+  return { create, get, remove, update };
 }
 
 export function useTraktAccounts() {
@@ -99,3 +121,16 @@ async function getTraktAccounts() {
   const { data } = await api<TraktAccount[]>("/vault/trakt/accounts");
   return data;
 }
+
+async function updateTraktAccount({
+  id,
+  ...params
+}: UpdateTraktAccountParams) {
+  const { data } = await api<TraktAccount>(
+    `PATCH /vault/trakt/accounts/${id}`,
+    {
+      body: params,
+    },
+  );
+  return data;
+}
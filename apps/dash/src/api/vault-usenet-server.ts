@@ -3,6 +3,7 @@ import { useMutation, useQuery } from "@tanstack/react-query";
 import { api } from "@/lib/api";
 
 export type UsenetServer = {
+  compression: boolean;
   created_at: string;
   disabled: boolean;
   host: string;
@@ -19,6 +20,7 @@ export type UsenetServer = {
 };
 
 type CreateUsenetServerParams = {
+  compression: boolean;
   host: string;
   is_backup: boolean;
   max_connections: number;
@@ -32,6 +34,7 @@ type CreateUsenetServerParams = {
 };
 
 type PingUsenetServerParams = {
+  compression: boolean;
   host: string;
   id?: string;
   password: string;
@@ -47,6 +50,7 @@ type PingUsenetServerResponse = {
 };
 
 type UpdateUsenetServerParams = Partial<{
+  compression: boolean;
   host: string;
   is_backup: boolean;
   max_connections: number;
@@ -8,6 +8,7 @@ export type WorkerDetails = Record<
     has_failed_job: boolean;
     id: string;
     interval: number;
+    lease: null | WorkerLease;
     title: string;
   }
 >;
@@ -22,6 +23,15 @@ export type WorkerJobLog = {
   updated_at: string;
 };
 
+export type WorkerLease = {
+  acquired_at: string;
+  expires_at: string;
+  holder: string;
+  is_active: boolean;
+  is_current_instance: boolean;
+  renewed_at: string;
+};
+
 export type WorkerTemporaryFile = {
   modified_at: string;
   path: string;
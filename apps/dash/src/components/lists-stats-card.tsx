@@ -18,6 +18,10 @@ const listsChartConfig = {
   items: {
     label: "Items",
   },
+  kitsu: {
+    color: "var(--chart-4)",
+    label: "Kitsu",
+  },
   letterboxd: {
     color: "var(--chart-3)",
     label: "Letterboxd",
@@ -25,10 +29,18 @@ const listsChartConfig = {
   lists: {
     label: "Lists",
   },
+  mal: {
+    color: "var(--chart-5)",
+    label: "MyAnimeList",
+  },
   mdblist: {
     color: "var(--chart-2)",
     label: "MDBList",
   },
+  simkl: {
+    color: "var(--chart-3)",
+    label: "Simkl",
+  },
   tmdb: {
     color: "var(--chart-1)",
     label: "TMDB",
@@ -54,6 +66,16 @@ export function ListStatsCard() {
           lists: data?.anilist.total_lists,
           service: "anilist",
         },
+        {
+          fill: "var(--color-kitsu)",
+          lists: data?.kitsu.total_lists,
+          service: "kitsu",
+        },
+        {
+          fill: "var(--color-mal)",
+          lists: data?.mal.total_lists,
+          service: "mal",
+        },
         {
           fill: "var(--color-mdblist)",
           lists: data?.mdblist.total_lists,
@@ -64,6 +86,11 @@ export function ListStatsCard() {
           lists: data?.letterboxd.total_lists,
           service: "letterboxd",
         },
+        {
+          fill: "var(--color-simkl)",
+          lists: data?.simkl.total_lists,
+          service: "simkl",
+        },
         {
           fill: "var(--color-tmdb)",
           lists: data?.tmdb.total_lists,
@@ -86,6 +113,16 @@ export function ListStatsCard() {
           items: data?.anilist.total_items,
           service: "anilist",
         },
+        {
+          fill: "var(--color-kitsu)",
+          items: data?.kitsu.total_items,
+          service: "kitsu",
+        },
+        {
+          fill: "var(--color-mal)",
+          items: data?.mal.total_items,
+          service: "mal",
+        },
         {
           fill: "var(--color-mdblist)",
           items: data?.mdblist.total_items,
@@ -96,6 +133,11 @@ export function ListStatsCard() {
           items: data?.letterboxd.total_items,
           service: "letterboxd",
         },
+        {
+          fill: "var(--color-simkl)",
+          items: data?.simkl.total_items,
+          service: "simkl",
+        },
         {
           fill: "var(--color-tmdb)",
           items: data?.tmdb.total_items,
@@ -340,6 +340,12 @@ function useNavItems(): NavItem[] {
           title: "Trakt Accounts",
         });
       }
+      if (server.integration.simkl) {
+        vault.items!.push({
+          path: "/dash/vault/simkl-accounts",
+          title: "Simkl Accounts",
+        });
+      }
       vault.items!.push({
         path: "/dash/vault/torznab-indexers",
         title: "Torznab Indexers",
@@ -367,6 +373,12 @@ function useNavItems(): NavItem[] {
           title: "Stremio ↔ Trakt",
         });
       }
+      if (server.integration.simkl) {
+        sync.items!.push({
+          path: "/dash/sync/stremio-simkl",
+          title: "Stremio ↔ Simkl",
+        });
+      }
       items.push(sync);
     }
 
@@ -384,5 +396,9 @@ function useNavItems(): NavItem[] {
     items.push(settings);
 
     return items;
-  }, [server?.feature.vault, server?.integration.trakt]);
+  }, [
+    server?.feature.vault,
+    server?.integration.simkl,
+    server?.integration.trakt,
+  ]);
 }
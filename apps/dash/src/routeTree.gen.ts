@@ -26,6 +26,7 @@ import { Route as DashTorrentsIndexRouteImport } from './routes/dash/torrents/in
 import { Route as DashSyncIndexRouteImport } from './routes/dash/sync/index'
 import { Route as DashSettingsIndexRouteImport } from './routes/dash/settings/index'
 import { Route as DashListsIndexRouteImport } from './routes/dash/lists/index'
+import { Route as DashVaultSimklAccountsRouteImport } from './routes/dash/vault/simkl-accounts'
 import { Route as DashVaultTraktAccountsRouteImport } from './routes/dash/vault/trakt-accounts'
 import { Route as DashVaultTorznabIndexersRouteImport } from './routes/dash/vault/torznab-indexers'
 import { Route as DashVaultStremioAccountsRouteImport } from './routes/dash/vault/stremio-accounts'
@@ -36,6 +37,7 @@ import { Route as DashUsenetNzbRouteImport } from './routes/dash/usenet/nzb'
 import { Route as DashUsenetIndexersRouteImport } from './routes/dash/usenet/indexers'
 import { Route as DashUsenetConfigRouteImport } from './routes/dash/usenet/config'
 import { Route as DashTorrentsIndexersSyncRouteImport } from './routes/dash/torrents/indexers-sync'
+import { Route as DashSyncStremioSimklRouteImport } from './routes/dash/sync/stremio-simkl'
 import { Route as DashSyncStremioTraktRouteImport } from './routes/dash/sync/stremio-trakt'
 import { Route as DashSyncStremioStremioRouteImport } from './routes/dash/sync/stremio-stremio'
 import { Route as DashSettingsRatelimitConfigsRouteImport } from './routes/dash/settings/ratelimit-configs'
@@ -125,6 +127,11 @@ const DashListsIndexRoute = DashListsIndexRouteImport.update({
   path: '/',
   getParentRoute: () => DashListsRoute,
 } as any)
+const DashVaultSimklAccountsRoute = DashVaultSimklAccountsRouteImport.update({
+  id: '/simkl-accounts',
+  path: '/simkl-accounts',
+  getParentRoute: () => DashVaultRoute,
+} as any)
 const DashVaultTraktAccountsRoute = DashVaultTraktAccountsRouteImport.update({
   id: '/trakt-accounts',
   path: '/trakt-accounts',
@@ -178,6 +185,11 @@ const DashTorrentsIndexersSyncRoute =
     path: '/indexers-sync',
     getParentRoute: () => DashTorrentsRoute,
   } as any)
+const DashSyncStremioSimklRoute = DashSyncStremioSimklRouteImport.update({
+  id: '/stremio-simkl',
+  path: '/stremio-simkl',
+  getParentRoute: () => DashSyncRoute,
+} as any)
 const DashSyncStremioTraktRoute = DashSyncStremioTraktRouteImport.update({
   id: '/stremio-trakt',
   path: '/stremio-trakt',
@@ -209,6 +221,7 @@ export interface FileRoutesByFullPath {
   '/dash/': typeof DashIndexRoute
   '/dash/settings/ratelimit-configs': typeof DashSettingsRatelimitConfigsRoute
   '/dash/sync/stremio-stremio': typeof DashSyncStremioStremioRoute
+  '/dash/sync/stremio-simkl': typeof DashSyncStremioSimklRoute
   '/dash/sync/stremio-trakt': typeof DashSyncStremioTraktRoute
   '/dash/torrents/indexers-sync': typeof DashTorrentsIndexersSyncRoute
   '/dash/usenet/config': typeof DashUsenetConfigRoute
@@ -219,6 +232,7 @@ export interface FileRoutesByFullPath {
   '/dash/usenet/servers': typeof DashUsenetServersRoute
   '/dash/vault/stremio-accounts': typeof DashVaultStremioAccountsRoute
   '/dash/vault/torznab-indexers': typeof DashVaultTorznabIndexersRoute
+  '/dash/vault/simkl-accounts': typeof DashVaultSimklAccountsRoute
   '/dash/vault/trakt-accounts': typeof DashVaultTraktAccountsRoute
   '/dash/lists/': typeof DashListsIndexRoute
   '/dash/settings/': typeof DashSettingsIndexRoute
@@ -234,6 +248,7 @@ export interface FileRoutesByTo {
   '/dash': typeof DashIndexRoute
   '/dash/settings/ratelimit-configs': typeof DashSettingsRatelimitConfigsRoute
   '/dash/sync/stremio-stremio': typeof DashSyncStremioStremioRoute
+  '/dash/sync/stremio-simkl': typeof DashSyncStremioSimklRoute
   '/dash/sync/stremio-trakt': typeof DashSyncStremioTraktRoute
   '/dash/torrents/indexers-sync': typeof DashTorrentsIndexersSyncRoute
   '/dash/usenet/config': typeof DashUsenetConfigRoute
@@ -244,6 +259,7 @@ export interface FileRoutesByTo {
   '/dash/usenet/servers': typeof DashUsenetServersRoute
   '/dash/vault/stremio-accounts': typeof DashVaultStremioAccountsRoute
   '/dash/vault/torznab-indexers': typeof DashVaultTorznabIndexersRoute
+  '/dash/vault/simkl-accounts': typeof DashVaultSimklAccountsRoute
   '/dash/vault/trakt-accounts': typeof DashVaultTraktAccountsRoute
   '/dash/lists': typeof DashListsIndexRoute
   '/dash/settings': typeof DashSettingsIndexRoute
@@ -267,6 +283,7 @@ export interface FileRoutesById {
   '/dash/': typeof DashIndexRoute
   '/dash/settings/ratelimit-configs': typeof DashSettingsRatelimitConfigsRoute
   '/dash/sync/stremio-stremio': typeof DashSyncStremioStremioRoute
+  '/dash/sync/stremio-simkl': typeof DashSyncStremioSimklRoute
   '/dash/sync/stremio-trakt': typeof DashSyncStremioTraktRoute
   '/dash/torrents/indexers-sync': typeof DashTorrentsIndexersSyncRoute
   '/dash/usenet/config': typeof DashUsenetConfigRoute
@@ -277,6 +294,7 @@ export interface FileRoutesById {
   '/dash/usenet/servers': typeof DashUsenetServersRoute
   '/dash/vault/stremio-accounts': typeof DashVaultStremioAccountsRoute
   '/dash/vault/torznab-indexers': typeof DashVaultTorznabIndexersRoute
+  '/dash/vault/simkl-accounts': typeof DashVaultSimklAccountsRoute
   '/dash/vault/trakt-accounts': typeof DashVaultTraktAccountsRoute
   '/dash/lists/': typeof DashListsIndexRoute
   '/dash/settings/': typeof DashSettingsIndexRoute
@@ -301,6 +319,7 @@ export interface FileRouteTypes {
     | '/dash/'
     | '/dash/settings/ratelimit-configs'
     | '/dash/sync/stremio-stremio'
+    | '/dash/sync/stremio-simkl'
     | '/dash/sync/stremio-trakt'
     | '/dash/torrents/indexers-sync'
     | '/dash/usenet/config'
@@ -311,6 +330,7 @@ export interface FileRouteTypes {
     | '/dash/usenet/servers'
     | '/dash/vault/stremio-accounts'
     | '/dash/vault/torznab-indexers'
+    | '/dash/vault/simkl-accounts'
     | '/dash/vault/trakt-accounts'
     | '/dash/lists/'
     | '/dash/settings/'
@@ -326,6 +346,7 @@ export interface FileRouteTypes {
     | '/dash'
     | '/dash/settings/ratelimit-configs'
     | '/dash/sync/stremio-stremio'
+    | '/dash/sync/stremio-simkl'
     | '/dash/sync/stremio-trakt'
     | '/dash/torrents/indexers-sync'
     | '/dash/usenet/config'
@@ -336,6 +357,7 @@ export interface FileRouteTypes {
     | '/dash/usenet/servers'
     | '/dash/vault/stremio-accounts'
     | '/dash/vault/torznab-indexers'
+    | '/dash/vault/simkl-accounts'
     | '/dash/vault/trakt-accounts'
     | '/dash/lists'
     | '/dash/settings'
@@ -358,6 +380,7 @@ export interface FileRouteTypes {
     | '/dash/'
     | '/dash/settings/ratelimit-configs'
     | '/dash/sync/stremio-stremio'
+    | '/dash/sync/stremio-simkl'
     | '/dash/sync/stremio-trakt'
     | '/dash/torrents/indexers-sync'
     | '/dash/usenet/config'
@@ -368,6 +391,7 @@ export interface FileRouteTypes {
     | '/dash/usenet/servers'
     | '/dash/vault/stremio-accounts'
     | '/dash/vault/torznab-indexers'
+    | '/dash/vault/simkl-accounts'
     | '/dash/vault/trakt-accounts'
     | '/dash/lists/'
     | '/dash/settings/'
@@ -502,6 +526,13 @@ declare module '@tanstack/react-router' {
       preLoaderRoute: typeof DashListsIndexRouteImport
       parentRoute: typeof DashListsRoute
     }
+    '/dash/vault/simkl-accounts': {
+      id: '/dash/vault/simkl-accounts'
+      path: '/simkl-accounts'
+      fullPath: '/dash/vault/simkl-accounts'
+      preLoaderRoute: typeof DashVaultSimklAccountsRouteImport
+      parentRoute: typeof DashVaultRoute
+    }
     '/dash/vault/trakt-accounts': {
       id: '/dash/vault/trakt-accounts'
       path: '/trakt-accounts'
@@ -572,6 +603,13 @@ declare module '@tanstack/react-router' {
       preLoaderRoute: typeof DashTorrentsIndexersSyncRouteImport
       parentRoute: typeof DashTorrentsRoute
     }
+    '/dash/sync/stremio-simkl': {
+      id: '/dash/sync/stremio-simkl'
+      path: '/stremio-simkl'
+      fullPath: '/dash/sync/stremio-simkl'
+      preLoaderRoute: typeof DashSyncStremioSimklRouteImport
+      parentRoute: typeof DashSyncRoute
+    }
     '/dash/sync/stremio-trakt': {
       id: '/dash/sync/stremio-trakt'
       path: '/stremio-trakt'
@@ -624,12 +662,14 @@ const DashSettingsRouteWithChildren = DashSettingsRoute._addFileChildren(
 
 interface DashSyncRouteChildren {
   DashSyncStremioStremioRoute: typeof DashSyncStremioStremioRoute
+  DashSyncStremioSimklRoute: typeof DashSyncStremioSimklRoute
   DashSyncStremioTraktRoute: typeof DashSyncStremioTraktRoute
   DashSyncIndexRoute: typeof DashSyncIndexRoute
 }
 
 const DashSyncRouteChildren: DashSyncRouteChildren = {
   DashSyncStremioStremioRoute: DashSyncStremioStremioRoute,
+  DashSyncStremioSimklRoute: DashSyncStremioSimklRoute,
   DashSyncStremioTraktRoute: DashSyncStremioTraktRoute,
   DashSyncIndexRoute: DashSyncIndexRoute,
 }
@@ -679,6 +719,7 @@ const DashUsenetRouteWithChildren = DashUsenetRoute._addFileChildren(
 interface DashVaultRouteChildren {
   DashVaultStremioAccountsRoute: typeof DashVaultStremioAccountsRoute
   DashVaultTorznabIndexersRoute: typeof DashVaultTorznabIndexersRoute
+  DashVaultSimklAccountsRoute: typeof DashVaultSimklAccountsRoute
   DashVaultTraktAccountsRoute: typeof DashVaultTraktAccountsRoute
   DashVaultIndexRoute: typeof DashVaultIndexRoute
 }
@@ -686,6 +727,7 @@ interface DashVaultRouteChildren {
 const DashVaultRouteChildren: DashVaultRouteChildren = {
   DashVaultStremioAccountsRoute: DashVaultStremioAccountsRoute,
   DashVaultTorznabIndexersRoute: DashVaultTorznabIndexersRoute,
+  DashVaultSimklAccountsRoute: DashVaultSimklAccountsRoute,
   DashVaultTraktAccountsRoute: DashVaultTraktAccountsRoute,
   DashVaultIndexRoute: DashVaultIndexRoute,
 }
@@ -1,10 +1,19 @@
 import { createFileRoute } from "@tanstack/react-router";
+import { ColumnDef, createColumnHelper } from "@tanstack/react-table";
 import { CopyIcon, LinkIcon } from "lucide-react";
+import prettyBytes from "pretty-bytes";
 import { useState } from "react";
 import { toast } from "sonner";
 import z from "zod";
 
-import { useProxifyLinkMutation } from "@/api/proxy";
+import {
+  ProxyUsageHistoryItem,
+  ProxyUserUsage,
+  useProxifyLinkMutation,
+  useProxyUsage,
+} from "@/api/proxy";
+import { DataTable } from "@/components/data-table";
+import { useDataTable } from "@/components/data-table/use-data-table";
 import { Form, useAppForm } from "@/components/form";
 import { Button } from "@/components/ui/button";
 import {
@@ -16,6 +25,56 @@ import {
 } from "@/components/ui/card";
 import { Input } from "@/components/ui/input";
 
+function formatUsage(bytes: number, quota: number) {
+  if (!quota) {
+    return prettyBytes(bytes);
+  }
+  return `${prettyBytes(bytes)} / ${prettyBytes(quota)}`;
+}
+
+const userUsageCol = createColumnHelper<ProxyUserUsage>();
+
+const userUsageColumns: ColumnDef<ProxyUserUsage>[] = [
+  userUsageCol.accessor("user", {
+    header: "User",
+  }),
+  userUsageCol.accessor("daily", {
+    cell: ({ row }) =>
+      formatUsage(row.original.daily, row.original.daily_quota),
+    header: "Today",
+  }),
+  userUsageCol.accessor("monthly", {
+    cell: ({ row }) =>
+      formatUsage(row.original.monthly, row.original.monthly_quota),
+    header: "This Month",
+  }),
+  userUsageCol.accessor("max_rate", {
+    cell: ({ getValue }) => {
+      const maxRate = getValue();
+      if (!maxRate) {
+        return <span className="text-muted-foreground">-</span>;
+      }
+      return `${prettyBytes(maxRate)}/s`;
+    },
+    header: "Max Rate",
+  }),
+];
+
+const usageHistoryCol = createColumnHelper<ProxyUsageHistoryItem>();
+
+const usageHistoryColumns: ColumnDef<ProxyUsageHistoryItem>[] = [
+  usageHistoryCol.accessor("date", {
+    header: "Date",
+  }),
+  usageHistoryCol.accessor("user", {
+    header: "User",
+  }),
+  usageHistoryCol.accessor("bytes", {
+    cell: ({ getValue }) => prettyBytes(getValue()),
+    header: "Usage",
+  }),
+];
+
 export const Route = createFileRoute("/dash/proxy")({
   component: RouteComponent,
   staticData: {
@@ -31,6 +90,44 @@ const formSchema = z.object({
   url: z.url(),
 });
 
+function ProxyUsageCard() {
+  const usage = useProxyUsage();
+
+  const userUsageTable = useDataTable({
+    columns: userUsageColumns,
+    data: usage.data?.users ?? [],
+  });
+
+  const usageHistoryTable = useDataTable({
+    columns: usageHistoryColumns,
+    data: usage.data?.history ?? [],
+  });
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle>Usage</CardTitle>
+        <CardDescription>
+          Bytes served by content proxy, per user (UTC)
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="flex flex-col gap-4">
+        {usage.isLoading ? (
+          <div className="text-muted-foreground text-sm">Loading...</div>
+        ) : usage.isError ? (
+          <div className="text-sm text-red-600">Error loading usage</div>
+        ) : (
+          <>
+            <DataTable table={userUsageTable} />
+            <h3 className="text-sm font-semibold">Last 30 Days</h3>
+            <DataTable table={usageHistoryTable} />
+          </>
+        )}
+      </CardContent>
+    </Card>
+  );
+}
+
 function RouteComponent() {
   const [proxyUrl, setProxyUrl] = useState<string>("");
 
@@ -157,6 +254,8 @@ function RouteComponent() {
           </CardContent>
         </Card>
       )}
+
+      <ProxyUsageCard />
     </div>
   );
 }
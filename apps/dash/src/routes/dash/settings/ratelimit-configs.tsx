@@ -8,8 +8,12 @@ import z from "zod";
 
 import {
   RateLimitConfig,
+  StoreRateLimit,
+  StoreRateLimitMode,
   useRateLimitConfigMutation,
   useRateLimitConfigs,
+  useStoreRateLimitMutation,
+  useStoreRateLimits,
 } from "@/api/ratelimit-config";
 import { DataTable } from "@/components/data-table";
 import { useDataTable } from "@/components/data-table/use-data-table";
@@ -50,10 +54,18 @@ declare module "@/components/data-table" {
       onEdit: (item: RateLimitConfig) => void;
       removeConfig: ReturnType<typeof useRateLimitConfigMutation>["remove"];
     };
+    StoreRateLimit: {
+      configNameById: Map<string, string>;
+      onEdit: (item: StoreRateLimit) => void;
+      removeStoreRateLimit: ReturnType<
+        typeof useStoreRateLimitMutation
+      >["remove"];
+    };
   }
 
   export interface DataTableMetaCtxKey {
     RateLimitConfig: RateLimitConfig;
+    StoreRateLimit: StoreRateLimit;
   }
 }
 
@@ -267,6 +279,278 @@ function RateLimitConfigFormSheet({
   );
 }
 
+const storeOptions = [
+  { label: "AllDebrid", value: "alldebrid" },
+  { label: "Debrider", value: "debrider" },
+  { label: "Debrid-Link", value: "debridlink" },
+  { label: "EasyDebrid", value: "easydebrid" },
+  { label: "Offcloud", value: "offcloud" },
+  { label: "PikPak", value: "pikpak" },
+  { label: "Premiumize", value: "premiumize" },
+  { label: "RealDebrid", value: "realdebrid" },
+  { label: "TorBox", value: "torbox" },
+];
+
+const storeLabelByValue = new Map(
+  storeOptions.map((option) => [option.value, option.label]),
+);
+
+const storeRateLimitModeOptions: Array<{
+  label: string;
+  value: StoreRateLimitMode;
+}> = [
+  { label: "Wait (up to Max Wait)", value: "wait" },
+  { label: "Fail", value: "fail" },
+];
+
+const storeCol = createColumnHelper<StoreRateLimit>();
+
+const storeColumns: ColumnDef<StoreRateLimit>[] = [
+  storeCol.accessor("store_name", {
+    cell: ({ getValue }) => storeLabelByValue.get(getValue()) ?? getValue(),
+    header: "Store",
+  }),
+  storeCol.accessor("rate_limit_config_id", {
+    cell: (c) => {
+      const { configNameById } = c.table.options.meta!.ctx;
+      return configNameById.get(c.getValue()) ?? c.getValue();
+    },
+    header: "Rate Limit Config",
+  }),
+  storeCol.accessor("mode", {
+    cell: ({ getValue }) => (getValue() === "wait" ? "Wait" : "Fail"),
+    header: "Mode",
+  }),
+  storeCol.accessor("max_wait", {
+    header: "Max Wait",
+  }),
+  storeCol.accessor("updated_at", {
+    cell: ({ getValue }) => {
+      const date = DateTime.fromISO(getValue());
+      return date.toLocaleString(DateTime.DATETIME_MED);
+    },
+    header: "Updated At",
+  }),
+  storeCol.display({
+    cell: (c) => {
+      const { onEdit, removeStoreRateLimit } = c.table.options.meta!.ctx;
+      const item = c.row.original;
+      return (
+        <div className="flex gap-1">
+          <Tooltip>
+            <TooltipTrigger asChild>
+              <Button
+                onClick={() => onEdit(item)}
+                size="icon-sm"
+                variant="ghost"
+              >
+                <Pencil />
+              </Button>
+            </TooltipTrigger>
+            <TooltipContent>Edit</TooltipContent>
+          </Tooltip>
+          <AlertDialog>
+            <AlertDialogTrigger asChild>
+              <Button size="icon-sm" variant="ghost">
+                <Trash2 className="text-destructive" />
+              </Button>
+            </AlertDialogTrigger>
+            <AlertDialogContent>
+              <AlertDialogHeader>
+                <AlertDialogTitle>Remove Store Rate Limit?</AlertDialogTitle>
+                <AlertDialogDescription>
+                  Requests to{" "}
+                  <strong>
+                    {storeLabelByValue.get(item.store_name) ?? item.store_name}
+                  </strong>{" "}
+                  will no longer be rate limited.
+                </AlertDialogDescription>
+              </AlertDialogHeader>
+              <AlertDialogFooter>
+                <AlertDialogCancel>Cancel</AlertDialogCancel>
+                <AlertDialogAction asChild>
+                  <Button
+                    disabled={removeStoreRateLimit.isPending}
+                    onClick={() => {
+                      toast.promise(
+                        removeStoreRateLimit.mutateAsync(item.store_name),
+                        {
+                          error(err: APIError) {
+                            console.error(err);
+                            return {
+                              closeButton: true,
+                              message: err.message,
+                            };
+                          },
+                          loading: "Removing...",
+                          success: {
+                            closeButton: true,
+                            message: "Removed successfully!",
+                          },
+                        },
+                      );
+                    }}
+                    variant="destructive"
+                  >
+                    Remove
+                  </Button>
+                </AlertDialogAction>
+              </AlertDialogFooter>
+            </AlertDialogContent>
+          </AlertDialog>
+        </div>
+      );
+    },
+    header: "",
+    id: "actions",
+  }),
+];
+
+const storeRateLimitSchema = z.object({
+  max_wait: z.string().min(2, "Max Wait is required"),
+  mode: z.enum(["fail", "wait"]),
+  rate_limit_config_id: z.string().min(1, "Rate Limit Config is required"),
+  store_name: z.string().min(1, "Store is required"),
+});
+
+function StoreRateLimitFormSheet({
+  editItem,
+  rateLimitConfigs,
+  setEditItem,
+}: {
+  editItem: null | StoreRateLimit;
+  rateLimitConfigs: RateLimitConfig[];
+  setEditItem: (item: null | StoreRateLimit) => void;
+}) {
+  const [isOpen, setIsOpen] = useState(false);
+  const { set } = useStoreRateLimitMutation();
+
+  useEffect(() => {
+    if (editItem) {
+      setIsOpen(true);
+    }
+  }, [editItem]);
+
+  const rateLimitConfigOptions = useMemo(
+    () =>
+      rateLimitConfigs.map((config) => ({
+        label: config.name,
+        value: config.id,
+      })),
+    [rateLimitConfigs],
+  );
+
+  const defaultValues = useMemo(
+    () => ({
+      max_wait: editItem?.max_wait ?? "10s",
+      mode: editItem?.mode ?? ("wait" as StoreRateLimitMode),
+      rate_limit_config_id: editItem?.rate_limit_config_id ?? "",
+      store_name: editItem?.store_name ?? "",
+    }),
+    [
+      editItem?.max_wait,
+      editItem?.mode,
+      editItem?.rate_limit_config_id,
+      editItem?.store_name,
+    ],
+  );
+
+  const form = useAppForm({
+    canSubmitWhenInvalid: true,
+    defaultValues,
+    onSubmit: async ({ value }) => {
+      value = storeRateLimitSchema.parse(value);
+      await set.mutateAsync(value);
+      toast.success("Saved successfully!");
+      setIsOpen(false);
+    },
+    validators: {
+      onChange: storeRateLimitSchema,
+    },
+  });
+
+  useEffect(() => {
+    form.reset(defaultValues);
+  }, [defaultValues, form]);
+
+  return (
+    <Sheet onOpenChange={setIsOpen} open={isOpen}>
+      <SheetTrigger asChild>
+        <Button
+          onClick={() => {
+            setEditItem(null);
+          }}
+          size="sm"
+        >
+          <Plus className="mr-2 size-4" />
+          Add Store
+        </Button>
+      </SheetTrigger>
+      <SheetContent asChild>
+        <Form form={form}>
+          <SheetHeader>
+            <SheetTitle>{editItem ? "Edit" : "Add"} Store Rate Limit</SheetTitle>
+            <SheetDescription>
+              Each token used with the store gets its own bucket. With Redis,
+              the limit is shared across instances.
+            </SheetDescription>
+          </SheetHeader>
+
+          <ScrollArea className="overflow-hidden">
+            <div className="flex flex-col gap-4 px-4">
+              <form.AppField name="store_name">
+                {(field) => (
+                  <field.Select
+                    disabled={Boolean(editItem)}
+                    label="Store"
+                    options={storeOptions}
+                    required
+                  />
+                )}
+              </form.AppField>
+              <form.AppField name="rate_limit_config_id">
+                {(field) => (
+                  <field.Select
+                    label="Rate Limit Config"
+                    options={rateLimitConfigOptions}
+                    required
+                  />
+                )}
+              </form.AppField>
+              <form.AppField name="mode">
+                {(field) => (
+                  <field.Select
+                    label="Mode"
+                    options={storeRateLimitModeOptions}
+                    required
+                  />
+                )}
+              </form.AppField>
+              <form.AppField name="max_wait">
+                {(field) => (
+                  <field.Input
+                    label="Max Wait"
+                    placeholder="e.g., 5s, 10s, 30s"
+                    type="text"
+                  />
+                )}
+              </form.AppField>
+            </div>
+          </ScrollArea>
+
+          <SheetFooter>
+            <form.AppForm>
+              <form.SubmitButton className="w-full">
+                {editItem ? "Update" : "Add"} Store Rate Limit
+              </form.SubmitButton>
+            </form.AppForm>
+          </SheetFooter>
+        </Form>
+      </SheetContent>
+    </Sheet>
+  );
+}
+
 export const Route = createFileRoute("/dash/settings/ratelimit-configs")({
   component: RouteComponent,
   staticData: {
@@ -297,6 +581,36 @@ function RouteComponent() {
     },
   });
 
+  const storeRateLimits = useStoreRateLimits();
+  const { remove: removeStoreRateLimit } = useStoreRateLimitMutation();
+
+  const [editStoreItem, setEditStoreItem] = useState<null | StoreRateLimit>(
+    null,
+  );
+
+  const configNameById = useMemo(
+    () =>
+      new Map(
+        (rateLimitConfigs.data ?? []).map((config) => [config.id, config.name]),
+      ),
+    [rateLimitConfigs.data],
+  );
+
+  const storeTable = useDataTable({
+    columns: storeColumns,
+    data: storeRateLimits.data ?? [],
+    initialState: {
+      columnPinning: { right: ["actions"] },
+    },
+    meta: {
+      ctx: {
+        configNameById,
+        onEdit: setEditStoreItem,
+        removeStoreRateLimit,
+      },
+    },
+  });
+
   return (
     <div className="flex flex-col gap-6">
       <div className="flex items-center justify-between">
@@ -316,6 +630,25 @@ function RouteComponent() {
       ) : (
         <DataTable table={table} />
       )}
+
+      <div className="flex items-center justify-between">
+        <h2 className="text-lg font-semibold">Store Rate Limits</h2>
+        <StoreRateLimitFormSheet
+          editItem={editStoreItem}
+          rateLimitConfigs={rateLimitConfigs.data ?? []}
+          setEditItem={setEditStoreItem}
+        />
+      </div>
+
+      {storeRateLimits.isLoading ? (
+        <div className="text-muted-foreground text-sm">Loading...</div>
+      ) : storeRateLimits.isError ? (
+        <div className="text-sm text-red-600">
+          Error loading store rate limits
+        </div>
+      ) : (
+        <DataTable table={storeTable} />
+      )}
     </div>
   );
 }
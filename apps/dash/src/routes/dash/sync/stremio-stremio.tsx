@@ -5,10 +5,12 @@ import {
   ArrowRight,
   CheckCircle,
   ExternalLinkIcon,
+  Eye,
   Link2,
   Plus,
   RefreshCw,
   Trash2,
+  Users,
   XCircle,
   XIcon,
 } from "lucide-react";
@@ -18,8 +20,12 @@ import { toast } from "sonner";
 
 import { IMDBTitle } from "@/api/imdb";
 import {
+  GroupSyncPlan,
+  StremioStremioGroup,
   StremioStremioLink,
   SyncDirection,
+  useStremioStremioGroupMutation,
+  useStremioStremioGroups,
   useStremioStremioLinkMutation,
   useStremioStremioLinks,
 } from "@/api/sync-stremio-stremio";
@@ -50,6 +56,14 @@ import {
   CardHeader,
   CardTitle,
 } from "@/components/ui/card";
+import { Checkbox } from "@/components/ui/checkbox";
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogHeader,
+  DialogTitle,
+} from "@/components/ui/dialog";
 import {
   Item,
   ItemActions,
@@ -108,6 +122,425 @@ const syncDirectionOptions: Array<{
   },
 ];
 
+function CreateGroupSheet({
+  onClose,
+  stremioAccounts,
+}: {
+  onClose: () => void;
+  stremioAccounts: StremioAccount[];
+}) {
+  const { create } = useStremioStremioGroupMutation();
+
+  const form = useAppForm({
+    defaultValues: {
+      account_ids: [] as string[],
+      name: "",
+    },
+    onSubmit: async ({ value }) => {
+      await create.mutateAsync({
+        account_ids: value.account_ids,
+        name: value.name,
+        sync_config: { watched: { enabled: false, ids: [] } },
+      });
+      toast.success("Group created successfully!");
+      onClose();
+    },
+  });
+
+  return (
+    <Form className="flex flex-col gap-4" form={form}>
+      <form.AppField name="name">
+        {(field) => <field.Input label="Name" placeholder="Family" />}
+      </form.AppField>
+
+      <form.AppField name="account_ids">
+        {(field) => (
+          <div className="flex flex-col gap-2">
+            <label className="text-sm font-medium">Stremio Accounts</label>
+            {stremioAccounts.length < 2 ? (
+              <div className="text-muted-foreground text-sm">
+                At least 2 Stremio accounts are needed.{" "}
+                <Link
+                  className="text-primary underline underline-offset-4"
+                  to="/dash/vault/stremio-accounts"
+                >
+                  Add one in Vault
+                </Link>
+                .
+              </div>
+            ) : (
+              stremioAccounts.map((account) => (
+                <label
+                  className="flex items-center gap-2 text-sm"
+                  key={account.id}
+                >
+                  <Checkbox
+                    checked={field.state.value.includes(account.id)}
+                    onCheckedChange={(checked) =>
+                      field.handleChange(
+                        checked
+                          ? [...field.state.value, account.id]
+                          : field.state.value.filter((id) => id !== account.id),
+                      )
+                    }
+                  />
+                  {account.email}
+                </label>
+              ))
+            )}
+          </div>
+        )}
+      </form.AppField>
+
+      <form.AppForm>
+        <form.SubmitButton className="w-full">Create Group</form.SubmitButton>
+      </form.AppForm>
+    </Form>
+  );
+}
+
+function DryRunDialog({
+  accountsById,
+  onOpenChange,
+  plan,
+}: {
+  accountsById: Map<string, StremioAccount>;
+  onOpenChange: (open: boolean) => void;
+  plan: GroupSyncPlan | null;
+}) {
+  return (
+    <Dialog onOpenChange={onOpenChange} open={Boolean(plan)}>
+      <DialogContent className="max-h-[80vh] overflow-y-auto">
+        <DialogHeader>
+          <DialogTitle>Dry Run</DialogTitle>
+          <DialogDescription>
+            Changes the next {plan?.is_full_sync ? "full " : ""}sync would make.
+            Nothing has been applied.
+          </DialogDescription>
+        </DialogHeader>
+        <div className="flex flex-col gap-4">
+          {plan?.accounts.map((account) => (
+            <div className="flex flex-col gap-2" key={account.account_id}>
+              <div className="text-sm font-medium">
+                {accountsById.get(account.account_id)?.email ||
+                  account.account_id}{" "}
+                <span className="text-muted-foreground font-normal">
+                  ({account.changes.length} change
+                  {account.changes.length !== 1 ? "s" : ""})
+                </span>
+              </div>
+              {account.changes.length > 0 && (
+                <ul className="text-muted-foreground flex flex-col gap-1 text-sm">
+                  {account.changes.map((change) => (
+                    <li key={change.id}>
+                      <span className="font-medium">
+                        {change.action === "add"
+                          ? "Add"
+                          : change.action === "remove"
+                            ? "Remove"
+                            : "Update"}
+                      </span>{" "}
+                      {change.name || change.id}
+                      {change.watched?.length
+                        ? ` · ${change.watched.length} watched`
+                        : ""}
+                      {change.source_account_id
+                        ? ` · state from ${accountsById.get(change.source_account_id)?.email || change.source_account_id}`
+                        : ""}
+                    </li>
+                  ))}
+                </ul>
+              )}
+            </div>
+          ))}
+        </div>
+      </DialogContent>
+    </Dialog>
+  );
+}
+
+function GroupCard({
+  accountsById,
+  group,
+}: {
+  accountsById: Map<string, StremioAccount>;
+  group: StremioStremioGroup;
+}) {
+  const { dryRun, remove, resetSyncState, update } =
+    useStremioStremioGroupMutation();
+
+  const [isEditingItems, setIsEditingItems] = useState(false);
+  const [tempIds, setTempIds] = useState<string[]>([]);
+  const [plan, setPlan] = useState<GroupSyncPlan | null>(null);
+
+  useEffect(() => {
+    setTempIds(group.sync_config.watched.ids);
+  }, [group.sync_config.watched.ids]);
+
+  const handleUpdate = (
+    watched: Partial<StremioStremioGroup["sync_config"]["watched"]>,
+    loading: string,
+    success: string,
+  ) => {
+    toast.promise(
+      update.mutateAsync({
+        account_ids: group.account_ids,
+        id: group.id,
+        name: group.name,
+        sync_config: {
+          watched: { ...group.sync_config.watched, ...watched },
+        },
+      }),
+      {
+        error(err: APIError) {
+          console.error(err);
+          return {
+            closeButton: true,
+            message: err.message,
+          };
+        },
+        loading,
+        success: {
+          closeButton: true,
+          message: success,
+        },
+      },
+    );
+  };
+
+  const handleSaveItems = () => {
+    handleUpdate({ ids: tempIds }, "Updating items...", "Items updated!");
+    setIsEditingItems(false);
+  };
+
+  const handleDryRun = () => {
+    toast.promise(dryRun.mutateAsync(group.id), {
+      error(err: APIError) {
+        console.error(err);
+        return {
+          closeButton: true,
+          message: err.message,
+        };
+      },
+      loading: "Planning sync...",
+      success(data) {
+        setPlan(data);
+        return {
+          closeButton: true,
+          message: "Dry run completed!",
+        };
+      },
+    });
+  };
+
+  const handleDelete = () => {
+    toast.promise(remove.mutateAsync(group.id), {
+      error(err: APIError) {
+        console.error(err);
+        return {
+          closeButton: true,
+          message: err.message,
+        };
+      },
+      loading: "Deleting group...",
+      success: {
+        closeButton: true,
+        message: "Group deleted!",
+      },
+    });
+  };
+
+  const handleResetSyncState = () => {
+    toast.promise(resetSyncState.mutateAsync(group.id), {
+      error(err: APIError) {
+        console.error(err);
+        return {
+          closeButton: true,
+          message: err.message,
+        };
+      },
+      loading: "Resetting sync status...",
+      success: {
+        closeButton: true,
+        message: "Sync status reset! Next sync will be a full sync.",
+      },
+    });
+  };
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2 text-base">
+          <Users className="size-4" />
+          {group.name}
+        </CardTitle>
+        <CardDescription>
+          <div className="flex flex-col gap-1">
+            {group.account_ids.map((accountId) => (
+              <div key={accountId}>
+                {accountsById.get(accountId)?.email || accountId}
+              </div>
+            ))}
+          </div>
+        </CardDescription>
+      </CardHeader>
+      <CardContent className="flex flex-col gap-4">
+        <label className="flex items-center gap-2 text-sm font-medium">
+          <Checkbox
+            checked={group.sync_config.watched.enabled}
+            onCheckedChange={(checked) =>
+              handleUpdate(
+                { enabled: checked === true },
+                "Updating sync...",
+                "Sync updated!",
+              )
+            }
+          />
+          Sync Watched
+        </label>
+
+        <div className="flex flex-col gap-2">
+          <div className="flex items-center justify-between">
+            <label className="text-sm font-medium">Items to Sync</label>
+            {!isEditingItems && (
+              <Button
+                onClick={() => setIsEditingItems(true)}
+                size="sm"
+                variant="ghost"
+              >
+                Edit
+              </Button>
+            )}
+          </div>
+
+          {isEditingItems ? (
+            <div className="flex flex-col gap-3">
+              <ItemsManager ids={tempIds} onIdsChange={setTempIds} />
+              <div className="flex gap-2">
+                <Button
+                  className="flex-1"
+                  onClick={handleSaveItems}
+                  size="sm"
+                  variant="outline"
+                >
+                  Save
+                </Button>
+                <Button
+                  className="flex-1"
+                  onClick={() => setIsEditingItems(false)}
+                  size="sm"
+                  variant="ghost"
+                >
+                  Cancel
+                </Button>
+              </div>
+            </div>
+          ) : (
+            <div className="text-muted-foreground text-sm">
+              {group.sync_config.watched.ids.length === 0 ? (
+                <span>Whole library. Click Edit to limit items.</span>
+              ) : (
+                <span>
+                  {group.sync_config.watched.ids.length} item
+                  {group.sync_config.watched.ids.length !== 1 ? "s" : ""}{" "}
+                  selected
+                </span>
+              )}
+            </div>
+          )}
+        </div>
+
+        {group.sync_state.watched.last_synced_at && (
+          <div className="text-muted-foreground flex items-center justify-between gap-2 text-sm">
+            <div className="flex items-center gap-1">
+              <CheckCircle className="size-3.5 text-green-500" />
+              <span>
+                Last synced:{" "}
+                {DateTime.fromISO(
+                  group.sync_state.watched.last_synced_at,
+                ).toLocaleString(DateTime.DATETIME_MED)}
+              </span>
+            </div>
+            <AlertDialog>
+              <AlertDialogTrigger asChild>
+                <Button size="sm" variant="ghost">
+                  Reset
+                </Button>
+              </AlertDialogTrigger>
+              <AlertDialogContent>
+                <AlertDialogHeader>
+                  <AlertDialogTitle>Reset Sync Status?</AlertDialogTitle>
+                  <AlertDialogDescription>
+                    This will clear the last sync timestamp and force a full
+                    re-sync on the next sync operation.
+                  </AlertDialogDescription>
+                </AlertDialogHeader>
+                <AlertDialogFooter>
+                  <AlertDialogCancel>Cancel</AlertDialogCancel>
+                  <AlertDialogAction asChild>
+                    <Button
+                      disabled={resetSyncState.isPending}
+                      onClick={handleResetSyncState}
+                    >
+                      Reset
+                    </Button>
+                  </AlertDialogAction>
+                </AlertDialogFooter>
+              </AlertDialogContent>
+            </AlertDialog>
+          </div>
+        )}
+      </CardContent>
+      <CardFooter className="mt-auto gap-4">
+        <Button
+          className="flex-1"
+          disabled={dryRun.isPending}
+          onClick={handleDryRun}
+          size="sm"
+          variant="outline"
+        >
+          <Eye className="mr-2 size-4" />
+          Dry Run
+        </Button>
+        <AlertDialog>
+          <AlertDialogTrigger asChild>
+            <Button size="sm" variant="outline">
+              <Trash2 className="text-destructive mr-2 size-4" />
+              Delete
+            </Button>
+          </AlertDialogTrigger>
+          <AlertDialogContent>
+            <AlertDialogHeader>
+              <AlertDialogTitle>Delete Group?</AlertDialogTitle>
+              <AlertDialogDescription>
+                This will delete the group <strong>{group.name}</strong>. Sync
+                will stop, but your watch history won't be deleted.
+              </AlertDialogDescription>
+            </AlertDialogHeader>
+            <AlertDialogFooter>
+              <AlertDialogCancel>Cancel</AlertDialogCancel>
+              <AlertDialogAction asChild>
+                <Button
+                  disabled={remove.isPending}
+                  onClick={handleDelete}
+                  variant="destructive"
+                >
+                  Delete
+                </Button>
+              </AlertDialogAction>
+            </AlertDialogFooter>
+          </AlertDialogContent>
+        </AlertDialog>
+      </CardFooter>
+      <DryRunDialog
+        accountsById={accountsById}
+        onOpenChange={(open) => !open && setPlan(null)}
+        plan={plan}
+      />
+    </Card>
+  );
+}
+
 function ItemsManager({
   ids,
   onIdsChange,
@@ -656,9 +1089,11 @@ function LinkCard({
 
 function RouteComponent() {
   const links = useStremioStremioLinks();
+  const groups = useStremioStremioGroups();
   const stremioAccounts = useStremioAccounts();
 
   const [sheetOpen, setSheetOpen] = useState(false);
+  const [groupSheetOpen, setGroupSheetOpen] = useState(false);
 
   const stremioAccountsById = useMemo(
     () => new Map(stremioAccounts.data?.map((acc) => [acc.id, acc])),
@@ -747,6 +1182,60 @@ function RouteComponent() {
           ))}
         </div>
       )}
+
+      <div className="flex items-center justify-between">
+        <div>
+          <h2 className="text-lg font-semibold">Groups</h2>
+          <p className="text-muted-foreground text-sm">
+            Share watched state across multiple Stremio accounts. Latest change
+            wins per item, watched episodes are merged.
+          </p>
+        </div>
+        <Sheet onOpenChange={setGroupSheetOpen} open={groupSheetOpen}>
+          <SheetTrigger asChild>
+            <Button size="sm">
+              <Plus className="mr-2 size-4" />
+              Create Group
+            </Button>
+          </SheetTrigger>
+          <SheetContent>
+            <SheetHeader>
+              <SheetTitle>Create Group</SheetTitle>
+              <SheetDescription>
+                Choose which Stremio accounts to sync together.
+              </SheetDescription>
+            </SheetHeader>
+            <div className="p-4">
+              {stremioAccounts.data ? (
+                <CreateGroupSheet
+                  onClose={() => setGroupSheetOpen(false)}
+                  stremioAccounts={stremioAccounts.data}
+                />
+              ) : (
+                <div className="text-muted-foreground text-sm">Loading...</div>
+              )}
+            </div>
+          </SheetContent>
+        </Sheet>
+      </div>
+
+      {groups.isLoading ? (
+        <div className="text-muted-foreground text-sm">Loading...</div>
+      ) : groups.isError ? (
+        <div className="text-sm text-red-600">Error loading data</div>
+      ) : groups.data?.length === 0 ? (
+        <div className="text-muted-foreground text-sm">No groups</div>
+      ) : (
+        <div className="grid gap-4 sm:grid-cols-2">
+          {groups.data?.map((group) => (
+            <GroupCard
+              accountsById={stremioAccountsById}
+              group={group}
+              key={group.id}
+            />
+          ))}
+        </div>
+      )}
     </div>
   );
 }
@@ -15,6 +15,7 @@ import { toast } from "sonner";
 
 import {
   StremioTraktLink,
+  SyncConfig,
   SyncDirection,
   useStremioTraktLinkMutation,
   useStremioTraktLinks,
@@ -97,6 +98,31 @@ const syncDirectionOptions: Array<{
   },
 ];
 
+const syncSections: Array<{
+  key: keyof SyncConfig;
+  label: string;
+}> = [
+  { key: "watched", label: "Watched Sync Direction" },
+  { key: "watchlist", label: "Watchlist Sync Direction" },
+  { key: "ratings", label: "Ratings Sync Direction" },
+  { key: "progress", label: "Progress Sync Direction" },
+];
+
+function getLastSyncedAt(link: StremioTraktLink) {
+  let lastSyncedAt: string | undefined;
+  for (const { key } of syncSections) {
+    const value = link.sync_state[key]?.last_synced_at;
+    if (value && (!lastSyncedAt || value > lastSyncedAt)) {
+      lastSyncedAt = value;
+    }
+  }
+  return lastSyncedAt;
+}
+
+function isSyncDisabled(syncConfig: SyncConfig) {
+  return syncSections.every(({ key }) => syncConfig[key].dir === "none");
+}
+
 function LinkAccountSheet({
   onClose,
   stremioAccounts,
@@ -119,7 +145,12 @@ function LinkAccountSheet({
     onSubmit: async ({ value }) => {
       await create.mutateAsync({
         stremio_account_id: value.stremio_account_id,
-        sync_config: { watched: { dir: "none" } },
+        sync_config: {
+          progress: { dir: "none" },
+          ratings: { dir: "none" },
+          watched: { dir: "none" },
+          watchlist: { dir: "none" },
+        },
         trakt_account_id: value.trakt_account_id,
       });
       toast.success("Accounts linked successfully!");
@@ -232,16 +263,19 @@ function LinkCard({
   const { remove, resetSyncState, sync, update } =
     useStremioTraktLinkMutation();
 
-  const selectedWatchedSyncDirection = syncDirectionOptions.find(
-    (opt) => opt.value === link.sync_config.watched.dir,
-  );
-  const SyncDirectionIcon = selectedWatchedSyncDirection?.icon || XCircle;
+  const lastSyncedAt = getLastSyncedAt(link);
 
-  const handleWatchedSyncDirectionChange = (value: string) => {
+  const handleSyncDirectionChange = (
+    section: keyof SyncConfig,
+    value: string,
+  ) => {
     toast.promise(
       update.mutateAsync({
         stremio_account_id: link.stremio_account_id,
-        sync_config: { watched: { dir: value as SyncDirection } },
+        sync_config: {
+          ...link.sync_config,
+          [section]: { dir: value as SyncDirection },
+        },
         trakt_account_id: link.trakt_account_id,
       }),
       {
@@ -351,46 +385,56 @@ function LinkCard({
         </CardDescription>
       </CardHeader>
       <CardContent className="flex flex-col gap-4">
-        <div className="flex flex-col gap-2">
-          <label className="text-sm font-medium">Watched Sync Direction</label>
-          <Select
-            onValueChange={handleWatchedSyncDirectionChange}
-            value={link.sync_config.watched.dir}
-          >
-            <SelectTrigger className="w-full">
-              <SelectValue>
-                <div className="flex items-center gap-2">
-                  <SyncDirectionIcon className="size-4" />
-                  {selectedWatchedSyncDirection?.label}
-                </div>
-              </SelectValue>
-            </SelectTrigger>
-            <SelectContent>
-              {syncDirectionOptions.map((option) => {
-                const OptionIcon = option.icon;
-                return (
-                  <SelectItem key={option.value} value={option.value}>
+        {syncSections.map((section) => {
+          const selectedSyncDirection = syncDirectionOptions.find(
+            (opt) => opt.value === link.sync_config[section.key].dir,
+          );
+          const SyncDirectionIcon = selectedSyncDirection?.icon || XCircle;
+          return (
+            <div className="flex flex-col gap-2" key={section.key}>
+              <label className="text-sm font-medium">{section.label}</label>
+              <Select
+                onValueChange={(value) =>
+                  handleSyncDirectionChange(section.key, value)
+                }
+                value={link.sync_config[section.key].dir}
+              >
+                <SelectTrigger className="w-full">
+                  <SelectValue>
                     <div className="flex items-center gap-2">
-                      <OptionIcon className="size-4" />
-                      {option.label}
+                      <SyncDirectionIcon className="size-4" />
+                      {selectedSyncDirection?.label}
                     </div>
-                  </SelectItem>
-                );
-              })}
-            </SelectContent>
-          </Select>
-        </div>
+                  </SelectValue>
+                </SelectTrigger>
+                <SelectContent>
+                  {syncDirectionOptions.map((option) => {
+                    const OptionIcon = option.icon;
+                    return (
+                      <SelectItem key={option.value} value={option.value}>
+                        <div className="flex items-center gap-2">
+                          <OptionIcon className="size-4" />
+                          {option.label}
+                        </div>
+                      </SelectItem>
+                    );
+                  })}
+                </SelectContent>
+              </Select>
+            </div>
+          );
+        })}
 
-        {link.sync_state.watched.last_synced_at && (
+        {lastSyncedAt && (
           <div className="text-muted-foreground flex flex-col gap-1 text-sm">
             <div className="flex items-center justify-between gap-2">
               <div className="flex items-center gap-1">
                 <CheckCircle className="size-3.5 text-green-500" />
                 <span>
                   Last synced:{" "}
-                  {DateTime.fromISO(
-                    link.sync_state.watched.last_synced_at,
-                  ).toLocaleString(DateTime.DATETIME_MED)}
+                  {DateTime.fromISO(lastSyncedAt).toLocaleString(
+                    DateTime.DATETIME_MED,
+                  )}
                 </span>
               </div>
               <AlertDialog>
@@ -428,7 +472,7 @@ function LinkCard({
       <CardFooter className="mt-auto gap-4">
         <Button
           className="hidden flex-1"
-          disabled={link.sync_config.watched.dir === "none" || sync.isPending}
+          disabled={isSyncDisabled(link.sync_config) || sync.isPending}
           onClick={handleSync}
           size="sm"
           variant="outline"
@@ -504,7 +548,8 @@ function RouteComponent() {
         <div>
           <h2 className="text-lg font-semibold">Stremio ↔ Trakt Sync</h2>
           <p className="text-muted-foreground text-sm">
-            Link Stremio and Trakt accounts to sync watch history
+            Link Stremio and Trakt accounts to sync watch history, watchlist,
+            ratings and progress
           </p>
         </div>
         <Sheet onOpenChange={setSheetOpen} open={sheetOpen}>
@@ -243,6 +243,7 @@ const columns: ColumnDef<UsenetServer>[] = [
 ];
 
 const usenetServerSchema = z.object({
+  compression: z.boolean(),
   host: z.string().min(1, "Host is required"),
   is_backup: z.boolean(),
   max_connections: z.coerce
@@ -294,6 +295,7 @@ function UsenetServerFormSheet({
   const form = useAppForm({
     canSubmitWhenInvalid: true,
     defaultValues: {
+      compression: editItem?.compression ?? false,
       host: editItem?.host ?? "",
       is_backup: editItem?.is_backup ?? false,
       max_connections: editItem?.max_connections ?? 10,
@@ -309,6 +311,7 @@ function UsenetServerFormSheet({
       value = usenetServerSchema.parse(value);
       if (editItem) {
         await update.mutateAsync({
+          compression: value.compression,
           host: value.host,
           id: editItem.id,
           is_backup: value.is_backup,
@@ -324,6 +327,7 @@ function UsenetServerFormSheet({
         toast.success("Updated successfully!");
       } else {
         await create.mutateAsync({
+          compression: value.compression,
           host: value.host,
           is_backup: value.is_backup,
           max_connections: value.max_connections,
@@ -461,6 +465,9 @@ function UsenetServerFormSheet({
                   <field.Input label="Max Connections" type="number" />
                 )}
               </form.AppField>
+              <form.AppField name="compression">
+                {(field) => <field.Checkbox label="Use Compression" />}
+              </form.AppField>
             </div>
           </ScrollArea>
 
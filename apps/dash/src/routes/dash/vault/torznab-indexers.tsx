@@ -19,6 +19,7 @@ import {
 } from "@/api/ratelimit-config";
 import {
   TorznabIndexer,
+  TorznabIndexerType,
   useTorznabIndexerMutation,
   useTorznabIndexers,
 } from "@/api/vault-torznab-indexer";
@@ -72,6 +73,11 @@ declare module "@/components/data-table" {
 
 const col = createColumnHelper<TorznabIndexer>();
 
+const indexerTypeOptions: { label: string; value: TorznabIndexerType }[] = [
+  { label: "Jackett", value: "jackett" },
+  { label: "Prowlarr", value: "prowlarr" },
+];
+
 function RateLimitConfigName({ id }: { id: null | string }) {
   const conf = useRateLimitConfig(id);
   return conf ? conf.name : "-";
@@ -285,9 +291,15 @@ function TorznabIndexerFormSheet({
       api_key: "",
       name: editItem?.name ?? "",
       rate_limit_config_id: editItem?.rate_limit_config_id ?? "",
+      type: (editItem?.type ?? "jackett") as string,
       url: editItem?.url ?? "",
     }),
-    [editItem?.name, editItem?.rate_limit_config_id, editItem?.url],
+    [
+      editItem?.name,
+      editItem?.rate_limit_config_id,
+      editItem?.type,
+      editItem?.url,
+    ],
   );
 
   const form = useAppForm({
@@ -302,13 +314,18 @@ function TorznabIndexerFormSheet({
         });
         toast.success("Updated successfully!");
       } else {
-        await create.mutateAsync({
+        const data = await create.mutateAsync({
           api_key: value.api_key,
           name: value.name,
           rate_limit_config_id: value.rate_limit_config_id || null,
+          type: value.type as TorznabIndexerType,
           url: value.url,
         });
-        toast.success("Created successfully!");
+        toast.success(
+          Array.isArray(data)
+            ? `Added ${data.length} indexer(s) successfully!`
+            : "Created successfully!",
+        );
       }
       setIsOpen(false);
     },
@@ -338,12 +355,22 @@ function TorznabIndexerFormSheet({
             <SheetDescription>
               {editItem
                 ? "Update the API key for this Torznab indexer."
-                : "Add a Jackett indexer. The API key will be encrypted before storage."}
+                : "Add a Jackett or Prowlarr indexer. For Prowlarr, use the base URL to add all the enabled torrent indexers. The API key will be encrypted before storage."}
             </SheetDescription>
           </SheetHeader>
 
           <ScrollArea className="overflow-hidden">
             <div className="flex flex-col gap-4 px-4">
+              <form.AppField name="type">
+                {(field) => (
+                  <field.Select
+                    disabled={Boolean(editItem)}
+                    label="Type"
+                    required
+                    options={indexerTypeOptions}
+                  />
+                )}
+              </form.AppField>
               <form.AppField name="name">
                 {(field) => <field.Input label="Name" type="text" />}
               </form.AppField>
@@ -4,6 +4,7 @@ import {
   CheckCircle,
   Plus,
   RefreshCwIcon,
+  Settings2,
   Trash2,
   XCircle,
 } from "lucide-react";
@@ -20,6 +21,7 @@ import {
 } from "@/api/vault-trakt-account";
 import { DataTable } from "@/components/data-table";
 import { useDataTable } from "@/components/data-table/use-data-table";
+import { Form, useAppForm } from "@/components/form";
 import {
   AlertDialog,
   AlertDialogAction,
@@ -32,6 +34,14 @@ import {
   AlertDialogTrigger,
 } from "@/components/ui/alert-dialog";
 import { Button } from "@/components/ui/button";
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogHeader,
+  DialogTitle,
+  DialogTrigger,
+} from "@/components/ui/dialog";
 import { Spinner } from "@/components/ui/spinner";
 import {
   Tooltip,
@@ -45,6 +55,7 @@ declare module "@/components/data-table" {
     TraktAccount: {
       getAccount: ReturnType<typeof useTraktAccountMutation>["get"];
       removeAccount: ReturnType<typeof useTraktAccountMutation>["remove"];
+      updateAccount: ReturnType<typeof useTraktAccountMutation>["update"];
     };
   }
 
@@ -79,6 +90,20 @@ const columns: ColumnDef<TraktAccount>[] = [
     },
     header: "Validity",
   }),
+  col.accessor("scrobble_config", {
+    cell: ({ getValue }) => {
+      const config = getValue();
+      return config.enabled ? (
+        <span>
+          {config.users.join(", ") || "-"}{" "}
+          <span className="text-muted-foreground">({config.threshold}%)</span>
+        </span>
+      ) : (
+        <span className="text-muted-foreground">Disabled</span>
+      );
+    },
+    header: "Scrobble",
+  }),
   col.accessor("updated_at", {
     cell: ({ getValue }) => {
       const date = DateTime.fromISO(getValue());
@@ -88,10 +113,12 @@ const columns: ColumnDef<TraktAccount>[] = [
   }),
   col.display({
     cell: (c) => {
-      const { getAccount, removeAccount } = c.table.options.meta!.ctx;
+      const { getAccount, removeAccount, updateAccount } =
+        c.table.options.meta!.ctx;
       const item = c.row.original;
       return (
         <div className="flex gap-1">
+          <ScrobbleConfigDialog account={item} updateAccount={updateAccount} />
           <Tooltip>
             <TooltipTrigger asChild>
               <Button
@@ -175,6 +202,91 @@ const columns: ColumnDef<TraktAccount>[] = [
   }),
 ];
 
+function ScrobbleConfigDialog({
+  account,
+  updateAccount,
+}: {
+  account: TraktAccount;
+  updateAccount: ReturnType<typeof useTraktAccountMutation>["update"];
+}) {
+  const [open, setOpen] = useState(false);
+
+  const form = useAppForm({
+    defaultValues: {
+      enabled: account.scrobble_config.enabled,
+      threshold: String(account.scrobble_config.threshold),
+      users: account.scrobble_config.users.join(", "),
+    },
+    onSubmit: async ({ value }) => {
+      await updateAccount.mutateAsync({
+        id: account.id,
+        scrobble_config: {
+          enabled: value.enabled,
+          threshold: Number(value.threshold),
+          users: value.users
+            .split(",")
+            .map((user) => user.trim())
+            .filter(Boolean),
+        },
+      });
+      toast.success("Scrobble config updated!");
+      setOpen(false);
+    },
+  });
+
+  return (
+    <Dialog onOpenChange={setOpen} open={open}>
+      <Tooltip>
+        <TooltipTrigger asChild>
+          <DialogTrigger asChild>
+            <Button size="icon-sm" variant="ghost">
+              <Settings2 />
+            </Button>
+          </DialogTrigger>
+        </TooltipTrigger>
+        <TooltipContent>Scrobble</TooltipContent>
+      </Tooltip>
+      <DialogContent>
+        <DialogHeader>
+          <DialogTitle>Scrobble</DialogTitle>
+          <DialogDescription>
+            Scrobble proxied playback of the StremThru users to{" "}
+            <strong>{account.user_name}</strong>.
+          </DialogDescription>
+        </DialogHeader>
+        <Form className="flex flex-col gap-4" form={form}>
+          <form.AppField name="enabled">
+            {(field) => (
+              <field.Checkbox
+                description="Real-time scrobbling from proxied playback"
+                label="Enabled"
+              />
+            )}
+          </form.AppField>
+          <form.AppField name="users">
+            {(field) => (
+              <field.Input label="Users" placeholder="user1, user2" />
+            )}
+          </form.AppField>
+          <form.AppField name="threshold">
+            {(field) => (
+              <field.Input
+                label="Watched Threshold (%)"
+                max={100}
+                min={1}
+                type="number"
+              />
+            )}
+          </form.AppField>
+          <form.AppForm>
+            <form.SubmitButton className="w-full">Save</form.SubmitButton>
+          </form.AppForm>
+        </Form>
+      </DialogContent>
+    </Dialog>
+  );
+}
+
 export const Route = createFileRoute("/dash/vault/trakt-accounts")({
   component: RouteComponent,
   staticData: {
@@ -188,6 +300,7 @@ function RouteComponent() {
     create: createAccount,
     get: getAccount,
     remove: removeAccount,
+    update: updateAccount,
   } = useTraktAccountMutation();
 
   const [oauthState, setOauthState] = useState("");
@@ -274,6 +387,7 @@ function RouteComponent() {
       ctx: {
         getAccount,
         removeAccount,
+        updateAccount,
       },
     },
   });
@@ -296,6 +296,8 @@ function RouteComponent() {
       .toHuman({ maximumFractionDigits: 0 });
   }, [selectedWorkerId, workerDetails.data]);
 
+  const selectedWorkerLease = workerDetails.data?.[selectedWorkerId]?.lease;
+
   const table = useDataTable({
     columns: jobLogsColumns,
     data: jobLogs.data ?? [],
@@ -336,6 +338,16 @@ function RouteComponent() {
             <div>Interval: {selectedWorkerInterval}</div>
           )}
         </div>
+        {selectedWorkerLease && (
+          <div
+            className="text-muted-foreground text-sm"
+            title={`Renewed at ${DateTime.fromISO(selectedWorkerLease.renewed_at).toLocaleString(DateTime.DATETIME_MED_WITH_SECONDS)}`}
+          >
+            {selectedWorkerLease.is_active ? "Running on" : "Last ran on"}{" "}
+            <code>{selectedWorkerLease.holder.slice(0, 8)}</code>
+            {selectedWorkerLease.is_current_instance && " (this instance)"}
+          </div>
+        )}
       </div>
 
       <div>
@@ -76,7 +76,10 @@ export default defineConfig({
           { text: "Store", link: "/api/store" },
           { text: "Newz", link: "/api/newz" },
           { text: "Torz", link: "/api/torz" },
+          { text: "WebDL", link: "/api/webdl" },
           { text: "Meta", link: "/api/meta" },
+          { text: "WebDAV", link: "/api/webdav" },
+          { text: "Metrics", link: "/api/metrics" },
         ],
       },
       {
@@ -85,8 +88,11 @@ export default defineConfig({
           { text: "Overview", link: "/integrations/" },
           { text: "AniList", link: "/integrations/anilist" },
           { text: "GitHub", link: "/integrations/github" },
+          { text: "Kitsu", link: "/integrations/kitsu" },
           { text: "Letterboxd", link: "/integrations/letterboxd" },
           { text: "MDBList", link: "/integrations/mdblist" },
+          { text: "MyAnimeList", link: "/integrations/mal" },
+          { text: "Simkl", link: "/integrations/simkl" },
           { text: "TMDB", link: "/integrations/tmdb" },
           { text: "TVDB", link: "/integrations/tvdb" },
           { text: "Trakt", link: "/integrations/trakt" },
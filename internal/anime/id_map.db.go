@@ -180,15 +180,29 @@ var IdMapColumns = []string{
 }
 
 var query_get_id_map = fmt.Sprintf(
-	"SELECT %s FROM %s WHERE %s IN ",
+	"SELECT %s FROM %s WHERE ",
 	strings.Join(IdMapColumns, ","),
 	IdMapTableName,
-	IdMapColumn.AniList,
 )
 
 func GetIdMapsForAniList(ids []int) ([]AnimeIdMap, error) {
+	return getIdMaps(IdMapColumn.AniList, ids)
+}
+
+func GetIdMapsForKitsu(ids []int) ([]AnimeIdMap, error) {
+	return getIdMaps(IdMapColumn.Kitsu, ids)
+}
+
+func GetIdMapsForMAL(ids []int) ([]AnimeIdMap, error) {
+	return getIdMaps(IdMapColumn.MAL, ids)
+}
+
+func getIdMaps(column string, ids []int) ([]AnimeIdMap, error) {
 	count := len(ids)
-	query := query_get_id_map + "(" + util.RepeatJoin("?", count, ",") + ")"
+	if count == 0 {
+		return []AnimeIdMap{}, nil
+	}
+	query := query_get_id_map + column + " IN (" + util.RepeatJoin("?", count, ",") + ")"
 	args := make([]any, count)
 	for i := range ids {
 		args[i] = strconv.Itoa(ids[i])
@@ -478,6 +492,11 @@ func normalizeOptionalId(id string) string {
 	return id
 }
 
+// GetIdForService returns the id of the service in the id map.
+func (idMap *AnimeIdMap) GetIdForService(service string) string {
+	return getAnchorColumnValue(*idMap, service)
+}
+
 func getAnchorColumnValue(item AnimeIdMap, anchorColumnName string) string {
 	switch anchorColumnName {
 	case IdMapColumn.AniDB:
@@ -55,7 +55,12 @@ var defaultValueByEnv = map[string]map[string]string{
 	},
 	"": {
 		"STREMTHRU_BASE_URL":                               "http://localhost:8080",
+		"STREMTHRU_CONTENT_PROXY_CHUNK_CACHE_SIZE":         "0",
 		"STREMTHRU_CONTENT_PROXY_CONNECTION_LIMIT":         "*:0",
+		"STREMTHRU_CONTENT_PROXY_DAILY_QUOTA":              "*:0",
+		"STREMTHRU_CONTENT_PROXY_MAX_RATE":                 "*:0",
+		"STREMTHRU_CONTENT_PROXY_MONTHLY_QUOTA":            "*:0",
+		"STREMTHRU_CONTENT_PROXY_READ_AHEAD":               "4",
 		"STREMTHRU_DATABASE_URI":                           "sqlite://./data/stremthru.db",
 		"STREMTHRU_DATA_DIR":                               "./data",
 		"STREMTHRU_LANDING_PAGE":                           "{}",
@@ -66,9 +71,12 @@ var defaultValueByEnv = map[string]map[string]string{
 		"STREMTHRU_STORE_TUNNEL":                           "*:true",
 		"STREMTHRU_STORE_CLIENT_USER_AGENT":                "stremthru",
 		"STREMTHRU_INTEGRATION_ANILIST_LIST_STALE_TIME":    "12h",
+		"STREMTHRU_INTEGRATION_KITSU_LIST_STALE_TIME":      "12h",
 		"STREMTHRU_INTEGRATION_LETTERBOXD_LIST_STALE_TIME": "24h",
 		"STREMTHRU_INTEGRATION_LETTERBOXD_USER_AGENT":      "stremthru",
+		"STREMTHRU_INTEGRATION_MAL_LIST_STALE_TIME":        "12h",
 		"STREMTHRU_INTEGRATION_MDBLIST_LIST_STALE_TIME":    "12h",
+		"STREMTHRU_INTEGRATION_SIMKL_LIST_STALE_TIME":      "12h",
 		"STREMTHRU_INTEGRATION_TMDB_LIST_STALE_TIME":       "12h",
 		"STREMTHRU_INTEGRATION_TRAKT_LIST_STALE_TIME":      "12h",
 		"STREMTHRU_INTEGRATION_TVDB_LIST_STALE_TIME":       "12h",
@@ -80,6 +88,13 @@ var defaultValueByEnv = map[string]map[string]string{
 		"STREMTHRU_TORZ_TORRENT_FILE_CACHE_SIZE":           "256MB",
 		"STREMTHRU_TORZ_TORRENT_FILE_CACHE_TTL":            "6h",
 		"STREMTHRU_TORZ_TORRENT_FILE_MAX_SIZE":             "1MB",
+		"STREMTHRU_TORZ_TORRENT_CLIENT_LISTEN_PORT":        "42069",
+		"STREMTHRU_TORZ_TORRENT_CLIENT_SEED":               "true",
+		"STREMTHRU_TORZ_DHT_CRAWLER_ENABLED":               "false",
+		"STREMTHRU_TORZ_DHT_CRAWLER_LISTEN_PORT":           "42070",
+		"STREMTHRU_TORZ_DHT_CRAWLER_CONCURRENCY":           "20",
+		"STREMTHRU_TORZ_DHT_CRAWLER_MAX_PER_RUN":           "2000",
+		"STREMTHRU_TORZ_DHT_CRAWLER_MAX_TORRENTS":          "100000",
 		"STREMTHRU_STREMIO_TORZ_INDEXER_MAX_TIMEOUT":       "10s",
 		"STREMTHRU_STREMIO_TORZ_PUBLIC_MAX_INDEXER_COUNT":  "2",
 		"STREMTHRU_STREMIO_TORZ_PUBLIC_MAX_STORE_COUNT":    "3",
@@ -198,6 +213,16 @@ func (m AuthMap) GetPassword(user string) string {
 	return ""
 }
 
+// ListUsers returns the proxy auth users, sorted by name.
+func (m AuthMap) ListUsers() []string {
+	users := make([]string, 0, len(m.user_pass))
+	for user := range m.user_pass {
+		users = append(users, user)
+	}
+	slices.Sort(users)
+	return users
+}
+
 func (m AuthMap) IsAdmin(user string) bool {
 	isAdmin, ok := m.is_admin[user]
 	return ok && isAdmin
@@ -321,6 +346,32 @@ func (cpcl ContentProxyConnectionLimitMap) Get(user string) int {
 	return cpcl[user]
 }
 
+// ContentProxyByteLimitMap is size in bytes per user, 0 means no limit.
+type ContentProxyByteLimitMap map[string]int64
+
+func (cpbl ContentProxyByteLimitMap) Get(user string) int64 {
+	if limit, ok := cpbl[user]; ok {
+		return limit
+	}
+	return cpbl["*"]
+}
+
+func parseContentProxyByteLimit(name, value string) ContentProxyByteLimitMap {
+	limitMap := make(ContentProxyByteLimitMap)
+	for _, item := range strings.FieldsFunc(value, func(c rune) bool {
+		return c == ','
+	}) {
+		if user, limitStr, ok := strings.Cut(item, ":"); ok {
+			limit := util.ToBytes(limitStr)
+			if limit < 0 {
+				log.Fatalf("Invalid content proxy %s: %s", name, item)
+			}
+			limitMap[user] = limit
+		}
+	}
+	return limitMap
+}
+
 type storeContentCachedStaleTimeMapItem struct {
 	cached   time.Duration
 	uncached time.Duration
@@ -416,6 +467,11 @@ type Config struct {
 	StoreContentCachedStaleTime storeContentCachedStaleTimeMap
 	StoreClientUserAgent        string
 	ContentProxyConnectionLimit ContentProxyConnectionLimitMap
+	ContentProxyDailyQuota      ContentProxyByteLimitMap
+	ContentProxyMonthlyQuota    ContentProxyByteLimitMap
+	ContentProxyMaxRate         ContentProxyByteLimitMap
+	ContentProxyChunkCacheSize  int64
+	ContentProxyReadAhead       int
 	IP                          *IPResolver
 
 	DataDir     string
@@ -666,6 +722,11 @@ var config = func() Config {
 		StoreContentCachedStaleTime: storeContentCachedStaleTimeMap,
 		StoreClientUserAgent:        getEnv("STREMTHRU_STORE_CLIENT_USER_AGENT"),
 		ContentProxyConnectionLimit: contentProxyConnectionMap,
+		ContentProxyDailyQuota:      parseContentProxyByteLimit("daily quota", getEnv("STREMTHRU_CONTENT_PROXY_DAILY_QUOTA")),
+		ContentProxyMonthlyQuota:    parseContentProxyByteLimit("monthly quota", getEnv("STREMTHRU_CONTENT_PROXY_MONTHLY_QUOTA")),
+		ContentProxyMaxRate:         parseContentProxyByteLimit("max rate", getEnv("STREMTHRU_CONTENT_PROXY_MAX_RATE")),
+		ContentProxyChunkCacheSize:  util.ToBytes(getEnv("STREMTHRU_CONTENT_PROXY_CHUNK_CACHE_SIZE")),
+		ContentProxyReadAhead:       max(0, util.MustParseInt(getEnv("STREMTHRU_CONTENT_PROXY_READ_AHEAD"))),
 		IP: &IPResolver{
 			checker: getEnv("STREMTHRU_IP_CHECKER"),
 		},
@@ -699,6 +760,11 @@ var StoreContentProxy = config.StoreContentProxy
 var StoreContentCachedStaleTime = config.StoreContentCachedStaleTime
 var StoreClientUserAgent = config.StoreClientUserAgent
 var ContentProxyConnectionLimit = config.ContentProxyConnectionLimit
+var ContentProxyDailyQuota = config.ContentProxyDailyQuota
+var ContentProxyMonthlyQuota = config.ContentProxyMonthlyQuota
+var ContentProxyMaxRate = config.ContentProxyMaxRate
+var ContentProxyChunkCacheSize = config.ContentProxyChunkCacheSize
+var ContentProxyReadAhead = config.ContentProxyReadAhead
 var InstanceId = strings.ReplaceAll(uuid.NewString(), "-", "")
 var IP = config.IP
 
@@ -834,6 +900,15 @@ func PrintConfig(state *AppState) {
 			if cpcl := ContentProxyConnectionLimit.Get(user); cpcl > 0 {
 				l.Println("       content_proxy_connection_limit: " + strconv.FormatUint(uint64(cpcl), 10))
 			}
+			if quota := ContentProxyDailyQuota.Get(user); quota > 0 {
+				l.Println("       content_proxy_daily_quota: " + util.ToSize(quota))
+			}
+			if quota := ContentProxyMonthlyQuota.Get(user); quota > 0 {
+				l.Println("       content_proxy_monthly_quota: " + util.ToSize(quota))
+			}
+			if rate := ContentProxyMaxRate.Get(user); rate > 0 {
+				l.Println("       content_proxy_max_rate: " + util.ToSize(rate) + "/s")
+			}
 		}
 		l.Println()
 	}
@@ -862,6 +937,13 @@ func PrintConfig(state *AppState) {
 	}
 	l.Println()
 
+	if !IsPublicInstance && ContentProxyChunkCacheSize > 0 {
+		l.Println(" Content Proxy:")
+		l.Println("   chunk cache size: " + util.ToSize(ContentProxyChunkCacheSize))
+		l.Println("         read ahead: " + strconv.Itoa(ContentProxyReadAhead) + " chunks")
+		l.Println()
+	}
+
 	if len(Auth.admin_pass) == 1 {
 		for username, password := range Auth.admin_pass {
 			if strings.HasPrefix(username, "st-") {
@@ -990,7 +1072,7 @@ func PrintConfig(state *AppState) {
 	l.Println()
 
 	l.Println(" Integrations:")
-	for _, integration := range []string{"anilist.co", "bitmagnet.io", "github.com", "kitsu.app", "letterboxd.com", "mdblist.com", "themoviedb.org", "trakt.tv", "thetvdb.com"} {
+	for _, integration := range []string{"anilist.co", "bitmagnet.io", "github.com", "kitsu.app", "letterboxd.com", "mdblist.com", "myanimelist.net", "simkl.com", "themoviedb.org", "trakt.tv", "thetvdb.com"} {
 		switch integration {
 		case "anilist.co":
 			disabled := ""
@@ -1033,6 +1115,19 @@ func PrintConfig(state *AppState) {
 				l.Println("                 email: " + Integration.Kitsu.Email)
 				l.Println("              password: " + "*******")
 			}
+			if Feature.IsEnabled(FeatureAnime) {
+				l.Println("       list stale time: " + Integration.Kitsu.ListStaleTime.String())
+			}
+		case "myanimelist.net":
+			disabled := ""
+			if !Feature.IsEnabled(FeatureAnime) || !Integration.MAL.IsEnabled() {
+				disabled = " (disabled)"
+			}
+			l.Println("   - " + integration + disabled)
+			if disabled == "" {
+				l.Println("             client_id: " + Integration.MAL.ClientId[0:3] + "..." + Integration.MAL.ClientId[len(Integration.MAL.ClientId)-3:])
+				l.Println("       list stale time: " + Integration.MAL.ListStaleTime.String())
+			}
 		case "letterboxd.com":
 			hasIntegration := true
 			info := ""
@@ -1054,6 +1149,17 @@ func PrintConfig(state *AppState) {
 		case "mdblist.com":
 			l.Println("   - " + integration)
 			l.Println("       list stale time: " + Integration.MDBList.ListStaleTime.String())
+		case "simkl.com":
+			disabled := ""
+			if !Integration.Simkl.IsEnabled() {
+				disabled = " (disabled)"
+			}
+			l.Println("   - " + integration + disabled)
+			if disabled == "" {
+				l.Println("             client_id: " + Integration.Simkl.ClientId[0:3] + "..." + Integration.Simkl.ClientId[len(Integration.Simkl.ClientId)-3:])
+				l.Println("         client_secret: " + Integration.Simkl.ClientSecret[0:3] + "..." + Integration.Simkl.ClientSecret[len(Integration.Simkl.ClientSecret)-3:])
+				l.Println("       list stale time: " + Integration.Simkl.ListStaleTime.String())
+			}
 		case "themoviedb.org":
 			disabled := ""
 			if !Integration.TMDB.IsEnabled() {
@@ -1106,6 +1212,12 @@ func PrintConfig(state *AppState) {
 		l.Println("    torrent file cache ttl: " + Torz.TorrentFileCacheTTL.String())
 	}
 	l.Println("     torrent file max size: " + util.ToSize(Torz.TorrentFileMaxSize))
+	if Torz.DHTCrawlerEnabled {
+		l.Println("   dht crawler listen port: " + strconv.Itoa(Torz.DHTCrawlerListenPort))
+		l.Println("   dht crawler concurrency: " + strconv.Itoa(Torz.DHTCrawlerConcurrency))
+		l.Println("   dht crawler max per run: " + strconv.Itoa(Torz.DHTCrawlerMaxPerRun))
+		l.Println("  dht crawler max torrents: " + strconv.Itoa(Torz.DHTCrawlerMaxTorrents))
+	}
 	l.Println()
 
 	l.Println(" Instance ID:")
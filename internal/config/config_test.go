@@ -43,6 +43,21 @@ func (s *StoreContentCachedStaleTimeTestSuite) TestStoreContentCachedStaleTime()
 	s.Equal(staleTime.GetStaleTime(false, "torbox"), 8*time.Hour)
 }
 
+type ContentProxyByteLimitTestSuite struct {
+	suite.Suite
+}
+
+func (s *ContentProxyByteLimitTestSuite) TestContentProxyByteLimit() {
+	limit := parseContentProxyByteLimit("daily quota", "*:1GB,alice:0,bob:500MB")
+	s.Equal(int64(1024*1024*1024), limit.Get("carol"))
+	s.Equal(int64(0), limit.Get("alice"))
+	s.Equal(int64(500*1024*1024), limit.Get("bob"))
+
+	limit = parseContentProxyByteLimit("max rate", "")
+	s.Equal(int64(0), limit.Get("alice"))
+}
+
 func TestConfig(t *testing.T) {
 	suite.Run(t, new(StoreContentCachedStaleTimeTestSuite))
+	suite.Run(t, new(ContentProxyByteLimitTestSuite))
 }
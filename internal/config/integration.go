@@ -45,10 +45,29 @@ func (c integrationConfigLettterboxd) IsPiggybacked() bool {
 	return !c.IsEnabled() && HasPeer
 }
 
+type integrationConfigMAL struct {
+	ClientId      string
+	ListStaleTime time.Duration
+}
+
+func (c integrationConfigMAL) IsEnabled() bool {
+	return c.ClientId != ""
+}
+
 type integrationConfigMDBList struct {
 	ListStaleTime time.Duration
 }
 
+type integrationConfigSimkl struct {
+	ClientId      string
+	ClientSecret  string
+	ListStaleTime time.Duration
+}
+
+func (c integrationConfigSimkl) IsEnabled() bool {
+	return c.ClientId != "" && c.ClientSecret != ""
+}
+
 type integrationConfigTrakt struct {
 	ClientId      string
 	ClientSecret  string
@@ -60,10 +79,11 @@ func (c integrationConfigTrakt) IsEnabled() bool {
 }
 
 type integrationConfigKitsu struct {
-	ClientId     string
-	ClientSecret string
-	Email        string
-	Password     string
+	ClientId      string
+	ClientSecret  string
+	Email         string
+	Password      string
+	ListStaleTime time.Duration
 }
 
 func (c integrationConfigKitsu) HasDefaultCredentials() bool {
@@ -103,7 +123,9 @@ type IntegrationConfig struct {
 	Bitmagnet  integrationConfigBitmagnet
 	GitHub     integrationConfigGitHub
 	Letterboxd integrationConfigLettterboxd
+	MAL        integrationConfigMAL
 	MDBList    integrationConfigMDBList
+	Simkl      integrationConfigSimkl
 	Trakt      integrationConfigTrakt
 	Kitsu      integrationConfigKitsu
 	TMDB       integrationConfigTMDB
@@ -141,19 +163,29 @@ func parseIntegration() IntegrationConfig {
 			Token: getEnv("STREMTHRU_INTEGRATION_GITHUB_TOKEN"),
 		},
 		Letterboxd: letterboxd,
+		MAL: integrationConfigMAL{
+			ClientId:      getEnv("STREMTHRU_INTEGRATION_MAL_CLIENT_ID"),
+			ListStaleTime: mustParseDuration("mal list stale time", getEnv("STREMTHRU_INTEGRATION_MAL_LIST_STALE_TIME"), 15*time.Minute),
+		},
 		MDBList: integrationConfigMDBList{
 			ListStaleTime: mustParseDuration("mdblist list stale time", getEnv("STREMTHRU_INTEGRATION_MDBLIST_LIST_STALE_TIME"), 15*time.Minute),
 		},
+		Simkl: integrationConfigSimkl{
+			ClientId:      getEnv("STREMTHRU_INTEGRATION_SIMKL_CLIENT_ID"),
+			ClientSecret:  getEnv("STREMTHRU_INTEGRATION_SIMKL_CLIENT_SECRET"),
+			ListStaleTime: mustParseDuration("simkl list stale time", getEnv("STREMTHRU_INTEGRATION_SIMKL_LIST_STALE_TIME"), 15*time.Minute),
+		},
 		Trakt: integrationConfigTrakt{
 			ClientId:      getEnv("STREMTHRU_INTEGRATION_TRAKT_CLIENT_ID"),
 			ClientSecret:  getEnv("STREMTHRU_INTEGRATION_TRAKT_CLIENT_SECRET"),
 			ListStaleTime: mustParseDuration("trakt list stale time", getEnv("STREMTHRU_INTEGRATION_TRAKT_LIST_STALE_TIME"), 15*time.Minute),
 		},
 		Kitsu: integrationConfigKitsu{
-			ClientId:     getEnv("STREMTHRU_INTEGRATION_KITSU_CLIENT_ID"),
-			ClientSecret: getEnv("STREMTHRU_INTEGRATION_KITSU_CLIENT_SECRET"),
-			Email:        getEnv("STREMTHRU_INTEGRATION_KITSU_EMAIL"),
-			Password:     getEnv("STREMTHRU_INTEGRATION_KITSU_PASSWORD"),
+			ClientId:      getEnv("STREMTHRU_INTEGRATION_KITSU_CLIENT_ID"),
+			ClientSecret:  getEnv("STREMTHRU_INTEGRATION_KITSU_CLIENT_SECRET"),
+			Email:         getEnv("STREMTHRU_INTEGRATION_KITSU_EMAIL"),
+			Password:      getEnv("STREMTHRU_INTEGRATION_KITSU_PASSWORD"),
+			ListStaleTime: mustParseDuration("kitsu list stale time", getEnv("STREMTHRU_INTEGRATION_KITSU_LIST_STALE_TIME"), 15*time.Minute),
 		},
 		TMDB: integrationConfigTMDB{
 			AccessToken:   getEnv("STREMTHRU_INTEGRATION_TMDB_ACCESS_TOKEN"),
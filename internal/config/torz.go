@@ -1,22 +1,39 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/util"
 )
 
 type torzConfig struct {
-	TorrentFileCacheSize int64
-	TorrentFileCacheTTL  time.Duration
-	TorrentFileMaxSize   int64
+	TorrentFileCacheSize    int64
+	TorrentFileCacheTTL     time.Duration
+	TorrentFileMaxSize      int64
+	TorrentClientListenPort int
+	TorrentClientSeed       bool
+
+	DHTCrawlerEnabled     bool
+	DHTCrawlerListenPort  int
+	DHTCrawlerConcurrency int
+	DHTCrawlerMaxPerRun   int
+	DHTCrawlerMaxTorrents int
 }
 
 var Torz = func() torzConfig {
 	torz := torzConfig{
-		TorrentFileCacheSize: util.ToBytes(getEnv("STREMTHRU_TORZ_TORRENT_FILE_CACHE_SIZE")),
-		TorrentFileCacheTTL:  mustParseDuration("torz torrent file cache ttl", getEnv("STREMTHRU_TORZ_TORRENT_FILE_CACHE_TTL")),
-		TorrentFileMaxSize:   util.ToBytes(getEnv("STREMTHRU_TORZ_TORRENT_FILE_MAX_SIZE")),
+		TorrentFileCacheSize:    util.ToBytes(getEnv("STREMTHRU_TORZ_TORRENT_FILE_CACHE_SIZE")),
+		TorrentFileCacheTTL:     mustParseDuration("torz torrent file cache ttl", getEnv("STREMTHRU_TORZ_TORRENT_FILE_CACHE_TTL")),
+		TorrentFileMaxSize:      util.ToBytes(getEnv("STREMTHRU_TORZ_TORRENT_FILE_MAX_SIZE")),
+		TorrentClientListenPort: util.MustParseInt(getEnv("STREMTHRU_TORZ_TORRENT_CLIENT_LISTEN_PORT")),
+		TorrentClientSeed:       strings.ToLower(getEnv("STREMTHRU_TORZ_TORRENT_CLIENT_SEED")) == "true",
+
+		DHTCrawlerEnabled:     strings.ToLower(getEnv("STREMTHRU_TORZ_DHT_CRAWLER_ENABLED")) == "true",
+		DHTCrawlerListenPort:  util.MustParseInt(getEnv("STREMTHRU_TORZ_DHT_CRAWLER_LISTEN_PORT")),
+		DHTCrawlerConcurrency: max(1, util.MustParseInt(getEnv("STREMTHRU_TORZ_DHT_CRAWLER_CONCURRENCY"))),
+		DHTCrawlerMaxPerRun:   util.MustParseInt(getEnv("STREMTHRU_TORZ_DHT_CRAWLER_MAX_PER_RUN")),
+		DHTCrawlerMaxTorrents: util.MustParseInt(getEnv("STREMTHRU_TORZ_DHT_CRAWLER_MAX_TORRENTS")),
 	}
 
 	return torz
@@ -0,0 +1,394 @@
+package content_proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/metrics"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"golang.org/x/sync/singleflight"
+)
+
+var log = logger.Scoped("content_proxy")
+
+const ChunkSize = 8 * 1024 * 1024 // 8 MB
+
+const chunkFetchTimeout = 2 * time.Minute
+const chunkFetchMaxAttempts = 3
+
+type Chunk struct {
+	Data []byte
+}
+
+func (c Chunk) CacheSize() int64 {
+	return int64(len(c.Data))
+}
+
+type FileInfo struct {
+	Size        int64
+	ContentType string
+	// upstream headers replayed to the client
+	Headers map[string]string
+	// upstream does not support range requests, so it can not be cached
+	IsRangeNotSupported bool
+}
+
+var fileInfoHeaderKeys = []string{
+	"Content-Disposition",
+	"Etag",
+	"Last-Modified",
+}
+
+func IsEnabled() bool {
+	return config.ContentProxyChunkCacheSize > 0
+}
+
+var getChunkCache = sync.OnceValue(func() cache.Cache[Chunk] {
+	return cache.NewCache[Chunk](&cache.CacheConfig{
+		Name:       "content_proxy_chunk",
+		MaxSize:    config.ContentProxyChunkCacheSize,
+		DiskBacked: true,
+	})
+})
+
+var fileInfoCache = cache.NewCache[FileInfo](&cache.CacheConfig{
+	Name:     "content_proxy:file_info",
+	Lifetime: 12 * time.Hour,
+})
+
+// concurrent viewers of the same file share the in-flight chunk fetches
+var chunkFetchGroup singleflight.Group
+
+// File is the upstream file being proxied.
+type File struct {
+	// identifies the upstream file, shared across links and users
+	Id          string
+	URL         string
+	Headers     map[string]string
+	TunnelType  config.TunnelType
+	RefreshLink func() (string, error)
+
+	mu sync.Mutex
+}
+
+// isManifest checks the url extension, and the content type if it is known.
+func (f *File) isManifest(contentType string) bool {
+	f.mu.Lock()
+	link := f.URL
+	f.mu.Unlock()
+	fileURL, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	return shared.GetManifestType(contentType, fileURL) != ""
+}
+
+func (f *File) getChunkKey(idx int64) string {
+	return f.Id + ":" + strconv.FormatInt(idx, 10)
+}
+
+func (f *File) fetchChunk(idx int64, size int64) (*Chunk, *FileInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), chunkFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range f.Headers {
+		req.Header.Set(k, v)
+	}
+
+	start := idx * ChunkSize
+	end := start + ChunkSize - 1
+	if size > 0 {
+		end = min(end, size-1)
+	}
+
+	f.mu.Lock()
+	url := f.URL
+	f.mu.Unlock()
+
+	res, err := shared.RequestProxyRange(req, &url, f.TunnelType, start, end, f.RefreshLink)
+
+	f.mu.Lock()
+	f.URL = url
+	f.mu.Unlock()
+
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	_, rEnd, total, _ := shared.ParseContentRange(res.Header.Get("Content-Range"))
+	if total <= 0 {
+		return nil, nil, errors.New("unknown file size")
+	}
+
+	data := make([]byte, rEnd-start+1)
+	if _, err := io.ReadFull(res.Body, data); err != nil {
+		return nil, nil, err
+	}
+
+	info := &FileInfo{
+		Size:        total,
+		ContentType: res.Header.Get("Content-Type"),
+		Headers:     map[string]string{},
+	}
+	for _, key := range fileInfoHeaderKeys {
+		if value := res.Header.Get(key); value != "" {
+			info.Headers[key] = value
+		}
+	}
+	return &Chunk{Data: data}, info, nil
+}
+
+func (f *File) getChunk(idx int64, size int64) (*Chunk, error) {
+	key := f.getChunkKey(idx)
+
+	chunk := Chunk{}
+	if getChunkCache().Get(key, &chunk) {
+		metrics.RecordContentProxyChunkCacheLookup(true)
+		return &chunk, nil
+	}
+	metrics.RecordContentProxyChunkCacheLookup(false)
+
+	v, err, _ := chunkFetchGroup.Do(key, func() (any, error) {
+		var lastErr error
+		for attempt := range chunkFetchMaxAttempts {
+			if attempt > 0 {
+				time.Sleep(time.Duration(attempt) * 500 * time.Millisecond)
+			}
+			chunk, info, err := f.fetchChunk(idx, size)
+			if err != nil {
+				log.Warn("failed to fetch chunk", "error", err, "file_id", f.Id, "idx", idx, "attempt", attempt+1)
+				lastErr = err
+				continue
+			}
+			if err := fileInfoCache.Add(f.Id, *info); err != nil {
+				log.Warn("failed to cache file info", "error", err, "file_id", f.Id)
+			}
+			if !f.isManifest(info.ContentType) {
+				if err := getChunkCache().Add(key, *chunk); err != nil {
+					log.Warn("failed to cache chunk", "error", err, "file_id", f.Id, "idx", idx)
+				}
+			}
+			return chunk, nil
+		}
+		return nil, lastErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Chunk), nil
+}
+
+func (f *File) getInfo() (*FileInfo, error) {
+	info := FileInfo{}
+	if fileInfoCache.Get(f.Id, &info) {
+		return &info, nil
+	}
+	v, err, _ := chunkFetchGroup.Do(f.Id+":info", func() (any, error) {
+		chunk, info, err := f.fetchChunk(0, 0)
+		if errors.Is(err, shared.ErrRangeNotSupported) {
+			info = &FileInfo{IsRangeNotSupported: true}
+			if err := fileInfoCache.Add(f.Id, *info); err != nil {
+				log.Warn("failed to cache file info", "error", err, "file_id", f.Id)
+			}
+			return info, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := fileInfoCache.Add(f.Id, *info); err != nil {
+			log.Warn("failed to cache file info", "error", err, "file_id", f.Id)
+		}
+		// manifest is not served from cache, its links need to be rewritten
+		if !f.isManifest(info.ContentType) {
+			if err := getChunkCache().Add(f.getChunkKey(0), *chunk); err != nil {
+				log.Warn("failed to cache chunk", "error", err, "file_id", f.Id, "idx", 0)
+			}
+		}
+		return info, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*FileInfo), nil
+}
+
+var (
+	_ io.ReadSeeker = (*chunkStream)(nil)
+)
+
+// chunkStream reads the file chunk by chunk, while the chunks ahead of the
+// current one are fetched in background.
+type chunkStream struct {
+	ctx       context.Context
+	file      *File
+	size      int64
+	readAhead int64
+
+	pos      int64
+	chunk    *Chunk
+	chunkIdx int64
+
+	mu      sync.Mutex
+	readIdx int64
+	signal  chan struct{}
+}
+
+func newChunkStream(ctx context.Context, file *File, size int64) *chunkStream {
+	s := &chunkStream{
+		ctx:       ctx,
+		file:      file,
+		size:      size,
+		readAhead: int64(config.ContentProxyReadAhead),
+		chunkIdx:  -1,
+		readIdx:   -1,
+		signal:    make(chan struct{}, 1),
+	}
+	if s.readAhead > 0 {
+		go s.startReadAhead()
+	}
+	return s
+}
+
+func (s *chunkStream) lastChunkIdx() int64 {
+	return (s.size - 1) / ChunkSize
+}
+
+func (s *chunkStream) startReadAhead() {
+	next := int64(-1)
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.signal:
+		}
+
+		for s.ctx.Err() == nil {
+			s.mu.Lock()
+			idx := s.readIdx
+			s.mu.Unlock()
+
+			if next <= idx || next > idx+s.readAhead {
+				next = idx + 1
+			}
+			if next > idx+s.readAhead || next > s.lastChunkIdx() {
+				break
+			}
+			if !getChunkCache().Has(s.file.getChunkKey(next)) {
+				if _, err := s.file.getChunk(next, s.size); err != nil {
+					break
+				}
+			}
+			next++
+		}
+	}
+}
+
+func (s *chunkStream) Read(p []byte) (int, error) {
+	if s.pos >= s.size {
+		return 0, io.EOF
+	}
+
+	idx := s.pos / ChunkSize
+	if s.chunkIdx != idx {
+		chunk, err := s.file.getChunk(idx, s.size)
+		if err != nil {
+			return 0, err
+		}
+		offset := s.pos - idx*ChunkSize
+		if int64(len(chunk.Data)) <= offset {
+			return 0, io.ErrUnexpectedEOF
+		}
+		s.chunk, s.chunkIdx = chunk, idx
+
+		s.mu.Lock()
+		s.readIdx = idx
+		s.mu.Unlock()
+		select {
+		case s.signal <- struct{}{}:
+		default:
+		}
+	}
+
+	n := copy(p, s.chunk.Data[s.pos-idx*ChunkSize:])
+	s.pos += int64(n)
+	return n, nil
+}
+
+func (s *chunkStream) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = s.pos + offset
+	case io.SeekEnd:
+		pos = s.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("negative position")
+	}
+	s.pos = pos
+	return pos, nil
+}
+
+type countingResponseWriter struct {
+	http.ResponseWriter
+	bytesWritten int64
+}
+
+func (w *countingResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.bytesWritten += int64(n)
+	return n, err
+}
+
+// Serve serves the file through the chunk cache. If the file can not be served
+// from the cache, e.g. upstream does not support range requests or the file is
+// HLS/DASH manifest, it returns false without writing the response.
+func Serve(w http.ResponseWriter, r *http.Request, file *File) (ok bool, bytesWritten int64) {
+	if file.isManifest("") {
+		return false, 0
+	}
+
+	info, err := file.getInfo()
+	if err != nil {
+		log.Warn("failed to get file info", "error", err, "file_id", file.Id)
+		return false, 0
+	}
+	if info.IsRangeNotSupported || file.isManifest(info.ContentType) {
+		return false, 0
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stream := newChunkStream(ctx, file, info.Size)
+
+	if info.ContentType != "" {
+		w.Header().Set("Content-Type", info.ContentType)
+	}
+	for key, value := range info.Headers {
+		w.Header().Set(key, value)
+	}
+	// used by `http.ServeContent` for conditional requests
+	lastModified, _ := http.ParseTime(info.Headers["Last-Modified"])
+	cw := &countingResponseWriter{ResponseWriter: w}
+	http.ServeContent(cw, r, "", lastModified, stream)
+	return true, cw.bytesWritten
+}
@@ -0,0 +1,142 @@
+package content_proxy
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServe(t *testing.T) {
+	config.ContentProxyChunkCacheSize = 64 * 1024 * 1024
+	config.ContentProxyReadAhead = 2
+
+	content := make([]byte, 2*ChunkSize+1024)
+	for i := range content {
+		content[i] = byte(rand.IntN(256))
+	}
+
+	lastModified := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var requestCount atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		if r.URL.Path == "/no-range" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if r.URL.Path == "/manifest" {
+			w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+			http.ServeContent(w, r, "", time.Time{}, strings.NewReader("#EXTM3U\n"))
+			return
+		}
+		w.Header().Set("Content-Type", "video/mp4")
+		w.Header().Set("Content-Disposition", `attachment; filename="video.mp4"`)
+		http.ServeContent(w, r, "", lastModified, bytes.NewReader(content))
+	}))
+	defer upstream.Close()
+
+	newFile := func(id, path string) *File {
+		return &File{
+			Id:         id,
+			URL:        upstream.URL + path,
+			TunnelType: config.TUNNEL_TYPE_NONE,
+		}
+	}
+
+	t.Run("shares chunks", func(t *testing.T) {
+		requestCount.Store(0)
+		id := uuid.NewString()
+
+		var wg sync.WaitGroup
+		for range 2 {
+			wg.Go(func() {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				w := httptest.NewRecorder()
+				ok, bytesWritten := Serve(w, r, newFile(id, "/"))
+				assert.True(t, ok)
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, "video/mp4", w.Header().Get("Content-Type"))
+				assert.Equal(t, int64(len(content)), bytesWritten)
+				assert.True(t, bytes.Equal(content, w.Body.Bytes()))
+			})
+		}
+		wg.Wait()
+
+		assert.LessOrEqual(t, requestCount.Load(), int32(3))
+	})
+
+	t.Run("serves range from cache", func(t *testing.T) {
+		id := uuid.NewString()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		Serve(w, r, newFile(id, "/"))
+
+		requestCount.Store(0)
+		start, end := ChunkSize-10, ChunkSize+10
+		r = httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Range", "bytes="+strconv.Itoa(start)+"-"+strconv.Itoa(end))
+		w = httptest.NewRecorder()
+		ok, _ := Serve(w, r, newFile(id, "/"))
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusPartialContent, w.Code)
+		assert.True(t, bytes.Equal(content[start:end+1], w.Body.Bytes()))
+		assert.Equal(t, int32(0), requestCount.Load())
+	})
+
+	t.Run("replays upstream headers", func(t *testing.T) {
+		id := uuid.NewString()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		ok, _ := Serve(w, r, newFile(id, "/"))
+		assert.True(t, ok)
+		assert.Equal(t, `attachment; filename="video.mp4"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, lastModified.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+
+		requestCount.Store(0)
+		r = httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("If-Modified-Since", lastModified.Format(http.TimeFormat))
+		w = httptest.NewRecorder()
+		ok, _ = Serve(w, r, newFile(id, "/"))
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, int32(0), requestCount.Load())
+	})
+
+	t.Run("skips manifest", func(t *testing.T) {
+		requestCount.Store(0)
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		ok, _ := Serve(httptest.NewRecorder(), r, newFile(uuid.NewString(), "/index.m3u8"))
+		assert.False(t, ok)
+		assert.Equal(t, int32(0), requestCount.Load())
+
+		file := newFile(uuid.NewString(), "/manifest")
+		ok, _ = Serve(httptest.NewRecorder(), r, file)
+		assert.False(t, ok)
+		assert.False(t, getChunkCache().Has(file.getChunkKey(0)))
+	})
+
+	t.Run("falls back without range support", func(t *testing.T) {
+		id := uuid.NewString()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		ok, _ := Serve(w, r, newFile(id, "/no-range"))
+		assert.False(t, ok)
+		assert.Equal(t, 0, w.Body.Len())
+
+		requestCount.Store(0)
+		ok, _ = Serve(httptest.NewRecorder(), r, newFile(id, "/no-range"))
+		assert.False(t, ok)
+		assert.Equal(t, int32(0), requestCount.Load())
+	})
+}
@@ -0,0 +1,138 @@
+package content_proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"golang.org/x/time/rate"
+)
+
+var ErrQuotaExceeded = errors.New("content proxy quota exceeded")
+
+const usageFlushInterval = 10 * time.Second
+
+func hasQuota(user string) bool {
+	return config.ContentProxyDailyQuota.Get(user) > 0 || config.ContentProxyMonthlyQuota.Get(user) > 0
+}
+
+func IsQuotaExceeded(user string) (bool, error) {
+	dailyQuota := config.ContentProxyDailyQuota.Get(user)
+	monthlyQuota := config.ContentProxyMonthlyQuota.Get(user)
+	if dailyQuota == 0 && monthlyQuota == 0 {
+		return false, nil
+	}
+	daily, monthly, err := GetUsageBytes(user)
+	if err != nil {
+		return false, err
+	}
+	return (dailyQuota > 0 && daily >= dailyQuota) || (monthlyQuota > 0 && monthly >= monthlyQuota), nil
+}
+
+var rateLimiterByUser = map[string]*rate.Limiter{}
+var rateLimiterByUserMu sync.Mutex
+
+// all connections of the user share the same limiter
+func getRateLimiter(user string) *rate.Limiter {
+	maxRate := config.ContentProxyMaxRate.Get(user)
+	if maxRate <= 0 {
+		return nil
+	}
+
+	rateLimiterByUserMu.Lock()
+	defer rateLimiterByUserMu.Unlock()
+
+	limiter, ok := rateLimiterByUser[user]
+	if !ok {
+		burst := int(max(min(maxRate, 256*1024), 1))
+		limiter = rate.NewLimiter(rate.Limit(maxRate), burst)
+		rateLimiterByUser[user] = limiter
+	}
+	return limiter
+}
+
+// MeteredResponseWriter throttles the response to the max rate of the user,
+// and records the usage periodically. Once the quota is exceeded, further
+// writes fail with ErrQuotaExceeded.
+type MeteredResponseWriter struct {
+	http.ResponseWriter
+	ctx      context.Context
+	user     string
+	limiter  *rate.Limiter
+	hasQuota bool
+
+	pending   int64
+	flushedAt time.Time
+	exceeded  bool
+}
+
+func NewMeteredResponseWriter(w http.ResponseWriter, r *http.Request, user string) *MeteredResponseWriter {
+	return &MeteredResponseWriter{
+		ResponseWriter: w,
+		ctx:            r.Context(),
+		user:           user,
+		limiter:        getRateLimiter(user),
+		hasQuota:       hasQuota(user),
+		flushedAt:      time.Now(),
+	}
+}
+
+func (w *MeteredResponseWriter) Write(p []byte) (int, error) {
+	if w.exceeded {
+		return 0, ErrQuotaExceeded
+	}
+
+	written := 0
+	for len(p) > 0 {
+		n := len(p)
+		if w.limiter != nil {
+			n = min(n, w.limiter.Burst())
+			if err := w.limiter.WaitN(w.ctx, n); err != nil {
+				return written, err
+			}
+		}
+		m, err := w.ResponseWriter.Write(p[:n])
+		written += m
+		w.pending += int64(m)
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+
+	if time.Since(w.flushedAt) >= usageFlushInterval {
+		w.flush(w.hasQuota)
+	}
+	return written, nil
+}
+
+func (w *MeteredResponseWriter) flush(checkQuota bool) {
+	now := time.Now()
+	w.flushedAt = now
+
+	if w.pending > 0 {
+		if err := recordUsage(w.user, getUsageDate(now), w.pending); err != nil {
+			log.Error("failed to record usage", "error", err, "user", w.user)
+		} else {
+			w.pending = 0
+		}
+	}
+
+	if checkQuota {
+		exceeded, err := IsQuotaExceeded(w.user)
+		if err != nil {
+			log.Error("failed to check quota", "error", err, "user", w.user)
+		} else if exceeded {
+			log.Info("quota exceeded", "user", w.user)
+			w.exceeded = true
+		}
+	}
+}
+
+// Close records the pending usage.
+func (w *MeteredResponseWriter) Close() {
+	w.flush(false)
+}
@@ -0,0 +1,128 @@
+package content_proxy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+const UsageTableName = "content_proxy_usage"
+
+const usageDateLayout = time.DateOnly
+
+type Usage struct {
+	UserName string
+	Date     string
+	Bytes    int64
+	UAt      db.Timestamp
+}
+
+var UsageColumn = struct {
+	UserName string
+	Date     string
+	Bytes    string
+	UAt      string
+}{
+	UserName: "user_name",
+	Date:     "date",
+	Bytes:    "bytes",
+	UAt:      "uat",
+}
+
+var usage_columns = []string{
+	UsageColumn.UserName,
+	UsageColumn.Date,
+	UsageColumn.Bytes,
+	UsageColumn.UAt,
+}
+
+func getUsageDate(t time.Time) string {
+	return t.UTC().Format(usageDateLayout)
+}
+
+func getUsageMonthStartDate(t time.Time) string {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).Format(usageDateLayout)
+}
+
+var query_record_usage = fmt.Sprintf(
+	`INSERT INTO %s AS u (%s) VALUES (%s) ON CONFLICT (%s, %s) DO UPDATE SET %s = u.%s + EXCLUDED.%s, %s = %s`,
+	UsageTableName,
+	db.JoinColumnNames(UsageColumn.UserName, UsageColumn.Date, UsageColumn.Bytes),
+	util.RepeatJoin("?", 3, ","),
+	UsageColumn.UserName,
+	UsageColumn.Date,
+	UsageColumn.Bytes,
+	UsageColumn.Bytes,
+	UsageColumn.Bytes,
+	UsageColumn.UAt,
+	db.CurrentTimestamp,
+)
+
+func recordUsage(user string, date string, bytes int64) error {
+	_, err := db.Exec(query_record_usage, user, date, bytes)
+	return err
+}
+
+var query_get_usage_bytes = fmt.Sprintf(
+	`SELECT COALESCE(SUM(%s), 0) FROM %s WHERE %s = ? AND %s >= ? AND %s <= ?`,
+	UsageColumn.Bytes,
+	UsageTableName,
+	UsageColumn.UserName,
+	UsageColumn.Date,
+	UsageColumn.Date,
+)
+
+func getUsageBytes(user string, fromDate, toDate string) (int64, error) {
+	var bytes int64
+	if err := db.QueryRow(query_get_usage_bytes, user, fromDate, toDate).Scan(&bytes); err != nil {
+		return 0, err
+	}
+	return bytes, nil
+}
+
+// GetUsageBytes returns the usage for the current day and month.
+func GetUsageBytes(user string) (daily int64, monthly int64, err error) {
+	now := time.Now()
+	today := getUsageDate(now)
+	daily, err = getUsageBytes(user, today, today)
+	if err != nil {
+		return 0, 0, err
+	}
+	monthly, err = getUsageBytes(user, getUsageMonthStartDate(now), today)
+	if err != nil {
+		return 0, 0, err
+	}
+	return daily, monthly, nil
+}
+
+var query_get_usage_history = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s >= ? ORDER BY %s DESC, %s`,
+	strings.Join(usage_columns, ", "),
+	UsageTableName,
+	UsageColumn.Date,
+	UsageColumn.Date,
+	UsageColumn.UserName,
+)
+
+// GetUsageHistory returns the daily usage of all users since `since`.
+func GetUsageHistory(since time.Time) ([]Usage, error) {
+	rows, err := db.Query(query_get_usage_history, getUsageDate(since))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Usage{}
+	for rows.Next() {
+		item := Usage{}
+		if err := rows.Scan(&item.UserName, &item.Date, &item.Bytes, &item.UAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -2,10 +2,12 @@ package dash_api
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/content_proxy"
 	"github.com/MunifTanjim/stremthru/internal/shared"
 )
 
@@ -76,8 +78,82 @@ func handleProxifyLink(w http.ResponseWriter, r *http.Request) {
 	SendData(w, r, 200, proxifyLinkResponse{URL: proxyLink})
 }
 
+type ProxyUserUsage struct {
+	User         string `json:"user"`
+	Daily        int64  `json:"daily"`
+	Monthly      int64  `json:"monthly"`
+	DailyQuota   int64  `json:"daily_quota"`
+	MonthlyQuota int64  `json:"monthly_quota"`
+	MaxRate      int64  `json:"max_rate"`
+}
+
+type ProxyUsageHistoryItem struct {
+	User  string `json:"user"`
+	Date  string `json:"date"`
+	Bytes int64  `json:"bytes"`
+}
+
+type ProxyUsageResponse struct {
+	Users   []ProxyUserUsage        `json:"users"`
+	History []ProxyUsageHistoryItem `json:"history"`
+}
+
+func handleGetProxyUsage(w http.ResponseWriter, r *http.Request) {
+	if !shared.IsMethod(r, http.MethodGet) {
+		ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	days := 30
+	if v := r.URL.Query().Get("days"); v != "" {
+		d, err := strconv.Atoi(v)
+		if err != nil || d < 1 || d > 366 {
+			ErrorBadRequest(r).WithMessage("invalid days").Send(w, r)
+			return
+		}
+		days = d
+	}
+
+	data := ProxyUsageResponse{
+		Users:   []ProxyUserUsage{},
+		History: []ProxyUsageHistoryItem{},
+	}
+
+	for _, user := range config.Auth.ListUsers() {
+		daily, monthly, err := content_proxy.GetUsageBytes(user)
+		if err != nil {
+			SendError(w, r, err)
+			return
+		}
+		data.Users = append(data.Users, ProxyUserUsage{
+			User:         user,
+			Daily:        daily,
+			Monthly:      monthly,
+			DailyQuota:   config.ContentProxyDailyQuota.Get(user),
+			MonthlyQuota: config.ContentProxyMonthlyQuota.Get(user),
+			MaxRate:      config.ContentProxyMaxRate.Get(user),
+		})
+	}
+
+	history, err := content_proxy.GetUsageHistory(time.Now().AddDate(0, 0, -(days - 1)))
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	for _, item := range history {
+		data.History = append(data.History, ProxyUsageHistoryItem{
+			User:  item.UserName,
+			Date:  item.Date,
+			Bytes: item.Bytes,
+		})
+	}
+
+	SendData(w, r, 200, data)
+}
+
 func AddProxyEndpoints(router *http.ServeMux) {
 	authed := EnsureAuthed
 
 	router.HandleFunc("/proxy", authed(handleProxifyLink))
+	router.HandleFunc("/proxy/usage", authed(handleGetProxyUsage))
 }
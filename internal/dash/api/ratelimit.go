@@ -2,9 +2,11 @@ package dash_api
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/ratelimit"
+	"github.com/MunifTanjim/stremthru/store"
 )
 
 type RateLimitConfigResponse struct {
@@ -189,6 +191,14 @@ func handleDeleteRateLimitConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if storeNames, err := ratelimit.GetStoreNamesByRateLimitConfigId(id); err != nil {
+		SendError(w, r, err)
+		return
+	} else if len(storeNames) > 0 {
+		ErrorBadRequest(r).WithMessage("rate limit config is used by stores: "+strings.Join(storeNames, ", ")).Send(w, r)
+		return
+	}
+
 	if err := ratelimit.Delete(id); err != nil {
 		SendError(w, r, err)
 		return
@@ -197,6 +207,133 @@ func handleDeleteRateLimitConfig(w http.ResponseWriter, r *http.Request) {
 	SendData(w, r, 204, nil)
 }
 
+type StoreRateLimitResponse struct {
+	StoreName         string `json:"store_name"`
+	RateLimitConfigId string `json:"rate_limit_config_id"`
+	Mode              string `json:"mode"`
+	MaxWait           string `json:"max_wait"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
+}
+
+func toStoreRateLimitResponse(item *ratelimit.StoreRateLimit) StoreRateLimitResponse {
+	return StoreRateLimitResponse{
+		StoreName:         item.StoreName,
+		RateLimitConfigId: item.RateLimitConfigId,
+		Mode:              string(item.Mode),
+		MaxWait:           item.MaxWait,
+		CreatedAt:         item.CAt.Format(time.RFC3339),
+		UpdatedAt:         item.UAt.Format(time.RFC3339),
+	}
+}
+
+func handleGetStoreRateLimits(w http.ResponseWriter, r *http.Request) {
+	items, err := ratelimit.GetAllStoreRateLimits()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]StoreRateLimitResponse, len(items))
+	for i, item := range items {
+		data[i] = toStoreRateLimitResponse(&item)
+	}
+
+	SendData(w, r, 200, data)
+}
+
+func validateStoreRateLimitStoreName(storeName string) bool {
+	name := store.StoreName(storeName)
+	return name.IsValid() && name != store.StoreNameStremThru
+}
+
+type SetStoreRateLimitRequest struct {
+	RateLimitConfigId string `json:"rate_limit_config_id"`
+	Mode              string `json:"mode"`
+	MaxWait           string `json:"max_wait"`
+}
+
+func handleSetStoreRateLimit(w http.ResponseWriter, r *http.Request) {
+	storeName := r.PathValue("store_name")
+	if !validateStoreRateLimitStoreName(storeName) {
+		ErrorNotFound(r).WithMessage("store not found").Send(w, r)
+		return
+	}
+
+	request := &SetStoreRateLimitRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	if request.Mode == "" {
+		request.Mode = string(ratelimit.StoreRateLimitModeWait)
+	}
+	if request.MaxWait == "" {
+		request.MaxWait = "10s"
+	}
+
+	errs := []Error{}
+	if request.RateLimitConfigId == "" {
+		errs = append(errs, Error{
+			Location: "rate_limit_config_id",
+			Message:  "missing rate_limit_config_id",
+		})
+	} else if config, err := ratelimit.GetById(request.RateLimitConfigId); err != nil {
+		SendError(w, r, err)
+		return
+	} else if config == nil {
+		errs = append(errs, Error{
+			Location: "rate_limit_config_id",
+			Message:  "rate limit config not found",
+		})
+	}
+	if !ratelimit.StoreRateLimitMode(request.Mode).IsValid() {
+		errs = append(errs, Error{
+			Location: "mode",
+			Message:  "mode must be one of: wait, fail",
+		})
+	}
+	if maxWait, err := time.ParseDuration(request.MaxWait); err != nil || maxWait < 0 {
+		errs = append(errs, Error{
+			Location: "max_wait",
+			Message:  "invalid duration format (e.g., 5s, 30s, 1m)",
+		})
+	}
+
+	if len(errs) > 0 {
+		ErrorBadRequest(r).Append(errs...).Send(w, r)
+		return
+	}
+
+	item, err := ratelimit.SetStoreRateLimit(storeName, request.RateLimitConfigId, ratelimit.StoreRateLimitMode(request.Mode), request.MaxWait)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 200, toStoreRateLimitResponse(item))
+}
+
+func handleDeleteStoreRateLimit(w http.ResponseWriter, r *http.Request) {
+	storeName := r.PathValue("store_name")
+
+	if existing, err := ratelimit.GetStoreRateLimit(storeName); err != nil {
+		SendError(w, r, err)
+		return
+	} else if existing == nil {
+		ErrorNotFound(r).WithMessage("store rate limit not found").Send(w, r)
+		return
+	}
+
+	if err := ratelimit.DeleteStoreRateLimit(storeName); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
 func AddRateLimitEndpoints(router *http.ServeMux) {
 	authed := EnsureAuthed
 
@@ -210,6 +347,24 @@ func AddRateLimitEndpoints(router *http.ServeMux) {
 			ErrorMethodNotAllowed(r).Send(w, r)
 		}
 	}))
+	router.HandleFunc("/ratelimit/configs/stores", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetStoreRateLimits(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/ratelimit/configs/stores/{store_name}", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			handleSetStoreRateLimit(w, r)
+		case http.MethodDelete:
+			handleDeleteStoreRateLimit(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
 	router.HandleFunc("/ratelimit/configs/{id}", authed(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPatch:
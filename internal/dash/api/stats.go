@@ -10,10 +10,13 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/db"
 	"github.com/MunifTanjim/stremthru/internal/imdb_title"
+	"github.com/MunifTanjim/stremthru/internal/kitsu"
 	"github.com/MunifTanjim/stremthru/internal/letterboxd"
 	"github.com/MunifTanjim/stremthru/internal/magnet_cache"
+	"github.com/MunifTanjim/stremthru/internal/mal"
 	"github.com/MunifTanjim/stremthru/internal/mdblist"
 	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
 	"github.com/MunifTanjim/stremthru/internal/tmdb"
 	"github.com/MunifTanjim/stremthru/internal/torrent_info"
 	"github.com/MunifTanjim/stremthru/internal/torrent_stream"
@@ -107,6 +110,7 @@ type ServerStatsFeature struct {
 }
 
 type ServerStatsIntegration struct {
+	Simkl bool `json:"simkl"`
 	Trakt bool `json:"trakt"`
 }
 
@@ -130,6 +134,7 @@ func HandleGetServerStats(w http.ResponseWriter, r *http.Request) {
 			Vault: config.Feature.HasVault(),
 		},
 		Integration: ServerStatsIntegration{
+			Simkl: config.Integration.Simkl.IsEnabled(),
 			Trakt: config.Integration.Trakt.IsEnabled(),
 		},
 	}
@@ -141,14 +146,26 @@ type ListsStats struct {
 		TotalLists int `json:"total_lists"`
 		TotalItems int `json:"total_items"`
 	} `json:"anilist"`
+	Kitsu struct {
+		TotalLists int `json:"total_lists"`
+		TotalItems int `json:"total_items"`
+	} `json:"kitsu"`
 	Letterboxd struct {
 		TotalLists int `json:"total_lists"`
 		TotalItems int `json:"total_items"`
 	} `json:"letterboxd"`
+	MAL struct {
+		TotalLists int `json:"total_lists"`
+		TotalItems int `json:"total_items"`
+	} `json:"mal"`
 	MDBList struct {
 		TotalLists int `json:"total_lists"`
 		TotalItems int `json:"total_items"`
 	} `json:"mdblist"`
+	Simkl struct {
+		TotalLists int `json:"total_lists"`
+		TotalItems int `json:"total_items"`
+	} `json:"simkl"`
 	TMDB struct {
 		TotalLists int `json:"total_lists"`
 		TotalItems int `json:"total_items"`
@@ -175,10 +192,19 @@ UNION ALL
 SELECT COUNT(1) FROM %s UNION ALL SELECT COUNT(1) FROM %s
 UNION ALL
 SELECT COUNT(1) FROM %s UNION ALL SELECT COUNT(1) FROM %s
+UNION ALL
+SELECT COUNT(1) FROM %s UNION ALL SELECT COUNT(1) FROM %s
+UNION ALL
+SELECT COUNT(1) FROM %s UNION ALL SELECT COUNT(1) FROM %s
+UNION ALL
+SELECT COUNT(1) FROM %s UNION ALL SELECT COUNT(1) FROM %s
 `,
 	anilist.ListTableName, anilist.MediaTableName,
+	kitsu.ListTableName, kitsu.ItemTableName,
 	letterboxd.ListTableName, letterboxd.ItemTableName,
+	mal.ListTableName, mal.ItemTableName,
 	mdblist.ListTableName, mdblist.ItemTableName,
+	simkl.ListTableName, simkl.ItemTableName,
 	tmdb.ListTableName, tmdb.ItemTableName,
 	trakt.ListTableName, trakt.ItemTableName,
 	tvdb.ListTableName, tvdb.ItemTableName,
@@ -192,7 +218,7 @@ var cachedListsStats = cache.NewCachedValue(cache.CachedValueConfig[*ListsStats]
 		}
 		defer rows.Close()
 
-		counts := make([]int, 0, 12)
+		counts := make([]int, 0, 18)
 		for rows.Next() {
 			var count int
 			if err := rows.Scan(&count); err != nil {
@@ -204,16 +230,22 @@ var cachedListsStats = cache.NewCachedValue(cache.CachedValueConfig[*ListsStats]
 		stats := ListsStats{}
 		stats.AniList.TotalLists = counts[0]
 		stats.AniList.TotalItems = counts[1]
-		stats.Letterboxd.TotalLists = counts[2]
-		stats.Letterboxd.TotalItems = counts[3]
-		stats.MDBList.TotalLists = counts[4]
-		stats.MDBList.TotalItems = counts[5]
-		stats.TMDB.TotalLists = counts[6]
-		stats.TMDB.TotalItems = counts[7]
-		stats.Trakt.TotalLists = counts[8]
-		stats.Trakt.TotalItems = counts[9]
-		stats.TVDB.TotalLists = counts[10]
-		stats.TVDB.TotalItems = counts[11]
+		stats.Kitsu.TotalLists = counts[2]
+		stats.Kitsu.TotalItems = counts[3]
+		stats.Letterboxd.TotalLists = counts[4]
+		stats.Letterboxd.TotalItems = counts[5]
+		stats.MAL.TotalLists = counts[6]
+		stats.MAL.TotalItems = counts[7]
+		stats.MDBList.TotalLists = counts[8]
+		stats.MDBList.TotalItems = counts[9]
+		stats.Simkl.TotalLists = counts[10]
+		stats.Simkl.TotalItems = counts[11]
+		stats.TMDB.TotalLists = counts[12]
+		stats.TMDB.TotalItems = counts[13]
+		stats.Trakt.TotalLists = counts[14]
+		stats.Trakt.TotalItems = counts[15]
+		stats.TVDB.TotalLists = counts[16]
+		stats.TVDB.TotalItems = counts[17]
 
 		return &stats, nil
 	},
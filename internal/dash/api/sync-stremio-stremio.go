@@ -5,7 +5,9 @@ import (
 	"strings"
 	"time"
 
+	stremio_account "github.com/MunifTanjim/stremthru/internal/stremio/account"
 	sync_stremio_stremio "github.com/MunifTanjim/stremthru/internal/sync/stremio_stremio"
+	"github.com/MunifTanjim/stremthru/internal/worker"
 )
 
 type StremioStremioLinkResponse struct {
@@ -228,6 +230,233 @@ func handleResetStremioStremioLinkSyncState(w http.ResponseWriter, r *http.Reque
 	SendData(w, r, 200, toStremioStremioLinkResponse(link))
 }
 
+type StremioStremioGroupResponse struct {
+	Id         string                               `json:"id"`
+	Name       string                               `json:"name"`
+	AccountIds []string                             `json:"account_ids"`
+	SyncConfig sync_stremio_stremio.GroupSyncConfig `json:"sync_config"`
+	SyncState  sync_stremio_stremio.SyncState       `json:"sync_state"`
+	CreatedAt  string                               `json:"created_at"`
+	UpdatedAt  string                               `json:"updated_at"`
+}
+
+func toStremioStremioGroupResponse(item *sync_stremio_stremio.SyncStremioStremioGroup) StremioStremioGroupResponse {
+	resp := StremioStremioGroupResponse{
+		Id:         item.Id,
+		Name:       item.Name,
+		AccountIds: item.AccountIds,
+		SyncConfig: item.SyncConfig,
+		SyncState:  item.SyncState,
+		CreatedAt:  item.CAt.Format(time.RFC3339),
+		UpdatedAt:  item.UAt.Format(time.RFC3339),
+	}
+	if resp.AccountIds == nil {
+		resp.AccountIds = []string{}
+	}
+	if resp.SyncConfig.Watched.Ids == nil {
+		resp.SyncConfig.Watched.Ids = []string{}
+	}
+	return resp
+}
+
+func handleGetStremioStremioGroups(w http.ResponseWriter, r *http.Request) {
+	items, err := sync_stremio_stremio.GetAllGroups()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]StremioStremioGroupResponse, len(items))
+	for i, item := range items {
+		data[i] = toStremioStremioGroupResponse(&item)
+	}
+
+	SendData(w, r, 200, data)
+}
+
+type StremioStremioGroupRequest struct {
+	Name       string                               `json:"name"`
+	AccountIds []string                             `json:"account_ids"`
+	SyncConfig sync_stremio_stremio.GroupSyncConfig `json:"sync_config"`
+}
+
+func validateStremioStremioGroupRequest(request *StremioStremioGroupRequest) ([]Error, error) {
+	errs := []Error{}
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		errs = append(errs, Error{
+			Location: "name",
+			Message:  "missing name",
+		})
+	}
+	if len(request.AccountIds) < 2 {
+		errs = append(errs, Error{
+			Location: "account_ids",
+			Message:  "at least 2 accounts are required",
+		})
+	}
+	seen := map[string]struct{}{}
+	for _, accountId := range request.AccountIds {
+		if _, ok := seen[accountId]; ok {
+			errs = append(errs, Error{
+				Location: "account_ids",
+				Message:  "duplicate account: " + accountId,
+			})
+			continue
+		}
+		seen[accountId] = struct{}{}
+		account, err := stremio_account.GetById(accountId)
+		if err != nil {
+			return nil, err
+		}
+		if account == nil {
+			errs = append(errs, Error{
+				Location: "account_ids",
+				Message:  "account not found: " + accountId,
+			})
+		}
+	}
+	return errs, nil
+}
+
+func handleCreateStremioStremioGroup(w http.ResponseWriter, r *http.Request) {
+	request := &StremioStremioGroupRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	errs, err := validateStremioStremioGroupRequest(request)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if len(errs) > 0 {
+		ErrorBadRequest(r).Append(errs...).Send(w, r)
+		return
+	}
+
+	group, err := sync_stremio_stremio.CreateGroup(request.Name, request.AccountIds, request.SyncConfig)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 201, toStremioStremioGroupResponse(group))
+}
+
+func handleGetStremioStremioGroup(w http.ResponseWriter, r *http.Request) {
+	group, err := sync_stremio_stremio.GetGroupById(r.PathValue("id"))
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if group == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	SendData(w, r, 200, toStremioStremioGroupResponse(group))
+}
+
+func handleUpdateStremioStremioGroup(w http.ResponseWriter, r *http.Request) {
+	group, err := sync_stremio_stremio.GetGroupById(r.PathValue("id"))
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if group == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	request := &StremioStremioGroupRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	errs, err := validateStremioStremioGroupRequest(request)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if len(errs) > 0 {
+		ErrorBadRequest(r).Append(errs...).Send(w, r)
+		return
+	}
+
+	if err := sync_stremio_stremio.UpdateGroup(group.Id, request.Name, request.AccountIds, request.SyncConfig); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	group.Name = request.Name
+	group.AccountIds = request.AccountIds
+	group.SyncConfig = request.SyncConfig
+	SendData(w, r, 200, toStremioStremioGroupResponse(group))
+}
+
+func handleDeleteStremioStremioGroup(w http.ResponseWriter, r *http.Request) {
+	group, err := sync_stremio_stremio.GetGroupById(r.PathValue("id"))
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if group == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	if err := sync_stremio_stremio.DeleteGroup(group.Id); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
+func handleDryRunStremioStremioGroup(w http.ResponseWriter, r *http.Request) {
+	group, err := sync_stremio_stremio.GetGroupById(r.PathValue("id"))
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if group == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	plan, err := worker.PlanStremioStremioGroupSync(group)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 200, plan)
+}
+
+func handleResetStremioStremioGroupSyncState(w http.ResponseWriter, r *http.Request) {
+	group, err := sync_stremio_stremio.GetGroupById(r.PathValue("id"))
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if group == nil {
+		ErrorNotFound(r).Send(w, r)
+		return
+	}
+
+	group.SyncState.Watched.LastSyncedAt = nil
+
+	if err := sync_stremio_stremio.SetGroupSyncState(group.Id, group.SyncState); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 200, toStremioStremioGroupResponse(group))
+}
+
 func AddSyncStremioStremioEndpoints(router *http.ServeMux) {
 	authed := EnsureAuthed
 
@@ -269,4 +498,43 @@ func AddSyncStremioStremioEndpoints(router *http.ServeMux) {
 			ErrorMethodNotAllowed(r).Send(w, r)
 		}
 	}))
+
+	router.HandleFunc("/sync/stremio-stremio/groups", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetStremioStremioGroups(w, r)
+		case http.MethodPost:
+			handleCreateStremioStremioGroup(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-stremio/groups/{id}", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetStremioStremioGroup(w, r)
+		case http.MethodPatch:
+			handleUpdateStremioStremioGroup(w, r)
+		case http.MethodDelete:
+			handleDeleteStremioStremioGroup(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-stremio/groups/{id}/dry-run", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handleDryRunStremioStremioGroup(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/sync/stremio-stremio/groups/{id}/reset-sync-state", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handleResetStremioStremioGroupSyncState(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
 }
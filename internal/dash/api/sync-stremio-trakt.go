@@ -85,8 +85,9 @@ func handleCreateStremioTraktLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !request.SyncConfig.Watched.Direction.IsValid() {
-		ErrorBadRequest(r).WithMessage("invalid sync direction").Send(w, r)
+	request.SyncConfig.Normalize()
+	if errs := validateStremioTraktSyncConfig(&request.SyncConfig); len(errs) > 0 {
+		ErrorBadRequest(r).Append(errs...).Send(w, r)
 		return
 	}
 
@@ -99,6 +100,27 @@ func handleCreateStremioTraktLink(w http.ResponseWriter, r *http.Request) {
 	SendData(w, r, 201, toStremioTraktLinkResponse(link))
 }
 
+func validateStremioTraktSyncConfig(syncConfig *sync_stremio_trakt.SyncConfig) []Error {
+	errs := []Error{}
+	for _, section := range []struct {
+		name      string
+		direction sync_stremio_trakt.SyncDirection
+	}{
+		{"watched", syncConfig.Watched.Direction},
+		{"watchlist", syncConfig.Watchlist.Direction},
+		{"ratings", syncConfig.Ratings.Direction},
+		{"progress", syncConfig.Progress.Direction},
+	} {
+		if !section.direction.IsValid() {
+			errs = append(errs, Error{
+				Location: "sync_config." + section.name + ".dir",
+				Message:  "invalid sync direction",
+			})
+		}
+	}
+	return errs
+}
+
 func parseAccountIdPair(accountIdPair string) (stremioAccountId, traktAccountId string) {
 	stremioAccountId, traktAccountId, _ = strings.Cut(accountIdPair, ":")
 	return stremioAccountId, traktAccountId
@@ -143,8 +165,9 @@ func handleUpdateStremioTraktLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !request.SyncConfig.Watched.Direction.IsValid() {
-		ErrorBadRequest(r).WithMessage("invalid sync direction").Send(w, r)
+	request.SyncConfig.Normalize()
+	if errs := validateStremioTraktSyncConfig(&request.SyncConfig); len(errs) > 0 {
+		ErrorBadRequest(r).Append(errs...).Send(w, r)
 		return
 	}
 
@@ -208,7 +231,7 @@ func handleResetStremioTraktLinkSyncState(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	link.SyncState.Watched.LastSyncedAt = nil
+	link.SyncState = sync_stremio_trakt.SyncState{}
 
 	if err := sync_stremio_trakt.SetSyncState(
 		link.StremioAccountId,
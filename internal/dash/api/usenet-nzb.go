@@ -395,7 +395,7 @@ func handleRequeueNZB(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	queueId, err := nzb_info.QueueJob(info.User, info.Name, info.URL, "", 0, info.Password)
+	queueId, err := nzb_info.QueueJob(info.User, info.Name, info.URL, info.Category, 0, info.Password)
 	if err != nil {
 		SendError(w, r, err)
 		return
@@ -0,0 +1,172 @@
+package dash_api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/oauth"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
+	simkl_account "github.com/MunifTanjim/stremthru/internal/simkl/account"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+type SimklAccountResponse struct {
+	Id        string `json:"id"`
+	UserName  string `json:"user_name"`
+	IsValid   bool   `json:"is_valid"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func toSimklAccountResponse(item *simkl_account.SimklAccount) SimklAccountResponse {
+	username := ""
+	if otok := item.OAuthToken(); otok != nil {
+		username = otok.UserName
+	}
+	return SimklAccountResponse{
+		Id:        item.Id,
+		UserName:  username,
+		IsValid:   item.IsValid(),
+		CreatedAt: item.CAt.Format(time.RFC3339),
+		UpdatedAt: item.UAt.Format(time.RFC3339),
+	}
+}
+
+func handleGetSimklAccounts(w http.ResponseWriter, r *http.Request) {
+	items, err := simkl_account.GetAll()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	data := make([]SimklAccountResponse, len(items))
+	for i, item := range items {
+		data[i] = toSimklAccountResponse(&item)
+	}
+
+	SendData(w, r, 200, data)
+}
+
+type CreateSimklAccountRequest struct {
+	OAuthTokenId string `json:"oauth_token_id"`
+}
+
+func handleCreateSimklAccount(w http.ResponseWriter, r *http.Request) {
+	request := &CreateSimklAccountRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	if request.OAuthTokenId == "" {
+		ErrorBadRequest(r).Append(Error{
+			Location: "oauth_token_id",
+			Message:  "missing oauth_token_id",
+		}).Send(w, r)
+		return
+	}
+
+	account, err := simkl_account.Insert(request.OAuthTokenId)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 201, toSimklAccountResponse(account))
+}
+
+func handleGetSimklAccount(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	account, err := simkl_account.GetById(id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if account == nil {
+		ErrorNotFound(r).WithMessage("simkl account not found").Send(w, r)
+		return
+	}
+
+	forceRefresh := util.StringToBool(r.URL.Query().Get("refresh"), false)
+	if forceRefresh {
+		client := simkl.GetAPIClient(account.OAuthTokenId)
+		_, err := client.RetrieveSettings(&simkl.RetrieveSettingsParams{})
+		if err != nil {
+			SendError(w, r, err)
+			return
+		}
+	}
+
+	SendData(w, r, 200, toSimklAccountResponse(account))
+}
+
+func handleDeleteSimklAccount(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	existing, err := simkl_account.GetById(id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if existing == nil {
+		ErrorNotFound(r).WithMessage("simkl account not found").Send(w, r)
+		return
+	}
+
+	if err := simkl_account.Delete(id); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	SendData(w, r, 204, nil)
+}
+
+type SimklAuthURLResponse struct {
+	URL string `json:"url"`
+}
+
+func handleGetSimklAuthURL(w http.ResponseWriter, r *http.Request) {
+	state := r.URL.Query().Get("state")
+	authURL := oauth.SimklOAuthConfig.AuthCodeURL(state)
+	SendData(w, r, 200, SimklAuthURLResponse{
+		URL: authURL,
+	})
+}
+
+func AddVaultSimklEndpoints(router *http.ServeMux) {
+	if !config.Integration.Simkl.IsEnabled() {
+		return
+	}
+
+	authed := EnsureAuthed
+
+	router.HandleFunc("/vault/simkl/accounts", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetSimklAccounts(w, r)
+		case http.MethodPost:
+			handleCreateSimklAccount(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/vault/simkl/accounts/{id}", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetSimklAccount(w, r)
+		case http.MethodDelete:
+			handleDeleteSimklAccount(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	router.HandleFunc("/vault/simkl/auth/url", authed(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleGetSimklAuthURL(w, r)
+		default:
+			ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+}
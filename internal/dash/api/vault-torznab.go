@@ -9,6 +9,8 @@ import (
 
 	"github.com/MunifTanjim/stremthru/internal/ratelimit"
 	torznab_indexer "github.com/MunifTanjim/stremthru/internal/torznab/indexer"
+	"github.com/MunifTanjim/stremthru/internal/torznab/prowlarr"
+	"github.com/MunifTanjim/stremthru/internal/util"
 )
 
 type TorznabIndexerResponse struct {
@@ -96,16 +98,7 @@ func handleCreateTorznabIndexer(w http.ResponseWriter, r *http.Request) {
 		indexerType = torznab_indexer.IndexerTypeJackett
 	}
 
-	indexer, err := torznab_indexer.NewTorznabIndexer(indexerType, request.URL, request.APIKey)
-	if err != nil {
-		ErrorBadRequest(r).WithMessage("Invalid Torznab URL").WithCause(err).Send(w, r)
-		return
-	}
-
-	if request.Name != "" {
-		indexer.Name = request.Name
-	}
-
+	var rateLimitConfigId sql.NullString
 	if request.RateLimitConfigId != nil && *request.RateLimitConfigId != "" {
 		if rlc, err := ratelimit.GetById(*request.RateLimitConfigId); err != nil {
 			SendError(w, r, err)
@@ -117,12 +110,29 @@ func handleCreateTorznabIndexer(w http.ResponseWriter, r *http.Request) {
 			}).Send(w, r)
 			return
 		}
-		indexer.RateLimitConfigId = sql.NullString{
+		rateLimitConfigId = sql.NullString{
 			String: *request.RateLimitConfigId,
 			Valid:  true,
 		}
 	}
 
+	if indexerType == torznab_indexer.IndexerTypeProwlarr && !prowlarr.IsTorznabURL(request.URL) {
+		createProwlarrTorznabIndexers(w, r, request, rateLimitConfigId)
+		return
+	}
+
+	indexer, err := torznab_indexer.NewTorznabIndexer(indexerType, request.URL, request.APIKey)
+	if err != nil {
+		ErrorBadRequest(r).WithMessage("Invalid Torznab URL").WithCause(err).Send(w, r)
+		return
+	}
+
+	if request.Name != "" {
+		indexer.Name = request.Name
+	}
+
+	indexer.RateLimitConfigId = rateLimitConfigId
+
 	if err := indexer.Validate(); err != nil {
 		ErrorBadRequest(r).WithMessage("Invalid Torznab URL or API key").Send(w, r)
 		return
@@ -136,6 +146,43 @@ func handleCreateTorznabIndexer(w http.ResponseWriter, r *http.Request) {
 	SendData(w, r, 201, toTorznabIndexerResponse(indexer))
 }
 
+// createProwlarrTorznabIndexers adds an indexer for each of the enabled
+// torrent indexers in Prowlarr. Indexers that are already in the vault are
+// skipped.
+func createProwlarrTorznabIndexers(w http.ResponseWriter, r *http.Request, request *CreateTorznabIndexerRequest, rateLimitConfigId sql.NullString) {
+	indexers, err := torznab_indexer.NewProwlarrTorznabIndexers(request.URL, request.APIKey)
+	if err != nil {
+		ErrorBadRequest(r).WithMessage("Invalid Prowlarr URL or API key").WithCause(err).Send(w, r)
+		return
+	}
+
+	existingItems, err := torznab_indexer.GetAll()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	existingURLs := util.NewSet[string]()
+	for i := range existingItems {
+		existingURLs.Add(existingItems[i].URL)
+	}
+
+	data := []TorznabIndexerResponse{}
+	for i := range indexers {
+		indexer := &indexers[i]
+		if existingURLs.Has(indexer.URL) {
+			continue
+		}
+		indexer.RateLimitConfigId = rateLimitConfigId
+		if err := indexer.Insert(); err != nil {
+			SendError(w, r, err)
+			return
+		}
+		data = append(data, toTorznabIndexerResponse(indexer))
+	}
+
+	SendData(w, r, 201, data)
+}
+
 func parseTorznabIndexerId(r *http.Request) (int64, error) {
 	return strconv.ParseInt(r.PathValue("id"), 10, 64)
 }
@@ -2,21 +2,24 @@ package dash_api
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/oauth"
 	"github.com/MunifTanjim/stremthru/internal/trakt"
 	trakt_account "github.com/MunifTanjim/stremthru/internal/trakt/account"
+	trakt_scrobbler "github.com/MunifTanjim/stremthru/internal/trakt/scrobbler"
 	"github.com/MunifTanjim/stremthru/internal/util"
 )
 
 type TraktAccountResponse struct {
-	Id        string `json:"id"`
-	UserName  string `json:"user_name"`
-	IsValid   bool   `json:"is_valid"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	Id             string                       `json:"id"`
+	UserName       string                       `json:"user_name"`
+	IsValid        bool                         `json:"is_valid"`
+	ScrobbleConfig trakt_account.ScrobbleConfig `json:"scrobble_config"`
+	CreatedAt      string                       `json:"created_at"`
+	UpdatedAt      string                       `json:"updated_at"`
 }
 
 func toTraktAccountResponse(item *trakt_account.TraktAccount) TraktAccountResponse {
@@ -24,12 +27,18 @@ func toTraktAccountResponse(item *trakt_account.TraktAccount) TraktAccountRespon
 	if otok := item.OAuthToken(); otok != nil {
 		username = otok.UserName
 	}
+	scrobbleConfig := item.ScrobbleConfig
+	if scrobbleConfig.Users == nil {
+		scrobbleConfig.Users = []string{}
+	}
+	scrobbleConfig.Threshold = scrobbleConfig.GetThreshold()
 	return TraktAccountResponse{
-		Id:        item.Id,
-		UserName:  username,
-		IsValid:   item.IsValid(),
-		CreatedAt: item.CAt.Format(time.RFC3339),
-		UpdatedAt: item.UAt.Format(time.RFC3339),
+		Id:             item.Id,
+		UserName:       username,
+		IsValid:        item.IsValid(),
+		ScrobbleConfig: scrobbleConfig,
+		CreatedAt:      item.CAt.Format(time.RFC3339),
+		UpdatedAt:      item.UAt.Format(time.RFC3339),
 	}
 }
 
@@ -101,6 +110,59 @@ func handleGetTraktAccount(w http.ResponseWriter, r *http.Request) {
 	SendData(w, r, 200, toTraktAccountResponse(account))
 }
 
+type UpdateTraktAccountRequest struct {
+	ScrobbleConfig trakt_account.ScrobbleConfig `json:"scrobble_config"`
+}
+
+func handleUpdateTraktAccount(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	request := &UpdateTraktAccountRequest{}
+	if err := ReadRequestBodyJSON(r, request); err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	account, err := trakt_account.GetById(id)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	if account == nil {
+		ErrorNotFound(r).WithMessage("trakt account not found").Send(w, r)
+		return
+	}
+
+	errs := []Error{}
+	if threshold := request.ScrobbleConfig.Threshold; threshold < 0 || threshold > 100 {
+		errs = append(errs, Error{
+			Location: "scrobble_config.threshold",
+			Message:  "threshold must be between 0 and 100",
+		})
+	}
+	for i, user := range request.ScrobbleConfig.Users {
+		if config.Auth.GetPassword(user) == "" {
+			errs = append(errs, Error{
+				Location: "scrobble_config.users[" + strconv.Itoa(i) + "]",
+				Message:  "unknown user: " + user,
+			})
+		}
+	}
+	if len(errs) > 0 {
+		ErrorBadRequest(r).Append(errs...).Send(w, r)
+		return
+	}
+
+	if err := trakt_account.SetScrobbleConfig(id, request.ScrobbleConfig); err != nil {
+		SendError(w, r, err)
+		return
+	}
+	trakt_scrobbler.InvalidateAccounts()
+
+	account.ScrobbleConfig = request.ScrobbleConfig
+	SendData(w, r, 200, toTraktAccountResponse(account))
+}
+
 func handleDeleteTraktAccount(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
@@ -118,6 +180,7 @@ func handleDeleteTraktAccount(w http.ResponseWriter, r *http.Request) {
 		SendError(w, r, err)
 		return
 	}
+	trakt_scrobbler.InvalidateAccounts()
 
 	SendData(w, r, 204, nil)
 }
@@ -155,6 +218,8 @@ func AddVaultTraktEndpoints(router *http.ServeMux) {
 		switch r.Method {
 		case http.MethodGet:
 			handleGetTraktAccount(w, r)
+		case http.MethodPatch:
+			handleUpdateTraktAccount(w, r)
 		case http.MethodDelete:
 			handleDeleteTraktAccount(w, r)
 		default:
@@ -21,6 +21,7 @@ type UsenetServerResponse struct {
 	Priority       int    `json:"priority"`
 	IsBackup       bool   `json:"is_backup"`
 	MaxConnections int    `json:"max_connections"`
+	Compression    bool   `json:"compression"`
 	Disabled       bool   `json:"disabled"`
 	CreatedAt      string `json:"created_at"`
 	UpdatedAt      string `json:"updated_at"`
@@ -38,6 +39,7 @@ func toUsenetServerResponse(item *usenet_server.UsenetServer) UsenetServerRespon
 		Priority:       item.Priority,
 		IsBackup:       item.IsBackup,
 		MaxConnections: item.MaxConnections,
+		Compression:    item.Compression,
 		Disabled:       item.Disabled,
 		CreatedAt:      item.CAt.Format(time.RFC3339),
 		UpdatedAt:      item.UAt.Format(time.RFC3339),
@@ -70,6 +72,7 @@ type CreateUsenetServerRequest struct {
 	Priority       int    `json:"priority"`
 	IsBackup       bool   `json:"is_backup"`
 	MaxConnections int    `json:"max_connections"`
+	Compression    bool   `json:"compression"`
 }
 
 func handleCreateUsenetServer(w http.ResponseWriter, r *http.Request) {
@@ -120,6 +123,7 @@ func handleCreateUsenetServer(w http.ResponseWriter, r *http.Request) {
 		request.Priority,
 		request.IsBackup,
 		request.MaxConnections,
+		request.Compression,
 	)
 	if err != nil {
 		SendError(w, r, err)
@@ -162,6 +166,7 @@ type UpdateUsenetServerRequest struct {
 	Priority       *int   `json:"priority"`
 	IsBackup       *bool  `json:"is_backup"`
 	MaxConnections *int   `json:"max_connections"`
+	Compression    *bool  `json:"compression"`
 }
 
 func handleUpdateUsenetServer(w http.ResponseWriter, r *http.Request) {
@@ -225,6 +230,9 @@ func handleUpdateUsenetServer(w http.ResponseWriter, r *http.Request) {
 			server.MaxConnections = 10
 		}
 	}
+	if request.Compression != nil {
+		server.Compression = *request.Compression
+	}
 
 	newProviderId := server.ProviderId()
 
@@ -277,6 +285,7 @@ type PingUsenetServerRequest struct {
 	Password      string `json:"password"`
 	TLS           bool   `json:"tls"`
 	TLSSkipVerify bool   `json:"tls_skip_verify"`
+	Compression   bool   `json:"compression"`
 }
 
 type PingUsenetServerResponse struct {
@@ -334,6 +343,7 @@ func handlePingUsenetServer(w http.ResponseWriter, r *http.Request) {
 		Password:      request.Password,
 		TLS:           request.TLS,
 		TLSSkipVerify: request.TLSSkipVerify,
+		Compression:   request.Compression,
 		Deadline:      time.Now().Add(15 * time.Second),
 		DialTimeout:   10 * time.Second,
 		KeepAliveTime: 20 * time.Second,
@@ -347,8 +357,17 @@ func handlePingUsenetServer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	message := "Connection Successful!"
+	if request.Compression {
+		if compression := conn.Compression(); compression != nntp.CompressionNone {
+			message += " Compression: " + compression.String()
+		} else {
+			message += " Compression not supported."
+		}
+	}
+
 	SendData(w, r, 200, PingUsenetServerResponse{
-		Message: "Connection Successful!",
+		Message: message,
 	})
 }
 
@@ -13,16 +13,27 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/imdb_title"
 	"github.com/MunifTanjim/stremthru/internal/job"
 	"github.com/MunifTanjim/stremthru/internal/job_log"
+	"github.com/MunifTanjim/stremthru/internal/lease"
 	"github.com/MunifTanjim/stremthru/internal/shared"
 	"github.com/MunifTanjim/stremthru/internal/util"
 	"github.com/MunifTanjim/stremthru/internal/worker"
 )
 
+type WorkerLease struct {
+	Holder            string `json:"holder"`
+	IsActive          bool   `json:"is_active"`
+	IsCurrentInstance bool   `json:"is_current_instance"`
+	AcquiredAt        string `json:"acquired_at"`
+	RenewedAt         string `json:"renewed_at"`
+	ExpiresAt         string `json:"expires_at"`
+}
+
 type WorkerDetails struct {
 	Id           string        `json:"id"`
 	Title        string        `json:"title"`
 	Interval     time.Duration `json:"interval"`
 	HasFailedJob bool          `json:"has_failed_job"`
+	Lease        *WorkerLease  `json:"lease"`
 }
 
 func handleGetWorkersDetails(w http.ResponseWriter, r *http.Request) {
@@ -61,6 +72,30 @@ func handleGetWorkersDetails(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	leases, err := lease.GetAll()
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+
+	for i := range leases {
+		l := &leases[i]
+		kind, name, _ := strings.Cut(l.Name, ":")
+		if kind != "worker" && kind != "job" {
+			continue
+		}
+		if workerResp, ok := data[name]; ok {
+			workerResp.Lease = &WorkerLease{
+				Holder:            l.Holder,
+				IsActive:          l.IsActive(),
+				IsCurrentInstance: l.Holder == config.InstanceId,
+				AcquiredAt:        l.AcquiredAt.Format(time.RFC3339),
+				RenewedAt:         l.RenewedAt.Format(time.RFC3339),
+				ExpiresAt:         l.ExpiresAt.Format(time.RFC3339),
+			}
+		}
+	}
+
 	SendData(w, r, 200, data)
 }
 
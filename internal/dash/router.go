@@ -40,6 +40,7 @@ func AddEndpoints(mux *http.ServeMux) {
 
 	if config.Feature.HasVault() {
 		dash_api.AddVaultStremioEndpoints(router)
+		dash_api.AddVaultSimklEndpoints(router)
 		dash_api.AddVaultTraktEndpoints(router)
 		dash_api.AddVaultTorznabEndpoints(router)
 		dash_api.AddUsenetNZBEndpoints(router)
@@ -49,6 +50,9 @@ func AddEndpoints(mux *http.ServeMux) {
 		dash_api.AddVaultNewznabEndpoints(router)
 		dash_api.AddNzbQueueEndpoints(router)
 		dash_api.AddSyncStremioStremioEndpoints(router)
+		if config.Integration.Simkl.IsEnabled() {
+			dash_api.AddSyncStremioSimklEndpoints(router)
+		}
 		if config.Integration.Trakt.IsEnabled() {
 			dash_api.AddSyncStremioTraktEndpoints(router)
 		}
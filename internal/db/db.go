@@ -200,6 +200,26 @@ func Open() *DB {
 	return db
 }
 
+// OpenSQLite opens the sqlite database at path, in place of the configured
+// one. It is meant for tests.
+func OpenSQLite(path string) (*DB, error) {
+	if connUri.Dialect != DBDialectSQLite {
+		return nil, errors.New("[db] configured dialect is not sqlite")
+	}
+	dsn := connUri.DSN(append(dsnModifiers, func(u *url.URL, q *url.Values) {
+		u.Host = ""
+		u.Path = path
+	})...)
+	database, err := sql.Open(connUri.DriverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.DB = database
+	db.URI = connUri
+	db.onClose = nil
+	return db, nil
+}
+
 func Close() error {
 	err := db.Close()
 	if db.onClose != nil {
@@ -0,0 +1,40 @@
+// Package dbtest sets up the database for tests.
+package dbtest
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/pressly/goose/v3"
+)
+
+var migrationsDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..", "migrations", "sqlite")
+}()
+
+// Open sets up a fresh sqlite database with the schema migrations applied,
+// it is closed when the test ends.
+func Open(t testing.TB) {
+	t.Helper()
+
+	database, err := db.OpenSQLite(filepath.Join(t.TempDir(), "stremthru.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	goose.SetBaseFS(nil)
+	goose.SetTableName("db_migration_version")
+	goose.SetLogger(goose.NopLogger())
+	if err := goose.SetDialect("sqlite"); err != nil {
+		t.Fatalf("failed to set migration dialect: %v", err)
+	}
+	if err := goose.Up(database.DB, migrationsDir); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+}
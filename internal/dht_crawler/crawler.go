@@ -0,0 +1,336 @@
+package dht_crawler
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/anacrolix/dht/v2"
+	"github.com/anacrolix/dht/v2/krpc"
+	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/anacrolix/torrent/storage"
+)
+
+const (
+	maxQueuedNodes         = 10000
+	maxSeen                = 100000
+	samplerConcurrency     = 8
+	sampleQueryTimeout     = 5 * time.Second
+	defaultMetadataTimeout = 30 * time.Second
+)
+
+type File struct {
+	Idx  int
+	Path string
+	Size int64
+}
+
+type Torrent struct {
+	Hash    string
+	Name    string
+	Size    int64
+	Private bool
+	Files   []File
+}
+
+type Config struct {
+	DataDir         string
+	ListenPort      int
+	Concurrency     int
+	MetadataTimeout time.Duration
+	// Filter returns the hashes that should be fetched, out of the sampled
+	// ones.
+	Filter func(hashes []string) ([]string, error)
+	Log    *logger.Logger
+}
+
+type Crawler struct {
+	client *torrent.Client
+	server *dht.Server
+	conf   Config
+	log    *logger.Logger
+}
+
+func NewCrawler(conf *Config) (*Crawler, error) {
+	if conf.Concurrency < 1 {
+		conf.Concurrency = 1
+	}
+	if conf.MetadataTimeout == 0 {
+		conf.MetadataTimeout = defaultMetadataTimeout
+	}
+	if conf.Filter == nil {
+		conf.Filter = func(hashes []string) ([]string, error) {
+			return hashes, nil
+		}
+	}
+	if conf.Log == nil {
+		conf.Log = logger.Scoped("dht_crawler")
+	}
+
+	if err := os.MkdirAll(conf.DataDir, 0755); err != nil {
+		return nil, err
+	}
+
+	cfg := torrent.NewDefaultClientConfig()
+	cfg.DataDir = conf.DataDir
+	cfg.DefaultStorage = storage.NewFileByInfoHash(conf.DataDir)
+	cfg.ListenPort = conf.ListenPort
+	cfg.NoUpload = true
+	cfg.Seed = false
+	cfg.NoDefaultPortForwarding = true
+
+	client, err := torrent.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Crawler{
+		client: client,
+		conf:   *conf,
+		log:    conf.Log,
+	}
+	for _, s := range client.DhtServers() {
+		if w, ok := s.(torrent.AnacrolixDhtServerWrapper); ok {
+			c.server = w.Server
+			break
+		}
+	}
+	if c.server == nil {
+		c.Close()
+		return nil, errors.New("dht server not available")
+	}
+	return c, nil
+}
+
+func (c *Crawler) Close() {
+	c.client.Close()
+	<-c.client.Closed()
+	if err := os.RemoveAll(c.conf.DataDir); err != nil {
+		c.log.Warn("failed to remove data dir", "error", err, "path", c.conf.DataDir)
+	}
+}
+
+type sampleResult struct {
+	hashes []metainfo.Hash
+	nodes  []krpc.NodeInfo
+}
+
+// sample sends a BEP 51 `sample_infohashes` query to the node.
+func (c *Crawler) sample(ctx context.Context, addr krpc.NodeAddr) (*sampleResult, error) {
+	var target krpc.ID
+	if _, err := rand.Read(target[:]); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, sampleQueryTimeout)
+	defer cancel()
+	res := c.server.Query(ctx, dht.NewAddr(addr.UDP()), "sample_infohashes", dht.QueryInput{
+		MsgArgs:  krpc.MsgArgs{Target: target},
+		NumTries: 1,
+	})
+	if err := res.ToError(); err != nil {
+		return nil, err
+	}
+	result := &sampleResult{}
+	if res.Reply.R == nil {
+		return result, nil
+	}
+	if samples := res.Reply.R.Samples; samples != nil {
+		result.hashes = make([]metainfo.Hash, len(*samples))
+		for i, sample := range *samples {
+			result.hashes[i] = metainfo.Hash(sample)
+		}
+	}
+	res.Reply.R.ForAllNodes(func(ni krpc.NodeInfo) {
+		result.nodes = append(result.nodes, ni)
+	})
+	return result, nil
+}
+
+// fetch gets the metadata for the hash from the peers using BEP 9.
+func (c *Crawler) fetch(ctx context.Context, hash metainfo.Hash) (*Torrent, error) {
+	t, _ := c.client.AddTorrentInfoHash(hash)
+	defer t.Drop()
+
+	ctx, cancel := context.WithTimeout(ctx, c.conf.MetadataTimeout)
+	defer cancel()
+
+	select {
+	case <-t.GotInfo():
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	info := t.Info()
+	if info == nil {
+		return nil, errors.New("missing info")
+	}
+
+	tor := &Torrent{
+		Hash:    hash.HexString(),
+		Name:    t.Name(),
+		Size:    t.Length(),
+		Private: info.Private != nil && *info.Private,
+	}
+	files := t.Files()
+	tor.Files = make([]File, len(files))
+	for i, f := range files {
+		tor.Files[i] = File{
+			Idx:  i,
+			Path: "/" + f.Path(),
+			Size: f.Length(),
+		}
+	}
+	return tor, nil
+}
+
+// Run samples infohashes from the DHT and fetches their metadata, until the
+// context is done. The returned channel is closed once it stops.
+func (c *Crawler) Run(ctx context.Context) <-chan Torrent {
+	log := c.log
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	results := make(chan Torrent)
+	hashes := make(chan metainfo.Hash, c.conf.Concurrency)
+
+	var fetchWg sync.WaitGroup
+	for range c.conf.Concurrency {
+		fetchWg.Go(func() {
+			for hash := range hashes {
+				t, err := c.fetch(ctx, hash)
+				if err != nil {
+					log.Trace("failed to fetch metadata", "error", err, "hash", hash.HexString())
+					continue
+				}
+
+				select {
+				case results <- *t:
+				case <-ctx.Done():
+				}
+			}
+		})
+	}
+
+	go func() {
+		defer func() {
+			close(hashes)
+			fetchWg.Wait()
+			cancel()
+			close(results)
+		}()
+
+		if _, err := c.server.BootstrapContext(ctx); err != nil {
+			log.Warn("failed to bootstrap", "error", err)
+		}
+
+		c.crawl(ctx, hashes)
+	}()
+
+	return results
+}
+
+func (c *Crawler) crawl(ctx context.Context, hashes chan<- metainfo.Hash) {
+	log := c.log
+
+	queue := make(chan krpc.NodeAddr, maxQueuedNodes)
+	seenNode := map[string]struct{}{}
+	seenHash := map[string]struct{}{}
+	var seenMu sync.Mutex
+
+	enqueueNode := func(addr krpc.NodeAddr) {
+		key := addr.String()
+		seenMu.Lock()
+		if _, seen := seenNode[key]; seen {
+			seenMu.Unlock()
+			return
+		}
+		if len(seenNode) >= maxSeen {
+			clear(seenNode)
+		}
+		seenNode[key] = struct{}{}
+		seenMu.Unlock()
+
+		select {
+		case queue <- addr:
+		default:
+		}
+	}
+
+	for _, ni := range c.server.Nodes() {
+		enqueueNode(ni.Addr)
+	}
+
+	var sampleWg sync.WaitGroup
+	for range samplerConcurrency {
+		sampleWg.Go(func() {
+			for {
+				var addr krpc.NodeAddr
+				select {
+				case <-ctx.Done():
+					return
+				case addr = <-queue:
+				case <-time.After(sampleQueryTimeout):
+					for _, ni := range c.server.Nodes() {
+						enqueueNode(ni.Addr)
+					}
+					continue
+				}
+
+				res, err := c.sample(ctx, addr)
+				if err != nil {
+					continue
+				}
+
+				for _, ni := range res.nodes {
+					enqueueNode(ni.Addr)
+				}
+
+				newHashes := make([]string, 0, len(res.hashes))
+				seenMu.Lock()
+				if len(seenHash) >= maxSeen {
+					clear(seenHash)
+				}
+				for _, h := range res.hashes {
+					hash := h.HexString()
+					if _, seen := seenHash[hash]; !seen {
+						seenHash[hash] = struct{}{}
+						newHashes = append(newHashes, hash)
+					}
+				}
+				seenMu.Unlock()
+				if len(newHashes) == 0 {
+					continue
+				}
+
+				newHashes, err = c.conf.Filter(newHashes)
+				if err != nil {
+					log.Warn("failed to filter hashes", "error", err)
+					continue
+				}
+
+				for _, hash := range newHashes {
+					var h metainfo.Hash
+					if err := h.FromHexString(hash); err != nil {
+						continue
+					}
+					select {
+					case hashes <- h:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		})
+	}
+	sampleWg.Wait()
+}
+
+func GetDataDir(baseDir string) string {
+	return filepath.Join(baseDir, "dht_crawler")
+}
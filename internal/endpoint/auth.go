@@ -73,6 +73,37 @@ func handleTraktAuthCallback(w http.ResponseWriter, r *http.Request) {
 	SendHTML(w, 200, buf)
 }
 
+func handleSimklAuthCallback(w http.ResponseWriter, r *http.Request) {
+	if !shared.IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	state := r.URL.Query().Get("state")
+
+	td := &AuthCallbackTemplateData{
+		Title:    "StremThru",
+		Version:  config.Version,
+		Provider: "Simkl",
+		State:    state,
+	}
+
+	tok, err := oauth.SimklOAuthConfig.Exchange(code, state)
+	if err != nil {
+		td.Error = err.Error()
+	} else {
+		td.Code = tok.Extra("id").(string)
+	}
+
+	buf, err := ExecuteAuthCallbackTemplate(td)
+	if err != nil {
+		SendError(w, r, err)
+		return
+	}
+	SendHTML(w, 200, buf)
+}
+
 func handleTMDBAuthInit(w http.ResponseWriter, r *http.Request) {
 	if !shared.IsMethod(r, http.MethodGet) {
 		shared.ErrorMethodNotAllowed(r).Send(w, r)
@@ -189,6 +220,9 @@ func AddAuthEndpoints(mux *http.ServeMux) {
 	if config.Integration.Trakt.IsEnabled() {
 		mux.HandleFunc("/auth/trakt.tv/callback", handleTraktAuthCallback)
 	}
+	if config.Integration.Simkl.IsEnabled() {
+		mux.HandleFunc("/auth/simkl.com/callback", handleSimklAuthCallback)
+	}
 	if config.Integration.TMDB.IsEnabled() {
 		mux.HandleFunc("/auth/themoviedb.org/init", handleTMDBAuthInit)
 		mux.HandleFunc("/auth/themoviedb.org/callback", handleTMDBAuthCallback)
@@ -0,0 +1,111 @@
+package endpoint
+
+import (
+	"net/http"
+
+	"github.com/MunifTanjim/stremthru/internal/magnet_cache"
+	"github.com/MunifTanjim/stremthru/internal/metrics"
+	"github.com/MunifTanjim/stremthru/internal/nntp"
+	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/torrent_info"
+	"github.com/MunifTanjim/stremthru/internal/torrent_stream"
+	usenetmanager "github.com/MunifTanjim/stremthru/internal/usenet/manager"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	usenetProviderConnectionsDesc = prometheus.NewDesc(
+		"stremthru_usenet_provider_connections",
+		"NNTP connections per usenet provider, by state.",
+		[]string{"provider", "state"}, nil,
+	)
+	usenetProviderMaxConnectionsDesc = prometheus.NewDesc(
+		"stremthru_usenet_provider_max_connections",
+		"Maximum NNTP connections allowed per usenet provider.",
+		[]string{"provider"}, nil,
+	)
+	usenetProviderUpDesc = prometheus.NewDesc(
+		"stremthru_usenet_provider_up",
+		"Whether the usenet provider is online.",
+		[]string{"provider", "backup"}, nil,
+	)
+)
+
+type usenetPoolCollector struct{}
+
+func (usenetPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- usenetProviderConnectionsDesc
+	ch <- usenetProviderMaxConnectionsDesc
+	ch <- usenetProviderUpDesc
+}
+
+func (usenetPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	info, ok := usenetmanager.GetPoolInfo()
+	if !ok {
+		return
+	}
+
+	for _, provider := range info.Providers {
+		up := 0.0
+		if provider.State == nntp.PoolStateOnline {
+			up = 1
+		}
+		backup := "false"
+		if provider.IsBackup {
+			backup = "true"
+		}
+		ch <- prometheus.MustNewConstMetric(usenetProviderUpDesc, prometheus.GaugeValue, up, provider.ID, backup)
+		ch <- prometheus.MustNewConstMetric(usenetProviderMaxConnectionsDesc, prometheus.GaugeValue, float64(provider.MaxConnections), provider.ID)
+		ch <- prometheus.MustNewConstMetric(usenetProviderConnectionsDesc, prometheus.GaugeValue, float64(provider.ActiveConnections), provider.ID, "active")
+		ch <- prometheus.MustNewConstMetric(usenetProviderConnectionsDesc, prometheus.GaugeValue, float64(provider.IdleConnections), provider.ID, "idle")
+	}
+}
+
+func newWriteCacheCounters(name string, getStats func() (skipped int64, allowed int64)) []prometheus.Collector {
+	newCounter := func(result string, get func() int64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "stremthru_db_write_cache_total",
+			Help:        "Database writes skipped or allowed by the write cache.",
+			ConstLabels: prometheus.Labels{"cache": name, "result": result},
+		}, func() float64 {
+			return float64(get())
+		})
+	}
+	return []prometheus.Collector{
+		newCounter("skipped", func() int64 {
+			skipped, _ := getStats()
+			return skipped
+		}),
+		newCounter("allowed", func() int64 {
+			_, allowed := getStats()
+			return allowed
+		}),
+	}
+}
+
+func init() {
+	metrics.MustRegister(usenetPoolCollector{})
+	metrics.MustRegister(newWriteCacheCounters("torrent_info", torrent_info.GetUpsertCacheStats)...)
+	metrics.MustRegister(newWriteCacheCounters("torrent_stream", torrent_stream.GetRecordCacheStats)...)
+	metrics.MustRegister(newWriteCacheCounters("magnet_cache", magnet_cache.GetTouchCacheStats)...)
+}
+
+var metricsHandler = metrics.Handler()
+
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if !shared.IsMethod(r, http.MethodGet) {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	server.GetReqCtx(r).NoRequestLog = true
+
+	metricsHandler.ServeHTTP(w, r)
+}
+
+func AddMetricsEndpoints(mux *http.ServeMux) {
+	withAdminAuth := server.Middleware(server.AdminAuthed)
+
+	mux.HandleFunc("/v0/metrics", withAdminAuth(handleMetrics))
+}
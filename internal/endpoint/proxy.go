@@ -8,9 +8,12 @@ import (
 
 	"github.com/MunifTanjim/stremthru/core"
 	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/content_proxy"
+	"github.com/MunifTanjim/stremthru/internal/metrics"
 	"github.com/MunifTanjim/stremthru/internal/server"
 	"github.com/MunifTanjim/stremthru/internal/shared"
 	store_video "github.com/MunifTanjim/stremthru/internal/store/video"
+	trakt_scrobbler "github.com/MunifTanjim/stremthru/internal/trakt/scrobbler"
 	"github.com/MunifTanjim/stremthru/internal/util"
 )
 
@@ -30,14 +33,23 @@ func handleProxyLinkAccess(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, link, headers, tunnelType, err := shared.UnwrapProxyLinkToken(encodedToken)
+	proxyLink, err := shared.UnwrapProxyLinkToken(encodedToken)
 	if err != nil {
 		SendError(w, r, err)
 		return
 	}
 
-	if headers != nil {
-		for k, v := range headers {
+	user, link := proxyLink.User, proxyLink.Link
+	if proxyLink.IsBase {
+		link, err = proxyLink.ResolveBaseLink(r, encodedToken)
+		if err != nil {
+			shared.ErrorBadRequest(r, err.Error()).Send(w, r)
+			return
+		}
+	}
+
+	if proxyLink.Headers != nil {
+		for k, v := range proxyLink.Headers {
 			r.Header.Set(k, v)
 		}
 	}
@@ -55,13 +67,65 @@ func handleProxyLinkAccess(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		if exceeded, err := content_proxy.IsQuotaExceeded(user); err != nil {
+			ctx.Log.Error("[proxy] failed to check quota", "error", err)
+		} else if exceeded {
+			store_video.Redirect(store_video.StoreVideoNameContentProxyQuotaExceeded, w, r)
+			return
+		}
+
 		if err := cpStore.Set(ctx.RequestId, contentProxyConnection{IP: core.GetRequestIP(r), Link: link}); err != nil {
 			ctx.Log.Error("[proxy] failed to record connection", "error", err)
 		} else {
 			defer cpStore.Del(ctx.RequestId)
 		}
+
+		if proxyLink.StremId != "" {
+			if scrobbleConn := trakt_scrobbler.Connect(user, proxyLink.StremId); scrobbleConn != nil {
+				w = scrobbleConn.WrapResponseWriter(w)
+				defer scrobbleConn.Close()
+			}
+		}
+
+		meteredWriter := content_proxy.NewMeteredResponseWriter(w, r, user)
+		w = meteredWriter
+		defer meteredWriter.Close()
+	}
+
+	var refreshLink func() (string, error)
+	if proxyLink.StoreLink != "" {
+		refreshLink = func() (string, error) {
+			ctx.Log.Info("[proxy] refreshing link", "user", user, "store", proxyLink.Store)
+			link, err := shared.RefreshProxyLink(encodedToken, proxyLink)
+			if err != nil {
+				ctx.Log.Error("[proxy] failed to refresh link", "error", err, "store", proxyLink.Store)
+				return "", err
+			}
+			return link, nil
+		}
+	}
+
+	rewriteManifestLink := func(link string, isBase bool) (string, error) {
+		return shared.CreateDerivedProxyLink(r, proxyLink, link, isBase)
+	}
+
+	trackDone := metrics.TrackContentProxyConnection(user)
+	if isGetReq && proxyLink.ContentId != "" && content_proxy.IsEnabled() {
+		served, bytesWritten := content_proxy.Serve(w, r, &content_proxy.File{
+			Id:          proxyLink.ContentId,
+			URL:         link,
+			Headers:     proxyLink.Headers,
+			TunnelType:  proxyLink.TunnelType,
+			RefreshLink: refreshLink,
+		})
+		if served {
+			trackDone(bytesWritten)
+			ctx.Log.Info("[proxy] connection closed", "user", user, "size", util.ToSize(bytesWritten), "cached", true)
+			return
+		}
 	}
-	bytesWritten, err := shared.ProxyResponse(w, r, link, tunnelType)
+	bytesWritten, err := shared.ProxyResponseWithRefresh(w, r, link, proxyLink.TunnelType, refreshLink, rewriteManifestLink)
+	trackDone(bytesWritten)
 	ctx.Log.Info("[proxy] connection closed", "user", user, "size", util.ToSize(bytesWritten), "error", err)
 }
 
@@ -178,5 +242,5 @@ func AddProxyEndpoints(mux *http.ServeMux) {
 
 	mux.HandleFunc("/v0/proxy", withCors(handleProxifyLinks))
 	mux.HandleFunc("/v0/proxy/{token}", withCors(handleProxyLinkAccess))
-	mux.HandleFunc("/v0/proxy/{token}/{filename}", withCors(handleProxyLinkAccess))
+	mux.HandleFunc("/v0/proxy/{token}/{filename...}", withCors(handleProxyLinkAccess))
 }
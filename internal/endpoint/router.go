@@ -5,9 +5,11 @@ import (
 
 	"github.com/MunifTanjim/stremthru/internal/newz"
 	"github.com/MunifTanjim/stremthru/internal/torz"
+	"github.com/MunifTanjim/stremthru/internal/webdl"
 )
 
 func AddEndpoints(mux *http.ServeMux) {
 	newz.AddEndpoints(mux)
 	torz.AddEndpoints(mux)
+	webdl.AddEndpoints(mux)
 }
@@ -0,0 +1,1035 @@
+package endpoint
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/job/job_queue"
+	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb_info"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	"github.com/MunifTanjim/stremthru/store"
+)
+
+// version reported to the *arr apps, they refuse anything older than 0.7.0
+const sabnzbdVersion = "4.5.5"
+
+const sabnzbdNzoIdPrefix = "SABnzbd_nzo_"
+
+const sabnzbdCompleteDir = "/complete"
+
+var sabnzbdDefaultCategories = []string{"*", "movies", "tv"}
+
+type SabnzbdErrorResponse struct {
+	Status bool   `json:"status"`
+	Error  string `json:"error"`
+}
+
+type SabnzbdStatusResponse struct {
+	Status bool     `json:"status"`
+	NzoIds []string `json:"nzo_ids,omitempty"`
+}
+
+func toSabnzbdNzoId(id string) string {
+	return sabnzbdNzoIdPrefix + id
+}
+
+func fromSabnzbdNzoIds(value string) []string {
+	ids := []string{}
+	for id := range strings.SplitSeq(value, ",") {
+		id = strings.TrimPrefix(strings.TrimSpace(id), sabnzbdNzoIdPrefix)
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func sendSabnzbdError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	shared.SendJSON(w, r, statusCode, SabnzbdErrorResponse{
+		Status: false,
+		Error:  message,
+	})
+}
+
+func sendSabnzbdInternalServerError(w http.ResponseWriter) {
+	shared.SendHTML(w, http.StatusInternalServerError, *bytes.NewBuffer([]byte("Internal Server Error")))
+}
+
+func handleSabnzbdAPI(w http.ResponseWriter, r *http.Request) {
+	rCtx := server.GetReqCtx(r)
+	rCtx.RedactURLQueryParams(r, "apikey")
+
+	q := r.URL.Query()
+
+	apikey := q.Get("apikey")
+	if apikey == "" {
+		shared.SendHTML(w, http.StatusForbidden, *bytes.NewBuffer([]byte("API Key Required")))
+		return
+	}
+
+	user := config.SabnzbdAuth.GetUser(apikey)
+	if user == "" {
+		shared.SendHTML(w, http.StatusForbidden, *bytes.NewBuffer([]byte("API Key Incorrect")))
+		return
+	}
+
+	mode := q.Get("mode")
+
+	switch mode {
+	case "addurl":
+		handleSabnzbdAddUrl(w, r, user)
+	case "addfile":
+		handleSabnzbdAddFile(w, r, user)
+	case "queue":
+		handleSabnzbdQueue(w, r, user)
+	case "history":
+		handleSabnzbdHistory(w, r, user)
+	case "change_cat":
+		handleSabnzbdChangeCat(w, r, user)
+	case "get_cats":
+		handleSabnzbdGetCats(w, r, user)
+	case "get_config":
+		handleSabnzbdGetConfig(w, r, user)
+	case "version":
+		handleSabnzbdVersion(w, r)
+	case "fullstatus":
+		handleSabnzbdFullStatus(w, r, user)
+	default:
+		sendSabnzbdError(w, r, http.StatusOK, "not implemented")
+	}
+}
+
+func getSabnzbdCategory(r *http.Request) string {
+	category := r.URL.Query().Get("cat")
+	if category == "*" {
+		category = ""
+	}
+	return category
+}
+
+func getSabnzbdPriority(r *http.Request) int {
+	priority := util.SafeParseInt(r.URL.Query().Get("priority"), 0)
+	if priority == -100 {
+		priority = 0
+	}
+	return priority
+}
+
+type SabnzbdAddUrlResponse struct {
+	Status bool     `json:"status"`
+	NzoIds []string `json:"nzo_ids"`
+}
+
+func handleSabnzbdAddUrl(w http.ResponseWriter, r *http.Request, user string) {
+	log := server.GetReqCtx(r).Log
+
+	q := r.URL.Query()
+
+	nzbURL := q.Get("name")
+	if nzbURL == "" {
+		sendSabnzbdError(w, r, http.StatusBadRequest, "expects one parameter")
+		return
+	}
+
+	nzbName := q.Get("nzbname")
+	password := q.Get("password")
+
+	id, err := nzb_info.QueueJob(user, nzbName, nzbURL, getSabnzbdCategory(r), getSabnzbdPriority(r), password)
+	if err != nil {
+		log.Error("failed to insert sabnzbd nzb queue item", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+
+	shared.SendJSON(w, r, http.StatusOK, SabnzbdAddUrlResponse{
+		Status: true,
+		NzoIds: []string{toSabnzbdNzoId(id)},
+	})
+}
+
+func handleSabnzbdAddFile(w http.ResponseWriter, r *http.Request, user string) {
+	log := server.GetReqCtx(r).Log
+
+	if r.Method != http.MethodPost {
+		shared.ErrorMethodNotAllowed(r).Send(w, r)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, config.Newz.NZBFileMaxSize)
+	if err := r.ParseMultipartForm(util.ToBytes("10MB")); err != nil {
+		sendSabnzbdError(w, r, http.StatusBadRequest, "invalid multipart form")
+		return
+	}
+
+	var fileHeaders []*multipart.FileHeader
+	if r.MultipartForm != nil && r.MultipartForm.File != nil {
+		fileHeaders = r.MultipartForm.File["name"]
+		if len(fileHeaders) == 0 {
+			fileHeaders = r.MultipartForm.File["nzbfile"]
+		}
+	}
+	if len(fileHeaders) != 1 {
+		sendSabnzbdError(w, r, http.StatusBadRequest, "expects one parameter")
+		return
+	}
+	fileHeader := fileHeaders[0]
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error("failed to open sabnzbd nzb file", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+	defer file.Close()
+
+	blob, err := io.ReadAll(file)
+	if err != nil {
+		log.Error("failed to read sabnzbd nzb file", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+
+	nzbDoc, err := nzb.ParseBytes(blob)
+	if err != nil {
+		if parseErr, ok := err.(*nzb.ParseError); ok {
+			sendSabnzbdError(w, r, http.StatusBadRequest, parseErr.Error())
+			return
+		}
+		log.Error("failed to parse sabnzbd nzb file", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+
+	filename := fileHeader.Filename
+	if !strings.HasSuffix(filename, ".nzb") {
+		filename += ".nzb"
+	}
+
+	nzbId := nzbDoc.HashByFileBoundarySegmentIds()
+	link := config.BaseURL.JoinPath("/v0/newznab/getnzb/", nzbId)
+	linkQuery := link.Query()
+	linkQuery.Set("apikey", util.Base64Encode(user+":"+config.Auth.GetPassword(user)))
+	link.RawQuery = linkQuery.Encode()
+
+	nzbFile := nzb_info.NZBFile{
+		Blob: blob,
+		Name: filename,
+		Link: link.String(),
+		Mod:  time.Now(),
+	}
+
+	hash := nzb_info.HashNZBFileLink(nzbFile.Link)
+	if err := nzb_info.CacheNZBFile(hash, nzbFile); err != nil {
+		log.Error("failed to cache sabnzbd nzb file", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+
+	q := r.URL.Query()
+
+	name := q.Get("nzbname")
+	if name == "" {
+		name = strings.TrimSuffix(filename, ".nzb")
+	}
+	category := getSabnzbdCategory(r)
+	password := q.Get("password")
+
+	if err := nzb_info.Upsert(&nzb_info.NZBInfo{
+		Hash:      hash,
+		Name:      name,
+		Size:      nzbDoc.TotalSize(),
+		FileCount: nzbDoc.FileCount(),
+		Password:  password,
+		URL:       nzbFile.Link,
+		User:      user,
+		Status:    string(store.NewzStatusQueued),
+		Category:  category,
+	}); err != nil {
+		log.Error("failed to upsert sabnzbd nzb info", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+
+	id, err := nzb_info.QueueJob(user, name, nzbFile.Link, category, getSabnzbdPriority(r), password)
+	if err != nil {
+		log.Error("failed to insert sabnzbd nzb queue item", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+
+	shared.SendJSON(w, r, http.StatusOK, SabnzbdAddUrlResponse{
+		Status: true,
+		NzoIds: []string{toSabnzbdNzoId(id)},
+	})
+}
+
+func isSabnzbdJobPending(entry *nzb_info.JobEntry) bool {
+	switch job_queue.EntryStatus(entry.Status) {
+	case job_queue.EntryStatusQueued, job_queue.EntryStatusProcessing, job_queue.EntryStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+type sabnzbdItems struct {
+	jobs     []nzb_info.JobEntry
+	infos    []nzb_info.NZBInfo
+	jobById  map[string]*nzb_info.JobEntry
+	infoById map[string]*nzb_info.NZBInfo
+}
+
+// pending jobs are the queue, finished nzb infos and dead jobs are the history
+func getSabnzbdItems(user string) (*sabnzbdItems, error) {
+	jobs, err := nzb_info.GetAllJobByUser(user)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := nzb_info.GetAllByUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	items := &sabnzbdItems{
+		jobs:     jobs,
+		infos:    infos,
+		jobById:  make(map[string]*nzb_info.JobEntry, len(jobs)),
+		infoById: make(map[string]*nzb_info.NZBInfo, len(infos)),
+	}
+	for i := range items.jobs {
+		items.jobById[items.jobs[i].Key] = &items.jobs[i]
+	}
+	for i := range items.infos {
+		items.infoById[items.infos[i].Hash] = &items.infos[i]
+	}
+	return items, nil
+}
+
+func (items *sabnzbdItems) isQueued(id string) bool {
+	job, ok := items.jobById[id]
+	return ok && isSabnzbdJobPending(job)
+}
+
+var sabnzbdPriorityText = map[int]string{
+	-2: "Paused",
+	-1: "Low",
+	0:  "Normal",
+	1:  "High",
+	2:  "Force",
+}
+
+type SabnzbdQueueSlot struct {
+	Status     string `json:"status"`
+	Index      int    `json:"index"`
+	Password   string `json:"password"`
+	AvgAge     string `json:"avg_age"`
+	Script     string `json:"script"`
+	MB         string `json:"mb"`
+	MBLeft     string `json:"mbleft"`
+	MBMissing  string `json:"mbmissing"`
+	Size       string `json:"size"`
+	SizeLeft   string `json:"sizeleft"`
+	Filename   string `json:"filename"`
+	Labels     []any  `json:"labels"`
+	Priority   string `json:"priority"`
+	Cat        string `json:"cat"`
+	TimeLeft   string `json:"timeleft"`
+	Percentage string `json:"percentage"`
+	NzoId      string `json:"nzo_id"`
+	UnpackOpts string `json:"unpackopts"`
+}
+
+type SabnzbdQueue struct {
+	Status      string             `json:"status"`
+	Version     string             `json:"version"`
+	Paused      bool               `json:"paused"`
+	PausedAll   bool               `json:"paused_all"`
+	NoOfSlots   int                `json:"noofslots"`
+	NoOfSlotsT  int                `json:"noofslots_total"`
+	Limit       int                `json:"limit"`
+	Start       int                `json:"start"`
+	Speed       string             `json:"speed"`
+	KBPerSec    string             `json:"kbpersec"`
+	SpeedLimit  string             `json:"speedlimit"`
+	TimeLeft    string             `json:"timeleft"`
+	MB          string             `json:"mb"`
+	MBLeft      string             `json:"mbleft"`
+	Size        string             `json:"size"`
+	SizeLeft    string             `json:"sizeleft"`
+	DiskSpace1  string             `json:"diskspace1"`
+	DiskSpace2  string             `json:"diskspace2"`
+	HaveWarning string             `json:"have_warnings"`
+	Slots       []SabnzbdQueueSlot `json:"slots"`
+}
+
+type SabnzbdQueueResponse struct {
+	Queue SabnzbdQueue `json:"queue"`
+}
+
+func toSabnzbdMB(size int64) string {
+	return fmt.Sprintf("%.2f", float64(size)/1024/1024)
+}
+
+func handleSabnzbdQueue(w http.ResponseWriter, r *http.Request, user string) {
+	log := server.GetReqCtx(r).Log
+
+	q := r.URL.Query()
+
+	switch name := q.Get("name"); name {
+	case "":
+	case "delete", "pause", "resume":
+		ids := fromSabnzbdNzoIds(q.Get("value"))
+
+		items, err := getSabnzbdItems(user)
+		if err != nil {
+			log.Error("failed to get sabnzbd items", "error", err)
+			sendSabnzbdInternalServerError(w)
+			return
+		}
+		if q.Get("value") == "all" {
+			ids = ids[:0]
+			for i := range items.jobs {
+				ids = append(ids, items.jobs[i].Key)
+			}
+		}
+		ids = slices.DeleteFunc(ids, func(id string) bool {
+			return !items.isQueued(id)
+		})
+
+		switch name {
+		case "delete":
+			err = job_queue.DeleteEntries(nzb_info.JobQueueName, ids)
+			if err == nil {
+				for _, id := range ids {
+					if info, ok := items.infoById[id]; ok {
+						if err = nzb_info.DeleteById(info.Id); err != nil {
+							break
+						}
+						nzb_info.DeleteNZBFile(info.URL)
+					}
+				}
+			}
+		case "pause":
+			err = nzb_info.PauseJobs(ids)
+		case "resume":
+			err = nzb_info.ResumeJobs(ids)
+		}
+		if err != nil {
+			log.Error("failed to update sabnzbd queue", "error", err, "action", name)
+			sendSabnzbdInternalServerError(w)
+			return
+		}
+
+		nzoIds := make([]string, len(ids))
+		for i, id := range ids {
+			nzoIds[i] = toSabnzbdNzoId(id)
+		}
+		shared.SendJSON(w, r, http.StatusOK, SabnzbdStatusResponse{
+			Status: true,
+			NzoIds: nzoIds,
+		})
+		return
+	default:
+		sendSabnzbdError(w, r, http.StatusOK, "not implemented")
+		return
+	}
+
+	items, err := getSabnzbdItems(user)
+	if err != nil {
+		log.Error("failed to get sabnzbd items", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+
+	queue := SabnzbdQueue{
+		Status:     "Idle",
+		Version:    sabnzbdVersion,
+		Speed:      "0",
+		KBPerSec:   "0.00",
+		SpeedLimit: "100",
+		TimeLeft:   "0:00:00",
+		Slots:      []SabnzbdQueueSlot{},
+	}
+
+	// oldest first, that's the processing order within the same priority
+	slices.Reverse(items.jobs)
+	slices.SortStableFunc(items.jobs, func(a, b nzb_info.JobEntry) int {
+		return b.Priority - a.Priority
+	})
+
+	totalSize := int64(0)
+	for i := range items.jobs {
+		job := &items.jobs[i]
+		if !isSabnzbdJobPending(job) {
+			continue
+		}
+
+		slot := SabnzbdQueueSlot{
+			Status:     "Queued",
+			Index:      len(queue.Slots),
+			Password:   job.Payload.Data.Password,
+			AvgAge:     "0d",
+			Script:     "None",
+			Filename:   job.Payload.Data.Name,
+			Labels:     []any{},
+			Priority:   sabnzbdPriorityText[max(-1, min(2, job.Priority))],
+			Cat:        job.Payload.Data.Category,
+			TimeLeft:   "0:00:00",
+			Percentage: "0",
+			NzoId:      toSabnzbdNzoId(job.Key),
+			UnpackOpts: "3",
+		}
+		if slot.Cat == "" {
+			slot.Cat = "*"
+		}
+		if job.Status == string(job_queue.EntryStatusProcessing) {
+			slot.Status = "Downloading"
+			queue.Status = "Downloading"
+		} else if nzb_info.IsJobPaused(job) {
+			slot.Status = "Paused"
+			slot.Priority = sabnzbdPriorityText[-2]
+		}
+
+		size := int64(0)
+		if info, ok := items.infoById[job.Key]; ok {
+			size = info.Size
+			if slot.Filename == "" {
+				slot.Filename = info.Name
+			}
+		}
+		if slot.Filename == "" {
+			slot.Filename = path.Base(nzb_info.CleanNZBFileLink(job.Payload.Data.URL))
+		}
+		slot.MB = toSabnzbdMB(size)
+		slot.MBLeft = slot.MB
+		slot.MBMissing = "0.0"
+		slot.Size = util.ToSize(size)
+		slot.SizeLeft = slot.Size
+		totalSize += size
+
+		queue.Slots = append(queue.Slots, slot)
+	}
+
+	queue.NoOfSlots = len(queue.Slots)
+	queue.NoOfSlotsT = len(queue.Slots)
+	queue.MB = toSabnzbdMB(totalSize)
+	queue.MBLeft = queue.MB
+	queue.Size = util.ToSize(totalSize)
+	queue.SizeLeft = queue.Size
+
+	start := util.SafeParseInt(q.Get("start"), 0)
+	limit := util.SafeParseInt(q.Get("limit"), 0)
+	queue.Slots = paginateSabnzbdSlots(queue.Slots, start, limit)
+	queue.Start = start
+	queue.Limit = limit
+
+	shared.SendJSON(w, r, http.StatusOK, SabnzbdQueueResponse{
+		Queue: queue,
+	})
+}
+
+func paginateSabnzbdSlots[T any](slots []T, start, limit int) []T {
+	if start > 0 {
+		slots = slots[min(start, len(slots)):]
+	}
+	if limit > 0 {
+		slots = slots[:min(limit, len(slots))]
+	}
+	return slots
+}
+
+type SabnzbdHistorySlot struct {
+	Id           int    `json:"id"`
+	Completed    int64  `json:"completed"`
+	Name         string `json:"name"`
+	NzbName      string `json:"nzb_name"`
+	Category     string `json:"category"`
+	PP           string `json:"pp"`
+	Script       string `json:"script"`
+	Report       string `json:"report"`
+	URL          string `json:"url"`
+	Status       string `json:"status"`
+	NzoId        string `json:"nzo_id"`
+	Storage      string `json:"storage"`
+	Path         string `json:"path"`
+	ScriptLine   string `json:"script_line"`
+	DownloadTime int64  `json:"download_time"`
+	PostprocTime int64  `json:"postproc_time"`
+	StageLog     []any  `json:"stage_log"`
+	Downloaded   int64  `json:"downloaded"`
+	FailMessage  string `json:"fail_message"`
+	URLInfo      string `json:"url_info"`
+	Bytes        int64  `json:"bytes"`
+	Meta         any    `json:"meta"`
+	Series       string `json:"series"`
+	MD5Sum       string `json:"md5sum"`
+	Password     string `json:"password"`
+	Size         string `json:"size"`
+	Loaded       bool   `json:"loaded"`
+	Retry        int    `json:"retry"`
+}
+
+type SabnzbdHistory struct {
+	NoOfSlots  int                  `json:"noofslots"`
+	TotalSize  string               `json:"total_size"`
+	MonthSize  string               `json:"month_size"`
+	WeekSize   string               `json:"week_size"`
+	DaySize    string               `json:"day_size"`
+	Version    string               `json:"version"`
+	LastUpdate int64                `json:"last_history_update"`
+	Slots      []SabnzbdHistorySlot `json:"slots"`
+}
+
+type SabnzbdHistoryResponse struct {
+	History SabnzbdHistory `json:"history"`
+}
+
+func toSabnzbdHistoryStatus(status string) string {
+	switch store.NewzStatus(status) {
+	case store.NewzStatusDownloaded, store.NewzStatusCached:
+		return "Completed"
+	default:
+		return "Failed"
+	}
+}
+
+func toSabnzbdHistorySlot(info *nzb_info.NZBInfo) SabnzbdHistorySlot {
+	category := info.Category
+	if category == "" {
+		category = "*"
+	}
+	storage := path.Join(sabnzbdCompleteDir, info.Category, info.Name)
+	slot := SabnzbdHistorySlot{
+		Completed:    info.UAt.Unix(),
+		Name:         info.Name,
+		NzbName:      info.Name + ".nzb",
+		Category:     category,
+		PP:           "D",
+		Script:       "None",
+		URL:          nzb_info.CleanNZBFileLink(info.URL),
+		Status:       toSabnzbdHistoryStatus(info.Status),
+		NzoId:        toSabnzbdNzoId(info.Hash),
+		Storage:      storage,
+		Path:         storage,
+		DownloadTime: int64(info.UAt.Sub(info.CAt.Time).Seconds()),
+		StageLog:     []any{},
+		Downloaded:   info.Size,
+		Bytes:        info.Size,
+		Password:     info.Password,
+		Size:         util.ToSize(info.Size),
+		Retry:        0,
+	}
+	if slot.Status == "Failed" {
+		slot.FailMessage = "Download failed"
+		if !info.Streamable && info.Status == string(store.NewzStatusFailed) {
+			slot.FailMessage = "Not streamable"
+		}
+		slot.Retry = 1
+	}
+	return slot
+}
+
+func toSabnzbdFailedJobHistorySlot(job *nzb_info.JobEntry) SabnzbdHistorySlot {
+	category := job.Payload.Data.Category
+	if category == "" {
+		category = "*"
+	}
+	// the link can carry credentials in the query, those are not echoed back
+	link := nzb_info.CleanNZBFileLink(job.Payload.Data.URL)
+	name := job.Payload.Data.Name
+	if name == "" {
+		name = path.Base(link)
+	}
+	slot := SabnzbdHistorySlot{
+		Completed:   job.UpdatedAt.Unix(),
+		Name:        name,
+		NzbName:     name + ".nzb",
+		Category:    category,
+		PP:          "D",
+		Script:      "None",
+		URL:         link,
+		Status:      "Failed",
+		NzoId:       toSabnzbdNzoId(job.Key),
+		StageLog:    []any{},
+		FailMessage: "Failed to fetch NZB",
+		Size:        util.ToSize(0),
+		Retry:       1,
+	}
+	if len(job.Error) > 0 {
+		slot.FailMessage = job.Error[len(job.Error)-1]
+	}
+	return slot
+}
+
+func handleSabnzbdHistory(w http.ResponseWriter, r *http.Request, user string) {
+	log := server.GetReqCtx(r).Log
+
+	q := r.URL.Query()
+
+	items, err := getSabnzbdItems(user)
+	if err != nil {
+		log.Error("failed to get sabnzbd items", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+
+	slots := []SabnzbdHistorySlot{}
+	for i := range items.infos {
+		info := &items.infos[i]
+		if items.isQueued(info.Hash) {
+			continue
+		}
+		slots = append(slots, toSabnzbdHistorySlot(info))
+	}
+	for i := range items.jobs {
+		job := &items.jobs[i]
+		if _, ok := items.infoById[job.Key]; ok || job.Status != string(job_queue.EntryStatusDead) {
+			continue
+		}
+		slots = append(slots, toSabnzbdFailedJobHistorySlot(job))
+	}
+	slices.SortStableFunc(slots, func(a, b SabnzbdHistorySlot) int {
+		return int(b.Completed - a.Completed)
+	})
+
+	switch name := q.Get("name"); name {
+	case "":
+	case "delete":
+		value := q.Get("value")
+		ids := []string{}
+		for i := range slots {
+			id := strings.TrimPrefix(slots[i].NzoId, sabnzbdNzoIdPrefix)
+			switch value {
+			case "all":
+				ids = append(ids, id)
+			case "failed":
+				if slots[i].Status == "Failed" {
+					ids = append(ids, id)
+				}
+			case "completed":
+				if slots[i].Status == "Completed" {
+					ids = append(ids, id)
+				}
+			}
+		}
+		if len(ids) == 0 {
+			ids = fromSabnzbdNzoIds(value)
+			ids = slices.DeleteFunc(ids, func(id string) bool {
+				return items.isQueued(id) || (items.infoById[id] == nil && items.jobById[id] == nil)
+			})
+		}
+
+		err := job_queue.DeleteEntries(nzb_info.JobQueueName, ids)
+		if err == nil {
+			for _, id := range ids {
+				if info, ok := items.infoById[id]; ok {
+					if err = nzb_info.DeleteById(info.Id); err != nil {
+						break
+					}
+					if q.Get("del_files") == "1" {
+						nzb_info.DeleteNZBFile(info.URL)
+					}
+				}
+			}
+		}
+		if err != nil {
+			log.Error("failed to delete sabnzbd history", "error", err)
+			sendSabnzbdInternalServerError(w)
+			return
+		}
+
+		shared.SendJSON(w, r, http.StatusOK, SabnzbdStatusResponse{
+			Status: true,
+		})
+		return
+	default:
+		sendSabnzbdError(w, r, http.StatusOK, "not implemented")
+		return
+	}
+
+	if category := q.Get("category"); category != "" && category != "*" {
+		slots = slices.DeleteFunc(slots, func(slot SabnzbdHistorySlot) bool {
+			return slot.Category != category
+		})
+	}
+	if failedOnly := q.Get("failed_only"); failedOnly == "1" {
+		slots = slices.DeleteFunc(slots, func(slot SabnzbdHistorySlot) bool {
+			return slot.Status != "Failed"
+		})
+	}
+
+	history := SabnzbdHistory{
+		NoOfSlots: len(slots),
+		Version:   sabnzbdVersion,
+	}
+	totalSize := int64(0)
+	for i := range slots {
+		slots[i].Id = i + 1
+		totalSize += slots[i].Bytes
+		if slots[i].Completed > history.LastUpdate {
+			history.LastUpdate = slots[i].Completed
+		}
+	}
+	history.TotalSize = util.ToSize(totalSize)
+	history.MonthSize = history.TotalSize
+	history.WeekSize = history.TotalSize
+	history.DaySize = history.TotalSize
+	history.Slots = paginateSabnzbdSlots(slots, util.SafeParseInt(q.Get("start"), 0), util.SafeParseInt(q.Get("limit"), 0))
+
+	shared.SendJSON(w, r, http.StatusOK, SabnzbdHistoryResponse{
+		History: history,
+	})
+}
+
+type SabnzbdChangeCatResponse struct {
+	Status bool `json:"status"`
+}
+
+func handleSabnzbdChangeCat(w http.ResponseWriter, r *http.Request, user string) {
+	log := server.GetReqCtx(r).Log
+
+	q := r.URL.Query()
+
+	ids := fromSabnzbdNzoIds(q.Get("value"))
+	if len(ids) == 0 {
+		sendSabnzbdError(w, r, http.StatusBadRequest, "expects one parameter")
+		return
+	}
+
+	category := q.Get("value2")
+	if category == "*" {
+		category = ""
+	}
+
+	items, err := getSabnzbdItems(user)
+	if err != nil {
+		log.Error("failed to get sabnzbd items", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+
+	for _, id := range ids {
+		if _, ok := items.jobById[id]; ok {
+			if err := nzb_info.SetJobCategory(id, category); err != nil {
+				log.Error("failed to update sabnzbd job category", "error", err)
+				sendSabnzbdInternalServerError(w)
+				return
+			}
+		}
+		if _, ok := items.infoById[id]; ok {
+			if err := nzb_info.UpdateCategory(id, category); err != nil {
+				log.Error("failed to update sabnzbd nzb category", "error", err)
+				sendSabnzbdInternalServerError(w)
+				return
+			}
+		}
+	}
+
+	shared.SendJSON(w, r, http.StatusOK, SabnzbdChangeCatResponse{
+		Status: true,
+	})
+}
+
+func getSabnzbdCategories(user string) ([]string, error) {
+	items, err := getSabnzbdItems(user)
+	if err != nil {
+		return nil, err
+	}
+	categories := slices.Clone(sabnzbdDefaultCategories)
+	for i := range items.jobs {
+		if category := items.jobs[i].Payload.Data.Category; category != "" && !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	for i := range items.infos {
+		if category := items.infos[i].Category; category != "" && !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+type SabnzbdGetCatsResponse struct {
+	Categories []string `json:"categories"`
+}
+
+func handleSabnzbdGetCats(w http.ResponseWriter, r *http.Request, user string) {
+	log := server.GetReqCtx(r).Log
+
+	categories, err := getSabnzbdCategories(user)
+	if err != nil {
+		log.Error("failed to get sabnzbd categories", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+
+	shared.SendJSON(w, r, http.StatusOK, SabnzbdGetCatsResponse{
+		Categories: categories,
+	})
+}
+
+type SabnzbdConfigMisc struct {
+	CompleteDir        string `json:"complete_dir"`
+	DownloadDir        string `json:"download_dir"`
+	EnableTVSorting    bool   `json:"enable_tv_sorting"`
+	EnableMovieSorting bool   `json:"enable_movie_sorting"`
+	EnableDateSorting  bool   `json:"enable_date_sorting"`
+	PreCheck           bool   `json:"pre_check"`
+	HistoryRetention   string `json:"history_retention"`
+}
+
+type SabnzbdConfigCategory struct {
+	Name     string `json:"name"`
+	Order    int    `json:"order"`
+	PP       string `json:"pp"`
+	Script   string `json:"script"`
+	Dir      string `json:"dir"`
+	Newzbin  string `json:"newzbin"`
+	Priority int    `json:"priority"`
+}
+
+type SabnzbdConfig struct {
+	Misc       SabnzbdConfigMisc       `json:"misc"`
+	Categories []SabnzbdConfigCategory `json:"categories"`
+	Sorters    []any                   `json:"sorters"`
+}
+
+type SabnzbdGetConfigResponse struct {
+	Config SabnzbdConfig `json:"config"`
+}
+
+func handleSabnzbdGetConfig(w http.ResponseWriter, r *http.Request, user string) {
+	log := server.GetReqCtx(r).Log
+
+	categories, err := getSabnzbdCategories(user)
+	if err != nil {
+		log.Error("failed to get sabnzbd categories", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+
+	conf := SabnzbdConfig{
+		Misc: SabnzbdConfigMisc{
+			CompleteDir: sabnzbdCompleteDir,
+			DownloadDir: sabnzbdCompleteDir,
+		},
+		Categories: make([]SabnzbdConfigCategory, len(categories)),
+		Sorters:    []any{},
+	}
+	for i, category := range categories {
+		conf.Categories[i] = SabnzbdConfigCategory{
+			Name:     category,
+			Order:    i,
+			PP:       "",
+			Script:   "Default",
+			Priority: -100,
+		}
+		if category == "*" {
+			conf.Categories[i].PP = "3"
+			conf.Categories[i].Script = "None"
+			conf.Categories[i].Priority = 0
+		} else {
+			conf.Categories[i].Dir = category
+		}
+	}
+
+	shared.SendJSON(w, r, http.StatusOK, SabnzbdGetConfigResponse{
+		Config: conf,
+	})
+}
+
+type SabnzbdVersionResponse struct {
+	Version string `json:"version"`
+}
+
+func handleSabnzbdVersion(w http.ResponseWriter, r *http.Request) {
+	shared.SendJSON(w, r, http.StatusOK, SabnzbdVersionResponse{
+		Version: sabnzbdVersion,
+	})
+}
+
+type SabnzbdFullStatus struct {
+	Version       string `json:"version"`
+	Paused        bool   `json:"paused"`
+	PauseInt      string `json:"pause_int"`
+	DownloadDir   string `json:"downloaddir"`
+	CompleteDir   string `json:"completedir"`
+	LocalIPv4     string `json:"localipv4"`
+	LoadAvg       string `json:"loadavg"`
+	Speed         string `json:"speed"`
+	KBPerSec      string `json:"kbpersec"`
+	SpeedLimit    string `json:"speedlimit"`
+	NoOfSlots     int    `json:"noofslots"`
+	NoOfSlotsT    int    `json:"noofslots_total"`
+	HaveWarnings  string `json:"have_warnings"`
+	QueueStatus   string `json:"status"`
+	QueueTimeLeft string `json:"timeleft"`
+	Servers       []any  `json:"servers"`
+	Warnings      []any  `json:"warnings"`
+}
+
+type SabnzbdFullStatusResponse struct {
+	Status SabnzbdFullStatus `json:"status"`
+}
+
+func handleSabnzbdFullStatus(w http.ResponseWriter, r *http.Request, user string) {
+	log := server.GetReqCtx(r).Log
+
+	items, err := getSabnzbdItems(user)
+	if err != nil {
+		log.Error("failed to get sabnzbd items", "error", err)
+		sendSabnzbdInternalServerError(w)
+		return
+	}
+
+	status := SabnzbdFullStatus{
+		Version:       sabnzbdVersion,
+		PauseInt:      "0",
+		DownloadDir:   sabnzbdCompleteDir,
+		CompleteDir:   sabnzbdCompleteDir,
+		Speed:         "0",
+		KBPerSec:      "0.00",
+		SpeedLimit:    "100",
+		HaveWarnings:  "0",
+		QueueStatus:   "Idle",
+		QueueTimeLeft: "0:00:00",
+		Servers:       []any{},
+		Warnings:      []any{},
+	}
+	for i := range items.jobs {
+		job := &items.jobs[i]
+		if !isSabnzbdJobPending(job) {
+			continue
+		}
+		status.NoOfSlots++
+		if job.Status == string(job_queue.EntryStatusProcessing) {
+			status.QueueStatus = "Downloading"
+		}
+	}
+	status.NoOfSlotsT = status.NoOfSlots
+
+	shared.SendJSON(w, r, http.StatusOK, SabnzbdFullStatusResponse{
+		Status: status,
+	})
+}
+
+func AddSabnzbdEndpoints(mux *http.ServeMux) {
+	if !config.Feature.HasVault() {
+		return
+	}
+
+	mux.HandleFunc("/v0/sabnzbd/api", handleSabnzbdAPI)
+	// Deprecated: kept for existing clients, use `/v0/sabnzbd/api`
+	mux.HandleFunc("/v0/__experiment__/sabnzbd/api", handleSabnzbdAPI)
+}
@@ -0,0 +1,303 @@
+package endpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/db/dbtest"
+	"github.com/MunifTanjim/stremthru/internal/job/job_queue"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb_info"
+	"github.com/MunifTanjim/stremthru/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sabnzbdTestNZB = `<?xml version="1.0" encoding="UTF-8"?>
+<nzb xmlns="http://www.newzbin.com/DTD/2003/nzb">
+  <file poster="user@example.com" date="1234567890" subject="Show.S01E01.mkv [1/1]">
+    <groups>
+      <group>alt.binaries.test</group>
+    </groups>
+    <segments>
+      <segment bytes="500000" number="1">msg-id-1@example.com</segment>
+    </segments>
+  </file>
+</nzb>`
+
+func setupSabnzbdTest(t *testing.T) {
+	t.Helper()
+
+	dbtest.Open(t)
+
+	sabnzbdAuth := config.SabnzbdAuth
+	config.SabnzbdAuth = config.SabnzbdAuthMap{
+		"alice": "alice-key",
+		"bob":   "bob-key",
+	}
+	t.Cleanup(func() {
+		config.SabnzbdAuth = sabnzbdAuth
+	})
+}
+
+func callSabnzbdAPI(t *testing.T, r *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	shared.RootServerContext(http.HandlerFunc(handleSabnzbdAPI)).ServeHTTP(w, r)
+	return w
+}
+
+func getSabnzbdAPI(t *testing.T, apikey string, query url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+
+	if apikey != "" {
+		query.Set("apikey", apikey)
+	}
+	return callSabnzbdAPI(t, httptest.NewRequest(http.MethodGet, "/v0/sabnzbd/api?"+query.Encode(), nil))
+}
+
+func postSabnzbdFile(t *testing.T, apikey string, query url.Values, field, filename, content string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	fw, err := mw.CreateFormFile(field, filename)
+	require.NoError(t, err)
+	_, err = fw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	query.Set("apikey", apikey)
+	query.Set("mode", "addfile")
+	r := httptest.NewRequest(http.MethodPost, "/v0/sabnzbd/api?"+query.Encode(), body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return callSabnzbdAPI(t, r)
+}
+
+func decodeSabnzbdResponse[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var res T
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res), w.Body.String())
+	return res
+}
+
+func getSabnzbdQueue(t *testing.T, apikey string) SabnzbdQueue {
+	t.Helper()
+
+	w := getSabnzbdAPI(t, apikey, url.Values{"mode": {"queue"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	return decodeSabnzbdResponse[SabnzbdQueueResponse](t, w).Queue
+}
+
+func getSabnzbdHistory(t *testing.T, apikey string) (SabnzbdHistory, string) {
+	t.Helper()
+
+	w := getSabnzbdAPI(t, apikey, url.Values{"mode": {"history"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	return decodeSabnzbdResponse[SabnzbdHistoryResponse](t, w).History, w.Body.String()
+}
+
+func TestSabnzbdAPIAuth(t *testing.T) {
+	sabnzbdAuth := config.SabnzbdAuth
+	config.SabnzbdAuth = config.SabnzbdAuthMap{"alice": "alice-key"}
+	defer func() {
+		config.SabnzbdAuth = sabnzbdAuth
+	}()
+
+	for _, tc := range []struct {
+		name       string
+		apikey     string
+		statusCode int
+		body       string
+	}{
+		{"missing apikey", "", http.StatusForbidden, "API Key Required"},
+		{"incorrect apikey", "wrong-key", http.StatusForbidden, "API Key Incorrect"},
+		{"correct apikey", "alice-key", http.StatusOK, `"version":"` + sabnzbdVersion + `"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := getSabnzbdAPI(t, tc.apikey, url.Values{"mode": {"version"}})
+			assert.Equal(t, tc.statusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tc.body)
+		})
+	}
+
+	t.Run("unknown mode", func(t *testing.T) {
+		w := getSabnzbdAPI(t, "alice-key", url.Values{"mode": {"unknown"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		res := decodeSabnzbdResponse[SabnzbdErrorResponse](t, w)
+		assert.False(t, res.Status)
+		assert.Equal(t, "not implemented", res.Error)
+	})
+}
+
+func TestSabnzbdAPI(t *testing.T) {
+	setupSabnzbdTest(t)
+
+	nzbURL := "https://indexer.example/getnzb/1234?apikey=indexer-secret"
+	urlNzoId := toSabnzbdNzoId(nzb_info.HashNZBFileLink(nzbURL))
+	fileNzoId := ""
+
+	t.Run("addurl", func(t *testing.T) {
+		w := getSabnzbdAPI(t, "alice-key", url.Values{"mode": {"addurl"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = getSabnzbdAPI(t, "alice-key", url.Values{"mode": {"addurl"}, "name": {nzbURL}, "cat": {"tv"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		res := decodeSabnzbdResponse[SabnzbdAddUrlResponse](t, w)
+		assert.True(t, res.Status)
+		assert.Equal(t, []string{urlNzoId}, res.NzoIds)
+	})
+
+	t.Run("addfile", func(t *testing.T) {
+		w := getSabnzbdAPI(t, "alice-key", url.Values{"mode": {"addfile"}})
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+		w = postSabnzbdFile(t, "alice-key", url.Values{}, "name", "broken.nzb", "<nzb>")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = postSabnzbdFile(t, "alice-key", url.Values{"cat": {"movies"}}, "nzbfile", "Movie", sabnzbdTestNZB)
+		assert.Equal(t, http.StatusOK, w.Code)
+		res := decodeSabnzbdResponse[SabnzbdAddUrlResponse](t, w)
+		assert.True(t, res.Status)
+		require.Len(t, res.NzoIds, 1)
+		fileNzoId = res.NzoIds[0]
+
+		info, err := nzb_info.GetByHash(fromSabnzbdNzoIds(fileNzoId)[0])
+		require.NoError(t, err)
+		require.NotNil(t, info)
+		assert.Equal(t, "Movie", info.Name)
+		assert.Equal(t, "alice", info.User)
+		assert.Equal(t, "movies", info.Category)
+		assert.Equal(t, int64(500000), info.Size)
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		queue := getSabnzbdQueue(t, "alice-key")
+		assert.Equal(t, 2, queue.NoOfSlots)
+		slotByNzoId := map[string]SabnzbdQueueSlot{}
+		for _, slot := range queue.Slots {
+			slotByNzoId[slot.NzoId] = slot
+		}
+		if assert.Contains(t, slotByNzoId, urlNzoId) {
+			slot := slotByNzoId[urlNzoId]
+			assert.Equal(t, "Queued", slot.Status)
+			assert.Equal(t, "1234", slot.Filename)
+			assert.Equal(t, "tv", slot.Cat)
+		}
+		if assert.Contains(t, slotByNzoId, fileNzoId) {
+			slot := slotByNzoId[fileNzoId]
+			assert.Equal(t, "Movie", slot.Filename)
+			assert.Equal(t, "movies", slot.Cat)
+			assert.Equal(t, "0.48", slot.MB)
+		}
+
+		assert.Empty(t, getSabnzbdQueue(t, "bob-key").Slots)
+	})
+
+	t.Run("queue pause and resume", func(t *testing.T) {
+		w := getSabnzbdAPI(t, "alice-key", url.Values{"mode": {"queue"}, "name": {"pause"}, "value": {urlNzoId}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{urlNzoId}, decodeSabnzbdResponse[SabnzbdStatusResponse](t, w).NzoIds)
+		for _, slot := range getSabnzbdQueue(t, "alice-key").Slots {
+			if slot.NzoId == urlNzoId {
+				assert.Equal(t, "Paused", slot.Status)
+			}
+		}
+
+		w = getSabnzbdAPI(t, "alice-key", url.Values{"mode": {"queue"}, "name": {"resume"}, "value": {urlNzoId}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		for _, slot := range getSabnzbdQueue(t, "alice-key").Slots {
+			if slot.NzoId == urlNzoId {
+				assert.Equal(t, "Queued", slot.Status)
+			}
+		}
+	})
+
+	t.Run("queue delete", func(t *testing.T) {
+		w := getSabnzbdAPI(t, "bob-key", url.Values{"mode": {"queue"}, "name": {"delete"}, "value": {fileNzoId}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, decodeSabnzbdResponse[SabnzbdStatusResponse](t, w).NzoIds)
+		assert.Len(t, getSabnzbdQueue(t, "alice-key").Slots, 2)
+
+		w = getSabnzbdAPI(t, "alice-key", url.Values{"mode": {"queue"}, "name": {"delete"}, "value": {fileNzoId}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{fileNzoId}, decodeSabnzbdResponse[SabnzbdStatusResponse](t, w).NzoIds)
+
+		queue := getSabnzbdQueue(t, "alice-key")
+		if assert.Len(t, queue.Slots, 1) {
+			assert.Equal(t, urlNzoId, queue.Slots[0].NzoId)
+		}
+		info, err := nzb_info.GetByHash(fromSabnzbdNzoIds(fileNzoId)[0])
+		assert.NoError(t, err)
+		assert.Nil(t, info)
+	})
+
+	doneLink := "http://127.0.0.1:8080/v0/newznab/getnzb/done?apikey=" + url.QueryEscape("YWxpY2U6cGFzc3dvcmQ=")
+	doneNzoId := toSabnzbdNzoId(nzb_info.HashNZBFileLink(doneLink))
+
+	t.Run("history", func(t *testing.T) {
+		require.NoError(t, nzb_info.Upsert(&nzb_info.NZBInfo{
+			Hash:       nzb_info.HashNZBFileLink(doneLink),
+			Name:       "Done",
+			Size:       1024,
+			URL:        doneLink,
+			User:       "alice",
+			Status:     string(store.NewzStatusDownloaded),
+			Streamable: true,
+		}))
+		require.NoError(t, job_queue.SetEntryDead(nzb_info.JobQueueName, fromSabnzbdNzoIds(urlNzoId)[0], db.JSONStringList{"failed to fetch"}))
+
+		history, body := getSabnzbdHistory(t, "alice-key")
+		assert.NotContains(t, body, "apikey")
+		assert.NotContains(t, body, "indexer-secret")
+		assert.Equal(t, 2, history.NoOfSlots)
+		slotByNzoId := map[string]SabnzbdHistorySlot{}
+		for _, slot := range history.Slots {
+			slotByNzoId[slot.NzoId] = slot
+		}
+		if assert.Contains(t, slotByNzoId, doneNzoId) {
+			slot := slotByNzoId[doneNzoId]
+			assert.Equal(t, "Completed", slot.Status)
+			assert.Equal(t, "http://127.0.0.1:8080/v0/newznab/getnzb/done", slot.URL)
+			assert.Equal(t, "/complete/Done", slot.Storage)
+		}
+		if assert.Contains(t, slotByNzoId, urlNzoId) {
+			slot := slotByNzoId[urlNzoId]
+			assert.Equal(t, "Failed", slot.Status)
+			assert.Equal(t, "https://indexer.example/getnzb/1234", slot.URL)
+			assert.Equal(t, "failed to fetch", slot.FailMessage)
+			assert.Equal(t, "tv", slot.Category)
+		}
+
+		assert.Empty(t, getSabnzbdQueue(t, "alice-key").Slots)
+
+		history, _ = getSabnzbdHistory(t, "bob-key")
+		assert.Empty(t, history.Slots)
+	})
+
+	t.Run("history delete", func(t *testing.T) {
+		w := getSabnzbdAPI(t, "alice-key", url.Values{"mode": {"history"}, "name": {"delete"}, "value": {"failed"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, decodeSabnzbdResponse[SabnzbdStatusResponse](t, w).Status)
+
+		history, _ := getSabnzbdHistory(t, "alice-key")
+		if assert.Len(t, history.Slots, 1) {
+			assert.Equal(t, doneNzoId, history.Slots[0].NzoId)
+		}
+
+		w = getSabnzbdAPI(t, "alice-key", url.Values{"mode": {"history"}, "name": {"delete"}, "value": {"all"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		history, _ = getSabnzbdHistory(t, "alice-key")
+		assert.Empty(t, history.Slots)
+	})
+}
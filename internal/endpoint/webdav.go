@@ -0,0 +1,45 @@
+package endpoint
+
+import (
+	"net/http"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/webdav"
+	xwebdav "golang.org/x/net/webdav"
+)
+
+var webdavLockSystem = xwebdav.NewMemLS()
+
+func handleWebDAV(w http.ResponseWriter, r *http.Request) {
+	user, password, ok := r.BasicAuth()
+	// unknown users have empty password
+	if !ok || user == "" || password == "" || config.Auth.GetPassword(user) != password {
+		w.Header().Set("WWW-Authenticate", `Basic realm="StremThru"`)
+		server.ErrorUnauthorized(r).Send(w, r)
+		return
+	}
+
+	log := server.GetReqCtx(r).Log
+
+	handler := &xwebdav.Handler{
+		Prefix:     "/v0/webdav",
+		FileSystem: webdav.NewFileSystem(user),
+		LockSystem: webdavLockSystem,
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				log.Debug("webdav request failed", "error", err, "method", r.Method, "path", r.URL.Path)
+			}
+		},
+	}
+	handler.ServeHTTP(w, r)
+}
+
+func AddWebDAVEndpoints(mux *http.ServeMux) {
+	if config.IsPublicInstance {
+		return
+	}
+
+	mux.HandleFunc("/v0/webdav", handleWebDAV)
+	mux.HandleFunc("/v0/webdav/", handleWebDAV)
+}
@@ -242,6 +242,24 @@ func SetEntryFailed(name, key string, errs db.JSONStringList, processAfter time.
 	return err
 }
 
+var query_set_entry_payload = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ? AND %s = ?`,
+	TableName,
+	Column.Payload,
+	Column.UpdatedAt, db.CurrentTimestamp,
+	Column.Name,
+	Column.Key,
+)
+
+func SetEntryPayload[T any](name, key string, payload T) error {
+	_, err := db.Exec(query_set_entry_payload,
+		db.JSONB[T]{Data: payload},
+		name,
+		key,
+	)
+	return err
+}
+
 var query_set_entry_dead = fmt.Sprintf(
 	`UPDATE %s SET %s = '%s', %s = ?, %s = %s WHERE %s = ? AND %s = ?`,
 	TableName,
@@ -357,3 +375,37 @@ func GetEntriesByName[T any](name string) ([]JobQueueEntry[T], error) {
 
 	return entries, nil
 }
+
+var query_get_entries_by_payload_field = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ? AND %s ->> '%%s' = ? ORDER BY %s DESC`,
+	strings.Join(columns, ", "),
+	TableName,
+	Column.Name,
+	Column.Payload,
+	Column.CreatedAt,
+)
+
+// GetEntriesByPayloadField returns the entries with the top-level payload
+// field set to the value, field must not come from user input.
+func GetEntriesByPayloadField[T any](name, field, value string) ([]JobQueueEntry[T], error) {
+	rows, err := db.Query(fmt.Sprintf(query_get_entries_by_payload_field, field), name, value)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []JobQueueEntry[T]{}
+	for rows.Next() {
+		e := JobQueueEntry[T]{}
+		if err := rows.Scan(&e.Name, &e.Key, &e.Payload, &e.Status, &e.Error, &e.Priority, &e.ProcessAfter, &e.CreatedAt, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
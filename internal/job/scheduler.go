@@ -1,12 +1,15 @@
 package job
 
 import (
+	"context"
 	"sync"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/db"
 	"github.com/MunifTanjim/stremthru/internal/job/job_queue"
+	"github.com/MunifTanjim/stremthru/internal/lease"
 	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/metrics"
 	"github.com/MunifTanjim/stremthru/internal/util"
 	"github.com/madflojo/tasks"
 )
@@ -18,8 +21,10 @@ type stoppable interface {
 
 type Scheduler[T any] struct {
 	conf       *SchedulerConfig[T]
+	ctx        context.Context
 	jobTracker *JobTracker[T]
 	mu         sync.Mutex
+	queue      job_queue.JobQueue[T]
 	scheduler  *tasks.Scheduler
 	triggerCh  chan struct{}
 }
@@ -29,7 +34,56 @@ func (sch *Scheduler[T]) Logger() *logger.Logger {
 }
 
 func (sch *Scheduler[T]) JobQueue() job_queue.JobQueue[T] {
-	return sch.conf.Queue
+	return sch.queue
+}
+
+// Context is cancelled if the lease for an exclusive job is lost, i.e.
+// another instance may take over. Executor should stop as soon as possible
+// after that.
+func (sch *Scheduler[T]) Context() context.Context {
+	if sch.ctx == nil {
+		return context.Background()
+	}
+	return sch.ctx
+}
+
+// Err is non-nil once Context is cancelled.
+func (sch *Scheduler[T]) Err() error {
+	return context.Cause(sch.Context())
+}
+
+// leasedJobQueue stops processing the items once the lease is lost, they are
+// left in the queue for later.
+type leasedJobQueue[T any] struct {
+	job_queue.JobQueue[T]
+	sch *Scheduler[T]
+}
+
+func (q leasedJobQueue[T]) getErr() error {
+	if q.sch.Err() == nil {
+		return nil
+	}
+	return &job_queue.ErrJobQueueItemDelayed{
+		RetryAfter: lease.GetTTL(q.sch.conf.HeartbeatInterval),
+	}
+}
+
+func (q leasedJobQueue[T]) Process(f func(item T) error) {
+	q.JobQueue.Process(func(item T) error {
+		if err := q.getErr(); err != nil {
+			return err
+		}
+		return f(item)
+	})
+}
+
+func (q leasedJobQueue[T]) ProcessGroup(f func(groupKey string, items []T) error) {
+	q.JobQueue.ProcessGroup(func(groupKey string, items []T) error {
+		if err := q.getErr(); err != nil {
+			return err
+		}
+		return f(groupKey, items)
+	})
 }
 
 func (sch *Scheduler[T]) init() {
@@ -142,6 +196,21 @@ func (j *Scheduler[T]) execute(triggered bool) {
 		time.Sleep(1 * time.Minute)
 	}
 
+	j.ctx = context.Background()
+	if conf.RunExclusive {
+		l, err := lease.Acquire("job:"+conf.Id, lease.GetTTL(conf.HeartbeatInterval), conf.HeartbeatInterval)
+		if err != nil {
+			log.Error("failed to acquire lease", "error", err)
+			return
+		}
+		if l == nil {
+			log.Debug("skipping, lease is held by another instance")
+			return
+		}
+		defer l.Release()
+		j.ctx = l.Context()
+	}
+
 	lock := db.NewAdvisoryLock("job", conf.Id)
 	if lock == nil {
 		log.Error("failed to create advisory lock", "name", conf.Id)
@@ -214,6 +283,7 @@ func (j *Scheduler[T]) execute(triggered bool) {
 	heartbeat := time.NewTicker(conf.HeartbeatInterval)
 	heartbeatDone := make(chan struct{})
 	defer close(heartbeatDone)
+	ctx := j.Context()
 	go func() {
 		for {
 			select {
@@ -221,6 +291,9 @@ func (j *Scheduler[T]) execute(triggered bool) {
 				if err := jobTracker.Set(jobId, JobStatusStarted, "", nil); err != nil {
 					log.Error("failed to set job status heartbeat", "error", err, "jobId", jobId)
 				}
+			case <-ctx.Done():
+				heartbeat.Stop()
+				return
 			case <-heartbeatDone:
 				heartbeat.Stop()
 				return
@@ -228,7 +301,13 @@ func (j *Scheduler[T]) execute(triggered bool) {
 		}
 	}()
 
-	if err := conf.Executor(j); err != nil {
+	startedAt := time.Now()
+	err := conf.Executor(j)
+	if err == nil {
+		err = j.Err()
+	}
+	metrics.ObserveWorkerRun(conf.Id, err, time.Since(startedAt))
+	if err != nil {
 		log.Error("Job Failure", "error", err)
 		if terr := jobTracker.Set(jobId, JobStatusFailed, err.Error(), nil); terr != nil {
 			log.Error("failed to set job status", "error", terr, "jobId", jobId, "status", JobStatusFailed)
@@ -298,6 +377,7 @@ func NewScheduler[T any](conf *SchedulerConfig[T]) *Scheduler[T] {
 		triggerCh: make(chan struct{}, 1),
 		conf:      conf,
 	}
+	sch.queue = leasedJobQueue[T]{JobQueue: conf.Queue, sch: sch}
 
 	registerJob(conf.Id, sch)
 
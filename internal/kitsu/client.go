@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/MunifTanjim/stremthru/core"
 	"github.com/MunifTanjim/stremthru/internal/config"
@@ -58,7 +59,10 @@ func NewAPIClient(conf *APIClientConfig) *APIClient {
 	}
 	c.OAuth.client = c
 
-	tokenSource := conf.OAuth.GetTokenSource(c.OAuth.Config)
+	var tokenSource oauth2.TokenSource
+	if conf.OAuth.GetTokenSource != nil {
+		tokenSource = conf.OAuth.GetTokenSource(c.OAuth.Config)
+	}
 	if tokenSource == nil {
 		c.httpClient = conf.HTTPClient
 	} else {
@@ -106,7 +110,7 @@ func (r *ResponseError) GetError(res *http.Response) error {
 func (r *ResponseError) Unmarshal(res *http.Response, body []byte, v any) error {
 	contentType := res.Header.Get("Content-Type")
 	switch {
-	case contentType == "application/vnd.api+json":
+	case strings.Contains(contentType, "application/vnd.api+json"):
 		return core.UnmarshalJSON(res.StatusCode, body, v)
 	default:
 		return errors.New("unexpected content type: " + contentType)
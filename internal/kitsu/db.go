@@ -0,0 +1,382 @@
+package kitsu
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+const ListTableName = "kitsu_list"
+
+type KitsuList struct {
+	Id        string
+	UserId    string
+	UserName  string
+	Status    LibraryEntryStatus
+	UpdatedAt db.Timestamp
+
+	Items []KitsuItem `json:"-"`
+}
+
+func NewListId(userId string, status LibraryEntryStatus) string {
+	return userId + ":" + string(status)
+}
+
+func ParseListId(id string) (userId string, status LibraryEntryStatus, err error) {
+	userId, statusStr, ok := strings.Cut(id, ":")
+	status = LibraryEntryStatus(statusStr)
+	if !ok || userId == "" || !status.IsValid() {
+		return "", "", errors.New("invalid list id")
+	}
+	return userId, status, nil
+}
+
+func (l *KitsuList) GetURL() string {
+	url := "https://kitsu.app/users/" + l.UserId + "/library?media=anime"
+	if l.Status != LibraryEntryStatusAll {
+		url += "&status=" + string(l.Status)
+	}
+	return url
+}
+
+func (l *KitsuList) GetDisplayName() string {
+	return l.UserName + " / " + libraryEntryStatusName[l.Status]
+}
+
+func (l *KitsuList) IsStale() bool {
+	return time.Now().After(l.UpdatedAt.Add(config.Integration.Kitsu.ListStaleTime + util.GetRandomDuration(5*time.Second, 5*time.Minute)))
+}
+
+var ListColumn = struct {
+	Id        string
+	UserId    string
+	UserName  string
+	Status    string
+	UpdatedAt string
+}{
+	Id:        "id",
+	UserId:    "user_id",
+	UserName:  "user_name",
+	Status:    "status",
+	UpdatedAt: "uat",
+}
+
+var ListColumns = []string{
+	ListColumn.Id,
+	ListColumn.UserId,
+	ListColumn.UserName,
+	ListColumn.Status,
+	ListColumn.UpdatedAt,
+}
+
+const ItemTableName = "kitsu_item"
+
+type KitsuItem struct {
+	Id          int
+	Type        AnimeSubtype
+	Title       string
+	Description string
+	Poster      string
+	Background  string
+	StartYear   int
+	IsAdult     bool
+	UpdatedAt   db.Timestamp
+
+	Idx int `json:"-"`
+}
+
+var ItemColumn = struct {
+	Id          string
+	Type        string
+	Title       string
+	Description string
+	Poster      string
+	Background  string
+	StartYear   string
+	IsAdult     string
+	UpdatedAt   string
+}{
+	Id:          "id",
+	Type:        "type",
+	Title:       "title",
+	Description: "description",
+	Poster:      "poster",
+	Background:  "background",
+	StartYear:   "start_year",
+	IsAdult:     "is_adult",
+	UpdatedAt:   "uat",
+}
+
+var ItemColumns = []string{
+	ItemColumn.Id,
+	ItemColumn.Type,
+	ItemColumn.Title,
+	ItemColumn.Description,
+	ItemColumn.Poster,
+	ItemColumn.Background,
+	ItemColumn.StartYear,
+	ItemColumn.IsAdult,
+	ItemColumn.UpdatedAt,
+}
+
+const ListItemTableName = "kitsu_list_item"
+
+type KitsuListItem struct {
+	ListId string
+	ItemId int
+	Idx    int
+}
+
+var ListItemColumn = struct {
+	ListId string
+	ItemId string
+	Idx    string
+}{
+	ListId: "list_id",
+	ItemId: "item_id",
+	Idx:    "idx",
+}
+
+var ListItemColumns = []string{
+	ListItemColumn.ListId,
+	ListItemColumn.ItemId,
+	ListItemColumn.Idx,
+}
+
+var query_get_list_by_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ?`,
+	db.JoinColumnNames(ListColumns...),
+	ListTableName,
+	ListColumn.Id,
+)
+
+func GetListById(id string) (*KitsuList, error) {
+	row := db.QueryRow(query_get_list_by_id, id)
+	list := &KitsuList{}
+	if err := row.Scan(
+		&list.Id,
+		&list.UserId,
+		&list.UserName,
+		&list.Status,
+		&list.UpdatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	items, err := GetListItems(id)
+	if err != nil {
+		return nil, err
+	}
+	list.Items = items
+	return list, nil
+}
+
+var query_get_list_items = fmt.Sprintf(
+	`SELECT %s, li.%s FROM %s li JOIN %s i ON i.%s = li.%s WHERE li.%s = ? ORDER BY li.%s ASC`,
+	db.JoinPrefixedColumnNames("i.", ItemColumns...),
+	ListItemColumn.Idx,
+	ListItemTableName,
+	ItemTableName,
+	ItemColumn.Id,
+	ListItemColumn.ItemId,
+	ListItemColumn.ListId,
+	ListItemColumn.Idx,
+)
+
+func GetListItems(listId string) ([]KitsuItem, error) {
+	var items []KitsuItem
+	rows, err := db.Query(query_get_list_items, listId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item KitsuItem
+		if err := rows.Scan(
+			&item.Id,
+			&item.Type,
+			&item.Title,
+			&item.Description,
+			&item.Poster,
+			&item.Background,
+			&item.StartYear,
+			&item.IsAdult,
+			&item.UpdatedAt,
+			&item.Idx,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+var query_upsert_list = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s`,
+	ListTableName,
+	strings.Join(ListColumns[:len(ListColumns)-1], ", "),
+	util.RepeatJoin("?", len(ListColumns)-1, ", "),
+	ListColumn.Id,
+	strings.Join([]string{
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ListColumn.UserId, ListColumn.UserId),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ListColumn.UserName, ListColumn.UserName),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ListColumn.Status, ListColumn.Status),
+		fmt.Sprintf(`%s = %s`, ListColumn.UpdatedAt, db.CurrentTimestamp),
+	}, ", "),
+)
+
+func UpsertList(list *KitsuList) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		tErr := tx.Rollback()
+		err = errors.Join(tErr, err)
+	}()
+
+	_, err = tx.Exec(
+		query_upsert_list,
+		list.Id,
+		list.UserId,
+		list.UserName,
+		list.Status,
+	)
+	if err != nil {
+		return err
+	}
+
+	list.UpdatedAt = db.Timestamp{Time: time.Now()}
+
+	err = upsertItems(tx, list.Items)
+	if err != nil {
+		return err
+	}
+
+	err = setListItems(tx, list.Id, list.Items)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var query_upsert_items_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES `,
+	ItemTableName,
+	strings.Join(ItemColumns[:len(ItemColumns)-1], ", "),
+)
+var query_upsert_items_values_placholder = fmt.Sprintf(
+	`(%s)`,
+	util.RepeatJoin("?", len(ItemColumns)-1, ","),
+)
+var query_upsert_items_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s) DO UPDATE SET %s`,
+	ItemColumn.Id,
+	strings.Join([]string{
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Type, ItemColumn.Type),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Title, ItemColumn.Title),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Description, ItemColumn.Description),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Poster, ItemColumn.Poster),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Background, ItemColumn.Background),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.StartYear, ItemColumn.StartYear),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.IsAdult, ItemColumn.IsAdult),
+		fmt.Sprintf(`%s = %s`, ItemColumn.UpdatedAt, db.CurrentTimestamp),
+	}, ", "),
+)
+
+func upsertItems(tx db.Executor, items []KitsuItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	for cItems := range slices.Chunk(items, 500) {
+		count := len(cItems)
+
+		query := query_upsert_items_before_values +
+			util.RepeatJoin(query_upsert_items_values_placholder, count, ",") +
+			query_upsert_items_after_values
+
+		columnCount := len(ItemColumns) - 1
+		args := make([]any, count*columnCount)
+		for i, item := range cItems {
+			args[i*columnCount+0] = item.Id
+			args[i*columnCount+1] = item.Type
+			args[i*columnCount+2] = item.Title
+			args[i*columnCount+3] = item.Description
+			args[i*columnCount+4] = item.Poster
+			args[i*columnCount+5] = item.Background
+			args[i*columnCount+6] = item.StartYear
+			args[i*columnCount+7] = item.IsAdult
+		}
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var query_set_list_item_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s,%s,%s) VALUES `,
+	ListItemTableName,
+	ListItemColumn.ListId,
+	ListItemColumn.ItemId,
+	ListItemColumn.Idx,
+)
+var query_set_list_item_values_placeholder = `(?,?,?)`
+var query_set_list_item_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s,%s) DO UPDATE SET %s = EXCLUDED.%s`,
+	ListItemColumn.ListId,
+	ListItemColumn.ItemId,
+	ListItemColumn.Idx,
+	ListItemColumn.Idx,
+)
+var query_cleanup_list_item = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	ListItemTableName,
+	ListItemColumn.ListId,
+)
+
+func setListItems(tx db.Executor, listId string, items []KitsuItem) error {
+	if _, err := tx.Exec(query_cleanup_list_item, listId); err != nil {
+		return err
+	}
+
+	for cItems := range slices.Chunk(items, 500) {
+		count := len(cItems)
+		query := query_set_list_item_before_values +
+			util.RepeatJoin(query_set_list_item_values_placeholder, count, ",") +
+			query_set_list_item_after_values
+		args := make([]any, count*3)
+		for i, item := range cItems {
+			args[i*3+0] = listId
+			args[i*3+1] = item.Id
+			args[i*3+2] = item.Idx
+		}
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package kitsu
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/anime"
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	"github.com/MunifTanjim/stremthru/internal/worker/worker_queue"
+)
+
+var listCache = cache.NewCache[KitsuList](&cache.CacheConfig{
+	Lifetime: 6 * time.Hour,
+	Name:     "kitsu:list",
+	MaxSize:  1024,
+})
+
+func ScheduleIdMapSync(items []KitsuItem) {
+	for i := range items {
+		worker_queue.AnimeIdMapperQueue.Queue(worker_queue.AnimeIdMapperQueueItem{
+			Service: anime.IdMapColumn.Kitsu,
+			Id:      strconv.Itoa(items[i].Id),
+		})
+	}
+}
+
+var syncListMutex sync.Mutex
+
+// toListItems skips the duplicate items.
+func toListItems(entries []LibraryAnime) []KitsuItem {
+	listItems := make([]KitsuItem, 0, len(entries))
+	seenMap := map[int]struct{}{}
+	for i := range entries {
+		media := entries[i].Anime
+		id := util.SafeParseInt(media.Id, 0)
+		if _, seen := seenMap[id]; seen {
+			continue
+		}
+		seenMap[id] = struct{}{}
+
+		item := KitsuItem{
+			Id:          id,
+			Type:        media.Attributes.Subtype,
+			Title:       media.Attributes.CanonicalTitle,
+			Description: media.Attributes.Synopsis,
+			StartYear:   media.StartYear(),
+			IsAdult:     media.Attributes.NSFW,
+
+			Idx: i,
+		}
+		if img := media.Attributes.PosterImage; img != nil {
+			item.Poster = img.Medium
+			if item.Poster == "" {
+				item.Poster = img.Original
+			}
+		}
+		if img := media.Attributes.CoverImage; img != nil {
+			item.Background = img.Original
+		}
+		listItems = append(listItems, item)
+	}
+	return listItems
+}
+
+func syncList(l *KitsuList) error {
+	syncListMutex.Lock()
+	defer syncListMutex.Unlock()
+
+	if l.UserName == "" {
+		user, err := FetchUser(l.UserId)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return errors.New("user not found")
+		}
+		l.UserName = user.Attributes.Name
+	}
+
+	log.Debug("fetching list items", "id", l.Id)
+	entries, err := FetchUserLibrary(l.UserId, l.Status)
+	if err != nil {
+		return err
+	}
+
+	l.Items = toListItems(entries)
+
+	if err := UpsertList(l); err != nil {
+		return err
+	}
+
+	if err := listCache.Add(l.Id, *l); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (l *KitsuList) Fetch() error {
+	if l.Id == "" {
+		return errors.New("id must be provided")
+	}
+
+	userId, status, err := ParseListId(l.Id)
+	if err != nil {
+		return err
+	}
+
+	isMissing := false
+
+	var cachedL KitsuList
+	if !listCache.Get(l.Id, &cachedL) {
+		if list, err := GetListById(l.Id); err != nil {
+			return err
+		} else if list == nil {
+			isMissing = true
+		} else {
+			*l = *list
+			log.Debug("found list by id", "id", l.Id, "is_stale", l.IsStale())
+			listCache.Add(l.Id, *l)
+		}
+	} else {
+		*l = cachedL
+	}
+
+	if isMissing {
+		l.UserId = userId
+		l.Status = status
+		return syncList(l)
+	}
+
+	if l.IsStale() {
+		staleList := *l
+		go func() {
+			if err := syncList(&staleList); err != nil {
+				log.Error("failed to sync stale list", "id", l.Id, "error", err)
+			}
+		}()
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package kitsu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToListItems(t *testing.T) {
+	newAnime := func(id, title string, subtype AnimeSubtype, startDate string, nsfw bool, poster, cover *AnimeImage) *Anime {
+		a := &Anime{Id: id, Type: "anime"}
+		a.Attributes.CanonicalTitle = title
+		a.Attributes.Synopsis = title + "."
+		a.Attributes.Subtype = subtype
+		a.Attributes.StartDate = startDate
+		a.Attributes.NSFW = nsfw
+		a.Attributes.PosterImage = poster
+		a.Attributes.CoverImage = cover
+		return a
+	}
+
+	items := toListItems([]LibraryAnime{
+		{Anime: newAnime("12", "One Piece", AnimeSubtypeTV, "1999-10-20", false,
+			&AnimeImage{Medium: "poster-medium", Original: "poster-original"},
+			&AnimeImage{Original: "cover-original"},
+		)},
+		{Anime: newAnime("176", "Spirited Away", AnimeSubtypeMovie, "", false,
+			&AnimeImage{Original: "poster-original"},
+			nil,
+		)},
+		{Anime: newAnime("12", "One Piece", AnimeSubtypeTV, "1999-10-20", false, nil, nil)},
+		{Anime: newAnime("300", "Adult", AnimeSubtypeOVA, "2001", true, nil, nil)},
+	})
+	assert.Equal(t, []KitsuItem{
+		{
+			Id:          12,
+			Type:        AnimeSubtypeTV,
+			Title:       "One Piece",
+			Description: "One Piece.",
+			Poster:      "poster-medium",
+			Background:  "cover-original",
+			StartYear:   1999,
+			Idx:         0,
+		},
+		{
+			Id:          176,
+			Type:        AnimeSubtypeMovie,
+			Title:       "Spirited Away",
+			Description: "Spirited Away.",
+			Poster:      "poster-original",
+			Idx:         1,
+		},
+		{
+			Id:          300,
+			Type:        AnimeSubtypeOVA,
+			Title:       "Adult",
+			Description: "Adult.",
+			StartYear:   2001,
+			IsAdult:     true,
+			Idx:         3,
+		},
+	}, items)
+}
@@ -8,6 +8,8 @@ import (
 	"golang.org/x/oauth2"
 )
 
+var publicClient = NewAPIClient(&APIClientConfig{})
+
 var apiClientCache = cache.NewLRUCache[APIClient](&cache.CacheConfig{
 	Lifetime: 1 * time.Hour,
 	Name:     "kitsu:api-client",
@@ -0,0 +1,216 @@
+package kitsu
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+type LibraryEntryStatus string
+
+const (
+	LibraryEntryStatusAll       LibraryEntryStatus = "all"
+	LibraryEntryStatusCurrent   LibraryEntryStatus = "current"
+	LibraryEntryStatusPlanned   LibraryEntryStatus = "planned"
+	LibraryEntryStatusCompleted LibraryEntryStatus = "completed"
+	LibraryEntryStatusOnHold    LibraryEntryStatus = "on_hold"
+	LibraryEntryStatusDropped   LibraryEntryStatus = "dropped"
+)
+
+var libraryEntryStatusName = map[LibraryEntryStatus]string{
+	LibraryEntryStatusAll:       "Library",
+	LibraryEntryStatusCurrent:   "Currently Watching",
+	LibraryEntryStatusPlanned:   "Want to Watch",
+	LibraryEntryStatusCompleted: "Completed",
+	LibraryEntryStatusOnHold:    "On Hold",
+	LibraryEntryStatusDropped:   "Dropped",
+}
+
+func (s LibraryEntryStatus) IsValid() bool {
+	_, ok := libraryEntryStatusName[s]
+	return ok
+}
+
+type User struct {
+	Id         string `json:"id"`
+	Attributes struct {
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	} `json:"attributes"`
+}
+
+type getUsersData struct {
+	ResponseError
+	Data []User `json:"data"`
+}
+
+type GetUserParams struct {
+	Ctx
+	// user id or slug
+	Id string
+}
+
+func (c APIClient) GetUser(params *GetUserParams) (APIResponse[*User], error) {
+	query := url.Values{}
+	if _, err := strconv.Atoi(params.Id); err == nil {
+		query.Set("filter[id]", params.Id)
+	} else {
+		query.Set("filter[slug]", params.Id)
+	}
+	query.Set("fields[users]", "name,slug")
+	params.Query = &query
+
+	response := getUsersData{}
+	res, err := c.Request("GET", "/users", params, &response)
+	if err != nil || len(response.Data) == 0 {
+		return newAPIResponse[*User](res, nil), err
+	}
+	return newAPIResponse(res, &response.Data[0]), nil
+}
+
+type AnimeImage struct {
+	Tiny     string `json:"tiny"`
+	Small    string `json:"small"`
+	Medium   string `json:"medium"`
+	Large    string `json:"large"`
+	Original string `json:"original"`
+}
+
+type Anime struct {
+	Id         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		CanonicalTitle string       `json:"canonicalTitle"`
+		Synopsis       string       `json:"synopsis"`
+		Subtype        AnimeSubtype `json:"subtype"`
+		StartDate      string       `json:"startDate"` // YYYY-MM-DD
+		NSFW           bool         `json:"nsfw"`
+		PosterImage    *AnimeImage  `json:"posterImage"`
+		CoverImage     *AnimeImage  `json:"coverImage"`
+	} `json:"attributes"`
+}
+
+func (a *Anime) StartYear() int {
+	year, _, _ := strings.Cut(a.Attributes.StartDate, "-")
+	y, _ := strconv.Atoi(year)
+	return y
+}
+
+type LibraryEntry struct {
+	Id         string `json:"id"`
+	Attributes struct {
+		Status LibraryEntryStatus `json:"status"`
+	} `json:"attributes"`
+	Relationships struct {
+		Anime struct {
+			Data *struct {
+				Id   string `json:"id"`
+				Type string `json:"type"`
+			} `json:"data"`
+		} `json:"anime"`
+	} `json:"relationships"`
+}
+
+type getLibraryEntriesData struct {
+	ResponseError
+	Data     []LibraryEntry `json:"data"`
+	Included []Anime        `json:"included"`
+	Links    struct {
+		Next string `json:"next,omitempty"`
+	} `json:"links"`
+}
+
+type GetLibraryEntriesParams struct {
+	Ctx
+	UserId string
+	Status LibraryEntryStatus
+	Limit  int // max 500
+	Offset int
+}
+
+type GetLibraryEntriesData struct {
+	Entries []LibraryEntry
+	Anime   map[string]*Anime
+	HasNext bool
+}
+
+func (c APIClient) GetLibraryEntries(params *GetLibraryEntriesParams) (APIResponse[GetLibraryEntriesData], error) {
+	query := url.Values{}
+	query.Set("filter[user_id]", params.UserId)
+	query.Set("filter[kind]", "anime")
+	if params.Status != "" && params.Status != LibraryEntryStatusAll {
+		query.Set("filter[status]", string(params.Status))
+	}
+	query.Set("include", "anime")
+	query.Set("fields[libraryEntries]", "status,anime")
+	query.Set("fields[anime]", "canonicalTitle,synopsis,subtype,startDate,nsfw,posterImage,coverImage")
+	query.Set("sort", "-updated_at")
+	if params.Limit == 0 {
+		params.Limit = 500
+	}
+	query.Set("page[limit]", strconv.Itoa(params.Limit))
+	query.Set("page[offset]", strconv.Itoa(params.Offset))
+	params.Query = &query
+
+	response := getLibraryEntriesData{}
+	res, err := c.Request("GET", "/library-entries", params, &response)
+	data := GetLibraryEntriesData{
+		Entries: response.Data,
+		Anime:   make(map[string]*Anime, len(response.Included)),
+		HasNext: response.Links.Next != "",
+	}
+	for i := range response.Included {
+		media := &response.Included[i]
+		if media.Type == "anime" {
+			data.Anime[media.Id] = media
+		}
+	}
+	return newAPIResponse(res, data), err
+}
+
+type LibraryAnime struct {
+	Status LibraryEntryStatus
+	Anime  *Anime
+}
+
+func FetchUserLibrary(userId string, status LibraryEntryStatus) ([]LibraryAnime, error) {
+	items := []LibraryAnime{}
+	limit := 500
+	for offset := 0; ; offset += limit {
+		res, err := publicClient.GetLibraryEntries(&GetLibraryEntriesParams{
+			UserId: userId,
+			Status: status,
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range res.Data.Entries {
+			entry := &res.Data.Entries[i]
+			if entry.Relationships.Anime.Data == nil {
+				continue
+			}
+			if media, ok := res.Data.Anime[entry.Relationships.Anime.Data.Id]; ok && util.SafeParseInt(media.Id, 0) > 0 {
+				items = append(items, LibraryAnime{
+					Status: entry.Attributes.Status,
+					Anime:  media,
+				})
+			}
+		}
+		if !res.Data.HasNext || len(res.Data.Entries) < limit {
+			break
+		}
+	}
+	return items, nil
+}
+
+func FetchUser(idOrSlug string) (*User, error) {
+	res, err := publicClient.GetUser(&GetUserParams{Id: idOrSlug})
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
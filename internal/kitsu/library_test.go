@@ -0,0 +1,123 @@
+package kitsu
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestServer(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	baseURL, err := url.Parse(server.URL + "/api/edge")
+	require.NoError(t, err)
+	originalBaseURL := publicClient.BaseURL
+	publicClient.BaseURL = baseURL
+	t.Cleanup(func() {
+		publicClient.BaseURL = originalBaseURL
+	})
+}
+
+func writeJSONAPI(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestFetchUserLibrary(t *testing.T) {
+	type M = map[string]any
+
+	pageSize := 500
+	totalCount := pageSize + 2
+
+	setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/edge/library-entries", r.URL.Path)
+		query := r.URL.Query()
+		assert.Equal(t, "1234", query.Get("filter[user_id]"))
+		assert.Equal(t, "anime", query.Get("filter[kind]"))
+		assert.Equal(t, "completed", query.Get("filter[status]"))
+		assert.Equal(t, strconv.Itoa(pageSize), query.Get("page[limit]"))
+
+		offset, _ := strconv.Atoi(query.Get("page[offset]"))
+		data, included := []M{}, []M{}
+		for i := offset; i < min(offset+pageSize, totalCount); i++ {
+			animeId := strconv.Itoa(i + 1)
+			entry := M{
+				"id":         "e" + animeId,
+				"attributes": M{"status": "completed"},
+				"relationships": M{"anime": M{
+					"data": M{"id": animeId, "type": "anime"},
+				}},
+			}
+			switch i {
+			case 1:
+				// entry without anime
+				entry["relationships"] = M{"anime": M{"data": nil}}
+			case 2:
+				// anime not included
+				entry["relationships"] = M{"anime": M{"data": M{"id": "99999", "type": "anime"}}}
+			}
+			data = append(data, entry)
+			included = append(included, M{
+				"id":         animeId,
+				"type":       "anime",
+				"attributes": M{"canonicalTitle": "Anime " + animeId, "subtype": "TV", "startDate": "2020-01-01"},
+			})
+		}
+		included = append(included, M{"id": "1", "type": "manga"})
+		links := M{}
+		if offset+pageSize < totalCount {
+			links["next"] = "next"
+		}
+		writeJSONAPI(w, M{"data": data, "included": included, "links": links})
+	})
+
+	items, err := FetchUserLibrary("1234", LibraryEntryStatusCompleted)
+	require.NoError(t, err)
+	assert.Len(t, items, totalCount-2)
+	assert.Equal(t, "1", items[0].Anime.Id)
+	assert.Equal(t, LibraryEntryStatusCompleted, items[0].Status)
+	assert.Equal(t, "Anime 1", items[0].Anime.Attributes.CanonicalTitle)
+	assert.Equal(t, AnimeSubtypeTV, items[0].Anime.Attributes.Subtype)
+	assert.Equal(t, 2020, items[0].Anime.StartYear())
+	assert.Equal(t, "4", items[1].Anime.Id)
+	assert.Equal(t, strconv.Itoa(totalCount), items[len(items)-1].Anime.Id)
+}
+
+func TestFetchUser(t *testing.T) {
+	type M = map[string]any
+
+	setupTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/edge/users", r.URL.Path)
+		query := r.URL.Query()
+		if query.Get("filter[id]") == "1234" || query.Get("filter[slug]") == "jane" {
+			writeJSONAPI(w, M{"data": []M{
+				{"id": "1234", "attributes": M{"name": "Jane", "slug": "jane"}},
+			}})
+			return
+		}
+		writeJSONAPI(w, M{"data": []M{}})
+	})
+
+	for _, idOrSlug := range []string{"1234", "jane"} {
+		user, err := FetchUser(idOrSlug)
+		require.NoError(t, err)
+		if assert.NotNil(t, user) {
+			assert.Equal(t, "1234", user.Id)
+			assert.Equal(t, "Jane", user.Attributes.Name)
+			assert.Equal(t, "jane", user.Attributes.Slug)
+		}
+	}
+
+	user, err := FetchUser("unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+}
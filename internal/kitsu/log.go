@@ -0,0 +1,5 @@
+package kitsu
+
+import "github.com/MunifTanjim/stremthru/internal/logger"
+
+var log = logger.Scoped("kitsu")
@@ -0,0 +1,182 @@
+package lease
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/db"
+)
+
+const TableName = "lease"
+
+type LeaseRecord struct {
+	Name       string
+	Holder     string
+	AcquiredAt db.Timestamp
+	RenewedAt  db.Timestamp
+	ExpiresAt  db.Timestamp
+
+	isActive bool
+}
+
+// IsActive is checked against the db clock at the time of the query.
+func (r *LeaseRecord) IsActive() bool {
+	return r.isActive
+}
+
+var Column = struct {
+	Name       string
+	Holder     string
+	AcquiredAt string
+	RenewedAt  string
+	ExpiresAt  string
+}{
+	Name:       "name",
+	Holder:     "holder",
+	AcquiredAt: "acquired_at",
+	RenewedAt:  "renewed_at",
+	ExpiresAt:  "expires_at",
+}
+
+var columns = []string{
+	Column.Name,
+	Column.Holder,
+	Column.AcquiredAt,
+	Column.RenewedAt,
+	Column.ExpiresAt,
+}
+
+// the db clock is used for expiry, so that the clock skew between instances
+// does not matter.
+var expiresAtExpr = func() string {
+	if db.Dialect == db.DBDialectPostgres {
+		return db.CurrentTimestamp + " + make_interval(secs => ?)"
+	}
+	return db.CurrentTimestamp + " + ?"
+}()
+
+// the existing lease is taken over only if it is held by this instance, or
+// if it has expired.
+var query_try_acquire = fmt.Sprintf(
+	`INSERT INTO %s AS l (%s) VALUES (?, ?, %s, %s, %s) ON CONFLICT (%s) DO UPDATE SET %s WHERE l.%s = EXCLUDED.%s OR l.%s <= EXCLUDED.%s`,
+	TableName,
+	strings.Join(columns, ","),
+	db.CurrentTimestamp,
+	db.CurrentTimestamp,
+	expiresAtExpr,
+	Column.Name,
+	strings.Join([]string{
+		fmt.Sprintf("%s = CASE WHEN l.%s = EXCLUDED.%s THEN l.%s ELSE EXCLUDED.%s END", Column.AcquiredAt, Column.Holder, Column.Holder, Column.AcquiredAt, Column.AcquiredAt),
+		fmt.Sprintf("%s = EXCLUDED.%s", Column.Holder, Column.Holder),
+		fmt.Sprintf("%s = EXCLUDED.%s", Column.RenewedAt, Column.RenewedAt),
+		fmt.Sprintf("%s = EXCLUDED.%s", Column.ExpiresAt, Column.ExpiresAt),
+	}, ", "),
+	Column.Holder, Column.Holder,
+	Column.ExpiresAt, Column.AcquiredAt,
+)
+
+func tryAcquire(name string, ttl time.Duration) (bool, error) {
+	result, err := db.Exec(
+		query_try_acquire,
+		name,
+		config.InstanceId,
+		int64(ttl.Seconds()),
+	)
+	if err != nil {
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+var query_renew = fmt.Sprintf(
+	`UPDATE %s SET %s = %s, %s = %s WHERE %s = ? AND %s = ? AND %s > %s`,
+	TableName,
+	Column.RenewedAt, db.CurrentTimestamp,
+	Column.ExpiresAt, expiresAtExpr,
+	Column.Name,
+	Column.Holder,
+	Column.ExpiresAt, db.CurrentTimestamp,
+)
+
+func renew(name string, ttl time.Duration) (bool, error) {
+	result, err := db.Exec(
+		query_renew,
+		int64(ttl.Seconds()),
+		name,
+		config.InstanceId,
+	)
+	if err != nil {
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+// the record is kept, so that the last holder is still known
+var query_release = fmt.Sprintf(
+	`UPDATE %s SET %s = %s WHERE %s = ? AND %s = ?`,
+	TableName,
+	Column.ExpiresAt, db.CurrentTimestamp,
+	Column.Name,
+	Column.Holder,
+)
+
+func release(name string) error {
+	_, err := db.Exec(query_release, name, config.InstanceId)
+	return err
+}
+
+var query_get_all = fmt.Sprintf(
+	`SELECT %s, %s > %s FROM %s`,
+	strings.Join(columns, ", "),
+	Column.ExpiresAt, db.CurrentTimestamp,
+	TableName,
+)
+
+func GetAll() ([]LeaseRecord, error) {
+	rows, err := db.Query(query_get_all)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []LeaseRecord{}
+	for rows.Next() {
+		item := LeaseRecord{}
+		if err := rows.Scan(&item.Name, &item.Holder, &item.AcquiredAt, &item.RenewedAt, &item.ExpiresAt, &item.isActive); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+var query_get_by_name = fmt.Sprintf(
+	`SELECT %s, %s > %s FROM %s WHERE %s = ?`,
+	strings.Join(columns, ", "),
+	Column.ExpiresAt, db.CurrentTimestamp,
+	TableName,
+	Column.Name,
+)
+
+func GetByName(name string) (*LeaseRecord, error) {
+	item := LeaseRecord{}
+	row := db.QueryRow(query_get_by_name, name)
+	if err := row.Scan(&item.Name, &item.Holder, &item.AcquiredAt, &item.RenewedAt, &item.ExpiresAt, &item.isActive); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
@@ -0,0 +1,125 @@
+package lease
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryAcquire(t *testing.T) {
+	dbtest.Open(t)
+
+	instanceId := config.InstanceId
+	t.Cleanup(func() { config.InstanceId = instanceId })
+
+	name := "worker:test"
+	ttl := 5 * time.Minute
+
+	// moves the timestamps to the past, the db clock has second resolution
+	backdate := func(t *testing.T, column string, seconds int) {
+		t.Helper()
+		_, err := db.Exec("UPDATE "+TableName+" SET "+column+" = "+column+" - ? WHERE "+Column.Name+" = ?", seconds, name)
+		require.NoError(t, err)
+	}
+
+	getLease := func(t *testing.T) *LeaseRecord {
+		t.Helper()
+		l, err := GetByName(name)
+		require.NoError(t, err)
+		require.NotNil(t, l)
+		return l
+	}
+
+	t.Run("acquire", func(t *testing.T) {
+		config.InstanceId = "instance-a"
+		ok, err := tryAcquire(name, ttl)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		l := getLease(t)
+		assert.Equal(t, "instance-a", l.Holder)
+		assert.True(t, l.IsActive())
+		assert.Equal(t, l.AcquiredAt.Unix(), l.RenewedAt.Unix())
+		assert.Equal(t, l.AcquiredAt.Add(ttl).Unix(), l.ExpiresAt.Unix())
+	})
+
+	t.Run("re-acquire by same holder", func(t *testing.T) {
+		backdate(t, Column.AcquiredAt, 60)
+		acquiredAt := getLease(t).AcquiredAt
+
+		config.InstanceId = "instance-a"
+		ok, err := tryAcquire(name, ttl)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		l := getLease(t)
+		assert.Equal(t, "instance-a", l.Holder)
+		assert.True(t, l.IsActive())
+		assert.Equal(t, acquiredAt.Unix(), l.AcquiredAt.Unix())
+		assert.True(t, l.RenewedAt.After(l.AcquiredAt.Time))
+	})
+
+	t.Run("blocked by another holder", func(t *testing.T) {
+		before := getLease(t)
+
+		config.InstanceId = "instance-b"
+		ok, err := tryAcquire(name, ttl)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		l := getLease(t)
+		assert.Equal(t, "instance-a", l.Holder)
+		assert.True(t, l.IsActive())
+		assert.Equal(t, before.AcquiredAt.Unix(), l.AcquiredAt.Unix())
+		assert.Equal(t, before.ExpiresAt.Unix(), l.ExpiresAt.Unix())
+
+		renewed, err := renew(name, ttl)
+		require.NoError(t, err)
+		assert.False(t, renewed)
+
+		require.NoError(t, release(name))
+		assert.True(t, getLease(t).IsActive())
+	})
+
+	t.Run("takeover after expiry", func(t *testing.T) {
+		backdate(t, Column.AcquiredAt, 600)
+		backdate(t, Column.ExpiresAt, 600)
+		expired := getLease(t)
+		require.False(t, expired.IsActive())
+
+		config.InstanceId = "instance-b"
+		ok, err := tryAcquire(name, ttl)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		l := getLease(t)
+		assert.Equal(t, "instance-b", l.Holder)
+		assert.True(t, l.IsActive())
+		assert.True(t, l.AcquiredAt.After(expired.AcquiredAt.Time))
+
+		config.InstanceId = "instance-a"
+		renewed, err := renew(name, ttl)
+		require.NoError(t, err)
+		assert.False(t, renewed)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		config.InstanceId = "instance-b"
+		require.NoError(t, release(name))
+
+		l := getLease(t)
+		assert.Equal(t, "instance-b", l.Holder)
+		assert.False(t, l.IsActive())
+
+		config.InstanceId = "instance-a"
+		ok, err := tryAcquire(name, ttl)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "instance-a", getLease(t).Holder)
+	})
+}
@@ -0,0 +1,124 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/logger"
+)
+
+var log = logger.Scoped("lease")
+
+var ErrLost = errors.New("lease lost")
+
+// Lease is held by a single instance at a time. It is kept alive by
+// heartbeats, and expires if the holder stops renewing it, so that another
+// instance can take over.
+type Lease struct {
+	name        string
+	ttl         time.Duration
+	lost        atomic.Bool
+	ctx         context.Context
+	cancel      context.CancelCauseFunc
+	stop        chan struct{}
+	done        chan struct{}
+	releaseOnce sync.Once
+}
+
+func (l *Lease) GetName() string {
+	return l.name
+}
+
+// IsLost reports if a heartbeat failed to renew the lease, i.e. another
+// instance may have taken it over.
+func (l *Lease) IsLost() bool {
+	return l.lost.Load()
+}
+
+// Context is cancelled with ErrLost as the cause once the lease is lost, so
+// that the work guarded by the lease can be stopped.
+func (l *Lease) Context() context.Context {
+	return l.ctx
+}
+
+func (l *Lease) setLost() {
+	l.lost.Store(true)
+	l.cancel(ErrLost)
+}
+
+func (l *Lease) heartbeat(interval time.Duration) {
+	defer close(l.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	renewedAt := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+			renewed, err := renew(l.name, l.ttl)
+			if err != nil {
+				log.Error("failed to renew lease", "error", err, "name", l.name)
+				// it may expire before the next heartbeat, so assume the worst
+				if time.Since(renewedAt)+interval >= l.ttl {
+					log.Warn("lease lost, failed to renew before expiry", "name", l.name)
+					l.setLost()
+					return
+				}
+				continue
+			}
+			if !renewed {
+				log.Warn("lease lost", "name", l.name)
+				l.setLost()
+				return
+			}
+			renewedAt = time.Now()
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+func (l *Lease) Release() {
+	l.releaseOnce.Do(func() {
+		close(l.stop)
+		<-l.done
+		if l.IsLost() {
+			return
+		}
+		l.cancel(nil)
+		if err := release(l.name); err != nil {
+			log.Error("failed to release lease", "error", err, "name", l.name)
+		}
+	})
+}
+
+// TTL for a lease that is renewed every heartbeatInterval, tolerating a
+// couple of missed heartbeats.
+func GetTTL(heartbeatInterval time.Duration) time.Duration {
+	return max(3*heartbeatInterval, 30*time.Second)
+}
+
+// Acquire returns nil if the lease is held by another instance. Once
+// acquired, it is renewed every heartbeatInterval until released.
+func Acquire(name string, ttl time.Duration, heartbeatInterval time.Duration) (*Lease, error) {
+	acquired, err := tryAcquire(name, ttl)
+	if err != nil || !acquired {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	l := &Lease{
+		name:   name,
+		ttl:    ttl,
+		ctx:    ctx,
+		cancel: cancel,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go l.heartbeat(heartbeatInterval)
+	return l, nil
+}
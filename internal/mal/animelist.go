@@ -0,0 +1,187 @@
+package mal
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/request"
+)
+
+type AnimeListStatus string
+
+const (
+	AnimeListStatusAll         AnimeListStatus = "all"
+	AnimeListStatusWatching    AnimeListStatus = "watching"
+	AnimeListStatusCompleted   AnimeListStatus = "completed"
+	AnimeListStatusOnHold      AnimeListStatus = "on_hold"
+	AnimeListStatusDropped     AnimeListStatus = "dropped"
+	AnimeListStatusPlanToWatch AnimeListStatus = "plan_to_watch"
+)
+
+var animeListStatusByCode = map[string]AnimeListStatus{
+	"1": AnimeListStatusWatching,
+	"2": AnimeListStatusCompleted,
+	"3": AnimeListStatusOnHold,
+	"4": AnimeListStatusDropped,
+	"6": AnimeListStatusPlanToWatch,
+	"7": AnimeListStatusAll,
+}
+
+var animeListStatusName = map[AnimeListStatus]string{
+	AnimeListStatusAll:         "All Anime",
+	AnimeListStatusWatching:    "Currently Watching",
+	AnimeListStatusCompleted:   "Completed",
+	AnimeListStatusOnHold:      "On Hold",
+	AnimeListStatusDropped:     "Dropped",
+	AnimeListStatusPlanToWatch: "Plan to Watch",
+}
+
+// ParseAnimeListStatusCode parses the `status` query param used in
+// `https://myanimelist.net/animelist/{user_name}?status={code}`.
+func ParseAnimeListStatusCode(code string) (AnimeListStatus, bool) {
+	if code == "" {
+		return AnimeListStatusAll, true
+	}
+	status, ok := animeListStatusByCode[code]
+	return status, ok
+}
+
+func (s AnimeListStatus) Code() string {
+	for code, status := range animeListStatusByCode {
+		if status == s {
+			return code
+		}
+	}
+	return ""
+}
+
+func (s AnimeListStatus) IsValid() bool {
+	_, ok := animeListStatusName[s]
+	return ok
+}
+
+type MediaType string
+
+const (
+	MediaTypeUnknown   MediaType = "unknown"
+	MediaTypeTV        MediaType = "tv"
+	MediaTypeOVA       MediaType = "ova"
+	MediaTypeMovie     MediaType = "movie"
+	MediaTypeSpecial   MediaType = "special"
+	MediaTypeONA       MediaType = "ona"
+	MediaTypeMusic     MediaType = "music"
+	MediaTypeTVSpecial MediaType = "tv_special"
+)
+
+func (mt MediaType) ToSimple() string {
+	switch mt {
+	case MediaTypeTV, MediaTypeOVA, MediaTypeONA:
+		return "series"
+	case MediaTypeMovie, MediaTypeSpecial, MediaTypeTVSpecial:
+		return "movie"
+	default:
+		return ""
+	}
+}
+
+type AnimeNode struct {
+	Id          int    `json:"id"`
+	Title       string `json:"title"`
+	MainPicture struct {
+		Medium string `json:"medium"`
+		Large  string `json:"large"`
+	} `json:"main_picture"`
+	MediaType MediaType `json:"media_type"`
+	StartDate string    `json:"start_date"` // YYYY-MM-DD / YYYY-MM / YYYY
+	Synopsis  string    `json:"synopsis"`
+	Genres    []struct {
+		Id   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"genres"`
+	NSFW string `json:"nsfw"` // white / gray / black
+}
+
+func (n *AnimeNode) StartYear() int {
+	year, _, _ := strings.Cut(n.StartDate, "-")
+	y, _ := strconv.Atoi(year)
+	return y
+}
+
+func (n *AnimeNode) GenreNames() []string {
+	names := make([]string, len(n.Genres))
+	for i := range n.Genres {
+		names[i] = n.Genres[i].Name
+	}
+	return names
+}
+
+type AnimeListItem struct {
+	Node       AnimeNode `json:"node"`
+	ListStatus struct {
+		Status             AnimeListStatus `json:"status"`
+		Score              int             `json:"score"`
+		NumEpisodesWatched int             `json:"num_episodes_watched"`
+		UpdatedAt          time.Time       `json:"updated_at"`
+	} `json:"list_status"`
+}
+
+type GetUserAnimeListData struct {
+	ResponseError
+	Data   []AnimeListItem `json:"data"`
+	Paging struct {
+		Next string `json:"next,omitempty"`
+	} `json:"paging"`
+}
+
+type GetUserAnimeListParams struct {
+	Ctx
+	UserName string
+	Status   AnimeListStatus
+	Limit    int // max 1000
+	Offset   int
+}
+
+func (c APIClient) GetUserAnimeList(params *GetUserAnimeListParams) (request.APIResponse[GetUserAnimeListData], error) {
+	query := url.Values{}
+	if params.Status != "" && params.Status != AnimeListStatusAll {
+		query.Set("status", string(params.Status))
+	}
+	query.Set("sort", "list_updated_at")
+	query.Set("fields", "list_status,media_type,start_date,synopsis,genres,nsfw")
+	query.Set("nsfw", "true")
+	if params.Limit == 0 {
+		params.Limit = 1000
+	}
+	query.Set("limit", strconv.Itoa(params.Limit))
+	if params.Offset > 0 {
+		query.Set("offset", strconv.Itoa(params.Offset))
+	}
+	params.Query = &query
+
+	response := GetUserAnimeListData{}
+	res, err := c.Request("GET", "/users/"+url.PathEscape(params.UserName)+"/animelist", params, &response)
+	return request.NewAPIResponse(res, response), err
+}
+
+func FetchUserAnimeList(userName string, status AnimeListStatus) ([]AnimeListItem, error) {
+	items := []AnimeListItem{}
+	limit := 1000
+	for offset := 0; ; offset += limit {
+		res, err := client.GetUserAnimeList(&GetUserAnimeListParams{
+			UserName: userName,
+			Status:   status,
+			Limit:    limit,
+			Offset:   offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, res.Data.Data...)
+		if res.Data.Paging.Next == "" || len(res.Data.Data) < limit {
+			break
+		}
+	}
+	return items, nil
+}
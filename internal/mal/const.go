@@ -0,0 +1,51 @@
+package mal
+
+type Genre = string
+
+const (
+	GenreAction       Genre = "Action"
+	GenreAdventure    Genre = "Adventure"
+	GenreAvantGarde   Genre = "Avant Garde"
+	GenreAwardWinning Genre = "Award Winning"
+	GenreBoysLove     Genre = "Boys Love"
+	GenreComedy       Genre = "Comedy"
+	GenreDrama        Genre = "Drama"
+	GenreEcchi        Genre = "Ecchi"
+	GenreErotica      Genre = "Erotica"
+	GenreFantasy      Genre = "Fantasy"
+	GenreGirlsLove    Genre = "Girls Love"
+	GenreGourmet      Genre = "Gourmet"
+	GenreHentai       Genre = "Hentai"
+	GenreHorror       Genre = "Horror"
+	GenreMystery      Genre = "Mystery"
+	GenreRomance      Genre = "Romance"
+	GenreSciFi        Genre = "Sci-Fi"
+	GenreSliceOfLife  Genre = "Slice of Life"
+	GenreSports       Genre = "Sports"
+	GenreSupernatural Genre = "Supernatural"
+	GenreSuspense     Genre = "Suspense"
+)
+
+var Genres = []Genre{
+	GenreAction,
+	GenreAdventure,
+	GenreAvantGarde,
+	GenreAwardWinning,
+	GenreBoysLove,
+	GenreComedy,
+	GenreDrama,
+	GenreEcchi,
+	GenreErotica,
+	GenreFantasy,
+	GenreGirlsLove,
+	GenreGourmet,
+	GenreHentai,
+	GenreHorror,
+	GenreMystery,
+	GenreRomance,
+	GenreSciFi,
+	GenreSliceOfLife,
+	GenreSports,
+	GenreSupernatural,
+	GenreSuspense,
+}
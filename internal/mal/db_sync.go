@@ -0,0 +1,128 @@
+package mal
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/anime"
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/worker/worker_queue"
+)
+
+var listCache = cache.NewCache[MALList](&cache.CacheConfig{
+	Lifetime: 6 * time.Hour,
+	Name:     "mal:list",
+	MaxSize:  1024,
+})
+
+func ScheduleIdMapSync(items []MALItem) {
+	for i := range items {
+		worker_queue.AnimeIdMapperQueue.Queue(worker_queue.AnimeIdMapperQueueItem{
+			Service: anime.IdMapColumn.MAL,
+			Id:      strconv.Itoa(items[i].Id),
+		})
+	}
+}
+
+var syncListMutex sync.Mutex
+
+// toListItems skips the duplicate items.
+func toListItems(items []AnimeListItem) []MALItem {
+	listItems := make([]MALItem, 0, len(items))
+	seenMap := map[int]struct{}{}
+	for i := range items {
+		node := &items[i].Node
+		if _, seen := seenMap[node.Id]; seen {
+			continue
+		}
+		seenMap[node.Id] = struct{}{}
+
+		poster := node.MainPicture.Large
+		if poster == "" {
+			poster = node.MainPicture.Medium
+		}
+		listItems = append(listItems, MALItem{
+			Id:          node.Id,
+			Type:        node.MediaType,
+			Title:       node.Title,
+			Description: node.Synopsis,
+			Poster:      poster,
+			StartYear:   node.StartYear(),
+			IsAdult:     node.NSFW == "black",
+			Genres:      node.GenreNames(),
+
+			Idx: i,
+		})
+	}
+	return listItems
+}
+
+func syncList(l *MALList) error {
+	syncListMutex.Lock()
+	defer syncListMutex.Unlock()
+
+	log.Debug("fetching list items", "id", l.Id)
+	items, err := FetchUserAnimeList(l.UserName, l.Status)
+	if err != nil {
+		return err
+	}
+
+	l.Items = toListItems(items)
+
+	if err := UpsertList(l); err != nil {
+		return err
+	}
+
+	if err := listCache.Add(l.Id, *l); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (l *MALList) Fetch() error {
+	if l.Id == "" {
+		return errors.New("id must be provided")
+	}
+
+	userName, status, err := ParseListId(l.Id)
+	if err != nil {
+		return err
+	}
+
+	isMissing := false
+
+	var cachedL MALList
+	if !listCache.Get(l.Id, &cachedL) {
+		if list, err := GetListById(l.Id); err != nil {
+			return err
+		} else if list == nil {
+			isMissing = true
+		} else {
+			*l = *list
+			log.Debug("found list by id", "id", l.Id, "is_stale", l.IsStale())
+			listCache.Add(l.Id, *l)
+		}
+	} else {
+		*l = cachedL
+	}
+
+	if isMissing {
+		l.UserName = userName
+		l.Status = status
+		return syncList(l)
+	}
+
+	if l.IsStale() {
+		staleList := *l
+		go func() {
+			if err := syncList(&staleList); err != nil {
+				log.Error("failed to sync stale list", "id", l.Id, "error", err)
+			}
+		}()
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package mal
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToListItems(t *testing.T) {
+	var data GetUserAnimeListData
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"data": [
+			{"node": {"id": 21, "title": "One Piece", "media_type": "tv", "start_date": "1999-10-20", "synopsis": "Pirates.", "nsfw": "white",
+				"main_picture": {"medium": "https://cdn.myanimelist.net/images/anime/6/73245.jpg", "large": "https://cdn.myanimelist.net/images/anime/6/73245l.jpg"},
+				"genres": [{"id": 1, "name": "Action"}, {"id": 2, "name": "Adventure"}]}},
+			{"node": {"id": 199, "title": "Spirited Away", "media_type": "movie", "start_date": "2001-07",
+				"main_picture": {"medium": "https://cdn.myanimelist.net/images/anime/6/179.jpg"}}},
+			{"node": {"id": 21, "title": "One Piece", "media_type": "tv"}},
+			{"node": {"id": 300, "title": "Adult", "media_type": "ova", "nsfw": "black"}}
+		]
+	}`), &data))
+
+	items := toListItems(data.Data)
+	assert.Equal(t, []MALItem{
+		{
+			Id:          21,
+			Type:        MediaTypeTV,
+			Title:       "One Piece",
+			Description: "Pirates.",
+			Poster:      "https://cdn.myanimelist.net/images/anime/6/73245l.jpg",
+			StartYear:   1999,
+			Genres:      []string{"Action", "Adventure"},
+			Idx:         0,
+		},
+		{
+			Id:        199,
+			Type:      MediaTypeMovie,
+			Title:     "Spirited Away",
+			Poster:    "https://cdn.myanimelist.net/images/anime/6/179.jpg",
+			StartYear: 2001,
+			Genres:    []string{},
+			Idx:       1,
+		},
+		{
+			Id:      300,
+			Type:    MediaTypeOVA,
+			Title:   "Adult",
+			IsAdult: true,
+			Genres:  []string{},
+			Idx:     3,
+		},
+	}, items)
+}
+
+func TestMediaTypeToSimple(t *testing.T) {
+	for mediaType, expected := range map[MediaType]string{
+		MediaTypeTV:        "series",
+		MediaTypeOVA:       "series",
+		MediaTypeONA:       "series",
+		MediaTypeMovie:     "movie",
+		MediaTypeSpecial:   "movie",
+		MediaTypeTVSpecial: "movie",
+		MediaTypeMusic:     "",
+		MediaTypeUnknown:   "",
+		"":                 "",
+	} {
+		assert.Equal(t, expected, mediaType.ToSimple(), "media type %q", mediaType)
+	}
+}
@@ -0,0 +1,5 @@
+package mal
+
+import "github.com/MunifTanjim/stremthru/internal/logger"
+
+var log = logger.Scoped("mal")
@@ -0,0 +1,7 @@
+package mal
+
+import "github.com/MunifTanjim/stremthru/internal/config"
+
+var client = NewAPIClient(&APIClientConfig{
+	ClientId: config.Integration.MAL.ClientId,
+})
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type storeTransport struct {
+	storeName string
+	base      http.RoundTripper
+}
+
+func (t *storeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.base.RoundTrip(req)
+	duration := time.Since(start)
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(res.StatusCode)
+	}
+	storeRequestDuration.WithLabelValues(t.storeName, req.Method, status).Observe(duration.Seconds())
+	if err != nil || res.StatusCode >= 400 {
+		storeRequestErrors.WithLabelValues(t.storeName).Inc()
+	}
+	return res, err
+}
+
+// InstrumentStoreHTTPClient returns a copy of the client that records the
+// upstream requests for the store. The original client is left untouched,
+// because it is shared across stores.
+func InstrumentStoreHTTPClient(client *http.Client, storeName string) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c := *client
+	c.Transport = &storeTransport{storeName: storeName, base: base}
+	return &c
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "stremthru"
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests, by endpoint pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "endpoint", "status"})
+
+	storeRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "store",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of upstream store API requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"store", "method", "status"})
+
+	storeRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "store",
+		Name:      "request_errors_total",
+		Help:      "Upstream store API requests that failed or responded with error status.",
+	}, []string{"store"})
+
+	usenetSegmentCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "usenet",
+		Name:      "segment_cache_requests_total",
+		Help:      "Usenet segment cache lookups, by result.",
+	}, []string{"result"})
+
+	workerRunDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "run_duration_seconds",
+		Help:      "Duration of worker and job runs.",
+		Buckets:   []float64{1, 5, 15, 30, 60, 300, 900, 1800, 3600, 3 * 3600, 6 * 3600},
+	}, []string{"name", "status"})
+
+	contentProxyConnections = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "content_proxy",
+		Name:      "connections",
+		Help:      "Active content proxy connections.",
+	}, []string{"user"})
+
+	contentProxyBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "content_proxy",
+		Name:      "bytes_total",
+		Help:      "Bytes served by content proxy.",
+	}, []string{"user"})
+
+	contentProxyChunkCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "content_proxy",
+		Name:      "chunk_cache_requests_total",
+		Help:      "Content proxy chunk cache lookups, by result.",
+	}, []string{"result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		storeRequestDuration,
+		storeRequestErrors,
+		usenetSegmentCacheRequests,
+		workerRunDuration,
+		contentProxyConnections,
+		contentProxyBytes,
+		contentProxyChunkCacheRequests,
+	)
+}
+
+func MustRegister(cs ...prometheus.Collector) {
+	registry.MustRegister(cs...)
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+	})
+}
+
+func ObserveHTTPRequest(method, endpoint string, status int, duration time.Duration) {
+	if endpoint == "" {
+		endpoint = "unmatched"
+	}
+	httpRequestDuration.WithLabelValues(method, endpoint, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+func ObserveWorkerRun(name string, err error, duration time.Duration) {
+	status := "done"
+	if err != nil {
+		status = "failed"
+	}
+	workerRunDuration.WithLabelValues(name, status).Observe(duration.Seconds())
+}
+
+func RecordSegmentCacheLookup(hit bool) {
+	if hit {
+		usenetSegmentCacheRequests.WithLabelValues("hit").Inc()
+	} else {
+		usenetSegmentCacheRequests.WithLabelValues("miss").Inc()
+	}
+}
+
+func RecordContentProxyChunkCacheLookup(hit bool) {
+	if hit {
+		contentProxyChunkCacheRequests.WithLabelValues("hit").Inc()
+	} else {
+		contentProxyChunkCacheRequests.WithLabelValues("miss").Inc()
+	}
+}
+
+// TrackContentProxyConnection marks the connection as active, the returned
+// func should be called with the bytes written once it is closed.
+func TrackContentProxyConnection(user string) (done func(bytesWritten int64)) {
+	gauge := contentProxyConnections.WithLabelValues(user)
+	gauge.Inc()
+	return func(bytesWritten int64) {
+		gauge.Dec()
+		contentProxyBytes.WithLabelValues(user).Add(float64(bytesWritten))
+	}
+}
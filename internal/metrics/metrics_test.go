@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentStoreHTTPClient(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	original := &http.Client{}
+	client := InstrumentStoreHTTPClient(original, "teststore")
+	assert.Nil(t, original.Transport)
+
+	for _, path := range []string{"/ok", "/fail"} {
+		res, err := client.Get(upstream.URL + path)
+		require.NoError(t, err)
+		res.Body.Close()
+	}
+
+	assert.Equal(t, 2, testutil.CollectAndCount(storeRequestDuration, "stremthru_store_request_duration_seconds"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(storeRequestErrors.WithLabelValues("teststore")))
+}
+
+func TestHandler(t *testing.T) {
+	ObserveHTTPRequest(http.MethodGet, "/v0/health", 200, 10*time.Millisecond)
+	ObserveWorkerRun("test-worker", errors.New("failed"), time.Second)
+	RecordSegmentCacheLookup(true)
+	RecordSegmentCacheLookup(false)
+	RecordSegmentCacheLookup(false)
+	RecordContentProxyChunkCacheLookup(true)
+
+	done := TrackContentProxyConnection("user")
+	assert.Equal(t, 1.0, testutil.ToFloat64(contentProxyConnections.WithLabelValues("user")))
+	done(1024)
+	assert.Equal(t, 0.0, testutil.ToFloat64(contentProxyConnections.WithLabelValues("user")))
+	assert.Equal(t, 1024.0, testutil.ToFloat64(contentProxyBytes.WithLabelValues("user")))
+
+	req := httptest.NewRequest(http.MethodGet, "/v0/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "application/openmetrics-text"))
+
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+	output := string(body)
+	assert.Contains(t, output, `stremthru_http_request_duration_seconds_count{endpoint="/v0/health",method="GET",status="200"} 1`)
+	assert.Contains(t, output, `stremthru_worker_run_duration_seconds_count{name="test-worker",status="failed"} 1`)
+	assert.Contains(t, output, `stremthru_usenet_segment_cache_requests_total{result="hit"} 1`)
+	assert.Contains(t, output, `stremthru_usenet_segment_cache_requests_total{result="miss"} 2`)
+	assert.True(t, strings.HasSuffix(output, "# EOF\n"))
+}
@@ -32,6 +32,7 @@ const (
 	SearchParamSeason   = znab.SearchParamSeason
 	SearchParamYear     = znab.SearchParamYear
 	SearchParamIMDBId   = znab.SearchParamIMDBId
+	SearchParamTMDBId   = znab.SearchParamTMDBId
 	SearchParamTVDBId   = znab.SearchParamTVDBId
 	SearchParamTVMazeId = znab.SearchParamTVMazeId
 	SearchParamTraktId  = znab.SearchParamTraktId
@@ -2,6 +2,8 @@ package nntp
 
 import (
 	"context"
+	"io"
+	"net/textproto"
 	"time"
 )
 
@@ -14,6 +16,7 @@ type Client struct {
 
 	tls           bool
 	tlsSkipVerify bool
+	compression   bool
 
 	dialTimeout   time.Duration
 	keepAliveTime time.Duration
@@ -50,6 +53,7 @@ func NewClient(conf *ClientConfig) *Client {
 
 		tls:           conf.TLS,
 		tlsSkipVerify: conf.TLSSkipVerify,
+		compression:   conf.Compression,
 
 		dialTimeout:   conf.DialTimeout,
 		keepAliveTime: conf.KeepAliveTime,
@@ -70,6 +74,7 @@ func (c *Client) ConnectContext(ctx context.Context) error {
 		Password:      c.password,
 		TLS:           c.tls,
 		TLSSkipVerify: c.tlsSkipVerify,
+		Compression:   c.compression,
 		DialTimeout:   c.dialTimeout,
 		KeepAliveTime: c.keepAliveTime,
 	})
@@ -79,6 +84,10 @@ func (c *Client) Close() error {
 	return c.conn.Close()
 }
 
+func (c *Client) Compression() Compression {
+	return c.conn.Compression()
+}
+
 func (c *Client) Authenticate(username, password string) error {
 	return c.conn.Authenticate(username, password)
 }
@@ -142,3 +151,11 @@ func (c *Client) Next() (int64, string, error) {
 func (c *Client) Last() (int64, string, error) {
 	return c.conn.Last()
 }
+
+func (c *Client) Post(headers textproto.MIMEHeader, body io.Reader) error {
+	return c.conn.Post(headers, body)
+}
+
+func (c *Client) IHave(messageId string, headers textproto.MIMEHeader, body io.Reader) error {
+	return c.conn.IHave(messageId, headers, body)
+}
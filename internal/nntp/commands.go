@@ -16,6 +16,7 @@ const (
 	CommandArticle      Command = "ARTICLE"
 	CommandBody         Command = "BODY"
 	CommandCapabilities Command = "CAPABILITIES"
+	CommandCompress     Command = "COMPRESS"
 	CommandDate         Command = "DATE"
 	CommandGroup        Command = "GROUP"
 	CommandHDR          Command = "HDR"
@@ -33,6 +34,7 @@ const (
 	CommandPost         Command = "POST"
 	CommandQuit         Command = "QUIT"
 	CommandStat         Command = "STAT"
+	CommandXFeature     Command = "XFEATURE"
 )
 
 func (c Command) String() string {
@@ -80,8 +82,9 @@ type BodyReader interface {
 }
 
 type bodyReadCloser struct {
-	textproto.Reader
+	*textproto.Reader
 	r      *CmdResult
+	done   func() error
 	closed bool
 }
 
@@ -98,8 +101,9 @@ func (r *bodyReadCloser) Close() error {
 		return nil
 	}
 	r.closed = true
+	err := r.done()
 	r.r.c.conn.EndResponse(r.r.id)
-	return nil
+	return err
 }
 
 func (r *CmdResult) readCodeLine(expectCode int) (code int, line string, err error) {
@@ -118,10 +122,18 @@ func (r *CmdResult) readCodeLineAndDotLines(expectCode int) (code int, message s
 		return code, message, nil, err
 	}
 
-	lines, err = r.c.conn.ReadDotLines()
+	reader, done, err := r.multiLineReader()
+	if err != nil {
+		return code, message, nil, err
+	}
+
+	lines, err = reader.ReadDotLines()
 	if err != nil {
 		return code, message, nil, err
 	}
+	if err := done(); err != nil {
+		return code, message, nil, err
+	}
 	return code, message, lines, err
 }
 
@@ -134,15 +146,22 @@ func (r *CmdResult) readCodeLineAndHeadersAndBody(expectCode int) (code int, mes
 		return code, message, nil, nil, err
 	}
 
-	headers, err = r.c.conn.ReadMIMEHeader()
+	reader, done, err := r.multiLineReader()
+	if err != nil {
+		r.c.conn.EndResponse(r.id)
+		return code, message, nil, nil, err
+	}
+
+	headers, err = reader.ReadMIMEHeader()
 	if err != nil {
 		r.c.conn.EndResponse(r.id)
 		return code, message, nil, nil, err
 	}
 
 	body = &bodyReadCloser{
-		Reader: r.c.conn.Reader,
+		Reader: reader,
 		r:      r,
+		done:   done,
 	}
 	return code, message, headers, body, err
 }
@@ -156,12 +175,20 @@ func (r *CmdResult) readCodeLineAndHeaders(expectCode int) (code int, message st
 		return code, message, nil, err
 	}
 
+	reader, done, err := r.multiLineReader()
+	if err != nil {
+		return code, message, nil, err
+	}
+
 	headers, err = textproto.NewReader(bufio.NewReader(
-		io.MultiReader(r.c.conn.DotReader(), bytes.NewReader([]byte{'\r', '\n'})),
+		io.MultiReader(reader.DotReader(), bytes.NewReader([]byte{'\r', '\n'})),
 	)).ReadMIMEHeader()
 	if err != nil {
 		return code, message, nil, err
 	}
+	if err := done(); err != nil {
+		return code, message, nil, err
+	}
 	return code, message, headers, nil
 }
 
@@ -174,9 +201,16 @@ func (r *CmdResult) readCodeLineAndBody(expectCode int) (code int, message strin
 		return code, message, nil, err
 	}
 
+	reader, done, err := r.multiLineReader()
+	if err != nil {
+		r.c.conn.EndResponse(r.id)
+		return code, message, nil, err
+	}
+
 	body = &bodyReadCloser{
-		Reader: r.c.conn.Reader,
+		Reader: reader,
 		r:      r,
+		done:   done,
 	}
 	return code, message, body, err
 }
@@ -719,3 +753,88 @@ func (c *Connection) Last() (int64, string, error) {
 
 	return parseLastResponseMessage(message)
 }
+
+// writes the article in response to 340/335, and reads the final status line
+func (r *CmdResult) writeArticleAndReadCodeLine(headers textproto.MIMEHeader, body io.Reader, expectCode int) (code int, message string, err error) {
+	id := r.c.conn.Next()
+	r.c.conn.StartRequest(id)
+	w := r.c.conn.DotWriter()
+	if err = writeArticle(w, headers, body); err != nil {
+		w.Close()
+		r.c.conn.EndRequest(id)
+		return 0, "", err
+	}
+	err = w.Close()
+	r.c.conn.EndRequest(id)
+	if err != nil {
+		return 0, "", err
+	}
+
+	r.c.conn.StartResponse(id)
+	defer r.c.conn.EndResponse(id)
+
+	return r.c.conn.ReadCodeLine(expectCode)
+}
+
+// Reference: RFC 3977 Section 6.3.1 (POST)
+// https://tools.ietf.org/html/rfc3977#section-6.3.1
+func (c *Connection) Post(headers textproto.MIMEHeader, body io.Reader) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+
+	if err := validateHeaders(headers); err != nil {
+		return err
+	}
+
+	r := c.cmd(CommandPost.String())
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	code, message, err := r.readCodeLine(StatusSendArticleToPost)
+	if err != nil {
+		return NewCommandError(r.cmd, code, message).WithCause(err)
+	}
+
+	code, message, err = r.writeArticleAndReadCodeLine(headers, body, StatusArticleReceivedOK)
+	if err != nil {
+		return NewCommandError(r.cmd, code, message).WithCause(err)
+	}
+
+	return nil
+}
+
+// The `messageId` parameter must be enclosed in angle brackets.
+//
+// Reference: RFC 3977 Section 6.3.2 (IHAVE)
+// https://tools.ietf.org/html/rfc3977#section-6.3.2
+func (c *Connection) IHave(messageId string, headers textproto.MIMEHeader, body io.Reader) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+
+	if err := validateInput(messageId); err != nil {
+		return err
+	}
+	if err := validateHeaders(headers); err != nil {
+		return err
+	}
+
+	r := c.cmd(CommandIHave.String(), messageId)
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	code, message, err := r.readCodeLine(StatusSendArticleToTransfer)
+	if err != nil {
+		return NewCommandError(r.cmd, code, message).WithCause(err)
+	}
+
+	code, message, err = r.writeArticleAndReadCodeLine(headers, body, StatusArticleTransferredOK)
+	if err != nil {
+		return NewCommandError(r.cmd, code, message).WithCause(err)
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package nntp_test
 
 import (
+	"net/textproto"
+	"strings"
 	"testing"
 	"time"
 
@@ -1239,3 +1241,184 @@ func TestLast_NoCurrentArticle(t *testing.T) {
 	_, _, err = client.Last()
 	assert.Error(t, err, "Last()")
 }
+
+func newTestArticleHeaders(messageId string) textproto.MIMEHeader {
+	headers := textproto.MIMEHeader{}
+	headers.Set("From", "\"Demo User\" <nobody@example.net>")
+	headers.Set("Newsgroups", "misc.test")
+	headers.Set("Subject", "I am just a test article")
+	headers.Set("Message-ID", messageId)
+	return headers
+}
+
+// TestPost uses the example from RFC 3977 Section 6.3.1
+func TestPost(t *testing.T) {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host: server.Host(),
+		Port: server.Port(),
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	err = client.Post(newTestArticleHeaders("<i.am.a.new.article@example.com>"), strings.NewReader("This is just a test article.\r\n.starts with a dot\r\n"))
+	assert.NoError(t, err, "Post()")
+
+	articles := server.GetArticles()
+	assert.Len(t, articles, 1, "articles")
+	assert.Equal(t, "<i.am.a.new.article@example.com>", articles[0].MessageId, "article.MessageId")
+	assert.Equal(t, "misc.test", articles[0].Headers.Get("Newsgroups"), "article.Headers.Newsgroups")
+	assert.Equal(t, "This is just a test article.\n.starts with a dot\n", string(articles[0].Body), "article.Body")
+
+	article, err := client.Body("<i.am.a.new.article@example.com>")
+	assert.NoError(t, err, "Body()")
+	content, err := article.Body.ReadAll()
+	assert.NoError(t, err, "ReadAll()")
+	assert.Equal(t, "This is just a test article.\n.starts with a dot\n", string(content), "body content")
+}
+
+func TestPost_PostingNotPermitted(t *testing.T) {
+	server := nntptest.NewServer(t, "201 NNTP Service Ready, posting prohibited")
+	server.SetResponse("POST", "440 Posting not permitted")
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host: server.Host(),
+		Port: server.Port(),
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	err = client.Post(newTestArticleHeaders("<i.am.a.new.article@example.com>"), strings.NewReader("This is just a test article.\r\n"))
+	assert.Error(t, err, "Post()")
+
+	nntpErr, ok := err.(*Error)
+	assert.True(t, ok, "error type should be *Error")
+	assert.Equal(t, ErrorCodePostingFailed, nntpErr.Code, "error code")
+	assert.Equal(t, StatusPostingNotPermitted, nntpErr.StatusCode, "status code")
+	assert.Empty(t, server.GetArticles(), "articles")
+}
+
+func TestPost_PostingFailed(t *testing.T) {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.SetArticleResponse("POST", "441 Posting failed")
+	server.SetResponse("CAPABILITIES", "101 Capability list:", []string{"VERSION 2"})
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host: server.Host(),
+		Port: server.Port(),
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	err = client.Post(newTestArticleHeaders("<i.am.a.new.article@example.com>"), strings.NewReader("This is just a test article.\r\n"))
+	assert.Error(t, err, "Post()")
+
+	nntpErr, ok := err.(*Error)
+	assert.True(t, ok, "error type should be *Error")
+	assert.Equal(t, ErrorCodePostingFailed, nntpErr.Code, "error code")
+	assert.Equal(t, StatusPostingFailed, nntpErr.StatusCode, "status code")
+
+	// connection is still usable
+	_, err = client.Capabilities()
+	assert.NoError(t, err, "Capabilities()")
+}
+
+func TestPost_InvalidHeader(t *testing.T) {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host: server.Host(),
+		Port: server.Port(),
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	headers := newTestArticleHeaders("<i.am.a.new.article@example.com>")
+	headers.Set("Subject", "injected\r\nQUIT")
+	err = client.Post(headers, strings.NewReader("This is just a test article.\r\n"))
+	assert.Error(t, err, "Post()")
+	assert.False(t, server.GetRequestCommands().HasCommand("POST"), "POST should not be sent")
+}
+
+// TestIHave uses the example from RFC 3977 Section 6.3.2
+func TestIHave(t *testing.T) {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host: server.Host(),
+		Port: server.Port(),
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	err = client.IHave("<i.am.an.article.you.will.want@example.com>", newTestArticleHeaders("<i.am.an.article.you.will.want@example.com>"), strings.NewReader("This is just a test article.\r\n"))
+	assert.NoError(t, err, "IHave()")
+	assert.True(t, server.GetRequestCommands().HasCommand("IHAVE <i.am.an.article.you.will.want@example.com>"), "IHAVE command")
+
+	articles := server.GetArticles()
+	assert.Len(t, articles, 1, "articles")
+	assert.Equal(t, "<i.am.an.article.you.will.want@example.com>", articles[0].MessageId, "article.MessageId")
+}
+
+func TestIHave_NotWanted(t *testing.T) {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.SetResponse("IHAVE <i.am.an.article.you.have@example.com>", "435 Duplicate")
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host: server.Host(),
+		Port: server.Port(),
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	err = client.IHave("<i.am.an.article.you.have@example.com>", newTestArticleHeaders("<i.am.an.article.you.have@example.com>"), strings.NewReader("This is just a test article.\r\n"))
+	assert.Error(t, err, "IHave()")
+
+	nntpErr, ok := err.(*Error)
+	assert.True(t, ok, "error type should be *Error")
+	assert.Equal(t, ErrorCodeTransferFailed, nntpErr.Code, "error code")
+	assert.Equal(t, StatusArticleNotWanted, nntpErr.StatusCode, "status code")
+}
+
+func TestIHave_TransferRejected(t *testing.T) {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.SetArticleResponse("IHAVE *", "437 Rejected")
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host: server.Host(),
+		Port: server.Port(),
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	err = client.IHave("<i.am.an.article.you.defer@example.com>", newTestArticleHeaders("<i.am.an.article.you.defer@example.com>"), strings.NewReader("This is just a test article.\r\n"))
+	assert.Error(t, err, "IHave()")
+
+	nntpErr, ok := err.(*Error)
+	assert.True(t, ok, "error type should be *Error")
+	assert.Equal(t, ErrorCodeTransferFailed, nntpErr.Code, "error code")
+	assert.Equal(t, StatusTransferRejected, nntpErr.StatusCode, "status code")
+	assert.Empty(t, server.GetArticles(), "articles")
+}
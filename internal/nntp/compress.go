@@ -0,0 +1,230 @@
+package nntp
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/textproto"
+	"slices"
+	"strings"
+)
+
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	// RFC 8054, everything after the command is compressed in both directions
+	CompressionDeflate Compression = "deflate"
+	// XFEATURE COMPRESS GZIP, only the multi-line responses are compressed
+	CompressionGzip Compression = "gzip"
+)
+
+func (c Compression) String() string {
+	return string(c)
+}
+
+func (caps *Capabilities) HasCompression(algorithm string) bool {
+	for _, capability := range caps.Capabilities {
+		fields := strings.Fields(capability)
+		if len(fields) > 1 && strings.EqualFold(fields[0], "COMPRESS") && slices.ContainsFunc(fields[1:], func(field string) bool {
+			return strings.EqualFold(field, algorithm)
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// deflateConn wraps the connection after `COMPRESS DEFLATE` is accepted.
+type deflateConn struct {
+	r  io.ReadCloser
+	w  *flate.Writer
+	bw *bufio.Writer
+	c  io.Closer
+}
+
+func newDeflateConn(conn *textproto.Conn) (*deflateConn, error) {
+	w, err := flate.NewWriter(conn.W, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	return &deflateConn{
+		// conn.R is an io.ByteReader, so flate does not read past the stream
+		r:  flate.NewReader(conn.R),
+		w:  w,
+		bw: conn.W,
+		c:  conn,
+	}, nil
+}
+
+func (dc *deflateConn) Read(p []byte) (int, error) {
+	return dc.r.Read(p)
+}
+
+// Write flushes the compressed data, every command needs to reach the server
+// without waiting for more input.
+func (dc *deflateConn) Write(p []byte) (int, error) {
+	n, err := dc.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	if err := dc.w.Flush(); err != nil {
+		return n, err
+	}
+	return n, dc.bw.Flush()
+}
+
+func (dc *deflateConn) Close() error {
+	return errors.Join(dc.r.Close(), dc.c.Close())
+}
+
+// Reference: RFC 8054 Section 2.2 (COMPRESS)
+// https://tools.ietf.org/html/rfc8054#section-2.2
+func (c *Connection) CompressDeflate() error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+
+	if c.compression != CompressionNone {
+		return NewProtocolError(0, "compression already active")
+	}
+
+	r := c.cmd(CommandCompress.String(), "DEFLATE")
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	code, message, err := r.readCodeLine(StatusCompressionActive)
+	if err != nil {
+		return NewCommandError(r.cmd, code, message).WithCause(err)
+	}
+
+	dc, err := newDeflateConn(c.conn)
+	if err != nil {
+		return NewConnectionError("failed to start compression").WithCause(err)
+	}
+	c.conn = textproto.NewConn(dc)
+	c.compression = CompressionDeflate
+
+	return nil
+}
+
+// XFEATURE COMPRESS GZIP is not standardized. Despite the name, the multi-line
+// responses are sent as zlib stream, which contains the dot-terminated data.
+func (c *Connection) XFeatureCompressGzip() error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+
+	if c.compression != CompressionNone {
+		return NewProtocolError(0, "compression already active")
+	}
+
+	r := c.cmd(CommandXFeature.String(), "COMPRESS GZIP")
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	code, message, err := r.readCodeLine(StatusFeatureEnabled)
+	if err != nil {
+		return NewCommandError(r.cmd, code, message).WithCause(err)
+	}
+
+	c.compression = CompressionGzip
+
+	return nil
+}
+
+func (c *Connection) Compression() Compression {
+	return c.compression
+}
+
+// isRefusedError reports if the server responded with an error status, and
+// the connection itself is still usable.
+func isRefusedError(err error) bool {
+	var nntpErr *Error
+	if !errors.As(err, &nntpErr) {
+		return false
+	}
+	return nntpErr.StatusCode >= 400 && nntpErr.StatusCode != StatusServiceTemporarilyUnavailable
+}
+
+// negotiateCompression prefers `COMPRESS DEFLATE`, and falls back to
+// `XFEATURE COMPRESS GZIP`. If the server refuses both, the connection is
+// used without compression.
+func (c *Connection) negotiateCompression() error {
+	caps, err := c.Capabilities()
+	if err != nil && !isRefusedError(err) {
+		return err
+	}
+
+	if caps != nil && caps.HasCompression("DEFLATE") {
+		err := c.CompressDeflate()
+		if err == nil || !isRefusedError(err) {
+			return err
+		}
+	}
+
+	if err := c.XFeatureCompressGzip(); err != nil && !isRefusedError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func isZlibHeader(b []byte) bool {
+	return len(b) == 2 && b[0]&0x0f == 8 && b[0]>>4 <= 7 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
+
+// max bytes inflated to probe for zlib stream
+const maxZlibProbeSize = 4096
+
+// isZlibStream reports if `b` is the start of a zlib stream. Uncompressed data
+// can also start with a valid zlib header (e.g. `x^`), so `b` must inflate
+// without error.
+func isZlibStream(b []byte) bool {
+	if len(b) < 2 || !isZlibHeader(b[:2]) {
+		return false
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	_, err = io.Copy(io.Discard, zr)
+	return err == nil || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// multiLineReader returns the reader for the data block of multi-line
+// response. With `XFEATURE COMPRESS GZIP` the block can be compressed, in
+// that case `done` consumes the rest of the zlib stream.
+func (r *CmdResult) multiLineReader() (reader *textproto.Reader, done func() error, err error) {
+	noop := func() error { return nil }
+
+	if r.c.compression != CompressionGzip {
+		return &r.c.conn.Reader, noop, nil
+	}
+
+	// servers may skip compression for some responses
+	if _, err := r.c.conn.R.Peek(2); err != nil {
+		return &r.c.conn.Reader, noop, nil
+	}
+	peeked, _ := r.c.conn.R.Peek(max(2, min(r.c.conn.R.Buffered(), maxZlibProbeSize)))
+	if !isZlibStream(peeked) {
+		return &r.c.conn.Reader, noop, nil
+	}
+
+	zr, err := zlib.NewReader(r.c.conn.R)
+	if err != nil {
+		return nil, noop, err
+	}
+
+	reader = textproto.NewReader(bufio.NewReader(zr))
+	done = func() error {
+		_, err := io.Copy(io.Discard, zr)
+		return errors.Join(err, zr.Close())
+	}
+	return reader, done, nil
+}
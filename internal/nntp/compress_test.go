@@ -0,0 +1,189 @@
+package nntp_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/MunifTanjim/stremthru/internal/nntp"
+	"github.com/MunifTanjim/stremthru/internal/nntp/nntptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCompressionTestServer(t *testing.T, capabilities ...string) *nntptest.Server {
+	server := nntptest.NewServer(t, "200 NNTP Service Ready")
+	server.SetResponse("CAPABILITIES", "101 Capability list:", append([]string{"VERSION 2", "READER"}, capabilities...))
+	server.SetResponse("OVER 3000234-3000235", "224 Overview information follows", []string{
+		"3000234\tI am just a test article\t\"Demo User\" <nobody@example.com>\t6 Oct 1998 04:38:40 -0500\t<45223423@example.com>\t<45454@example.net>\t1234\t17",
+		"3000235\tAnother test article\tnobody@nowhere.to (Demo User)\t6 Oct 1998 04:38:45 -0500\t<45223425@to.to>\t\t4818\t37",
+	})
+	server.SetResponse("HEAD <45223423@example.com>", "221 0 <45223423@example.com>", []string{
+		"From: \"Demo User\" <nobody@example.net>",
+		"Subject: I am just a test article",
+		"Message-ID: <45223423@example.com>",
+	})
+	server.SetResponse("BODY <45223423@example.com>", "222 0 <45223423@example.com>", []string{
+		"This is just a test article.",
+		"..with a leading dot",
+	})
+	return server
+}
+
+func assertCompressedSession(t *testing.T, client *Client) {
+	t.Helper()
+
+	overviews, err := client.Over("3000234-3000235")
+	assert.NoError(t, err, "Over()")
+	assert.Len(t, overviews, 2, "overviews")
+
+	article, err := client.Head("<45223423@example.com>")
+	assert.NoError(t, err, "Head()")
+	assert.Equal(t, "I am just a test article", article.Headers.Get("Subject"), "article.Headers.Subject")
+
+	article, err = client.Body("<45223423@example.com>")
+	assert.NoError(t, err, "Body()")
+	content, err := article.Body.ReadAll()
+	assert.NoError(t, err, "ReadAll()")
+	assert.Equal(t, "This is just a test article.\n.with a leading dot\n", string(content), "body content")
+	assert.NoError(t, article.Body.Close(), "Body.Close()")
+
+	err = client.Post(newTestArticleHeaders("<i.am.a.new.article@example.com>"), strings.NewReader("This is just a test article.\r\n"))
+	assert.NoError(t, err, "Post()")
+
+	// connection is still in sync after the multi-line responses
+	overviews, err = client.Over("3000234-3000235")
+	assert.NoError(t, err, "Over()")
+	assert.Len(t, overviews, 2, "overviews")
+}
+
+func TestCompression_Deflate(t *testing.T) {
+	server := newCompressionTestServer(t, "COMPRESS DEFLATE")
+	server.SetCompression(CompressionDeflate)
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host:        server.Host(),
+		Port:        server.Port(),
+		Compression: true,
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	assert.Equal(t, CompressionDeflate, client.Compression(), "compression")
+	assert.True(t, server.GetRequestCommands().HasCommand("COMPRESS DEFLATE"), "COMPRESS command")
+	assert.False(t, server.GetRequestCommands().HasCommand("XFEATURE COMPRESS GZIP"), "XFEATURE command")
+
+	assertCompressedSession(t, client)
+	assert.Len(t, server.GetArticles(), 1, "articles")
+}
+
+func TestCompression_Gzip(t *testing.T) {
+	server := newCompressionTestServer(t)
+	server.SetCompression(CompressionGzip)
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host:        server.Host(),
+		Port:        server.Port(),
+		Compression: true,
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	assert.Equal(t, CompressionGzip, client.Compression(), "compression")
+	assert.False(t, server.GetRequestCommands().HasCommand("COMPRESS DEFLATE"), "COMPRESS command")
+
+	assertCompressedSession(t, client)
+}
+
+func TestCompression_GzipUncompressedResponse(t *testing.T) {
+	server := newCompressionTestServer(t)
+	server.SetCompression(CompressionGzip)
+	// starts with `x^`, which is a valid zlib header
+	server.SetUncompressedResponse("BODY <x@example.com>", "222 0 <x@example.com>", []string{
+		"x^ plain text body",
+	})
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host:        server.Host(),
+		Port:        server.Port(),
+		Compression: true,
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	assert.Equal(t, CompressionGzip, client.Compression(), "compression")
+
+	article, err := client.Body("<x@example.com>")
+	assert.NoError(t, err, "Body()")
+	content, err := article.Body.ReadAll()
+	assert.NoError(t, err, "ReadAll()")
+	assert.Equal(t, "x^ plain text body\n", string(content), "body content")
+	assert.NoError(t, article.Body.Close(), "Body.Close()")
+
+	assertCompressedSession(t, client)
+}
+
+func TestCompression_FallbackToGzip(t *testing.T) {
+	server := newCompressionTestServer(t, "COMPRESS DEFLATE")
+	server.SetResponse("COMPRESS DEFLATE", "403 Unable to activate compression")
+	server.SetCompression(CompressionGzip)
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host:        server.Host(),
+		Port:        server.Port(),
+		Compression: true,
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	assert.Equal(t, CompressionGzip, client.Compression(), "compression")
+	assertCompressedSession(t, client)
+}
+
+func TestCompression_Refused(t *testing.T) {
+	server := newCompressionTestServer(t)
+	server.SetResponse("CAPABILITIES", "500 Unknown command")
+	server.SetResponse("XFEATURE COMPRESS GZIP", "500 Unknown command")
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host:        server.Host(),
+		Port:        server.Port(),
+		Compression: true,
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	assert.Equal(t, CompressionNone, client.Compression(), "compression")
+	assertCompressedSession(t, client)
+}
+
+func TestCompression_Disabled(t *testing.T) {
+	server := newCompressionTestServer(t, "COMPRESS DEFLATE")
+	server.SetCompression(CompressionDeflate)
+	server.Start(t)
+
+	client := NewClient(&ClientConfig{
+		Host: server.Host(),
+		Port: server.Port(),
+	})
+
+	err := client.Connect()
+	assert.NoError(t, err, "Connect()")
+	defer client.Close()
+
+	assert.Equal(t, CompressionNone, client.Compression(), "compression")
+	assert.False(t, server.GetRequestCommands().HasCommand("CAPABILITIES"), "CAPABILITIES command")
+}
@@ -26,6 +26,9 @@ type ConnectionConfig struct {
 	TLS           bool
 	TLSSkipVerify bool
 
+	// negotiate compression, falls back to no compression if refused
+	Compression bool
+
 	Deadline      time.Time
 	DialTimeout   time.Duration
 	KeepAliveTime time.Duration
@@ -46,6 +49,7 @@ type Connection struct {
 
 	connected     bool
 	authenticated bool
+	compression   Compression
 	currentGroup  string
 	staleAt       time.Time
 }
@@ -166,6 +170,13 @@ func (c *Connection) Connect(ctx context.Context, config *ConnectionConfig) erro
 		}
 	}
 
+	if config.Compression {
+		if err := c.negotiateCompression(); err != nil {
+			c.Close()
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -188,6 +199,7 @@ func (c *Connection) Close() error {
 
 	c.connected = false
 	c.authenticated = false
+	c.compression = CompressionNone
 	c.currentGroup = ""
 
 	return r.Err()
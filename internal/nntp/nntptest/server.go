@@ -2,20 +2,29 @@ package nntptest
 
 import (
 	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
 	"fmt"
+	"io"
 	"net"
+	"net/textproto"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/nntp"
 )
 
 type response struct {
 	statusLine  string
 	body        []string
 	isMultiLine bool
+	// sent without compression, even if `XFEATURE COMPRESS GZIP` is enabled
+	isUncompressed bool
 }
 
 type requestCommands []string
@@ -24,13 +33,22 @@ func (rcmds requestCommands) HasCommand(cmd string) bool {
 	return slices.Contains(rcmds, cmd)
 }
 
+type Article struct {
+	MessageId string
+	Headers   textproto.MIMEHeader
+	Body      []byte
+}
+
 type Server struct {
-	listener        net.Listener
-	greeting        string
-	responses       map[string]response
-	requestCommands requestCommands
-	mu              sync.RWMutex
-	done            chan struct{}
+	listener         net.Listener
+	greeting         string
+	responses        map[string]response
+	articleResponses map[string]response
+	articles         []Article
+	compression      nntp.Compression
+	requestCommands  requestCommands
+	mu               sync.RWMutex
+	done             chan struct{}
 }
 
 func NewServer(t *testing.T, greeting string) *Server {
@@ -42,13 +60,18 @@ func NewServer(t *testing.T, greeting string) *Server {
 	}
 
 	s := &Server{
-		listener:  listener,
-		greeting:  greeting,
-		responses: make(map[string]response),
-		done:      make(chan struct{}),
+		listener:         listener,
+		greeting:         greeting,
+		responses:        make(map[string]response),
+		articleResponses: make(map[string]response),
+		done:             make(chan struct{}),
 	}
 
 	s.SetResponse("DATE", "111 20260101000000")
+	s.SetResponse("POST", "340 Send article to be posted")
+	s.SetResponse("IHAVE *", "335 Send article to be transferred")
+	s.SetArticleResponse("POST", "240 Article received OK")
+	s.SetArticleResponse("IHAVE *", "235 Article transferred OK")
 
 	return s
 }
@@ -90,15 +113,53 @@ func (s *Server) SetResponse(command, statusLine string, body ...[]string) {
 	s.responses[command] = response
 }
 
+// SetUncompressedResponse is same as SetResponse, but the multi-line block is
+// not compressed even if `XFEATURE COMPRESS GZIP` is enabled.
+func (s *Server) SetUncompressedResponse(command, statusLine string, body ...[]string) {
+	s.SetResponse(command, statusLine, body...)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response := s.responses[command]
+	response.isUncompressed = true
+	s.responses[command] = response
+}
+
+// SetCompression enables the command for the compression, i.e. `COMPRESS
+// DEFLATE` or `XFEATURE COMPRESS GZIP`.
+func (s *Server) SetCompression(compression nntp.Compression) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.compression = compression
+}
+
+// SetArticleResponse sets the response sent after receiving the article for
+// POST/IHAVE command.
+func (s *Server) SetArticleResponse(command, statusLine string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.articleResponses[command] = response{statusLine: statusLine}
+}
+
 func (s *Server) getResponse(command string) (response, bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if response, ok := s.responses[command]; ok {
+	return findResponse(s.responses, command)
+}
+
+func (s *Server) getArticleResponse(command string) (response, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return findResponse(s.articleResponses, command)
+}
+
+func findResponse(responses map[string]response, command string) (response, bool) {
+	if response, ok := responses[command]; ok {
 		return response, true
 	}
 
-	for cmd, response := range s.responses {
+	for cmd, response := range responses {
 		if strings.HasSuffix(cmd, " *") && strings.HasPrefix(command, strings.TrimSuffix(cmd, "*")) {
 			return response, true
 		}
@@ -107,6 +168,54 @@ func (s *Server) getResponse(command string) (response, bool) {
 	return response{}, false
 }
 
+// GetArticles returns the articles received with POST/IHAVE command.
+func (s *Server) GetArticles() []Article {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.articles)
+}
+
+// addArticle stores the article, and sets the responses for retrieving it.
+func (s *Server) addArticle(data []byte) {
+	reader := textproto.NewReader(bufio.NewReader(bytes.NewReader(data)))
+	headers, _ := reader.ReadMIMEHeader()
+	body := data
+	if idx := bytes.Index(data, []byte("\n\n")); idx != -1 {
+		body = data[idx+2:]
+	}
+
+	article := Article{
+		MessageId: headers.Get("Message-ID"),
+		Headers:   headers,
+		Body:      body,
+	}
+
+	s.mu.Lock()
+	s.articles = append(s.articles, article)
+	s.mu.Unlock()
+
+	if article.MessageId == "" {
+		return
+	}
+
+	bodyLines := dotStuffedLines(body)
+	articleLines := dotStuffedLines(data)
+	s.SetResponse("ARTICLE "+article.MessageId, "220 0 "+article.MessageId, articleLines)
+	s.SetResponse("HEAD "+article.MessageId, "221 0 "+article.MessageId, dotStuffedLines(bytes.TrimRight(data[:len(data)-len(body)], "\n")))
+	s.SetResponse("BODY "+article.MessageId, "222 0 "+article.MessageId, bodyLines)
+	s.SetResponse("STAT "+article.MessageId, "223 0 "+article.MessageId)
+}
+
+func dotStuffedLines(data []byte) []string {
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") {
+			lines[i] = "." + line
+		}
+	}
+	return lines
+}
+
 func (s *Server) GetRequestCommands() requestCommands {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -142,12 +251,26 @@ func (s *Server) Start(t *testing.T) {
 	}()
 }
 
+type flushWriter struct {
+	w *flate.Writer
+}
+
+func (fw flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, fw.w.Flush()
+}
+
 func (s *Server) handleConn(conn net.Conn) {
 	defer conn.Close()
 
 	fmt.Fprintf(conn, "%s\r\n", s.greeting)
 
+	var w io.Writer = conn
 	reader := bufio.NewReader(conn)
+	compression := nntp.CompressionNone
 	for {
 		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
 		line, err := reader.ReadString('\n')
@@ -162,20 +285,64 @@ func (s *Server) handleConn(conn net.Conn) {
 
 		s.mu.Lock()
 		s.requestCommands = append(s.requestCommands, line)
+		supportedCompression := s.compression
 		s.mu.Unlock()
 
 		if line == "QUIT" {
-			fmt.Fprintf(conn, "205 Connection closing\r\n")
+			fmt.Fprintf(w, "205 Connection closing\r\n")
 			return
 		}
 
-		if response, ok := s.getResponse(line); ok {
-			fmt.Fprintf(conn, "%s\r\n", response.statusLine)
-			for _, bodyLine := range response.body {
-				fmt.Fprintf(conn, "%s\r\n", bodyLine)
+		if compression == nntp.CompressionNone {
+			if line == "COMPRESS DEFLATE" && supportedCompression == nntp.CompressionDeflate {
+				fmt.Fprintf(w, "206 Compression active\r\n")
+				fw, err := flate.NewWriter(conn, flate.DefaultCompression)
+				if err != nil {
+					return
+				}
+				w = flushWriter{fw}
+				reader = bufio.NewReader(flate.NewReader(reader))
+				compression = nntp.CompressionDeflate
+				continue
+			}
+			if line == "XFEATURE COMPRESS GZIP" && supportedCompression == nntp.CompressionGzip {
+				fmt.Fprintf(w, "290 feature enabled\r\n")
+				compression = nntp.CompressionGzip
+				continue
 			}
+		}
+
+		if response, ok := s.getResponse(line); ok {
+			fmt.Fprintf(w, "%s\r\n", response.statusLine)
 			if response.isMultiLine {
-				fmt.Fprintf(conn, ".\r\n") // dot-terminator
+				var block bytes.Buffer
+				for _, bodyLine := range response.body {
+					fmt.Fprintf(&block, "%s\r\n", bodyLine)
+				}
+				block.WriteString(".\r\n") // dot-terminator
+				if compression == nntp.CompressionGzip && !response.isUncompressed {
+					var compressed bytes.Buffer
+					zw := zlib.NewWriter(&compressed)
+					zw.Write(block.Bytes())
+					zw.Close()
+					block = compressed
+				}
+				w.Write(block.Bytes())
+			}
+
+			if strings.HasPrefix(response.statusLine, "340") || strings.HasPrefix(response.statusLine, "335") {
+				data, err := textproto.NewReader(reader).ReadDotBytes()
+				if err != nil {
+					return
+				}
+				response, ok := s.getArticleResponse(line)
+				if !ok {
+					continue
+				}
+				if strings.HasPrefix(response.statusLine, "2") {
+					s.addArticle(data)
+				}
+				fmt.Fprintf(w, "%s\r\n", response.statusLine)
 			}
 		}
 	}
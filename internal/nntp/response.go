@@ -27,6 +27,7 @@ const (
 	StatusPostingAllowed       = 200 // service available, posting allowed
 	StatusPostingNotAllowed    = 201 // service available, posting prohibited
 	StatusClosingConnection    = 205 // connection closing
+	StatusCompressionActive    = 206 // compression active (RFC 8054)
 	StatusGroupSelected        = 211 // group selected
 	StatusArticleNumber        = 211 // article numbers follow
 	StatusInformation          = 215 // information follows
@@ -42,6 +43,7 @@ const (
 	StatusArticleReceivedOK    = 240 // article received OK
 	StatusAuthAccepted         = 281 // authentication accepted
 	StatusAuthAcceptedWithData = 283 // authentication accepted (with success data)
+	StatusFeatureEnabled       = 290 // feature enabled (XFEATURE)
 
 	StatusSendArticleToTransfer = 335 // send article to be transferred
 	StatusSendArticleToPost     = 340 // send article to be posted
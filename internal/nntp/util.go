@@ -1,7 +1,11 @@
 package nntp
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"net/textproto"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +19,48 @@ func validateInput(s string) error {
 	return nil
 }
 
+// validateHeaders checks the article headers for command injection attempts
+func validateHeaders(headers textproto.MIMEHeader) error {
+	for key, values := range headers {
+		if key == "" || strings.ContainsAny(key, ": \r\n") {
+			return NewProtocolError(0, "invalid header: "+strconv.Quote(key))
+		}
+		for _, value := range values {
+			if err := validateInput(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writes the headers (sorted by key), followed by a blank line and the body.
+// `w` is expected to take care of dot-stuffing.
+func writeArticle(w io.Writer, headers textproto.MIMEHeader, body io.Reader) error {
+	bw := bufio.NewWriter(w)
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		for _, value := range headers[key] {
+			if _, err := fmt.Fprintf(bw, "%s: %s\r\n", key, value); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := bw.WriteString("\r\n"); err != nil {
+		return err
+	}
+	if body != nil {
+		if _, err := io.Copy(bw, body); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
 // parses CAPABILITIES response
 func parseCapabilities(lines []string) (*Capabilities, error) {
 	if len(lines) == 0 {
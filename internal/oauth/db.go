@@ -18,6 +18,7 @@ type Provider string
 const (
 	ProviderKitsu      Provider = "kitsu.app"
 	ProviderLetterboxd Provider = "letterboxd.com"
+	ProviderSimkl      Provider = "simkl.com"
 	ProviderTMDB       Provider = "themoviedb.org"
 	ProviderTraktTv    Provider = "trakt.tv"
 	ProviderTVDB       Provider = "thetvdb.com"
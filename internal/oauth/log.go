@@ -6,5 +6,6 @@ import (
 
 var log = logger.Scoped("oauth")
 var traktLog = logger.Scoped("oauth/trakt")
+var simklLog = logger.Scoped("oauth/simkl")
 var kitsuLog = logger.Scoped("oauth/kitsu")
 var tokenSourceLog = logger.Scoped("oauth/token_source")
@@ -0,0 +1,180 @@
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/request"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+)
+
+type simklResponseError struct {
+	Err     string `json:"error"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *simklResponseError) Error() string {
+	ret, _ := json.Marshal(e)
+	return string(ret)
+}
+
+func (e *simklResponseError) Unmarshal(res *http.Response, body []byte, v any) error {
+	contentType := res.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(contentType, "application/json"):
+		return core.UnmarshalJSON(res.StatusCode, body, v)
+	case strings.Contains(contentType, "text/html"):
+		if res.StatusCode >= http.StatusBadRequest {
+			errMsg := strings.TrimSpace(string(body))
+			if errMsg == "" {
+				errMsg = res.Status
+			}
+			return errors.New(errMsg)
+		}
+		fallthrough
+	default:
+		return fmt.Errorf("unexpected content type: %s", contentType)
+	}
+}
+
+func (r *simklResponseError) GetError(res *http.Response) error {
+	if r == nil || r.Err == "" {
+		return nil
+	}
+	return r
+}
+
+var SimklTokenSourceConfig = TokenSourceConfig{
+	Provider: ProviderSimkl,
+	GetUser: func(client *http.Client, oauthConfig *oauth2.Config) (userId, userName string, err error) {
+		req, err := http.NewRequest("POST", "https://api.simkl.com/users/settings", nil)
+		if err != nil {
+			return "", "", err
+		}
+		req.Header.Set("simkl-api-key", oauthConfig.ClientID)
+		res, err := client.Do(req)
+		var response struct {
+			simklResponseError
+			User struct {
+				Name string `json:"name"`
+			} `json:"user"`
+			Account struct {
+				Id int64 `json:"id"`
+			} `json:"account"`
+		}
+		err = request.ProcessResponseBody(res, err, &response)
+		if err != nil {
+			return "", "", err
+		}
+
+		return strconv.FormatInt(response.Account.Id, 10), response.User.Name, nil
+	},
+	PrepareToken: func(tok *oauth2.Token, id, userId string, userName string) *oauth2.Token {
+		return tok.WithExtra(map[string]any{
+			"id":         id,
+			"provider":   ProviderSimkl,
+			"user_id":    userId,
+			"user_name":  userName,
+			"scope":      tok.Extra("scope").(string),
+			"created_at": time.Unix(int64(tok.Extra("created_at").(float64)), 0),
+		})
+	},
+}
+
+var simklOAuthConfig = oauth2.Config{
+	ClientID:     config.Integration.Simkl.ClientId,
+	ClientSecret: config.Integration.Simkl.ClientSecret,
+	Endpoint: oauth2.Endpoint{
+		AuthURL:  "https://simkl.com/oauth/authorize",
+		TokenURL: "https://api.simkl.com/oauth/token",
+	},
+	RedirectURL: config.BaseURL.JoinPath("/auth/simkl.com/callback").String(),
+}
+
+// Simkl access tokens do not expire and there is no refresh token.
+var SimklOAuthConfig = OAuthConfig{
+	Config:      simklOAuthConfig,
+	AuthCodeURL: simklOAuthConfig.AuthCodeURL,
+	Exchange: func(code, state string) (*oauth2.Token, error) {
+		jsonBytes, err := json.Marshal(map[string]string{
+			"code":          code,
+			"client_id":     simklOAuthConfig.ClientID,
+			"client_secret": simklOAuthConfig.ClientSecret,
+			"redirect_uri":  simklOAuthConfig.RedirectURL,
+			"grant_type":    "authorization_code",
+		})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequest("POST", simklOAuthConfig.Endpoint.TokenURL, bytes.NewBuffer(jsonBytes))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Add("Accept", "application/json")
+		req.Header.Add("Content-Type", "application/json")
+
+		res, err := config.DefaultHTTPClient.Do(req)
+		var response struct {
+			simklResponseError
+			AccessToken string `json:"access_token"`
+			TokenType   string `json:"token_type"`
+			Scope       string `json:"scope"`
+		}
+		err = request.ProcessResponseBody(res, err, &response)
+		if err != nil {
+			return nil, err
+		}
+		if response.AccessToken == "" {
+			return nil, &oauth2.RetrieveError{
+				ErrorCode: "invalid_grant",
+			}
+		}
+
+		tok := &oauth2.Token{AccessToken: response.AccessToken, TokenType: "Bearer"}
+		tok = tok.WithExtra(map[string]any{
+			"scope":      response.Scope,
+			"created_at": float64(time.Now().Unix()),
+		})
+
+		simklLog.Debug("fetching user info for new token")
+		userId, userName, err := SimklTokenSourceConfig.GetUser(
+			oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(tok)),
+			&simklOAuthConfig,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		existingOTok, err := GetOAuthTokenByUserId(SimklTokenSourceConfig.Provider, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		tokenId := uuid.NewString()
+		if existingOTok != nil {
+			tokenId = existingOTok.Id
+		}
+
+		tok = SimklTokenSourceConfig.PrepareToken(tok, tokenId, userId, userName)
+
+		otok := &OAuthToken{}
+		otok = otok.FromToken(tok)
+		err = SaveOAuthToken(otok)
+		if err != nil {
+			return nil, err
+		}
+
+		return tok, nil
+	},
+}
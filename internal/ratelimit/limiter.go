@@ -10,7 +10,16 @@ import (
 	rrl "github.com/nccapo/rate-limiter"
 )
 
-var cachedLimiterById sync.Map // map[string]*Limiter
+var cachedLimiterById sync.Map // map[string]*cachedLimiter
+
+// the config of a cached limiter is checked again after this, so that changes
+// made on other instances are picked up.
+const limiterConfigCheckInterval = 1 * time.Minute
+
+type cachedLimiter struct {
+	limiter   *Limiter
+	checkedAt time.Time
+}
 
 func createStore() rrl.Store {
 	if redis.IsAvailable() {
@@ -25,11 +34,24 @@ type Limiter struct {
 }
 
 func (l *Limiter) Try(key string) (*rrl.RateLimitResult, error) {
-	return l.rl.Allow(context.Background(), key)
+	return l.TryContext(context.Background(), key)
+}
+
+func (l *Limiter) TryContext(ctx context.Context, key string) (*rrl.RateLimitResult, error) {
+	return l.rl.Allow(ctx, key)
 }
 
 func (l *Limiter) Wait(key string) error {
-	return l.rl.Wait(context.Background(), key)
+	return l.WaitContext(context.Background(), key)
+}
+
+func (l *Limiter) WaitContext(ctx context.Context, key string) error {
+	return l.rl.Wait(ctx, key)
+}
+
+// time it takes to refill a single token
+func (l *Limiter) interval() time.Duration {
+	return l.rl.RefillInterval
 }
 
 func (l *Limiter) Config() *RateLimitConfig {
@@ -59,9 +81,18 @@ func NewLimiter(conf *RateLimitConfig) (*Limiter, error) {
 	}, nil
 }
 
+// other instances pick up the change on their next config check
+func invalidateLimiter(id string) {
+	cachedLimiterById.Delete(id)
+}
+
 func NewLimiterById(id string) (*Limiter, error) {
-	if cached, ok := cachedLimiterById.Load(id); ok {
-		return cached.(*Limiter), nil
+	var cached *cachedLimiter
+	if v, ok := cachedLimiterById.Load(id); ok {
+		cached = v.(*cachedLimiter)
+		if time.Since(cached.checkedAt) < limiterConfigCheckInterval {
+			return cached.limiter, nil
+		}
 	}
 
 	cfg, err := GetById(id)
@@ -69,14 +100,18 @@ func NewLimiterById(id string) (*Limiter, error) {
 		return nil, err
 	}
 	if cfg == nil {
+		cachedLimiterById.Delete(id)
 		return nil, fmt.Errorf("rate limit config not found: %s", id)
 	}
 
-	limiter, err := NewLimiter(cfg)
-	if err != nil {
+	var limiter *Limiter
+	if cached != nil && cached.limiter.config.Limit == cfg.Limit && cached.limiter.config.Window == cfg.Window {
+		// keeps the in-memory buckets
+		limiter = &Limiter{config: cfg, rl: cached.limiter.rl}
+	} else if limiter, err = NewLimiter(cfg); err != nil {
 		return nil, err
 	}
 
-	cachedLimiterById.Store(id, limiter)
+	cachedLimiterById.Store(id, &cachedLimiter{limiter: limiter, checkedAt: time.Now()})
 	return limiter, nil
 }
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLimiterById(t *testing.T) {
+	dbtest.Open(t)
+
+	cfg, err := Create("test", 10, "1m")
+	require.NoError(t, err)
+	t.Cleanup(func() { invalidateLimiter(cfg.Id) })
+
+	expireCheck := func() {
+		v, ok := cachedLimiterById.Load(cfg.Id)
+		require.True(t, ok)
+		v.(*cachedLimiter).checkedAt = time.Now().Add(-limiterConfigCheckInterval)
+	}
+
+	limiter, err := NewLimiterById(cfg.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 10, limiter.Config().Limit)
+
+	cached, err := NewLimiterById(cfg.Id)
+	require.NoError(t, err)
+	assert.Same(t, limiter, cached)
+
+	t.Run("unchanged config", func(t *testing.T) {
+		expireCheck()
+		checked, err := NewLimiterById(cfg.Id)
+		require.NoError(t, err)
+		assert.Same(t, limiter.rl, checked.rl)
+		limiter = checked
+	})
+
+	t.Run("changed on other instance", func(t *testing.T) {
+		// bypasses the local invalidation
+		_, err := db.Exec(query_update, "test", 20, "1m", cfg.Id)
+		require.NoError(t, err)
+
+		stale, err := NewLimiterById(cfg.Id)
+		require.NoError(t, err)
+		assert.Same(t, limiter, stale)
+
+		expireCheck()
+		updated, err := NewLimiterById(cfg.Id)
+		require.NoError(t, err)
+		assert.Equal(t, 20, updated.Config().Limit)
+		assert.NotSame(t, limiter.rl, updated.rl)
+		limiter = updated
+	})
+
+	t.Run("changed locally", func(t *testing.T) {
+		_, err := Update(cfg.Id, "test", 30, "1m")
+		require.NoError(t, err)
+
+		updated, err := NewLimiterById(cfg.Id)
+		require.NoError(t, err)
+		assert.Equal(t, 30, updated.Config().Limit)
+	})
+
+	t.Run("deleted on other instance", func(t *testing.T) {
+		_, err := db.Exec(query_delete, cfg.Id)
+		require.NoError(t, err)
+
+		expireCheck()
+		_, err = NewLimiterById(cfg.Id)
+		assert.Error(t, err)
+		_, ok := cachedLimiterById.Load(cfg.Id)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,178 @@
+package ratelimit
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+const StoreTableName = "store_rate_limit"
+
+type StoreRateLimitMode string
+
+const (
+	// wait for the limiter, up to MaxWait, before sending the request
+	StoreRateLimitModeWait StoreRateLimitMode = "wait"
+	// respond with 429 right away when the limit is exceeded
+	StoreRateLimitModeFail StoreRateLimitMode = "fail"
+)
+
+func (m StoreRateLimitMode) IsValid() bool {
+	return m == StoreRateLimitModeWait || m == StoreRateLimitModeFail
+}
+
+type StoreRateLimit struct {
+	StoreName         string
+	RateLimitConfigId string
+	Mode              StoreRateLimitMode
+	MaxWait           string // Duration string like "10s", "1m"
+	CAt               db.Timestamp
+	UAt               db.Timestamp
+}
+
+func (srl *StoreRateLimit) ParseMaxWait() (time.Duration, error) {
+	return time.ParseDuration(srl.MaxWait)
+}
+
+var StoreColumn = struct {
+	StoreName         string
+	RateLimitConfigId string
+	Mode              string
+	MaxWait           string
+	CAt               string
+	UAt               string
+}{
+	StoreName:         "store_name",
+	RateLimitConfigId: "rate_limit_config_id",
+	Mode:              "mode",
+	MaxWait:           "max_wait",
+	CAt:               "cat",
+	UAt:               "uat",
+}
+
+var store_columns = []string{
+	StoreColumn.StoreName,
+	StoreColumn.RateLimitConfigId,
+	StoreColumn.Mode,
+	StoreColumn.MaxWait,
+	StoreColumn.CAt,
+	StoreColumn.UAt,
+}
+
+var query_get_all_store_rate_limits = fmt.Sprintf(
+	`SELECT %s FROM %s ORDER BY %s`,
+	db.JoinColumnNames(store_columns...),
+	StoreTableName,
+	StoreColumn.StoreName,
+)
+
+func GetAllStoreRateLimits() ([]StoreRateLimit, error) {
+	rows, err := db.Query(query_get_all_store_rate_limits)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []StoreRateLimit{}
+	for rows.Next() {
+		item := StoreRateLimit{}
+		if err := rows.Scan(&item.StoreName, &item.RateLimitConfigId, &item.Mode, &item.MaxWait, &item.CAt, &item.UAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+var query_get_store_rate_limit = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ?`,
+	db.JoinColumnNames(store_columns...),
+	StoreTableName,
+	StoreColumn.StoreName,
+)
+
+func GetStoreRateLimit(storeName string) (*StoreRateLimit, error) {
+	row := db.QueryRow(query_get_store_rate_limit, storeName)
+
+	item := StoreRateLimit{}
+	if err := row.Scan(&item.StoreName, &item.RateLimitConfigId, &item.Mode, &item.MaxWait, &item.CAt, &item.UAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+var query_set_store_rate_limit = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s`,
+	StoreTableName,
+	db.JoinColumnNames(
+		StoreColumn.StoreName,
+		StoreColumn.RateLimitConfigId,
+		StoreColumn.Mode,
+		StoreColumn.MaxWait,
+	),
+	util.RepeatJoin("?", 4, ","),
+	StoreColumn.StoreName,
+	strings.Join([]string{
+		fmt.Sprintf("%s = EXCLUDED.%s", StoreColumn.RateLimitConfigId, StoreColumn.RateLimitConfigId),
+		fmt.Sprintf("%s = EXCLUDED.%s", StoreColumn.Mode, StoreColumn.Mode),
+		fmt.Sprintf("%s = EXCLUDED.%s", StoreColumn.MaxWait, StoreColumn.MaxWait),
+		fmt.Sprintf("%s = %s", StoreColumn.UAt, db.CurrentTimestamp),
+	}, ", "),
+)
+
+func SetStoreRateLimit(storeName string, rateLimitConfigId string, mode StoreRateLimitMode, maxWait string) (*StoreRateLimit, error) {
+	_, err := db.Exec(query_set_store_rate_limit, storeName, rateLimitConfigId, mode, maxWait)
+	if err != nil {
+		return nil, err
+	}
+	storeRateLimitCache.Remove(storeName)
+
+	return GetStoreRateLimit(storeName)
+}
+
+var query_delete_store_rate_limit = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	StoreTableName,
+	StoreColumn.StoreName,
+)
+
+func DeleteStoreRateLimit(storeName string) error {
+	_, err := db.Exec(query_delete_store_rate_limit, storeName)
+	if err != nil {
+		return err
+	}
+	storeRateLimitCache.Remove(storeName)
+	return nil
+}
+
+var query_get_store_names_by_rate_limit_config_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ?`,
+	StoreColumn.StoreName,
+	StoreTableName,
+	StoreColumn.RateLimitConfigId,
+)
+
+func GetStoreNamesByRateLimitConfigId(rateLimitConfigId string) ([]string, error) {
+	rows, err := db.Query(query_get_store_names_by_rate_limit_config_id, rateLimitConfigId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	storeNames := []string{}
+	for rows.Next() {
+		var storeName string
+		if err := rows.Scan(&storeName); err != nil {
+			return nil, err
+		}
+		storeNames = append(storeNames, storeName)
+	}
+	return storeNames, nil
+}
@@ -0,0 +1,261 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+var storeLog = logger.Scoped("ratelimit/store")
+
+const defaultStoreMaxWait = 10 * time.Second
+
+type cachedStoreRateLimit struct {
+	RateLimitConfigId string             `json:"rlc_id"`
+	Mode              StoreRateLimitMode `json:"mode"`
+	MaxWait           string             `json:"max_wait"`
+}
+
+var storeRateLimitCache = cache.NewCache[cachedStoreRateLimit](&cache.CacheConfig{
+	Name:     "ratelimit:store",
+	Lifetime: 1 * time.Minute,
+})
+
+// unix milli timestamp until which upstream asked us to back off
+var storeCooldownCache = cache.NewCache[int64](&cache.CacheConfig{
+	Name:     "ratelimit:store:cooldown",
+	Lifetime: 1 * time.Hour,
+})
+
+func getCachedStoreRateLimit(storeName string) (*cachedStoreRateLimit, error) {
+	srl := cachedStoreRateLimit{}
+	if !storeRateLimitCache.Get(storeName, &srl) {
+		item, err := GetStoreRateLimit(storeName)
+		if err != nil {
+			return nil, err
+		}
+		if item != nil {
+			srl.RateLimitConfigId = item.RateLimitConfigId
+			srl.Mode = item.Mode
+			srl.MaxWait = item.MaxWait
+		}
+		if err := storeRateLimitCache.Add(storeName, srl); err != nil {
+			storeLog.Warn("failed to cache store rate limit", "error", err, "store", storeName)
+		}
+	}
+	if srl.RateLimitConfigId == "" {
+		return nil, nil
+	}
+	return &srl, nil
+}
+
+// StoreTokenGetter returns the token the store request is authenticated with.
+type StoreTokenGetter func(req *http.Request) string
+
+func StoreTokenFromHeader(name string) StoreTokenGetter {
+	return func(req *http.Request) string {
+		return req.Header.Get(name)
+	}
+}
+
+func StoreTokenFromQuery(name string) StoreTokenGetter {
+	return func(req *http.Request) string {
+		return req.URL.Query().Get(name)
+	}
+}
+
+var defaultStoreTokenGetters = []StoreTokenGetter{StoreTokenFromHeader("Authorization")}
+
+// the token is hashed, it only needs to tell the buckets apart
+func getStoreRateLimitKey(storeName string, req *http.Request, getTokens []StoreTokenGetter) string {
+	token := ""
+	for _, getToken := range getTokens {
+		if token = getToken(req); token != "" {
+			break
+		}
+	}
+	return "store:" + storeName + ":" + util.MD5Hash(token)
+}
+
+// parseRetryAfter supports both delay-seconds and HTTP-date values.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
+
+func newTooManyRequestsResponse(req *http.Request, retryAfter time.Duration) *http.Response {
+	body := `{"error":"too many requests","error_code":"TOO_MANY_REQUESTS"}`
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	return &http.Response{
+		Status:        "429 Too Many Requests",
+		StatusCode:    http.StatusTooManyRequests,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type storeTransport struct {
+	storeName string
+	getTokens []StoreTokenGetter
+	base      http.RoundTripper
+}
+
+// acquire returns a 429 response if the request should not be sent upstream.
+func (t *storeTransport) acquire(req *http.Request, limiter *Limiter, key string, mode StoreRateLimitMode, maxWait time.Duration) (*http.Response, error) {
+	ctx := req.Context()
+
+	var cooldownUntil int64
+	if storeCooldownCache.Get(key, &cooldownUntil) {
+		if cooldown := time.Until(time.UnixMilli(cooldownUntil)); cooldown > 0 {
+			if mode == StoreRateLimitModeFail || cooldown > maxWait {
+				return newTooManyRequestsResponse(req, cooldown), nil
+			}
+			if err := sleep(ctx, cooldown); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if mode == StoreRateLimitModeFail {
+		result, err := limiter.TryContext(ctx, key)
+		if err != nil {
+			storeLog.Warn("failed to check rate limit", "error", err, "store", t.storeName)
+			return nil, nil
+		}
+		if !result.Allowed {
+			return newTooManyRequestsResponse(req, result.RetryAfter), nil
+		}
+		return nil, nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, maxWait)
+	defer cancel()
+	if err := limiter.WaitContext(waitCtx, key); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return newTooManyRequestsResponse(req, limiter.interval()), nil
+		}
+		storeLog.Warn("failed to wait for rate limit", "error", err, "store", t.storeName)
+	}
+	return nil, nil
+}
+
+func (t *storeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	srl, err := getCachedStoreRateLimit(t.storeName)
+	if err != nil {
+		storeLog.Warn("failed to get store rate limit", "error", err, "store", t.storeName)
+		return t.base.RoundTrip(req)
+	}
+	if srl == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	limiter, err := NewLimiterById(srl.RateLimitConfigId)
+	if err != nil {
+		storeLog.Warn("failed to get rate limiter", "error", err, "store", t.storeName)
+		return t.base.RoundTrip(req)
+	}
+
+	maxWait, err := time.ParseDuration(srl.MaxWait)
+	if err != nil {
+		maxWait = defaultStoreMaxWait
+	}
+
+	key := getStoreRateLimitKey(t.storeName, req, t.getTokens)
+
+	if res, err := t.acquire(req, limiter, key, srl.Mode, maxWait); res != nil || err != nil {
+		return res, err
+	}
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil || res.StatusCode != http.StatusTooManyRequests {
+		return res, err
+	}
+
+	retryAfter := parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+	if retryAfter == 0 {
+		retryAfter = limiter.interval()
+	}
+	if err := storeCooldownCache.AddWithLifetime(key, time.Now().Add(retryAfter).UnixMilli(), retryAfter); err != nil {
+		storeLog.Warn("failed to set cooldown", "error", err, "store", t.storeName)
+	}
+	storeLog.Debug("upstream rate limited", "store", t.storeName, "retry_after", retryAfter.String())
+
+	if srl.Mode != StoreRateLimitModeWait || retryAfter > maxWait || (req.Body != nil && req.GetBody == nil) {
+		return res, nil
+	}
+
+	retryReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return res, nil
+		}
+		retryReq.Body = body
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	if res, err := t.acquire(retryReq, limiter, key, srl.Mode, maxWait); res != nil || err != nil {
+		return res, err
+	}
+	return t.base.RoundTrip(retryReq)
+}
+
+// WrapStoreHTTPClient returns a copy of the client that applies the rate limit
+// configured for the store, with separate buckets for each token. The token is
+// taken from the first getter that finds one, defaults to the `Authorization`
+// header.
+func WrapStoreHTTPClient(client *http.Client, storeName string, getTokens ...StoreTokenGetter) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c := *client
+	if len(getTokens) == 0 {
+		getTokens = defaultStoreTokenGetters
+	}
+	c.Transport = &storeTransport{storeName: storeName, getTokens: getTokens, base: base}
+	return &c
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		value  string
+		expect time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{" 120 ", 2 * time.Minute},
+		{"-1", 0},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{now.Add(-30 * time.Second).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expect, parseRetryAfter(tc.value, now))
+		})
+	}
+}
+
+func TestGetStoreRateLimitKey(t *testing.T) {
+	newReq := func(url string, header http.Header) *http.Request {
+		req, _ := http.NewRequest("GET", url, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		return req
+	}
+
+	getTokens := defaultStoreTokenGetters
+	a := getStoreRateLimitKey("realdebrid", newReq("https://example.com", http.Header{"Authorization": {"Bearer a"}}), getTokens)
+	b := getStoreRateLimitKey("realdebrid", newReq("https://example.com", http.Header{"Authorization": {"Bearer b"}}), getTokens)
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, a, getStoreRateLimitKey("realdebrid", newReq("https://example.com/other", http.Header{"Authorization": {"Bearer a"}}), getTokens))
+	assert.NotEqual(t, a, getStoreRateLimitKey("torbox", newReq("https://example.com", http.Header{"Authorization": {"Bearer a"}}), getTokens))
+
+	getTokens = []StoreTokenGetter{StoreTokenFromQuery("apikey")}
+	c := getStoreRateLimitKey("premiumize", newReq("https://example.com?apikey=secret-c", nil), getTokens)
+	d := getStoreRateLimitKey("premiumize", newReq("https://example.com?apikey=secret-d", nil), getTokens)
+	assert.NotEqual(t, c, d)
+	assert.NotContains(t, c, "secret", "token should be hashed")
+
+	getTokens = []StoreTokenGetter{StoreTokenFromQuery("key"), StoreTokenFromHeader("Cookie")}
+	e := getStoreRateLimitKey("offcloud", newReq("https://example.com?key=e", nil), getTokens)
+	f := getStoreRateLimitKey("offcloud", newReq("https://example.com?key=f", nil), getTokens)
+	assert.NotEqual(t, e, f)
+	assert.Equal(t, e, getStoreRateLimitKey("offcloud", newReq("https://example.com?key=e", http.Header{"Cookie": {"session=f"}}), getTokens))
+	g := getStoreRateLimitKey("offcloud", newReq("https://example.com", http.Header{"Cookie": {"session=g"}}), getTokens)
+	h := getStoreRateLimitKey("offcloud", newReq("https://example.com", http.Header{"Cookie": {"session=h"}}), getTokens)
+	assert.NotEqual(t, g, h)
+
+	assert.Equal(t,
+		getStoreRateLimitKey("offcloud", newReq("https://example.com", nil), getTokens),
+		getStoreRateLimitKey("offcloud", newReq("https://example.com?apikey=other", http.Header{"Authorization": {"Bearer other"}}), getTokens),
+		"only the store's token is used",
+	)
+}
@@ -2,6 +2,7 @@ package shared
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
@@ -11,6 +12,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/MunifTanjim/stremthru/core"
 	"github.com/MunifTanjim/stremthru/internal/config"
@@ -182,7 +184,106 @@ var proxyHttpClientByTunnelType = map[config.TunnelType]*http.Client{
 }
 
 func ProxyResponse(w http.ResponseWriter, r *http.Request, url string, tunnelType config.TunnelType) (bytesWritten int64, err error) {
-	request, err := http.NewRequest(r.Method, url, nil)
+	return ProxyResponseWithRefresh(w, r, url, tunnelType, nil, nil)
+}
+
+// max consecutive attempts to resume upstream without making progress
+const proxyResponseMaxResumeAttempts = 5
+
+var proxyResponseResumeBackoff = 500 * time.Millisecond
+
+type proxyResponseWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (pw *proxyResponseWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	if err != nil {
+		pw.err = err
+	}
+	return n, err
+}
+
+func newProxyRequest(r *http.Request, url string, byteRange string) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(r.Context(), r.Method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	copyHeaders(r.Header, request.Header, true)
+	if byteRange != "" {
+		request.Header.Set("Range", byteRange)
+		request.Header.Del("If-Range")
+	}
+	return request, nil
+}
+
+// getManifestAcceptEncoding limits the encodings to the ones that can be
+// decoded for rewriting the manifest, and are accepted by the client.
+func getManifestAcceptEncoding(r *http.Request) string {
+	for _, value := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(value, ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		return "gzip"
+	}
+	return "identity"
+}
+
+// ParseContentRange parses `bytes <start>-<end>/<size>`, size is -1 if unknown.
+func ParseContentRange(value string) (start, end, size int64, ok bool) {
+	value, ok = strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, 0, 0, false
+	}
+	byteRange, sizeStr, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, 0, 0, false
+	}
+	startStr, endStr, ok := strings.Cut(byteRange, "-")
+	if !ok {
+		return 0, 0, 0, false
+	}
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	end, err = strconv.ParseInt(endStr, 10, 64)
+	if err != nil || end < start {
+		return 0, 0, 0, false
+	}
+	size = -1
+	if sizeStr != "*" {
+		size, err = strconv.ParseInt(sizeStr, 10, 64)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+	}
+	return start, end, size, true
+}
+
+// ProxyResponseWithRefresh proxies the upstream response. If the upstream
+// connection drops midway, the request is resumed with a `Range` header from
+// the last byte written, so that the client sees a single continuous response.
+// If `refreshLink` is not nil, it is used to get a new url when upstream
+// responds with 403/410, i.e. the link has expired. If `rewriteManifestLink`
+// is not nil, the links in HLS/DASH manifest are rewritten with it.
+func ProxyResponseWithRefresh(w http.ResponseWriter, r *http.Request, url string, tunnelType config.TunnelType, refreshLink func() (string, error), rewriteManifestLink ManifestLinkRewriter) (bytesWritten int64, err error) {
+	newRequest := func(url string) (*http.Request, error) {
+		request, err := newProxyRequest(r, url, "")
+		if err == nil && rewriteManifestLink != nil && IsMethod(r, http.MethodGet) {
+			request.Header.Set("Accept-Encoding", getManifestAcceptEncoding(r))
+		}
+		return request, err
+	}
+
+	request, err := newRequest(url)
 	if err != nil {
 		e := ErrorInternalServerError(r, "failed to create request")
 		e.Cause = err
@@ -190,24 +291,210 @@ func ProxyResponse(w http.ResponseWriter, r *http.Request, url string, tunnelTyp
 		return
 	}
 
-	copyHeaders(r.Header, request.Header, true)
-
 	proxyHttpClient := proxyHttpClientByTunnelType[tunnelType]
 
 	response, err := proxyHttpClient.Do(request)
+	if err == nil && refreshLink != nil && isExpiredLinkStatus(response.StatusCode) {
+		response.Body.Close()
+		if url, err = refreshLink(); err == nil {
+			if request, err = newRequest(url); err == nil {
+				response, err = proxyHttpClient.Do(request)
+			}
+		}
+	}
 	if err != nil {
 		e := ErrorBadGateway(r, "failed to request url")
 		e.Cause = err
 		SendError(w, r, e)
 		return
 	}
-	defer response.Body.Close()
+
+	if rewriteManifestLink != nil && IsMethod(r, http.MethodGet) && response.StatusCode == http.StatusOK {
+		if manifestType := GetManifestType(response.Header.Get("Content-Type"), response.Request.URL); manifestType != "" {
+			return proxyManifestResponse(w, r, response, manifestType, rewriteManifestLink)
+		}
+	}
 
 	copyHeaders(response.Header, w.Header(), false)
 
 	w.WriteHeader(response.StatusCode)
 
-	return io.Copy(w, response.Body)
+	if !IsMethod(r, http.MethodGet) || (response.StatusCode != http.StatusOK && response.StatusCode != http.StatusPartialContent) {
+		defer response.Body.Close()
+		return io.Copy(w, response.Body)
+	}
+
+	// byte range of the response body, end is -1 if unknown
+	start, end := int64(0), int64(-1)
+	if response.StatusCode == http.StatusPartialContent {
+		s, e, _, ok := ParseContentRange(response.Header.Get("Content-Range"))
+		if !ok {
+			defer response.Body.Close()
+			return io.Copy(w, response.Body)
+		}
+		start, end = s, e
+	} else if response.ContentLength > 0 {
+		end = response.ContentLength - 1
+	}
+
+	pw := &proxyResponseWriter{w: w}
+	attempt := 0
+	for {
+		n, err := io.Copy(pw, response.Body)
+		response.Body.Close()
+		bytesWritten += n
+
+		if pw.err != nil || r.Context().Err() != nil {
+			return bytesWritten, err
+		}
+		if err == nil && (end == -1 || start+bytesWritten > end) {
+			return bytesWritten, nil
+		}
+		if err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+
+		if n > 0 {
+			attempt = 0
+		}
+		attempt++
+		if attempt > proxyResponseMaxResumeAttempts {
+			return bytesWritten, err
+		}
+
+		reqLog.Warn("proxy: upstream interrupted, resuming", "error", err, "offset", start+bytesWritten, "attempt", attempt)
+
+		if attempt > 1 {
+			timer := time.NewTimer(time.Duration(attempt-1) * proxyResponseResumeBackoff)
+			select {
+			case <-r.Context().Done():
+				timer.Stop()
+				return bytesWritten, r.Context().Err()
+			case <-timer.C:
+			}
+		}
+
+		response, err = requestProxyRange(r, proxyHttpClient, &url, start+bytesWritten, end, refreshLink)
+		if err != nil {
+			if r.Context().Err() != nil {
+				return bytesWritten, err
+			}
+			reqLog.Warn("proxy: failed to resume upstream", "error", err, "offset", start+bytesWritten, "attempt", attempt)
+			response = &http.Response{Body: http.NoBody}
+		}
+	}
+}
+
+func proxyManifestResponse(w http.ResponseWriter, r *http.Request, response *http.Response, manifestType ManifestType, rewriteLink ManifestLinkRewriter) (bytesWritten int64, err error) {
+	defer response.Body.Close()
+
+	var body io.Reader = response.Body
+	switch strings.ToLower(response.Header.Get("Content-Encoding")) {
+	case "", "identity":
+	case "gzip":
+		gr, err := gzip.NewReader(response.Body)
+		if err != nil {
+			e := ErrorBadGateway(r, "failed to read manifest")
+			e.Cause = err
+			SendError(w, r, e)
+			return 0, err
+		}
+		defer gr.Close()
+		body = gr
+	default:
+		copyHeaders(response.Header, w.Header(), false)
+		w.WriteHeader(response.StatusCode)
+		return io.Copy(w, response.Body)
+	}
+
+	manifest, err := io.ReadAll(io.LimitReader(body, maxManifestSize+1))
+	if err == nil && len(manifest) > maxManifestSize {
+		err = errors.New("manifest too large")
+	}
+	if err != nil {
+		e := ErrorBadGateway(r, "failed to read manifest")
+		e.Cause = err
+		SendError(w, r, e)
+		return 0, err
+	}
+
+	rewritten, err := RewriteManifest(manifestType, string(manifest), response.Request.URL, rewriteLink)
+	if err != nil {
+		e := ErrorInternalServerError(r, "failed to rewrite manifest")
+		e.Cause = err
+		SendError(w, r, e)
+		return 0, err
+	}
+
+	copyHeaders(response.Header, w.Header(), false)
+	for _, key := range []string{"Accept-Ranges", "Content-Encoding", "Content-Length", "Content-Md5", "Etag", "Last-Modified"} {
+		w.Header().Del(key)
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(rewritten)))
+	w.WriteHeader(response.StatusCode)
+	n, err := io.WriteString(w, rewritten)
+	return int64(n), err
+}
+
+// ErrRangeNotSupported is returned when upstream ignores the range request
+// and responds with the full content.
+var ErrRangeNotSupported = errors.New("range request not supported")
+
+func isExpiredLinkStatus(statusCode int) bool {
+	return statusCode == http.StatusForbidden || statusCode == http.StatusGone
+}
+
+// RequestProxyRange requests the bytes from `offset` till `end` (-1 for the
+// rest) of the url, with the method and headers of `r`. The url is updated if
+// the link is refreshed. The response is always partial content starting at
+// `offset`.
+func RequestProxyRange(r *http.Request, url *string, tunnelType config.TunnelType, offset, end int64, refreshLink func() (string, error)) (*http.Response, error) {
+	return requestProxyRange(r, proxyHttpClientByTunnelType[tunnelType], url, offset, end, refreshLink)
+}
+
+func requestProxyRange(r *http.Request, client *http.Client, url *string, offset, end int64, refreshLink func() (string, error)) (*http.Response, error) {
+	byteRange := "bytes=" + strconv.FormatInt(offset, 10) + "-"
+	if end != -1 {
+		byteRange += strconv.FormatInt(end, 10)
+	}
+
+	request, err := newProxyRequest(r, *url, byteRange)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if refreshLink != nil && isExpiredLinkStatus(response.StatusCode) {
+		response.Body.Close()
+		link, err := refreshLink()
+		if err != nil {
+			return nil, err
+		}
+		*url = link
+		if request, err = newProxyRequest(r, *url, byteRange); err != nil {
+			return nil, err
+		}
+		if response, err = client.Do(request); err != nil {
+			return nil, err
+		}
+	}
+
+	if response.StatusCode == http.StatusOK {
+		response.Body.Close()
+		return nil, ErrRangeNotSupported
+	}
+	if response.StatusCode != http.StatusPartialContent {
+		response.Body.Close()
+		return nil, errors.New("unexpected status: " + response.Status)
+	}
+	if start, _, _, ok := ParseContentRange(response.Header.Get("Content-Range")); !ok || start != offset {
+		response.Body.Close()
+		return nil, errors.New("unexpected content range: " + response.Header.Get("Content-Range"))
+	}
+	return response, nil
 }
 
 func extractRequestScheme(r *http.Request) string {
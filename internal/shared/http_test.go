@@ -0,0 +1,175 @@
+package shared
+
+import (
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseContentRange(t *testing.T) {
+	for _, tc := range []struct {
+		value string
+		start int64
+		end   int64
+		size  int64
+		ok    bool
+	}{
+		{"bytes 0-99/100", 0, 99, 100, true},
+		{"bytes 10-19/*", 10, 19, -1, true},
+		{"bytes */100", 0, 0, 0, false},
+		{"bytes 20-10/100", 0, 0, 0, false},
+		{"items 0-9/10", 0, 0, 0, false},
+	} {
+		start, end, size, ok := ParseContentRange(tc.value)
+		assert.Equal(t, tc.ok, ok, tc.value)
+		if ok {
+			assert.Equal(t, []int64{tc.start, tc.end, tc.size}, []int64{start, end, size}, tc.value)
+		}
+	}
+}
+
+func TestProxyResponseWithRefresh(t *testing.T) {
+	proxyResponseResumeBackoff = 0
+
+	content := strings.Repeat("0123456789", 100)
+
+	// drops the connection after sending half of the requested bytes, the
+	// link expires after the first request
+	var requestCount atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestCount.Add(1) > 1 && r.URL.Path == "/expired" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+
+		start, end := 0, len(content)-1
+		if byteRange, ok := strings.CutPrefix(r.Header.Get("Range"), "bytes="); ok {
+			startStr, endStr, _ := strings.Cut(byteRange, "-")
+			start, _ = strconv.Atoi(startStr)
+			if endStr != "" {
+				end, _ = strconv.Atoi(endStr)
+			}
+			w.Header().Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(end)+"/"+strconv.Itoa(len(content)))
+			w.Header().Set("Content-Length", strconv.Itoa(end-start+1))
+			w.WriteHeader(http.StatusPartialContent)
+		} else {
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.WriteHeader(http.StatusOK)
+		}
+
+		length := end - start + 1
+		if length > 100 {
+			length = length / 2
+		}
+		w.Write([]byte(content[start : start+length]))
+	}))
+	defer upstream.Close()
+
+	t.Run("resumes", func(t *testing.T) {
+		requestCount.Store(0)
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		bytesWritten, err := ProxyResponseWithRefresh(w, r, upstream.URL+"/ok", config.TUNNEL_TYPE_NONE, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int64(len(content)), bytesWritten)
+		assert.Equal(t, content, w.Body.String())
+		assert.Greater(t, requestCount.Load(), int32(1))
+	})
+
+	t.Run("resumes partial content", func(t *testing.T) {
+		requestCount.Store(0)
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Range", "bytes=500-")
+		w := httptest.NewRecorder()
+		_, err := ProxyResponseWithRefresh(w, r, upstream.URL+"/ok", config.TUNNEL_TYPE_NONE, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, content[500:], w.Body.String())
+	})
+
+	t.Run("refreshes expired link", func(t *testing.T) {
+		requestCount.Store(0)
+		refreshCount := 0
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		_, err := ProxyResponseWithRefresh(w, r, upstream.URL+"/expired", config.TUNNEL_TYPE_NONE, func() (string, error) {
+			refreshCount++
+			return upstream.URL + "/ok", nil
+		}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, refreshCount)
+		assert.Equal(t, content, w.Body.String())
+	})
+
+	t.Run("gives up without range support", func(t *testing.T) {
+		noRange := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(content[:100]))
+		}))
+		defer noRange.Close()
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		bytesWritten, err := ProxyResponseWithRefresh(w, r, noRange.URL, config.TUNNEL_TYPE_NONE, nil, nil)
+		assert.Error(t, err)
+		assert.Equal(t, int64(100), bytesWritten)
+	})
+}
+
+func TestProxyResponseWithRefreshManifest(t *testing.T) {
+	manifest := "#EXTM3U\n#EXTINF:10,\nseg-1.ts\n"
+
+	// compresses with the first encoding the client accepts
+	var acceptEncoding atomic.Value
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding.Store(r.Header.Get("Accept-Encoding"))
+		w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		encoding, _, _ := strings.Cut(r.Header.Get("Accept-Encoding"), ",")
+		switch encoding {
+		case "gzip":
+			w.Header().Set("Content-Encoding", "gzip")
+			gw := gzip.NewWriter(w)
+			gw.Write([]byte(manifest))
+			gw.Close()
+		case "br":
+			w.Header().Set("Content-Encoding", "br")
+			w.Write([]byte("not-really-brotli"))
+		default:
+			w.Write([]byte(manifest))
+		}
+	}))
+	defer upstream.Close()
+
+	for _, tc := range []struct {
+		clientAcceptEncoding   string
+		upstreamAcceptEncoding string
+	}{
+		{"br, gzip, deflate", "gzip"},
+		{"br, zstd", "identity"},
+		{"gzip;q=0, br", "identity"},
+		{"", "identity"},
+	} {
+		t.Run(tc.clientAcceptEncoding, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.clientAcceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", tc.clientAcceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			_, err := ProxyResponseWithRefresh(w, r, upstream.URL+"/index.m3u8", config.TUNNEL_TYPE_NONE, nil, testManifestLinkRewriter)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.upstreamAcceptEncoding, acceptEncoding.Load())
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get("Content-Encoding"))
+			assert.Contains(t, w.Body.String(), "https://st.example/l/")
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package shared
+
+import (
+	"html"
+	"mime"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type ManifestType string
+
+const (
+	ManifestTypeHLS  ManifestType = "hls"
+	ManifestTypeDASH ManifestType = "dash"
+)
+
+// max size of manifest that is read into memory for rewriting
+const maxManifestSize = 16 * 1024 * 1024 // 16 MB
+
+// GetManifestType detects HLS/DASH manifest from the content type, or from the
+// extension of the url if the content type is generic.
+func GetManifestType(contentType string, link *url.URL) ManifestType {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch strings.ToLower(mediaType) {
+	case "application/vnd.apple.mpegurl", "application/x-mpegurl", "audio/mpegurl", "audio/x-mpegurl":
+		return ManifestTypeHLS
+	case "application/dash+xml":
+		return ManifestTypeDASH
+	case "", "application/octet-stream", "binary/octet-stream", "text/plain":
+		if link == nil {
+			return ""
+		}
+		switch strings.ToLower(path.Ext(link.Path)) {
+		case ".m3u8":
+			return ManifestTypeHLS
+		case ".mpd":
+			return ManifestTypeDASH
+		}
+	}
+	return ""
+}
+
+// ManifestLinkRewriter returns the link to replace `link` with. If `isBase` is
+// true, the returned link must end with `/` and relative paths resolved
+// against it must resolve against `link`.
+type ManifestLinkRewriter func(link string, isBase bool) (string, error)
+
+// RewriteManifest rewrites the links in the manifest. `manifestURL` is used
+// to resolve the relative links.
+func RewriteManifest(manifestType ManifestType, manifest string, manifestURL *url.URL, rewriteLink ManifestLinkRewriter) (string, error) {
+	switch manifestType {
+	case ManifestTypeHLS:
+		return rewriteHLSManifest(manifest, manifestURL, rewriteLink)
+	case ManifestTypeDASH:
+		return rewriteDASHManifest(manifest, manifestURL, rewriteLink)
+	default:
+		return manifest, nil
+	}
+}
+
+func resolveManifestLink(base *url.URL, link string) (string, bool) {
+	u, err := base.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", false
+	}
+	return u.String(), true
+}
+
+var hlsURIAttrRegex = regexp.MustCompile(`URI="([^"]*)"`)
+
+func rewriteHLSManifest(manifest string, manifestURL *url.URL, rewriteLink ManifestLinkRewriter) (string, error) {
+	lines := strings.Split(manifest, "\n")
+	for i, line := range lines {
+		line, cr := strings.CutSuffix(line, "\r")
+		value := strings.TrimSpace(line)
+		if value == "" {
+			continue
+		}
+
+		if strings.HasPrefix(value, "#") {
+			// e.g. EXT-X-KEY, EXT-X-MAP, EXT-X-MEDIA, EXT-X-I-FRAME-STREAM-INF
+			if !strings.HasPrefix(value, "#EXT") || !strings.Contains(value, `URI="`) {
+				continue
+			}
+			var rerr error
+			line = hlsURIAttrRegex.ReplaceAllStringFunc(line, func(attr string) string {
+				uri := hlsURIAttrRegex.FindStringSubmatch(attr)[1]
+				link, ok := resolveManifestLink(manifestURL, uri)
+				if !ok {
+					return attr
+				}
+				link, err := rewriteLink(link, false)
+				if err != nil {
+					rerr = err
+					return attr
+				}
+				return `URI="` + link + `"`
+			})
+			if rerr != nil {
+				return "", rerr
+			}
+		} else if link, ok := resolveManifestLink(manifestURL, value); ok {
+			link, err := rewriteLink(link, false)
+			if err != nil {
+				return "", err
+			}
+			line = link
+		}
+
+		if cr {
+			line += "\r"
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+var dashTagRegex = regexp.MustCompile(`<(/?)([A-Za-z_][\w.:-]*)((?:[^>"']|"[^"]*"|'[^']*')*?)(/?)>`)
+var dashAttrRegex = regexp.MustCompile(`([\w.:-]+)(\s*=\s*)("[^"]*"|'[^']*')`)
+
+// attributes with links, by element
+var dashLinkAttrs = map[string][]string{
+	"SegmentTemplate":     {"media", "initialization", "index"},
+	"SegmentURL":          {"media", "index"},
+	"Initialization":      {"sourceURL"},
+	"RepresentationIndex": {"sourceURL"},
+}
+
+func getXMLLocalName(name string) string {
+	if _, local, ok := strings.Cut(name, ":"); ok {
+		return local
+	}
+	return name
+}
+
+// splitDASHLink splits absolute `link` into its directory and the rest of it.
+// The directory does not include template identifiers, e.g. `$Number$`.
+func splitDASHLink(link string) (dir string, rest string, ok bool) {
+	u, err := url.Parse(strings.Split(link, "$")[0])
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", false
+	}
+	authorityEnd := len(u.Scheme) + len("://")
+	if idx := strings.IndexAny(link[authorityEnd:], "/?#$"); idx != -1 {
+		authorityEnd += idx
+	} else {
+		return "", "", false
+	}
+	idx := strings.IndexAny(link, "?#$")
+	if idx == -1 {
+		idx = len(link)
+	}
+	idx = strings.LastIndex(link[:idx], "/")
+	if idx < authorityEnd {
+		return "", "", false
+	}
+	return link[:idx+1], link[idx+1:], true
+}
+
+// rewriteDASHLink rewrites absolute `link` to base link of its directory
+// followed by the rest of it, so that segment templates keep working.
+func rewriteDASHLink(link string, rewriteLink ManifestLinkRewriter) (string, bool, error) {
+	dir, rest, ok := splitDASHLink(link)
+	if !ok {
+		return link, false, nil
+	}
+	baseLink, err := rewriteLink(dir, true)
+	if err != nil {
+		return "", false, err
+	}
+	return baseLink + rest, true, nil
+}
+
+// Relative links in DASH manifest are resolved against the `BaseURL`s, so
+// instead of rewriting each link, the `BaseURL`s are rewritten to base links.
+// Top-level `BaseURL` is added if missing, so that relative links resolve
+// against the upstream manifest url.
+func rewriteDASHManifest(manifest string, manifestURL *url.URL, rewriteLink ManifestLinkRewriter) (string, error) {
+	var sb strings.Builder
+	sb.Grow(len(manifest))
+
+	depth := 0
+	mpdStartTagEnd := -1
+	hasTopLevelBaseURL := false
+
+	pos := 0
+	for pos < len(manifest) {
+		loc := dashTagRegex.FindStringSubmatchIndex(manifest[pos:])
+		if loc == nil {
+			break
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		isEndTag := loc[3] > loc[2]
+		name := getXMLLocalName(manifest[pos+loc[4] : pos+loc[5]])
+		isSelfClosing := loc[9] > loc[8]
+
+		sb.WriteString(manifest[pos:start])
+		pos = end
+
+		if isEndTag {
+			depth--
+			sb.WriteString(manifest[start:end])
+			continue
+		}
+
+		switch name {
+		case "BaseURL", "Location", "PatchLocation":
+			sb.WriteString(manifest[start:end])
+			if isSelfClosing {
+				continue
+			}
+			closeIdx := strings.Index(manifest[pos:], "</")
+			if closeIdx == -1 {
+				continue
+			}
+			text := manifest[pos : pos+closeIdx]
+			value := strings.TrimSpace(html.UnescapeString(text))
+			pos += closeIdx
+
+			if name != "BaseURL" {
+				if link, ok := resolveManifestLink(manifestURL, value); ok {
+					link, err := rewriteLink(link, false)
+					if err != nil {
+						return "", err
+					}
+					text = html.EscapeString(link)
+				}
+			} else if isTopLevel := depth == 1; isTopLevel || strings.Contains(value, "://") {
+				hasTopLevelBaseURL = hasTopLevelBaseURL || isTopLevel
+				if link, ok := resolveManifestLink(manifestURL, value); ok {
+					link, rewritten, err := rewriteDASHLink(link, rewriteLink)
+					if err != nil {
+						return "", err
+					}
+					if rewritten {
+						text = html.EscapeString(link)
+					}
+				}
+			}
+			sb.WriteString(text)
+			depth++
+			continue
+		}
+
+		tag := manifest[start:end]
+		if attrs, ok := dashLinkAttrs[name]; ok {
+			var rerr error
+			tag = dashAttrRegex.ReplaceAllStringFunc(tag, func(attr string) string {
+				m := dashAttrRegex.FindStringSubmatch(attr)
+				if !slices.Contains(attrs, getXMLLocalName(m[1])) {
+					return attr
+				}
+				quote := m[3][:1]
+				value := html.UnescapeString(m[3][1 : len(m[3])-1])
+				if !strings.Contains(value, "://") {
+					return attr
+				}
+				link, rewritten, err := rewriteDASHLink(value, rewriteLink)
+				if err != nil {
+					rerr = err
+					return attr
+				}
+				if !rewritten {
+					return attr
+				}
+				return m[1] + m[2] + quote + html.EscapeString(link) + quote
+			})
+			if rerr != nil {
+				return "", rerr
+			}
+		}
+		sb.WriteString(tag)
+
+		if name == "MPD" && depth == 0 {
+			mpdStartTagEnd = sb.Len()
+		}
+		if !isSelfClosing {
+			depth++
+		}
+	}
+	sb.WriteString(manifest[pos:])
+
+	rewritten := sb.String()
+	if !hasTopLevelBaseURL && mpdStartTagEnd != -1 {
+		if dir, _, ok := splitDASHLink(manifestURL.String()); ok {
+			link, err := rewriteLink(dir, true)
+			if err != nil {
+				return "", err
+			}
+			rewritten = rewritten[:mpdStartTagEnd] + "<BaseURL>" + html.EscapeString(link) + "</BaseURL>" + rewritten[mpdStartTagEnd:]
+		}
+	}
+	return rewritten, nil
+}
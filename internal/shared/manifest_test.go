@@ -0,0 +1,129 @@
+package shared
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func testManifestLinkRewriter(link string, isBase bool) (string, error) {
+	if isBase {
+		return "https://st.example/b/" + url.PathEscape(link) + "/", nil
+	}
+	return "https://st.example/l/" + url.PathEscape(link), nil
+}
+
+func TestGetManifestType(t *testing.T) {
+	for _, tc := range []struct {
+		contentType string
+		link        string
+		expected    ManifestType
+	}{
+		{"application/vnd.apple.mpegurl", "https://cdn.example/v", ManifestTypeHLS},
+		{"application/x-mpegURL; charset=utf-8", "https://cdn.example/v", ManifestTypeHLS},
+		{"application/dash+xml", "https://cdn.example/v", ManifestTypeDASH},
+		{"application/octet-stream", "https://cdn.example/v/index.m3u8?t=1", ManifestTypeHLS},
+		{"", "https://cdn.example/v/manifest.MPD", ManifestTypeDASH},
+		{"video/mp4", "https://cdn.example/v/index.m3u8", ""},
+		{"application/octet-stream", "https://cdn.example/v/video.mkv", ""},
+	} {
+		link, _ := url.Parse(tc.link)
+		assert.Equal(t, tc.expected, GetManifestType(tc.contentType, link), tc.contentType+" "+tc.link)
+	}
+}
+
+func TestRewriteManifest(t *testing.T) {
+	manifestURL, _ := url.Parse("https://cdn.example/v/master.m3u8?t=1")
+
+	t.Run("hls", func(t *testing.T) {
+		manifest := strings.Join([]string{
+			"#EXTM3U",
+			`#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="a",NAME="en",URI="audio/en.m3u8"`,
+			`#EXT-X-KEY:METHOD=AES-128,URI="https://keys.example/k?id=1",IV=0x1`,
+			`#EXT-X-KEY:METHOD=SAMPLE-AES,URI="skd://key"`,
+			"#EXT-X-STREAM-INF:BANDWIDTH=1280000",
+			"720p/index.m3u8",
+			"#EXTINF:10,",
+			"/seg-1.ts\r",
+			"",
+		}, "\n")
+		rewritten, err := RewriteManifest(ManifestTypeHLS, manifest, manifestURL, testManifestLinkRewriter)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"#EXTM3U",
+			`#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="a",NAME="en",URI="https://st.example/l/https:%2F%2Fcdn.example%2Fv%2Faudio%2Fen.m3u8"`,
+			`#EXT-X-KEY:METHOD=AES-128,URI="https://st.example/l/https:%2F%2Fkeys.example%2Fk%3Fid=1",IV=0x1`,
+			`#EXT-X-KEY:METHOD=SAMPLE-AES,URI="skd://key"`,
+			"#EXT-X-STREAM-INF:BANDWIDTH=1280000",
+			"https://st.example/l/https:%2F%2Fcdn.example%2Fv%2F720p%2Findex.m3u8",
+			"#EXTINF:10,",
+			"https://st.example/l/https:%2F%2Fcdn.example%2Fseg-1.ts\r",
+			"",
+		}, "\n"), rewritten)
+	})
+
+	t.Run("dash", func(t *testing.T) {
+		manifest := `<?xml version="1.0"?>
+<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" type="static">
+  <Period>
+    <AdaptationSet>
+      <BaseURL>video/</BaseURL>
+      <SegmentTemplate media="$RepresentationID$/seg-$Number%05d$.m4s" initialization="$RepresentationID$/init.mp4"/>
+    </AdaptationSet>
+    <AdaptationSet>
+      <BaseURL>https://other.example/a/</BaseURL>
+      <SegmentList>
+        <SegmentURL media="https://seg.example/s/1.m4s?a=1&amp;b=2"/>
+      </SegmentList>
+    </AdaptationSet>
+  </Period>
+</MPD>`
+		rewritten, err := RewriteManifest(ManifestTypeDASH, manifest, manifestURL, testManifestLinkRewriter)
+		assert.NoError(t, err)
+		assert.Equal(t, `<?xml version="1.0"?>
+<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" type="static"><BaseURL>https://st.example/b/https:%2F%2Fcdn.example%2Fv%2F/</BaseURL>
+  <Period>
+    <AdaptationSet>
+      <BaseURL>video/</BaseURL>
+      <SegmentTemplate media="$RepresentationID$/seg-$Number%05d$.m4s" initialization="$RepresentationID$/init.mp4"/>
+    </AdaptationSet>
+    <AdaptationSet>
+      <BaseURL>https://st.example/b/https:%2F%2Fother.example%2Fa%2F/</BaseURL>
+      <SegmentList>
+        <SegmentURL media="https://st.example/b/https:%2F%2Fseg.example%2Fs%2F/1.m4s?a=1&amp;b=2"/>
+      </SegmentList>
+    </AdaptationSet>
+  </Period>
+</MPD>`, rewritten)
+	})
+
+	t.Run("dash with top-level base url", func(t *testing.T) {
+		manifest := `<MPD><BaseURL>../media/</BaseURL><Period/></MPD>`
+		rewritten, err := RewriteManifest(ManifestTypeDASH, manifest, manifestURL, testManifestLinkRewriter)
+		assert.NoError(t, err)
+		assert.Equal(t, `<MPD><BaseURL>https://st.example/b/https:%2F%2Fcdn.example%2Fmedia%2F/</BaseURL><Period/></MPD>`, rewritten)
+	})
+}
+
+func TestProxyResponseWithManifest(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		w.Header().Set("Content-Length", "26")
+		w.Write([]byte("#EXTM3U\n#EXTINF:10,\nseg.ts"))
+	}))
+	defer upstream.Close()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	_, err := ProxyResponseWithRefresh(w, r, upstream.URL+"/v/index.m3u8", config.TUNNEL_TYPE_NONE, nil, testManifestLinkRewriter)
+	assert.NoError(t, err)
+	expected := "#EXTM3U\n#EXTINF:10,\nhttps://st.example/l/" + url.PathEscape(upstream.URL+"/v/seg.ts")
+	assert.Equal(t, expected, w.Body.String())
+	assert.Equal(t, "application/vnd.apple.mpegurl", w.Header().Get("Content-Type"))
+	assert.Equal(t, len(expected), int(w.Result().ContentLength))
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/metrics"
 	"github.com/MunifTanjim/stremthru/internal/server"
 	"github.com/rs/xid"
 )
@@ -38,6 +39,7 @@ func RootServerContext(next http.Handler) http.Handler {
 
 				reqLog.Error("panic recovered", "error", err, "stack", string(buf), "req.id", ctx.RequestId)
 				ErrorInternalServerError(r, "").Send(rw, r)
+				observeRequest(rw, r)
 				logRequest(rw, r)
 			}
 		}()
@@ -52,6 +54,7 @@ func RootServerContext(next http.Handler) http.Handler {
 		ctx.Log = logger.New(r.Context(), "req.id", ctx.RequestId)
 
 		next.ServeHTTP(rw, r)
+		observeRequest(rw, r)
 		logRequest(rw, r)
 	})
 }
@@ -77,6 +80,15 @@ func (rw *responseWriter) getStatusCode() int {
 	return rw.statusCode
 }
 
+func observeRequest(w *responseWriter, r *http.Request) {
+	ctx := server.GetReqCtx(r)
+	status := w.getStatusCode()
+	if status == 0 {
+		status = http.StatusOK
+	}
+	metrics.ObserveHTTPRequest(ctx.ReqMethod, r.Pattern, status, time.Since(ctx.StartTime))
+}
+
 func logRequest(w *responseWriter, r *http.Request) {
 	ctx := server.GetReqCtx(r)
 	if ctx.NoRequestLog {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"time"
@@ -11,6 +12,8 @@ import (
 	"github.com/MunifTanjim/stremthru/core"
 	"github.com/MunifTanjim/stremthru/internal/cache"
 	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/metrics"
+	"github.com/MunifTanjim/stremthru/internal/ratelimit"
 	storecontext "github.com/MunifTanjim/stremthru/internal/store/context"
 	"github.com/MunifTanjim/stremthru/internal/util"
 	"github.com/MunifTanjim/stremthru/store"
@@ -27,41 +30,46 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+func getStoreHTTPClient(storeName string, getTokens ...ratelimit.StoreTokenGetter) *http.Client {
+	client := metrics.InstrumentStoreHTTPClient(config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI(storeName)), storeName)
+	return ratelimit.WrapStoreHTTPClient(client, storeName, getTokens...)
+}
+
 var adStore = alldebrid.NewStoreClient(&alldebrid.StoreClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("alldebrid")),
+	HTTPClient: getStoreHTTPClient("alldebrid"),
 	UserAgent:  config.StoreClientUserAgent,
 })
 var drStore = debrider.NewStoreClient(&debrider.StoreClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("debrider")),
+	HTTPClient: getStoreHTTPClient("debrider"),
 	UserAgent:  config.StoreClientUserAgent,
 })
 var dlStore = debridlink.NewStoreClient(&debridlink.StoreClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("debridlink")),
+	HTTPClient: getStoreHTTPClient("debridlink"),
 	UserAgent:  config.StoreClientUserAgent,
 })
 var edStore = easydebrid.NewStoreClient(&easydebrid.StoreClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("easydebrid")),
+	HTTPClient: getStoreHTTPClient("easydebrid"),
 	UserAgent:  config.StoreClientUserAgent,
 })
 var pmStore = premiumize.NewStoreClient(&premiumize.StoreClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("premiumize")),
+	HTTPClient: getStoreHTTPClient("premiumize", ratelimit.StoreTokenFromQuery("apikey")),
 	UserAgent:  config.StoreClientUserAgent,
 })
 var ppStore = pikpak.NewStoreClient(&pikpak.StoreClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("pikpak")),
+	HTTPClient: getStoreHTTPClient("pikpak"),
 	UserAgent:  config.StoreClientUserAgent,
 })
 var ocStore = offcloud.NewStoreClient(&offcloud.StoreClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("offcloud")),
+	HTTPClient: getStoreHTTPClient("offcloud", ratelimit.StoreTokenFromQuery("key"), ratelimit.StoreTokenFromHeader("Cookie")),
 	UserAgent:  config.StoreClientUserAgent,
 })
 var rdStore = realdebrid.NewStoreClient(&realdebrid.StoreClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("realdebrid")),
+	HTTPClient: getStoreHTTPClient("realdebrid"),
 	UserAgent:  "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
 })
 var stStore = stremthru.NewStoreClient(&stremthru.StoreClientConfig{})
 var tbStore = torbox.NewStoreClient(&torbox.StoreClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("torbox")),
+	HTTPClient: getStoreHTTPClient("torbox"),
 	UserAgent:  config.StoreClientUserAgent,
 })
 
@@ -123,6 +131,11 @@ type proxyLinkTokenData struct {
 	EncLink    string            `json:"enc_link"`
 	EncFormat  string            `json:"enc_format"`
 	TunnelType config.TunnelType `json:"tunt,omitempty"`
+	StremId    string            `json:"sid,omitempty"`
+	Store      string            `json:"st,omitempty"`
+	EncStLink  string            `json:"enc_st_link,omitempty"`
+	ContentId  string            `json:"cid,omitempty"`
+	IsBase     bool              `json:"base,omitempty"`
 }
 
 type proxyLinkData struct {
@@ -130,17 +143,89 @@ type proxyLinkData struct {
 	Value   string            `json:"v"`
 	Headers map[string]string `json:"reqh,omitempty"`
 	TunT    config.TunnelType `json:"tunt,omitempty"`
+	SId     string            `json:"sid,omitempty"`
+	St      string            `json:"st,omitempty"`
+	StLink  string            `json:"stl,omitempty"`
+	CId     string            `json:"cid,omitempty"`
+	Base    bool              `json:"base,omitempty"`
+	Exp     int64             `json:"exp,omitempty"`
+	Enc     bool              `json:"enc,omitempty"`
+}
+
+// ProxyLink is the unwrapped proxy link token.
+type ProxyLink struct {
+	User       string
+	Link       string
+	Headers    map[string]string
+	TunnelType config.TunnelType
+	// optional, stremio meta id for the content
+	StremId string
+	// optional, store and store link that `Link` was generated from
+	Store     store.StoreName
+	StoreLink string
+	// optional, identifies the upstream file for the content proxy chunk cache
+	ContentId string
+	// `Link` is a base url, the path after the token is resolved against it
+	IsBase      bool
+	ExpiresAt   time.Time
+	IsEncrypted bool
 }
 
 func CreateProxyLink(r *http.Request, link string, headers map[string]string, tunnelType config.TunnelType, expiresIn time.Duration, user, password string, shouldEncrypt bool, filename string) (string, error) {
+	return CreateStremProxyLink(r, link, headers, tunnelType, expiresIn, user, password, shouldEncrypt, filename, "")
+}
+
+// CreateStremProxyLink creates proxy link for playback of stremio content with `stremId`.
+func CreateStremProxyLink(r *http.Request, link string, headers map[string]string, tunnelType config.TunnelType, expiresIn time.Duration, user, password string, shouldEncrypt bool, filename string, stremId string) (string, error) {
+	return createProxyLink(r, link, headers, tunnelType, expiresIn, user, password, shouldEncrypt, filename, stremId, nil)
+}
+
+// proxyLinkSource is the store link that the proxied link was generated from.
+type proxyLinkSource struct {
+	store store.StoreName
+	// used to regenerate the link if it expires while being proxied
+	link string
+	// identifies the upstream file, shared across users
+	contentId string
+}
+
+func createProxyLink(r *http.Request, link string, headers map[string]string, tunnelType config.TunnelType, expiresIn time.Duration, user, password string, shouldEncrypt bool, filename string, stremId string, source *proxyLinkSource) (string, error) {
+	encodedToken, err := createProxyLinkToken(link, headers, tunnelType, expiresIn, user, password, shouldEncrypt, stremId, source, false)
+	if err != nil {
+		return "", err
+	}
+
+	pLink := ExtractRequestBaseURL(r).JoinPath("/v0/proxy", encodedToken)
+
+	if filename == "" {
+		filename, _, _ = strings.Cut(filepath.Base(link), "?")
+	}
+	if filename != "" {
+		pLink = pLink.JoinPath(filename)
+	}
+
+	return pLink.String(), nil
+}
+
+func createProxyLinkToken(link string, headers map[string]string, tunnelType config.TunnelType, expiresIn time.Duration, user, password string, shouldEncrypt bool, stremId string, source *proxyLinkSource, isBase bool) (string, error) {
 	var encodedToken string
 
+	storeName, storeLink, contentId := "", "", ""
+	if source != nil {
+		storeName, storeLink, contentId = string(source.store), source.link, source.contentId
+	}
+
 	if !shouldEncrypt && expiresIn == 0 {
 		blob, err := json.Marshal(proxyLinkData{
 			User:    user + ":" + password,
 			Value:   link,
 			Headers: headers,
 			TunT:    tunnelType,
+			SId:     stremId,
+			St:      storeName,
+			StLink:  storeLink,
+			CId:     contentId,
+			Base:    isBase,
 		})
 		if err != nil {
 			return "", err
@@ -155,6 +240,7 @@ func CreateProxyLink(r *http.Request, link string, headers map[string]string, tu
 		}
 
 		var encLink string
+		var encStLink string
 		var encFormat string
 
 		if shouldEncrypt {
@@ -163,9 +249,19 @@ func CreateProxyLink(r *http.Request, link string, headers map[string]string, tu
 				return "", err
 			}
 			encLink = encryptedLink
+			if storeLink != "" {
+				encryptedStLink, err := core.Encrypt(password, storeLink)
+				if err != nil {
+					return "", err
+				}
+				encStLink = encryptedStLink
+			}
 			encFormat = core.EncryptionFormat
 		} else {
 			encLink = util.Base64Encode(linkBlob)
+			if storeLink != "" {
+				encStLink = util.Base64Encode(storeLink)
+			}
 			encFormat = "base64"
 		}
 
@@ -178,6 +274,11 @@ func CreateProxyLink(r *http.Request, link string, headers map[string]string, tu
 				EncLink:    encLink,
 				EncFormat:  encFormat,
 				TunnelType: tunnelType,
+				StremId:    stremId,
+				Store:      storeName,
+				EncStLink:  encStLink,
+				ContentId:  contentId,
+				IsBase:     isBase,
 			},
 		}
 		if expiresIn != 0 {
@@ -190,24 +291,50 @@ func CreateProxyLink(r *http.Request, link string, headers map[string]string, tu
 		encodedToken = token
 	}
 
-	pLink := ExtractRequestBaseURL(r).JoinPath("/v0/proxy", encodedToken)
+	return encodedToken, nil
+}
 
-	if filename == "" {
-		filename, _, _ = strings.Cut(filepath.Base(link), "?")
+// CreateDerivedProxyLink creates proxy link for `link` found in the content of
+// `parent`, e.g. HLS/DASH manifest. It inherits the user, headers, tunnel type
+// and expiry of `parent`. If `isBase` is true, the path after the token in the
+// created link is resolved against `link`.
+func CreateDerivedProxyLink(r *http.Request, parent *ProxyLink, link string, isBase bool) (string, error) {
+	expiresIn := 0 * time.Second
+	if !parent.ExpiresAt.IsZero() {
+		expiresIn = time.Until(parent.ExpiresAt)
+		if expiresIn <= 0 {
+			return "", errors.New("proxy link expired")
+		}
 	}
-	if filename != "" {
-		pLink = pLink.JoinPath(filename)
+
+	password := config.Auth.GetPassword(parent.User)
+	encodedToken, err := createProxyLinkToken(link, parent.Headers, parent.TunnelType, expiresIn, parent.User, password, parent.IsEncrypted, "", nil, isBase)
+	if err != nil {
+		return "", err
 	}
 
+	pLink := ExtractRequestBaseURL(r).JoinPath("/v0/proxy", encodedToken)
+	if isBase {
+		return pLink.String() + "/", nil
+	}
+
+	filename, _, _ := strings.Cut(filepath.Base(link), "?")
+	if filename != "" && filename != "." && filename != "/" {
+		pLink = pLink.JoinPath(filename)
+	}
 	return pLink.String(), nil
 }
 
 func ProxyWrapLink(r *http.Request, ctx *storecontext.Context, link string, filename string) (string, error) {
+	return proxyWrapStoreLink(r, ctx, link, filename, nil)
+}
+
+func proxyWrapStoreLink(r *http.Request, ctx *storecontext.Context, link string, filename string, source *proxyLinkSource) (string, error) {
 	storeName := string(ctx.Store.GetName())
 	if config.StoreContentProxy.IsEnabled(storeName) && ctx.StoreAuthToken == config.StoreAuthToken.GetToken(ctx.ProxyAuthUser, storeName) {
 		if ctx.IsProxyAuthorized {
 			tunnelType := config.StoreTunnel.GetTypeForStream(string(ctx.Store.GetName()))
-			proxyLink, err := CreateProxyLink(r, link, nil, tunnelType, 12*time.Hour, ctx.ProxyAuthUser, ctx.ProxyAuthPassword, true, filename)
+			proxyLink, err := createProxyLink(r, link, nil, tunnelType, 12*time.Hour, ctx.ProxyAuthUser, ctx.ProxyAuthPassword, true, filename, ctx.StremId, source)
 			if err != nil {
 				return link, err
 			}
@@ -219,6 +346,19 @@ func ProxyWrapLink(r *http.Request, ctx *storecontext.Context, link string, file
 }
 
 func GenerateStremThruLink(r *http.Request, ctx *storecontext.Context, link string, filename string) (*store.GenerateLinkData, error) {
+	contentId := util.MD5Hash(string(ctx.Store.GetName()) + ":" + link)
+	return generateStremThruLink(r, ctx, link, filename, contentId)
+}
+
+// GenerateStremThruMagnetFileLink is same as GenerateStremThruLink, but
+// `hash` and `file` identify the content, so that the content proxy can share
+// it across users.
+func GenerateStremThruMagnetFileLink(r *http.Request, ctx *storecontext.Context, hash string, file store.File, filename string) (*store.GenerateLinkData, error) {
+	contentId := util.MD5Hash(string(ctx.Store.GetName()) + ":" + strings.ToLower(hash) + ":" + file.GetPath())
+	return generateStremThruLink(r, ctx, file.GetLink(), filename, contentId)
+}
+
+func generateStremThruLink(r *http.Request, ctx *storecontext.Context, link string, filename string, contentId string) (*store.GenerateLinkData, error) {
 	params := &store.GenerateLinkParams{}
 	params.APIKey = ctx.StoreAuthToken
 	params.Link = link
@@ -231,7 +371,11 @@ func GenerateStremThruLink(r *http.Request, ctx *storecontext.Context, link stri
 		return nil, err
 	}
 
-	data.Link, err = ProxyWrapLink(r, ctx, data.Link, filename)
+	data.Link, err = proxyWrapStoreLink(r, ctx, data.Link, filename, &proxyLinkSource{
+		store:     ctx.Store.GetName(),
+		link:      link,
+		contentId: contentId,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -255,32 +399,33 @@ func getUserCredsFromJWT(t *jwt.Token) (user, password string, err error) {
 	return user, password, nil
 }
 
-func UnwrapProxyLinkToken(encodedToken string) (user string, link string, headers map[string]string, tunnelType config.TunnelType, err error) {
+func UnwrapProxyLinkToken(encodedToken string) (*ProxyLink, error) {
 	proxyLink := &proxyLinkData{}
 	if found := proxyLinkTokenCache.Get(encodedToken, proxyLink); found {
-		return proxyLink.User, proxyLink.Value, proxyLink.Headers, proxyLink.TunT, nil
+		return proxyLink.toProxyLink(), nil
 	}
 
 	if encodedBlob, ok := strings.CutPrefix(encodedToken, "base64."); ok {
 		blob, err := util.Base64DecodeToByte(encodedBlob)
 		if err != nil {
-			return "", "", nil, "", err
+			return nil, err
 		}
 		if err := json.Unmarshal(blob, proxyLink); err != nil {
-			return "", "", nil, "", err
+			return nil, err
 		}
 		user, pass, _ := strings.Cut(proxyLink.User, ":")
 		if pass != config.Auth.GetPassword(user) {
 			err := core.NewAPIError("unauthorized")
 			err.StatusCode = http.StatusUnauthorized
-			return "", "", nil, "", err
+			return nil, err
 		}
 		proxyLink.User = user
 	} else {
 		claims := &core.JWTClaims[proxyLinkTokenData]{}
-		password := ""
-		_, err = core.ParseJWT(func(t *jwt.Token) (any, error) {
-			user, password, err = getUserCredsFromJWT(t)
+		user, password := "", ""
+		_, err := core.ParseJWT(func(t *jwt.Token) (any, error) {
+			u, p, err := getUserCredsFromJWT(t)
+			user, password = u, p
 			return []byte(password), err
 		}, encodedToken, claims)
 
@@ -292,20 +437,20 @@ func UnwrapProxyLinkToken(encodedToken string) (user string, link string, header
 				err = rerr
 			}
 
-			return "", "", nil, "", err
+			return nil, err
 		}
 
 		var linkBlob string
 		if claims.Data.EncFormat == "base64" {
 			blob, err := util.Base64Decode(claims.Data.EncLink)
 			if err != nil {
-				return "", "", nil, "", err
+				return nil, err
 			}
 			linkBlob = blob
 		} else {
 			blob, err := core.Decrypt(password, claims.Data.EncLink)
 			if err != nil {
-				return "", "", nil, "", err
+				return nil, err
 			}
 			linkBlob = blob
 		}
@@ -314,8 +459,32 @@ func UnwrapProxyLinkToken(encodedToken string) (user string, link string, header
 
 		proxyLink.User = user
 		proxyLink.TunT = claims.Data.TunnelType
+		proxyLink.SId = claims.Data.StremId
+		proxyLink.St = claims.Data.Store
+		proxyLink.CId = claims.Data.ContentId
+		proxyLink.Base = claims.Data.IsBase
+		proxyLink.Enc = claims.Data.EncFormat != "base64"
+		if claims.ExpiresAt != nil {
+			proxyLink.Exp = claims.ExpiresAt.Unix()
+		}
 		proxyLink.Value = link
 
+		if claims.Data.EncStLink != "" {
+			if claims.Data.EncFormat == "base64" {
+				stLink, err := util.Base64Decode(claims.Data.EncStLink)
+				if err != nil {
+					return nil, err
+				}
+				proxyLink.StLink = stLink
+			} else {
+				stLink, err := core.Decrypt(password, claims.Data.EncStLink)
+				if err != nil {
+					return nil, err
+				}
+				proxyLink.StLink = stLink
+			}
+		}
+
 		if hasHeaders {
 			proxyLink.Headers = map[string]string{}
 			for header := range strings.SplitSeq(headersBlob, "\n") {
@@ -328,5 +497,79 @@ func UnwrapProxyLinkToken(encodedToken string) (user string, link string, header
 
 	proxyLinkTokenCache.Add(encodedToken, *proxyLink)
 
-	return proxyLink.User, proxyLink.Value, proxyLink.Headers, proxyLink.TunT, nil
+	return proxyLink.toProxyLink(), nil
+}
+
+func (d *proxyLinkData) toProxyLink() *ProxyLink {
+	var expiresAt time.Time
+	if d.Exp != 0 {
+		expiresAt = time.Unix(d.Exp, 0)
+	}
+	return &ProxyLink{
+		User:        d.User,
+		Link:        d.Value,
+		Headers:     d.Headers,
+		TunnelType:  d.TunT,
+		StremId:     d.SId,
+		Store:       store.StoreName(d.St),
+		StoreLink:   d.StLink,
+		ContentId:   d.CId,
+		IsBase:      d.Base,
+		ExpiresAt:   expiresAt,
+		IsEncrypted: d.Enc,
+	}
+}
+
+// ResolveBaseLink resolves the path after the token in `r` against the base
+// link, the query of `r` is kept as is.
+func (l *ProxyLink) ResolveBaseLink(r *http.Request, encodedToken string) (string, error) {
+	_, path, _ := strings.Cut(r.URL.EscapedPath(), "/v0/proxy/"+encodedToken+"/")
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment, err := url.PathUnescape(segment); err != nil || segment == ".." {
+			return "", errors.New("invalid path")
+		}
+	}
+	link := l.Link + path
+	if r.URL.RawQuery != "" {
+		link += "?" + r.URL.RawQuery
+	}
+	return link, nil
+}
+
+// RefreshProxyLink regenerates the link from the store, e.g. when the
+// previously generated link has expired. The cached token is updated, so that
+// later requests with the same token use the new link.
+func RefreshProxyLink(encodedToken string, proxyLink *ProxyLink) (string, error) {
+	if proxyLink.Store == "" || proxyLink.StoreLink == "" {
+		return "", errors.New("proxy link can not be refreshed")
+	}
+	s := GetStore(string(proxyLink.Store))
+	if s == nil {
+		return "", errors.New("invalid store: " + string(proxyLink.Store))
+	}
+	storeToken := config.StoreAuthToken.GetToken(proxyLink.User, string(proxyLink.Store))
+	if storeToken == "" {
+		return "", errors.New("missing store token")
+	}
+
+	params := &store.GenerateLinkParams{}
+	params.APIKey = storeToken
+	params.Link = proxyLink.StoreLink
+	if config.StoreTunnel.GetTypeForAPI(string(proxyLink.Store)) == config.TUNNEL_TYPE_NONE {
+		params.ClientIP = config.IP.GetMachineIP()
+	}
+	data, err := s.GenerateLink(params)
+	if err != nil {
+		return "", err
+	}
+
+	proxyLink.Link = data.Link
+
+	cached := proxyLinkData{}
+	if proxyLinkTokenCache.Get(encodedToken, &cached) {
+		cached.Value = data.Link
+		proxyLinkTokenCache.Add(encodedToken, cached)
+	}
+
+	return data.Link, nil
 }
@@ -0,0 +1,418 @@
+package simkl
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/MunifTanjim/stremthru/internal/meta"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+const ListTableName = "simkl_list"
+
+type SimklList struct {
+	Id        string
+	UserId    string
+	UserName  string
+	Type      ListType
+	Status    ListStatus
+	UpdatedAt db.Timestamp
+
+	Items []SimklItem `json:"-"`
+}
+
+func NewListId(userId string, listType ListType, status ListStatus) string {
+	return userId + ":" + string(listType) + ":" + string(status)
+}
+
+func ParseListId(id string) (userId string, listType ListType, status ListStatus, err error) {
+	parts := strings.Split(id, ":")
+	if len(parts) != 3 {
+		return "", "", "", errors.New("invalid list id")
+	}
+	userId, listType, status = parts[0], ListType(parts[1]), ListStatus(parts[2])
+	if userId == "" || listType.ItemType() == "" || !status.IsValid() {
+		return "", "", "", errors.New("invalid list id")
+	}
+	return userId, listType, status, nil
+}
+
+func (l *SimklList) GetURL() string {
+	listType := string(l.Type)
+	if l.Type == ListTypeShows {
+		listType = "tv"
+	}
+	return "https://simkl.com/" + l.UserId + "/" + listType + "/" + string(l.Status)
+}
+
+func (l *SimklList) GetDisplayName() string {
+	name := listStatusName[l.Status]
+	switch l.Type {
+	case ListTypeMovies:
+		name += " Movies"
+	case ListTypeShows:
+		name += " Shows"
+	case ListTypeAnime:
+		name += " Anime"
+	}
+	return name
+}
+
+func (l *SimklList) IsStale() bool {
+	return time.Now().After(l.UpdatedAt.Add(config.Integration.Simkl.ListStaleTime + util.GetRandomDuration(5*time.Second, 5*time.Minute)))
+}
+
+var ListColumn = struct {
+	Id        string
+	UserId    string
+	UserName  string
+	Type      string
+	Status    string
+	UpdatedAt string
+}{
+	Id:        "id",
+	UserId:    "user_id",
+	UserName:  "user_name",
+	Type:      "type",
+	Status:    "status",
+	UpdatedAt: "uat",
+}
+
+var ListColumns = []string{
+	ListColumn.Id,
+	ListColumn.UserId,
+	ListColumn.UserName,
+	ListColumn.Type,
+	ListColumn.Status,
+	ListColumn.UpdatedAt,
+}
+
+const ItemTableName = "simkl_item"
+
+type SimklItem struct {
+	Id        int
+	Type      ItemType
+	Title     string
+	Year      int
+	Runtime   int
+	Poster    string
+	IMDBId    string
+	UpdatedAt db.Timestamp
+
+	Idx         int    `json:"-"`
+	Ids         Ids    `json:"-"`
+	NextToWatch string `json:"-"`
+}
+
+func (item *SimklItem) PosterURL() string {
+	if item.Poster == "" {
+		return ""
+	}
+	return "https://simkl.in/posters/" + item.Poster + "_m.jpg"
+}
+
+var ItemColumn = struct {
+	Id        string
+	Type      string
+	Title     string
+	Year      string
+	Runtime   string
+	Poster    string
+	IMDBId    string
+	UpdatedAt string
+}{
+	Id:        "id",
+	Type:      "type",
+	Title:     "title",
+	Year:      "year",
+	Runtime:   "runtime",
+	Poster:    "poster",
+	IMDBId:    "imdb_id",
+	UpdatedAt: "uat",
+}
+
+var ItemColumns = []string{
+	ItemColumn.Id,
+	ItemColumn.Type,
+	ItemColumn.Title,
+	ItemColumn.Year,
+	ItemColumn.Runtime,
+	ItemColumn.Poster,
+	ItemColumn.IMDBId,
+	ItemColumn.UpdatedAt,
+}
+
+const ListItemTableName = "simkl_list_item"
+
+type SimklListItem struct {
+	ListId string
+	ItemId int
+	Idx    int
+}
+
+var ListItemColumn = struct {
+	ListId string
+	ItemId string
+	Idx    string
+}{
+	ListId: "list_id",
+	ItemId: "item_id",
+	Idx:    "idx",
+}
+
+var ListItemColumns = []string{
+	ListItemColumn.ListId,
+	ListItemColumn.ItemId,
+	ListItemColumn.Idx,
+}
+
+var query_get_list_by_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ?`,
+	db.JoinColumnNames(ListColumns...),
+	ListTableName,
+	ListColumn.Id,
+)
+
+func GetListById(id string) (*SimklList, error) {
+	row := db.QueryRow(query_get_list_by_id, id)
+	list := &SimklList{}
+	if err := row.Scan(
+		&list.Id,
+		&list.UserId,
+		&list.UserName,
+		&list.Type,
+		&list.Status,
+		&list.UpdatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	items, err := GetListItems(id)
+	if err != nil {
+		return nil, err
+	}
+	list.Items = items
+	return list, nil
+}
+
+var query_get_list_items = fmt.Sprintf(
+	`SELECT %s, li.%s FROM %s li JOIN %s i ON i.%s = li.%s WHERE li.%s = ? ORDER BY li.%s ASC`,
+	db.JoinPrefixedColumnNames("i.", ItemColumns...),
+	ListItemColumn.Idx,
+	ListItemTableName,
+	ItemTableName,
+	ItemColumn.Id,
+	ListItemColumn.ItemId,
+	ListItemColumn.ListId,
+	ListItemColumn.Idx,
+)
+
+func GetListItems(listId string) ([]SimklItem, error) {
+	var items []SimklItem
+	rows, err := db.Query(query_get_list_items, listId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item SimklItem
+		if err := rows.Scan(
+			&item.Id,
+			&item.Type,
+			&item.Title,
+			&item.Year,
+			&item.Runtime,
+			&item.Poster,
+			&item.IMDBId,
+			&item.UpdatedAt,
+			&item.Idx,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+var query_upsert_list = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s`,
+	ListTableName,
+	strings.Join(ListColumns[:len(ListColumns)-1], ", "),
+	util.RepeatJoin("?", len(ListColumns)-1, ", "),
+	ListColumn.Id,
+	strings.Join([]string{
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ListColumn.UserId, ListColumn.UserId),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ListColumn.UserName, ListColumn.UserName),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ListColumn.Type, ListColumn.Type),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ListColumn.Status, ListColumn.Status),
+		fmt.Sprintf(`%s = %s`, ListColumn.UpdatedAt, db.CurrentTimestamp),
+	}, ", "),
+)
+
+func UpsertList(list *SimklList) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		tErr := tx.Rollback()
+		err = errors.Join(tErr, err)
+	}()
+
+	_, err = tx.Exec(
+		query_upsert_list,
+		list.Id,
+		list.UserId,
+		list.UserName,
+		list.Type,
+		list.Status,
+	)
+	if err != nil {
+		return err
+	}
+
+	list.UpdatedAt = db.Timestamp{Time: time.Now()}
+
+	err = upsertItems(tx, list.Items)
+	if err != nil {
+		return err
+	}
+
+	err = setListItems(tx, list.Id, list.Items)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var query_upsert_items_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES `,
+	ItemTableName,
+	strings.Join(ItemColumns[:len(ItemColumns)-1], ", "),
+)
+var query_upsert_items_values_placholder = fmt.Sprintf(
+	`(%s)`,
+	util.RepeatJoin("?", len(ItemColumns)-1, ","),
+)
+var query_upsert_items_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s) DO UPDATE SET %s`,
+	ItemColumn.Id,
+	strings.Join([]string{
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Type, ItemColumn.Type),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Title, ItemColumn.Title),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Year, ItemColumn.Year),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Runtime, ItemColumn.Runtime),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.Poster, ItemColumn.Poster),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, ItemColumn.IMDBId, ItemColumn.IMDBId),
+		fmt.Sprintf(`%s = %s`, ItemColumn.UpdatedAt, db.CurrentTimestamp),
+	}, ", "),
+)
+
+func upsertItems(tx db.Executor, items []SimklItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	for cItems := range slices.Chunk(items, 500) {
+		count := len(cItems)
+
+		query := query_upsert_items_before_values +
+			util.RepeatJoin(query_upsert_items_values_placholder, count, ",") +
+			query_upsert_items_after_values
+
+		columnCount := len(ItemColumns) - 1
+		args := make([]any, count*columnCount)
+		for i, item := range cItems {
+			args[i*columnCount+0] = item.Id
+			args[i*columnCount+1] = item.Type
+			args[i*columnCount+2] = item.Title
+			args[i*columnCount+3] = item.Year
+			args[i*columnCount+4] = item.Runtime
+			args[i*columnCount+5] = item.Poster
+			args[i*columnCount+6] = item.IMDBId
+		}
+
+		_, err := tx.Exec(query, args...)
+		if err != nil {
+			return err
+		}
+
+		idMaps := make([]meta.IdMap, 0, count)
+		for _, item := range cItems {
+			if item.IMDBId != "" && item.Type != ItemTypeAnime {
+				idMaps = append(idMaps, item.Ids.ToIdMap(item.Type))
+			}
+		}
+		util.LogError(log, meta.SetIdMapsInTrx(tx, idMaps, meta.IdProviderIMDB), "failed to set id maps")
+	}
+
+	return nil
+}
+
+var query_set_list_item_before_values = fmt.Sprintf(
+	`INSERT INTO %s (%s,%s,%s) VALUES `,
+	ListItemTableName,
+	ListItemColumn.ListId,
+	ListItemColumn.ItemId,
+	ListItemColumn.Idx,
+)
+var query_set_list_item_values_placeholder = `(?,?,?)`
+var query_set_list_item_after_values = fmt.Sprintf(
+	` ON CONFLICT (%s,%s) DO UPDATE SET %s = EXCLUDED.%s`,
+	ListItemColumn.ListId,
+	ListItemColumn.ItemId,
+	ListItemColumn.Idx,
+	ListItemColumn.Idx,
+)
+var query_cleanup_list_item = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	ListItemTableName,
+	ListItemColumn.ListId,
+)
+
+func setListItems(tx db.Executor, listId string, items []SimklItem) error {
+	if _, err := tx.Exec(query_cleanup_list_item, listId); err != nil {
+		return err
+	}
+
+	count := len(items)
+	if count == 0 {
+		return nil
+	}
+
+	for cItems := range slices.Chunk(items, 500) {
+		count := len(cItems)
+		query := query_set_list_item_before_values +
+			util.RepeatJoin(query_set_list_item_values_placeholder, count, ",") +
+			query_set_list_item_after_values
+		args := make([]any, count*3)
+		for i, item := range cItems {
+			args[i*3+0] = listId
+			args[i*3+1] = item.Id
+			args[i*3+2] = item.Idx
+		}
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package simkl
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+)
+
+var listCache = cache.NewCache[SimklList](&cache.CacheConfig{
+	Lifetime: 6 * time.Hour,
+	Name:     "simkl:list",
+	MaxSize:  1024,
+})
+
+var syncListMutex sync.Mutex
+
+// toListItems skips the items without simkl id, and the duplicate ones.
+func toListItems(listType ListType, items []AllItemsItem) []SimklItem {
+	itemType := listType.ItemType()
+
+	listItems := make([]SimklItem, 0, len(items))
+	seenMap := map[int]struct{}{}
+	for i := range items {
+		item := &items[i]
+		media := item.GetMedia()
+		if media == nil || media.Ids.Simkl == 0 {
+			continue
+		}
+
+		if _, seen := seenMap[media.Ids.Simkl]; seen {
+			continue
+		}
+		seenMap[media.Ids.Simkl] = struct{}{}
+
+		listItems = append(listItems, SimklItem{
+			Id:      media.Ids.Simkl,
+			Type:    itemType,
+			Title:   media.Title,
+			Year:    media.Year,
+			Runtime: media.Runtime,
+			Poster:  media.Poster,
+			IMDBId:  media.Ids.IMDB,
+
+			Idx:         i,
+			Ids:         media.Ids,
+			NextToWatch: item.NextToWatch,
+		})
+	}
+	return listItems
+}
+
+func syncList(l *SimklList, tokenId string) error {
+	syncListMutex.Lock()
+	defer syncListMutex.Unlock()
+
+	client := GetAPIClient(tokenId)
+
+	log.Debug("fetching list items", "id", l.Id)
+	res, err := client.GetAllItems(&GetAllItemsParams{
+		Type:   l.Type,
+		Status: l.Status,
+	})
+	if err != nil {
+		return err
+	}
+
+	var items []AllItemsItem
+	switch l.Type {
+	case ListTypeMovies:
+		items = res.Data.Movies
+	case ListTypeShows:
+		items = res.Data.Shows
+	case ListTypeAnime:
+		items = res.Data.Anime
+	}
+
+	l.Items = toListItems(l.Type, items)
+
+	if err := UpsertList(l); err != nil {
+		return err
+	}
+
+	if err := listCache.Add(l.Id, *l); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (l *SimklList) Fetch(tokenId string) error {
+	if l.Id == "" {
+		return errors.New("id must be provided")
+	}
+
+	userId, listType, status, err := ParseListId(l.Id)
+	if err != nil {
+		return err
+	}
+
+	isMissing := false
+
+	var cachedL SimklList
+	if !listCache.Get(l.Id, &cachedL) {
+		if list, err := GetListById(l.Id); err != nil {
+			return err
+		} else if list == nil {
+			isMissing = true
+		} else {
+			*l = *list
+			log.Debug("found list by id", "id", l.Id, "is_stale", l.IsStale())
+			listCache.Add(l.Id, *l)
+		}
+	} else {
+		*l = cachedL
+	}
+
+	if isMissing {
+		l.UserId = userId
+		l.Type = listType
+		l.Status = status
+		return syncList(l, tokenId)
+	}
+
+	if l.IsStale() {
+		staleList := *l
+		go func() {
+			if err := syncList(&staleList, tokenId); err != nil {
+				log.Error("failed to sync stale list", "id", l.Id, "error", err)
+			}
+		}()
+	}
+
+	return nil
+}
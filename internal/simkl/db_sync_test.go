@@ -0,0 +1,58 @@
+package simkl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToListItems(t *testing.T) {
+	items := []AllItemsItem{
+		{
+			NextToWatch: "S01E04",
+			Show: &Media{
+				Title:  "Breaking Bad",
+				Year:   2008,
+				Poster: "74/7415",
+				Ids:    Ids{Simkl: 11121, IMDB: "tt0903747", TVDB: "81189"},
+			},
+		},
+		{Show: &Media{Title: "No Simkl Id", Ids: Ids{IMDB: "tt0000001"}}},
+		{},
+		{Show: &Media{Title: "Breaking Bad", Ids: Ids{Simkl: 11121}}},
+		{Show: &Media{Title: "Better Call Saul", Year: 2015, Ids: Ids{Simkl: 17125}}},
+	}
+
+	listItems := toListItems(ListTypeShows, items)
+	assert.Equal(t, []SimklItem{
+		{
+			Id:          11121,
+			Type:        ItemTypeShow,
+			Title:       "Breaking Bad",
+			Year:        2008,
+			Poster:      "74/7415",
+			IMDBId:      "tt0903747",
+			Idx:         0,
+			Ids:         Ids{Simkl: 11121, IMDB: "tt0903747", TVDB: "81189"},
+			NextToWatch: "S01E04",
+		},
+		{
+			Id:    17125,
+			Type:  ItemTypeShow,
+			Title: "Better Call Saul",
+			Year:  2015,
+			Idx:   4,
+			Ids:   Ids{Simkl: 17125},
+		},
+	}, listItems)
+
+	assert.Equal(t, "https://simkl.in/posters/74/7415_m.jpg", listItems[0].PosterURL())
+	assert.Equal(t, "", listItems[1].PosterURL())
+
+	movieItems := toListItems(ListTypeMovies, []AllItemsItem{
+		{Movie: &Media{Title: "Inception", Ids: Ids{Simkl: 1}}},
+	})
+	if assert.Len(t, movieItems, 1) {
+		assert.Equal(t, ItemTypeMovie, movieItems[0].Type)
+	}
+}
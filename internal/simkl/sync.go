@@ -0,0 +1,231 @@
+package simkl
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/meta"
+	"github.com/MunifTanjim/stremthru/internal/request"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+type ItemType string
+
+const (
+	ItemTypeMovie ItemType = "movie"
+	ItemTypeShow  ItemType = "show"
+	ItemTypeAnime ItemType = "anime"
+)
+
+type ListType string
+
+const (
+	ListTypeMovies ListType = "movies"
+	ListTypeShows  ListType = "shows"
+	ListTypeAnime  ListType = "anime"
+)
+
+func (lt ListType) ItemType() ItemType {
+	switch lt {
+	case ListTypeMovies:
+		return ItemTypeMovie
+	case ListTypeShows:
+		return ItemTypeShow
+	case ListTypeAnime:
+		return ItemTypeAnime
+	default:
+		return ""
+	}
+}
+
+type ListStatus string
+
+const (
+	ListStatusPlanToWatch ListStatus = "plantowatch"
+	ListStatusWatching    ListStatus = "watching"
+	ListStatusCompleted   ListStatus = "completed"
+	ListStatusHold        ListStatus = "hold"
+	ListStatusDropped     ListStatus = "dropped"
+)
+
+var listStatusName = map[ListStatus]string{
+	ListStatusPlanToWatch: "Plan to Watch",
+	ListStatusWatching:    "Watching",
+	ListStatusCompleted:   "Completed",
+	ListStatusHold:        "On Hold",
+	ListStatusDropped:     "Dropped",
+}
+
+func (ls ListStatus) IsValid() bool {
+	_, ok := listStatusName[ls]
+	return ok
+}
+
+type Ids struct {
+	Simkl int             `json:"simkl,omitempty"`
+	Slug  string          `json:"slug,omitempty"`
+	IMDB  string          `json:"imdb,omitempty"`
+	TMDB  util.JSONNumber `json:"tmdb,omitempty"`
+	TVDB  util.JSONNumber `json:"tvdb,omitempty"`
+	MAL   util.JSONNumber `json:"mal,omitempty"`
+	AniDB util.JSONNumber `json:"anidb,omitempty"`
+}
+
+func (ids Ids) ToIdMap(itemType ItemType) meta.IdMap {
+	idMapType := meta.IdTypeUnknown
+	switch itemType {
+	case ItemTypeMovie:
+		idMapType = meta.IdTypeMovie
+	case ItemTypeShow:
+		idMapType = meta.IdTypeShow
+	}
+
+	idMap := meta.IdMap{
+		Type: idMapType,
+		IMDB: ids.IMDB,
+	}
+	if ids.TMDB != "" && ids.TMDB != "0" {
+		idMap.TMDB = string(ids.TMDB)
+	}
+	if ids.TVDB != "" && ids.TVDB != "0" {
+		idMap.TVDB = string(ids.TVDB)
+	}
+	return idMap
+}
+
+type Media struct {
+	Title     string `json:"title"`
+	Poster    string `json:"poster"`
+	Year      int    `json:"year"`
+	Runtime   int    `json:"runtime"`
+	Ids       Ids    `json:"ids"`
+	AnimeType string `json:"anime_type,omitempty"` // tv / movie / ova / ona / special / music video
+}
+
+type WatchedEpisode struct {
+	Number    int        `json:"number"`
+	WatchedAt *time.Time `json:"watched_at,omitempty"`
+}
+
+type WatchedSeason struct {
+	Number   int              `json:"number"`
+	Episodes []WatchedEpisode `json:"episodes,omitempty"`
+}
+
+type AllItemsItem struct {
+	AddedToWatchlistAt *time.Time      `json:"added_to_watchlist_at"`
+	LastWatchedAt      *time.Time      `json:"last_watched_at"`
+	UserRatedAt        *time.Time      `json:"user_rated_at"`
+	UserRating         int             `json:"user_rating"`
+	Status             ListStatus      `json:"status"`
+	LastWatched        string          `json:"last_watched,omitempty"`  // S01E03
+	NextToWatch        string          `json:"next_to_watch,omitempty"` // S01E04
+	WatchedEpisodes    int             `json:"watched_episodes_count,omitempty"`
+	TotalEpisodes      int             `json:"total_episodes_count,omitempty"`
+	AnimeType          string          `json:"anime_type,omitempty"`
+	Movie              *Media          `json:"movie,omitempty"`
+	Show               *Media          `json:"show,omitempty"`
+	Seasons            []WatchedSeason `json:"seasons,omitempty"`
+}
+
+func (item *AllItemsItem) GetMedia() *Media {
+	if item.Movie != nil {
+		return item.Movie
+	}
+	return item.Show
+}
+
+type GetAllItemsData struct {
+	ResponseError
+	Movies []AllItemsItem `json:"movies"`
+	Shows  []AllItemsItem `json:"shows"`
+	Anime  []AllItemsItem `json:"anime"`
+}
+
+type GetAllItemsParams struct {
+	Ctx
+	Type     ListType
+	Status   ListStatus
+	DateFrom *time.Time
+	// include `watched_at` for episodes
+	EpisodeWatchedAt bool
+}
+
+func (c APIClient) GetAllItems(params *GetAllItemsParams) (request.APIResponse[GetAllItemsData], error) {
+	path := "/sync/all-items"
+	if params.Type != "" {
+		path += "/" + string(params.Type)
+		if params.Status != "" {
+			path += "/" + string(params.Status)
+		}
+	}
+
+	params.Query = &url.Values{}
+	params.Query.Set("extended", "full")
+	if params.EpisodeWatchedAt {
+		params.Query.Set("episode_watched_at", "yes")
+	}
+	if params.DateFrom != nil {
+		params.Query.Set("date_from", params.DateFrom.UTC().Format(time.RFC3339))
+	}
+
+	response := GetAllItemsData{}
+	res, err := c.Request("GET", path, params, &response)
+	return request.NewAPIResponse(res, response), err
+}
+
+type SyncHistoryParamsItem struct {
+	Title     string     `json:"title,omitempty"`
+	Year      int        `json:"year,omitempty"`
+	WatchedAt *time.Time `json:"watched_at,omitempty"`
+	Ids       Ids        `json:"ids"`
+}
+
+type SyncHistoryShow struct {
+	SyncHistoryParamsItem
+	Seasons []WatchedSeason `json:"seasons,omitempty"`
+}
+
+type AddToHistoryData struct {
+	ResponseError
+	Added struct {
+		Movies   int `json:"movies"`
+		Shows    int `json:"shows"`
+		Episodes int `json:"episodes"`
+	} `json:"added"`
+	NotFound struct {
+		Movies []SyncHistoryParamsItem `json:"movies"`
+		Shows  []SyncHistoryParamsItem `json:"shows"`
+	} `json:"not_found"`
+}
+
+type AddToHistoryParams struct {
+	Ctx
+	Movies []SyncHistoryParamsItem `json:"movies,omitempty"`
+	Shows  []SyncHistoryShow       `json:"shows,omitempty"`
+}
+
+func (c APIClient) AddToHistory(params *AddToHistoryParams) (request.APIResponse[AddToHistoryData], error) {
+	params.JSON = params
+	response := AddToHistoryData{}
+	res, err := c.Request("POST", "/sync/history", params, &response)
+	return request.NewAPIResponse(res, response), err
+}
+
+// ParseEpisodeCode parses episode code in `S01E03` format.
+func ParseEpisodeCode(code string) (season, episode int, ok bool) {
+	rest, ok := strings.CutPrefix(strings.ToUpper(code), "S")
+	if !ok {
+		return 0, 0, false
+	}
+	sStr, eStr, ok := strings.Cut(rest, "E")
+	if !ok {
+		return 0, 0, false
+	}
+	season, episode = util.SafeParseInt(sStr, -1), util.SafeParseInt(eStr, -1)
+	if season < 0 || episode < 0 {
+		return 0, 0, false
+	}
+	return season, episode, true
+}
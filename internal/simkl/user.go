@@ -0,0 +1,31 @@
+package simkl
+
+import "github.com/MunifTanjim/stremthru/internal/request"
+
+type RetrieveSettingsData struct {
+	ResponseError
+	User struct {
+		Name     string `json:"name"`
+		JoinedAt string `json:"joined_at"`
+		Gender   string `json:"gender"`
+		Avatar   string `json:"avatar"`
+		Bio      string `json:"bio"`
+		Loc      string `json:"loc"`
+		Age      string `json:"age"`
+	} `json:"user"`
+	Account struct {
+		Id       int64  `json:"id"`
+		Timezone string `json:"timezone"`
+		Type     string `json:"type"` // free / vip
+	} `json:"account"`
+}
+
+type RetrieveSettingsParams struct {
+	Ctx
+}
+
+func (c APIClient) RetrieveSettings(params *RetrieveSettingsParams) (request.APIResponse[RetrieveSettingsData], error) {
+	response := RetrieveSettingsData{}
+	res, err := c.Request("POST", "/users/settings", params, &response)
+	return request.NewAPIResponse(res, response), err
+}
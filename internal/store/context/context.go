@@ -16,6 +16,7 @@ type Context struct {
 	ProxyAuthUser     string
 	ProxyAuthPassword string
 	ClientIP          string // optional
+	StremId           string // optional
 }
 
 func Set(r *http.Request) *http.Request {
@@ -122,3 +122,16 @@ func EnsureNewzStore(next http.HandlerFunc) http.HandlerFunc {
 		next.ServeHTTP(w, r)
 	})
 }
+
+func EnsureWebDLStore(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := storecontext.Get(r)
+
+		if _, ok := ctx.Store.(store.WebDLStore); !ok {
+			server.ErrorBadRequest(r).WithMessage("store does not support webdl").Send(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
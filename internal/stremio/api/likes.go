@@ -0,0 +1,85 @@
+package stremio_api
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/MunifTanjim/stremthru/internal/request"
+)
+
+var likesBaseURL = "https://likes.stremio.com"
+
+type LikeStatus string
+
+const (
+	LikeStatusNone  LikeStatus = ""
+	LikeStatusLiked LikeStatus = "liked"
+	LikeStatusLoved LikeStatus = "loved"
+)
+
+type likesResponse[D any] struct {
+	data D
+}
+
+func (r likesResponse[D]) GetError() error {
+	return nil
+}
+
+func (r *likesResponse[D]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &r.data)
+}
+
+type GetLikeStatusData struct {
+	Status LikeStatus `json:"status"`
+}
+
+type GetLikeStatusParams struct {
+	Ctx
+	MediaId   string
+	MediaType string // movie / series
+}
+
+func (c Client) GetLikeStatus(params *GetLikeStatusParams) (request.APIResponse[GetLikeStatusData], error) {
+	params.Query = &url.Values{}
+	params.Query.Set("authToken", params.APIKey)
+	params.Query.Set("mediaId", params.MediaId)
+	params.Query.Set("mediaType", params.MediaType)
+
+	response := &likesResponse[GetLikeStatusData]{}
+	res, err := c.Request("GET", likesBaseURL+"/api/get_status", params, response)
+	return request.NewAPIResponse(res, response.data), err
+}
+
+type sendLikeStatusPayload struct {
+	AuthToken string      `json:"authToken"`
+	MediaId   string      `json:"mediaId"`
+	MediaType string      `json:"mediaType"`
+	Status    *LikeStatus `json:"status"`
+}
+
+type SendLikeStatusData struct {
+	Success bool `json:"success"`
+}
+
+type SendLikeStatusParams struct {
+	Ctx
+	MediaId   string
+	MediaType string // movie / series
+	Status    LikeStatus
+}
+
+func (c Client) SendLikeStatus(params *SendLikeStatusParams) (request.APIResponse[SendLikeStatusData], error) {
+	payload := sendLikeStatusPayload{
+		AuthToken: params.APIKey,
+		MediaId:   params.MediaId,
+		MediaType: params.MediaType,
+	}
+	if params.Status != LikeStatusNone {
+		payload.Status = &params.Status
+	}
+	params.JSON = payload
+
+	response := &likesResponse[SendLikeStatusData]{}
+	res, err := c.Request("POST", likesBaseURL+"/api/send", params, response)
+	return request.NewAPIResponse(res, response.data), err
+}
@@ -10,12 +10,16 @@ import (
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/anilist"
+	"github.com/MunifTanjim/stremthru/internal/anime"
 	"github.com/MunifTanjim/stremthru/internal/db"
 	"github.com/MunifTanjim/stremthru/internal/imdb_title"
+	"github.com/MunifTanjim/stremthru/internal/kitsu"
 	"github.com/MunifTanjim/stremthru/internal/letterboxd"
+	"github.com/MunifTanjim/stremthru/internal/mal"
 	"github.com/MunifTanjim/stremthru/internal/mdblist"
 	"github.com/MunifTanjim/stremthru/internal/meta"
 	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
 	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
 	"github.com/MunifTanjim/stremthru/internal/tmdb"
 	"github.com/MunifTanjim/stremthru/internal/trakt"
@@ -320,6 +324,119 @@ func getTMDBIdsForIMDBIds(tokenId string, imdbIds []string) (map[string]string,
 	return tmdbIdByImdbId, nil
 }
 
+func getAnimeMetaId(idMap *anime.AnimeIdMap, metaIdAnime string) string {
+	switch metaIdAnime {
+	case "mal":
+		if idMap.MAL != "" {
+			return "mal:" + idMap.MAL
+		}
+	case "anilist":
+		if idMap.AniList != "" {
+			return "anilist:" + idMap.AniList
+		}
+	case "anidb":
+		if idMap.AniDB != "" {
+			return "anidb:" + idMap.AniDB
+		}
+	}
+	if idMap.Kitsu != "" {
+		return "kitsu:" + idMap.Kitsu
+	}
+	return ""
+}
+
+// getAnimeListItemMetaId falls back to the `{service}:{id}` id, if the item
+// is not mapped yet.
+func getAnimeListItemMetaId(idMap *anime.AnimeIdMap, metaIdAnime string, service, id string) string {
+	if idMap != nil {
+		if metaId := getAnimeMetaId(idMap, metaIdAnime); metaId != "" {
+			return metaId
+		}
+	}
+	return service + ":" + id
+}
+
+func getKitsuItemMetaPreview(item *kitsu.KitsuItem) stremio.MetaPreview {
+	meta := stremio.MetaPreview{
+		Name:        item.Title,
+		Description: item.Description,
+		Poster:      item.Poster,
+		Background:  item.Background,
+		PosterShape: stremio.MetaPosterShapePoster,
+	}
+	switch item.Type {
+	case kitsu.AnimeSubtypeMovie:
+		meta.Type = stremio.ContentTypeMovie
+	case kitsu.AnimeSubtypeTV, kitsu.AnimeSubtypeOVA, kitsu.AnimeSubtypeONA:
+		meta.Type = stremio.ContentTypeSeries
+	default:
+		meta.Type = "anime"
+	}
+	if item.StartYear > 0 {
+		meta.ReleaseInfo = strconv.Itoa(item.StartYear)
+	}
+	return meta
+}
+
+func getMALItemMetaPreview(item *mal.MALItem) stremio.MetaPreview {
+	meta := stremio.MetaPreview{
+		Type:        stremio.ContentType(item.Type.ToSimple()),
+		Name:        item.Title,
+		Description: item.Description,
+		Poster:      item.Poster,
+		PosterShape: stremio.MetaPosterShapePoster,
+		Genres:      item.Genres,
+	}
+	if meta.Type == "" {
+		meta.Type = "anime"
+	}
+	if item.StartYear > 0 {
+		meta.ReleaseInfo = strconv.Itoa(item.StartYear)
+	}
+	return meta
+}
+
+// getSimklItemMetaPreview returns false if the item does not belong to the
+// catalog type.
+func getSimklItemMetaPreview(item *simkl.SimklItem, catalogType string) (stremio.MetaPreview, bool) {
+	meta := stremio.MetaPreview{
+		Name:        item.Title,
+		Poster:      item.PosterURL(),
+		PosterShape: stremio.MetaPosterShapePoster,
+	}
+	switch item.Type {
+	case simkl.ItemTypeMovie:
+		if catalogType == string(stremio.ContentTypeSeries) {
+			return meta, false
+		}
+		meta.Type = stremio.ContentTypeMovie
+	case simkl.ItemTypeShow, simkl.ItemTypeAnime:
+		if catalogType == string(stremio.ContentTypeMovie) {
+			return meta, false
+		}
+		meta.Type = stremio.ContentTypeSeries
+	default:
+		return meta, false
+	}
+	if item.Year > 0 {
+		meta.ReleaseInfo = strconv.Itoa(item.Year)
+	}
+	return meta, true
+}
+
+// getSimklItemDefaultVideoId returns the `{imdbId}:{season}:{episode}` video
+// id for the next episode to watch.
+func getSimklItemDefaultVideoId(item *simkl.SimklItem) string {
+	if item.IMDBId == "" || item.NextToWatch == "" {
+		return ""
+	}
+	season, episode, ok := simkl.ParseEpisodeCode(item.NextToWatch)
+	if !ok {
+		return ""
+	}
+	return item.IMDBId + ":" + strconv.Itoa(season) + ":" + strconv.Itoa(episode)
+}
+
 type catalogItem struct {
 	stremio.MetaPreview
 	item any
@@ -379,6 +496,18 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 			catalogItems = append(catalogItems, catalogItem{meta, *media})
 		}
 
+	case "kitsu":
+		list := kitsu.KitsuList{Id: id}
+		if err := ud.FetchKitsuList(&list, false); err != nil {
+			SendError(w, r, err)
+			return
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			catalogItems = append(catalogItems, catalogItem{getKitsuItemMetaPreview(item), item})
+		}
+
 	case "letterboxd":
 		list := letterboxd.LetterboxdList{Id: id}
 		if err := ud.FetchLetterboxdList(&list); err != nil {
@@ -399,6 +528,18 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 			catalogItems = append(catalogItems, catalogItem{meta, item})
 		}
 
+	case "mal":
+		list := mal.MALList{Id: id}
+		if err := ud.FetchMALList(&list, false); err != nil {
+			SendError(w, r, err)
+			return
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			catalogItems = append(catalogItems, catalogItem{getMALItemMetaPreview(item), item})
+		}
+
 	case "mdblist":
 		list := mdblist.MDBListList{Id: id}
 		if err := ud.FetchMDBListList(&list); err != nil {
@@ -516,6 +657,20 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 			catalogItems = append(catalogItems, catalogItem{meta, item})
 		}
 
+	case "simkl":
+		list := simkl.SimklList{Id: id}
+		if err := ud.FetchSimklList(&list); err != nil {
+			SendError(w, r, err)
+			return
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			if meta, ok := getSimklItemMetaPreview(item, catalogType); ok {
+				catalogItems = append(catalogItems, catalogItem{meta, item})
+			}
+		}
+
 	case "tvdb":
 		list := tvdb.TVDBList{Id: id}
 		if err := ud.FetchTVDBList(&list); err != nil {
@@ -604,23 +759,7 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 				continue
 			}
 
-			switch ud.MetaIdAnime {
-			case "mal":
-				if media.IdMap.MAL != "" {
-					item.Id = "mal:" + media.IdMap.MAL
-				}
-			case "anilist":
-				if media.IdMap.AniList != "" {
-					item.Id = "anilist:" + media.IdMap.AniList
-				}
-			case "anidb":
-				if media.IdMap.AniDB != "" {
-					item.Id = "anidb:" + media.IdMap.AniDB
-				}
-			}
-			if item.Id == "" && media.IdMap.Kitsu != "" {
-				item.Id = "kitsu:" + media.IdMap.Kitsu
-			}
+			item.Id = getAnimeMetaId(media.IdMap, ud.MetaIdAnime)
 			if item.Id == "" {
 				continue
 			}
@@ -632,6 +771,36 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 			items = append(items, item.MetaPreview)
 		}
 
+	case "kitsu":
+		kitsuIds := make([]int, len(catalogItems))
+		for i := range catalogItems {
+			kitsuIds[i] = catalogItems[i].item.(*kitsu.KitsuItem).Id
+		}
+		idMaps, err := anime.GetIdMapsForKitsu(kitsuIds)
+		if err != nil {
+			SendError(w, r, err)
+			return
+		}
+		idMapByKitsuId := make(map[string]*anime.AnimeIdMap, len(idMaps))
+		for i := range idMaps {
+			idMap := &idMaps[i]
+			idMapByKitsuId[idMap.Kitsu] = idMap
+		}
+
+		for i := range catalogItems {
+			item := &catalogItems[i]
+			kitsuId := strconv.Itoa(kitsuIds[i])
+
+			idMap, ok := idMapByKitsuId[kitsuId]
+			item.Id = getAnimeListItemMetaId(idMap, ud.MetaIdAnime, "kitsu", kitsuId)
+
+			if ok && posterBaseUrl != "" && idMap.IMDB != "" {
+				item.Poster = posterBaseUrl + idMap.IMDB + ".jpg" + posterQueryParams
+			}
+
+			items = append(items, item.MetaPreview)
+		}
+
 	case "letterboxd":
 		letterboxdIds := []string{}
 		for i := range catalogItems {
@@ -670,6 +839,36 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 			items = append(items, item.MetaPreview)
 		}
 
+	case "mal":
+		malIds := make([]int, len(catalogItems))
+		for i := range catalogItems {
+			malIds[i] = catalogItems[i].item.(*mal.MALItem).Id
+		}
+		idMaps, err := anime.GetIdMapsForMAL(malIds)
+		if err != nil {
+			SendError(w, r, err)
+			return
+		}
+		idMapByMALId := make(map[string]*anime.AnimeIdMap, len(idMaps))
+		for i := range idMaps {
+			idMap := &idMaps[i]
+			idMapByMALId[idMap.MAL] = idMap
+		}
+
+		for i := range catalogItems {
+			item := &catalogItems[i]
+			malId := strconv.Itoa(malIds[i])
+
+			idMap, ok := idMapByMALId[malId]
+			item.Id = getAnimeListItemMetaId(idMap, ud.MetaIdAnime, "mal", malId)
+
+			if ok && posterBaseUrl != "" && idMap.IMDB != "" {
+				item.Poster = posterBaseUrl + idMap.IMDB + ".jpg" + posterQueryParams
+			}
+
+			items = append(items, item.MetaPreview)
+		}
+
 	case "mdblist":
 		imdbIds := []string{}
 		for i := range catalogItems {
@@ -798,6 +997,29 @@ func handleCatalog(w http.ResponseWriter, r *http.Request) {
 			items = append(items, item.MetaPreview)
 		}
 
+	case "simkl":
+		for i := range catalogItems {
+			item := &catalogItems[i]
+			sitem := item.item.(*simkl.SimklItem)
+			imdbId := sitem.IMDBId
+			if imdbId == "" {
+				continue
+			}
+
+			if videoId := getSimklItemDefaultVideoId(sitem); videoId != "" {
+				item.BehaviorHints = &stremio.MetaBehaviorHints{
+					DefaultVideoId: videoId,
+				}
+			}
+
+			item.MetaPreview.Id = imdbId
+			if posterBaseUrl != "" {
+				item.MetaPreview.Poster = posterBaseUrl + imdbId + ".jpg" + posterQueryParams
+			}
+
+			items = append(items, item.MetaPreview)
+		}
+
 	case "tvdb":
 		tvdbMovieIds := make([]string, 0, len(catalogItems))
 		tvdbShowIds := make([]string, 0, len(catalogItems))
@@ -0,0 +1,181 @@
+package stremio_list
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/anime"
+	"github.com/MunifTanjim/stremthru/internal/kitsu"
+	"github.com/MunifTanjim/stremthru/internal/mal"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
+	"github.com/MunifTanjim/stremthru/stremio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSimklItemMetaPreview(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		itemType    simkl.ItemType
+		catalogType string
+		ok          bool
+		metaType    stremio.ContentType
+	}{
+		{"movie in movie catalog", simkl.ItemTypeMovie, "movie", true, stremio.ContentTypeMovie},
+		{"movie in series catalog", simkl.ItemTypeMovie, "series", false, ""},
+		{"movie in other catalog", simkl.ItemTypeMovie, "other", true, stremio.ContentTypeMovie},
+		{"show in series catalog", simkl.ItemTypeShow, "series", true, stremio.ContentTypeSeries},
+		{"show in movie catalog", simkl.ItemTypeShow, "movie", false, ""},
+		{"anime in series catalog", simkl.ItemTypeAnime, "series", true, stremio.ContentTypeSeries},
+		{"anime in movie catalog", simkl.ItemTypeAnime, "movie", false, ""},
+		{"unknown type", "", "series", false, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			meta, ok := getSimklItemMetaPreview(&simkl.SimklItem{
+				Type:   tc.itemType,
+				Title:  "Title",
+				Year:   2008,
+				Poster: "74/7415",
+			}, tc.catalogType)
+			assert.Equal(t, tc.ok, ok)
+			if ok {
+				assert.Equal(t, stremio.MetaPreview{
+					Type:        tc.metaType,
+					Name:        "Title",
+					Poster:      "https://simkl.in/posters/74/7415_m.jpg",
+					PosterShape: stremio.MetaPosterShapePoster,
+					ReleaseInfo: "2008",
+				}, meta)
+			}
+		})
+	}
+
+	meta, ok := getSimklItemMetaPreview(&simkl.SimklItem{Type: simkl.ItemTypeMovie, Title: "Title"}, "movie")
+	assert.True(t, ok)
+	assert.Empty(t, meta.ReleaseInfo)
+	assert.Empty(t, meta.Poster)
+}
+
+func TestGetSimklItemDefaultVideoId(t *testing.T) {
+	for _, tc := range []struct {
+		imdbId      string
+		nextToWatch string
+		videoId     string
+	}{
+		{"tt0903747", "S01E04", "tt0903747:1:4"},
+		{"tt0903747", "s02e10", "tt0903747:2:10"},
+		{"tt0903747", "S00E01", "tt0903747:0:1"},
+		{"tt0903747", "", ""},
+		{"tt0903747", "E04", ""},
+		{"", "S01E04", ""},
+	} {
+		assert.Equal(t, tc.videoId, getSimklItemDefaultVideoId(&simkl.SimklItem{
+			IMDBId:      tc.imdbId,
+			NextToWatch: tc.nextToWatch,
+		}), "%s %s", tc.imdbId, tc.nextToWatch)
+	}
+}
+
+func TestGetAnimeListItemMetaId(t *testing.T) {
+	fullIdMap := &anime.AnimeIdMap{AniDB: "69", AniList: "21", Kitsu: "12", MAL: "21"}
+	kitsuOnlyIdMap := &anime.AnimeIdMap{Kitsu: "12"}
+	emptyIdMap := &anime.AnimeIdMap{}
+
+	for _, tc := range []struct {
+		name        string
+		idMap       *anime.AnimeIdMap
+		metaIdAnime string
+		service     string
+		id          string
+		metaId      string
+	}{
+		{"mal", fullIdMap, "mal", "kitsu", "12", "mal:21"},
+		{"anilist", fullIdMap, "anilist", "mal", "21", "anilist:21"},
+		{"anidb", fullIdMap, "anidb", "mal", "21", "anidb:69"},
+		{"kitsu", fullIdMap, "kitsu", "mal", "21", "kitsu:12"},
+		{"empty meta id", fullIdMap, "", "mal", "21", "kitsu:12"},
+		{"unknown meta id", fullIdMap, "unknown", "mal", "21", "kitsu:12"},
+		{"missing preferred id", kitsuOnlyIdMap, "anidb", "mal", "21", "kitsu:12"},
+		{"empty id map for mal", emptyIdMap, "mal", "mal", "21", "mal:21"},
+		{"empty id map for kitsu", emptyIdMap, "", "kitsu", "12", "kitsu:12"},
+		{"unmapped mal", nil, "anilist", "mal", "21", "mal:21"},
+		{"unmapped kitsu", nil, "", "kitsu", "12", "kitsu:12"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.metaId, getAnimeListItemMetaId(tc.idMap, tc.metaIdAnime, tc.service, tc.id))
+		})
+	}
+}
+
+func TestGetMALItemMetaPreview(t *testing.T) {
+	for _, tc := range []struct {
+		mediaType mal.MediaType
+		metaType  stremio.ContentType
+	}{
+		{mal.MediaTypeTV, stremio.ContentTypeSeries},
+		{mal.MediaTypeONA, stremio.ContentTypeSeries},
+		{mal.MediaTypeMovie, stremio.ContentTypeMovie},
+		{mal.MediaTypeTVSpecial, stremio.ContentTypeMovie},
+		{mal.MediaTypeMusic, "anime"},
+		{mal.MediaTypeUnknown, "anime"},
+	} {
+		t.Run(string(tc.mediaType), func(t *testing.T) {
+			assert.Equal(t, stremio.MetaPreview{
+				Type:        tc.metaType,
+				Name:        "One Piece",
+				Description: "Pirates.",
+				Poster:      "poster",
+				PosterShape: stremio.MetaPosterShapePoster,
+				Genres:      []string{"Action"},
+				ReleaseInfo: "1999",
+			}, getMALItemMetaPreview(&mal.MALItem{
+				Id:          21,
+				Type:        tc.mediaType,
+				Title:       "One Piece",
+				Description: "Pirates.",
+				Poster:      "poster",
+				StartYear:   1999,
+				Genres:      []string{"Action"},
+			}))
+		})
+	}
+
+	meta := getMALItemMetaPreview(&mal.MALItem{Type: mal.MediaTypeTV})
+	assert.Empty(t, meta.ReleaseInfo)
+}
+
+func TestGetKitsuItemMetaPreview(t *testing.T) {
+	for _, tc := range []struct {
+		subtype  kitsu.AnimeSubtype
+		metaType stremio.ContentType
+	}{
+		{kitsu.AnimeSubtypeTV, stremio.ContentTypeSeries},
+		{kitsu.AnimeSubtypeOVA, stremio.ContentTypeSeries},
+		{kitsu.AnimeSubtypeONA, stremio.ContentTypeSeries},
+		{kitsu.AnimeSubtypeMovie, stremio.ContentTypeMovie},
+		{kitsu.AnimeSubtypeSpecial, "anime"},
+		{kitsu.AnimeSubtypeMusic, "anime"},
+		{"", "anime"},
+	} {
+		t.Run(string(tc.subtype), func(t *testing.T) {
+			assert.Equal(t, stremio.MetaPreview{
+				Type:        tc.metaType,
+				Name:        "One Piece",
+				Description: "Pirates.",
+				Poster:      "poster",
+				Background:  "background",
+				PosterShape: stremio.MetaPosterShapePoster,
+				ReleaseInfo: "1999",
+			}, getKitsuItemMetaPreview(&kitsu.KitsuItem{
+				Id:          12,
+				Type:        tc.subtype,
+				Title:       "One Piece",
+				Description: "Pirates.",
+				Poster:      "poster",
+				Background:  "background",
+				StartYear:   1999,
+			}))
+		})
+	}
+
+	meta := getKitsuItemMetaPreview(&kitsu.KitsuItem{Type: kitsu.AnimeSubtypeTV})
+	assert.Empty(t, meta.ReleaseInfo)
+}
@@ -7,9 +7,12 @@ import (
 	"github.com/MunifTanjim/stremthru/core"
 	"github.com/MunifTanjim/stremthru/internal/anilist"
 	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/kitsu"
 	"github.com/MunifTanjim/stremthru/internal/letterboxd"
+	"github.com/MunifTanjim/stremthru/internal/mal"
 	"github.com/MunifTanjim/stremthru/internal/mdblist"
 	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
 	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
 	"github.com/MunifTanjim/stremthru/internal/tmdb"
 	"github.com/MunifTanjim/stremthru/internal/trakt"
@@ -78,6 +81,33 @@ func GetManifest(r *http.Request, ud *UserData) (*stremio.Manifest, error) {
 				}
 				catalogs = append(catalogs, catalog)
 
+			case "kitsu":
+				list := kitsu.KitsuList{Id: idStr}
+				if err := ud.FetchKitsuList(&list, false); err != nil {
+					return nil, err
+				}
+				catalog := stremio.Catalog{
+					Type: "anime",
+					Id:   "st.list.kitsu." + idStr,
+					Name: list.GetDisplayName(),
+					Extra: []stremio.CatalogExtra{
+						{
+							Name: "skip",
+						},
+					},
+				}
+				if hasListNames {
+					if name := ud.ListNames[idx]; name != "" {
+						catalog.Name = name
+					}
+				}
+				if hasListTypes {
+					if listType := ud.ListTypes[idx]; listType != "" {
+						catalog.Type = listType
+					}
+				}
+				catalogs = append(catalogs, catalog)
+
 			case "letterboxd":
 				list := &letterboxd.LetterboxdList{Id: idStr}
 				if err := ud.FetchLetterboxdList(list); err != nil {
@@ -112,6 +142,37 @@ func GetManifest(r *http.Request, ud *UserData) (*stremio.Manifest, error) {
 				}
 				catalogs = append(catalogs, catalog)
 
+			case "mal":
+				list := mal.MALList{Id: idStr}
+				if err := ud.FetchMALList(&list, false); err != nil {
+					return nil, err
+				}
+				catalog := stremio.Catalog{
+					Type: "anime",
+					Id:   "st.list.mal." + idStr,
+					Name: list.GetDisplayName(),
+					Extra: []stremio.CatalogExtra{
+						{
+							Name:    "genre",
+							Options: mal.Genres,
+						},
+						{
+							Name: "skip",
+						},
+					},
+				}
+				if hasListNames {
+					if name := ud.ListNames[idx]; name != "" {
+						catalog.Name = name
+					}
+				}
+				if hasListTypes {
+					if listType := ud.ListTypes[idx]; listType != "" {
+						catalog.Type = listType
+					}
+				}
+				catalogs = append(catalogs, catalog)
+
 			case "mdblist":
 				list := mdblist.MDBListList{Id: idStr}
 				if err := list.Fetch(ud.MDBListAPIkey); err != nil {
@@ -251,6 +312,39 @@ func GetManifest(r *http.Request, ud *UserData) (*stremio.Manifest, error) {
 				}
 				catalogs = append(catalogs, catalog)
 
+			case "simkl":
+				list := &simkl.SimklList{Id: idStr}
+				if err := ud.FetchSimklList(list); err != nil {
+					return nil, err
+				}
+				catalog := stremio.Catalog{
+					Type: "Simkl",
+					Id:   "st.list.simkl." + idStr,
+					Name: list.GetDisplayName(),
+					Extra: []stremio.CatalogExtra{
+						{
+							Name: "skip",
+						},
+					},
+				}
+				switch list.Type {
+				case simkl.ListTypeMovies:
+					catalog.Type = string(stremio.ContentTypeMovie)
+				case simkl.ListTypeShows, simkl.ListTypeAnime:
+					catalog.Type = string(stremio.ContentTypeSeries)
+				}
+				if hasListNames {
+					if name := ud.ListNames[idx]; name != "" {
+						catalog.Name = name
+					}
+				}
+				if hasListTypes {
+					if listType := ud.ListTypes[idx]; listType != "" {
+						catalog.Type = listType
+					}
+				}
+				catalogs = append(catalogs, catalog)
+
 			case "tvdb":
 				list := tvdb.TVDBList{Id: idStr}
 				if err := list.Fetch(); err != nil {
@@ -8,9 +8,12 @@ import (
 
 	"github.com/MunifTanjim/stremthru/internal/anilist"
 	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/kitsu"
 	"github.com/MunifTanjim/stremthru/internal/letterboxd"
+	"github.com/MunifTanjim/stremthru/internal/mal"
 	"github.com/MunifTanjim/stremthru/internal/mdblist"
 	"github.com/MunifTanjim/stremthru/internal/oauth"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
 	"github.com/MunifTanjim/stremthru/internal/stremio/configure"
 	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
 	stremio_template "github.com/MunifTanjim/stremthru/internal/stremio/template"
@@ -24,7 +27,9 @@ import (
 var IsPublicInstance = config.IsPublicInstance
 var MaxPublicInstanceListCount = config.Stremio.List.PublicMaxListCount
 var TraktEnabled = config.Integration.Trakt.IsEnabled()
+var SimklEnabled = config.Integration.Simkl.IsEnabled()
 var AnimeEnabled = config.Feature.IsEnabled("anime")
+var MALEnabled = config.Integration.MAL.IsEnabled()
 var TMDBEnabled = config.Integration.TMDB.IsEnabled()
 var TVDBEnabled = config.Integration.TVDB.IsEnabled()
 var LetterboxdEnabled = config.Integration.Letterboxd.IsEnabled() || config.HasPeer
@@ -119,6 +124,8 @@ type TemplateData struct {
 
 	TraktTokenId configure.Config
 
+	SimklTokenId configure.Config
+
 	MetaIdMovie  configure.Config
 	MetaIdSeries configure.Config
 	MetaIdAnime  configure.Config
@@ -229,6 +236,20 @@ func getTemplateData(ud *UserData, udError userDataError, isAuthed bool, r *http
 			},
 			Hidden: !TraktEnabled,
 		},
+		SimklTokenId: configure.Config{
+			Key:          "simkl_token_id",
+			Title:        "Auth Code",
+			Type:         configure.ConfigTypePassword,
+			Default:      ud.SimklTokenId,
+			Error:        udError.simkl_token_id,
+			Autocomplete: "off",
+			Action: configure.ConfigAction{
+				Visible: ud.SimklTokenId == "" || udError.simkl_token_id != "",
+				Label:   "Authorize",
+				OnClick: template.JS(`window.open("` + oauth.SimklOAuthConfig.AuthCodeURL(uuid.NewString()) + `", "_blank")`),
+			},
+			Hidden: !SimklEnabled,
+		},
 		MetaIdMovie: configure.Config{
 			Key:     "meta_id_movie",
 			Title:   "Movie",
@@ -272,6 +293,16 @@ func getTemplateData(ud *UserData, udError userDataError, isAuthed bool, r *http
 		}
 	}
 
+	if SimklEnabled && td.SimklTokenId.Error == "" {
+		otok, err := ud.getSimklToken()
+		if err != nil {
+			td.SimklTokenId.Error = err.Error()
+			td.SimklTokenId.Action.Visible = true
+		} else if otok != nil {
+			td.SimklTokenId.Title += " (" + otok.UserName + ")"
+		}
+	}
+
 	if ud.Shuffle {
 		td.Shuffle.Default = "checked"
 	}
@@ -317,6 +348,15 @@ func getTemplateData(ud *UserData, udError userDataError, isAuthed bool, r *http
 						list.URL = l.GetURL()
 					}
 
+				case "kitsu":
+					l := kitsu.KitsuList{Id: id}
+					if err := ud.FetchKitsuList(&l, false); err != nil {
+						log.Error("failed to fetch list", "error", err, "id", listId)
+						list.Error.URL = "Failed to Fetch List: " + err.Error()
+					} else {
+						list.URL = l.GetURL()
+					}
+
 				case "letterboxd":
 					l := letterboxd.LetterboxdList{Id: id}
 					if err := ud.FetchLetterboxdList(&l); err != nil {
@@ -326,6 +366,15 @@ func getTemplateData(ud *UserData, udError userDataError, isAuthed bool, r *http
 						list.URL = l.GetURL()
 					}
 
+				case "mal":
+					l := mal.MALList{Id: id}
+					if err := ud.FetchMALList(&l, false); err != nil {
+						log.Error("failed to fetch list", "error", err, "id", listId)
+						list.Error.URL = "Failed to Fetch List: " + err.Error()
+					} else {
+						list.URL = l.GetURL()
+					}
+
 				case "mdblist":
 					l := mdblist.MDBListList{Id: id}
 					if err := ud.FetchMDBListList(&l); err != nil {
@@ -363,6 +412,20 @@ func getTemplateData(ud *UserData, udError userDataError, isAuthed bool, r *http
 						list.Error.URL = "Trakt.tv authorization needed"
 					}
 
+				case "simkl":
+					if td.SimklTokenId.Error == "" {
+						l := simkl.SimklList{Id: id}
+						if err := ud.FetchSimklList(&l); err != nil {
+							log.Error("failed to fetch list", "error", err, "id", listId)
+							list.Error.URL = "Failed to Fetch List: " + err.Error()
+						} else {
+							list.URL = l.GetURL()
+						}
+					} else {
+						list.Disabled.URL = true
+						list.Error.URL = "Simkl authorization needed"
+					}
+
 				case "tvdb":
 					l := tvdb.TVDBList{Id: id}
 					if err := ud.FetchTVDBList(&l); err != nil {
@@ -431,6 +494,36 @@ var executeTemplate = func() stremio_template.Executor[TemplateData] {
 					{Pattern: "/search/anime/top-100"},
 				},
 			})
+			td.SupportedServices = append(td.SupportedServices, supportedService{
+				Name:     "Kitsu",
+				Hostname: "kitsu.app",
+				Icon:     "https://kitsu.app/favicon-32x32.png",
+				URLs: []supportedServiceUrl{
+					{
+						Pattern: "/users/{user_slug_or_id}/library?media=anime&status={current,planned,completed,on_hold,dropped}",
+						Examples: []string{
+							"/users/vikhyat/library?media=anime&status=current",
+							"/users/1/library?media=anime&status=planned",
+						},
+					},
+				},
+			})
+		}
+		if AnimeEnabled && MALEnabled {
+			td.SupportedServices = append(td.SupportedServices, supportedService{
+				Name:     "MyAnimeList",
+				Hostname: "myanimelist.net",
+				Icon:     "https://cdn.myanimelist.net/images/favicon.ico",
+				URLs: []supportedServiceUrl{
+					{
+						Pattern: "/animelist/{user_name}?status={1,2,3,4,6,7}",
+						Examples: []string{
+							"/animelist/Xinil?status=1",
+							"/animelist/Xinil?status=6",
+						},
+					},
+				},
+			})
 		}
 		if LetterboxdEnabled {
 			td.SupportedServices = append(td.SupportedServices, supportedService{
@@ -649,6 +742,23 @@ var executeTemplate = func() stremio_template.Executor[TemplateData] {
 				},
 			})
 		}
+		if SimklEnabled {
+			td.SupportedServices = append(td.SupportedServices, supportedService{
+				Name:     "Simkl",
+				Hostname: "simkl.com",
+				Icon:     "https://eu.simkl.in/img_favicon/v2/favicon-32x32.png",
+				URLs: []supportedServiceUrl{
+					{
+						Pattern: "/{own_user_id}/{movies,tv,anime}/{plantowatch,watching,completed,hold,dropped}",
+						Examples: []string{
+							"/123456/movies/plantowatch",
+							"/123456/tv/watching",
+							"/123456/anime/completed",
+						},
+					},
+				},
+			})
+		}
 		if TVDBEnabled {
 			td.SupportedServices = append(td.SupportedServices, supportedService{
 				Name:     "TVDB",
@@ -697,6 +807,9 @@ var executeTemplate = func() stremio_template.Executor[TemplateData] {
 			if td.TraktTokenId.Default != "" {
 				td.TraktTokenId.Default = redacted
 			}
+			if td.SimklTokenId.Default != "" {
+				td.SimklTokenId.Default = redacted
+			}
 		}
 
 		return td
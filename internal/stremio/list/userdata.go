@@ -9,10 +9,13 @@ import (
 
 	"github.com/MunifTanjim/stremthru/internal/anilist"
 	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/kitsu"
 	"github.com/MunifTanjim/stremthru/internal/letterboxd"
+	"github.com/MunifTanjim/stremthru/internal/mal"
 	"github.com/MunifTanjim/stremthru/internal/mdblist"
 	"github.com/MunifTanjim/stremthru/internal/oauth"
 	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
 	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
 	stremio_userdata "github.com/MunifTanjim/stremthru/internal/stremio/userdata"
 	"github.com/MunifTanjim/stremthru/internal/tmdb"
@@ -35,6 +38,9 @@ type UserData struct {
 	TMDBTokenId string            `json:"tmdb_token_id,omitempty"`
 	tmdbToken   *oauth.OAuthToken `json:"-"`
 
+	SimklTokenId string            `json:"simkl_token_id,omitempty"`
+	simklToken   *oauth.OAuthToken `json:"-"`
+
 	TraktTokenId string            `json:"trakt_token_id,omitempty"`
 	traktToken   *oauth.OAuthToken `json:"-"`
 
@@ -51,6 +57,9 @@ type UserData struct {
 
 	mdblistById    map[string]mdblist.MDBListList       `json:"-"`
 	anilistById    map[string]anilist.AniListList       `json:"-"`
+	kitsuById      map[string]kitsu.KitsuList           `json:"-"`
+	malById        map[string]mal.MALList               `json:"-"`
+	simklById      map[string]simkl.SimklList           `json:"-"`
 	traktById      map[string]trakt.TraktList           `json:"-"`
 	tmdbById       map[string]tmdb.TMDBList             `json:"-"`
 	tvdbById       map[string]tvdb.TVDBList             `json:"-"`
@@ -60,6 +69,7 @@ type UserData struct {
 func (ud UserData) StripSecrets() UserData {
 	ud.MDBListAPIkey = ""
 	ud.TMDBTokenId = ""
+	ud.SimklTokenId = ""
 	ud.TraktTokenId = ""
 	ud.RPDBAPIKey = ""
 	ud.TopPostersAPIKey = ""
@@ -92,6 +102,7 @@ type userDataError struct {
 	}
 	list_urls           []string
 	tmdb_token_id       string
+	simkl_token_id      string
 	trakt_token_id      string
 	meta_id_movie       string
 	meta_id_series      string
@@ -171,6 +182,7 @@ func getUserData(r *http.Request, isAuthed bool) (*UserData, error) {
 
 		ud.MDBListAPIkey = r.Form.Get("mdblist_api_key")
 		ud.TMDBTokenId = r.Form.Get("tmdb_token_id")
+		ud.SimklTokenId = r.Form.Get("simkl_token_id")
 		ud.TraktTokenId = r.Form.Get("trakt_token_id")
 
 		ud.RPDBAPIKey = r.Form.Get("rpdb_api_key")
@@ -204,6 +216,7 @@ func getUserData(r *http.Request, isAuthed bool) (*UserData, error) {
 		isLetterboxdEnabled := LetterboxdEnabled
 		isMDBListEnabled := ud.MDBListAPIkey != ""
 		isTMDBConfigured := TMDBEnabled && ud.TMDBTokenId != ""
+		isSimklConfigured := SimklEnabled && ud.SimklTokenId != ""
 		isTraktTvConfigured := TraktEnabled && ud.TraktTokenId != ""
 		isTVDBConfigured := TVDBEnabled
 
@@ -227,6 +240,14 @@ func getUserData(r *http.Request, isAuthed bool) (*UserData, error) {
 			isTMDBConfigured = ud.TMDBTokenId != ""
 		}
 
+		if isSimklConfigured {
+			ud.simklToken, err = ud.getSimklToken()
+			if err != nil {
+				udErr.simkl_token_id = err.Error()
+			}
+			isSimklConfigured = ud.SimklTokenId != ""
+		}
+
 		if isTraktTvConfigured {
 			ud.traktToken, err = ud.getTraktToken()
 			if err != nil {
@@ -314,6 +335,82 @@ func getUserData(r *http.Request, isAuthed bool) (*UserData, error) {
 				}
 				ud.Lists[idx] = "anilist:" + list.Id
 
+			case "kitsu.app", "kitsu.io":
+				if !AnimeEnabled {
+					udErr.list_urls[idx] = "Unsupported List URL"
+					continue
+				}
+
+				restPath, ok := strings.CutPrefix(listUrl.Path, "/users/")
+				if !ok {
+					udErr.list_urls[idx] = "Unsupported Kitsu URL"
+					continue
+				}
+				userIdOrSlug, rest, _ := strings.Cut(restPath, "/")
+				if userIdOrSlug == "" || rest != "library" {
+					udErr.list_urls[idx] = "Unsupported Kitsu URL"
+					continue
+				}
+				if media := listUrl.Query().Get("media"); media != "" && media != "anime" {
+					udErr.list_urls[idx] = "Unsupported Kitsu URL"
+					continue
+				}
+				status := kitsu.LibraryEntryStatusAll
+				if s := listUrl.Query().Get("status"); s != "" {
+					status = kitsu.LibraryEntryStatus(s)
+				}
+				if !status.IsValid() {
+					udErr.list_urls[idx] = "Unsupported Kitsu URL"
+					continue
+				}
+
+				user, err := kitsu.FetchUser(userIdOrSlug)
+				if err != nil {
+					udErr.list_urls[idx] = "Failed to fetch User: " + err.Error()
+					continue
+				}
+				if user == nil {
+					udErr.list_urls[idx] = "Invalid Kitsu URL: user not found"
+					continue
+				}
+
+				list := kitsu.KitsuList{
+					Id:       kitsu.NewListId(user.Id, status),
+					UserName: user.Attributes.Name,
+				}
+				err = ud.FetchKitsuList(&list, true)
+				if err != nil {
+					udErr.list_urls[idx] = "Failed to fetch List: " + err.Error()
+					continue
+				}
+				ud.Lists[idx] = "kitsu:" + list.Id
+
+			case "myanimelist.net":
+				if !AnimeEnabled || !MALEnabled {
+					udErr.list_urls[idx] = "Unsupported List URL"
+					continue
+				}
+
+				userName, ok := strings.CutPrefix(listUrl.Path, "/animelist/")
+				userName = strings.TrimSuffix(userName, "/")
+				if !ok || userName == "" || strings.Contains(userName, "/") {
+					udErr.list_urls[idx] = "Unsupported MyAnimeList URL"
+					continue
+				}
+				status, ok := mal.ParseAnimeListStatusCode(listUrl.Query().Get("status"))
+				if !ok {
+					udErr.list_urls[idx] = "Unsupported MyAnimeList URL"
+					continue
+				}
+
+				list := mal.MALList{Id: mal.NewListId(userName, status)}
+				err := ud.FetchMALList(&list, true)
+				if err != nil {
+					udErr.list_urls[idx] = "Failed to fetch List: " + err.Error()
+					continue
+				}
+				ud.Lists[idx] = "mal:" + list.Id
+
 			case "boxd.it", "letterboxd.com":
 				if !isLetterboxdEnabled {
 					udErr.list_urls[idx] = "Unsupported List URL"
@@ -437,6 +534,55 @@ func getUserData(r *http.Request, isAuthed bool) (*UserData, error) {
 				}
 				ud.Lists[idx] = "mdblist:" + list.Id
 
+			case "simkl.com":
+				if !isSimklConfigured {
+					if SimklEnabled {
+						udErr.list_urls[idx] = "Simkl Auth Code is required"
+					} else {
+						udErr.list_urls[idx] = "Unsupported List URL"
+					}
+					continue
+				}
+
+				parts := strings.Split(strings.Trim(listUrl.Path, "/"), "/")
+				if len(parts) != 3 {
+					udErr.list_urls[idx] = "Unsupported Simkl URL"
+					continue
+				}
+				userId := parts[0]
+				if userId != ud.simklToken.UserId {
+					udErr.list_urls[idx] = "Invalid URL: not own list"
+					continue
+				}
+				var listType simkl.ListType
+				switch parts[1] {
+				case "movies":
+					listType = simkl.ListTypeMovies
+				case "tv":
+					listType = simkl.ListTypeShows
+				case "anime":
+					listType = simkl.ListTypeAnime
+				default:
+					udErr.list_urls[idx] = "Unsupported Simkl URL"
+					continue
+				}
+				status := simkl.ListStatus(parts[2])
+				if status == "watchlist" {
+					status = simkl.ListStatusPlanToWatch
+				}
+				if !status.IsValid() {
+					udErr.list_urls[idx] = "Unsupported Simkl URL"
+					continue
+				}
+
+				list := simkl.SimklList{Id: simkl.NewListId(userId, listType, status)}
+				err := ud.FetchSimklList(&list)
+				if err != nil {
+					udErr.list_urls[idx] = "Failed to fetch List: " + err.Error()
+					continue
+				}
+				ud.Lists[idx] = "simkl:" + list.Id
+
 			case "www.themoviedb.org", "themoviedb.org":
 				if !isTMDBConfigured {
 					if TMDBEnabled {
@@ -666,6 +812,29 @@ func (ud *UserData) getTraktToken() (*oauth.OAuthToken, error) {
 	return ud.traktToken, nil
 }
 
+func (ud *UserData) getSimklToken() (*oauth.OAuthToken, error) {
+	if ud.SimklTokenId == "" {
+		return nil, nil
+	}
+
+	if ud.simklToken != nil {
+		return ud.simklToken, nil
+	}
+
+	otok, err := oauth.GetOAuthTokenById(ud.SimklTokenId)
+	if err != nil {
+		ud.SimklTokenId = ""
+		return nil, errors.New("failed to retrieve token: " + err.Error())
+	}
+	if otok == nil || otok.AccessToken == "" {
+		ud.SimklTokenId = ""
+		return nil, errors.New("Invalid or Revoked")
+	}
+
+	ud.simklToken = otok
+	return ud.simklToken, nil
+}
+
 func (ud *UserData) getTMDBToken() (*oauth.OAuthToken, error) {
 	if ud.TMDBTokenId == "" {
 		return nil, nil
@@ -783,6 +952,46 @@ func (ud *UserData) FetchAniListList(list *anilist.AniListList, scheduleIdMapSyn
 	return nil
 }
 
+func (ud *UserData) FetchKitsuList(list *kitsu.KitsuList, scheduleIdMapSync bool) error {
+	if ud.kitsuById == nil {
+		ud.kitsuById = map[string]kitsu.KitsuList{}
+	}
+	if l, ok := ud.kitsuById[list.Id]; ok {
+		*list = l
+		return nil
+	}
+	if err := list.Fetch(); err != nil {
+		return err
+	}
+
+	if scheduleIdMapSync {
+		kitsu.ScheduleIdMapSync(list.Items)
+	}
+
+	ud.kitsuById[list.Id] = *list
+	return nil
+}
+
+func (ud *UserData) FetchMALList(list *mal.MALList, scheduleIdMapSync bool) error {
+	if ud.malById == nil {
+		ud.malById = map[string]mal.MALList{}
+	}
+	if l, ok := ud.malById[list.Id]; ok {
+		*list = l
+		return nil
+	}
+	if err := list.Fetch(); err != nil {
+		return err
+	}
+
+	if scheduleIdMapSync {
+		mal.ScheduleIdMapSync(list.Items)
+	}
+
+	ud.malById[list.Id] = *list
+	return nil
+}
+
 func (ud *UserData) FetchTMDBList(list *tmdb.TMDBList) error {
 	if ud.TMDBTokenId == "" {
 		return errors.New("TMDB Auth Code missing")
@@ -811,6 +1020,32 @@ func (ud *UserData) FetchTMDBList(list *tmdb.TMDBList) error {
 	return nil
 }
 
+func (ud *UserData) FetchSimklList(list *simkl.SimklList) error {
+	if ud.SimklTokenId == "" {
+		return errors.New("Simkl Auth Code missing")
+	}
+	if ud.simklById == nil {
+		ud.simklById = map[string]simkl.SimklList{}
+	}
+	if l, ok := ud.simklById[list.Id]; ok {
+		*list = l
+		return nil
+	}
+	if list.UserName == "" {
+		tok, err := ud.getSimklToken()
+		if err != nil {
+			return err
+		}
+		list.UserName = tok.UserName
+	}
+	if err := list.Fetch(ud.SimklTokenId); err != nil {
+		return err
+	}
+
+	ud.simklById[list.Id] = *list
+	return nil
+}
+
 func (ud *UserData) FetchTraktList(list *trakt.TraktList) error {
 	if ud.TraktTokenId == "" {
 		return errors.New("Trakt Auth Code missing")
@@ -17,6 +17,7 @@ import (
 	store_video "github.com/MunifTanjim/stremthru/internal/store/video"
 	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
 	stremio_store_usenet "github.com/MunifTanjim/stremthru/internal/stremio/store/usenet"
+	trakt_scrobbler "github.com/MunifTanjim/stremthru/internal/trakt/scrobbler"
 	usenetmanager "github.com/MunifTanjim/stremthru/internal/usenet/manager"
 	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
 	"github.com/MunifTanjim/stremthru/internal/usenet/nzb_info"
@@ -78,6 +79,7 @@ func handlePlaybackFromStore(w http.ResponseWriter, r *http.Request, ud *UserDat
 	ctx.Store, ctx.StoreAuthToken = s.Store, s.AuthToken
 
 	cacheKey := strings.Join([]string{ctx.ClientIP, string(storeCode), ctx.StoreAuthToken, sid, nzbUrl}, ":")
+	ctx.StremId = sid
 
 	stremLink := ""
 	if streamLinkCache.Get(cacheKey, &stremLink) {
@@ -422,6 +424,13 @@ func handleStreamFromUsenet(w http.ResponseWriter, r *http.Request, ud *UserData
 	}
 	defer stream.Close()
 
+	if IsMethod(r, http.MethodGet) {
+		if scrobbleConn := trakt_scrobbler.Connect(ctx.ProxyAuthUser, sid); scrobbleConn != nil {
+			w = scrobbleConn.WrapResponseWriter(w)
+			defer scrobbleConn.Close()
+		}
+	}
+
 	w.Header().Set("Content-Type", stream.ContentType)
 	w.Header().Set("Content-Length", strconv.FormatInt(stream.Size, 10))
 	w.Header().Set("Accept-Ranges", "bytes")
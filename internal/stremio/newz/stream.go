@@ -38,20 +38,8 @@ func (s WrappedStream) IsSortable() bool {
 	return s.R != nil
 }
 
-func (s WrappedStream) GetQuality() string {
-	return s.R.Quality
-}
-
-func (s WrappedStream) GetResolution() string {
-	return s.R.Resolution
-}
-
-func (s WrappedStream) GetSize() string {
-	return s.R.Size
-}
-
-func (s WrappedStream) GetHDR() string {
-	return strings.Join(s.R.HDR, "|")
+func (s WrappedStream) GetExtractorResult() *stremio_transformer.StreamExtractorResult {
+	return s.R
 }
 
 func matchesTitle(titles []string, parsedTitle string, normalizer *util.StringNormalizer) bool {
@@ -306,6 +294,13 @@ func handleStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// `Store.IsCached` needs to be known before filtering and sorting
+	for _, wStream := range wrappedStreams {
+		if storeCode, isCached := isCachedByHash[wStream.R.Hash]; isCached && storeCode != "" {
+			wStream.R.Store.IsCached = true
+		}
+	}
+
 	if ud.Filter != "" {
 		filter, err := stremio_transformer.StreamFilterBlob(ud.Filter).Parse()
 		if err == nil {
@@ -200,7 +200,7 @@ func getTemplateData(ud *UserData, w http.ResponseWriter, r *http.Request) *Temp
 			Type:        "text",
 			Default:     ud.Sort,
 			Title:       "Stream Sort",
-			Description: "Comma separated fields: <code>resolution</code>, <code>quality</code>, <code>size</code>, <code>hdr</code>. Prefix with <code>-</code> for reverse sort. Default: <code>" + stremio_transformer.StreamDefaultSortConfig + "</code>",
+			Description: "Comma separated fields: <code>resolution</code>, <code>quality</code>, <code>size</code>, <code>hdr</code>, <code>codec</code>, <code>audio</code>, <code>age</code>, <code>language</code>, <code>indexer</code>, or expressions like <code>\"en\" in Languages</code>, <code>Seeders</code>. Prefix with <code>-</code> for reverse sort. Cached streams are always listed first. Default: <code>" + stremio_transformer.StreamDefaultSortConfig + "</code>",
 		},
 		FilterConfig: configure.Config{
 			Key:         "filter",
@@ -8,8 +8,8 @@ import (
 	"github.com/MunifTanjim/stremthru/core"
 	"github.com/MunifTanjim/stremthru/internal/cache"
 	"github.com/MunifTanjim/stremthru/internal/config"
-	"github.com/MunifTanjim/stremthru/internal/shared"
 	stremio_store_webdl "github.com/MunifTanjim/stremthru/internal/stremio/store/webdl"
+	"github.com/MunifTanjim/stremthru/store"
 	"github.com/MunifTanjim/stremthru/stremio"
 )
 
@@ -37,7 +37,9 @@ func getADWebDLsMeta(r *http.Request, ctx *Ctx, idr *ParsedId, eud string) strem
 	}
 	cacheKey := getADLinksCacheKey(idr.getStoreCode(), ctx.StoreAuthToken)
 	if !adLinksCache.Get(cacheKey, &meta.Videos) {
-		params := &stremio_store_webdl.ListWebDLsParams{}
+		params := &stremio_store_webdl.ListWebDLsParams{
+			Limit: 500,
+		}
 		params.APIKey = ctx.StoreAuthToken
 		res, err := stremio_store_webdl.ListWebDLs(params, idr.storeName)
 		if err != nil {
@@ -47,51 +49,32 @@ func getADWebDLsMeta(r *http.Request, ctx *Ctx, idr *ParsedId, eud string) strem
 
 		storeName := ctx.Store.GetName()
 		shouldCreateProxyLink := config.StoreContentProxy.IsEnabled(string(storeName)) && ctx.StoreAuthToken == config.StoreAuthToken.GetToken(ctx.ProxyAuthUser, string(storeName)) && ctx.IsProxyAuthorized
-		tunnelType := config.StoreTunnel.GetTypeForStream(string(ctx.Store.GetName()))
 		idPrefix := getWebDLsMetaIdPrefix(idr.getStoreCode())
 
+		// the link is unlocked on playback
 		streamBaseUrl := ExtractRequestBaseURL(r).JoinPath("/stremio/store/" + eud + "/_/strem/")
 		for i := range res.Items {
 			dl := &res.Items[i]
+			if dl.Status != store.MagnetStatusDownloaded || !core.HasVideoExtension(dl.Name) {
+				continue
+			}
 
+			streamId := getWebDLsMetaId(idr.getStoreCode()) + "::" + dl.Link
 			stream := stremio.Stream{
+				URL: streamBaseUrl.JoinPath(url.PathEscape(streamId), url.PathEscape(dl.Name)).String(),
 				BehaviorHints: &stremio.StreamBehaviorHints{
 					VideoSize: dl.Size,
 					Filename:  dl.Name,
 				},
 			}
 
-			videoId := idPrefix + dl.Id
-			isDirectLink := false
-			if len(dl.Files) == 0 {
-				stream.URL = streamBaseUrl.JoinPath(url.PathEscape(videoId)).String()
-			} else {
-				file := dl.Files[0]
-				if !core.HasVideoExtension(file.Name) {
-					continue
-				}
-				isDirectLink = true
-				stream.URL = file.Link
-				stream.BehaviorHints.VideoSize = file.Size
-				stream.BehaviorHints.Filename = file.Name
-			}
-
-			videoTitle := getMetaPreviewDescriptionForWebDL("", dl.Name, true) + "\n📄 " + stream.BehaviorHints.Filename
-
+			videoTitle := getMetaPreviewDescriptionForWebDL("", dl.Name, true) + "\n📄 " + dl.Name
 			if shouldCreateProxyLink {
 				videoTitle = "✨ " + videoTitle
-				if isDirectLink {
-					if proxyLink, err := shared.CreateProxyLink(r, stream.URL, nil, tunnelType, 12*time.Hour, ctx.ProxyAuthUser, ctx.ProxyAuthPassword, true, stream.BehaviorHints.Filename); err == nil {
-						stream.URL = proxyLink
-					} else {
-						log.Error("failed to create proxy link, skipping file", "error", err, "store.name", storeName, "filename", stream.BehaviorHints.Filename)
-						continue
-					}
-				}
 			}
 
 			video := stremio.MetaVideo{
-				Id:       videoId,
+				Id:       idPrefix + dl.Id,
 				Title:    videoTitle,
 				Released: dl.AddedAt,
 				Streams:  []stremio.Stream{stream},
@@ -54,6 +54,8 @@ func handleStrem(w http.ResponseWriter, r *http.Request) {
 
 	log := ctx.Log
 
+	ctx.StremId = r.URL.Query().Get("sid")
+
 	videoId := strings.TrimPrefix(videoIdWithLink, idPrefix)
 	videoId, link, _ := strings.Cut(videoId, "::")
 
@@ -65,7 +67,7 @@ func handleStrem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cacheKey := strings.Join([]string{ctx.ClientIP, idr.getStoreCode(), ctx.StoreAuthToken, url}, ":")
+	cacheKey := strings.Join([]string{ctx.ClientIP, idr.getStoreCode(), ctx.StoreAuthToken, url, ctx.StremId}, ":")
 
 	stremLink := ""
 	if stremLinkCache.Get(cacheKey, &stremLink) {
@@ -95,7 +97,7 @@ func handleStrem(w http.ResponseWriter, r *http.Request) {
 			if config.StoreContentProxy.IsEnabled(string(storeName)) && ctx.StoreAuthToken == config.StoreAuthToken.GetToken(ctx.ProxyAuthUser, string(storeName)) {
 				if ctx.IsProxyAuthorized {
 					tunnelType := config.StoreTunnel.GetTypeForStream(string(ctx.Store.GetName()))
-					if proxyLink, err := shared.CreateProxyLink(r, data.Link, nil, tunnelType, 12*time.Hour, ctx.ProxyAuthUser, ctx.ProxyAuthPassword, true, fileName); err == nil {
+					if proxyLink, err := shared.CreateStremProxyLink(r, data.Link, nil, tunnelType, 12*time.Hour, ctx.ProxyAuthUser, ctx.ProxyAuthPassword, true, fileName, ctx.StremId); err == nil {
 						data.Link = proxyLink
 					} else {
 						lerr = err
@@ -130,7 +132,7 @@ func handleStrem(w http.ResponseWriter, r *http.Request) {
 			if config.StoreContentProxy.IsEnabled(string(storeName)) && ctx.StoreAuthToken == config.StoreAuthToken.GetToken(ctx.ProxyAuthUser, string(storeName)) {
 				if ctx.IsProxyAuthorized {
 					tunnelType := config.StoreTunnel.GetTypeForStream(string(ctx.Store.GetName()))
-					if proxyLink, err := shared.CreateProxyLink(r, data.Link, nil, tunnelType, 12*time.Hour, ctx.ProxyAuthUser, ctx.ProxyAuthPassword, true, fileName); err == nil {
+					if proxyLink, err := shared.CreateStremProxyLink(r, data.Link, nil, tunnelType, 12*time.Hour, ctx.ProxyAuthUser, ctx.ProxyAuthPassword, true, fileName, ctx.StremId); err == nil {
 						data.Link = proxyLink
 					} else {
 						lerr = err
@@ -3,13 +3,14 @@ package stremio_store
 import (
 	"net/http"
 	"net/url"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/core"
 	"github.com/MunifTanjim/stremthru/internal/cache"
 	"github.com/MunifTanjim/stremthru/internal/config"
 	stremio_store_webdl "github.com/MunifTanjim/stremthru/internal/stremio/store/webdl"
+	"github.com/MunifTanjim/stremthru/store"
 	"github.com/MunifTanjim/stremthru/stremio"
 )
 
@@ -37,7 +38,9 @@ func getPMWebDLsMeta(r *http.Request, ctx *Ctx, idr *ParsedId, eud string) (stre
 	}
 	cacheKey := getPMItemsCacheKey(idr.getStoreCode(), ctx.StoreAuthToken)
 	if !pmItemsCache.Get(cacheKey, &meta.Videos) {
-		params := &stremio_store_webdl.ListWebDLsParams{}
+		params := &stremio_store_webdl.ListWebDLsParams{
+			Limit: 500,
+		}
 		params.APIKey = ctx.StoreAuthToken
 		res, err := stremio_store_webdl.ListWebDLs(params, idr.storeName)
 		if err != nil {
@@ -45,39 +48,53 @@ func getPMWebDLsMeta(r *http.Request, ctx *Ctx, idr *ParsedId, eud string) (stre
 			return meta, err
 		}
 
+		shouldCreateProxyLink := config.StoreContentProxy.IsEnabled(string(idr.storeName)) && ctx.StoreAuthToken == config.StoreAuthToken.GetToken(ctx.ProxyAuthUser, string(idr.storeName)) && ctx.IsProxyAuthorized
 		idPrefix := getWebDLsMetaIdPrefix(idr.getStoreCode())
 
 		streamBaseUrl := ExtractRequestBaseURL(r).JoinPath("/stremio/store/" + eud + "/_/strem/")
 		for i := range res.Items {
 			item := &res.Items[i]
-			if len(item.Files) == 0 {
-				continue
-			}
-			file := item.Files[0]
-			if strings.HasPrefix(file.Path, "stremthru/") || !core.HasVideoExtension(file.Name) {
+			if item.Status != store.MagnetStatusDownloaded {
 				continue
 			}
-			streamId := idPrefix + item.Id
-			stream := stremio.Stream{
-				URL: streamBaseUrl.JoinPath(url.PathEscape(streamId)).String(),
-				BehaviorHints: &stremio.StreamBehaviorHints{
-					VideoSize: file.Size,
-					Filename:  file.Name,
-				},
+
+			// the files are not included in the list
+			gParams := &stremio_store_webdl.GetWebDLParams{
+				Id: item.Id,
 			}
-			videoTitle := getMetaPreviewDescriptionForWebDL("", file.Name, true) + "\n📄 " + file.Name
-			if config.StoreContentProxy.IsEnabled(string(idr.storeName)) && ctx.StoreAuthToken == config.StoreAuthToken.GetToken(ctx.ProxyAuthUser, string(idr.storeName)) && ctx.IsProxyAuthorized {
-				videoTitle = "✨ " + videoTitle
+			gParams.APIKey = ctx.StoreAuthToken
+			webdl, err := stremio_store_webdl.GetWebDL(gParams, idr.storeName)
+			if err != nil {
+				log.Error("failed to get webdl", "error", err, "store.name", idr.storeName, "id", item.Id)
+				continue
 			}
-			video := stremio.MetaVideo{
-				Id:       streamId,
-				Title:    videoTitle,
-				Released: item.AddedAt,
-				Streams:  []stremio.Stream{stream},
-				Episode:  -1,
-				Season:   -1,
+
+			for _, file := range webdl.Files {
+				if !core.HasVideoExtension(file.Name) {
+					continue
+				}
+				streamId := getWebDLsMetaId(idr.getStoreCode()) + "::" + file.Link
+				stream := stremio.Stream{
+					URL: streamBaseUrl.JoinPath(url.PathEscape(streamId), url.PathEscape(file.Name)).String(),
+					BehaviorHints: &stremio.StreamBehaviorHints{
+						VideoSize: file.Size,
+						Filename:  file.Name,
+					},
+				}
+				videoTitle := getMetaPreviewDescriptionForWebDL("", file.Name, true) + "\n📄 " + file.Name
+				if shouldCreateProxyLink {
+					videoTitle = "✨ " + videoTitle
+				}
+				video := stremio.MetaVideo{
+					Id:       idPrefix + item.Id + ":" + strconv.Itoa(file.Idx),
+					Title:    videoTitle,
+					Released: item.AddedAt,
+					Streams:  []stremio.Stream{stream},
+					Episode:  -1,
+					Season:   -1,
+				}
+				meta.Videos = append(meta.Videos, video)
 			}
-			meta.Videos = append(meta.Videos, video)
 		}
 		pmItemsCache.Add(cacheKey, meta.Videos)
 	}
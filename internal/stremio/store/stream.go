@@ -486,6 +486,9 @@ func handleStream(w http.ResponseWriter, r *http.Request) {
 			if file.Name != "" {
 				streamUrl = streamUrl.JoinPath(url.PathEscape(file.Name))
 			}
+			if isImdbId {
+				streamUrl.RawQuery = "sid=" + url.QueryEscape(videoIdWithLink)
+			}
 			stream := stremio.Stream{
 				URL:  streamUrl.String(),
 				Name: file.Name,
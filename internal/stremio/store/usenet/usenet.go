@@ -9,13 +9,14 @@ import (
 
 	"github.com/MunifTanjim/stremthru/core"
 	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/ratelimit"
 	"github.com/MunifTanjim/stremthru/internal/request"
 	"github.com/MunifTanjim/stremthru/store"
 	"github.com/MunifTanjim/stremthru/store/torbox"
 )
 
 var tbClient = torbox.NewAPIClient(&torbox.APIClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("torbox")),
+	HTTPClient: ratelimit.WrapStoreHTTPClient(config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("torbox")), "torbox"),
 })
 
 func IsSupported(storeCode store.StoreCode) bool {
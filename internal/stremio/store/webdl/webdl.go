@@ -2,33 +2,17 @@ package stremio_store_webdl
 
 import (
 	"errors"
-	"net/http"
-	"strconv"
 	"time"
 
-	"github.com/MunifTanjim/stremthru/core"
-	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/request"
+	"github.com/MunifTanjim/stremthru/internal/shared"
 	"github.com/MunifTanjim/stremthru/store"
-	"github.com/MunifTanjim/stremthru/store/alldebrid"
-	"github.com/MunifTanjim/stremthru/store/premiumize"
-	"github.com/MunifTanjim/stremthru/store/torbox"
 )
 
-var adClient = alldebrid.NewAPIClient(&alldebrid.APIClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("alldebrid")),
-	UserAgent:  config.StoreClientUserAgent,
-})
-
-var tbClient = torbox.NewAPIClient(&torbox.APIClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("torbox")),
-	UserAgent:  config.StoreClientUserAgent,
-})
-
-var pmClient = premiumize.NewAPIClient(&premiumize.APIClientConfig{
-	HTTPClient: config.GetHTTPClient(config.StoreTunnel.GetTypeForAPI("premiumize")),
-	UserAgent:  config.StoreClientUserAgent,
-})
+func getWebDLStore(storeName store.StoreName) (store.WebDLStore, bool) {
+	webdlStore, ok := shared.GetStore(string(storeName)).(store.WebDLStore)
+	return webdlStore, ok
+}
 
 type WebDLFile struct {
 	Idx       int    `json:"index"`
@@ -51,6 +35,7 @@ type WebDLStatus = store.MagnetStatus
 type WebDL struct {
 	Id      string      `json:"id"`
 	Hash    string      `json:"hash"`
+	Link    string      `json:"link"`
 	Name    string      `json:"name"`
 	Size    int64       `json:"size"`
 	Status  WebDLStatus `json:"status"`
@@ -64,207 +49,85 @@ type ListWebDLsData struct {
 }
 
 func ListWebDLs(params *ListWebDLsParams, storeName store.StoreName) (*ListWebDLsData, error) {
-	params.Limit = max(1, min(params.Limit, 500))
-
-	switch storeName {
-	case store.StoreNameAlldebrid:
-		rParamsRecent := &alldebrid.GetRecentUserLinksParams{
-			Ctx: params.Ctx,
-		}
-		rParamsSaved := &alldebrid.GetSavedUserLinksParams{
-			Ctx: params.Ctx,
-		}
-		resRecent, errRecent := adClient.GetRecentUserLinks(rParamsRecent)
-		resSaved, errSaved := adClient.GetSavedUserLinks(rParamsSaved)
-		if errRecent != nil {
-			return nil, errRecent
-		}
-		if errSaved != nil {
-			return nil, errSaved
-		}
-
-		links := append(resRecent.Data, resSaved.Data...)
-
-		seenLink := map[string]struct{}{}
-
-		data := ListWebDLsData{}
-		for i := range links {
-			link := &links[i]
-			if link.Host == "error" || link.Host == "magnet" {
-				continue
-			}
-			if _, seen := seenLink[link.Link]; seen {
-				continue
-			}
-			seenLink[link.Link] = struct{}{}
-			item := WebDL{
-				Id:      link.Link,
-				Hash:    "",
-				Name:    link.Filename,
-				Size:    link.GetSize(),
-				Status:  store.MagnetStatusUnknown,
-				AddedAt: link.GetDate(),
-				Files:   []WebDLFile{},
-			}
-			if link.LinkDL != "" {
-				item.Status = store.MagnetStatusDownloaded
-				item.Files = append(item.Files, WebDLFile{
-					Link: link.LinkDL,
-					Name: link.Filename,
-					Size: link.GetSize(),
-				})
-			}
-			data.Items = append(data.Items, item)
-		}
-
-		data.TotalItems = len(data.Items)
-
-		return &data, nil
-	case store.StoreNamePremiumize:
-		rParams := &premiumize.ListItemsParams{
-			Ctx: params.Ctx,
-		}
-		res, err := pmClient.ListItems(rParams)
-		if err != nil {
-			return nil, err
-		}
-
-		data := ListWebDLsData{}
-		for i := range res.Data.Files {
-			dl := &res.Data.Files[i]
-			item := WebDL{
-				Id:      dl.Id,
-				Hash:    "",
-				Name:    dl.Name,
-				Size:    dl.Size,
-				Status:  store.MagnetStatusDownloaded,
-				AddedAt: dl.GetCreatedAt(),
-			}
-			file := WebDLFile{
-				Name: dl.Name,
-				Path: dl.Path,
-				Size: dl.Size,
-			}
-			item.Files = append(item.Files, file)
-			data.Items = append(data.Items, item)
-		}
-
-		data.TotalItems = len(data.Items)
-
-		return &data, nil
-	case store.StoreNameTorBox:
-		rParams := &torbox.ListWebDLDownloadParams{
-			Ctx:    params.Ctx,
-			Limit:  params.Limit,
-			Offset: params.Offset,
-		}
-		res, err := tbClient.ListWebDLDownload(rParams)
-		if err != nil {
-			return nil, err
-		}
-
-		data := ListWebDLsData{}
-		for i := range res.Data {
-			und := &res.Data[i]
-			item := WebDL{
-				Id:      strconv.Itoa(und.Id),
-				Hash:    und.Hash,
-				Name:    und.Name,
-				Size:    und.Size,
-				Status:  store.MagnetStatusUnknown,
-				AddedAt: und.GetAddedAt(),
-			}
-			if und.DownloadState == torbox.TorrentDownloadStateDownloading {
-				item.Status = store.MagnetStatusDownloading
-			} else if und.DownloadFinished && und.DownloadPresent {
-				item.Status = store.MagnetStatusDownloaded
-			}
-			for i := range und.Files {
-				f := &und.Files[i]
-				file := WebDLFile{
-					Idx:  f.Id,
-					Link: torbox.LockedFileLink("").Create(und.Id, f.Id),
-					Name: f.ShortName,
-					Path: "/" + f.Name,
-					Size: f.Size,
-				}
-				item.Files = append(item.Files, file)
-			}
-			data.Items = append(data.Items, item)
-		}
+	webdlStore, ok := getWebDLStore(storeName)
+	if !ok {
+		return &ListWebDLsData{}, nil
+	}
 
-		count := len(data.Items)
-		// torbox returns 1 extra item
-		if count > params.Limit {
-			data.Items = data.Items[0:params.Limit]
-			count = params.Limit
-		}
-		data.TotalItems = params.Offset + count
-		if count == params.Limit {
-			data.TotalItems += 1
-		}
+	rParams := &store.ListWebDLsParams{
+		Ctx:      params.Ctx,
+		Limit:    max(1, min(params.Limit, 500)),
+		Offset:   params.Offset,
+		ClientIP: params.ClientIP,
+	}
+	res, err := webdlStore.ListWebDLs(rParams)
+	if err != nil {
+		return nil, err
+	}
 
-		return &data, nil
-	default:
-		return &ListWebDLsData{}, nil
+	data := ListWebDLsData{
+		Items:      make([]WebDL, 0, len(res.Items)),
+		TotalItems: res.TotalItems,
 	}
+	for i := range res.Items {
+		dl := &res.Items[i]
+		data.Items = append(data.Items, WebDL{
+			Id:      dl.Id,
+			Link:    dl.Link,
+			Name:    dl.Name,
+			Size:    dl.Size,
+			Status:  WebDLStatus(dl.Status),
+			AddedAt: dl.AddedAt,
+			Files:   []WebDLFile{},
+		})
+	}
+	return &data, nil
 }
 
 type GetWebDLParams struct {
 	request.Ctx
-	Id          string
-	ClientIP    string
-	BypassCache bool
+	Id       string
+	ClientIP string
 }
 
 type GetWebDLData = WebDL
 
 func GetWebDL(params *GetWebDLParams, storeName store.StoreName) (*WebDL, error) {
-	switch storeName {
-	case store.StoreNameTorBox:
-		id, err := strconv.Atoi(params.Id)
-		if err != nil {
-			return nil, err
-		}
-		rParams := &torbox.GetWebDLDownloadParams{
-			Ctx:         params.Ctx,
-			Id:          id,
-			BypassCache: params.BypassCache,
-		}
-		res, err := tbClient.GetWebDLDownload(rParams)
-		if err != nil {
-			return nil, err
-		}
-		und := &res.Data
-		item := WebDL{
-			Id:      strconv.Itoa(und.Id),
-			Hash:    und.Hash,
-			Name:    und.Name,
-			Size:    und.Size,
-			Status:  store.MagnetStatusUnknown,
-			AddedAt: und.GetAddedAt(),
-		}
-		if und.DownloadState == torbox.TorrentDownloadStateDownloading {
-			item.Status = store.MagnetStatusDownloading
-		}
-		if und.DownloadFinished && und.DownloadPresent {
-			item.Status = store.MagnetStatusDownloaded
-		}
-		for i := range und.Files {
-			f := &und.Files[i]
-			file := WebDLFile{
-				Idx:  f.Id,
-				Link: torbox.LockedFileLink("").Create(und.Id, f.Id),
-				Name: f.ShortName,
-				Path: "/" + f.Name,
-				Size: f.Size,
-			}
-			item.Files = append(item.Files, file)
-		}
-		return &item, nil
-	default:
+	webdlStore, ok := getWebDLStore(storeName)
+	if !ok {
 		return nil, errors.New("unsupported")
 	}
+
+	rParams := &store.GetWebDLParams{
+		Ctx:      params.Ctx,
+		Id:       params.Id,
+		ClientIP: params.ClientIP,
+	}
+	res, err := webdlStore.GetWebDL(rParams)
+	if err != nil {
+		return nil, err
+	}
+
+	item := WebDL{
+		Id:      res.Id,
+		Link:    res.Link,
+		Name:    res.Name,
+		Size:    res.Size,
+		Status:  WebDLStatus(res.Status),
+		AddedAt: res.AddedAt,
+		Files:   make([]WebDLFile, 0, len(res.Files)),
+	}
+	for i := range res.Files {
+		f := &res.Files[i]
+		item.Files = append(item.Files, WebDLFile{
+			Idx:  f.Idx,
+			Link: f.Link,
+			Name: f.Name,
+			Path: f.Path,
+			Size: f.Size,
+		})
+	}
+	return &item, nil
 }
 
 type GenerateLinkData struct {
@@ -278,96 +141,22 @@ type GenerateLinkParams struct {
 }
 
 func GenerateLink(params *GenerateLinkParams, storeName store.StoreName) (*GenerateLinkData, error) {
-	switch storeName {
-	case store.StoreNameAlldebrid:
-		res, err := adClient.UnlockLink(&alldebrid.UnlockLinkParams{
-			Ctx:    params.Ctx,
-			Link:   params.Link,
-			UserIP: params.CLientIP,
-		})
-		if err != nil {
-			return nil, err
-		}
-		data := GenerateLinkData{}
-		if res.Data.Link != "" {
-			if !core.HasVideoExtension(res.Data.Filename) {
-				error := core.NewAPIError("no video file found")
-				error.StatusCode = http.StatusUnprocessableEntity
-				error.StoreName = string(storeName)
-				return nil, error
-			}
-			data.Link = res.Data.Link
-			return &data, nil
-		}
-
-		if len(res.Data.Streams) > 0 {
-			var stream *alldebrid.UnlockLinkDataStream
-			for i := range res.Data.Streams {
-				s := &res.Data.Streams[i]
-				if !core.HasVideoExtension("." + s.Ext) {
-					continue
-				}
-				stream = s
-			}
-			if stream == nil {
-				error := core.NewAPIError("no video stream found")
-				error.StatusCode = http.StatusUnprocessableEntity
-				error.StoreName = string(storeName)
-				return nil, error
-			}
-			sRes, err := adClient.GetStreamingLink(&alldebrid.GetStreamingLinkParams{
-				Ctx:    params.Ctx,
-				Id:     res.Data.Id,
-				Stream: stream.Id,
-			})
-			if err != nil {
-				return nil, err
-			}
-			data.Link = sRes.Data.Link
-			return &data, nil
-		}
-
-		return &data, nil
-
-	case store.StoreNamePremiumize:
-		res, err := pmClient.GetItem(&premiumize.GetItemParams{
-			Ctx: params.Ctx,
-			Id:  params.Link,
-		})
-		if err != nil {
-			return nil, err
-		}
-		link := res.Data.StreamLink
-		if link == "" {
-			link = res.Data.Link
-		}
-		data := GenerateLinkData{
-			Link: res.Data.Link,
-		}
-		return &data, nil
-	case store.StoreNameTorBox:
-		id, fileId, err := torbox.LockedFileLink(params.Link).Parse()
-		if err != nil {
-			error := core.NewAPIError("invalid link")
-			error.StatusCode = http.StatusBadRequest
-			error.Cause = err
-			return nil, error
-		}
-		rParams := &torbox.RequestWebDLDownloadLinkParams{
-			Ctx:     params.Ctx,
-			WebDLId: id,
-			FileId:  fileId,
-			UserIP:  params.CLientIP,
-		}
-		res, err := tbClient.RequestWebDLDownloadLink(rParams)
-		if err != nil {
-			return nil, err
-		}
-		data := GenerateLinkData{
-			Link: res.Data.Link,
-		}
-		return &data, nil
-	default:
+	webdlStore, ok := getWebDLStore(storeName)
+	if !ok {
 		return nil, errors.New("unsupported")
 	}
+
+	rParams := &store.GenerateWebDLLinkParams{
+		Ctx:      params.Ctx,
+		Link:     params.Link,
+		ClientIP: params.CLientIP,
+	}
+	res, err := webdlStore.GenerateWebDLLink(rParams)
+	if err != nil {
+		return nil, err
+	}
+	data := GenerateLinkData{
+		Link: res.Link,
+	}
+	return &data, nil
 }
@@ -1,8 +1,10 @@
 package stremio_torz
 
 import (
+	"html/template"
 	"net/http"
 	"slices"
+	"strings"
 
 	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/server"
@@ -188,6 +190,15 @@ func handleConfigure(w http.ResponseWriter, r *http.Request) {
 				td.Indexers[i].URL.Error = err.Error()
 			case "apikey":
 				td.Indexers[i].APIKey.Error = err.Error()
+			case "":
+				warning, err := indexer.Check()
+				if err != nil {
+					log.Warn("failed to access indexer", "name", indexer.Name, "error", err)
+					td.Indexers[i].URL.Error = "Failed to access indexer:\n" + err.Error()
+				} else if warning != "" {
+					log.Warn("failed to access some indexers", "name", indexer.Name, "warning", warning)
+					td.Indexers[i].URL.Description = template.HTML("⚠️ Failed to access some indexers:<br />" + strings.ReplaceAll(template.HTMLEscapeString(warning), "\n", "<br />"))
+				}
 			}
 		}
 	}
@@ -71,6 +71,7 @@ func handleStrem(w http.ResponseWriter, r *http.Request) {
 	}
 
 	sid := r.PathValue("stremId")
+	ctx.StremId = sid
 
 	s := ud.GetStoreByCode(r.PathValue("storeCode"))
 	ctx.Store, ctx.StoreAuthToken = s.Store, s.AuthToken
@@ -249,7 +250,7 @@ func handleStrem(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		glRes, err := shared.GenerateStremThruLink(r, &ctx.Context, link, fileName)
+		glRes, err := shared.GenerateStremThruMagnetFileLink(r, &ctx.Context, magnet.Hash, file, fileName)
 		if err != nil {
 			return &stremResult{
 				error_level: logger.LevelError,
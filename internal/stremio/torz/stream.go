@@ -15,6 +15,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/buddy"
 	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/imdb_title"
+	"github.com/MunifTanjim/stremthru/internal/meta"
 	"github.com/MunifTanjim/stremthru/internal/shared"
 	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
 	stremio_transformer "github.com/MunifTanjim/stremthru/internal/stremio/transformer"
@@ -42,26 +43,41 @@ func (s WrappedStream) IsSortable() bool {
 	return s.R != nil
 }
 
-func (s WrappedStream) GetQuality() string {
-	return s.R.Quality
+func (s WrappedStream) GetExtractorResult() *stremio_transformer.StreamExtractorResult {
+	return s.R
 }
 
-func (s WrappedStream) GetResolution() string {
-	return s.R.Resolution
+type indexerSearchQueryMeta struct {
+	titles     []string
+	year       int
+	season, ep int
+	ids        map[tznc.SearchParam]string
 }
 
-func (s WrappedStream) GetSize() string {
-	return s.R.Size
+var indexerSearchIdParamsMovie = []tznc.SearchParam{
+	tznc.SearchParamIMDBId,
+	tznc.SearchParamTMDBId,
 }
 
-func (s WrappedStream) GetHDR() string {
-	return strings.Join(s.R.HDR, "|")
+var indexerSearchIdParamsSeries = []tznc.SearchParam{
+	tznc.SearchParamIMDBId,
+	tznc.SearchParamTVDBId,
+	tznc.SearchParamTMDBId,
 }
 
-type indexerSearchQueryMeta struct {
-	titles     []string
-	year       int
-	season, ep int
+// getIdParam returns the first id param, in the order of preference, that is
+// supported by the query and has a known value.
+func (m *indexerSearchQueryMeta) getIdParam(query *tznc.Query, isSeries bool) (tznc.SearchParam, string) {
+	params := indexerSearchIdParamsMovie
+	if isSeries {
+		params = indexerSearchIdParamsSeries
+	}
+	for _, param := range params {
+		if value := m.ids[param]; value != "" && query.IsSupported(param) {
+			return param, value
+		}
+	}
+	return "", ""
 }
 
 func (m *indexerSearchQueryMeta) MatchesTitle(parsedTitle string, normalizer *util.StringNormalizer) bool {
@@ -93,7 +109,7 @@ func GetStreamsFromIndexers(ctx *Ctx, stremType, stremId string) ([]WrappedStrea
 		return nil, nil, err
 	}
 
-	queryMeta := indexerSearchQueryMeta{titles: []string{}}
+	queryMeta := indexerSearchQueryMeta{titles: []string{}, ids: map[tznc.SearchParam]string{}}
 	if nsid.IsAnime {
 		if aniEp := util.SafeParseInt(nsid.Episode, -1); aniEp != -1 {
 			tvdbMaps, err := anidb.GetTVDBEpisodeMaps(nsid.Id, false)
@@ -112,6 +128,9 @@ func GetStreamsFromIndexers(ctx *Ctx, stremType, stremId string) ([]WrappedStrea
 				queryMeta.titles = make([]string, 0, len(titles))
 				queryMeta.season = epMap.TVDBSeason
 				queryMeta.ep = ep
+				if epMap.TVDBId != "" && epMap.TVDBSeason > 0 {
+					queryMeta.ids[tznc.SearchParamTVDBId] = epMap.TVDBId
+				}
 				seenTitle := util.NewSet[string]()
 				for i := range titles {
 					title := &titles[i]
@@ -145,6 +164,17 @@ func GetStreamsFromIndexers(ctx *Ctx, stremType, stremId string) ([]WrappedStrea
 			queryMeta.season = util.SafeParseInt(nsid.Season, 0)
 			queryMeta.ep = util.SafeParseInt(nsid.Episode, 0)
 		}
+		queryMeta.ids[tznc.SearchParamIMDBId] = nsid.Id
+		idType := meta.IdTypeMovie
+		if nsid.IsSeries() {
+			idType = meta.IdTypeShow
+		}
+		if idMap, err := meta.GetIdMap(idType, nsid.Id); err != nil {
+			log.Warn("failed to get id map", "error", err, "id", nsid.Id)
+		} else if idMap != nil {
+			queryMeta.ids[tznc.SearchParamTMDBId] = idMap.TMDB
+			queryMeta.ids[tznc.SearchParamTVDBId] = idMap.TVDB
+		}
 	}
 
 	sQueries := make([]indexerSearchQuery, 0, len(ctx.Indexers)*2)
@@ -165,14 +195,14 @@ func GetStreamsFromIndexers(ctx *Ctx, stremType, stremId string) ([]WrappedStrea
 			continue
 		}
 		query.SetLimit(-1)
-		if !nsid.IsAnime && query.IsSupported(tznc.SearchParamIMDBId) {
-			query.Set(tznc.SearchParamIMDBId, nsid.Id)
+		if idParam, idValue := queryMeta.getIdParam(query, nsid.IsSeries()); idParam != "" {
+			query.Set(idParam, idValue)
 			is_exact := !nsid.IsSeries()
 			if nsid.IsSeries() {
-				if query.IsSupported(tznc.SearchParamSeason) && nsid.Season != "" {
-					query.Set(tznc.SearchParamSeason, nsid.Season)
-					if query.IsSupported(tznc.SearchParamEp) && nsid.Episode != "" {
-						query.Set(tznc.SearchParamEp, nsid.Episode)
+				if query.IsSupported(tznc.SearchParamSeason) && queryMeta.season > 0 {
+					query.Set(tznc.SearchParamSeason, strconv.Itoa(queryMeta.season))
+					if query.IsSupported(tznc.SearchParamEp) && queryMeta.ep > 0 {
+						query.Set(tznc.SearchParamEp, strconv.Itoa(queryMeta.ep))
 						is_exact = true
 					}
 				}
@@ -798,6 +828,13 @@ func handleStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// `Store.IsCached` needs to be known before filtering and sorting
+	for _, wStream := range wrappedStreams {
+		if storeCode, isCached := isCachedByHash[wStream.R.Hash]; isCached && storeCode != "" {
+			wStream.R.Store.IsCached = true
+		}
+	}
+
 	if ud.Filter != "" {
 		filter, err := stremio_transformer.StreamFilterBlob(ud.Filter).Parse()
 		if err == nil {
@@ -0,0 +1,115 @@
+package stremio_torz
+
+import (
+	"testing"
+
+	tznc "github.com/MunifTanjim/stremthru/internal/torznab/client"
+	"github.com/MunifTanjim/stremthru/internal/znab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexerSearchQueryMetaGetIdParam(t *testing.T) {
+	newQuery := func(t tznc.Function, params ...tznc.SearchParam) *tznc.Query {
+		item := &znab.CapsSearchingItem{
+			Available:       true,
+			SupportedParams: params,
+		}
+		caps := tznc.Caps{Searching: &znab.CapsSearching{
+			Search:      &znab.CapsSearchingItem{Available: true, SupportedParams: []string{"q"}},
+			TVSearch:    item,
+			MovieSearch: item,
+		}}
+		return tznc.NewQuery(&caps).SetT(t)
+	}
+
+	allIds := map[tznc.SearchParam]string{
+		tznc.SearchParamIMDBId: "tt0903747",
+		tznc.SearchParamTVDBId: "81189",
+		tznc.SearchParamTMDBId: "1396",
+	}
+
+	for _, tc := range []struct {
+		name      string
+		isSeries  bool
+		ids       map[tznc.SearchParam]string
+		query     *tznc.Query
+		wantParam tznc.SearchParam
+		wantValue string
+	}{
+		{
+			name:      "movie prefers imdbid",
+			ids:       allIds,
+			query:     newQuery(tznc.FunctionSearchMovie, "q", "imdbid", "tmdbid"),
+			wantParam: tznc.SearchParamIMDBId,
+			wantValue: "tt0903747",
+		},
+		{
+			name:      "movie without imdbid caps",
+			ids:       allIds,
+			query:     newQuery(tznc.FunctionSearchMovie, "q", "tmdbid"),
+			wantParam: tznc.SearchParamTMDBId,
+			wantValue: "1396",
+		},
+		{
+			name:      "movie ignores tvdbid",
+			ids:       allIds,
+			query:     newQuery(tznc.FunctionSearchMovie, "q", "tvdbid"),
+			wantParam: "",
+		},
+		{
+			name:      "series prefers imdbid",
+			isSeries:  true,
+			ids:       allIds,
+			query:     newQuery(tznc.FunctionSearchTV, "q", "season", "ep", "imdbid", "tvdbid", "tmdbid"),
+			wantParam: tznc.SearchParamIMDBId,
+			wantValue: "tt0903747",
+		},
+		{
+			name:      "series without imdbid caps prefers tvdbid",
+			isSeries:  true,
+			ids:       allIds,
+			query:     newQuery(tznc.FunctionSearchTV, "q", "season", "ep", "tmdbid", "tvdbid"),
+			wantParam: tznc.SearchParamTVDBId,
+			wantValue: "81189",
+		},
+		{
+			name:      "series without imdbid and tvdbid caps",
+			isSeries:  true,
+			ids:       allIds,
+			query:     newQuery(tznc.FunctionSearchTV, "q", "season", "ep", "tmdbid"),
+			wantParam: tznc.SearchParamTMDBId,
+			wantValue: "1396",
+		},
+		{
+			name:     "series skips missing id value",
+			isSeries: true,
+			ids: map[tznc.SearchParam]string{
+				tznc.SearchParamIMDBId: "tt0903747",
+				tznc.SearchParamTMDBId: "1396",
+			},
+			query:     newQuery(tznc.FunctionSearchTV, "q", "tvdbid", "tmdbid"),
+			wantParam: tznc.SearchParamTMDBId,
+			wantValue: "1396",
+		},
+		{
+			name:      "no id caps",
+			isSeries:  true,
+			ids:       allIds,
+			query:     newQuery(tznc.FunctionSearchTV, "q", "season", "ep"),
+			wantParam: "",
+		},
+		{
+			name:      "fallback to search function",
+			ids:       allIds,
+			query:     newQuery(tznc.FunctionSearchMusic),
+			wantParam: "",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := indexerSearchQueryMeta{ids: tc.ids}
+			param, value := m.getIdParam(tc.query, tc.isSeries)
+			assert.Equal(t, tc.wantParam, param)
+			assert.Equal(t, tc.wantValue, value)
+		})
+	}
+}
@@ -27,11 +27,13 @@ const (
 	StreamExtractorFieldFileIdx       StreamExtractorField = "file_idx"
 	StreamExtractorFieldFileName      StreamExtractorField = "file_name"
 	StreamExtractorFieldFileSize      StreamExtractorField = "file_size"
+	StreamExtractorFieldGroup         StreamExtractorField = "group"
 	StreamExtractorFieldHDR           StreamExtractorField = "hdr"
 	StreamExtractorFieldHDRSep        StreamExtractorField = "hdr_sep"
 	StreamExtractorFieldLanguage      StreamExtractorField = "language"
 	StreamExtractorFieldLanguageSep   StreamExtractorField = "language_sep"
 	StreamExtractorFieldHash          StreamExtractorField = "hash"
+	StreamExtractorFieldIndexer       StreamExtractorField = "indexer"
 	StreamExtractorFieldQuality       StreamExtractorField = "quality"
 	StreamExtractorFieldResolution    StreamExtractorField = "resolution"
 	StreamExtractorFieldSeeders       StreamExtractorField = "seeders"
@@ -117,7 +119,8 @@ type StreamExtractorResultStore struct {
 }
 
 type StreamExtractorResultAddon struct {
-	Name string
+	Name  string
+	Names []string
 }
 
 type StreamExtractorResultRaw struct {
@@ -361,12 +364,16 @@ func (se StreamExtractor) Parse(stream *stremio.Stream, sType string) *StreamExt
 						r.File.Name = value
 					case StreamExtractorFieldFileSize:
 						r.Size = value
+					case StreamExtractorFieldGroup:
+						r.Group = value
 					case StreamExtractorFieldHash:
 						r.Hash = value
 					case StreamExtractorFieldHDR:
 						hdr = value
 					case StreamExtractorFieldHDRSep:
 						hdr_sep = value
+					case StreamExtractorFieldIndexer:
+						r.Indexer.Name = value
 					case StreamExtractorFieldLanguage:
 						language = value
 					case StreamExtractorFieldLanguageSep:
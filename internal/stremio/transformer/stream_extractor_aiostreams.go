@@ -0,0 +1,23 @@
+package stremio_transformer
+
+import "strings"
+
+var StreamExtractorAIOStreams = StreamExtractorBlob(strings.TrimSpace(`
+name
+(?i)^(?:🕵️‍♂️ )?(?:\[P2P\] )?(?:\[(?<store_code>\w+?)(?:(?<store_is_cached>⚡)|⏳)?\] )?(?<addon_name>.+?)(?: (?<resolution>\d+[kp]))?(?: \(.+\))?$
+
+description
+(?i)🎥 (?<quality>` + qualityPattern + `)
+(?i)🎞️ (?<codec>` + codecPattern + `)
+(?m)🏷️ (?<group>\S+)
+(?m)📺 (?<hdr>[^|🎧🔊\n]+?(?:(?<hdr_sep> \| )[^|🎧🔊\n]+?)*) ?(?:🎧|🔊|$)
+🔊 (?<channel>\d\.\d)
+📦 (?<size>[\d.]+ [KMGT]?B)
+👥 (?<seeders>\d+)
+(?m)🔍 (?<site>.+?)$
+(?m)🌎 (?<language>[^|\n]+?(?:(?<language_sep> \| )[^|\n]+?)*)$
+(?m)📁 (?<file_name>.+)$
+
+url
+(?i)\/(?<hash>[a-f0-9]{40})\/
+`)).MustParse()
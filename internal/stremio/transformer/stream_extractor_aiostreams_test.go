@@ -0,0 +1,91 @@
+package stremio_transformer
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/go-ptt"
+	"github.com/MunifTanjim/stremthru/stremio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamExtractorAIOStreams(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		sType  string
+		stream stremio.Stream
+		result StreamExtractorResult
+	}{
+		{
+			"debrid",
+			"movie", stremio.Stream{
+				Name:        "[TB⚡] Torrentio 2160p",
+				Description: "🎥 BluRay REMUX 🎞️ HEVC 🏷️ FraMeSToR\n📺 DV | HDR10 🎧 Atmos | TrueHD 🔊 7.1\n📦 40.33 GB 👥 47 🔍 TorrentGalaxy\n🌎 🇬🇧 | 🇫🇷\n📁 Deadpool.2016.UHD.BluRay.2160p.TrueHD.Atmos.7.1.DV.HEVC.HYBRiD.REMUX-FraMeSToR.mkv",
+				URL:         "https://aiostreams.example.com/resolve/torbox/e4f5d7a2f3dd6b7b1826bd77e316b6b5ba31eb72/0",
+			}, StreamExtractorResult{
+				Hash:    "e4f5d7a2f3dd6b7b1826bd77e316b6b5ba31eb72",
+				Seeders: 47,
+				Result: &ptt.Result{
+					Channels:   []string{"7.1"},
+					Codec:      "HEVC",
+					Group:      "FraMeSToR",
+					HDR:        []string{"DV", "HDR10"},
+					Languages:  []string{"en", "fr"},
+					Quality:    "BluRay REMUX",
+					Resolution: "2160p",
+					Site:       "TorrentGalaxy",
+					Size:       "40.33 GB",
+				},
+				Addon: StreamExtractorResultAddon{
+					Name: "Torrentio",
+				},
+				File: StreamExtractorResultFile{
+					Idx:  -1,
+					Name: "Deadpool.2016.UHD.BluRay.2160p.TrueHD.Atmos.7.1.DV.HEVC.HYBRiD.REMUX-FraMeSToR.mkv",
+				},
+				Store: StreamExtractorResultStore{
+					Code:     "TB",
+					Name:     "torbox",
+					IsCached: true,
+				},
+				Episode: -1,
+				Season:  -1,
+			},
+		},
+		{
+			"p2p",
+			"movie", stremio.Stream{
+				Name:        "[P2P] Comet 1080p",
+				Description: "🎥 WEB-DL 🎞️ AVC\n📦 2.1 GB 👥 12 🔍 ThePirateBay",
+				InfoHash:    "387ccd318d583405bbadcec55b9b05029645dd1d",
+				FileIndex:   2,
+			}, StreamExtractorResult{
+				Hash:    "387ccd318d583405bbadcec55b9b05029645dd1d",
+				Seeders: 12,
+				Result: &ptt.Result{
+					Codec:      "AVC",
+					Quality:    "WEB-DL",
+					Resolution: "1080p",
+					Site:       "ThePirateBay",
+					Size:       "2.1 GB",
+				},
+				Addon: StreamExtractorResultAddon{
+					Name: "Comet",
+				},
+				File: StreamExtractorResultFile{
+					Idx: 2,
+				},
+				Episode: -1,
+				Season:  -1,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := StreamExtractorAIOStreams.Parse(&tc.stream, tc.sType)
+			tc.result.Category = tc.sType
+			tc.result.Result.Normalize()
+			tc.result.Raw.Name = tc.stream.Name
+			tc.result.Raw.Description = tc.stream.Description
+			assert.Equal(t, &tc.result, data)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package stremio_transformer
+
+import "strings"
+
+var StreamExtractorJackettio = StreamExtractorBlob(strings.TrimSpace(`
+name
+(?i)^\[(?<store_code>\w+?)(?<store_is_cached>\+)?\] (?<addon_name>.+) (?:(?<resolution>\d+[kp])|\w+)$
+
+description
+^(?<t_title>[^\n]+)(?:\n(?<file_name>[^💾ℹ\n][^\n]*))?(?:\nℹ️ [^\n]*)?\n💾 ?(?<size>[\d.]+ \w+) 👥 ?(?<seeders>\d+) ⚙️ ?(?<site>\S+)(?: (?<language>` + flagEmojiPattern + `(?:(?<language_sep> )` + flagEmojiPattern + `)*))?
+(?i)(?<quality>` + qualityPattern + `)
+(?i)\b(?<codec>` + codecPattern + `)\b
+`)).MustParse()
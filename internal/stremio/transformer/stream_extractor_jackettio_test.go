@@ -0,0 +1,91 @@
+package stremio_transformer
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/go-ptt"
+	"github.com/MunifTanjim/stremthru/stremio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamExtractorJackettio(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		sType  string
+		stream stremio.Stream
+		result StreamExtractorResult
+	}{
+		{
+			"movie",
+			"movie", stremio.Stream{
+				Name:  "[RD+] Jackettio 4K",
+				Title: "Deadpool.2016.UHD.BluRay.2160p.TrueHD.Atmos.7.1.DV.HEVC.HYBRiD.REMUX-FraMeSToR\n💾 40.33 GB 👥 47 ⚙️ yts 🇬🇧 🇫🇷",
+				URL:   "https://jackettio.example.com/eyJ9/download/movie/tt1431045/a1b2c3",
+			}, StreamExtractorResult{
+				TTitle:  "Deadpool.2016.UHD.BluRay.2160p.TrueHD.Atmos.7.1.DV.HEVC.HYBRiD.REMUX-FraMeSToR",
+				Seeders: 47,
+				Result: &ptt.Result{
+					Codec:      "HEVC",
+					Languages:  []string{"en", "fr"},
+					Quality:    "BluRay",
+					Resolution: "4k",
+					Site:       "yts",
+					Size:       "40.33 GB",
+				},
+				Addon: StreamExtractorResultAddon{
+					Name: "Jackettio",
+				},
+				File: StreamExtractorResultFile{
+					Idx: -1,
+				},
+				Store: StreamExtractorResultStore{
+					Code:     "RD",
+					Name:     "realdebrid",
+					IsCached: true,
+				},
+				Episode: -1,
+				Season:  -1,
+			},
+		},
+		{
+			"series w/ file name and info",
+			"series", stremio.Stream{
+				Name:  "[AD] Jackettio 1080p",
+				Title: "The.Office.US.S01.1080p.WEB-DL.x264-NTb\nThe.Office.US.S01E02.Diversity.Day.1080p.WEB-DL.x264-NTb.mkv\nℹ️ Download required\n💾 1.2 GB 👥 12 ⚙️ eztv",
+				URL:   "https://jackettio.example.com/eyJ9/download/series/tt0386676:1:2/d4e5f6",
+			}, StreamExtractorResult{
+				TTitle:  "The.Office.US.S01.1080p.WEB-DL.x264-NTb",
+				Seeders: 12,
+				Result: &ptt.Result{
+					Codec:      "AVC",
+					Quality:    "WEB-DL",
+					Resolution: "1080p",
+					Site:       "eztv",
+					Size:       "1.2 GB",
+				},
+				Addon: StreamExtractorResultAddon{
+					Name: "Jackettio",
+				},
+				File: StreamExtractorResultFile{
+					Idx:  -1,
+					Name: "The.Office.US.S01E02.Diversity.Day.1080p.WEB-DL.x264-NTb.mkv",
+				},
+				Store: StreamExtractorResultStore{
+					Code: "AD",
+					Name: "alldebrid",
+				},
+				Episode: -1,
+				Season:  -1,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := StreamExtractorJackettio.Parse(&tc.stream, tc.sType)
+			tc.result.Category = tc.sType
+			tc.result.Result.Normalize()
+			tc.result.Raw.Name = tc.stream.Name
+			tc.result.Raw.Description = tc.stream.Title
+			assert.Equal(t, &tc.result, data)
+		})
+	}
+}
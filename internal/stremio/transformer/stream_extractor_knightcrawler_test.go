@@ -0,0 +1,93 @@
+package stremio_transformer
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/go-ptt"
+	"github.com/MunifTanjim/stremthru/stremio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamExtractorKnightcrawler(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		sType  string
+		stream stremio.Stream
+		result StreamExtractorResult
+	}{
+		{
+			"torrent",
+			"movie", stremio.Stream{
+				Name:      "Knightcrawler\n720p",
+				Title:     "Deadpool (2016) 720p BluRay x264 [Dual Audio] [Hindi (Line Audio) - English] ESubs - Downloadhub\n👤 3 💾 934.8 MB ⚙️ 1337x\nDual Audio / 🇬🇧 / 🇮🇳",
+				InfoHash:  "f5d0ab292f5a244a4b38efac9ae1f8d311179588",
+				FileIndex: 0,
+				BehaviorHints: &stremio.StreamBehaviorHints{
+					BingeGroup: "knightcrawler|720p|BluRay|x264",
+				},
+			}, StreamExtractorResult{
+				Hash:    "f5d0ab292f5a244a4b38efac9ae1f8d311179588",
+				TTitle:  "Deadpool (2016) 720p BluRay x264 [Dual Audio] [Hindi (Line Audio) - English] ESubs - Downloadhub",
+				Seeders: 3,
+				Result: &ptt.Result{
+					Codec:      "AVC",
+					Languages:  []string{"daud", "en", "hi"},
+					Quality:    "BluRay",
+					Resolution: "720p",
+					Site:       "1337x",
+					Size:       "934.8 MB",
+				},
+				Addon: StreamExtractorResultAddon{
+					Name: "Knightcrawler",
+				},
+				Episode: -1,
+				Season:  -1,
+			},
+		},
+		{
+			"debrid",
+			"movie", stremio.Stream{
+				Name:  "[RD+] Knightcrawler\n4k DV | HDR",
+				Title: "Deadpool [2016] 2160p Hybrid UHD BDRip DV HDR10 x265 TrueHD Atmos 7.1 Kira [SEV]\n👤 16 💾 22.42 GB ⚙️ 1337x",
+				URL:   "https://knightcrawler.example.com/realdebrid/APIKEY/9d5a3e0d4a5f3b0a1c7e4f0e2a8b6c4d2e0f1a3b/3",
+				BehaviorHints: &stremio.StreamBehaviorHints{
+					BingeGroup: "knightcrawler|4k|BDRip|DV|HDR|x265",
+				},
+			}, StreamExtractorResult{
+				Hash:    "9d5a3e0d4a5f3b0a1c7e4f0e2a8b6c4d2e0f1a3b",
+				TTitle:  "Deadpool [2016] 2160p Hybrid UHD BDRip DV HDR10 x265 TrueHD Atmos 7.1 Kira [SEV]",
+				Seeders: 16,
+				Result: &ptt.Result{
+					Codec:      "HEVC",
+					HDR:        []string{"DV", "HDR"},
+					Quality:    "BDRip",
+					Resolution: "4k",
+					Site:       "1337x",
+					Size:       "22.42 GB",
+				},
+				Addon: StreamExtractorResultAddon{
+					Name: "Knightcrawler",
+				},
+				File: StreamExtractorResultFile{
+					Idx: 3,
+				},
+				Store: StreamExtractorResultStore{
+					Code:     "RD",
+					Name:     "realdebrid",
+					IsCached: true,
+				},
+				Episode: -1,
+				Season:  -1,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := StreamExtractorKnightcrawler.Parse(&tc.stream, tc.sType)
+			tc.result.Category = tc.sType
+			tc.result.Result.Normalize()
+			tc.result.Raw.Name = tc.stream.Name
+			tc.result.Raw.Description = tc.stream.Title
+			assert.Equal(t, &tc.result, data)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package stremio_transformer
+
+import "strings"
+
+var StreamExtractorStremThru = StreamExtractorBlob(strings.TrimSpace(`
+name
+(?i)^(?:📎 )?(?:(?:✨ ?)?(?:(?:(?<store_is_cached>⚡️) )?\[(?<store_code>\w+)\])?(?: ?🔑)?\n)?(?<addon_name>[^\n]+)(?:\n(?<resolution>\d+[kp]))?$
+
+description
+(?m)^💿 (?<quality>.+?) ?(?:🎞️ .*)?$
+(?m)🎞️ (?<codec>.+)$
+(?m)^📺 (?<hdr>[^\s🎧]+(?:(?<hdr_sep> )[^\s🎧]+)*) ?(?:🎧.*)?$
+(?m)🎧 (?:.+ \| )?(?<channel>\d\.\d)(?:, .+)?$
+(?m)^(?:💾 \S+ \S+ )?📦 (?<size>\S+ \S+)
+👤 (?<seeders>\d+)
+(?m)⚙️ (?<group>.+?)(?: (?:🔗|🔍) .+)?$
+(?m)🔗 (?<site>.+?)(?: 🔍 .+)?$
+(?m)🔍 (?<indexer>.+)$
+(?m)^🌐 (?<language>\S+(?:(?<language_sep> )\S+)*)$
+(?m)^📄 (?<file_name>.+)$
+(?m)^📁 (?<t_title>.+)$
+
+url
+(?i)\/strem\/[^/]+\/(?<store_code>\w+)\/(?<hash>[a-f0-9]{40})\/(?<file_idx>-?\d+)\/
+`)).MustParse()
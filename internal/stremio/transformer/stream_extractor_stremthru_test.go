@@ -0,0 +1,120 @@
+package stremio_transformer
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/go-ptt"
+	"github.com/MunifTanjim/stremthru/stremio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamExtractorStremThru(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		sType  string
+		stream stremio.Stream
+		result StreamExtractorResult
+	}{
+		{
+			"torz - cached",
+			"movie", stremio.Stream{
+				Name:        "⚡️ [RD]\nTorz\n2160p",
+				Description: "💿 BluRay REMUX 🎞️ HEVC\n📺 DV HDR10 🎧 Atmos, TrueHD | 7.1\n💾 40.1 GB 📦 58.3 GB 👤 42 ⚙️ FraMeSToR 🔗 TorrentGalaxy\n🌐 🇬🇧 🇫🇷\n📄 Deadpool.2016.UHD.BluRay.2160p.TrueHD.Atmos.7.1.DV.HEVC.REMUX-FraMeSToR.mkv",
+				URL:         "https://stremthru.example.com/stremio/torz/eyJ9/_/strem/tt1431045/rd/e4f5d7a2f3dd6b7b1826bd77e316b6b5ba31eb72/0/Deadpool.2016.UHD.BluRay.2160p.TrueHD.Atmos.7.1.DV.HEVC.REMUX-FraMeSToR.mkv",
+			}, StreamExtractorResult{
+				Hash:    "e4f5d7a2f3dd6b7b1826bd77e316b6b5ba31eb72",
+				Seeders: 42,
+				Result: &ptt.Result{
+					Channels:   []string{"7.1"},
+					Codec:      "HEVC",
+					Group:      "FraMeSToR",
+					HDR:        []string{"DV", "HDR10"},
+					Languages:  []string{"en", "fr"},
+					Quality:    "BluRay REMUX",
+					Resolution: "2160p",
+					Site:       "TorrentGalaxy",
+					Size:       "58.3 GB",
+				},
+				Addon: StreamExtractorResultAddon{
+					Name: "Torz",
+				},
+				File: StreamExtractorResultFile{
+					Idx:  0,
+					Name: "Deadpool.2016.UHD.BluRay.2160p.TrueHD.Atmos.7.1.DV.HEVC.REMUX-FraMeSToR.mkv",
+				},
+				Store: StreamExtractorResultStore{
+					Code:     "RD",
+					Name:     "realdebrid",
+					IsCached: true,
+				},
+				Episode: -1,
+				Season:  -1,
+			},
+		},
+		{
+			"torz - p2p",
+			"movie", stremio.Stream{
+				Name:        "[P2P]\nTorz",
+				Description: "📁 A Simple Favor 2018 DVDRip x264 ESub [MW]",
+				InfoHash:    "387ccd318d583405bbadcec55b9b05029645dd1d",
+				FileIndex:   1,
+			}, StreamExtractorResult{
+				Hash:   "387ccd318d583405bbadcec55b9b05029645dd1d",
+				TTitle: "A Simple Favor 2018 DVDRip x264 ESub [MW]",
+				Result: &ptt.Result{},
+				Addon: StreamExtractorResultAddon{
+					Name: "Torz",
+				},
+				File: StreamExtractorResultFile{
+					Idx: 1,
+				},
+				Store: StreamExtractorResultStore{
+					Code: "P2P",
+				},
+				Episode: -1,
+				Season:  -1,
+			},
+		},
+		{
+			"newz - private",
+			"series", stremio.Stream{
+				Name:        "📎 [ST] 🔑\nNewz\n1080p",
+				Description: "💿 WEB-DL 🎞️ AVC\n📦 2.1 GB ⏱️ 2d ⚙️ NTb 🔍 NZBgeek\n📁 Show.S01E02.1080p.WEB-DL",
+				URL:         "https://stremthru.example.com/stremio/newz/eyJ9/_/strem/tt0000001:1:2/stream/st/https%3A%2F%2Fexample.com%2Fnzb/Show.S01E02.1080p.WEB-DL",
+			}, StreamExtractorResult{
+				TTitle: "Show.S01E02.1080p.WEB-DL",
+				Result: &ptt.Result{
+					Codec:      "AVC",
+					Group:      "NTb",
+					Quality:    "WEB-DL",
+					Resolution: "1080p",
+					Size:       "2.1 GB",
+				},
+				Addon: StreamExtractorResultAddon{
+					Name: "Newz",
+				},
+				File: StreamExtractorResultFile{
+					Idx: -1,
+				},
+				Indexer: StreamExtractorResultIndexer{
+					Name: "NZBgeek",
+				},
+				Store: StreamExtractorResultStore{
+					Code: "ST",
+					Name: "stremthru",
+				},
+				Episode: -1,
+				Season:  -1,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := StreamExtractorStremThru.Parse(&tc.stream, tc.sType)
+			tc.result.Category = tc.sType
+			tc.result.Result.Normalize()
+			tc.result.Raw.Name = tc.stream.Name
+			tc.result.Raw.Description = tc.stream.Description
+			assert.Equal(t, &tc.result, data)
+		})
+	}
+}
@@ -91,6 +91,23 @@ func (ValuePatcher) Visit(node *ast.Node) {
 	}
 }
 
+func getExprOptions(options ...expr.Option) []expr.Option {
+	return append([]expr.Option{
+		expr.Env(&StreamExtractorResult{}),
+		expr.AllowUndefinedVariables(),
+		expr.Function("__Resolution__", func(val ...any) (any, error) {
+			return Resolution(val[0].(string)), nil
+		}, new(func(string) Resolution)),
+		expr.Function("__Quality__", func(val ...any) (any, error) {
+			return Quality(val[0].(string)), nil
+		}, new(func(string) Quality)),
+		expr.Function("__Size__", func(val ...any) (any, error) {
+			return Size(val[0].(string)), nil
+		}, new(func(string) Size)),
+		expr.Patch(ValuePatcher{}),
+	}, options...)
+}
+
 type StreamFilterEnv struct {
 	*StreamExtractorResult
 }
@@ -111,22 +128,7 @@ func (sfb StreamFilterBlob) Parse() (*StreamFilter, error) {
 		return sf, nil
 	}
 
-	program, err := expr.Compile(
-		string(sfb),
-		expr.Env(&StreamExtractorResult{}),
-		expr.AsBool(),
-		expr.AllowUndefinedVariables(),
-		expr.Function("__Resolution__", func(val ...any) (any, error) {
-			return Resolution(val[0].(string)), nil
-		}, new(func(string) Resolution)),
-		expr.Function("__Quality__", func(val ...any) (any, error) {
-			return Quality(val[0].(string)), nil
-		}, new(func(string) Quality)),
-		expr.Function("__Size__", func(val ...any) (any, error) {
-			return Size(val[0].(string)), nil
-		}, new(func(string) Size)),
-		expr.Patch(ValuePatcher{}),
-	)
+	program, err := expr.Compile(string(sfb), getExprOptions(expr.AsBool())...)
 	if err != nil {
 		return sf, err
 	}
@@ -1,11 +1,16 @@
 package stremio_transformer
 
 import (
+	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/util"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
 )
 
 type StreamSortableField string
@@ -15,13 +20,27 @@ const (
 	StreamSortableFieldQuality    StreamSortableField = "quality"
 	StreamSortableFieldSize       StreamSortableField = "size"
 	StreamSortableFieldHDR        StreamSortableField = "hdr"
+	StreamSortableFieldCodec      StreamSortableField = "codec"
+	StreamSortableFieldAudio      StreamSortableField = "audio"
+	StreamSortableFieldAge        StreamSortableField = "age"
+	StreamSortableFieldLanguage   StreamSortableField = "language"
+	StreamSortableFieldIndexer    StreamSortableField = "indexer"
 )
 
+var streamSortableFields = []StreamSortableField{
+	StreamSortableFieldResolution,
+	StreamSortableFieldQuality,
+	StreamSortableFieldSize,
+	StreamSortableFieldHDR,
+	StreamSortableFieldCodec,
+	StreamSortableFieldAudio,
+	StreamSortableFieldAge,
+	StreamSortableFieldLanguage,
+	StreamSortableFieldIndexer,
+}
+
 type StreamSortable interface {
-	GetQuality() string
-	GetResolution() string
-	GetSize() string
-	GetHDR() string
+	GetExtractorResult() *StreamExtractorResult
 	IsSortable() bool
 }
 
@@ -114,43 +133,239 @@ func getHDRRank(input string) int64 {
 	return int64(len(input))
 }
 
-func getFieldRank(str StreamSortable, field StreamSortableField) int64 {
+func getCodecRank(input string) int64 {
+	switch strings.ToLower(input) {
+	case "av1":
+		return 50
+	case "hevc", "x265", "h265":
+		return 40
+	case "avc", "x264", "h264":
+		return 30
+	case "xvid", "divx", "dvix":
+		return 20
+	case "mpeg2":
+		return 10
+	}
+	return 0
+}
+
+var audioRank = map[string]int64{
+	"atmos":        100,
+	"truehd":       95,
+	"dts lossless": 90,
+	"flac":         85,
+	"pcm":          84,
+	"dts lossy":    70,
+	"ddp":          65,
+	"eac3":         65,
+	"dd":           60,
+	"ac3":          60,
+	"opus":         50,
+	"aac":          45,
+	"mp3":          30,
+	"hq":           10,
+}
+
+func getAudioRank(input []string) int64 {
+	rank := int64(0)
+	for _, audio := range input {
+		rank = max(rank, audioRank[strings.ToLower(audio)])
+	}
+	return rank
+}
+
+// getAgeRank returns the age in seconds, nil for unknown date.
+func getAgeRank(date time.Time) any {
+	if date.IsZero() {
+		return nil
+	}
+	return int64(time.Since(date) / time.Second)
+}
+
+func getLanguageRank(input []string) int64 {
+	return int64(len(input))
+}
+
+func getFieldValue(r *StreamExtractorResult, field StreamSortableField) any {
+	if r.Result == nil {
+		switch field {
+		case StreamSortableFieldAge:
+			return getAgeRank(r.Date)
+		case StreamSortableFieldIndexer:
+			return r.Indexer.Name
+		default:
+			return int64(0)
+		}
+	}
+
 	switch field {
 	case StreamSortableFieldResolution:
-		return getResolutionRank(str.GetResolution())
+		return getResolutionRank(r.Resolution)
 	case StreamSortableFieldQuality:
-		return getQualityRank(str.GetQuality())
+		return getQualityRank(r.Quality)
 	case StreamSortableFieldSize:
-		return getSizeRank(str.GetSize())
+		return getSizeRank(r.Size)
 	case StreamSortableFieldHDR:
-		return getHDRRank(str.GetHDR())
+		return getHDRRank(strings.Join(r.HDR, "|"))
+	case StreamSortableFieldCodec:
+		return getCodecRank(r.Codec)
+	case StreamSortableFieldAudio:
+		return getAudioRank(r.Audio)
+	case StreamSortableFieldAge:
+		return getAgeRank(r.Date)
+	case StreamSortableFieldLanguage:
+		return getLanguageRank(r.Languages)
+	case StreamSortableFieldIndexer:
+		return r.Indexer.Name
 	default:
 		panic("Unsupported field for sorting")
 	}
 }
 
+// toSortValue normalizes the value for comparison, returns nil if the value
+// is not comparable.
+func toSortValue(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case bool:
+		if v {
+			return int64(1)
+		}
+		return int64(0)
+	case string:
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		return v.UnixNano()
+	case time.Duration:
+		return int64(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	}
+	return nil
+}
+
+func compareSortValue(a, b any) int {
+	switch va := a.(type) {
+	case int64:
+		switch vb := b.(type) {
+		case int64:
+			return compareOrdered(va, vb)
+		case float64:
+			return compareOrdered(float64(va), vb)
+		}
+	case float64:
+		switch vb := b.(type) {
+		case int64:
+			return compareOrdered(va, float64(vb))
+		case float64:
+			return compareOrdered(va, vb)
+		}
+	case string:
+		if vb, ok := b.(string); ok {
+			return strings.Compare(va, vb)
+		}
+	}
+	return 0
+}
+
+func compareOrdered[T int64 | float64](a, b T) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
 type StreamSorterConfig struct {
-	Field StreamSortableField
-	Desc  bool
+	Field   StreamSortableField
+	Expr    string
+	Desc    bool
+	program *vm.Program
+}
+
+func (c StreamSorterConfig) getValue(r *StreamExtractorResult) any {
+	if c.program == nil {
+		return getFieldValue(r, c.Field)
+	}
+	output, err := expr.Run(c.program, r)
+	if err != nil {
+		return nil
+	}
+	return toSortValue(output)
+}
+
+// splitSortConfig splits the config by comma, ignoring the ones inside
+// quotes and brackets of expressions.
+func splitSortConfig(config string) []string {
+	parts := []string{}
+	depth := 0
+	var quote rune
+	start := 0
+	for i, c := range config {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, config[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, config[start:])
 }
 
 func parseSortConfig(config string) []StreamSorterConfig {
 	sortConfigs := []StreamSorterConfig{}
-	for part := range strings.SplitSeq(config, ",") {
+	for _, part := range splitSortConfig(config) {
 		part = strings.TrimSpace(part)
 		desc := strings.HasPrefix(part, "-")
-		field := StreamSortableField(strings.TrimPrefix(part, "-"))
-		switch field {
-		case StreamSortableFieldResolution, StreamSortableFieldQuality, StreamSortableFieldSize, StreamSortableFieldHDR:
+		part = strings.TrimSpace(strings.TrimPrefix(part, "-"))
+		if part == "" {
+			continue
+		}
+
+		field := StreamSortableField(strings.ToLower(part))
+		if slices.Contains(streamSortableFields, field) {
 			sortConfigs = append(sortConfigs, StreamSorterConfig{Field: field, Desc: desc})
+			continue
+		}
+
+		program, err := expr.Compile(part, getExprOptions()...)
+		if err != nil {
+			log.Warn("failed to parse sort expression", "expr", part, "error", err)
+			continue
 		}
+		sortConfigs = append(sortConfigs, StreamSorterConfig{Expr: part, Desc: desc, program: program})
 	}
 	return sortConfigs
 }
 
 type streamSorter[T StreamSortable] struct {
-	items  []T
-	config []StreamSorterConfig
+	items    []T
+	sortable []bool
+	values   [][]any
+	config   []StreamSorterConfig
 }
 
 func (ss streamSorter[StreamSortable]) Len() int {
@@ -158,29 +373,38 @@ func (ss streamSorter[StreamSortable]) Len() int {
 }
 func (ss streamSorter[StreamSortable]) Swap(i, j int) {
 	ss.items[i], ss.items[j] = ss.items[j], ss.items[i]
+	ss.sortable[i], ss.sortable[j] = ss.sortable[j], ss.sortable[i]
+	ss.values[i], ss.values[j] = ss.values[j], ss.values[i]
 }
 
 func (ss streamSorter[StreamSortable]) Less(a, b int) bool {
-	aData, bData := ss.items[a], ss.items[b]
-	if !bData.IsSortable() {
+	if !ss.sortable[b] {
 		return true
-	} else if !aData.IsSortable() {
+	} else if !ss.sortable[a] {
 		return false
 	}
 
-	for _, config := range ss.config {
-		va := getFieldRank(aData, config.Field)
-		vb := getFieldRank(bData, config.Field)
+	for i, config := range ss.config {
+		va, vb := ss.values[a][i], ss.values[b][i]
 
-		if va == vb {
+		// values that could not be evaluated always go last
+		if va == nil || vb == nil {
+			if va == nil && vb == nil {
+				continue
+			}
+			return vb == nil
+		}
+
+		cmp := compareSortValue(va, vb)
+		if cmp == 0 {
 			continue
 		}
 
 		if config.Desc {
-			return va > vb
+			return cmp > 0
 		}
 
-		return va < vb
+		return cmp < 0
 	}
 	return false
 }
@@ -196,6 +420,24 @@ func SortStreams[T StreamSortable](items []T, config string) {
 	if len(sortConfigs) == 0 {
 		return
 	}
-	sorter := streamSorter[T]{items: items, config: sortConfigs}
+
+	sorter := streamSorter[T]{
+		items:    items,
+		sortable: make([]bool, len(items)),
+		values:   make([][]any, len(items)),
+		config:   sortConfigs,
+	}
+	for i, item := range items {
+		if !item.IsSortable() {
+			continue
+		}
+		sorter.sortable[i] = true
+		r := item.GetExtractorResult()
+		values := make([]any, len(sortConfigs))
+		for j, config := range sortConfigs {
+			values[j] = config.getValue(r)
+		}
+		sorter.values[i] = values
+	}
 	sort.Stable(sorter)
 }
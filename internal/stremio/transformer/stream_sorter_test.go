@@ -0,0 +1,146 @@
+package stremio_transformer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MunifTanjim/go-ptt"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSortableStream struct {
+	name string
+	r    *StreamExtractorResult
+}
+
+func (s testSortableStream) GetExtractorResult() *StreamExtractorResult {
+	return s.r
+}
+
+func (s testSortableStream) IsSortable() bool {
+	return s.r != nil
+}
+
+func getSortedNames(items []testSortableStream) []string {
+	names := make([]string, len(items))
+	for i := range items {
+		names[i] = items[i].name
+	}
+	return names
+}
+
+func TestSplitSortConfig(t *testing.T) {
+	for _, tc := range []struct {
+		config   string
+		expected []string
+	}{
+		{"-resolution,size", []string{"-resolution", "size"}},
+		{`-Store.IsCached, "en" in Languages`, []string{"-Store.IsCached", ` "en" in Languages`}},
+		{`any(Languages, # in ["en", "fr"]),-Seeders`, []string{`any(Languages, # in ["en", "fr"])`, "-Seeders"}},
+		{`Raw.Name contains "a,b"`, []string{`Raw.Name contains "a,b"`}},
+	} {
+		t.Run(tc.config, func(t *testing.T) {
+			assert.Equal(t, tc.expected, splitSortConfig(tc.config))
+		})
+	}
+}
+
+func TestParseSortConfig(t *testing.T) {
+	configs := parseSortConfig(`-resolution,Codec,-Seeders,Seeders +,, "en" in Languages`)
+	assert.Len(t, configs, 4)
+	assert.Equal(t, StreamSortableFieldResolution, configs[0].Field)
+	assert.True(t, configs[0].Desc)
+	assert.Equal(t, StreamSortableFieldCodec, configs[1].Field)
+	assert.Equal(t, "Seeders", configs[2].Expr)
+	assert.True(t, configs[2].Desc)
+	assert.Equal(t, `"en" in Languages`, configs[3].Expr)
+	assert.False(t, configs[3].Desc)
+}
+
+func TestSortStreams(t *testing.T) {
+	newResult := func(resolution string, cached bool, seeders int, languages ...string) *StreamExtractorResult {
+		return &StreamExtractorResult{
+			Result: &ptt.Result{
+				Resolution: resolution,
+				Languages:  languages,
+			},
+			Seeders: seeders,
+			Store:   StreamExtractorResultStore{IsCached: cached},
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		config   string
+		items    []testSortableStream
+		expected []string
+	}{
+		{
+			name:   "default",
+			config: "",
+			items: []testSortableStream{
+				{"a", newResult("720p", false, 0)},
+				{"b", nil},
+				{"c", newResult("2160p", false, 0)},
+				{"d", newResult("1080p", false, 0)},
+			},
+			expected: []string{"c", "d", "a", "b"},
+		},
+		{
+			name:   "cached first, then preferred language, then seeders",
+			config: `-Store.IsCached,-("en" in Languages),-Seeders`,
+			items: []testSortableStream{
+				{"a", newResult("1080p", false, 100, "en")},
+				{"b", newResult("1080p", true, 5, "fr")},
+				{"c", newResult("1080p", true, 10, "en")},
+				{"d", newResult("1080p", true, 50, "en")},
+			},
+			expected: []string{"d", "c", "b", "a"},
+		},
+		{
+			name:   "weighted score",
+			config: `-(Seeders + (Store.IsCached ? 100 : 0))`,
+			items: []testSortableStream{
+				{"a", newResult("1080p", false, 150)},
+				{"b", newResult("1080p", true, 10)},
+				{"c", newResult("1080p", false, 90)},
+			},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:   "codec and audio",
+			config: "-codec,-audio",
+			items: []testSortableStream{
+				{"a", &StreamExtractorResult{Result: &ptt.Result{Codec: "avc", Audio: []string{"Atmos"}}}},
+				{"b", &StreamExtractorResult{Result: &ptt.Result{Codec: "hevc", Audio: []string{"AAC"}}}},
+				{"c", &StreamExtractorResult{Result: &ptt.Result{Codec: "hevc", Audio: []string{"AAC", "TrueHD"}}}},
+			},
+			expected: []string{"c", "b", "a"},
+		},
+		{
+			name:   "age, unknown last",
+			config: "age",
+			items: []testSortableStream{
+				{"a", &StreamExtractorResult{Result: &ptt.Result{}}},
+				{"b", &StreamExtractorResult{Result: &ptt.Result{}, Date: time.Now().Add(-48 * time.Hour)}},
+				{"c", &StreamExtractorResult{Result: &ptt.Result{}, Date: time.Now().Add(-1 * time.Hour)}},
+			},
+			expected: []string{"c", "b", "a"},
+		},
+		{
+			name:   "indexer",
+			config: "indexer,-language",
+			items: []testSortableStream{
+				{"a", &StreamExtractorResult{Result: &ptt.Result{}, Indexer: StreamExtractorResultIndexer{Name: "Zeta"}}},
+				{"b", &StreamExtractorResult{Result: &ptt.Result{Languages: []string{"en"}}, Indexer: StreamExtractorResultIndexer{Name: "Alpha"}}},
+				{"c", &StreamExtractorResult{Result: &ptt.Result{Languages: []string{"en", "fr"}}, Indexer: StreamExtractorResultIndexer{Name: "Alpha"}}},
+			},
+			expected: []string{"c", "b", "a"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			SortStreams(tc.items, tc.config)
+			assert.Equal(t, tc.expected, getSortedNames(tc.items))
+		})
+	}
+}
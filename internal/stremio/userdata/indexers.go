@@ -4,18 +4,21 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/cache"
 	torznab_client "github.com/MunifTanjim/stremthru/internal/torznab/client"
 	"github.com/MunifTanjim/stremthru/internal/torznab/jackett"
+	"github.com/MunifTanjim/stremthru/internal/torznab/prowlarr"
 )
 
 type IndexerName string
 
 const (
-	IndexerNameGeneric IndexerName = "generic"
-	IndexerNameJackett IndexerName = "jackett"
+	IndexerNameGeneric  IndexerName = "generic"
+	IndexerNameJackett  IndexerName = "jackett"
+	IndexerNameProwlarr IndexerName = "prowlarr"
 )
 
 type Indexer struct {
@@ -68,6 +71,56 @@ func (i Indexer) Validate() (string, error) {
 		if err := jackett.TorznabURL(i.URL).Parse(); err != nil {
 			return "url", fmt.Errorf("indexer url is invalid")
 		}
+	case IndexerNameProwlarr:
+		if !strings.HasPrefix(i.URL, "http://") && !strings.HasPrefix(i.URL, "https://") {
+			return "url", fmt.Errorf("indexer url is invalid")
+		}
+		if i.APIKey == "" {
+			return "apikey", fmt.Errorf("indexer api key is required")
+		}
+	}
+	return "", nil
+}
+
+func (i Indexer) getProwlarrClient() (*prowlarr.Client, error) {
+	key := i.URL + ":" + i.APIKey
+	var client *prowlarr.Client
+	if !prowlarrCache.Get(key, &client) {
+		client = prowlarr.NewClient(&prowlarr.ClientConfig{
+			BaseURL: i.URL,
+			APIKey:  i.APIKey,
+		})
+		if err := prowlarrCache.Add(key, client); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// Check verifies that the indexer is reachable. For prowlarr, every enabled
+// indexer is also checked, but their failures are only returned as warning
+// since the rest of the indexers are still usable.
+func (i Indexer) Check() (warning string, err error) {
+	switch i.Name {
+	case IndexerNameProwlarr:
+		client, err := i.getProwlarrClient()
+		if err != nil {
+			return "", err
+		}
+		clients, err := client.GetTorznabClients()
+		if err != nil {
+			return "", fmt.Errorf("failed to list indexers: %w", err)
+		}
+		if len(clients) == 0 {
+			return "", errors.New("no enabled torrent indexer found")
+		}
+		failed := []string{}
+		for _, c := range clients {
+			if _, err := c.GetCaps(); err != nil {
+				failed = append(failed, c.GetName()+": "+err.Error())
+			}
+		}
+		return strings.Join(failed, "\n"), nil
 	}
 	return "", nil
 }
@@ -100,6 +153,11 @@ var jackettCache = cache.NewLRUCache[*jackett.Client](&cache.CacheConfig{
 	Name:     "stremio:userdata:indexers:jackett",
 })
 
+var prowlarrCache = cache.NewLRUCache[*prowlarr.Client](&cache.CacheConfig{
+	Lifetime: 2 * time.Hour,
+	Name:     "stremio:userdata:indexers:prowlarr",
+})
+
 func (ud *UserDataIndexers) Compress() {
 	for i := range ud.Indexers {
 		indexer := &ud.Indexers[i]
@@ -144,6 +202,19 @@ func (ud *UserDataIndexers) Prepare() ([]torznab_client.Indexer, error) {
 			c := client.GetTorznabClient(u.IndexerId)
 			indexers = append(indexers, c)
 
+		case IndexerNameProwlarr:
+			client, err := indexer.getProwlarrClient()
+			if err != nil {
+				return indexers, err
+			}
+			clients, err := client.GetTorznabClients()
+			if err != nil {
+				return indexers, err
+			}
+			for _, c := range clients {
+				indexers = append(indexers, c)
+			}
+
 		default:
 			return indexers, errors.New("unsupported indexer: " + string(indexer.Name))
 		}
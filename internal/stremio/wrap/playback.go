@@ -73,6 +73,7 @@ func handleStrem(w http.ResponseWriter, r *http.Request) {
 
 	s := ud.GetStoreByCode(query.Get("s"))
 	ctx.Store, ctx.StoreAuthToken = s.Store, s.AuthToken
+	ctx.StremId = query.Get("sid")
 	storeCode := ctx.Store.GetName().Code()
 
 	cacheKey := strings.Join([]string{ctx.ClientIP, string(storeCode), ctx.StoreAuthToken, magnetHash, strconv.Itoa(fileIdx), fileName, query.Encode()}, ":")
@@ -196,7 +197,7 @@ func handleStrem(w http.ResponseWriter, r *http.Request) {
 			torrent_stream.TagStremId(magnet.Hash, file.GetPath(), sid)
 		}
 
-		glRes, err := shared.GenerateStremThruLink(r, &ctx.Context, link, fileName)
+		glRes, err := shared.GenerateStremThruMagnetFileLink(r, &ctx.Context, magnet.Hash, file, fileName)
 		if err != nil {
 			return &stremResult{
 				error_level: logger.LevelError,
@@ -40,6 +40,8 @@ func (ud UserData) fetchStream(ctx *Ctx, r *http.Request, rType, id string) (*st
 	upstreamsCount := len(upstreams)
 	log.Debug("found addons for stream", "count", upstreamsCount)
 
+	ud.detectUpstreamExtractors(ctx, upstreams)
+
 	chunksCount := upstreamsCount
 	if ud.IncludeTorz {
 		chunksCount += 1
@@ -105,14 +107,18 @@ func (ud UserData) fetchStream(ctx *Ctx, r *http.Request, rType, id string) (*st
 				if tmpl == nil || tmpl.IsEmpty() || tmpl.IsRaw() {
 					tmpl = stremio_transformer.StreamTemplateDefault
 				}
+				if wstream.R.Addon.Name != "" && len(wstream.R.Addon.Names) == 0 {
+					wstream.R.Addon.Names = []string{wstream.R.Addon.Name}
+				}
 				s, err := tmpl.Execute(stream, wstream.R)
 				if err != nil {
 					errs[0] = err
 					return
 				}
 				wstreams[i] = WrappedStream{
-					Stream: s,
-					r:      wstream.R,
+					Stream:   s,
+					r:        wstream.R,
+					template: tmpl,
 				}
 			}
 			chunks[0] = wstreams
@@ -190,7 +196,7 @@ func (ud UserData) fetchStream(ctx *Ctx, r *http.Request, rType, id string) (*st
 	}
 
 	if ud.IncludeTorz {
-		allStreams = dedupeStreams(allStreams)
+		allStreams = dedupeStreams(allStreams, ud.Dedupe)
 	}
 
 	if ud.Filter != "" {
@@ -207,7 +213,7 @@ func (ud UserData) fetchStream(ctx *Ctx, r *http.Request, rType, id string) (*st
 	}
 
 	if !ud.IncludeTorz {
-		allStreams = dedupeStreams(allStreams)
+		allStreams = dedupeStreams(allStreams, ud.Dedupe)
 	}
 
 	totalStreams := len(allStreams)
@@ -46,7 +46,7 @@ func getTemplateData(ud *UserData, w http.ResponseWriter, r *http.Request) *Temp
 			Type:        "text",
 			Default:     ud.Sort,
 			Title:       "Stream Sort",
-			Description: "Comma separated fields: <code>resolution</code>, <code>quality</code>, <code>size</code>, <code>hdr</code>. Prefix with <code>-</code> for reverse sort. Default: <code>" + stremio_transformer.StreamDefaultSortConfig + "</code>",
+			Description: "Comma separated fields: <code>resolution</code>, <code>quality</code>, <code>size</code>, <code>hdr</code>, <code>codec</code>, <code>audio</code>, <code>age</code>, <code>language</code>, <code>indexer</code>, or expressions like <code>Store.IsCached</code>, <code>\"en\" in Languages</code>, <code>Seeders</code>. Prefix with <code>-</code> for reverse sort. Default: <code>" + stremio_transformer.StreamDefaultSortConfig + "</code>",
 		},
 
 		FilterConfig: configure.Config{
@@ -57,6 +57,18 @@ func getTemplateData(ud *UserData, w http.ResponseWriter, r *http.Request) *Temp
 			Description: `Filter expression, check <a href="https://github.com/MunifTanjim/stremthru/wiki/Stream-Filter" target="_blank">documentation</a>.`,
 		},
 
+		DedupeConfig: configure.Config{
+			Key:         "dedupe",
+			Type:        configure.ConfigTypeSelect,
+			Default:     string(ud.Dedupe),
+			Title:       "Stream Deduplication",
+			Description: "Streams for the same torrent file and store, from different addons, are deduplicated. <code>Merge</code> combines seeders, languages and addon names (<code>Addon.Names</code>) of the duplicates.",
+			Options: []configure.ConfigOption{
+				{Label: "Merge", Value: string(UserDataDedupeModeMerge)},
+				{Label: "Keep First", Value: string(UserDataDedupeModeKeepFirst)},
+			},
+		},
+
 		RPDBAPIKey: configure.Config{
 			Key:          "rpdb_akey",
 			Type:         configure.ConfigTypePassword,
@@ -264,6 +276,7 @@ type TemplateData struct {
 	TemplateError    stremio_transformer.StreamTemplateBlob
 	SortConfig       configure.Config
 	FilterConfig     configure.Config
+	DedupeConfig     configure.Config
 	RPDBAPIKey       configure.Config
 	TopPostersAPIKey configure.Config
 
@@ -18,6 +18,7 @@ type StreamTransformer struct {
 type WrappedStream struct {
 	*stremio.Stream
 	r              *stremio_transformer.StreamExtractorResult
+	template       *stremio_transformer.StreamTemplate
 	noContentProxy bool
 }
 
@@ -25,20 +26,8 @@ func (ws WrappedStream) IsSortable() bool {
 	return ws.r != nil
 }
 
-func (ws WrappedStream) GetQuality() string {
-	return ws.r.Quality
-}
-
-func (ws WrappedStream) GetResolution() string {
-	return ws.r.Resolution
-}
-
-func (ws WrappedStream) GetSize() string {
-	return ws.r.Size
-}
-
-func (ws WrappedStream) GetHDR() string {
-	return strings.Join(ws.r.HDR, "|")
+func (ws WrappedStream) GetExtractorResult() *stremio_transformer.StreamExtractorResult {
+	return ws.r
 }
 
 func (st StreamTransformer) Do(stream *stremio.Stream, sType string, tryReconfigure bool) (*WrappedStream, error) {
@@ -68,9 +57,14 @@ func (st StreamTransformer) Do(stream *stremio.Stream, sType string, tryReconfig
 		}
 	}
 
+	if data.Addon.Name != "" && len(data.Addon.Names) == 0 {
+		data.Addon.Names = []string{data.Addon.Name}
+	}
+
 	s.r = data
 
 	if st.Template != nil && !st.Template.IsEmpty() {
+		s.template = st.Template
 		var err error
 		s.Stream, err = st.Template.Execute(s.Stream, data)
 		if err != nil {
@@ -106,10 +100,48 @@ var builtInExtractors = func() map[string]stremio_transformer.StreamExtractorBlo
 	extractors[BUILTIN_TRANSFORMER_ENTITY_ID_PREFIX+"Peerflix"] = stremio_transformer.StreamExtractorPeerflix.Blob
 	extractors[BUILTIN_TRANSFORMER_ENTITY_ID_PREFIX+"Torrentio"] = stremio_transformer.StreamExtractorTorrentio.Blob
 	extractors[BUILTIN_TRANSFORMER_ENTITY_ID_PREFIX+"Orion"] = stremio_transformer.StreamExtractorOrion.Blob
+	extractors[BUILTIN_TRANSFORMER_ENTITY_ID_PREFIX+"Jackettio"] = stremio_transformer.StreamExtractorJackettio.Blob
+	extractors[BUILTIN_TRANSFORMER_ENTITY_ID_PREFIX+"Knightcrawler"] = stremio_transformer.StreamExtractorKnightcrawler.Blob
+	extractors[BUILTIN_TRANSFORMER_ENTITY_ID_PREFIX+"AIOStreams"] = stremio_transformer.StreamExtractorAIOStreams.Blob
+	extractors[BUILTIN_TRANSFORMER_ENTITY_ID_PREFIX+"StremThru"] = stremio_transformer.StreamExtractorStremThru.Blob
 
 	return extractors
 }()
 
+// detectExtractorId returns the built-in extractor id for the upstream addon,
+// based on its manifest id.
+func detectExtractorId(manifestId string) string {
+	id := strings.ToLower(manifestId)
+	name := ""
+	switch {
+	case id == "":
+	case strings.HasSuffix(id, ".torz"), strings.HasSuffix(id, ".newz"):
+		name = "StremThru"
+	case strings.Contains(id, "aiostreams"):
+		name = "AIOStreams"
+	case strings.Contains(id, "jackettio"):
+		name = "Jackettio"
+	case strings.Contains(id, "knightcrawler"):
+		name = "Knightcrawler"
+	case strings.Contains(id, "torrentio"):
+		name = "Torrentio"
+	case strings.Contains(id, "comet"):
+		name = "Comet"
+	case strings.Contains(id, "mediafusion"):
+		name = "MediaFusion"
+	case strings.Contains(id, "debridio"):
+		name = "Debridio"
+	case strings.Contains(id, "peerflix"):
+		name = "Peerflix"
+	case strings.Contains(id, "orion"):
+		name = "Orion"
+	}
+	if name == "" {
+		return ""
+	}
+	return BUILTIN_TRANSFORMER_ENTITY_ID_PREFIX + name
+}
+
 var extractorStore = kv.NewKVStore[stremio_transformer.StreamExtractorBlob](&kv.KVStoreConfig{
 	Type: "st:wrap:transformer:extractor",
 	GetKey: func(key string) string {
@@ -66,6 +66,22 @@ type UserDataUpstream struct {
 	ReconfigureStore bool                                    `json:"rs,omitempty"`
 }
 
+type UserDataDedupeMode string
+
+const (
+	UserDataDedupeModeMerge     UserDataDedupeMode = "merge"
+	UserDataDedupeModeKeepFirst UserDataDedupeMode = "first"
+)
+
+func (m UserDataDedupeMode) IsValid() bool {
+	switch m {
+	case UserDataDedupeModeMerge, UserDataDedupeModeKeepFirst:
+		return true
+	default:
+		return false
+	}
+}
+
 type UserData struct {
 	Upstreams   []UserDataUpstream `json:"upstreams"`
 	ManifestURL string             `json:"manifest_url,omitempty"`
@@ -81,8 +97,9 @@ type UserData struct {
 	TemplateId string                                 `json:"template,omitempty"`
 	template   stremio_transformer.StreamTemplateBlob `json:"-"`
 
-	Sort   string `json:"sort,omitempty"`
-	Filter string `json:"filter,omitempty"`
+	Sort   string             `json:"sort,omitempty"`
+	Filter string             `json:"filter,omitempty"`
+	Dedupe UserDataDedupeMode `json:"dedupe,omitempty"`
 
 	RPDBAPIKey       string `json:"rpdb_akey,omitempty"`
 	TopPostersAPIKey string `json:"top_posters_akey,omitempty"`
@@ -299,6 +316,36 @@ func (ud UserData) getUpstreamManifests(ctx *Ctx, useCache bool) ([]stremio.Mani
 	return ud.manifests, nil
 }
 
+// detectUpstreamExtractors sets a built-in extractor, picked using the
+// manifest id, for the upstreams without a configured extractor.
+func (ud UserData) detectUpstreamExtractors(ctx *Ctx, upstreams []UserDataUpstream) {
+	var manifestIdByUrl map[string]string
+	for i := range upstreams {
+		up := &upstreams[i]
+		if up.ExtractorId != "" || up.extractor != "" || up.baseUrl == nil {
+			continue
+		}
+
+		if manifestIdByUrl == nil {
+			manifestIdByUrl = map[string]string{}
+			manifests, _ := ud.getUpstreamManifests(ctx, true)
+			for mIdx := range manifests {
+				manifestIdByUrl[ud.Upstreams[mIdx].baseUrl.String()] = manifests[mIdx].ID
+			}
+		}
+
+		extractorId := detectExtractorId(manifestIdByUrl[up.baseUrl.String()])
+		if extractorId == "" {
+			continue
+		}
+		if extractor, err := getExtractor(extractorId); err != nil {
+			ctx.Log.Warn("failed to fetch detected extractor", "error", err, "extractor_id", extractorId)
+		} else {
+			up.extractor = extractor
+		}
+	}
+}
+
 func (ud UserData) getUpstreamsResolver(ctx *Ctx) (upstreamsResolver, error) {
 	eud := ud.GetEncoded()
 
@@ -521,6 +568,9 @@ func getUserData(r *http.Request) (*UserData, error) {
 		data.IncludeTorz = r.Form.Get("torz") == "on"
 		data.Sort = r.Form.Get("sort")
 		data.Filter = r.Form.Get("filter")
+		if dedupe := UserDataDedupeMode(r.Form.Get("dedupe")); dedupe.IsValid() && dedupe != UserDataDedupeModeMerge {
+			data.Dedupe = dedupe
+		}
 		data.RPDBAPIKey = r.Form.Get("rpdb_akey")
 		data.TopPostersAPIKey = r.Form.Get("top_posters_akey")
 
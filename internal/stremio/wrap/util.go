@@ -1,8 +1,13 @@
 package stremio_wrap
 
 import (
+	"slices"
+	"strconv"
+	"strings"
+
 	"github.com/MunifTanjim/stremthru/internal/shared"
 	stremio_shared "github.com/MunifTanjim/stremthru/internal/stremio/shared"
+	stremio_transformer "github.com/MunifTanjim/stremthru/internal/stremio/transformer"
 )
 
 type Ctx = stremio_shared.Ctx
@@ -14,20 +19,72 @@ var ExtractRequestBaseURL = shared.ExtractRequestBaseURL
 var SendResponse = stremio_shared.SendResponse
 var SendHTML = stremio_shared.SendHTML
 
-func dedupeStreams(allStreams []WrappedStream) []WrappedStream {
-	hashSeen := map[string]struct{}{}
+func getDedupeKey(r *stremio_transformer.StreamExtractorResult) string {
+	if r == nil || r.Hash == "" {
+		return ""
+	}
+	file := ""
+	if r.File.Idx >= 0 {
+		file = strconv.Itoa(r.File.Idx)
+	} else if r.File.Name != "" {
+		file = strings.ToLower(r.File.Name)
+	}
+	return strings.ToLower(r.Hash) + ":" + file + ":" + strings.ToUpper(r.Store.Code)
+}
+
+func mergeStreamExtractorResult(r, other *stremio_transformer.StreamExtractorResult) {
+	if other.Seeders > r.Seeders {
+		r.Seeders = other.Seeders
+	}
+	for _, lang := range other.Languages {
+		if !slices.Contains(r.Languages, lang) {
+			r.Languages = append(r.Languages, lang)
+		}
+	}
+	for _, name := range other.Addon.Names {
+		if !slices.Contains(r.Addon.Names, name) {
+			r.Addon.Names = append(r.Addon.Names, name)
+		}
+	}
+}
+
+// dedupeStreams drops the streams for the same file of a torrent on the same
+// store. Unless the mode is keep-first, metadata from the dropped copies is
+// merged into the first one and it is rendered again.
+func dedupeStreams(allStreams []WrappedStream, mode UserDataDedupeMode) []WrappedStream {
+	idxByKey := map[string]int{}
+	mergedIdxs := []int{}
 
 	streams := []WrappedStream{}
 	for i := range allStreams {
 		s := allStreams[i]
-		if s.r != nil && s.r.Hash != "" {
-			if _, seen := hashSeen[s.r.Hash]; seen {
+		if key := getDedupeKey(s.r); key != "" {
+			if idx, seen := idxByKey[key]; seen {
+				if mode != UserDataDedupeModeKeepFirst {
+					mergeStreamExtractorResult(streams[idx].r, s.r)
+					if !slices.Contains(mergedIdxs, idx) {
+						mergedIdxs = append(mergedIdxs, idx)
+					}
+				}
 				continue
-			} else {
-				hashSeen[s.r.Hash] = struct{}{}
 			}
+			idxByKey[key] = len(streams)
 		}
 		streams = append(streams, s)
 	}
+
+	for _, idx := range mergedIdxs {
+		s := &streams[idx]
+		if s.template == nil {
+			continue
+		}
+		stream, err := s.template.Execute(s.Stream, s.r)
+		if err != nil {
+			log.Warn("failed to render merged stream", "error", err)
+			continue
+		}
+		s.Stream = stream
+	}
+
 	return streams
 }
@@ -0,0 +1,62 @@
+package stremio_wrap
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/go-ptt"
+	stremio_transformer "github.com/MunifTanjim/stremthru/internal/stremio/transformer"
+	"github.com/MunifTanjim/stremthru/stremio"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupeStreams(t *testing.T) {
+	newStream := func(addonName, hash string, fileIdx int, storeCode string, seeders int, languages ...string) WrappedStream {
+		return WrappedStream{
+			Stream: &stremio.Stream{Name: addonName},
+			r: &stremio_transformer.StreamExtractorResult{
+				Result:  &ptt.Result{Languages: languages},
+				Addon:   stremio_transformer.StreamExtractorResultAddon{Name: addonName, Names: []string{addonName}},
+				Hash:    hash,
+				File:    stremio_transformer.StreamExtractorResultFile{Idx: fileIdx},
+				Seeders: seeders,
+				Store:   stremio_transformer.StreamExtractorResultStore{Code: storeCode},
+			},
+			template: stremio_transformer.StreamTemplateBlob{
+				Name: `{{str_join .Addon.Names ", "}}`,
+			}.MustParse(),
+		}
+	}
+
+	getStreams := func() []WrappedStream {
+		return []WrappedStream{
+			newStream("Torrentio", "a6a80257d62e53e55c877a7067ea5055129b462c", 1, "RD", 10, "en"),
+			newStream("Comet", "a6a80257d62e53e55c877a7067ea5055129b462c", 1, "RD", 25, "fr"),
+			newStream("Comet", "a6a80257d62e53e55c877a7067ea5055129b462c", 2, "RD", 25),
+			newStream("MediaFusion", "a6a80257d62e53e55c877a7067ea5055129b462c", 1, "AD", 5),
+			newStream("MediaFusion", "A6A80257D62E53E55C877A7067EA5055129B462C", 1, "rd", 30, "en", "es"),
+			{Stream: &stremio.Stream{Name: "Unknown"}},
+		}
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		streams := dedupeStreams(getStreams(), "")
+		names := []string{}
+		for i := range streams {
+			names = append(names, streams[i].Name)
+		}
+		assert.Equal(t, []string{"Torrentio, Comet, MediaFusion", "Comet", "MediaFusion", "Unknown"}, names)
+		assert.Equal(t, 30, streams[0].r.Seeders)
+		assert.Equal(t, []string{"en", "fr", "es"}, streams[0].r.Languages)
+	})
+
+	t.Run("keep first", func(t *testing.T) {
+		streams := dedupeStreams(getStreams(), UserDataDedupeModeKeepFirst)
+		names := []string{}
+		for i := range streams {
+			names = append(names, streams[i].Name)
+		}
+		assert.Equal(t, []string{"Torrentio", "Comet", "MediaFusion", "Unknown"}, names)
+		assert.Equal(t, 10, streams[0].r.Seeders)
+		assert.Equal(t, []string{"en"}, streams[0].r.Languages)
+	})
+}
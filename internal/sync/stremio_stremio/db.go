@@ -227,6 +227,9 @@ func UnlinkByStremioAccount(accountId string) error {
 	if err := UnlinkByAccountB(accountId); err != nil {
 		return err
 	}
+	if err := RemoveAccountFromGroups(accountId); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,229 @@
+package sync_stremio_stremio
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/db"
+	"github.com/rs/xid"
+)
+
+const GroupTableName = "sync_stremio_stremio_group"
+
+type GroupSyncConfigWatched struct {
+	Enabled bool     `json:"enabled"`
+	Ids     []string `json:"ids"` // empty means the whole library
+}
+
+type GroupSyncConfig struct {
+	Watched GroupSyncConfigWatched `json:"watched"`
+}
+
+func (sc GroupSyncConfig) Value() (driver.Value, error) {
+	return db.JSONValue(sc)
+}
+
+func (sc *GroupSyncConfig) Scan(value any) error {
+	return db.JSONScan(value, sc)
+}
+
+type SyncStremioStremioGroup struct {
+	Id         string
+	Name       string
+	AccountIds db.JSONStringList
+	SyncConfig GroupSyncConfig
+	SyncState  SyncState
+	CAt        db.Timestamp
+	UAt        db.Timestamp
+}
+
+var GroupColumn = struct {
+	Id         string
+	Name       string
+	AccountIds string
+	SyncConfig string
+	SyncState  string
+	CAt        string
+	UAt        string
+}{
+	Id:         "id",
+	Name:       "name",
+	AccountIds: "account_ids",
+	SyncConfig: "sync_config",
+	SyncState:  "sync_state",
+	CAt:        "cat",
+	UAt:        "uat",
+}
+
+var group_columns = []string{
+	GroupColumn.Id,
+	GroupColumn.Name,
+	GroupColumn.AccountIds,
+	GroupColumn.SyncConfig,
+	GroupColumn.SyncState,
+	GroupColumn.CAt,
+	GroupColumn.UAt,
+}
+
+type groupRowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanGroup(row groupRowScanner) (*SyncStremioStremioGroup, error) {
+	item := SyncStremioStremioGroup{}
+	if err := row.Scan(
+		&item.Id,
+		&item.Name,
+		&item.AccountIds,
+		&item.SyncConfig,
+		&item.SyncState,
+		&item.CAt,
+		&item.UAt,
+	); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+var query_get_all_groups = fmt.Sprintf(
+	`SELECT %s FROM %s ORDER BY %s`,
+	strings.Join(group_columns, ", "),
+	GroupTableName,
+	GroupColumn.CAt,
+)
+
+func GetAllGroups() ([]SyncStremioStremioGroup, error) {
+	rows, err := db.Query(query_get_all_groups)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []SyncStremioStremioGroup{}
+	for rows.Next() {
+		item, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, nil
+}
+
+var query_get_group_by_id = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ?`,
+	strings.Join(group_columns, ", "),
+	GroupTableName,
+	GroupColumn.Id,
+)
+
+func GetGroupById(id string) (*SyncStremioStremioGroup, error) {
+	item, err := scanGroup(db.QueryRow(query_get_group_by_id, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+var query_insert_group = fmt.Sprintf(
+	`INSERT INTO %s (%s) VALUES (?,?,?,?)`,
+	GroupTableName,
+	db.JoinColumnNames(
+		GroupColumn.Id,
+		GroupColumn.Name,
+		GroupColumn.AccountIds,
+		GroupColumn.SyncConfig,
+	),
+)
+
+func CreateGroup(name string, accountIds []string, syncConfig GroupSyncConfig) (*SyncStremioStremioGroup, error) {
+	id := xid.New().String()
+
+	_, err := db.Exec(query_insert_group, id, name, db.JSONStringList(accountIds), syncConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &SyncStremioStremioGroup{
+		Id:         id,
+		Name:       name,
+		AccountIds: accountIds,
+		SyncConfig: syncConfig,
+		SyncState:  SyncState{},
+		CAt:        db.Timestamp{Time: now},
+		UAt:        db.Timestamp{Time: now},
+	}, nil
+}
+
+var query_update_group = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = ?, %s = ?, %s = %s WHERE %s = ?`,
+	GroupTableName,
+	GroupColumn.Name,
+	GroupColumn.AccountIds,
+	GroupColumn.SyncConfig,
+	GroupColumn.UAt, db.CurrentTimestamp,
+	GroupColumn.Id,
+)
+
+func UpdateGroup(id string, name string, accountIds []string, syncConfig GroupSyncConfig) error {
+	_, err := db.Exec(query_update_group, name, db.JSONStringList(accountIds), syncConfig, id)
+	return err
+}
+
+var query_delete_group = fmt.Sprintf(
+	`DELETE FROM %s WHERE %s = ?`,
+	GroupTableName,
+	GroupColumn.Id,
+)
+
+func DeleteGroup(id string) error {
+	_, err := db.Exec(query_delete_group, id)
+	return err
+}
+
+var query_set_group_sync_state = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ?`,
+	GroupTableName,
+	GroupColumn.SyncState,
+	GroupColumn.UAt, db.CurrentTimestamp,
+	GroupColumn.Id,
+)
+
+func SetGroupSyncState(id string, syncState SyncState) error {
+	_, err := db.Exec(query_set_group_sync_state, syncState, id)
+	return err
+}
+
+// groups left with less than 2 accounts are deleted
+func RemoveAccountFromGroups(accountId string) error {
+	groups, err := GetAllGroups()
+	if err != nil {
+		return err
+	}
+	for i := range groups {
+		group := &groups[i]
+		if !slices.Contains(group.AccountIds, accountId) {
+			continue
+		}
+		accountIds := slices.DeleteFunc(slices.Clone(group.AccountIds), func(id string) bool {
+			return id == accountId
+		})
+		if len(accountIds) < 2 {
+			err = DeleteGroup(group.Id)
+		} else {
+			err = UpdateGroup(group.Id, group.Name, accountIds, group.SyncConfig)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
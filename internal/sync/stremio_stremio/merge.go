@@ -0,0 +1,371 @@
+package sync_stremio_stremio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	stremio_api "github.com/MunifTanjim/stremthru/internal/stremio/api"
+	"github.com/MunifTanjim/stremthru/internal/stremio/cinemeta"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	stremio_watched_bitfield "github.com/MunifTanjim/stremthru/stremio/watched_bitfield"
+)
+
+type GroupSyncChangeAction string
+
+const (
+	GroupSyncChangeActionAdd    GroupSyncChangeAction = "add"
+	GroupSyncChangeActionUpdate GroupSyncChangeAction = "update"
+	GroupSyncChangeActionRemove GroupSyncChangeAction = "remove"
+)
+
+type GroupSyncChange struct {
+	Action GroupSyncChangeAction `json:"action"`
+	Id     string                `json:"id"`
+	Type   string                `json:"type"`
+	Name   string                `json:"name"`
+	// account the playback state is taken from, i.e. the one with latest mtime
+	SourceAccountId string `json:"source_account_id,omitempty"`
+	// newly watched video ids
+	Watched []string `json:"watched,omitempty"`
+
+	item stremio_api.LibraryItem
+}
+
+type GroupSyncPlanAccount struct {
+	AccountId string            `json:"account_id"`
+	Changes   []GroupSyncChange `json:"changes"`
+}
+
+type GroupSyncPlan struct {
+	IsFullSync bool                   `json:"is_full_sync"`
+	Accounts   []GroupSyncPlanAccount `json:"accounts"`
+}
+
+func (p *GroupSyncPlan) ChangeCount() int {
+	count := 0
+	for i := range p.Accounts {
+		count += len(p.Accounts[i].Changes)
+	}
+	return count
+}
+
+func isInLibrary(item *stremio_api.LibraryItem) bool {
+	return !item.Removed && !item.Temp
+}
+
+func isStateDifferent(a, b *stremio_api.LibraryItemState) bool {
+	return a.VideoId != b.VideoId || a.TimeOffset != b.TimeOffset || a.Duration != b.Duration
+}
+
+// MergeLibraryItems plans the changes needed for every account to end up with
+// the same library items. For each item, the one with the latest mtime wins,
+// i.e. its playback state is used and if it is removed from the library, it
+// is removed from the other accounts too. Watched state is the union across
+// all accounts.
+func MergeLibraryItems(
+	accountIds []string,
+	itemsByAccountId map[string][]stremio_api.LibraryItem,
+	getVideoIds func(seriesId string) ([]string, error),
+	now time.Time,
+) ([]GroupSyncPlanAccount, error) {
+	itemByIdByAccountId := make(map[string]map[string]*stremio_api.LibraryItem, len(accountIds))
+	ids := []string{}
+	seenIds := util.NewSet[string]()
+	for _, accountId := range accountIds {
+		items := itemsByAccountId[accountId]
+		itemById := make(map[string]*stremio_api.LibraryItem, len(items))
+		for i := range items {
+			item := &items[i]
+			itemById[item.Id] = item
+			if !seenIds.Has(item.Id) {
+				seenIds.Add(item.Id)
+				ids = append(ids, item.Id)
+			}
+		}
+		itemByIdByAccountId[accountId] = itemById
+	}
+	slices.Sort(ids)
+
+	changesByAccountId := make(map[string][]GroupSyncChange, len(accountIds))
+
+	for _, id := range ids {
+		var winnerAccountId string
+		var winner *stremio_api.LibraryItem
+		for _, accountId := range accountIds {
+			if item, ok := itemByIdByAccountId[accountId][id]; ok {
+				if winner == nil || item.MTime.After(winner.MTime.Time) {
+					winner, winnerAccountId = item, accountId
+				}
+			}
+		}
+
+		isRemoved := !isInLibrary(winner)
+		if isRemoved {
+			inLibrary := false
+			for _, accountId := range accountIds {
+				if item, ok := itemByIdByAccountId[accountId][id]; ok && isInLibrary(item) {
+					inLibrary = true
+					break
+				}
+			}
+			if !inLibrary {
+				continue
+			}
+		}
+
+		var lastWatched time.Time
+		timesWatched := 0
+		for _, accountId := range accountIds {
+			if item, ok := itemByIdByAccountId[accountId][id]; ok {
+				timesWatched = max(timesWatched, item.State.TimesWatched)
+				if item.State.LastWatched.After(lastWatched) {
+					lastWatched = item.State.LastWatched
+				}
+			}
+		}
+
+		var videoIds []string
+		var unionWbf *stremio_watched_bitfield.WatchedBitField
+		wbfByAccountId := map[string]*stremio_watched_bitfield.WatchedBitField{}
+		if winner.Type == "series" {
+			for _, accountId := range accountIds {
+				item, ok := itemByIdByAccountId[accountId][id]
+				if !ok || item.State.Watched == "" {
+					continue
+				}
+				if videoIds == nil {
+					var err error
+					if videoIds, err = getVideoIds(id); err != nil {
+						return nil, fmt.Errorf("failed to get video ids for %s: %w", id, err)
+					}
+					unionWbf = stremio_watched_bitfield.NewWatchedBitField(stremio_watched_bitfield.NewBitField8(len(videoIds)), videoIds)
+				}
+				wbf, err := stremio_watched_bitfield.NewWatchedBitFieldFromString(item.State.Watched, videoIds)
+				if err != nil {
+					continue
+				}
+				wbfByAccountId[accountId] = wbf
+				for _, videoId := range videoIds {
+					if wbf.GetVideo(videoId) {
+						unionWbf.SetVideo(videoId, true)
+					}
+				}
+			}
+		}
+
+		unionWatched := ""
+		if unionWbf != nil {
+			var err error
+			if unionWatched, err = unionWbf.String(); err != nil {
+				return nil, fmt.Errorf("failed to serialize watched bitfield for %s: %w", id, err)
+			}
+		}
+
+		for _, accountId := range accountIds {
+			change := GroupSyncChange{
+				Id:   id,
+				Type: winner.Type,
+				Name: winner.Name,
+			}
+
+			item, exists := itemByIdByAccountId[accountId][id]
+			var target stremio_api.LibraryItem
+			if isRemoved {
+				if !exists || !isInLibrary(item) {
+					continue
+				}
+				change.Action = GroupSyncChangeActionRemove
+				change.SourceAccountId = winnerAccountId
+				target = *item
+				target.Removed = true
+			} else if exists && isInLibrary(item) {
+				change.Action = GroupSyncChangeActionUpdate
+				target = *item
+				if accountId != winnerAccountId && item.MTime.Before(winner.MTime.Time) && isStateDifferent(&item.State, &winner.State) {
+					change.SourceAccountId = winnerAccountId
+					target.State = winner.State
+				}
+			} else {
+				change.Action = GroupSyncChangeActionAdd
+				change.SourceAccountId = winnerAccountId
+				target = *winner
+				target.CTime = stremio_api.JSONTime{Time: now}
+			}
+
+			switch winner.Type {
+			case "movie":
+				if timesWatched > 0 && (!exists || item.State.TimesWatched == 0) {
+					change.Watched = append(change.Watched, id)
+				}
+				target.State.TimesWatched = timesWatched
+			case "series":
+				if unionWbf != nil {
+					ownWbf := wbfByAccountId[accountId]
+					for _, videoId := range videoIds {
+						if unionWbf.GetVideo(videoId) && (!exists || ownWbf == nil || !ownWbf.GetVideo(videoId)) {
+							change.Watched = append(change.Watched, videoId)
+						}
+					}
+					target.State.Watched = unionWatched
+				}
+			}
+
+			if change.Action == GroupSyncChangeActionUpdate && change.SourceAccountId == "" && len(change.Watched) == 0 {
+				continue
+			}
+
+			target.State.LastWatched = lastWatched
+			target.MTime = stremio_api.JSONTime{Time: now}
+			change.item = target
+			changesByAccountId[accountId] = append(changesByAccountId[accountId], change)
+		}
+	}
+
+	accounts := make([]GroupSyncPlanAccount, len(accountIds))
+	for i, accountId := range accountIds {
+		accounts[i] = GroupSyncPlanAccount{
+			AccountId: accountId,
+			Changes:   changesByAccountId[accountId],
+		}
+		if accounts[i].Changes == nil {
+			accounts[i].Changes = []GroupSyncChange{}
+		}
+	}
+	return accounts, nil
+}
+
+type GroupMember struct {
+	AccountId string
+	Token     string
+}
+
+func getChangedLibraryItemIds(client *stremio_api.Client, token string, startAt time.Time) ([]string, error) {
+	res, err := client.GetAllLibraryItemTimestamps(&stremio_api.GetAllLibraryItemTimestampsParams{
+		Ctx: stremio_api.Ctx{APIKey: token},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, ts := range res.Data {
+		if strings.HasPrefix(ts.Id, "tt") && ts.ModifiedAt.After(startAt) {
+			ids = append(ids, ts.Id)
+		}
+	}
+	return ids, nil
+}
+
+// PlanGroupSync fetches the library items of the group members and plans the
+// changes needed to merge them. Only items modified after startAt in any of the
+// accounts are considered, unless startAt is zero.
+func PlanGroupSync(members []GroupMember, includeIds []string, startAt time.Time, now time.Time) (*GroupSyncPlan, error) {
+	client := stremio_api.NewClient(&stremio_api.ClientConfig{})
+
+	plan := &GroupSyncPlan{
+		IsFullSync: startAt.IsZero(),
+	}
+
+	includeIdSet := util.NewSet[string]()
+	for _, id := range includeIds {
+		includeIdSet.Add(id)
+	}
+
+	ids := includeIds
+	if !plan.IsFullSync {
+		changedIdSet := util.NewSet[string]()
+		for _, member := range members {
+			changedIds, err := getChangedLibraryItemIds(client, member.Token, startAt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch library item timestamps for account %s: %w", member.AccountId, err)
+			}
+			for _, id := range changedIds {
+				if len(includeIds) == 0 || includeIdSet.Has(id) {
+					changedIdSet.Add(id)
+				}
+			}
+		}
+		ids = changedIdSet.ToSlice()
+		if len(ids) == 0 {
+			for _, member := range members {
+				plan.Accounts = append(plan.Accounts, GroupSyncPlanAccount{AccountId: member.AccountId, Changes: []GroupSyncChange{}})
+			}
+			return plan, nil
+		}
+	}
+
+	accountIds := make([]string, len(members))
+	itemsByAccountId := make(map[string][]stremio_api.LibraryItem, len(members))
+	for i, member := range members {
+		accountIds[i] = member.AccountId
+		res, err := client.GetAllLibraryItems(&stremio_api.GetAllLibraryItemsParams{
+			Ctx: stremio_api.Ctx{APIKey: member.Token},
+			Ids: ids,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch library items for account %s: %w", member.AccountId, err)
+		}
+		items := []stremio_api.LibraryItem{}
+		for _, item := range res.Data {
+			// removed items are kept, so that removal is synced too
+			if !strings.HasPrefix(item.Id, "tt") {
+				continue
+			}
+			if len(includeIds) > 0 && !includeIdSet.Has(item.Id) {
+				continue
+			}
+			if item.Type != "movie" && item.Type != "series" {
+				continue
+			}
+			items = append(items, item)
+		}
+		itemsByAccountId[member.AccountId] = items
+	}
+
+	getVideoIds := func(seriesId string) ([]string, error) {
+		meta, err := cinemeta.FetchMeta("series", seriesId)
+		if err != nil {
+			return nil, err
+		}
+		videoIds := make([]string, len(meta.Videos))
+		for i := range meta.Videos {
+			videoIds[i] = meta.Videos[i].Id
+		}
+		return videoIds, nil
+	}
+
+	accounts, err := MergeLibraryItems(accountIds, itemsByAccountId, getVideoIds, now)
+	if err != nil {
+		return nil, err
+	}
+	plan.Accounts = accounts
+	return plan, nil
+}
+
+func ApplyGroupSyncPlan(members []GroupMember, plan *GroupSyncPlan) error {
+	client := stremio_api.NewClient(&stremio_api.ClientConfig{})
+
+	tokenByAccountId := make(map[string]string, len(members))
+	for _, member := range members {
+		tokenByAccountId[member.AccountId] = member.Token
+	}
+
+	for _, account := range plan.Accounts {
+		if len(account.Changes) == 0 {
+			continue
+		}
+		changes := make([]stremio_api.LibraryItem, len(account.Changes))
+		for i := range account.Changes {
+			changes[i] = account.Changes[i].item
+		}
+		_, err := client.UpdateLibraryItems(&stremio_api.UpdateLibraryItemsParams{
+			Ctx:     stremio_api.Ctx{APIKey: tokenByAccountId[account.AccountId]},
+			Changes: changes,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update library items for account %s: %w", account.AccountId, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package sync_stremio_stremio
+
+import (
+	"testing"
+	"time"
+
+	stremio_api "github.com/MunifTanjim/stremthru/internal/stremio/api"
+	stremio_watched_bitfield "github.com/MunifTanjim/stremthru/stremio/watched_bitfield"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeLibraryItems(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	at := func(day int) stremio_api.JSONTime {
+		return stremio_api.JSONTime{Time: time.Date(2026, 1, day, 0, 0, 0, 0, time.UTC)}
+	}
+
+	videoIds := []string{"tt2:1:1", "tt2:1:2", "tt2:1:3"}
+	watched := func(values ...bool) string {
+		wbf := stremio_watched_bitfield.NewWatchedBitFieldFromArray(values, videoIds)
+		str, err := wbf.String()
+		if err != nil {
+			panic(err)
+		}
+		return str
+	}
+	getVideoIds := func(seriesId string) ([]string, error) {
+		return videoIds, nil
+	}
+
+	itemsByAccountId := map[string][]stremio_api.LibraryItem{
+		"a": {
+			{Id: "tt1", Type: "movie", Name: "Movie", MTime: at(1), State: stremio_api.LibraryItemState{TimesWatched: 1}},
+			{Id: "tt2", Type: "series", Name: "Series", MTime: at(2), State: stremio_api.LibraryItemState{Watched: watched(true, false, false), VideoId: "tt2:1:2"}},
+		},
+		"b": {
+			{Id: "tt1", Type: "movie", Name: "Movie", MTime: at(3), State: stremio_api.LibraryItemState{TimeOffset: 1000, Duration: 5000, VideoId: "tt1"}},
+			{Id: "tt2", Type: "series", Name: "Series", MTime: at(4), State: stremio_api.LibraryItemState{Watched: watched(false, true, false), VideoId: "tt2:1:3", TimeOffset: 2000, Duration: 6000}},
+		},
+		"c": {},
+	}
+
+	accounts, err := MergeLibraryItems([]string{"a", "b", "c"}, itemsByAccountId, getVideoIds, now)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 3)
+
+	t.Run("latest mtime wins, watched is kept", func(t *testing.T) {
+		changes := accounts[0].Changes
+		assert.Len(t, changes, 2)
+
+		movie := changes[0]
+		assert.Equal(t, GroupSyncChangeActionUpdate, movie.Action)
+		assert.Equal(t, "b", movie.SourceAccountId)
+		assert.Empty(t, movie.Watched)
+		assert.Equal(t, 1000, movie.item.State.TimeOffset)
+		assert.Equal(t, 1, movie.item.State.TimesWatched)
+		assert.Equal(t, now, movie.item.MTime.Time)
+
+		series := changes[1]
+		assert.Equal(t, "b", series.SourceAccountId)
+		assert.Equal(t, []string{"tt2:1:2"}, series.Watched)
+		assert.Equal(t, "tt2:1:3", series.item.State.VideoId)
+		assert.Equal(t, watched(true, true, false), series.item.State.Watched)
+	})
+
+	t.Run("winner gets union of watched", func(t *testing.T) {
+		changes := accounts[1].Changes
+		assert.Len(t, changes, 2)
+
+		movie := changes[0]
+		assert.Equal(t, "", movie.SourceAccountId)
+		assert.Equal(t, []string{"tt1"}, movie.Watched)
+		assert.Equal(t, 1, movie.item.State.TimesWatched)
+		assert.Equal(t, 1000, movie.item.State.TimeOffset)
+
+		series := changes[1]
+		assert.Equal(t, []string{"tt2:1:1"}, series.Watched)
+		assert.Equal(t, watched(true, true, false), series.item.State.Watched)
+	})
+
+	t.Run("missing items are added", func(t *testing.T) {
+		changes := accounts[2].Changes
+		assert.Len(t, changes, 2)
+		for _, change := range changes {
+			assert.Equal(t, GroupSyncChangeActionAdd, change.Action)
+			assert.Equal(t, "b", change.SourceAccountId)
+			assert.Equal(t, now, change.item.CTime.Time)
+		}
+		assert.Equal(t, []string{"tt2:1:1", "tt2:1:2"}, changes[1].Watched)
+	})
+
+	t.Run("no changes when in sync", func(t *testing.T) {
+		synced := map[string][]stremio_api.LibraryItem{}
+		for _, account := range accounts {
+			for _, change := range account.Changes {
+				synced[account.AccountId] = append(synced[account.AccountId], change.item)
+			}
+		}
+		accounts, err := MergeLibraryItems([]string{"a", "b", "c"}, synced, getVideoIds, now.Add(time.Hour))
+		assert.NoError(t, err)
+		for _, account := range accounts {
+			assert.Empty(t, account.Changes, account.AccountId)
+		}
+	})
+}
+
+func TestMergeLibraryItemsRemoved(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	at := func(day int) stremio_api.JSONTime {
+		return stremio_api.JSONTime{Time: time.Date(2026, 1, day, 0, 0, 0, 0, time.UTC)}
+	}
+	getVideoIds := func(seriesId string) ([]string, error) {
+		return nil, nil
+	}
+
+	t.Run("removed on A after added on B", func(t *testing.T) {
+		itemsByAccountId := map[string][]stremio_api.LibraryItem{
+			"a": {
+				{Id: "tt1", Type: "movie", Name: "Movie", MTime: at(3), Removed: true, State: stremio_api.LibraryItemState{TimesWatched: 1}},
+			},
+			"b": {
+				{Id: "tt1", Type: "movie", Name: "Movie", MTime: at(2)},
+			},
+			"c": {},
+		}
+
+		accounts, err := MergeLibraryItems([]string{"a", "b", "c"}, itemsByAccountId, getVideoIds, now)
+		assert.NoError(t, err)
+		assert.Len(t, accounts, 3)
+
+		assert.Empty(t, accounts[0].Changes)
+
+		changes := accounts[1].Changes
+		assert.Len(t, changes, 1)
+		assert.Equal(t, GroupSyncChangeActionRemove, changes[0].Action)
+		assert.Equal(t, "a", changes[0].SourceAccountId)
+		assert.True(t, changes[0].item.Removed)
+		assert.Equal(t, 1, changes[0].item.State.TimesWatched)
+		assert.Equal(t, now, changes[0].item.MTime.Time)
+
+		assert.Empty(t, accounts[2].Changes)
+	})
+
+	t.Run("added on B after removed on A", func(t *testing.T) {
+		itemsByAccountId := map[string][]stremio_api.LibraryItem{
+			"a": {
+				{Id: "tt1", Type: "movie", Name: "Movie", MTime: at(2), Removed: true, State: stremio_api.LibraryItemState{TimesWatched: 1}},
+			},
+			"b": {
+				{Id: "tt1", Type: "movie", Name: "Movie", MTime: at(3)},
+			},
+		}
+
+		accounts, err := MergeLibraryItems([]string{"a", "b"}, itemsByAccountId, getVideoIds, now)
+		assert.NoError(t, err)
+		assert.Len(t, accounts, 2)
+
+		changes := accounts[0].Changes
+		assert.Len(t, changes, 1)
+		assert.Equal(t, GroupSyncChangeActionAdd, changes[0].Action)
+		assert.Equal(t, "b", changes[0].SourceAccountId)
+		assert.False(t, changes[0].item.Removed)
+		assert.Empty(t, changes[0].Watched)
+		assert.Equal(t, 1, changes[0].item.State.TimesWatched)
+
+		changes = accounts[1].Changes
+		assert.Len(t, changes, 1)
+		assert.Equal(t, GroupSyncChangeActionUpdate, changes[0].Action)
+		assert.Equal(t, []string{"tt1"}, changes[0].Watched)
+	})
+
+	t.Run("no changes when removed everywhere", func(t *testing.T) {
+		itemsByAccountId := map[string][]stremio_api.LibraryItem{
+			"a": {
+				{Id: "tt1", Type: "movie", Name: "Movie", MTime: at(3), Removed: true},
+			},
+			"b": {
+				{Id: "tt1", Type: "movie", Name: "Movie", MTime: at(2), Removed: true, Temp: true},
+			},
+		}
+
+		accounts, err := MergeLibraryItems([]string{"a", "b"}, itemsByAccountId, getVideoIds, now)
+		assert.NoError(t, err)
+		for _, account := range accounts {
+			assert.Empty(t, account.Changes, account.AccountId)
+		}
+	})
+}
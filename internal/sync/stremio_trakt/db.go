@@ -45,8 +45,43 @@ type SyncConfigWatched struct {
 	Direction SyncDirection `json:"dir"`
 }
 
+type SyncConfigWatchlist struct {
+	Direction SyncDirection `json:"dir"`
+}
+
+type SyncConfigRatings struct {
+	Direction SyncDirection `json:"dir"`
+}
+
+type SyncConfigProgress struct {
+	Direction SyncDirection `json:"dir"`
+}
+
 type SyncConfig struct {
-	Watched SyncConfigWatched `json:"watched"`
+	Watched   SyncConfigWatched   `json:"watched"`
+	Watchlist SyncConfigWatchlist `json:"watchlist"`
+	Ratings   SyncConfigRatings   `json:"ratings"`
+	Progress  SyncConfigProgress  `json:"progress"`
+}
+
+// sections missing in stored config or in request are disabled
+func (sc *SyncConfig) Normalize() {
+	if sc.Watchlist.Direction == "" {
+		sc.Watchlist.Direction = SyncDirectionNone
+	}
+	if sc.Ratings.Direction == "" {
+		sc.Ratings.Direction = SyncDirectionNone
+	}
+	if sc.Progress.Direction == "" {
+		sc.Progress.Direction = SyncDirectionNone
+	}
+}
+
+func (sc SyncConfig) IsDisabled() bool {
+	return sc.Watched.Direction.IsDisabled() &&
+		sc.Watchlist.Direction.IsDisabled() &&
+		sc.Ratings.Direction.IsDisabled() &&
+		sc.Progress.Direction.IsDisabled()
 }
 
 func (sc SyncConfig) Value() (driver.Value, error) {
@@ -54,15 +89,36 @@ func (sc SyncConfig) Value() (driver.Value, error) {
 }
 
 func (sc *SyncConfig) Scan(value any) error {
-	return db.JSONScan(value, sc)
+	if err := db.JSONScan(value, sc); err != nil {
+		return err
+	}
+	sc.Normalize()
+	return nil
 }
 
 type SyncStateWatched struct {
 	LastSyncedAt *time.Time `json:"last_synced_at"`
 }
 
+type SyncStateWatchlist struct {
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+}
+
+type SyncStateRatings struct {
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+	// stremio likes are rescanned for the whole library periodically
+	LastLikesScannedAt *time.Time `json:"last_likes_scanned_at,omitempty"`
+}
+
+type SyncStateProgress struct {
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+}
+
 type SyncState struct {
-	Watched SyncStateWatched `json:"watched"`
+	Watched   SyncStateWatched   `json:"watched"`
+	Watchlist SyncStateWatchlist `json:"watchlist"`
+	Ratings   SyncStateRatings   `json:"ratings"`
+	Progress  SyncStateProgress  `json:"progress"`
 }
 
 func (ss SyncState) Value() (driver.Value, error) {
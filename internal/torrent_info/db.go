@@ -1517,6 +1517,22 @@ func GetStats() (*Stats, error) {
 	return stats, nil
 }
 
+var count_by_source_query = fmt.Sprintf(
+	"SELECT COUNT(%s) FROM %s WHERE %s = ?",
+	Column.Hash,
+	TableName,
+	Column.Source,
+)
+
+func CountBySource(source TorrentInfoSource) (int, error) {
+	var count int
+	row := db.QueryRow(count_by_source_query, source)
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 var exists_by_hash_query = fmt.Sprintf(
 	"SELECT %s FROM %s WHERE %s IN ",
 	Column.Hash,
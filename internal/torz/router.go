@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/shared"
 	storemiddleware "github.com/MunifTanjim/stremthru/internal/store/middleware"
 )
 
@@ -46,4 +47,12 @@ func AddEndpoints(mux *http.ServeMux) {
 			server.ErrorMethodNotAllowed(r).Send(w, r)
 		}
 	}))
+	mux.HandleFunc("/v0/store/torz/stream/{token}/{filename}", shared.EnableCORS(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			handleStoreTorzStreamFile(w, r)
+		default:
+			server.ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
 }
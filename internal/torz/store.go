@@ -15,6 +15,7 @@ import (
 	store_util "github.com/MunifTanjim/stremthru/internal/store/util"
 	"github.com/MunifTanjim/stremthru/internal/util"
 	"github.com/MunifTanjim/stremthru/store"
+	"github.com/MunifTanjim/stremthru/store/stremthru"
 )
 
 func handleStoreTorzCheck(w http.ResponseWriter, r *http.Request) {
@@ -304,3 +305,20 @@ func handleStoreTorzLinkGenerate(w http.ResponseWriter, r *http.Request) {
 
 	server.SendData(w, r, 200, data)
 }
+
+func handleStoreTorzStreamFile(w http.ResponseWriter, r *http.Request) {
+	ctx := server.GetReqCtx(r)
+	ctx.RedactURLPathValues(r, "token")
+
+	token := r.PathValue("token")
+
+	_, hash, path, err := stremthru.UnwrapTorzStreamToken(token)
+	if err != nil {
+		server.SendError(w, r, err)
+		return
+	}
+
+	if err := stremthru.ServeTorrentFile(w, r, hash, path); err != nil {
+		server.SendError(w, r, err)
+	}
+}
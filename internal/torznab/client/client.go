@@ -144,12 +144,15 @@ func (c *Client) Request(method, path string, params request.Context, v request.
 	return res, nil
 }
 
+func NewQuery(caps *Caps) *Query {
+	return &Query{caps: caps, values: url.Values{}}
+}
+
 func (c *Client) NewSearchQuery(fn func(caps Caps) Function) (*Query, error) {
 	caps, err := c.GetCaps()
 	if err != nil {
 		return nil, err
 	}
-	q := Query{caps: &caps, values: url.Values{}}
-	q.SetT(fn(caps))
-	return &q, nil
+	q := NewQuery(&caps).SetT(fn(caps))
+	return q, nil
 }
@@ -30,6 +30,7 @@ const (
 	SearchParamSeason   SearchParam = znab.SearchParamSeason
 	SearchParamYear     SearchParam = znab.SearchParamYear
 	SearchParamIMDBId   SearchParam = znab.SearchParamIMDBId
+	SearchParamTMDBId   SearchParam = znab.SearchParamTMDBId
 	SearchParamTVDBId   SearchParam = znab.SearchParamTVDBId
 	SearchParamTVMazeId SearchParam = znab.SearchParamTVMazeId
 	SearchParamTraktId  SearchParam = znab.SearchParamTraktId
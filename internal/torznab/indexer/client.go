@@ -8,6 +8,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/cache"
 	torznab_client "github.com/MunifTanjim/stremthru/internal/torznab/client"
 	"github.com/MunifTanjim/stremthru/internal/torznab/jackett"
+	"github.com/MunifTanjim/stremthru/internal/torznab/prowlarr"
 )
 
 var jackettCache = cache.NewLRUCache[*jackett.Client](&cache.CacheConfig{
@@ -15,6 +16,11 @@ var jackettCache = cache.NewLRUCache[*jackett.Client](&cache.CacheConfig{
 	Name:     "torznab:indexer:jackett",
 })
 
+var prowlarrCache = cache.NewLRUCache[*prowlarr.Client](&cache.CacheConfig{
+	Lifetime: 3 * time.Hour,
+	Name:     "torznab:indexer:prowlarr",
+})
+
 func (tidxr TorznabIndexer) GetClient() (torznab_client.Indexer, error) {
 	switch tidxr.Type {
 	case IndexerTypeJackett:
@@ -42,6 +48,34 @@ func (tidxr TorznabIndexer) GetClient() (torznab_client.Indexer, error) {
 		}
 		c := client.GetTorznabClient(u.IndexerId)
 		return c, nil
+	case IndexerTypeProwlarr:
+		apiKey, err := tidxr.GetAPIKey()
+		if err != nil {
+			return nil, err
+		}
+
+		u, err := prowlarr.ParseTorznabURL(tidxr.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		cacheKey := strconv.FormatInt(tidxr.Id, 10)
+		var client *prowlarr.Client
+		if !prowlarrCache.Get(cacheKey, &client) {
+			client = prowlarr.NewClient(&prowlarr.ClientConfig{
+				BaseURL: u.BaseURL,
+				APIKey:  apiKey,
+			})
+			err := prowlarrCache.Add(cacheKey, client)
+			if err != nil {
+				return nil, err
+			}
+		}
+		c := client.GetTorznabClient(prowlarr.IndexerDetails{
+			ID:   u.IndexerId,
+			Name: tidxr.Name,
+		})
+		return c, nil
 	default:
 		return nil, errors.New("invalid indexer type: " + string(tidxr.Type))
 	}
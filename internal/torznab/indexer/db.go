@@ -3,6 +3,7 @@ package torznab_indexer
 import (
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/MunifTanjim/stremthru/core"
@@ -10,6 +11,7 @@ import (
 	"github.com/MunifTanjim/stremthru/internal/db"
 	"github.com/MunifTanjim/stremthru/internal/ratelimit"
 	"github.com/MunifTanjim/stremthru/internal/torznab/jackett"
+	"github.com/MunifTanjim/stremthru/internal/torznab/prowlarr"
 	rrl "github.com/nccapo/rate-limiter"
 )
 
@@ -26,12 +28,13 @@ const TableName = "torznab_indexer"
 type IndexerType string
 
 const (
-	IndexerTypeJackett IndexerType = "jackett"
+	IndexerTypeJackett  IndexerType = "jackett"
+	IndexerTypeProwlarr IndexerType = "prowlarr"
 )
 
 func (it IndexerType) IsValid() bool {
 	switch it {
-	case IndexerTypeJackett:
+	case IndexerTypeJackett, IndexerTypeProwlarr:
 		return true
 	default:
 		return false
@@ -85,6 +88,20 @@ func NewTorznabIndexer(indexerType IndexerType, url, apiKey string) (*TorznabInd
 			return nil, fmt.Errorf("invalid torznab url: %w", err)
 		}
 
+		indexer := &TorznabIndexer{
+			Type: indexerType,
+			URL:  url,
+		}
+		err := indexer.SetAPIKey(apiKey)
+		if err != nil {
+			return nil, err
+		}
+		return indexer, nil
+	case IndexerTypeProwlarr:
+		if _, err := prowlarr.ParseTorznabURL(url); err != nil {
+			return nil, fmt.Errorf("invalid torznab url: %w", err)
+		}
+
 		indexer := &TorznabIndexer{
 			Type: indexerType,
 			URL:  url,
@@ -99,6 +116,30 @@ func NewTorznabIndexer(indexerType IndexerType, url, apiKey string) (*TorznabInd
 	}
 }
 
+// NewProwlarrTorznabIndexers discovers the enabled torrent indexers of the
+// Prowlarr instance at baseURL, and returns one indexer for each of them.
+func NewProwlarrTorznabIndexers(baseURL, apiKey string) ([]TorznabIndexer, error) {
+	client := prowlarr.NewClient(&prowlarr.ClientConfig{
+		BaseURL: baseURL,
+		APIKey:  apiKey,
+	})
+	pIndexers, err := client.ListTorrentIndexers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexers: %w", err)
+	}
+	indexers := make([]TorznabIndexer, 0, len(pIndexers))
+	for i := range pIndexers {
+		pIndexer := &pIndexers[i]
+		indexer, err := NewTorznabIndexer(IndexerTypeProwlarr, client.GetIndexerTorznabURL(*pIndexer), apiKey)
+		if err != nil {
+			return nil, err
+		}
+		indexer.Name = pIndexer.Name
+		indexers = append(indexers, *indexer)
+	}
+	return indexers, nil
+}
+
 func (i *TorznabIndexer) SetAPIKey(apiKey string) error {
 	encAPIKey, err := encrypt(apiKey)
 	if err != nil {
@@ -144,6 +185,43 @@ func (i *TorznabIndexer) Validate() error {
 			i.Name = jackett.GetIndexerName(u.IndexerId)
 		}
 
+		return nil
+	case IndexerTypeProwlarr:
+		u, err := prowlarr.ParseTorznabURL(i.URL)
+		if err != nil {
+			return fmt.Errorf("invalid torznab url: %w", err)
+		}
+
+		apiKey, err := i.GetAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to decrypt api key: %w", err)
+		}
+
+		client := prowlarr.NewClient(&prowlarr.ClientConfig{
+			BaseURL: u.BaseURL,
+			APIKey:  apiKey,
+		})
+
+		pIndexers, err := client.ListIndexers()
+		if err != nil {
+			return fmt.Errorf("failed to list indexers: %w", err)
+		}
+		idx := slices.IndexFunc(pIndexers, func(pIndexer prowlarr.IndexerDetails) bool {
+			return pIndexer.ID == u.IndexerId
+		})
+		if idx == -1 {
+			return fmt.Errorf("indexer not found: %d", u.IndexerId)
+		}
+
+		_, err = client.GetTorznabClient(pIndexers[idx]).GetCaps()
+		if err != nil {
+			return fmt.Errorf("failed to fetch capabilities: %w", err)
+		}
+
+		if i.Name == "" {
+			i.Name = pIndexers[idx].Name
+		}
+
 		return nil
 	default:
 		return fmt.Errorf("unsupported indexer type: %s", i.Type)
@@ -0,0 +1,152 @@
+package prowlarr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/request"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+type ClientConfig struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	APIKey     string
+	UserAgent  string
+}
+
+type Client struct {
+	BaseURL    *url.URL
+	HTTPClient *http.Client
+
+	userAgent string
+	apiKey    string
+
+	reqQuery  func(query *url.Values, params request.Context)
+	reqHeader func(query *http.Header, params request.Context)
+
+	indexers          *cache.CachedValue[[]IndexerDetails]
+	torznabClientById cache.Cache[TorznabClient]
+}
+
+func NewClient(conf *ClientConfig) *Client {
+	if conf.HTTPClient == nil {
+		conf.HTTPClient = config.GetHTTPClient(config.TUNNEL_TYPE_AUTO)
+	}
+
+	if conf.UserAgent == "" {
+		conf.UserAgent = "stremthru/" + config.Version
+	}
+
+	c := Client{
+		HTTPClient: conf.HTTPClient,
+		userAgent:  conf.UserAgent,
+		apiKey:     conf.APIKey,
+	}
+
+	c.BaseURL = util.MustParseURL(strings.TrimRight(conf.BaseURL, "/"))
+
+	c.reqQuery = func(query *url.Values, params request.Context) {
+	}
+
+	c.reqHeader = func(header *http.Header, params request.Context) {
+		header.Set("User-Agent", c.userAgent)
+		header.Set("X-Api-Key", c.apiKey)
+	}
+
+	c.indexers = cache.NewCachedValue(cache.CachedValueConfig[[]IndexerDetails]{
+		Get: func() ([]IndexerDetails, error) {
+			res, err := c.listIndexers(&ListIndexersParams{})
+			return res.Data, err
+		},
+		TTL: 15 * time.Minute,
+	})
+
+	c.torznabClientById = cache.NewCache[TorznabClient](&cache.CacheConfig{
+		Lifetime: 30 * time.Minute,
+		Name:     "torznab:prowlarr:torznab-client",
+	})
+
+	return &c
+}
+
+type ResponseError struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"message"`
+}
+
+func (e *ResponseError) Error() string {
+	if e.Message == "" {
+		return http.StatusText(e.StatusCode)
+	}
+	return e.Message
+}
+
+type Response[T any] struct {
+	Error *ResponseError
+	Data  T
+}
+
+func (r Response[T]) GetError(res *http.Response) error {
+	if r.Error == nil {
+		return nil
+	}
+	return r.Error
+}
+
+func (r *Response[T]) Unmarshal(res *http.Response, body []byte, v any) error {
+	if res.StatusCode >= http.StatusBadRequest {
+		r.Error = &ResponseError{StatusCode: res.StatusCode}
+		if strings.Contains(res.Header.Get("Content-Type"), "application/json") {
+			if err := json.Unmarshal(body, r.Error); err != nil {
+				r.Error.Message = ""
+			}
+		}
+		return nil
+	}
+
+	contentType := res.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(contentType, "application/json"):
+		return json.Unmarshal(body, &r.Data)
+	default:
+		return errors.New("unexpected content type: " + contentType)
+	}
+}
+
+type Ctx = request.Ctx
+
+func (c *Client) Request(method, path string, params request.Context, v request.ResponseContainer) (*http.Response, error) {
+	if params == nil {
+		params = &Ctx{}
+	}
+	req, err := params.NewRequest(c.BaseURL, method, path, c.reqHeader, c.reqQuery)
+	if err != nil {
+		error := core.NewAPIError("failed to create request")
+		error.Cause = err
+		return nil, error
+	}
+	res, err := params.DoRequest(c.HTTPClient, req)
+	err = request.ProcessResponseBody(res, err, v)
+	if err != nil {
+		error := core.NewUpstreamError("")
+		if rerr, ok := err.(*core.Error); ok {
+			error.Msg = rerr.Msg
+			error.Code = rerr.Code
+			error.StatusCode = rerr.StatusCode
+			error.UpstreamCause = rerr
+		} else {
+			error.Cause = err
+		}
+		error.InjectReq(req)
+		return res, err
+	}
+	return res, nil
+}
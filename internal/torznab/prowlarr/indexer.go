@@ -0,0 +1,126 @@
+package prowlarr
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/request"
+	tznc "github.com/MunifTanjim/stremthru/internal/torznab/client"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	"github.com/MunifTanjim/stremthru/internal/znab"
+)
+
+type IndexerProtocol string
+
+const (
+	IndexerProtocolTorrent IndexerProtocol = "torrent"
+	IndexerProtocolUsenet  IndexerProtocol = "usenet"
+)
+
+type IndexerPrivacy string
+
+const (
+	IndexerPrivacyPublic      IndexerPrivacy = "public"
+	IndexerPrivacyPrivate     IndexerPrivacy = "private"
+	IndexerPrivacySemiPrivate IndexerPrivacy = "semiPrivate"
+)
+
+type IndexerDetails struct {
+	ID             int             `json:"id"`
+	Name           string          `json:"name"`
+	DefinitionName string          `json:"definitionName"`
+	Enable         bool            `json:"enable"`
+	Protocol       IndexerProtocol `json:"protocol"`
+	Privacy        IndexerPrivacy  `json:"privacy"`
+}
+
+func (i IndexerDetails) IsPrivate() bool {
+	return i.Privacy == IndexerPrivacyPrivate || i.Privacy == IndexerPrivacySemiPrivate
+}
+
+type ListIndexersParams struct {
+	Ctx
+}
+
+func (c *Client) listIndexers(params *ListIndexersParams) (request.APIResponse[[]IndexerDetails], error) {
+	var resp Response[[]IndexerDetails]
+	res, err := c.Request("GET", "/api/v1/indexer", params, &resp)
+	return request.NewAPIResponse(res, resp.Data), err
+}
+
+func (c *Client) ListIndexers() ([]IndexerDetails, error) {
+	return c.indexers.Get()
+}
+
+// ListTorrentIndexers returns the enabled indexers for the torrent protocol.
+func (c *Client) ListTorrentIndexers() ([]IndexerDetails, error) {
+	indexers, err := c.ListIndexers()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]IndexerDetails, 0, len(indexers))
+	for i := range indexers {
+		indexer := &indexers[i]
+		if indexer.Enable && indexer.Protocol == IndexerProtocolTorrent {
+			result = append(result, *indexer)
+		}
+	}
+	return result, nil
+}
+
+type ItemProwlarrIndexer struct {
+	ID   string `xml:"id,attr"`
+	Type string `xml:"type,attr"`
+	Name string `xml:",chardata"`
+}
+
+type ChannelItem struct {
+	znab.ChannelItem
+	Grabs           int                   `xml:"grabs"`
+	ProwlarrIndexer ItemProwlarrIndexer   `xml:"prowlarrindexer"`
+	Size            int64                 `xml:"size"`
+	Attributes      znab.ChannelItemAttrs `xml:"http://torznab.com/schemas/2015/feed attr"`
+}
+
+func (o ChannelItem) ToTorz() *tznc.Torz {
+	t := &tznc.Torz{}
+	t.Indexer = o.ProwlarrIndexer.Name
+	t.Hash = strings.ToLower(o.Attributes.Get(znab.TorznabAttrNameInfoHash))
+	t.Title = o.Title
+	t.Size = o.Size
+	if t.Size == 0 {
+		t.Size = o.Enclosure.Length
+	}
+	t.Seeders = util.SafeParseInt(o.Attributes.Get(znab.TorznabAttrNameSeeders), 0)
+	if peers := util.SafeParseInt(o.Attributes.Get(znab.TorznabAttrNamePeers), 0); peers > t.Seeders {
+		t.Leechers = peers - t.Seeders
+	}
+	t.Private = o.ProwlarrIndexer.Type == string(IndexerPrivacyPrivate) || o.ProwlarrIndexer.Type == string(IndexerPrivacySemiPrivate)
+	magnetUrl := o.Attributes.Get(znab.TorznabAttrNameMagnetURL)
+	if magnetUrl == "" && strings.HasPrefix(o.Enclosure.URL, "magnet:?") {
+		magnetUrl = o.Enclosure.URL
+	}
+	if magnetUrl != "" {
+		t.MagnetLink = magnetUrl
+		if t.Hash == "" {
+			if m, err := core.ParseMagnetLink(t.MagnetLink); err == nil {
+				t.Hash = m.Hash
+			}
+		}
+	}
+	if strings.HasPrefix(o.Enclosure.URL, "http") {
+		t.SourceLink = o.Enclosure.URL
+	}
+	return t
+}
+
+type Channel struct {
+	znab.Channel[ChannelItem]
+}
+
+type SearchResponse struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr,omitempty"`
+	Channel Channel  `xml:"channel"`
+}
@@ -0,0 +1,114 @@
+package prowlarr
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	torznab_client "github.com/MunifTanjim/stremthru/internal/torznab/client"
+	"github.com/MunifTanjim/stremthru/internal/util"
+)
+
+var torznabUrlPattern = regexp.MustCompile(`(?i)^(?<base_url>https?:\/\/.+?)\/(?<indexer_id>\d+)\/api\/?$`)
+
+type torznabURL struct {
+	BaseURL   string
+	IndexerId int
+}
+
+// ParseTorznabURL parses the per-indexer torznab url, i.e.
+// `{base_url}/{indexer_id}/api`.
+func ParseTorznabURL(torznabUrl string) (*torznabURL, error) {
+	r := torznabURL{}
+	match := torznabUrlPattern.FindStringSubmatch(torznabUrl)
+	if match == nil {
+		return &r, errors.New("invalid torznab url")
+	}
+	r.BaseURL = match[torznabUrlPattern.SubexpIndex("base_url")]
+	r.IndexerId = util.SafeParseInt(match[torznabUrlPattern.SubexpIndex("indexer_id")], 0)
+	return &r, nil
+}
+
+func IsTorznabURL(str string) bool {
+	return torznabUrlPattern.MatchString(str)
+}
+
+func (turl torznabURL) String() string {
+	return strings.TrimRight(turl.BaseURL, "/") + "/" + strconv.Itoa(turl.IndexerId) + "/api"
+}
+
+type TorznabClient struct {
+	*torznab_client.Client
+	indexer IndexerDetails
+}
+
+func (tc TorznabClient) GetId() string {
+	return "prowlarr/" + strconv.Itoa(tc.indexer.ID)
+}
+
+func (tc TorznabClient) GetName() string {
+	return tc.indexer.Name
+}
+
+func (tc TorznabClient) Search(query url.Values) ([]torznab_client.Torz, error) {
+	params := &Ctx{}
+	params.Query = &query
+	var resp torznab_client.Response[SearchResponse]
+	_, err := tc.Client.Request("GET", "/api", params, &resp)
+	if err != nil {
+		return nil, err
+	}
+	items := resp.Data.Channel.Items
+	result := make([]torznab_client.Torz, 0, len(items))
+	for i := range items {
+		item := &items[i]
+		t := item.ToTorz()
+		if t.Indexer == "" {
+			t.Indexer = tc.indexer.Name
+		}
+		if tc.indexer.IsPrivate() {
+			t.Private = true
+		}
+		result = append(result, *t)
+	}
+	return result, nil
+}
+
+func (c *Client) GetTorznabClient(indexer IndexerDetails) *TorznabClient {
+	id := strconv.Itoa(indexer.ID)
+	var client TorznabClient
+	if c.torznabClientById.Get(id, &client) {
+		client.indexer = indexer
+		return &client
+	}
+	tc := torznab_client.NewClient(&torznab_client.ClientConfig{
+		BaseURL:    c.BaseURL.JoinPath(id).String(),
+		HTTPClient: c.HTTPClient,
+		APIKey:     c.apiKey,
+		UserAgent:  c.userAgent,
+	})
+	client = TorznabClient{Client: tc, indexer: indexer}
+	c.torznabClientById.Add(id, client)
+	return &client
+}
+
+// GetIndexerTorznabURL returns the torznab url for the indexer.
+func (c *Client) GetIndexerTorznabURL(indexer IndexerDetails) string {
+	return torznabURL{BaseURL: c.BaseURL.String(), IndexerId: indexer.ID}.String()
+}
+
+// GetTorznabClients returns the torznab clients for the enabled torrent
+// indexers.
+func (c *Client) GetTorznabClients() ([]*TorznabClient, error) {
+	indexers, err := c.ListTorrentIndexers()
+	if err != nil {
+		return nil, err
+	}
+	clients := make([]*TorznabClient, len(indexers))
+	for i := range indexers {
+		clients[i] = c.GetTorznabClient(indexers[i])
+	}
+	return clients, nil
+}
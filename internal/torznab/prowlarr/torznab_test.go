@@ -0,0 +1,120 @@
+package prowlarr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTorznabURL(t *testing.T) {
+	for _, test := range []struct {
+		input           string
+		expectedBase    string
+		expectedIndexer int
+		shouldError     bool
+	}{
+		{
+			input:           "http://localhost:9696/1/api",
+			expectedBase:    "http://localhost:9696",
+			expectedIndexer: 1,
+		},
+		{
+			input:           "https://example.com/prowlarr/42/api/",
+			expectedBase:    "https://example.com/prowlarr",
+			expectedIndexer: 42,
+		},
+		{
+			input:       "http://localhost:9696",
+			shouldError: true,
+		},
+		{
+			input:       "http://localhost:9696/api/v2.0/indexers/all/results/torznab",
+			shouldError: true,
+		},
+	} {
+		t.Run(test.input, func(t *testing.T) {
+			u, err := ParseTorznabURL(test.input)
+			if test.shouldError {
+				assert.Error(t, err)
+				assert.False(t, IsTorznabURL(test.input))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedBase, u.BaseURL)
+			assert.Equal(t, test.expectedIndexer, u.IndexerId)
+		})
+	}
+}
+
+const testSearchResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:torznab="http://torznab.com/schemas/2015/feed">
+  <channel>
+    <title>TestTracker</title>
+    <item>
+      <title>Movie.2024.1080p.WEB-DL</title>
+      <guid>https://tracker.test/t/1</guid>
+      <prowlarrindexer id="2" type="private">TestTracker</prowlarrindexer>
+      <size>1073741824</size>
+      <grabs>10</grabs>
+      <enclosure url="http://localhost:9696/2/download?apikey=key&amp;link=abc" length="1073741824" type="application/x-bittorrent" />
+      <torznab:attr name="seeders" value="12" />
+      <torznab:attr name="peers" value="15" />
+      <torznab:attr name="infohash" value="0123456789ABCDEF0123456789ABCDEF01234567" />
+    </item>
+  </channel>
+</rss>`
+
+func TestTorznabClientSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" && r.URL.Query().Get("apikey") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/indexer":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[
+				{"id":1,"name":"Disabled","enable":false,"protocol":"torrent","privacy":"public"},
+				{"id":2,"name":"TestTracker","enable":true,"protocol":"torrent","privacy":"private"},
+				{"id":3,"name":"Usenet","enable":true,"protocol":"usenet","privacy":"private"}
+			]`))
+		case "/2/api":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			w.Write([]byte(testSearchResponse))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL:    server.URL,
+		APIKey:     "key",
+		HTTPClient: server.Client(),
+	})
+
+	clients, err := client.GetTorznabClients()
+	require.NoError(t, err)
+	require.Len(t, clients, 1)
+
+	tc := clients[0]
+	assert.Equal(t, "prowlarr/2", tc.GetId())
+	assert.Equal(t, "TestTracker", tc.GetName())
+	assert.Equal(t, server.URL+"/2/api", client.GetIndexerTorznabURL(tc.indexer))
+
+	items, err := tc.Search(url.Values{"t": []string{"search"}, "q": []string{"Movie"}})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	item := items[0]
+	assert.Equal(t, "TestTracker", item.Indexer)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", item.Hash)
+	assert.Equal(t, int64(1073741824), item.Size)
+	assert.Equal(t, 12, item.Seeders)
+	assert.Equal(t, 3, item.Leechers)
+	assert.True(t, item.Private)
+	assert.Contains(t, item.SourceLink, "/2/download")
+}
@@ -2,8 +2,10 @@ package trakt_account
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,11 +16,41 @@ import (
 
 const TableName = "trakt_account"
 
+const DefaultScrobbleThreshold = 80
+
+type ScrobbleConfig struct {
+	Enabled bool `json:"enabled"`
+	// StremThru users whose proxied playback is scrobbled
+	Users []string `json:"users,omitempty"`
+	// progress (in percent) after which playback is marked as watched
+	Threshold int `json:"threshold,omitempty"`
+}
+
+func (sc ScrobbleConfig) GetThreshold() int {
+	if sc.Threshold <= 0 || sc.Threshold > 100 {
+		return DefaultScrobbleThreshold
+	}
+	return sc.Threshold
+}
+
+func (sc ScrobbleConfig) HasUser(user string) bool {
+	return sc.Enabled && slices.Contains(sc.Users, user)
+}
+
+func (sc ScrobbleConfig) Value() (driver.Value, error) {
+	return db.JSONValue(sc)
+}
+
+func (sc *ScrobbleConfig) Scan(value any) error {
+	return db.JSONScan(value, sc)
+}
+
 type TraktAccount struct {
-	Id           string
-	OAuthTokenId string
-	CAt          db.Timestamp
-	UAt          db.Timestamp
+	Id             string
+	OAuthTokenId   string
+	ScrobbleConfig ScrobbleConfig
+	CAt            db.Timestamp
+	UAt            db.Timestamp
 
 	otok *oauth.OAuthToken
 }
@@ -43,20 +75,23 @@ func (a *TraktAccount) IsValid() bool {
 }
 
 var Column = struct {
-	Id           string
-	OAuthTokenId string
-	CAt          string
-	UAt          string
+	Id             string
+	OAuthTokenId   string
+	ScrobbleConfig string
+	CAt            string
+	UAt            string
 }{
-	Id:           "id",
-	OAuthTokenId: "oauth_token_id",
-	CAt:          "cat",
-	UAt:          "uat",
+	Id:             "id",
+	OAuthTokenId:   "oauth_token_id",
+	ScrobbleConfig: "scrobble_config",
+	CAt:            "cat",
+	UAt:            "uat",
 }
 
 var columns = []string{
 	Column.Id,
 	Column.OAuthTokenId,
+	Column.ScrobbleConfig,
 	Column.CAt,
 	Column.UAt,
 }
@@ -77,7 +112,7 @@ func GetAll() ([]TraktAccount, error) {
 	items := []TraktAccount{}
 	for rows.Next() {
 		item := TraktAccount{}
-		if err := rows.Scan(&item.Id, &item.OAuthTokenId, &item.CAt, &item.UAt); err != nil {
+		if err := rows.Scan(&item.Id, &item.OAuthTokenId, &item.ScrobbleConfig, &item.CAt, &item.UAt); err != nil {
 			return nil, err
 		}
 
@@ -97,7 +132,7 @@ func GetById(id string) (*TraktAccount, error) {
 	row := db.QueryRow(query_get_by_id, id)
 
 	item := TraktAccount{}
-	if err := row.Scan(&item.Id, &item.OAuthTokenId, &item.CAt, &item.UAt); err != nil {
+	if err := row.Scan(&item.Id, &item.OAuthTokenId, &item.ScrobbleConfig, &item.CAt, &item.UAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -152,6 +187,19 @@ func Insert(oauthTokenId string) (*TraktAccount, error) {
 	}, nil
 }
 
+var query_set_scrobble_config = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ?`,
+	TableName,
+	Column.ScrobbleConfig,
+	Column.UAt, db.CurrentTimestamp,
+	Column.Id,
+)
+
+func SetScrobbleConfig(id string, scrobbleConfig ScrobbleConfig) error {
+	_, err := db.Exec(query_set_scrobble_config, scrobbleConfig, id)
+	return err
+}
+
 var query_delete = fmt.Sprintf(
 	`DELETE FROM %s WHERE %s = ?`,
 	TableName,
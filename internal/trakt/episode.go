@@ -1,8 +1,9 @@
 package trakt
 
 type MinimalItemEpisode struct {
-	Season int         `json:"season"`
-	Number int         `json:"number"`
-	Title  string      `json:"title"`
-	Ids    ListItemIds `json:"ids"`
+	Season  int         `json:"season"`
+	Number  int         `json:"number"`
+	Title   string      `json:"title"`
+	Ids     ListItemIds `json:"ids"`
+	Runtime int         `json:"runtime,omitempty"` // in minutes
 }
@@ -0,0 +1,50 @@
+package trakt
+
+import "github.com/MunifTanjim/stremthru/internal/request"
+
+type ScrobbleAction string
+
+const (
+	ScrobbleActionStart ScrobbleAction = "start"
+	ScrobbleActionPause ScrobbleAction = "pause"
+	ScrobbleActionStop  ScrobbleAction = "stop"
+)
+
+type ScrobbleIds struct {
+	Trakt int    `json:"trakt,omitempty"`
+	IMDB  string `json:"imdb,omitempty"`
+	TMDB  int    `json:"tmdb,omitempty"`
+	TVDB  int    `json:"tvdb,omitempty"`
+}
+
+type ScrobbleItem struct {
+	Ids ScrobbleIds `json:"ids"`
+}
+
+type ScrobbleEpisode struct {
+	Season int `json:"season"`
+	Number int `json:"number"`
+}
+
+type ScrobbleData struct {
+	ResponseError
+	Id       int64          `json:"id"`
+	Action   ScrobbleAction `json:"action"` // start / pause / scrobble
+	Progress float64        `json:"progress"`
+}
+
+type ScrobbleParams struct {
+	Ctx
+	Action   ScrobbleAction   `json:"-"`
+	Movie    *ScrobbleItem    `json:"movie,omitempty"`
+	Show     *ScrobbleItem    `json:"show,omitempty"`
+	Episode  *ScrobbleEpisode `json:"episode,omitempty"`
+	Progress float64          `json:"progress"`
+}
+
+func (c APIClient) Scrobble(params *ScrobbleParams) (request.APIResponse[ScrobbleData], error) {
+	params.JSON = params
+	response := ScrobbleData{}
+	res, err := c.Request("POST", "/scrobble/"+string(params.Action), params, &response)
+	return request.NewAPIResponse(res, response), err
+}
@@ -0,0 +1,339 @@
+package trakt_scrobbler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/trakt"
+	trakt_account "github.com/MunifTanjim/stremthru/internal/trakt/account"
+)
+
+var log = logger.Scoped("trakt/scrobbler")
+
+const (
+	// bytes a connection needs to serve before it is considered as playback,
+	// players open short-lived connections for probing the file.
+	minPlaybackBytes = 8 * 1024 * 1024
+	// grace period after the last connection is closed, players re-connect on seek.
+	idleTimeout = 30 * time.Second
+	// interval for checking if the served byte offset is progressing.
+	tickInterval = 2 * time.Minute
+)
+
+var scrobbleAccounts = cache.NewCachedValue(cache.CachedValueConfig[[]trakt_account.TraktAccount]{
+	Get: func() ([]trakt_account.TraktAccount, error) {
+		accounts, err := trakt_account.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		items := []trakt_account.TraktAccount{}
+		for i := range accounts {
+			if accounts[i].ScrobbleConfig.Enabled {
+				items = append(items, accounts[i])
+			}
+		}
+		return items, nil
+	},
+	TTL: 5 * time.Minute,
+})
+
+func InvalidateAccounts() {
+	scrobbleAccounts.Invalidate()
+}
+
+type target struct {
+	accountId string
+	tokenId   string
+	threshold int
+}
+
+func getTargets(user string) []target {
+	accounts, err := scrobbleAccounts.Get()
+	if err != nil {
+		log.Error("failed to get accounts", "error", err)
+		return nil
+	}
+	targets := []target{}
+	for i := range accounts {
+		account := &accounts[i]
+		if account.ScrobbleConfig.HasUser(user) {
+			targets = append(targets, target{
+				accountId: account.Id,
+				tokenId:   account.OAuthTokenId,
+				threshold: account.ScrobbleConfig.GetThreshold(),
+			})
+		}
+	}
+	return targets
+}
+
+// only imdb ids are supported, i.e. `tt1234567` or `tt1234567:1:2`
+func newScrobbleParams(stremId string) *trakt.ScrobbleParams {
+	if !strings.HasPrefix(stremId, "tt") {
+		return nil
+	}
+	parts := strings.Split(stremId, ":")
+	switch len(parts) {
+	case 1:
+		return &trakt.ScrobbleParams{
+			Movie: &trakt.ScrobbleItem{Ids: trakt.ScrobbleIds{IMDB: parts[0]}},
+		}
+	case 3:
+		season, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil
+		}
+		episode, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil
+		}
+		return &trakt.ScrobbleParams{
+			Show:    &trakt.ScrobbleItem{Ids: trakt.ScrobbleIds{IMDB: parts[0]}},
+			Episode: &trakt.ScrobbleEpisode{Season: season, Number: episode},
+		}
+	default:
+		return nil
+	}
+}
+
+type event struct {
+	action   trakt.ScrobbleAction
+	progress float64
+	isFinal  bool
+}
+
+type session struct {
+	key     string
+	stremId string
+	targets []target
+	params  *trakt.ScrobbleParams
+	events  chan event
+
+	mu           sync.Mutex
+	size         int64
+	position     int64
+	tickPosition int64
+	conns        int
+	state        trakt.ScrobbleAction
+	idleTimer    *time.Timer
+	closed       bool
+}
+
+var (
+	sessions   = map[string]*session{}
+	sessionsMu sync.Mutex
+)
+
+func (s *session) progress() float64 {
+	if s.size <= 0 {
+		return 0
+	}
+	return min(100, max(0, float64(s.position)*100/float64(s.size)))
+}
+
+// must be called with s.mu locked
+func (s *session) emit(action trakt.ScrobbleAction, isFinal bool) {
+	if s.closed {
+		return
+	}
+	s.state = action
+	select {
+	case s.events <- event{action: action, progress: s.progress(), isFinal: isFinal}:
+	default:
+		log.Warn("dropped event", "strem_id", s.stremId, "action", action)
+	}
+}
+
+func (s *session) send(t *target, action trakt.ScrobbleAction, progress float64) {
+	params := *s.params
+	params.Action = action
+	params.Progress = progress
+	if _, err := trakt.GetAPIClient(t.tokenId).Scrobble(&params); err != nil {
+		log.Error("failed to scrobble", "error", err, "account_id", t.accountId, "strem_id", s.stremId, "action", action)
+		return
+	}
+	log.Debug("scrobbled", "account_id", t.accountId, "strem_id", s.stremId, "action", action, "progress", progress)
+}
+
+func (s *session) run() {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e, ok := <-s.events:
+			if !ok {
+				return
+			}
+			for i := range s.targets {
+				t := &s.targets[i]
+				action := e.action
+				if e.isFinal {
+					if e.progress >= float64(t.threshold) {
+						action = trakt.ScrobbleActionStop
+					} else {
+						action = trakt.ScrobbleActionPause
+					}
+				}
+				s.send(t, action, e.progress)
+			}
+		case <-ticker.C:
+			s.tick()
+		}
+	}
+}
+
+func (s *session) tick() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	isProgressing := s.position != s.tickPosition
+	s.tickPosition = s.position
+	switch s.state {
+	case trakt.ScrobbleActionStart:
+		if !isProgressing {
+			s.emit(trakt.ScrobbleActionPause, false)
+		}
+	case trakt.ScrobbleActionPause:
+		if isProgressing {
+			s.emit(trakt.ScrobbleActionStart, false)
+		}
+	}
+}
+
+func (s *session) finish() {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conns > 0 || s.closed {
+		return
+	}
+
+	delete(sessions, s.key)
+	if s.state != "" {
+		s.emit(trakt.ScrobbleActionStop, true)
+	}
+	s.closed = true
+	close(s.events)
+}
+
+type Connection struct {
+	w       http.ResponseWriter
+	s       *session
+	start   int64
+	written int64
+}
+
+// Connect returns nil if scrobbling is not applicable for the playback.
+func Connect(user, stremId string) *Connection {
+	if user == "" || !config.Integration.Trakt.IsEnabled() {
+		return nil
+	}
+
+	params := newScrobbleParams(stremId)
+	if params == nil {
+		return nil
+	}
+
+	targets := getTargets(user)
+	if len(targets) == 0 {
+		return nil
+	}
+
+	key := user + ":" + stremId
+
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	s, ok := sessions[key]
+	if !ok {
+		s = &session{
+			key:     key,
+			stremId: stremId,
+			targets: targets,
+			params:  params,
+			events:  make(chan event, 8),
+		}
+		sessions[key] = s
+		go s.run()
+	}
+
+	s.mu.Lock()
+	s.conns++
+	if s.idleTimer != nil {
+		s.idleTimer.Stop()
+		s.idleTimer = nil
+	}
+	s.mu.Unlock()
+
+	return &Connection{s: s}
+}
+
+func (c *Connection) WrapResponseWriter(w http.ResponseWriter) http.ResponseWriter {
+	c.w = w
+	return c
+}
+
+func (c *Connection) Header() http.Header {
+	return c.w.Header()
+}
+
+func (c *Connection) WriteHeader(statusCode int) {
+	size := int64(0)
+	switch statusCode {
+	case http.StatusOK:
+		size, _ = strconv.ParseInt(c.w.Header().Get("Content-Length"), 10, 64)
+	case http.StatusPartialContent:
+		// bytes {start}-{end}/{size}
+		if cr, ok := strings.CutPrefix(c.w.Header().Get("Content-Range"), "bytes "); ok {
+			if rng, total, ok := strings.Cut(cr, "/"); ok {
+				size, _ = strconv.ParseInt(total, 10, 64)
+				start, _, _ := strings.Cut(rng, "-")
+				c.start, _ = strconv.ParseInt(start, 10, 64)
+			}
+		}
+	}
+	if size > 0 {
+		c.s.mu.Lock()
+		c.s.size = size
+		c.s.mu.Unlock()
+	}
+	c.w.WriteHeader(statusCode)
+}
+
+func (c *Connection) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.written += int64(n)
+	if c.written >= minPlaybackBytes {
+		c.s.mu.Lock()
+		c.s.position = c.start + c.written
+		if c.s.state != trakt.ScrobbleActionStart && c.s.size > 0 {
+			c.s.emit(trakt.ScrobbleActionStart, false)
+		}
+		c.s.mu.Unlock()
+	}
+	return n, err
+}
+
+func (c *Connection) Unwrap() http.ResponseWriter {
+	return c.w
+}
+
+func (c *Connection) Close() {
+	s := c.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conns--
+	if s.conns == 0 {
+		s.idleTimer = time.AfterFunc(idleTimeout, s.finish)
+	}
+}
@@ -0,0 +1,81 @@
+package trakt_scrobbler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/trakt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScrobbleParams(t *testing.T) {
+	for _, tc := range []struct {
+		stremId string
+		movie   string
+		show    string
+		episode *trakt.ScrobbleEpisode
+	}{
+		{stremId: "tt0111161", movie: "tt0111161"},
+		{stremId: "tt0903747:2:5", show: "tt0903747", episode: &trakt.ScrobbleEpisode{Season: 2, Number: 5}},
+		{stremId: "tt0903747:2"},
+		{stremId: "tt0903747:a:5"},
+		{stremId: "kitsu:1:2"},
+	} {
+		t.Run(tc.stremId, func(t *testing.T) {
+			params := newScrobbleParams(tc.stremId)
+			if tc.movie == "" && tc.show == "" {
+				assert.Nil(t, params)
+				return
+			}
+			if tc.movie != "" {
+				assert.Equal(t, tc.movie, params.Movie.Ids.IMDB)
+				assert.Nil(t, params.Show)
+			}
+			if tc.show != "" {
+				assert.Equal(t, tc.show, params.Show.Ids.IMDB)
+				assert.Equal(t, tc.episode, params.Episode)
+				assert.Nil(t, params.Movie)
+			}
+		})
+	}
+}
+
+func TestConnection(t *testing.T) {
+	s := &session{
+		stremId: "tt0111161",
+		events:  make(chan event, 8),
+	}
+	s.conns = 1
+
+	rec := httptest.NewRecorder()
+	c := &Connection{s: s}
+	w := c.WrapResponseWriter(rec)
+
+	size := int64(4 * minPlaybackBytes)
+	w.Header().Set("Content-Range", "bytes 1024-16777215/33554432")
+	w.WriteHeader(http.StatusPartialContent)
+	assert.Equal(t, size, s.size)
+	assert.Equal(t, int64(1024), c.start)
+
+	chunk := make([]byte, 1024*1024)
+	for range 7 {
+		w.Write(chunk)
+	}
+	assert.Equal(t, trakt.ScrobbleAction(""), s.state, "not started before min playback bytes")
+
+	w.Write(chunk)
+	assert.Equal(t, trakt.ScrobbleActionStart, s.state)
+	assert.Equal(t, int64(1024+minPlaybackBytes), s.position)
+
+	e := <-s.events
+	assert.Equal(t, trakt.ScrobbleActionStart, e.action)
+	assert.InDelta(t, 25.0, e.progress, 0.01)
+
+	s.tick()
+	s.tick()
+	assert.Equal(t, trakt.ScrobbleActionPause, s.state, "paused when position is not progressing")
+
+	e = <-s.events
+	assert.Equal(t, trakt.ScrobbleActionPause, e.action)
+}
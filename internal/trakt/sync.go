@@ -161,3 +161,183 @@ func (c APIClient) RemoveFromHistory(params *RemoveFromHistoryParams) (request.A
 	res, err := c.Request("POST", "/sync/history/remove", params, &response)
 	return request.NewAPIResponse(res, response), err
 }
+
+type WatchlistItemType string
+
+const (
+	WatchlistItemTypeAll    WatchlistItemType = ""
+	WatchlistItemTypeMovies WatchlistItemType = "movies"
+	WatchlistItemTypeShows  WatchlistItemType = "shows"
+)
+
+type GetWatchlistData = []ListItem
+
+type GetWatchlistParams struct {
+	Ctx
+	Type WatchlistItemType
+}
+
+func (c APIClient) GetWatchlist(params *GetWatchlistParams) (request.APIResponse[GetWatchlistData], error) {
+	path := "/sync/watchlist"
+	if params.Type != "" {
+		path += "/" + string(params.Type)
+	}
+
+	response := paginatedResponseData[ListItem]{}
+	res, err := c.Request("GET", path, params, &response)
+	return request.NewAPIResponse(res, response.data), err
+}
+
+type SyncWatchlistParamsItem struct {
+	Ids ListItemIds `json:"ids"`
+}
+
+type AddToWatchlistData struct {
+	ResponseError
+	Added struct {
+		Movies int `json:"movies"`
+		Shows  int `json:"shows"`
+	} `json:"added"`
+	Existing struct {
+		Movies int `json:"movies"`
+		Shows  int `json:"shows"`
+	} `json:"existing"`
+	NotFound struct {
+		Movies []SyncHistoryResponseNotFoundItem `json:"movies"`
+		Shows  []SyncHistoryResponseNotFoundItem `json:"shows"`
+	} `json:"not_found"`
+}
+
+type AddToWatchlistParams struct {
+	Ctx
+	Movies []SyncWatchlistParamsItem `json:"movies,omitempty"`
+	Shows  []SyncWatchlistParamsItem `json:"shows,omitempty"`
+}
+
+func (c APIClient) AddToWatchlist(params *AddToWatchlistParams) (request.APIResponse[AddToWatchlistData], error) {
+	params.JSON = params
+	response := AddToWatchlistData{}
+	res, err := c.Request("POST", "/sync/watchlist", params, &response)
+	return request.NewAPIResponse(res, response), err
+}
+
+type RatingItemType string
+
+const (
+	RatingItemTypeAll      RatingItemType = ""
+	RatingItemTypeMovies   RatingItemType = "movies"
+	RatingItemTypeShows    RatingItemType = "shows"
+	RatingItemTypeEpisodes RatingItemType = "episodes"
+)
+
+type RatingItem struct {
+	RatedAt time.Time           `json:"rated_at"`
+	Rating  int                 `json:"rating"` // 1 - 10
+	Type    ItemType            `json:"type"`
+	Movie   *ListItemMovie      `json:"movie,omitempty"`
+	Show    *ListItemShow       `json:"show,omitempty"`
+	Episode *MinimalItemEpisode `json:"episode,omitempty"`
+}
+
+type GetRatingsData = []RatingItem
+
+type GetRatingsParams struct {
+	Ctx
+	Type RatingItemType
+}
+
+func (c APIClient) GetRatings(params *GetRatingsParams) (request.APIResponse[GetRatingsData], error) {
+	path := "/sync/ratings"
+	if params.Type != "" {
+		path += "/" + string(params.Type)
+	}
+
+	response := paginatedResponseData[RatingItem]{}
+	res, err := c.Request("GET", path, params, &response)
+	return request.NewAPIResponse(res, response.data), err
+}
+
+type SyncRatingsParamsItem struct {
+	RatedAt *time.Time  `json:"rated_at,omitempty"`
+	Rating  int         `json:"rating"`
+	Ids     ListItemIds `json:"ids"`
+}
+
+type AddRatingsData struct {
+	ResponseError
+	Added struct {
+		Movies   int `json:"movies"`
+		Shows    int `json:"shows"`
+		Seasons  int `json:"seasons"`
+		Episodes int `json:"episodes"`
+	} `json:"added"`
+	NotFound struct {
+		Movies   []SyncHistoryResponseNotFoundItem `json:"movies"`
+		Shows    []SyncHistoryResponseNotFoundItem `json:"shows"`
+		Seasons  []SyncHistoryResponseNotFoundItem `json:"seasons"`
+		Episodes []SyncHistoryResponseNotFoundItem `json:"episodes"`
+	} `json:"not_found"`
+}
+
+type AddRatingsParams struct {
+	Ctx
+	Movies []SyncRatingsParamsItem `json:"movies,omitempty"`
+	Shows  []SyncRatingsParamsItem `json:"shows,omitempty"`
+}
+
+func (c APIClient) AddRatings(params *AddRatingsParams) (request.APIResponse[AddRatingsData], error) {
+	params.JSON = params
+	response := AddRatingsData{}
+	res, err := c.Request("POST", "/sync/ratings", params, &response)
+	return request.NewAPIResponse(res, response), err
+}
+
+type PlaybackItemType string
+
+const (
+	PlaybackItemTypeAll      PlaybackItemType = ""
+	PlaybackItemTypeMovies   PlaybackItemType = "movies"
+	PlaybackItemTypeEpisodes PlaybackItemType = "episodes"
+)
+
+type PlaybackItem struct {
+	Id       int64               `json:"id"`
+	Progress float64             `json:"progress"` // 0.0 - 100.0
+	PausedAt time.Time           `json:"paused_at"`
+	Type     ItemType            `json:"type"` // "movie" or "episode"
+	Movie    *ListItemMovie      `json:"movie,omitempty"`
+	Episode  *MinimalItemEpisode `json:"episode,omitempty"`
+	Show     *ListItemShow       `json:"show,omitempty"`
+}
+
+type GetPlaybackProgressData = []PlaybackItem
+
+type GetPlaybackProgressParams struct {
+	Ctx
+	Type     PlaybackItemType
+	StartAt  *time.Time
+	EndAt    *time.Time
+	Extended string // full
+}
+
+func (c APIClient) GetPlaybackProgress(params *GetPlaybackProgressParams) (request.APIResponse[GetPlaybackProgressData], error) {
+	path := "/sync/playback"
+	if params.Type != "" {
+		path += "/" + string(params.Type)
+	}
+
+	params.Query = &url.Values{}
+	if params.StartAt != nil {
+		params.Query.Set("start_at", params.StartAt.UTC().Format(time.RFC3339))
+	}
+	if params.EndAt != nil {
+		params.Query.Set("end_at", params.EndAt.UTC().Format(time.RFC3339))
+	}
+	if params.Extended != "" {
+		params.Query.Set("extended", params.Extended)
+	}
+
+	response := paginatedResponseData[PlaybackItem]{}
+	res, err := c.Request("GET", path, params, &response)
+	return request.NewAPIResponse(res, response.data), err
+}
@@ -67,6 +67,15 @@ func GetPool() (*usenet_pool.Pool, error) {
 	return globalManager.getPool(), nil
 }
 
+// GetPoolInfo returns the info for the current pool, without initializing it.
+func GetPoolInfo() (info usenet_pool.PoolInfo, ok bool) {
+	pool := globalManager.getPool()
+	if pool == nil {
+		return info, false
+	}
+	return pool.GetPoolInfo(), true
+}
+
 func LockServer(serverId string) error {
 	globalManager.lockedServersMutex.Lock()
 	defer globalManager.lockedServersMutex.Unlock()
@@ -246,6 +255,7 @@ func (m *Manager) createPoolFromServers(servers []usenet_server.UsenetServer) (*
 					Password:      password,
 					TLS:           s.TLS,
 					TLSSkipVerify: s.TLSSkipVerify,
+					Compression:   s.Compression,
 				},
 				MaxSize: int32(s.MaxConnections),
 			},
@@ -281,6 +291,7 @@ func (m *Manager) createProviderConfig(server *usenet_server.UsenetServer) (*use
 				Password:      password,
 				TLS:           server.TLS,
 				TLSSkipVerify: server.TLSSkipVerify,
+				Compression:   server.Compression,
 			},
 			MaxSize: int32(server.MaxConnections),
 		},
@@ -116,6 +116,26 @@ func ParseBytes(data []byte) (*NZB, error) {
 	return Parse(bytes.NewReader(data))
 }
 
+const xmlDoctype = `<!DOCTYPE nzb PUBLIC "-//newzBin//DTD NZB 1.1//EN" "http://www.newzbin.com/DTD/nzb/nzb-1.1.dtd">` + "\n"
+
+const xmlNamespace = "http://www.newzbin.com/DTD/2003/nzb"
+
+func (n *NZB) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(xmlDoctype)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.EncodeElement(n, xml.StartElement{
+		Name: xml.Name{Local: "nzb"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: xmlNamespace}},
+	}); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
 func (n *NZB) TotalSize() (bytes int64) {
 	for i := range n.Files {
 		bytes += n.Files[i].Size()
@@ -113,3 +113,34 @@ func TestMessageIds_Ordering(t *testing.T) {
 		"msg-id-3@example.com",
 	}, msgIds)
 }
+
+func TestBytes(t *testing.T) {
+	nzb := &NZB{
+		Head: &Head{Meta: []Meta{{Type: "title", Value: "My Test File"}}},
+		Files: []File{
+			{
+				Poster:  "user@example.com",
+				Date:    1234567890,
+				Subject: `[1/1] - "file.mkv" yEnc (1/2)`,
+				Groups:  []string{"alt.binaries.test"},
+				Segments: []Segment{
+					{Bytes: 500000, Number: 1, MessageId: "msg-id-1@example.com"},
+					{Bytes: 450000, Number: 2, MessageId: "msg-id-2@example.com"},
+				},
+			},
+		},
+	}
+
+	data, err := nzb.Bytes()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `<!DOCTYPE nzb PUBLIC`)
+	assert.Contains(t, string(data), `<nzb xmlns="http://www.newzbin.com/DTD/2003/nzb">`)
+
+	parsed, err := ParseBytes(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "My Test File", parsed.GetMeta("title"))
+	assert.Equal(t, 1, parsed.FileCount())
+	assert.Equal(t, "file.mkv", parsed.Files[0].Name())
+	assert.Equal(t, int64(950000), parsed.TotalSize())
+	assert.Equal(t, []string{"msg-id-1@example.com", "msg-id-2@example.com"}, parsed.Files[0].MessageIds())
+}
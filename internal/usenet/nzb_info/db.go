@@ -24,6 +24,7 @@ var Column = struct {
 	User       string
 	Date       string
 	Status     string
+	Category   string
 	CAt        string
 	UAt        string
 }{
@@ -39,6 +40,7 @@ var Column = struct {
 	User:       "user",
 	Date:       "date",
 	Status:     "status",
+	Category:   "category",
 	CAt:        "cat",
 	UAt:        "uat",
 }
@@ -56,6 +58,7 @@ var columns = []string{
 	Column.User,
 	Column.Date,
 	Column.Status,
+	Column.Category,
 	Column.CAt,
 	Column.UAt,
 }
@@ -73,14 +76,15 @@ type NZBInfo struct {
 	User         string
 	Date         db.Timestamp
 	Status       string
+	Category     string
 	CAt          db.Timestamp
 	UAt          db.Timestamp
 }
 
 var query_upsert = fmt.Sprintf(
-	`INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (%s) DO UPDATE SET %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = %s`,
+	`INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (%s) DO UPDATE SET %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = EXCLUDED.%s, %s = %s`,
 	TableName,
-	db.JoinColumnNames(Column.Id, Column.Hash, Column.Name, Column.Size, Column.FileCount, Column.Password, Column.URL, Column.Files, Column.Streamable, Column.User, Column.Date, Column.Status),
+	db.JoinColumnNames(Column.Id, Column.Hash, Column.Name, Column.Size, Column.FileCount, Column.Password, Column.URL, Column.Files, Column.Streamable, Column.User, Column.Date, Column.Status, Column.Category),
 	Column.Hash,
 	Column.Name, Column.Name,
 	Column.Size, Column.Size,
@@ -91,6 +95,7 @@ var query_upsert = fmt.Sprintf(
 	Column.Streamable, Column.Streamable,
 	Column.Date, Column.Date,
 	Column.Status, Column.Status,
+	Column.Category, Column.Category,
 	Column.UAt, db.CurrentTimestamp,
 )
 
@@ -111,6 +116,7 @@ func Upsert(info *NZBInfo) error {
 		info.User,
 		info.Date,
 		info.Status,
+		info.Category,
 	)
 	return err
 }
@@ -128,6 +134,19 @@ func UpdateStatus(hash string, status string) error {
 	return err
 }
 
+var query_update_category = fmt.Sprintf(
+	`UPDATE %s SET %s = ?, %s = %s WHERE %s = ?`,
+	TableName,
+	Column.Category,
+	Column.UAt, db.CurrentTimestamp,
+	Column.Hash,
+)
+
+func UpdateCategory(hash string, category string) error {
+	_, err := db.Exec(query_update_category, category, hash)
+	return err
+}
+
 var query_get_by_id = fmt.Sprintf(
 	`SELECT %s FROM %s WHERE %s = ?`,
 	db.JoinColumnNames(columns...),
@@ -138,7 +157,7 @@ var query_get_by_id = fmt.Sprintf(
 func GetById(id string) (*NZBInfo, error) {
 	row := db.QueryRow(query_get_by_id, id)
 	info := NZBInfo{}
-	if err := row.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.CAt, &info.UAt); err != nil {
+	if err := row.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.Category, &info.CAt, &info.UAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -157,7 +176,7 @@ var query_get_by_hash = fmt.Sprintf(
 func GetByHash(hash string) (*NZBInfo, error) {
 	row := db.QueryRow(query_get_by_hash, hash)
 	info := NZBInfo{}
-	if err := row.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.CAt, &info.UAt); err != nil {
+	if err := row.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.Category, &info.CAt, &info.UAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -183,7 +202,38 @@ func GetAll() ([]NZBInfo, error) {
 	infos := []NZBInfo{}
 	for rows.Next() {
 		info := NZBInfo{}
-		if err := rows.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.CAt, &info.UAt); err != nil {
+		if err := rows.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.Category, &info.CAt, &info.UAt); err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+var query_get_all_by_user = fmt.Sprintf(
+	`SELECT %s FROM %s WHERE %s = ? ORDER BY %s DESC`,
+	db.JoinColumnNames(columns...),
+	TableName,
+	db.JoinColumnNames(Column.User),
+	Column.CAt,
+)
+
+func GetAllByUser(user string) ([]NZBInfo, error) {
+	rows, err := db.Query(query_get_all_by_user, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	infos := []NZBInfo{}
+	for rows.Next() {
+		info := NZBInfo{}
+		if err := rows.Scan(&info.Id, &info.Hash, &info.Name, &info.Size, &info.FileCount, &info.Password, &info.URL, &info.ContentFiles, &info.Streamable, &info.User, &info.Date, &info.Status, &info.Category, &info.CAt, &info.UAt); err != nil {
 			return nil, err
 		}
 		infos = append(infos, info)
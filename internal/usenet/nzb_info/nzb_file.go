@@ -82,10 +82,10 @@ var nzbFetchErrCache = cache.NewCache[string](&cache.CacheConfig{
 })
 
 func HashNZBFileLink(link string) string {
-	return util.MD5Hash(cleanNZBFileLink(link))
+	return util.MD5Hash(CleanNZBFileLink(link))
 }
 
-func cleanNZBFileLink(link string) string {
+func CleanNZBFileLink(link string) string {
 	link, _, ok := strings.Cut(link, "?")
 	if !ok {
 		link, _, _ = strings.Cut(link, "&")
@@ -103,7 +103,7 @@ var nzbFileFetcher = func() *http.Client {
 }()
 
 func fetchNZBFile(link string, name string, log *logger.Logger, onFetch func(*NZBFile)) (*NZBFile, error) {
-	clink := cleanNZBFileLink(link)
+	clink := CleanNZBFileLink(link)
 	cacheKey := HashNZBFileLink(link)
 	var nzbFile NZBFile
 	if nzbFileCache.Get(cacheKey, &nzbFile) {
@@ -1,6 +1,8 @@
 package nzb_info
 
 import (
+	"time"
+
 	"github.com/MunifTanjim/stremthru/internal/job/job_queue"
 )
 
@@ -42,6 +44,10 @@ func GetAllJob() ([]JobEntry, error) {
 	return job_queue.GetEntriesByName[JobData](JobQueueName)
 }
 
+func GetAllJobByUser(user string) ([]JobEntry, error) {
+	return job_queue.GetEntriesByPayloadField[JobData](JobQueueName, "user", user)
+}
+
 func GetJobById(id string) (*JobEntry, error) {
 	return job_queue.GetEntryByKey[JobData](JobQueueName, id)
 }
@@ -49,3 +55,28 @@ func GetJobById(id string) (*JobEntry, error) {
 func DeleteJob(id string) error {
 	return job_queue.DeleteEntries(JobQueueName, []string{id})
 }
+
+// paused jobs are pushed far enough into the future to never get picked up
+var jobPausedUntil = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func IsJobPaused(entry *JobEntry) bool {
+	return !entry.ProcessAfter.Time.Before(jobPausedUntil)
+}
+
+func PauseJobs(ids []string) error {
+	return job_queue.DelayEntries(JobQueueName, ids, jobPausedUntil)
+}
+
+func ResumeJobs(ids []string) error {
+	return job_queue.DelayEntries(JobQueueName, ids, time.Now())
+}
+
+func SetJobCategory(id string, category string) error {
+	entry, err := GetJobById(id)
+	if err != nil || entry == nil {
+		return err
+	}
+	data := entry.Payload.Data
+	data.Category = category
+	return job_queue.SetEntryPayload(JobQueueName, id, data)
+}
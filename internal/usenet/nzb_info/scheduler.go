@@ -1,7 +1,6 @@
 package nzb_info
 
 import (
-	"context"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/db"
@@ -68,6 +67,7 @@ var scheduler = job.NewScheduler(&job.SchedulerConfig[JobData]{
 				User:      data.User,
 				Date:      db.Timestamp{Time: nzbDate},
 				Status:    string(store.NewzStatusDownloading),
+				Category:  data.Category,
 			}
 
 			if err := Upsert(info); err != nil {
@@ -78,7 +78,7 @@ var scheduler = job.NewScheduler(&job.SchedulerConfig[JobData]{
 			if err != nil {
 				return err
 			}
-			content, err := pool.InspectNZBContent(context.Background(), nzbDoc, password)
+			content, err := pool.InspectNZBContent(j.Context(), nzbDoc, password)
 			if err != nil {
 				log.Warn("failed to inspect nzb content", "error", err)
 				UpdateStatus(hash, string(store.NewzStatusFailed))
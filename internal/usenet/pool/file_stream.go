@@ -49,6 +49,7 @@ type FileStream struct {
 
 	pool       *Pool
 	bufferSize int64
+	repair     segmentRepairFunc
 
 	mu     sync.Mutex
 	ctx    context.Context
@@ -66,15 +67,34 @@ func NewFileStream(
 	file *nzb.File,
 	bufferSize int64,
 ) (*FileStream, error) {
-	if bufferSize <= 0 {
-		bufferSize = config.Newz.StreamBufferSize
-	}
+	return newFileStream(ctx, pool, file, bufferSize, nil)
+}
 
-	firstSegment, err := pool.fetchFirstSegment(ctx, file)
+func newFileStream(
+	ctx context.Context,
+	pool *Pool,
+	file *nzb.File,
+	bufferSize int64,
+	repair segmentRepairFunc,
+) (*FileStream, error) {
+	firstSegment, err := pool.fetchOrRepairSegment(ctx, &file.Segments[0], file.Groups, repair)
 	if err != nil {
 		return nil, err
 	}
-	fileSize := firstSegment.FileSize
+	return newFileStreamWithSize(ctx, pool, file, firstSegment.FileSize, bufferSize, repair), nil
+}
+
+func newFileStreamWithSize(
+	ctx context.Context,
+	pool *Pool,
+	file *nzb.File,
+	fileSize int64,
+	bufferSize int64,
+	repair segmentRepairFunc,
+) *FileStream {
+	if bufferSize <= 0 {
+		bufferSize = config.Newz.StreamBufferSize
+	}
 
 	fileLog.Trace("file stream - created", "segment_count", file.SegmentCount(), "file_size", fileSize, "buffer_size", bufferSize)
 
@@ -98,10 +118,11 @@ func NewFileStream(
 
 		pool:       pool,
 		bufferSize: bufferSize,
+		repair:     repair,
 
 		ctx:    ctx,
 		cancel: cancel,
-	}, nil
+	}
 }
 
 func (s *FileStream) Read(p []byte) (n int, err error) {
@@ -207,7 +228,7 @@ func (s *FileStream) createSegmentsStream(startPos int64, bufferSize int64) (*Se
 	fileLog.Trace("create segments stream - start", "position", startPos)
 
 	if startPos == 0 {
-		return NewSegmentsStream(s.ctx, s.pool, s.file.Segments, s.file.Groups, bufferSize), nil
+		return newSegmentsStream(s.ctx, s.pool, s.file.Segments, s.file.Groups, bufferSize, s.repair), nil
 	}
 
 	result, err := s.interpolationSearch(startPos)
@@ -217,7 +238,7 @@ func (s *FileStream) createSegmentsStream(startPos int64, bufferSize int64) (*Se
 
 	fileLog.Trace("create segments stream - found segment", "segment_idx", result.SegmentIndex, "byte_range", fmt.Sprintf("[%d, %d)", result.ByteRange.Start, result.ByteRange.End))
 
-	stream := NewSegmentsStream(s.ctx, s.pool, s.file.Segments[result.SegmentIndex:], s.file.Groups, bufferSize)
+	stream := newSegmentsStream(s.ctx, s.pool, s.file.Segments[result.SegmentIndex:], s.file.Groups, bufferSize, s.repair)
 
 	skipBytes := startPos - result.ByteRange.Start
 	if skipBytes > 0 {
@@ -239,7 +260,7 @@ func (s *FileStream) getSegmentByteRange(ctx context.Context, index int) (ByteRa
 
 	fileLog.Trace("file stream - get segment byte range", "segment_num", segment.Number, "message_id", segment.MessageId)
 
-	data, err := s.pool.fetchSegment(ctx, segment, s.file.Groups)
+	data, err := s.pool.fetchOrRepairSegment(ctx, segment, s.file.Groups, s.repair)
 	if err != nil {
 		return ByteRange{}, err
 	}
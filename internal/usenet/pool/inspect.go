@@ -171,10 +171,16 @@ func (p *Pool) InspectNZBContent(ctx context.Context, nzbDoc *nzb.NZB, password
 	}
 	fetchPool.StopAndWait()
 
+	repairer := newPar2Repairer(p, nzbDoc)
+
 	for _, fr := range fetchResults {
 		filename := fr.nzbFile.Name()
 
 		articleNotFound := errors.Is(fr.startErr, ErrArticleNotFound) || errors.Is(fr.endErr, ErrArticleNotFound)
+		repairable := articleNotFound && repairer.canRepair(ctx, fr.nzbFile)
+		if repairable {
+			inspectLog.Debug("missing articles can be repaired using par2", "name", filename)
+		}
 
 		if isVideoFile(filename) {
 			entry := NZBContentFile{
@@ -184,7 +190,7 @@ func (p *Pool) InspectNZBContent(ctx context.Context, nzbDoc *nzb.NZB, password
 				Streamable: true,
 			}
 			if articleNotFound {
-				entry.Streamable = false
+				entry.Streamable = repairable
 				entry.Errors = append(entry.Errors, NZBContentFileErrorArticleNotFound)
 			} else if fr.startErr != nil {
 				entry.Streamable = false
@@ -198,7 +204,7 @@ func (p *Pool) InspectNZBContent(ctx context.Context, nzbDoc *nzb.NZB, password
 		}
 
 		if IsArchiveFile(filename) {
-			if articleNotFound {
+			if articleNotFound && !repairable {
 				content.Files = append(content.Files, NZBContentFile{
 					Type:       NZBContentFileTypeArchive,
 					Name:       filename,
@@ -236,7 +242,7 @@ func (p *Pool) InspectNZBContent(ctx context.Context, nzbDoc *nzb.NZB, password
 		} else {
 			fileType = DetectFileType(fr.startSegment.Body, filename)
 			if fr.endErr != nil && errors.Is(fr.endErr, ErrArticleNotFound) {
-				streamable = false
+				streamable = repairable
 				errs = append(errs, NZBContentFileErrorArticleNotFound)
 			}
 		}
@@ -0,0 +1,345 @@
+package usenet_pool
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+)
+
+// https://parchive.github.io/doc/Parity%20Volume%20Set%20Specification%20v2.0.html
+
+const par2PacketHeaderSize = 64
+
+var (
+	par2PacketMagic = [8]byte{'P', 'A', 'R', '2', 0, 'P', 'K', 'T'}
+
+	par2PacketTypeMain     = [16]byte{'P', 'A', 'R', ' ', '2', '.', '0', 0, 'M', 'a', 'i', 'n', 0, 0, 0, 0}
+	par2PacketTypeFileDesc = [16]byte{'P', 'A', 'R', ' ', '2', '.', '0', 0, 'F', 'i', 'l', 'e', 'D', 'e', 's', 'c'}
+	par2PacketTypeIFSC     = [16]byte{'P', 'A', 'R', ' ', '2', '.', '0', 0, 'I', 'F', 'S', 'C', 0, 0, 0, 0}
+	par2PacketTypeRecvSlic = [16]byte{'P', 'A', 'R', ' ', '2', '.', '0', 0, 'R', 'e', 'c', 'v', 'S', 'l', 'i', 'c'}
+)
+
+// packets other than recovery slices are tiny, anything bigger is garbage
+const par2MaxCriticalPacketSize = 16 * 1024 * 1024
+
+var errPar2InvalidPacket = errors.New("par2: invalid packet")
+
+func IsPar2File(filename string) bool {
+	return strings.HasSuffix(strings.ToLower(filename), ".par2")
+}
+
+type par2PacketHeader struct {
+	length int64
+	hash   [16]byte
+	setId  [16]byte
+	typ    [16]byte
+}
+
+func parsePar2PacketHeader(b []byte) (*par2PacketHeader, error) {
+	if len(b) < par2PacketHeaderSize || !bytes.Equal(b[0:8], par2PacketMagic[:]) {
+		return nil, errPar2InvalidPacket
+	}
+	h := &par2PacketHeader{
+		length: int64(binary.LittleEndian.Uint64(b[8:16])),
+	}
+	if h.length < par2PacketHeaderSize || h.length%4 != 0 {
+		return nil, errPar2InvalidPacket
+	}
+	copy(h.hash[:], b[16:32])
+	copy(h.setId[:], b[32:48])
+	copy(h.typ[:], b[48:64])
+	return h, nil
+}
+
+type par2SliceChecksum struct {
+	hash  [16]byte
+	crc32 uint32
+}
+
+type par2FileDesc struct {
+	id        [16]byte
+	hash      [16]byte
+	hash16k   [16]byte
+	length    int64
+	name      string
+	checksums []par2SliceChecksum
+}
+
+type par2RecoverySlice struct {
+	exponent   uint32
+	nzbFileIdx int
+	offset     int64 // offset of the recovery data in the par2 file
+}
+
+type par2SetFile struct {
+	*par2FileDesc
+	firstSlice int // index of the first input slice in the recovery set
+	sliceCount int
+	nzbFileIdx int // -1 when the file is not part of the nzb
+}
+
+type par2Set struct {
+	sliceSize  int64
+	sliceCount int
+	files      []par2SetFile
+	recovery   []par2RecoverySlice
+	constants  []uint16
+}
+
+func (s *par2Set) getFileByNZBFileIdx(idx int) *par2SetFile {
+	for i := range s.files {
+		if s.files[i].nzbFileIdx == idx {
+			return &s.files[i]
+		}
+	}
+	return nil
+}
+
+type par2SetBuilder struct {
+	hasMain    bool
+	sliceSize  int64
+	fileIds    [][16]byte
+	fileDescs  map[[16]byte]*par2FileDesc
+	checksums  map[[16]byte][]par2SliceChecksum
+	recovery   []par2RecoverySlice
+	seenExpons map[uint32]struct{}
+}
+
+func newPar2SetBuilder() *par2SetBuilder {
+	return &par2SetBuilder{
+		fileDescs:  map[[16]byte]*par2FileDesc{},
+		checksums:  map[[16]byte][]par2SliceChecksum{},
+		seenExpons: map[uint32]struct{}{},
+	}
+}
+
+func (b *par2SetBuilder) addPacket(h *par2PacketHeader, body []byte) error {
+	if md5.Sum(slices.Concat(h.setId[:], h.typ[:], body)) != h.hash {
+		return errPar2InvalidPacket
+	}
+
+	switch h.typ {
+	case par2PacketTypeMain:
+		if len(body) < 12 {
+			return errPar2InvalidPacket
+		}
+		if b.hasMain {
+			return nil
+		}
+		sliceSize := int64(binary.LittleEndian.Uint64(body[0:8]))
+		fileCount := int(binary.LittleEndian.Uint32(body[8:12]))
+		if sliceSize <= 0 || sliceSize%4 != 0 || len(body) < 12+fileCount*16 {
+			return errPar2InvalidPacket
+		}
+		b.hasMain = true
+		b.sliceSize = sliceSize
+		b.fileIds = make([][16]byte, fileCount)
+		for i := range fileCount {
+			copy(b.fileIds[i][:], body[12+i*16:])
+		}
+	case par2PacketTypeFileDesc:
+		if len(body) < 56 {
+			return errPar2InvalidPacket
+		}
+		fd := &par2FileDesc{
+			length: int64(binary.LittleEndian.Uint64(body[48:56])),
+			name:   string(bytes.TrimRight(body[56:], "\x00")),
+		}
+		copy(fd.id[:], body[0:16])
+		copy(fd.hash[:], body[16:32])
+		copy(fd.hash16k[:], body[32:48])
+		b.fileDescs[fd.id] = fd
+	case par2PacketTypeIFSC:
+		if len(body) < 16 || (len(body)-16)%20 != 0 {
+			return errPar2InvalidPacket
+		}
+		var id [16]byte
+		copy(id[:], body[0:16])
+		checksums := make([]par2SliceChecksum, (len(body)-16)/20)
+		for i := range checksums {
+			entry := body[16+i*20:]
+			copy(checksums[i].hash[:], entry[0:16])
+			checksums[i].crc32 = binary.LittleEndian.Uint32(entry[16:20])
+		}
+		b.checksums[id] = checksums
+	}
+	return nil
+}
+
+func (b *par2SetBuilder) addRecoverySlice(exponent uint32, nzbFileIdx int, offset int64) {
+	if _, seen := b.seenExpons[exponent]; seen {
+		return
+	}
+	b.seenExpons[exponent] = struct{}{}
+	b.recovery = append(b.recovery, par2RecoverySlice{
+		exponent:   exponent,
+		nzbFileIdx: nzbFileIdx,
+		offset:     offset,
+	})
+}
+
+// scan reads all the packets in a par2 file. Recovery slice data is not read,
+// only its location is recorded.
+func (b *par2SetBuilder) scan(r io.ReaderAt, size int64, nzbFileIdx int) error {
+	header := make([]byte, par2PacketHeaderSize+4)
+	offset := int64(0)
+	for offset+par2PacketHeaderSize <= size {
+		n, err := r.ReadAt(header, offset)
+		if n < par2PacketHeaderSize {
+			if err == nil || err == io.EOF || err == io.ErrUnexpectedEOF {
+				err = errPar2InvalidPacket
+			}
+			return err
+		}
+		h, err := parsePar2PacketHeader(header)
+		if err != nil {
+			return err
+		}
+		if offset+h.length > size {
+			return errPar2InvalidPacket
+		}
+
+		if h.typ == par2PacketTypeRecvSlic {
+			if n < len(header) || h.length < par2PacketHeaderSize+4 {
+				return errPar2InvalidPacket
+			}
+			exponent := binary.LittleEndian.Uint32(header[par2PacketHeaderSize:])
+			b.addRecoverySlice(exponent, nzbFileIdx, offset+par2PacketHeaderSize+4)
+		} else if h.length <= par2MaxCriticalPacketSize {
+			body := make([]byte, h.length-par2PacketHeaderSize)
+			if _, err := r.ReadAt(body, offset+par2PacketHeaderSize); err != nil {
+				return err
+			}
+			if err := b.addPacket(h, body); err != nil {
+				if err != errPar2InvalidPacket {
+					return err
+				}
+				par2Log.Debug("par2 - skipped invalid packet", "offset", offset)
+			}
+		}
+
+		offset += h.length
+	}
+	return nil
+}
+
+func (b *par2SetBuilder) build() (*par2Set, error) {
+	if !b.hasMain {
+		return nil, errors.New("par2: missing main packet")
+	}
+
+	set := &par2Set{
+		sliceSize: b.sliceSize,
+		files:     make([]par2SetFile, 0, len(b.fileIds)),
+	}
+	for _, id := range b.fileIds {
+		fd, ok := b.fileDescs[id]
+		if !ok {
+			return nil, fmt.Errorf("par2: missing file description packet for %x", id)
+		}
+		fd.checksums = b.checksums[id]
+		sliceCount := int((fd.length + b.sliceSize - 1) / b.sliceSize)
+		set.files = append(set.files, par2SetFile{
+			par2FileDesc: fd,
+			firstSlice:   set.sliceCount,
+			sliceCount:   sliceCount,
+			nzbFileIdx:   -1,
+		})
+		set.sliceCount += sliceCount
+	}
+	if set.sliceCount > 32768 {
+		return nil, fmt.Errorf("par2: too many input slices: %d", set.sliceCount)
+	}
+	set.recovery = b.recovery
+	set.constants = gf16InputConstants(set.sliceCount)
+	return set, nil
+}
+
+func (p *Pool) loadPar2Set(ctx context.Context, nzbDoc *nzb.NZB) (*par2Set, error) {
+	var par2FileIdxs []int
+	for i := range nzbDoc.Files {
+		if IsPar2File(nzbDoc.Files[i].Name()) && nzbDoc.Files[i].SegmentCount() > 0 {
+			par2FileIdxs = append(par2FileIdxs, i)
+		}
+	}
+	if len(par2FileIdxs) == 0 {
+		return nil, errors.New("par2: no par2 files")
+	}
+
+	// the index file (smallest one) carries only the critical packets
+	slices.SortStableFunc(par2FileIdxs, func(a, b int) int {
+		return int(nzbDoc.Files[a].Size() - nzbDoc.Files[b].Size())
+	})
+
+	builder := newPar2SetBuilder()
+	for _, idx := range par2FileIdxs {
+		file := &nzbDoc.Files[idx]
+		stream, err := newFileStream(ctx, p, file, 0, nil)
+		if err != nil {
+			p.Log.Debug("par2 - failed to open file", "error", err, "filename", file.Name())
+			continue
+		}
+		err = builder.scan(stream, stream.Size(), idx)
+		stream.Close()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			p.Log.Debug("par2 - failed to scan file", "error", err, "filename", file.Name())
+		}
+	}
+
+	set, err := builder.build()
+	if err != nil {
+		return nil, err
+	}
+
+	unmatched := []int{}
+	for i := range set.files {
+		f := &set.files[i]
+		for idx := range nzbDoc.Files {
+			if nzbDoc.Files[idx].Name() == f.name {
+				f.nzbFileIdx = idx
+				break
+			}
+		}
+		if f.nzbFileIdx == -1 {
+			unmatched = append(unmatched, i)
+		}
+	}
+
+	// obfuscated posts, match using the hash of the first 16k
+	for _, i := range unmatched {
+		f := &set.files[i]
+		for idx := range nzbDoc.Files {
+			file := &nzbDoc.Files[idx]
+			if IsPar2File(file.Name()) || file.SegmentCount() == 0 || set.getFileByNZBFileIdx(idx) != nil {
+				continue
+			}
+			data, err := p.fetchSegment(ctx, &file.Segments[0], file.Groups)
+			if err != nil || data.FileSize != f.length || data.ByteRange.Start != 0 {
+				continue
+			}
+			size := min(f.length, 16*1024)
+			if int64(len(data.Body)) < size {
+				continue
+			}
+			if md5.Sum(data.Body[:size]) == f.hash16k {
+				f.nzbFileIdx = idx
+				break
+			}
+		}
+	}
+
+	p.Log.Debug("par2 - loaded recovery set", "slice_size", set.sliceSize, "slice_count", set.sliceCount, "recovery_count", len(set.recovery), "file_count", len(set.files))
+
+	return set, nil
+}
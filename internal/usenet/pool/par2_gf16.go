@@ -0,0 +1,152 @@
+package usenet_pool
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// PAR2 Reed-Solomon arithmetic in GF(2^16) with generator polynomial 0x1100B.
+
+const (
+	gf16Limit      = 0xFFFF
+	gf16Generator  = 0x1100B
+	gf16FieldCount = 0x10000
+)
+
+var gf16Log, gf16Exp = func() ([gf16FieldCount]uint16, [gf16FieldCount]uint16) {
+	var log, exp [gf16FieldCount]uint16
+	b := uint32(1)
+	for l := range gf16Limit {
+		log[b] = uint16(l)
+		exp[l] = uint16(b)
+		b <<= 1
+		if b&gf16FieldCount != 0 {
+			b ^= gf16Generator
+		}
+	}
+	exp[gf16Limit] = exp[0]
+	return log, exp
+}()
+
+func gf16Mul(a, b uint16) uint16 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	sum := uint32(gf16Log[a]) + uint32(gf16Log[b])
+	if sum >= gf16Limit {
+		sum -= gf16Limit
+	}
+	return gf16Exp[sum]
+}
+
+func gf16Div(a, b uint16) uint16 {
+	if a == 0 {
+		return 0
+	}
+	diff := int32(gf16Log[a]) - int32(gf16Log[b])
+	if diff < 0 {
+		diff += gf16Limit
+	}
+	return gf16Exp[diff]
+}
+
+func gf16Pow(a uint16, exponent uint32) uint16 {
+	if exponent == 0 {
+		return 1
+	}
+	if a == 0 {
+		return 0
+	}
+	return gf16Exp[(uint64(gf16Log[a])*uint64(exponent))%gf16Limit]
+}
+
+// gf16InputConstants returns the constants assigned to the first `count` input
+// slices of a recovery set: 2^n for every n coprime with 65535.
+func gf16InputConstants(count int) []uint16 {
+	constants := make([]uint16, count)
+	n := uint32(0)
+	for i := range constants {
+		for n%3 == 0 || n%5 == 0 || n%17 == 0 || n%257 == 0 {
+			n++
+		}
+		constants[i] = gf16Exp[n]
+		n++
+	}
+	return constants
+}
+
+// gf16MulAddSlice does dst ^= factor * src, treating both as little-endian
+// 16-bit words. len(src) must not exceed len(dst) and both must be even.
+func gf16MulAddSlice(dst, src []byte, factor uint16) {
+	switch factor {
+	case 0:
+		return
+	case 1:
+		for i := range src {
+			dst[i] ^= src[i]
+		}
+		return
+	}
+	logFactor := uint32(gf16Log[factor])
+	for i := 0; i+1 < len(src); i += 2 {
+		w := binary.LittleEndian.Uint16(src[i:])
+		if w == 0 {
+			continue
+		}
+		sum := uint32(gf16Log[w]) + logFactor
+		if sum >= gf16Limit {
+			sum -= gf16Limit
+		}
+		binary.LittleEndian.PutUint16(dst[i:], binary.LittleEndian.Uint16(dst[i:])^gf16Exp[sum])
+	}
+}
+
+var errGF16SingularMatrix = errors.New("par2: singular matrix")
+
+// gf16Invert inverts a square matrix in-place using Gauss-Jordan elimination.
+func gf16Invert(matrix [][]uint16) ([][]uint16, error) {
+	size := len(matrix)
+	inverse := make([][]uint16, size)
+	for i := range inverse {
+		inverse[i] = make([]uint16, size)
+		inverse[i][i] = 1
+	}
+
+	for col := range size {
+		pivot := -1
+		for row := col; row < size; row++ {
+			if matrix[row][col] != 0 {
+				pivot = row
+				break
+			}
+		}
+		if pivot == -1 {
+			return nil, errGF16SingularMatrix
+		}
+		matrix[col], matrix[pivot] = matrix[pivot], matrix[col]
+		inverse[col], inverse[pivot] = inverse[pivot], inverse[col]
+
+		if factor := matrix[col][col]; factor != 1 {
+			for j := range size {
+				matrix[col][j] = gf16Div(matrix[col][j], factor)
+				inverse[col][j] = gf16Div(inverse[col][j], factor)
+			}
+		}
+
+		for row := range size {
+			if row == col {
+				continue
+			}
+			factor := matrix[row][col]
+			if factor == 0 {
+				continue
+			}
+			for j := range size {
+				matrix[row][j] ^= gf16Mul(factor, matrix[col][j])
+				inverse[row][j] ^= gf16Mul(factor, inverse[col][j])
+			}
+		}
+	}
+
+	return inverse, nil
+}
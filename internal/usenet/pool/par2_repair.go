@@ -0,0 +1,451 @@
+package usenet_pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+	"github.com/alitto/pond/v2"
+)
+
+var par2Log = logger.Scoped("usenet/pool/par2")
+
+var par2SetCache = cache.NewLRUCache[*par2Set](&cache.CacheConfig{
+	Name:     "newz_par2_set",
+	MaxSize:  64,
+	Lifetime: 3 * time.Hour,
+})
+
+var errPar2InsufficientRecovery = errors.New("par2: not enough recovery slices")
+
+type segmentRepairFunc func(ctx context.Context, segment *nzb.Segment) (*SegmentData, error)
+
+// fetchOrRepairSegment falls back to repair when the article is missing from
+// every provider. The original error is returned if the repair fails too.
+func (p *Pool) fetchOrRepairSegment(ctx context.Context, segment *nzb.Segment, groups []string, repair segmentRepairFunc) (*SegmentData, error) {
+	data, err := p.fetchSegment(ctx, segment, groups)
+	if err == nil || repair == nil || !errors.Is(err, ErrArticleNotFound) {
+		return data, err
+	}
+	repaired, repairErr := repair(ctx, segment)
+	if repairErr != nil {
+		p.Log.Debug("fetch segment - repair failed", "error", repairErr, "segment_num", segment.Number, "message_id", segment.MessageId)
+		return nil, err
+	}
+	return repaired, nil
+}
+
+type par2Repairer struct {
+	pool   *Pool
+	nzb    *nzb.NZB
+	mu     sync.Mutex
+	loaded bool
+	set    *par2Set
+	err    error
+}
+
+// newPar2Repairer returns nil if the nzb does not have any par2 file.
+func newPar2Repairer(pool *Pool, nzbDoc *nzb.NZB) *par2Repairer {
+	if pool == nil || nzbDoc == nil {
+		return nil
+	}
+	for i := range nzbDoc.Files {
+		if IsPar2File(nzbDoc.Files[i].Name()) {
+			return &par2Repairer{pool: pool, nzb: nzbDoc}
+		}
+	}
+	return nil
+}
+
+func (r *par2Repairer) getSet(ctx context.Context) (*par2Set, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.loaded {
+		return r.set, r.err
+	}
+
+	key := r.nzb.HashByFileBoundarySegmentIds()
+	if par2SetCache.Get(key, &r.set) {
+		r.loaded = true
+		return r.set, nil
+	}
+
+	set, err := r.pool.loadPar2Set(ctx, r.nzb)
+	if err != nil && ctx.Err() != nil {
+		return nil, err
+	}
+	r.loaded = true
+	r.set, r.err = set, err
+	if err == nil {
+		par2SetCache.Add(key, set)
+	}
+	return r.set, r.err
+}
+
+func (r *par2Repairer) getNZBFileIdx(file *nzb.File) int {
+	for i := range r.nzb.Files {
+		if &r.nzb.Files[i] == file {
+			return i
+		}
+	}
+	if file.SegmentCount() == 0 {
+		return -1
+	}
+	return slices.IndexFunc(r.nzb.Files, func(f nzb.File) bool {
+		return f.SegmentCount() > 0 && f.Segments[0].MessageId == file.Segments[0].MessageId
+	})
+}
+
+func (r *par2Repairer) repairFunc(file *nzb.File) segmentRepairFunc {
+	if r == nil || IsPar2File(file.Name()) {
+		return nil
+	}
+	fileIdx := r.getNZBFileIdx(file)
+	if fileIdx == -1 {
+		return nil
+	}
+	return func(ctx context.Context, segment *nzb.Segment) (*SegmentData, error) {
+		result, err, _ := r.pool.fetchGroup.Do("par2:"+segment.MessageId, func() (any, error) {
+			return r.repairSegment(ctx, fileIdx, segment)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.(*SegmentData), nil
+	}
+}
+
+// canRepair reports whether the file is covered by a recovery set that has
+// recovery slices available.
+func (r *par2Repairer) canRepair(ctx context.Context, file *nzb.File) bool {
+	if r == nil || IsPar2File(file.Name()) {
+		return false
+	}
+	set, err := r.getSet(ctx)
+	if err != nil || len(set.recovery) == 0 {
+		return false
+	}
+	fileIdx := r.getNZBFileIdx(file)
+	return fileIdx != -1 && set.getFileByNZBFileIdx(fileIdx) != nil
+}
+
+func (r *par2Repairer) repairSegment(ctx context.Context, fileIdx int, segment *nzb.Segment) (*SegmentData, error) {
+	set, err := r.getSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sf := set.getFileByNZBFileIdx(fileIdx)
+	if sf == nil {
+		return nil, errors.New("par2: file not in recovery set")
+	}
+
+	file := &r.nzb.Files[fileIdx]
+	segmentIdx := slices.IndexFunc(file.Segments, func(s nzb.Segment) bool {
+		return s.MessageId == segment.MessageId
+	})
+	if segmentIdx == -1 {
+		return nil, errors.New("par2: segment not found in file")
+	}
+
+	byteRange, err := r.getSegmentByteRange(ctx, file, segmentIdx, sf.length)
+	if err != nil {
+		return nil, err
+	}
+
+	par2Log.Debug("repair segment - start", "filename", file.Name(), "segment_num", segment.Number, "message_id", segment.MessageId, "byte_range", fmt.Sprintf("[%d, %d)", byteRange.Start, byteRange.End))
+
+	w := &par2RepairWorker{
+		ctx:     ctx,
+		pool:    r.pool,
+		nzb:     r.nzb,
+		set:     set,
+		streams: map[int]*FileStream{},
+		errs:    map[int]error{},
+	}
+	defer w.close()
+
+	body := make([]byte, byteRange.Count())
+	for offset := byteRange.Start; offset < byteRange.End; {
+		localSliceIdx := offset / set.sliceSize
+		sliceStart := localSliceIdx * set.sliceSize
+		start := offset - sliceStart
+		end := min(byteRange.End, sliceStart+set.sliceSize) - sliceStart
+
+		// computation is done on 16-bit words
+		alignedStart := start &^ 1
+		data, err := w.repairSliceRange(sf.firstSlice+int(localSliceIdx), alignedStart, (end+1)&^1)
+		if err != nil {
+			return nil, err
+		}
+
+		if alignedStart == 0 && end == set.sliceSize && int(localSliceIdx) < len(sf.checksums) {
+			if crc32.ChecksumIEEE(data) != sf.checksums[localSliceIdx].crc32 {
+				return nil, errors.New("par2: repaired slice failed verification")
+			}
+		}
+
+		copy(body[offset-byteRange.Start:], data[start-alignedStart:end-alignedStart])
+		offset = sliceStart + end
+	}
+
+	segmentData := SegmentData{
+		Body:      body,
+		ByteRange: byteRange,
+		FileSize:  sf.length,
+		Size:      int64(len(body)),
+	}
+
+	par2Log.Info("repair segment - done", "filename", file.Name(), "segment_num", segment.Number, "message_id", segment.MessageId, "size", len(body))
+
+	r.pool.segmentCache.Set(segment.MessageId, segmentData)
+
+	return &segmentData, nil
+}
+
+// getSegmentByteRange figures out the position of a missing segment using its
+// neighbours, falling back to the part size of the yEnc post.
+func (r *par2Repairer) getSegmentByteRange(ctx context.Context, file *nzb.File, segmentIdx int, fileSize int64) (ByteRange, error) {
+	fetch := func(idx int) *SegmentData {
+		if idx < 0 || idx >= file.SegmentCount() {
+			return nil
+		}
+		data, err := r.pool.fetchSegment(ctx, &file.Segments[idx], file.Groups)
+		if err != nil {
+			return nil
+		}
+		return data
+	}
+
+	lastIdx := file.SegmentCount() - 1
+
+	start, end := int64(-1), int64(-1)
+	if segmentIdx == 0 {
+		start = 0
+	} else if prev := fetch(segmentIdx - 1); prev != nil {
+		start = prev.ByteRange.End
+	}
+	if segmentIdx == lastIdx {
+		end = fileSize
+	} else if next := fetch(segmentIdx + 1); next != nil {
+		end = next.ByteRange.Start
+	}
+
+	if start == -1 || end == -1 {
+		partSize := int64(0)
+		for _, idx := range []int{0, 1} {
+			if idx == segmentIdx || idx == lastIdx {
+				continue
+			}
+			if data := fetch(idx); data != nil {
+				partSize = data.ByteRange.Count()
+				break
+			}
+		}
+		if partSize <= 0 {
+			return ByteRange{}, errors.New("par2: failed to determine segment byte range")
+		}
+		if start == -1 {
+			start = int64(segmentIdx) * partSize
+		}
+		if end == -1 {
+			end = min(start+partSize, fileSize)
+		}
+	}
+
+	if start < 0 || end <= start || end > fileSize {
+		return ByteRange{}, fmt.Errorf("par2: invalid segment byte range [%d, %d)", start, end)
+	}
+	return ByteRange{Start: start, End: end}, nil
+}
+
+type par2RepairWorker struct {
+	ctx     context.Context
+	pool    *Pool
+	nzb     *nzb.NZB
+	set     *par2Set
+	mu      sync.Mutex
+	streams map[int]*FileStream
+	errs    map[int]error
+}
+
+func (w *par2RepairWorker) close() {
+	for _, stream := range w.streams {
+		stream.Close()
+	}
+}
+
+// getStream opens the file without repair. For input files, the size is known
+// from the recovery set, so a missing first segment does not fail the open.
+func (w *par2RepairWorker) getStream(nzbFileIdx int, fileSize int64) (*FileStream, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if stream, ok := w.streams[nzbFileIdx]; ok {
+		return stream, nil
+	}
+	if err, ok := w.errs[nzbFileIdx]; ok {
+		return nil, err
+	}
+	file := &w.nzb.Files[nzbFileIdx]
+	if fileSize >= 0 {
+		stream := newFileStreamWithSize(w.ctx, w.pool, file, fileSize, 0, nil)
+		w.streams[nzbFileIdx] = stream
+		return stream, nil
+	}
+	stream, err := newFileStream(w.ctx, w.pool, file, 0, nil)
+	if err != nil {
+		w.errs[nzbFileIdx] = err
+		return nil, err
+	}
+	w.streams[nzbFileIdx] = stream
+	return stream, nil
+}
+
+// readSliceRange reads [start, end) of an input slice, zero padded past the
+// end of the file.
+func (w *par2RepairWorker) readSliceRange(sliceIdx int, start, end int64) ([]byte, error) {
+	fileIdx := sort.Search(len(w.set.files), func(i int) bool {
+		f := &w.set.files[i]
+		return sliceIdx < f.firstSlice+f.sliceCount
+	})
+	sf := &w.set.files[fileIdx]
+	if sf.nzbFileIdx == -1 {
+		return nil, fmt.Errorf("par2: file %s not in nzb", sf.name)
+	}
+
+	buf := make([]byte, end-start)
+	offset := int64(sliceIdx-sf.firstSlice)*w.set.sliceSize + start
+	size := min(end-start, sf.length-offset)
+	if size <= 0 {
+		return buf, nil
+	}
+
+	stream, err := w.getStream(sf.nzbFileIdx, sf.length)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stream.ReadAt(buf[:size], offset); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// readRecoveryRanges reads [start, end) of up to `count` recovery slices,
+// skipping the ones that are not available.
+func (w *par2RepairWorker) readRecoveryRanges(count int, start, end int64) ([]par2RecoverySlice, [][]byte, error) {
+	recovery := make([]par2RecoverySlice, 0, count)
+	data := make([][]byte, 0, count)
+	for _, rs := range w.set.recovery {
+		if len(recovery) == count {
+			break
+		}
+		stream, err := w.getStream(rs.nzbFileIdx, -1)
+		if err == nil {
+			buf := make([]byte, end-start)
+			if _, err = stream.ReadAt(buf, rs.offset+start); err == nil {
+				recovery = append(recovery, rs)
+				data = append(data, buf)
+				continue
+			}
+		}
+		if w.ctx.Err() != nil {
+			return nil, nil, w.ctx.Err()
+		}
+		par2Log.Debug("repair - recovery slice unavailable", "error", err, "exponent", rs.exponent)
+	}
+	return recovery, data, nil
+}
+
+// repairSliceRange reconstructs [start, end) of the target input slice.
+//
+// Every recovery slice is a linear combination of all the input slices, so the
+// same range needs to be read from every other input slice. Input slices that
+// turn out to be missing as well are solved for together with the target.
+func (w *par2RepairWorker) repairSliceRange(target int, start, end int64) ([]byte, error) {
+	set := w.set
+	missing := []int{target}
+	isMissing := map[int]struct{}{target: {}}
+	count := min(len(set.recovery), 4)
+
+	for {
+		if len(missing) > len(set.recovery) {
+			return nil, errPar2InsufficientRecovery
+		}
+		count = max(count, len(missing))
+
+		recovery, syndromes, err := w.readRecoveryRanges(count, start, end)
+		if err != nil {
+			return nil, err
+		}
+		if len(recovery) < len(missing) {
+			return nil, errPar2InsufficientRecovery
+		}
+
+		var mu sync.Mutex
+		fetchPool := pond.NewPool(config.Newz.MaxConnectionPerStream)
+		for sliceIdx := range set.sliceCount {
+			if _, ok := isMissing[sliceIdx]; ok {
+				continue
+			}
+			fetchPool.Submit(func() {
+				data, err := w.readSliceRange(sliceIdx, start, end)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				if err != nil {
+					if w.ctx.Err() == nil {
+						par2Log.Debug("repair - input slice unavailable", "error", err, "slice_idx", sliceIdx)
+					}
+					missing = append(missing, sliceIdx)
+					isMissing[sliceIdx] = struct{}{}
+					return
+				}
+				for i := range recovery {
+					gf16MulAddSlice(syndromes[i], data, gf16Pow(set.constants[sliceIdx], recovery[i].exponent))
+				}
+			})
+		}
+		fetchPool.StopAndWait()
+
+		if err := w.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if len(missing) > len(recovery) {
+			par2Log.Debug("repair - more input slices missing, retrying", "missing_count", len(missing), "recovery_count", len(recovery))
+			continue
+		}
+
+		size := len(missing)
+		matrix := make([][]uint16, size)
+		for i := range size {
+			matrix[i] = make([]uint16, size)
+			for j, sliceIdx := range missing {
+				matrix[i][j] = gf16Pow(set.constants[sliceIdx], recovery[i].exponent)
+			}
+		}
+		inverse, err := gf16Invert(matrix)
+		if err != nil {
+			return nil, err
+		}
+
+		// missing[0] is the target
+		result := make([]byte, end-start)
+		for i := range size {
+			gf16MulAddSlice(result, syndromes[i], inverse[0][i])
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,224 @@
+package usenet_pool
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/nntp/nntptest"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createPar2Packet(setId [16]byte, typ [16]byte, body []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(par2PacketMagic[:])
+	binary.Write(&buf, binary.LittleEndian, uint64(par2PacketHeaderSize+len(body)))
+	hash := md5.Sum(bytes.Join([][]byte{setId[:], typ[:], body}, nil))
+	buf.Write(hash[:])
+	buf.Write(setId[:])
+	buf.Write(typ[:])
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+// createTestPar2 creates a par2 file for a single input file, with
+// `recoveryCount` recovery slices.
+func createTestPar2(filename string, data []byte, sliceSize int64, recoveryCount int) []byte {
+	name := []byte(filename)
+	for len(name)%4 != 0 {
+		name = append(name, 0)
+	}
+
+	hash16k := md5.Sum(data[:min(len(data), 16*1024)])
+	fileId := md5.Sum(bytes.Join([][]byte{hash16k[:], binary.LittleEndian.AppendUint64(nil, uint64(len(data))), []byte(filename)}, nil))
+
+	mainBody := binary.LittleEndian.AppendUint64(nil, uint64(sliceSize))
+	mainBody = binary.LittleEndian.AppendUint32(mainBody, 1)
+	mainBody = append(mainBody, fileId[:]...)
+	setId := md5.Sum(mainBody)
+
+	fileHash := md5.Sum(data)
+	descBody := bytes.Join([][]byte{fileId[:], fileHash[:], hash16k[:], binary.LittleEndian.AppendUint64(nil, uint64(len(data))), name}, nil)
+
+	sliceCount := (int64(len(data)) + sliceSize - 1) / sliceSize
+	slices := make([][]byte, sliceCount)
+	ifscBody := append([]byte{}, fileId[:]...)
+	for i := range slices {
+		slices[i] = make([]byte, sliceSize)
+		copy(slices[i], data[int64(i)*sliceSize:])
+		sliceHash := md5.Sum(slices[i])
+		ifscBody = append(ifscBody, sliceHash[:]...)
+		ifscBody = binary.LittleEndian.AppendUint32(ifscBody, crc32.ChecksumIEEE(slices[i]))
+	}
+
+	var buf bytes.Buffer
+	buf.Write(createPar2Packet(setId, par2PacketTypeMain, mainBody))
+	buf.Write(createPar2Packet(setId, par2PacketTypeFileDesc, descBody))
+	buf.Write(createPar2Packet(setId, par2PacketTypeIFSC, ifscBody))
+
+	constants := gf16InputConstants(len(slices))
+	for exponent := range uint32(recoveryCount) {
+		recovery := make([]byte, sliceSize)
+		for i := range slices {
+			gf16MulAddSlice(recovery, slices[i], gf16Pow(constants[i], exponent))
+		}
+		body := binary.LittleEndian.AppendUint32(nil, exponent)
+		buf.Write(createPar2Packet(setId, par2PacketTypeRecvSlic, append(body, recovery...)))
+	}
+
+	return buf.Bytes()
+}
+
+func TestGF16(t *testing.T) {
+	assert.Equal(t, []uint16{2, 4, 16, 128, 256}, gf16InputConstants(5))
+
+	for _, a := range []uint16{1, 2, 3, 0x1234, 0xFFFF} {
+		for _, b := range []uint16{1, 7, 0x8000, 0xFFFF} {
+			assert.Equal(t, a, gf16Div(gf16Mul(a, b), b))
+		}
+	}
+
+	assert.Equal(t, gf16Mul(gf16Mul(3, 3), 3), gf16Pow(3, 3))
+
+	matrix := [][]uint16{{1, 2}, {3, 4}}
+	inverse, err := gf16Invert([][]uint16{{1, 2}, {3, 4}})
+	require.NoError(t, err)
+	for i := range 2 {
+		for j := range 2 {
+			var v uint16
+			for k := range 2 {
+				v ^= gf16Mul(matrix[i][k], inverse[k][j])
+			}
+			if i == j {
+				assert.Equal(t, uint16(1), v)
+			} else {
+				assert.Equal(t, uint16(0), v)
+			}
+		}
+	}
+}
+
+func TestPar2Repair(t *testing.T) {
+	const segmentSize = 100
+	const segmentCount = 4
+	const sliceSize = 128
+
+	setup := func(t *testing.T, recoveryCount int, droppedSegments ...int) (*nntptest.Server, *nzb.NZB, []byte) {
+		t.Helper()
+
+		data := make([]byte, segmentSize*segmentCount)
+		for i := range data {
+			data[i] = byte(i*7 + 3)
+		}
+
+		server := nntptest.NewServer(t, "200 NNTP Service Ready")
+		server.SetResponse("GROUP alt.binaries.test", "211 1 1 1 alt.binaries.test")
+
+		segments := make([]nzb.Segment, segmentCount)
+		for i := range segmentCount {
+			messageId := fmt.Sprintf("msg%d@test", i+1)
+			segments[i] = nzb.Segment{MessageId: messageId, Bytes: segmentSize, Number: i + 1}
+			body := data[i*segmentSize : (i+1)*segmentSize]
+			encoded := encodeYenc(body, "video.mkv", i+1, segmentCount, int64(len(data)), int64(i*segmentSize)+1)
+			server.SetResponse("BODY <"+messageId+">", "222 0 <"+messageId+">", []string{string(encoded)})
+		}
+		for _, i := range droppedSegments {
+			server.SetResponse("BODY <"+segments[i].MessageId+">", "430 No Such Article")
+		}
+
+		par2Data := createTestPar2("video.mkv", data, sliceSize, recoveryCount)
+		par2Encoded := encodeYenc(par2Data, "video.par2", 1, 1, int64(len(par2Data)), 1)
+		server.SetResponse("BODY <par2@test>", "222 0 <par2@test>", []string{string(par2Encoded)})
+
+		server.Start(t)
+
+		nzbDoc := createTestNZB(
+			nzb.File{
+				Subject:  `Test - "video.mkv" yEnc (1/4)`,
+				Segments: segments,
+			},
+			nzb.File{
+				Subject: `Test - "video.par2" yEnc (1/1)`,
+				Segments: []nzb.Segment{
+					{MessageId: "par2@test", Bytes: int64(len(par2Data)), Number: 1},
+				},
+			},
+		)
+
+		return server, nzbDoc, data
+	}
+
+	t.Run("RepairsMissingSegment", func(t *testing.T) {
+		server, nzbDoc, data := setup(t, 2, 1)
+		pool := createTestPool(t, server)
+
+		ufs := NewUsenetFS(t.Context(), &UsenetFSConfig{
+			NZB:  nzbDoc,
+			Pool: pool,
+		})
+		defer ufs.Close()
+
+		f, err := ufs.Open("video.mkv")
+		require.NoError(t, err)
+
+		got, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("RepairsMissingFirstSegment", func(t *testing.T) {
+		server, nzbDoc, data := setup(t, 1, 0)
+		pool := createTestPool(t, server)
+
+		ufs := NewUsenetFS(t.Context(), &UsenetFSConfig{
+			NZB:  nzbDoc,
+			Pool: pool,
+		})
+		defer ufs.Close()
+
+		f, err := ufs.Open("video.mkv")
+		require.NoError(t, err)
+
+		got, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("NotEnoughRecoverySlices", func(t *testing.T) {
+		server, nzbDoc, _ := setup(t, 1, 1, 2)
+		pool := createTestPool(t, server)
+
+		ufs := NewUsenetFS(t.Context(), &UsenetFSConfig{
+			NZB:  nzbDoc,
+			Pool: pool,
+		})
+		defer ufs.Close()
+
+		f, err := ufs.Open("video.mkv")
+		require.NoError(t, err)
+
+		_, err = io.ReadAll(f)
+		assert.ErrorIs(t, err, ErrArticleNotFound)
+	})
+
+	t.Run("InspectMarksRepairableFileStreamable", func(t *testing.T) {
+		server, nzbDoc, _ := setup(t, 1, segmentCount-1)
+		pool := createTestPool(t, server)
+
+		content, err := pool.InspectNZBContent(t.Context(), nzbDoc, "")
+		require.NoError(t, err)
+		assert.True(t, content.Streamable)
+		for _, f := range content.Files {
+			if f.Name == "video.mkv" {
+				assert.True(t, f.Streamable)
+				assert.Contains(t, f.Errors, NZBContentFileErrorArticleNotFound)
+			}
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/metrics"
 )
 
 type SegmentData struct {
@@ -46,6 +47,7 @@ func NewSegmentCache(size int64) SegmentCache {
 func (c *segmentCache) Get(messageId string) (SegmentData, bool) {
 	var data SegmentData
 	ok := c.cache.Get(messageId, &data)
+	metrics.RecordSegmentCacheLookup(ok)
 	return data, ok
 }
 
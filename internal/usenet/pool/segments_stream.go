@@ -32,6 +32,7 @@ type SegmentsStream struct {
 	segments []nzb.Segment
 	groups   []string
 	pool     *Pool
+	repair   segmentRepairFunc
 
 	ctx      context.Context
 	cancel   context.CancelFunc
@@ -55,6 +56,17 @@ func NewSegmentsStream(
 	segments []nzb.Segment,
 	groups []string,
 	bufferSize int64,
+) *SegmentsStream {
+	return newSegmentsStream(ctx, pool, segments, groups, bufferSize, nil)
+}
+
+func newSegmentsStream(
+	ctx context.Context,
+	pool *Pool,
+	segments []nzb.Segment,
+	groups []string,
+	bufferSize int64,
+	repair segmentRepairFunc,
 ) *SegmentsStream {
 	ctx, cancel := context.WithCancel(ctx)
 
@@ -64,6 +76,7 @@ func NewSegmentsStream(
 		segments:    segments,
 		groups:      groups,
 		pool:        pool,
+		repair:      repair,
 		ctx:         ctx,
 		cancel:      cancel,
 		dataChan:    make(chan *SegmentData, workerCount*2),
@@ -147,7 +160,7 @@ func (s *SegmentsStream) startFetcher(segmentChan <-chan segmentWithIdx, resultC
 		default:
 		}
 
-		data, err := s.pool.fetchSegment(s.ctx, segmentWithIdx.Segment, s.groups)
+		data, err := s.pool.fetchOrRepairSegment(s.ctx, segmentWithIdx.Segment, s.groups, s.repair)
 		if data != nil {
 			if adjustment := segmentWithIdx.Bytes - data.Size; adjustment != 0 {
 				s.bufferSizeRemaining.Add(adjustment)
@@ -166,7 +179,7 @@ func (s *SegmentsStream) startFetcher(segmentChan <-chan segmentWithIdx, resultC
 func (s *SegmentsStream) startSegmentResultCollector(resultCh <-chan segmentResult) {
 	defer close(s.dataChan)
 
-	pending := make(map[int]*SegmentData)
+	pending := make(map[int]segmentResult)
 	nextIdx := 0
 	totalSegments := len(s.segments)
 	receivedCount := 0
@@ -182,29 +195,30 @@ func (s *SegmentsStream) startSegmentResultCollector(resultCh <-chan segmentResu
 
 			receivedCount++
 
-			if result.err != nil {
-				segmentLog.Trace("segments stream - failed result", "error", result.err, "idx", result.idx)
-				select {
-				case s.errChan <- result.err:
-				default:
-				}
-				return
-			}
-
 			segmentLog.Trace("segments stream - received result", "idx", result.idx, "next_expected_idx", nextIdx, "pending_count", len(pending))
 
-			pending[result.idx] = result.data
+			pending[result.idx] = result
 
 			for {
-				data, ok := pending[nextIdx]
+				result, ok := pending[nextIdx]
 				if !ok {
 					break
 				}
 				delete(pending, nextIdx)
 
+				// errors are surfaced in order, after the data preceding it is read
+				if result.err != nil {
+					segmentLog.Trace("segments stream - failed result", "error", result.err, "idx", result.idx)
+					select {
+					case s.errChan <- result.err:
+					default:
+					}
+					return
+				}
+
 				select {
-				case s.dataChan <- data:
-					segmentLog.Trace("segments stream - sent segment", "idx", nextIdx, "size", len(data.Body))
+				case s.dataChan <- result.data:
+					segmentLog.Trace("segments stream - sent segment", "idx", nextIdx, "size", len(result.data.Body))
 					nextIdx++
 				case <-s.ctx.Done():
 					return
@@ -223,12 +237,6 @@ func (s *SegmentsStream) Read(p []byte) (n int, err error) {
 	}
 
 	for n < len(p) {
-		select {
-		case err := <-s.errChan:
-			return n, err
-		default:
-		}
-
 		if s.currPos < len(s.currData) {
 			copied := copy(p[n:], s.currData[s.currPos:])
 			s.currPos += copied
@@ -240,6 +248,11 @@ func (s *SegmentsStream) Read(p []byte) (n int, err error) {
 
 		data, ok := <-s.dataChan
 		if !ok {
+			select {
+			case err := <-s.errChan:
+				return n, err
+			default:
+			}
 			segmentLog.Trace("segments stream - no more segments", "segment_count", len(s.segments))
 			if n > 0 {
 				return n, nil
@@ -51,7 +51,9 @@ func (p *Pool) streamFile(
 
 	p.Log.Trace("found file", "idx", fileIdx, "name", file.Name(), "segment_count", file.SegmentCount())
 
-	firstSegment, err := p.fetchFirstSegment(ctx, file)
+	repairer := newPar2Repairer(p, nzbDoc)
+
+	firstSegment, err := p.fetchOrRepairSegment(ctx, &file.Segments[0], file.Groups, repairer.repairFunc(file))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch file header: %w", err)
 	}
@@ -64,7 +66,7 @@ func (p *Pool) streamFile(
 
 	switch fileType {
 	case FileTypePlain:
-		return p.streamPlainFile(file, config)
+		return p.streamPlainFile(file, repairer, config)
 	case FileTypeRAR:
 		return p.streamRARFile(ctx, nzbDoc, config)
 	case FileType7z:
@@ -93,17 +95,19 @@ func (p *Pool) fetchFirstSegment(
 
 func (p *Pool) streamPlainFile(
 	file *nzb.File,
+	repairer *par2Repairer,
 	config *StreamConfig,
 ) (*Stream, error) {
 	filename := file.Name()
 
 	p.Log.Trace("creating stream", "stream_type", "plain", "filename", filename, "segment_count", file.SegmentCount())
 
-	stream, err := NewFileStream(
+	stream, err := newFileStream(
 		context.Background(),
 		p,
 		file,
 		config.SegmentBufferSize,
+		repairer.repairFunc(file),
 	)
 	if err != nil {
 		return nil, err
@@ -503,8 +507,10 @@ func (p *Pool) StreamByContentPath(
 		return nil, fmt.Errorf("no file matching '%s' found", name)
 	}
 
+	repairer := newPar2Repairer(p, nzbDoc)
+
 	if len(pathParts) == 1 {
-		return p.streamPlainFile(file, config)
+		return p.streamPlainFile(file, repairer, config)
 	}
 
 	archiveName := contentFile.Name
@@ -513,7 +519,7 @@ func (p *Pool) StreamByContentPath(
 	}
 	archiveFile := file
 
-	firstSegment, err := p.fetchFirstSegment(ctx, archiveFile)
+	firstSegment, err := p.fetchOrRepairSegment(ctx, &archiveFile.Segments[0], archiveFile.Groups, repairer.repairFunc(archiveFile))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch archive header: %w", err)
 	}
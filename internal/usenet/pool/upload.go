@@ -0,0 +1,278 @@
+package usenet_pool
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/nntp"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+	"github.com/alitto/pond/v2"
+)
+
+var uploadLog = logger.Scoped("usenet/pool/upload")
+
+var ErrUploadMissingGroups = errors.New("usenet: missing groups for upload")
+
+type UploadConfig struct {
+	Groups      []string
+	Poster      string // default: StremThru <stremthru@stremthru.local>
+	Domain      string // for Message-ID, default: stremthru.local
+	SegmentSize int64  // default: 700KiB
+	Concurrency int    // default: 4
+	MaxAttempts int    // per segment, default: 3
+}
+
+func (conf *UploadConfig) setDefaults() {
+	if conf.Poster == "" {
+		conf.Poster = "StremThru <stremthru@stremthru.local>"
+	}
+	if conf.Domain == "" {
+		conf.Domain = "stremthru.local"
+	}
+	if conf.SegmentSize <= 0 {
+		conf.SegmentSize = 700 * 1024
+	}
+	if conf.Concurrency <= 0 {
+		conf.Concurrency = 4
+	}
+	if conf.MaxAttempts <= 0 {
+		conf.MaxAttempts = 3
+	}
+}
+
+type UploadFile struct {
+	Name   string
+	Size   int64
+	Reader io.ReaderAt
+}
+
+type uploadSegment struct {
+	file       *UploadFile
+	fileIdx    int
+	fileCount  int
+	number     int
+	totalParts int
+	offset     int64
+	size       int64
+}
+
+func (s *uploadSegment) subject() string {
+	return fmt.Sprintf(`[%d/%d] - "%s" yEnc (%d/%d)`, s.fileIdx+1, s.fileCount, s.file.Name, s.number, s.totalParts)
+}
+
+func (s *uploadSegment) encode() ([]byte, error) {
+	data := make([]byte, s.size)
+	if _, err := s.file.Reader.ReadAt(data, s.offset); err != nil && !(errors.Is(err, io.EOF) && s.offset+s.size == s.file.Size) {
+		return nil, err
+	}
+
+	header := &YEncHeader{}
+	header.FileName = s.file.Name
+	header.FileSize = s.file.Size
+	header.PartNumber = int64(s.number)
+	header.TotalParts = int64(s.totalParts)
+	header.Offset = s.offset
+	header.PartSize = s.size
+
+	var body bytes.Buffer
+	encoder, err := NewYEncEncoder(&body, header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := encoder.Write(data); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// Upload splits the files into segments, posts them across the providers and
+// returns the nzb for the posted articles.
+func (p *Pool) Upload(ctx context.Context, files []UploadFile, conf *UploadConfig) (*nzb.NZB, error) {
+	conf.setDefaults()
+
+	if len(conf.Groups) == 0 {
+		return nil, ErrUploadMissingGroups
+	}
+
+	date := time.Now()
+
+	nzbDoc := &nzb.NZB{
+		Files: make([]nzb.File, len(files)),
+	}
+	segments := []uploadSegment{}
+	for i := range files {
+		file := &files[i]
+		if file.Size <= 0 {
+			return nil, fmt.Errorf("usenet: cannot upload empty file: %s", file.Name)
+		}
+
+		totalParts := int((file.Size + conf.SegmentSize - 1) / conf.SegmentSize)
+		for part := range totalParts {
+			offset := int64(part) * conf.SegmentSize
+			segments = append(segments, uploadSegment{
+				file:       file,
+				fileIdx:    i,
+				fileCount:  len(files),
+				number:     part + 1,
+				totalParts: totalParts,
+				offset:     offset,
+				size:       min(conf.SegmentSize, file.Size-offset),
+			})
+		}
+
+		nzbDoc.Files[i] = nzb.File{
+			Poster:   conf.Poster,
+			Date:     date.Unix(),
+			Subject:  (&uploadSegment{file: file, fileIdx: i, fileCount: len(files), number: 1, totalParts: totalParts}).subject(),
+			Groups:   conf.Groups,
+			Segments: make([]nzb.Segment, totalParts),
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var uploadErr error
+
+	uploadPool := pond.NewPool(conf.Concurrency)
+	for i := range segments {
+		segment := &segments[i]
+		uploadPool.Submit(func() {
+			if ctx.Err() != nil {
+				return
+			}
+
+			messageId, size, err := p.uploadSegment(ctx, segment, date, conf)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				if uploadErr == nil {
+					uploadErr = err
+					cancel()
+				}
+				return
+			}
+			nzbDoc.Files[segment.fileIdx].Segments[segment.number-1] = nzb.Segment{
+				Bytes:     size,
+				Number:    segment.number,
+				MessageId: messageId,
+			}
+		})
+	}
+	uploadPool.StopAndWait()
+
+	if uploadErr != nil {
+		return nil, uploadErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	nzbDoc.ParseFileSubject()
+
+	return nzbDoc, nil
+}
+
+func (p *Pool) uploadSegment(ctx context.Context, segment *uploadSegment, date time.Time, conf *UploadConfig) (messageId string, size int64, err error) {
+	body, err := segment.encode()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to encode segment %d of %s: %w", segment.number, segment.file.Name, err)
+	}
+
+	messageId = rand.Text() + "@" + conf.Domain
+
+	headers := textproto.MIMEHeader{}
+	headers.Set("From", conf.Poster)
+	headers.Set("Newsgroups", strings.Join(conf.Groups, ","))
+	headers.Set("Subject", segment.subject())
+	headers.Set("Message-ID", "<"+messageId+">")
+	headers.Set("Date", date.UTC().Format(time.RFC1123Z))
+
+	if err := p.postArticle(ctx, headers, body, conf.MaxAttempts); err != nil {
+		return "", 0, fmt.Errorf("failed to post segment %d of %s: %w", segment.number, segment.file.Name, err)
+	}
+
+	uploadLog.Trace("upload segment - posted", "filename", segment.file.Name, "segment_num", segment.number, "message_id", messageId, "size", len(body))
+
+	return messageId, int64(len(body)), nil
+}
+
+func isPostingFailedError(err error) bool {
+	var nntpErr *nntp.Error
+	if errors.As(err, &nntpErr) {
+		return nntpErr.Code == nntp.ErrorCodePostingFailed
+	}
+	return false
+}
+
+// postArticle retries with a different provider if the posting is rejected,
+// and with the backup providers once all the primary providers are excluded.
+func (p *Pool) postArticle(ctx context.Context, headers textproto.MIMEHeader, body []byte, maxAttempts int) error {
+	var excludeProviders []string
+	errs := []error{}
+	useBackup := false
+	failedAttempts := 0
+
+	for failedAttempts < maxAttempts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		conn, err := p.GetConnection(ctx, excludeProviders, math.MaxInt, useBackup)
+		if err != nil {
+			if errors.Is(err, ErrNoProvidersAvailable) {
+				if !useBackup {
+					useBackup = true
+					continue
+				}
+				if len(excludeProviders) > 0 {
+					excludeProviders = nil
+					useBackup = false
+					continue
+				}
+			}
+			errs = append(errs, err)
+			if errors.Is(err, ErrNoProvidersAvailable) || errors.Is(err, ErrNoProvidersConfigured) {
+				break
+			}
+			failedAttempts++
+			continue
+		}
+
+		err = conn.Post(headers, bytes.NewReader(body))
+		if err == nil {
+			conn.Release()
+			return nil
+		}
+
+		errs = append(errs, err)
+		failedAttempts++
+		if isPostingFailedError(err) {
+			conn.Release()
+			excludeProviders = append(excludeProviders, conn.ProviderId())
+			uploadLog.Debug("post article - rejected", "error", err, "message_id", headers.Get("Message-ID"), "provider_id", conn.ProviderId())
+			continue
+		}
+
+		conn.Destroy()
+		uploadLog.Warn("post article - failed", "error", err, "message_id", headers.Get("Message-ID"), "provider_id", conn.ProviderId())
+	}
+
+	return errors.Join(errs...)
+}
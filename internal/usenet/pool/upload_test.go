@@ -0,0 +1,143 @@
+package usenet_pool
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/nntp"
+	"github.com/MunifTanjim/stremthru/internal/nntp/nntptest"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpload(t *testing.T) {
+	fetchAll := func(t *testing.T, pool *Pool, file *nzb.File) []byte {
+		t.Helper()
+
+		var data []byte
+		for i := range file.Segments {
+			segment, err := pool.fetchSegment(t.Context(), &file.Segments[i], file.Groups)
+			require.NoError(t, err)
+			data = append(data, segment.Body...)
+		}
+		return data
+	}
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		server := nntptest.NewServer(t, "200 NNTP Service Ready")
+		server.SetResponse("GROUP alt.binaries.test", "211 0 0 0 alt.binaries.test")
+		server.Start(t)
+
+		pool := createTestPool(t, server)
+
+		video := makeTestBytes(2500)
+		subtitle := []byte("1\n00:00:01,000 --> 00:00:02,000\n.hello\n")
+
+		nzbDoc, err := pool.Upload(t.Context(), []UploadFile{
+			{Name: "movie.mkv", Size: int64(len(video)), Reader: bytes.NewReader(video)},
+			{Name: "movie.srt", Size: int64(len(subtitle)), Reader: bytes.NewReader(subtitle)},
+		}, &UploadConfig{
+			Groups:      []string{"alt.binaries.test"},
+			SegmentSize: 1000,
+		})
+		require.NoError(t, err)
+
+		assert.Len(t, server.GetArticles(), 4)
+
+		blob, err := nzbDoc.Bytes()
+		require.NoError(t, err)
+		parsed, err := nzb.ParseBytes(blob)
+		require.NoError(t, err)
+
+		require.Equal(t, 2, parsed.FileCount())
+		assert.Equal(t, "movie.mkv", parsed.Files[0].Name())
+		assert.Equal(t, "movie.srt", parsed.Files[1].Name())
+		assert.Equal(t, 3, parsed.Files[0].SegmentCount())
+		assert.Equal(t, 1, parsed.Files[1].SegmentCount())
+		assert.Equal(t, []string{"alt.binaries.test"}, parsed.Files[0].Groups)
+
+		assert.Equal(t, video, fetchAll(t, pool, &parsed.Files[0]))
+		assert.Equal(t, subtitle, fetchAll(t, pool, &parsed.Files[1]))
+	})
+
+	t.Run("RetryWithOtherProvider", func(t *testing.T) {
+		rejectingServer := nntptest.NewServer(t, "200 NNTP Service Ready")
+		rejectingServer.SetArticleResponse("POST", "441 Posting failed")
+		rejectingServer.SetResponse("GROUP alt.binaries.test", "211 0 0 0 alt.binaries.test")
+		rejectingServer.SetResponse("BODY *", "430 No such article")
+		rejectingServer.Start(t)
+
+		server := nntptest.NewServer(t, "200 NNTP Service Ready")
+		server.SetResponse("GROUP alt.binaries.test", "211 0 0 0 alt.binaries.test")
+		server.Start(t)
+
+		pool, err := NewPool(&Config{
+			Providers: []ProviderConfig{
+				{
+					PoolConfig: nntp.PoolConfig{
+						ConnectionConfig: nntp.ConnectionConfig{
+							Host: rejectingServer.Host(),
+							Port: rejectingServer.Port(),
+						},
+					},
+					Priority: 0,
+				},
+				{
+					PoolConfig: nntp.PoolConfig{
+						ConnectionConfig: nntp.ConnectionConfig{
+							Host: server.Host(),
+							Port: server.Port(),
+						},
+					},
+					Priority: 1,
+				},
+			},
+		})
+		require.NoError(t, err)
+		t.Cleanup(pool.Close)
+
+		data := makeTestBytes(1500)
+		nzbDoc, err := pool.Upload(t.Context(), []UploadFile{
+			{Name: "movie.mkv", Size: int64(len(data)), Reader: bytes.NewReader(data)},
+		}, &UploadConfig{
+			Groups:      []string{"alt.binaries.test"},
+			SegmentSize: 1000,
+			Concurrency: 1,
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, rejectingServer.GetArticles())
+		assert.Len(t, server.GetArticles(), 2)
+		assert.Equal(t, data, fetchAll(t, pool, &nzbDoc.Files[0]))
+	})
+
+	t.Run("AllProvidersReject", func(t *testing.T) {
+		server := nntptest.NewServer(t, "200 NNTP Service Ready")
+		server.SetResponse("POST", "440 Posting not permitted")
+		server.Start(t)
+
+		pool := createTestPool(t, server)
+
+		data := makeTestBytes(100)
+		_, err := pool.Upload(t.Context(), []UploadFile{
+			{Name: "movie.mkv", Size: int64(len(data)), Reader: bytes.NewReader(data)},
+		}, &UploadConfig{
+			Groups: []string{"alt.binaries.test"},
+		})
+		assert.Error(t, err)
+		assert.True(t, isPostingFailedError(err))
+	})
+
+	t.Run("MissingGroups", func(t *testing.T) {
+		server := nntptest.NewServer(t, "200 NNTP Service Ready")
+		server.Start(t)
+
+		pool := createTestPool(t, server)
+
+		_, err := pool.Upload(t.Context(), []UploadFile{
+			{Name: "movie.mkv", Size: 1, Reader: bytes.NewReader([]byte{0})},
+		}, &UploadConfig{})
+		assert.ErrorIs(t, err, ErrUploadMissingGroups)
+	})
+}
@@ -44,6 +44,7 @@ type UsenetFS struct {
 	aliases           map[string]string // alias name → real filename
 	segmentBufferSize int64
 	openFiles         []*UsenetFile
+	repairer          *par2Repairer
 }
 
 func (ufs *UsenetFS) SetAliases(aliases map[string]string) {
@@ -74,6 +75,7 @@ func NewUsenetFS(ctx context.Context, conf *UsenetFSConfig) *UsenetFS {
 		nzb:               conf.NZB,
 		files:             make(map[string]UsenetFileInfo, conf.NZB.FileCount()),
 		segmentBufferSize: conf.SegmentBufferSize,
+		repairer:          newPar2Repairer(conf.Pool, conf.NZB),
 	}
 	for i := range conf.NZB.Files {
 		f := &conf.NZB.Files[i]
@@ -96,7 +98,7 @@ func (ufs *UsenetFS) Open(name string) (fs.File, error) {
 		}
 	}
 
-	stream, err := NewFileStream(ufs.ctx, ufs.pool, fi.f, ufs.segmentBufferSize)
+	stream, err := newFileStream(ufs.ctx, ufs.pool, fi.f, ufs.segmentBufferSize, ufs.repairer.repairFunc(fi.f))
 	if err != nil {
 		return nil, err
 	}
@@ -123,7 +125,7 @@ func (ufs *UsenetFS) Stat(name string) (os.FileInfo, error) {
 		}
 	}
 
-	firstSegment, err := ufs.pool.fetchFirstSegment(ufs.ctx, fi.f)
+	firstSegment, err := ufs.pool.fetchOrRepairSegment(ufs.ctx, &fi.f.Segments[0], fi.f.Groups, ufs.repairer.repairFunc(fi.f))
 	if err != nil {
 		return nil, err
 	}
@@ -147,3 +147,29 @@ func (d *YEncDecoder) ReadAll() (*YEncDecodedData, error) {
 		body:   body,
 	}, nil
 }
+
+var (
+	_ io.WriteCloser = (*YEncEncoder)(nil)
+)
+
+type YEncEncoder struct {
+	encoder *rapidyenc.Encoder
+}
+
+// NewYEncEncoder writes the `=ybegin`/`=ypart` lines before the first write,
+// and the `=yend` line on close.
+func NewYEncEncoder(w io.Writer, header *YEncHeader) (*YEncEncoder, error) {
+	encoder, err := rapidyenc.NewEncoder(w, header.Meta)
+	if err != nil {
+		return nil, err
+	}
+	return &YEncEncoder{encoder: encoder}, nil
+}
+
+func (e *YEncEncoder) Write(p []byte) (n int, err error) {
+	return e.encoder.Write(p)
+}
+
+func (e *YEncEncoder) Close() error {
+	return e.encoder.Close()
+}
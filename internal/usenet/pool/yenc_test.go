@@ -129,4 +129,48 @@ func TestYencDecoder(t *testing.T) {
 		assert.Equal(t, 0, n)
 		assert.Equal(t, io.EOF, err)
 	})
+
+	t.Run("EncoderRoundTrip", func(t *testing.T) {
+		data := makeTestBytes(1000)
+
+		header := &YEncHeader{}
+		header.FileName = "test.bin"
+		header.FileSize = 3000
+		header.PartNumber = 2
+		header.TotalParts = 3
+		header.Offset = 1000
+		header.PartSize = int64(len(data))
+
+		var buf bytes.Buffer
+		encoder, err := NewYEncEncoder(&buf, header)
+		require.NoError(t, err)
+		_, err = encoder.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, encoder.Close())
+
+		decoded, err := NewYEncDecoder(bytes.NewReader(buf.Bytes())).ReadAll()
+		require.NoError(t, err)
+
+		segment := decoded.ToSegmentData()
+		assert.Equal(t, data, segment.Body)
+		assert.Equal(t, int64(3000), segment.FileSize)
+		assert.Equal(t, int64(1000), segment.ByteRange.Start)
+		assert.Equal(t, int64(2000), segment.ByteRange.End)
+	})
+
+	t.Run("EncoderSizeMismatch", func(t *testing.T) {
+		header := &YEncHeader{}
+		header.FileName = "test.bin"
+		header.FileSize = 100
+		header.PartNumber = 1
+		header.TotalParts = 1
+		header.PartSize = 100
+
+		var buf bytes.Buffer
+		encoder, err := NewYEncEncoder(&buf, header)
+		require.NoError(t, err)
+		_, err = encoder.Write(makeTestBytes(50))
+		require.NoError(t, err)
+		assert.Error(t, encoder.Close())
+	})
 }
@@ -40,6 +40,7 @@ type UsenetServer struct {
 	Priority       int
 	IsBackup       bool
 	MaxConnections int
+	Compression    bool
 	Disabled       bool
 	CAt            db.Timestamp
 	UAt            db.Timestamp
@@ -49,7 +50,7 @@ func (s *UsenetServer) ProviderId() string {
 	return s.Host + ":" + util.IntToString(s.Port) + ":" + s.Username
 }
 
-func NewUsenetServer(name, host string, port int, username, password string, tls, tlsSkipVerify bool, priority int, isBackup bool, maxConnections int) (*UsenetServer, error) {
+func NewUsenetServer(name, host string, port int, username, password string, tls, tlsSkipVerify bool, priority int, isBackup bool, maxConnections int, compression bool) (*UsenetServer, error) {
 	server := &UsenetServer{
 		Id:             xid.New().String(),
 		Name:           name,
@@ -61,6 +62,7 @@ func NewUsenetServer(name, host string, port int, username, password string, tls
 		Priority:       priority,
 		IsBackup:       isBackup,
 		MaxConnections: maxConnections,
+		Compression:    compression,
 	}
 	err := server.SetPassword(password)
 	if err != nil {
@@ -94,6 +96,7 @@ var Column = struct {
 	Priority       string
 	IsBackup       string
 	MaxConnections string
+	Compression    string
 	Disabled       string
 	CAt            string
 	UAt            string
@@ -109,6 +112,7 @@ var Column = struct {
 	Priority:       "priority",
 	IsBackup:       "is_backup",
 	MaxConnections: "max_conn",
+	Compression:    "compression",
 	Disabled:       "disabled",
 	CAt:            "cat",
 	UAt:            "uat",
@@ -126,6 +130,7 @@ var columns = []string{
 	Column.Priority,
 	Column.IsBackup,
 	Column.MaxConnections,
+	Column.Compression,
 	Column.Disabled,
 	Column.CAt,
 	Column.UAt,
@@ -148,6 +153,7 @@ var query_upsert = fmt.Sprintf(
 		fmt.Sprintf(`%s = EXCLUDED.%s`, Column.Priority, Column.Priority),
 		fmt.Sprintf(`%s = EXCLUDED.%s`, Column.IsBackup, Column.IsBackup),
 		fmt.Sprintf(`%s = EXCLUDED.%s`, Column.MaxConnections, Column.MaxConnections),
+		fmt.Sprintf(`%s = EXCLUDED.%s`, Column.Compression, Column.Compression),
 		fmt.Sprintf(`%s = EXCLUDED.%s`, Column.Disabled, Column.Disabled),
 		fmt.Sprintf(`%s = %s`, Column.UAt, db.CurrentTimestamp),
 	}, ", "),
@@ -166,6 +172,7 @@ func (s *UsenetServer) Upsert() error {
 		s.Priority,
 		s.IsBackup,
 		s.MaxConnections,
+		s.Compression,
 		s.Disabled,
 	)
 	return err
@@ -189,7 +196,7 @@ func GetAll() ([]UsenetServer, error) {
 	items := []UsenetServer{}
 	for rows.Next() {
 		item := UsenetServer{}
-		if err := rows.Scan(&item.Id, &item.Name, &item.Host, &item.Port, &item.Username, &item.Password, &item.TLS, &item.TLSSkipVerify, &item.Priority, &item.IsBackup, &item.MaxConnections, &item.Disabled, &item.CAt, &item.UAt); err != nil {
+		if err := rows.Scan(&item.Id, &item.Name, &item.Host, &item.Port, &item.Username, &item.Password, &item.TLS, &item.TLSSkipVerify, &item.Priority, &item.IsBackup, &item.MaxConnections, &item.Compression, &item.Disabled, &item.CAt, &item.UAt); err != nil {
 			return nil, err
 		}
 		items = append(items, item)
@@ -216,7 +223,7 @@ func GetAllEnabled() ([]UsenetServer, error) {
 	items := []UsenetServer{}
 	for rows.Next() {
 		item := UsenetServer{}
-		if err := rows.Scan(&item.Id, &item.Name, &item.Host, &item.Port, &item.Username, &item.Password, &item.TLS, &item.TLSSkipVerify, &item.Priority, &item.IsBackup, &item.MaxConnections, &item.Disabled, &item.CAt, &item.UAt); err != nil {
+		if err := rows.Scan(&item.Id, &item.Name, &item.Host, &item.Port, &item.Username, &item.Password, &item.TLS, &item.TLSSkipVerify, &item.Priority, &item.IsBackup, &item.MaxConnections, &item.Compression, &item.Disabled, &item.CAt, &item.UAt); err != nil {
 			return nil, err
 		}
 		items = append(items, item)
@@ -235,7 +242,7 @@ func GetById(id string) (*UsenetServer, error) {
 	row := db.QueryRow(query_get_by_id, id)
 
 	item := UsenetServer{}
-	if err := row.Scan(&item.Id, &item.Name, &item.Host, &item.Port, &item.Username, &item.Password, &item.TLS, &item.TLSSkipVerify, &item.Priority, &item.IsBackup, &item.MaxConnections, &item.Disabled, &item.CAt, &item.UAt); err != nil {
+	if err := row.Scan(&item.Id, &item.Name, &item.Host, &item.Port, &item.Username, &item.Password, &item.TLS, &item.TLSSkipVerify, &item.Priority, &item.IsBackup, &item.MaxConnections, &item.Compression, &item.Disabled, &item.CAt, &item.UAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -0,0 +1,313 @@
+package webdav
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	xwebdav "golang.org/x/net/webdav"
+)
+
+var log = logger.Scoped("webdav")
+
+var (
+	_ xwebdav.FileSystem   = (*FileSystem)(nil)
+	_ xwebdav.File         = (*file)(nil)
+	_ xwebdav.ContentTyper = (*fileInfo)(nil)
+)
+
+type node struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+	list    func(ctx context.Context) ([]*node, error)
+	open    func(ctx context.Context) (io.ReadSeekCloser, error)
+}
+
+func newDirNode(name string, modTime time.Time, list func(ctx context.Context) ([]*node, error)) *node {
+	return &node{name: name, modTime: modTime, isDir: true, list: list}
+}
+
+func newFileNode(name string, size int64, modTime time.Time, open func(ctx context.Context) (io.ReadSeekCloser, error)) *node {
+	return &node{name: name, size: size, modTime: modTime, open: open}
+}
+
+type fileInfo struct {
+	n *node
+}
+
+func (fi *fileInfo) Name() string       { return fi.n.name }
+func (fi *fileInfo) Size() int64        { return fi.n.size }
+func (fi *fileInfo) ModTime() time.Time { return fi.n.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.n.isDir }
+func (fi *fileInfo) Sys() any           { return nil }
+
+func (fi *fileInfo) Mode() fs.FileMode {
+	if fi.n.isDir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+// ContentType avoids sniffing, which would open the file for every PROPFIND.
+func (fi *fileInfo) ContentType(ctx context.Context) (string, error) {
+	if fi.n.isDir {
+		return "", xwebdav.ErrNotImplemented
+	}
+	if contentType := mime.TypeByExtension(path.Ext(fi.n.name)); contentType != "" {
+		return contentType, nil
+	}
+	return "application/octet-stream", nil
+}
+
+// uniqueName suffixes `name` till it is not present in `seen`.
+func uniqueName(seen map[string]struct{}, name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(strings.TrimSpace(name))
+	if name == "" || name == "." || name == ".." {
+		name = "_"
+	}
+	unique := name
+	for i := 2; ; i++ {
+		if _, ok := seen[unique]; !ok {
+			break
+		}
+		ext := path.Ext(name)
+		unique = strings.TrimSuffix(name, ext) + " (" + strconv.Itoa(i) + ")" + ext
+	}
+	seen[unique] = struct{}{}
+	return unique
+}
+
+type treeEntry struct {
+	path string // slash separated, relative to the tree root
+	size int64
+	key  string
+	open func(ctx context.Context) (io.ReadSeekCloser, error)
+}
+
+// buildTree creates the nodes for the entries, with intermediate directories.
+func buildTree(entries []treeEntry, modTime time.Time) []*node {
+	type dir struct {
+		nodes []*node
+		dirs  map[string]*dir
+		seen  map[string]struct{}
+	}
+	newDir := func() *dir {
+		return &dir{nodes: []*node{}, dirs: map[string]*dir{}, seen: map[string]struct{}{}}
+	}
+
+	root := newDir()
+	for i := range entries {
+		e := &entries[i]
+		parts := strings.Split(strings.Trim(e.path, "/"), "/")
+		d := root
+		for _, part := range parts[:len(parts)-1] {
+			if part == "" || part == "." || part == ".." {
+				continue
+			}
+			child, ok := d.dirs[part]
+			if !ok {
+				child = newDir()
+				d.dirs[part] = child
+				d.seen[part] = struct{}{}
+				d.nodes = append(d.nodes, newDirNode(part, modTime, func(ctx context.Context) ([]*node, error) {
+					return child.nodes, nil
+				}))
+			}
+			d = child
+		}
+		name := uniqueName(d.seen, parts[len(parts)-1])
+		d.nodes = append(d.nodes, newFileNode(name, e.size, modTime, e.open))
+	}
+	return root.nodes
+}
+
+type FileSystem struct {
+	root *node
+}
+
+// NewFileSystem returns a read-only filesystem with the usenet library and
+// the store content of `user`.
+func NewFileSystem(user string) *FileSystem {
+	return &FileSystem{
+		root: newDirNode("/", time.Time{}, func(ctx context.Context) ([]*node, error) {
+			nodes := []*node{}
+			if n := newUsenetNode(user); n != nil {
+				nodes = append(nodes, n)
+			}
+			nodes = append(nodes, newStoreNodes(user)...)
+			return nodes, nil
+		}),
+	}
+}
+
+func (fsys *FileSystem) resolve(ctx context.Context, name string) (*node, error) {
+	n := fsys.root
+	for part := range strings.SplitSeq(strings.Trim(path.Clean("/"+name), "/"), "/") {
+		if part == "" {
+			continue
+		}
+		if !n.isDir {
+			return nil, os.ErrNotExist
+		}
+		children, err := n.list(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var child *node
+		for _, c := range children {
+			if c.name == part {
+				child = c
+				break
+			}
+		}
+		if child == nil {
+			return nil, os.ErrNotExist
+		}
+		n = child
+	}
+	return n, nil
+}
+
+func (fsys *FileSystem) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	return os.ErrPermission
+}
+
+func (fsys *FileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (xwebdav.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		return nil, os.ErrPermission
+	}
+	n, err := fsys.resolve(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &file{ctx: ctx, n: n}, nil
+}
+
+func (fsys *FileSystem) RemoveAll(ctx context.Context, name string) error {
+	return os.ErrPermission
+}
+
+func (fsys *FileSystem) Rename(ctx context.Context, oldName, newName string) error {
+	return os.ErrPermission
+}
+
+func (fsys *FileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	n, err := fsys.resolve(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &fileInfo{n: n}, nil
+}
+
+// file opens the underlying stream lazily, stat and seek do not need it.
+type file struct {
+	ctx      context.Context
+	n        *node
+	children []*node
+	listed   bool
+	stream   io.ReadSeekCloser
+	offset   int64
+}
+
+func (f *file) Close() error {
+	if f.stream != nil {
+		return f.stream.Close()
+	}
+	return nil
+}
+
+func (f *file) Read(p []byte) (int, error) {
+	if f.n.isDir {
+		return 0, os.ErrInvalid
+	}
+	if f.stream == nil {
+		if f.offset >= f.n.size {
+			return 0, io.EOF
+		}
+		stream, err := f.n.open(f.ctx)
+		if err != nil {
+			log.Warn("failed to open file", "error", err, "name", f.n.name)
+			return 0, err
+		}
+		if f.offset > 0 {
+			if _, err := stream.Seek(f.offset, io.SeekStart); err != nil {
+				stream.Close()
+				return 0, err
+			}
+		}
+		f.stream = stream
+	}
+	return f.stream.Read(p)
+}
+
+func (f *file) Seek(offset int64, whence int) (int64, error) {
+	if f.n.isDir {
+		return 0, os.ErrInvalid
+	}
+	if f.stream != nil {
+		return f.stream.Seek(offset, whence)
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.n.size
+	default:
+		return 0, os.ErrInvalid
+	}
+	if offset < 0 {
+		return 0, os.ErrInvalid
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *file) Readdir(count int) ([]fs.FileInfo, error) {
+	if !f.n.isDir {
+		return nil, os.ErrInvalid
+	}
+	if !f.listed {
+		children, err := f.n.list(f.ctx)
+		if err != nil {
+			return nil, err
+		}
+		f.children = children
+		f.listed = true
+	}
+	if count <= 0 {
+		infos := make([]fs.FileInfo, len(f.children))
+		for i, c := range f.children {
+			infos[i] = &fileInfo{n: c}
+		}
+		f.children = nil
+		return infos, nil
+	}
+	if len(f.children) == 0 {
+		return nil, io.EOF
+	}
+	count = min(count, len(f.children))
+	infos := make([]fs.FileInfo, count)
+	for i, c := range f.children[:count] {
+		infos[i] = &fileInfo{n: c}
+	}
+	f.children = f.children[count:]
+	return infos, nil
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	return &fileInfo{n: f.n}, nil
+}
+
+func (f *file) Write(p []byte) (int, error) {
+	return 0, os.ErrPermission
+}
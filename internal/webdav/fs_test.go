@@ -0,0 +1,97 @@
+package webdav
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error { return nil }
+
+func TestFileSystem(t *testing.T) {
+	data := []byte("0123456789")
+	opened := 0
+	open := func(ctx context.Context) (io.ReadSeekCloser, error) {
+		opened++
+		return nopCloser{bytes.NewReader(data)}, nil
+	}
+
+	fsys := &FileSystem{
+		root: newDirNode("/", time.Time{}, func(ctx context.Context) ([]*node, error) {
+			return []*node{
+				newDirNode("release", time.Time{}, func(ctx context.Context) ([]*node, error) {
+					return buildTree([]treeEntry{
+						{path: "/Movie/movie.mkv", size: int64(len(data)), open: open},
+						{path: "/Movie/movie.mkv", size: int64(len(data)), open: open},
+						{path: "sample.mkv", size: int64(len(data)), open: open},
+					}, time.Time{}), nil
+				}),
+			}, nil
+		}),
+	}
+
+	t.Run("Stat", func(t *testing.T) {
+		fi, err := fsys.Stat(t.Context(), "/release/Movie/movie (2).mkv")
+		require.NoError(t, err)
+		assert.False(t, fi.IsDir())
+		assert.Equal(t, int64(len(data)), fi.Size())
+
+		fi, err = fsys.Stat(t.Context(), "/release/Movie")
+		require.NoError(t, err)
+		assert.True(t, fi.IsDir())
+
+		_, err = fsys.Stat(t.Context(), "/release/missing.mkv")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Readdir", func(t *testing.T) {
+		f, err := fsys.OpenFile(t.Context(), "/release", os.O_RDONLY, 0)
+		require.NoError(t, err)
+		defer f.Close()
+
+		infos, err := f.Readdir(0)
+		require.NoError(t, err)
+		names := []string{}
+		for _, fi := range infos {
+			names = append(names, fi.Name())
+		}
+		assert.Equal(t, []string{"Movie", "sample.mkv"}, names)
+	})
+
+	t.Run("RangedRead", func(t *testing.T) {
+		opened = 0
+
+		f, err := fsys.OpenFile(t.Context(), "/release/sample.mkv", os.O_RDONLY, 0)
+		require.NoError(t, err)
+		defer f.Close()
+
+		size, err := f.Seek(0, io.SeekEnd)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), size)
+		_, err = f.Seek(4, io.SeekStart)
+		require.NoError(t, err)
+		assert.Equal(t, 0, opened)
+
+		buf := make([]byte, 3)
+		_, err = io.ReadFull(f, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "456", string(buf))
+		assert.Equal(t, 1, opened)
+	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		_, err := fsys.OpenFile(t.Context(), "/release/new.mkv", os.O_CREATE|os.O_WRONLY, 0644)
+		assert.ErrorIs(t, err, os.ErrPermission)
+		assert.ErrorIs(t, fsys.RemoveAll(t.Context(), "/release"), os.ErrPermission)
+	})
+}
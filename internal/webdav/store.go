@@ -0,0 +1,237 @@
+package webdav
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/cache"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/MunifTanjim/stremthru/store"
+)
+
+var storeMagnetsCache = cache.NewCache[[]store.ListMagnetsDataItem](&cache.CacheConfig{
+	Name:     "webdav_store_magnets",
+	Lifetime: 1 * time.Minute,
+})
+
+var storeMagnetCache = cache.NewCache[store.GetMagnetData](&cache.CacheConfig{
+	Name:     "webdav_store_magnet",
+	Lifetime: 10 * time.Minute,
+})
+
+func newStoreNodes(user string) []*node {
+	nodes := []*node{}
+	for _, storeName := range config.StoreAuthToken.ListStores(user) {
+		s := shared.GetStore(storeName)
+		if s == nil {
+			continue
+		}
+		token := config.StoreAuthToken.GetToken(user, storeName)
+		if token == "" {
+			continue
+		}
+		nodes = append(nodes, newStoreNode(user, s, token))
+	}
+	return nodes
+}
+
+func listMagnets(user string, s store.Store, token string) ([]store.ListMagnetsDataItem, error) {
+	cacheKey := user + ":" + string(s.GetName())
+	items := []store.ListMagnetsDataItem{}
+	if storeMagnetsCache.Get(cacheKey, &items) {
+		return items, nil
+	}
+
+	limit := 500
+	for {
+		params := &store.ListMagnetsParams{
+			Limit:  limit,
+			Offset: len(items),
+		}
+		params.APIKey = token
+		res, err := s.ListMagnets(params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, res.Items...)
+		if len(res.Items) == 0 || res.TotalItems <= len(items) {
+			break
+		}
+	}
+
+	if err := storeMagnetsCache.Add(cacheKey, items); err != nil {
+		log.Warn("failed to cache store magnets", "error", err, "store", s.GetName())
+	}
+	return items, nil
+}
+
+func getMagnet(user string, s store.Store, token string, id string) (*store.GetMagnetData, error) {
+	cacheKey := user + ":" + string(s.GetName()) + ":" + id
+	magnet := store.GetMagnetData{}
+	if storeMagnetCache.Get(cacheKey, &magnet) {
+		return &magnet, nil
+	}
+
+	params := &store.GetMagnetParams{
+		Id: id,
+	}
+	params.APIKey = token
+	res, err := s.GetMagnet(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status == store.MagnetStatusDownloaded {
+		if err := storeMagnetCache.Add(cacheKey, *res); err != nil {
+			log.Warn("failed to cache store magnet", "error", err, "store", s.GetName())
+		}
+	}
+	return res, nil
+}
+
+func newStoreNode(user string, s store.Store, token string) *node {
+	return newDirNode(string(s.GetName()), config.ServerStartTime, func(ctx context.Context) ([]*node, error) {
+		items, err := listMagnets(user, s, token)
+		if err != nil {
+			return nil, err
+		}
+		seen := map[string]struct{}{}
+		nodes := []*node{}
+		for i := range items {
+			item := &items[i]
+			if item.Status != store.MagnetStatusDownloaded {
+				continue
+			}
+			nodes = append(nodes, newMagnetNode(uniqueName(seen, item.Name), user, s, token, item))
+		}
+		return nodes, nil
+	})
+}
+
+func newMagnetNode(name string, user string, s store.Store, token string, item *store.ListMagnetsDataItem) *node {
+	return newDirNode(name, item.AddedAt, func(ctx context.Context) ([]*node, error) {
+		magnet, err := getMagnet(user, s, token, item.Id)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]treeEntry, 0, len(magnet.Files))
+		for i := range magnet.Files {
+			f := &magnet.Files[i]
+			if f.Link == "" {
+				continue
+			}
+			path := f.Path
+			if path == "" {
+				path = f.Name
+			}
+			link, size := f.Link, f.Size
+			entries = append(entries, treeEntry{
+				path: path,
+				size: size,
+				key:  link,
+				open: func(ctx context.Context) (io.ReadSeekCloser, error) {
+					params := &store.GenerateLinkParams{
+						Link: link,
+					}
+					params.APIKey = token
+					res, err := s.GenerateLink(params)
+					if err != nil {
+						return nil, err
+					}
+					return newHTTPRangeReader(ctx, streamHTTPClientByTunnelType[config.StoreTunnel.GetTypeForStream(string(s.GetName()))], res.Link, size), nil
+				},
+			})
+		}
+		return buildTree(entries, item.AddedAt), nil
+	})
+}
+
+var streamHTTPClientByTunnelType = func() map[config.TunnelType]*http.Client {
+	clients := map[config.TunnelType]*http.Client{}
+	for _, tunnelType := range []config.TunnelType{config.TUNNEL_TYPE_NONE, config.TUNNEL_TYPE_AUTO, config.TUNNEL_TYPE_FORCED} {
+		transport := config.DefaultHTTPTransport.Clone()
+		transport.Proxy = config.Tunnel.GetProxy(tunnelType)
+		clients[tunnelType] = &http.Client{
+			Transport: transport,
+		}
+	}
+	return clients
+}()
+
+// httpRangeReader reads a remote file using range requests, a new request is
+// made only when seeking to a different offset.
+type httpRangeReader struct {
+	ctx    context.Context
+	client *http.Client
+	link   string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+func newHTTPRangeReader(ctx context.Context, client *http.Client, link string, size int64) *httpRangeReader {
+	return &httpRangeReader{
+		ctx:    ctx,
+		client: client,
+		link:   link,
+		size:   size,
+	}
+}
+
+func (r *httpRangeReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.link, nil)
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", r.offset))
+		res, err := r.client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		if res.StatusCode != http.StatusPartialContent && !(res.StatusCode == http.StatusOK && r.offset == 0) {
+			res.Body.Close()
+			return 0, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+		}
+		r.body = res.Body
+	}
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *httpRangeReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, os.ErrInvalid
+	}
+	if offset < 0 {
+		return 0, os.ErrInvalid
+	}
+	if offset != r.offset && r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+func (r *httpRangeReader) Close() error {
+	if r.body != nil {
+		return r.body.Close()
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package webdav
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/MunifTanjim/stremthru/internal/config"
+	usenetmanager "github.com/MunifTanjim/stremthru/internal/usenet/manager"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb"
+	"github.com/MunifTanjim/stremthru/internal/usenet/nzb_info"
+	usenet_pool "github.com/MunifTanjim/stremthru/internal/usenet/pool"
+)
+
+const usenetDirName = "usenet"
+
+func newUsenetNode(user string) *node {
+	if !config.Feature.HasVault() {
+		return nil
+	}
+	return newDirNode(usenetDirName, config.ServerStartTime, func(ctx context.Context) ([]*node, error) {
+		infos, err := nzb_info.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		seen := map[string]struct{}{}
+		nodes := []*node{}
+		for i := range infos {
+			info := &infos[i]
+			if info.User != user || !info.Streamable {
+				continue
+			}
+			nodes = append(nodes, newNZBNode(uniqueName(seen, info.Name), info))
+		}
+		return nodes, nil
+	})
+}
+
+func newNZBNode(name string, info *nzb_info.NZBInfo) *node {
+	return newDirNode(name, info.CAt.Time, func(ctx context.Context) ([]*node, error) {
+		entries := []treeEntry{}
+		collectUsenetEntries(&entries, info.ContentFiles.Data, "")
+		for i := range entries {
+			contentPath := entries[i].key
+			entries[i].open = func(ctx context.Context) (io.ReadSeekCloser, error) {
+				return openUsenetFile(ctx, info, contentPath)
+			}
+		}
+		return buildTree(entries, info.CAt.Time), nil
+	})
+}
+
+// collectUsenetEntries flattens the content files, the files inside archives
+// are placed alongside the regular files.
+func collectUsenetEntries(entries *[]treeEntry, files []usenet_pool.NZBContentFile, parentContentPath string) {
+	for i := range files {
+		f := &files[i]
+		contentPath := "/" + f.Name
+		if parentContentPath != "" {
+			contentPath = parentContentPath + "::" + contentPath
+		}
+		if len(f.Files) > 0 {
+			collectUsenetEntries(entries, f.Files, contentPath)
+			continue
+		}
+		if f.Size == 0 || !f.Streamable || usenet_pool.IsPar2File(f.Name) {
+			continue
+		}
+		name := f.Name
+		if parentContentPath == "" && f.Alias != "" {
+			name = f.Alias
+		}
+		*entries = append(*entries, treeEntry{
+			path: name,
+			size: f.Size,
+			key:  contentPath,
+		})
+	}
+}
+
+func openUsenetFile(ctx context.Context, info *nzb_info.NZBInfo, contentPath string) (io.ReadSeekCloser, error) {
+	nzbFile, err := nzb_info.FetchNZBFile(info.URL, info.Name, log)
+	if err != nil {
+		return nil, err
+	}
+	nzbDoc, err := nzb.ParseBytes(nzbFile.Blob)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := usenetmanager.GetPool()
+	if err != nil {
+		return nil, err
+	}
+	if pool == nil {
+		return nil, errors.New("no NNTP providers configured")
+	}
+	return pool.StreamByContentPath(ctx, nzbDoc, contentPath, &usenet_pool.StreamConfig{
+		Password:     info.Password,
+		ContentFiles: info.ContentFiles.Data,
+	})
+}
@@ -0,0 +1,49 @@
+package webdl
+
+import (
+	"net/http"
+
+	"github.com/MunifTanjim/stremthru/internal/server"
+	storemiddleware "github.com/MunifTanjim/stremthru/internal/store/middleware"
+)
+
+func AddEndpoints(mux *http.ServeMux) {
+	withStore := server.Middleware(storemiddleware.WithStoreContext, storemiddleware.RequireStore, storemiddleware.EnsureWebDLStore)
+
+	mux.HandleFunc("/v0/store/webdl", withStore(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleStoreWebDLList(w, r)
+		case http.MethodPost:
+			handleStoreWebDLAdd(w, r)
+		default:
+			server.ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	mux.HandleFunc("/v0/store/webdl/check", withStore(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleStoreWebDLCheck(w, r)
+		default:
+			server.ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	mux.HandleFunc("/v0/store/webdl/{webdlId}", withStore(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleStoreWebDLGet(w, r)
+		case http.MethodDelete:
+			handleStoreWebDLRemove(w, r)
+		default:
+			server.ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+	mux.HandleFunc("/v0/store/webdl/link/generate", withStore(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			handleStoreWebDLLinkGenerate(w, r)
+		default:
+			server.ErrorMethodNotAllowed(r).Send(w, r)
+		}
+	}))
+}
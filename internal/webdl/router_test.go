@@ -0,0 +1,68 @@
+package webdl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	mux := http.NewServeMux()
+	AddEndpoints(mux)
+	handler := shared.RootServerContext(mux)
+
+	tooManyLinks := url.Values{}
+	for i := range 101 {
+		tooManyLinks.Add("link", "https://hoster.example/f/"+strconv.Itoa(i))
+	}
+
+	for _, tc := range []struct {
+		name       string
+		method     string
+		target     string
+		storeName  string
+		storeToken string
+		statusCode int
+		message    string
+	}{
+		{"missing store", http.MethodGet, "/v0/store/webdl", "", "", http.StatusBadRequest, "missing store"},
+		{"invalid store", http.MethodGet, "/v0/store/webdl", "unknown", "token", http.StatusBadRequest, "invalid store name"},
+		{"missing store token", http.MethodGet, "/v0/store/webdl", "realdebrid", "", http.StatusUnauthorized, "Unauthorized"},
+		{"unsupported store", http.MethodGet, "/v0/store/webdl", "easydebrid", "token", http.StatusBadRequest, "store does not support webdl"},
+		{"list method not allowed", http.MethodPut, "/v0/store/webdl", "realdebrid", "token", http.StatusMethodNotAllowed, ""},
+		{"check method not allowed", http.MethodPost, "/v0/store/webdl/check", "realdebrid", "token", http.StatusMethodNotAllowed, ""},
+		{"check missing link", http.MethodGet, "/v0/store/webdl/check", "realdebrid", "token", http.StatusBadRequest, "missing link"},
+		{"check too many links", http.MethodGet, "/v0/store/webdl/check?" + tooManyLinks.Encode(), "realdebrid", "token", http.StatusBadRequest, "too many links"},
+		{"add missing link", http.MethodPost, "/v0/store/webdl", "realdebrid", "token", http.StatusBadRequest, "missing link"},
+		{"item method not allowed", http.MethodPost, "/v0/store/webdl/dl1", "realdebrid", "token", http.StatusMethodNotAllowed, ""},
+		{"generate method not allowed", http.MethodGet, "/v0/store/webdl/link/generate", "realdebrid", "token", http.StatusMethodNotAllowed, ""},
+		{"generate missing link", http.MethodPost, "/v0/store/webdl/link/generate", "realdebrid", "token", http.StatusBadRequest, "missing link"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.target, nil)
+			if tc.method == http.MethodPost {
+				r = httptest.NewRequest(tc.method, tc.target, strings.NewReader(`{}`))
+				r.Header.Set("Content-Type", "application/json")
+			}
+			if tc.storeName != "" {
+				r.Header.Set(server.HEADER_STREMTHRU_STORE_NAME, tc.storeName)
+			}
+			if tc.storeToken != "" {
+				r.Header.Set(server.HEADER_STREMTHRU_STORE_AUTHORIZATION, "Bearer "+tc.storeToken)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			assert.Equal(t, tc.statusCode, w.Code)
+			if tc.message != "" {
+				assert.Contains(t, w.Body.String(), tc.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package webdl
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/MunifTanjim/stremthru/internal/server"
+	"github.com/MunifTanjim/stremthru/internal/shared"
+	storecontext "github.com/MunifTanjim/stremthru/internal/store/context"
+	"github.com/MunifTanjim/stremthru/store"
+)
+
+func handleStoreWebDLCheck(w http.ResponseWriter, r *http.Request) {
+	ctx := storecontext.Get(r)
+	webdlStore := ctx.Store.(store.WebDLStore)
+
+	queryParams := r.URL.Query()
+	links, ok := queryParams["link"]
+	if !ok || len(links) == 0 {
+		server.ErrorBadRequest(r).Append(server.Error{
+			LocationType: server.LocationTypeQuery,
+			Location:     "link",
+			Message:      "missing link",
+		}).Send(w, r)
+		return
+	}
+
+	rCtx := server.GetReqCtx(r)
+	rCtx.ReqQuery.Set("link", "..."+strconv.Itoa(len(links))+" items...")
+
+	// links can contain `,`, so they are not split
+	if len(links) > 100 {
+		server.ErrorBadRequest(r).WithMessage("too many links, max allowed 100").Send(w, r)
+		return
+	}
+
+	params := &store.CheckWebDLParams{
+		Links:    links,
+		ClientIP: ctx.ClientIP,
+	}
+	params.APIKey = ctx.StoreAuthToken
+	data, err := webdlStore.CheckWebDL(params)
+	if err != nil {
+		server.SendError(w, r, err)
+		return
+	}
+	server.SendData(w, r, 200, data)
+}
+
+type AddWebDLPayload struct {
+	Link     string `json:"link"`
+	Password string `json:"password"`
+}
+
+func handleStoreWebDLAdd(w http.ResponseWriter, r *http.Request) {
+	payload := &AddWebDLPayload{}
+	if err := server.ReadRequestBodyJSON(r, payload); err != nil {
+		server.SendError(w, r, err)
+		return
+	}
+
+	if payload.Link == "" {
+		server.ErrorBadRequest(r).Append(server.Error{
+			LocationType: server.LocationTypeBody,
+			Location:     "link",
+			Message:      "missing link",
+		}).Send(w, r)
+		return
+	}
+
+	ctx := storecontext.Get(r)
+	webdlStore := ctx.Store.(store.WebDLStore)
+
+	params := &store.AddWebDLParams{
+		Link:     payload.Link,
+		Password: payload.Password,
+		ClientIP: ctx.ClientIP,
+	}
+	params.APIKey = ctx.StoreAuthToken
+	data, err := webdlStore.AddWebDL(params)
+	if err != nil {
+		server.SendError(w, r, err)
+		return
+	}
+	server.SendData(w, r, 201, data)
+}
+
+func handleStoreWebDLList(w http.ResponseWriter, r *http.Request) {
+	ctx := storecontext.Get(r)
+	webdlStore := ctx.Store.(store.WebDLStore)
+
+	queryParams := r.URL.Query()
+	limit, err := shared.GetQueryInt(queryParams, "limit", 100)
+	if err != nil {
+		server.ErrorBadRequest(r).WithMessage(err.Error()).Send(w, r)
+		return
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	offset, err := shared.GetQueryInt(queryParams, "offset", 0)
+	if err != nil {
+		server.ErrorBadRequest(r).WithMessage(err.Error()).Send(w, r)
+		return
+	}
+
+	params := &store.ListWebDLsParams{
+		Limit:    limit,
+		Offset:   offset,
+		ClientIP: ctx.ClientIP,
+	}
+	params.APIKey = ctx.StoreAuthToken
+	data, err := webdlStore.ListWebDLs(params)
+	if err != nil {
+		server.SendError(w, r, err)
+		return
+	}
+	server.SendData(w, r, 200, data)
+}
+
+func handleStoreWebDLGet(w http.ResponseWriter, r *http.Request) {
+	webdlId := r.PathValue("webdlId")
+	if webdlId == "" {
+		server.ErrorBadRequest(r).Append(server.Error{
+			LocationType: server.LocationTypePath,
+			Location:     "webdlId",
+			Message:      "missing webdl id",
+		}).Send(w, r)
+		return
+	}
+
+	ctx := storecontext.Get(r)
+	webdlStore := ctx.Store.(store.WebDLStore)
+
+	params := &store.GetWebDLParams{
+		Id:       webdlId,
+		ClientIP: ctx.ClientIP,
+	}
+	params.APIKey = ctx.StoreAuthToken
+	data, err := webdlStore.GetWebDL(params)
+	if err != nil {
+		server.SendError(w, r, err)
+		return
+	}
+	server.SendData(w, r, 200, data)
+}
+
+func handleStoreWebDLRemove(w http.ResponseWriter, r *http.Request) {
+	webdlId := r.PathValue("webdlId")
+	if webdlId == "" {
+		server.ErrorBadRequest(r).Append(server.Error{
+			LocationType: server.LocationTypePath,
+			Location:     "webdlId",
+			Message:      "missing webdl id",
+		}).Send(w, r)
+		return
+	}
+
+	ctx := storecontext.Get(r)
+	webdlStore := ctx.Store.(store.WebDLStore)
+
+	params := &store.RemoveWebDLParams{
+		Id: webdlId,
+	}
+	params.APIKey = ctx.StoreAuthToken
+	data, err := webdlStore.RemoveWebDL(params)
+	if err != nil {
+		server.SendError(w, r, err)
+		return
+	}
+	server.SendData(w, r, 200, data)
+}
+
+type GenerateWebDLLinkPayload struct {
+	Link string `json:"link"`
+}
+
+func handleStoreWebDLLinkGenerate(w http.ResponseWriter, r *http.Request) {
+	payload := &GenerateWebDLLinkPayload{}
+	if err := server.ReadRequestBodyJSON(r, payload); err != nil {
+		server.SendError(w, r, err)
+		return
+	}
+
+	if payload.Link == "" {
+		server.ErrorBadRequest(r).Append(server.Error{
+			LocationType: server.LocationTypeBody,
+			Location:     "link",
+			Message:      "missing link",
+		}).Send(w, r)
+		return
+	}
+
+	ctx := storecontext.Get(r)
+	webdlStore := ctx.Store.(store.WebDLStore)
+
+	params := &store.GenerateWebDLLinkParams{
+		Link:     payload.Link,
+		ClientIP: ctx.ClientIP,
+	}
+	params.APIKey = ctx.StoreAuthToken
+	data, err := webdlStore.GenerateWebDLLink(params)
+	if err != nil {
+		server.SendError(w, r, err)
+		return
+	}
+
+	data.Link, err = shared.ProxyWrapLink(r, ctx, data.Link, "")
+	if err != nil {
+		server.SendError(w, r, err)
+		return
+	}
+
+	server.SendData(w, r, 200, data)
+}
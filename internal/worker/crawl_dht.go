@@ -0,0 +1,155 @@
+package worker
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/dht_crawler"
+	"github.com/MunifTanjim/stremthru/internal/torrent_info"
+	ts "github.com/MunifTanjim/stremthru/internal/torrent_stream"
+)
+
+var dhtWhitelistedExtension = map[string]struct{}{
+	".vob": {},
+	".iso": {},
+}
+
+// filterNewDHTHashes returns the hashes that are not stored yet.
+func filterNewDHTHashes(hashes []string) ([]string, error) {
+	exists, err := torrent_info.ExistsByHash(hashes)
+	if err != nil {
+		return nil, err
+	}
+	newHashes := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		if !exists[hash] {
+			newHashes = append(newHashes, hash)
+		}
+	}
+	return newHashes, nil
+}
+
+// dhtTorrentToInsertData returns false if the torrent has no video file.
+func dhtTorrentToInsertData(t *dht_crawler.Torrent) (torrent_info.TorrentInfoInsertData, bool) {
+	torrent := torrent_info.TorrentInfoInsertData{
+		Hash:         t.Hash,
+		TorrentTitle: t.Name,
+		Size:         t.Size,
+		Source:       torrent_info.TorrentInfoSourceDHT,
+		Private:      t.Private,
+		Files:        make(ts.Files, len(t.Files)),
+	}
+	hasValidFiles := false
+	for i := range t.Files {
+		f := &t.Files[i]
+		name := filepath.Base(f.Path)
+		if !hasValidFiles {
+			hasValidFiles = core.HasVideoExtension(name)
+			if !hasValidFiles {
+				ext := strings.ToLower(filepath.Ext(name))
+				_, hasValidFiles = dhtWhitelistedExtension[ext]
+			}
+		}
+
+		torrent.Files[i] = ts.File{
+			Path:   f.Path,
+			Name:   name,
+			Idx:    f.Idx,
+			Size:   f.Size,
+			Source: string(torrent_info.TorrentInfoSourceDHT),
+		}
+	}
+	return torrent, hasValidFiles
+}
+
+func InitCrawlDHTWorker(conf *WorkerConfig) *Worker {
+	conf.Executor = func(w *Worker) error {
+		log := w.Log
+
+		limit := config.Torz.DHTCrawlerMaxPerRun
+		if maxTorrents := config.Torz.DHTCrawlerMaxTorrents; maxTorrents > 0 {
+			count, err := torrent_info.CountBySource(torrent_info.TorrentInfoSourceDHT)
+			if err != nil {
+				return err
+			}
+			if count >= maxTorrents {
+				log.Info("storage cap reached, skipping", "count", count, "max_torrents", maxTorrents)
+				return nil
+			}
+			if limit <= 0 || maxTorrents-count < limit {
+				limit = maxTorrents - count
+			}
+		}
+
+		crawler, err := dht_crawler.NewCrawler(&dht_crawler.Config{
+			DataDir:     dht_crawler.GetDataDir(config.DataDir),
+			ListenPort:  config.Torz.DHTCrawlerListenPort,
+			Concurrency: config.Torz.DHTCrawlerConcurrency,
+			Filter:      filterNewDHTHashes,
+			Log:         log,
+		})
+		if err != nil {
+			return err
+		}
+		defer crawler.Close()
+
+		ctx, cancel := context.WithTimeout(w.Context(), 30*time.Minute)
+		defer cancel()
+
+		batchSize := 100
+		torrents := make([]torrent_info.TorrentInfoInsertData, 0, batchSize)
+		fetchedCount, acceptedCount, totalCount := 0, 0, 0
+
+		upsert := func() error {
+			if len(torrents) == 0 {
+				return nil
+			}
+			if err := torrent_info.Upsert(torrents, "", false); err != nil {
+				return err
+			}
+			count := len(torrents)
+			totalCount += count
+			log.Info("upserted torrents", "count", count, "total_count", totalCount, "fetched_count", fetchedCount)
+			torrents = torrents[:0]
+			return nil
+		}
+
+		// only the torrents that are going to be stored count against the limit
+		results := crawler.Run(ctx)
+		for t := range results {
+			if limit > 0 && acceptedCount >= limit {
+				continue
+			}
+			fetchedCount++
+			if torrent, ok := dhtTorrentToInsertData(&t); ok {
+				torrents = append(torrents, torrent)
+				acceptedCount++
+				if limit > 0 && acceptedCount >= limit {
+					cancel()
+				}
+			}
+			if len(torrents) >= batchSize {
+				if err := upsert(); err != nil {
+					cancel()
+					for range results {
+					}
+					return err
+				}
+			}
+		}
+
+		if err := upsert(); err != nil {
+			return err
+		}
+
+		log.Info("crawled dht", "fetched_count", fetchedCount, "total_count", totalCount)
+
+		return nil
+	}
+
+	return NewWorker(conf)
+}
@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/db/dbtest"
+	"github.com/MunifTanjim/stremthru/internal/dht_crawler"
+	"github.com/MunifTanjim/stremthru/internal/torrent_info"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDHTTorrentToInsertData(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		files []dht_crawler.File
+		ok    bool
+	}{
+		{
+			name: "video",
+			files: []dht_crawler.File{
+				{Idx: 0, Path: "/Movie/Movie.mkv", Size: 1000},
+				{Idx: 1, Path: "/Movie/Movie.nfo", Size: 10},
+			},
+			ok: true,
+		},
+		{
+			name: "whitelisted extension",
+			files: []dht_crawler.File{
+				{Idx: 0, Path: "/Movie/Movie.ISO", Size: 1000},
+			},
+			ok: true,
+		},
+		{
+			name: "no video",
+			files: []dht_crawler.File{
+				{Idx: 0, Path: "/Album/01.flac", Size: 1000},
+				{Idx: 1, Path: "/Album/cover.jpg", Size: 10},
+			},
+			ok: false,
+		},
+		{
+			name:  "no files",
+			files: []dht_crawler.File{},
+			ok:    false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, ok := dhtTorrentToInsertData(&dht_crawler.Torrent{
+				Hash:    "0123456789abcdef0123456789abcdef01234567",
+				Name:    "Torrent",
+				Size:    1010,
+				Private: true,
+				Files:   tc.files,
+			})
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", data.Hash)
+			assert.Equal(t, "Torrent", data.TorrentTitle)
+			assert.Equal(t, int64(1010), data.Size)
+			assert.Equal(t, torrent_info.TorrentInfoSourceDHT, data.Source)
+			assert.True(t, data.Private)
+			require.Len(t, data.Files, len(tc.files))
+			for i, f := range tc.files {
+				assert.Equal(t, f.Idx, data.Files[i].Idx)
+				assert.Equal(t, f.Path, data.Files[i].Path)
+				assert.Equal(t, f.Size, data.Files[i].Size)
+				assert.Equal(t, string(torrent_info.TorrentInfoSourceDHT), data.Files[i].Source)
+			}
+		})
+	}
+}
+
+func TestFilterNewDHTHashes(t *testing.T) {
+	dbtest.Open(t)
+
+	storedHash := "0123456789abcdef0123456789abcdef01234567"
+	newHash := "fedcba9876543210fedcba9876543210fedcba98"
+
+	data, ok := dhtTorrentToInsertData(&dht_crawler.Torrent{
+		Hash:  storedHash,
+		Name:  "Movie",
+		Size:  1000,
+		Files: []dht_crawler.File{{Idx: 0, Path: "/Movie/Movie.mkv", Size: 1000}},
+	})
+	require.True(t, ok)
+	require.NoError(t, torrent_info.Upsert([]torrent_info.TorrentInfoInsertData{data}, "", false))
+
+	hashes, err := filterNewDHTHashes([]string{storedHash, newHash})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{newHash}, hashes)
+
+	hashes, err = filterNewDHTHashes([]string{})
+	assert.NoError(t, err)
+	assert.Empty(t, hashes)
+}
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/internal/anime"
+	"github.com/MunifTanjim/stremthru/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAnimeIdMapsGetter(t *testing.T) {
+	for _, service := range []string{"", "unknown", anime.IdMapColumn.AniDB} {
+		assert.Nil(t, getAnimeIdMapsGetter(service), "service %q", service)
+	}
+
+	dbtest.Open(t)
+
+	require.NoError(t, anime.BulkRecordIdMaps([]anime.AnimeIdMap{
+		{Type: anime.AnimeIdMapTypeTV, AniList: "21", Kitsu: "12", MAL: "21", IMDB: "tt0388629"},
+	}, anime.IdMapColumn.MAL))
+
+	for _, tc := range []struct {
+		service string
+		id      int
+	}{
+		{anime.IdMapColumn.AniList, 21},
+		{anime.IdMapColumn.Kitsu, 12},
+		{anime.IdMapColumn.MAL, 21},
+	} {
+		t.Run(tc.service, func(t *testing.T) {
+			getIdMaps := getAnimeIdMapsGetter(tc.service)
+			require.NotNil(t, getIdMaps)
+
+			idMaps, err := getIdMaps([]int{tc.id, 99999})
+			assert.NoError(t, err)
+			if assert.Len(t, idMaps, 1) {
+				idMap := idMaps[0]
+				assert.Equal(t, "21", idMap.AniList)
+				assert.Equal(t, "12", idMap.Kitsu)
+				assert.Equal(t, "21", idMap.MAL)
+				assert.Equal(t, "tt0388629", idMap.IMDB)
+			}
+
+			idMaps, err = getIdMaps([]int{})
+			assert.NoError(t, err)
+			assert.Empty(t, idMaps)
+		})
+	}
+}
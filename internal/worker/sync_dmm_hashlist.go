@@ -244,6 +244,9 @@ func InitSyncDMMHashlistWorker(conf *WorkerConfig) *Worker {
 			if !hashlistFilenameRegex.MatchString(filename) {
 				continue
 			}
+			if err := w.Err(); err != nil {
+				return err
+			}
 			newTotalCount, err := processHashlistFile(w, filename, hashSeenLru, totalCount)
 			if err != nil {
 				return err
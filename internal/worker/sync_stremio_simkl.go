@@ -0,0 +1,570 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/simkl"
+	simkl_account "github.com/MunifTanjim/stremthru/internal/simkl/account"
+	stremio_account "github.com/MunifTanjim/stremthru/internal/stremio/account"
+	stremio_api "github.com/MunifTanjim/stremthru/internal/stremio/api"
+	"github.com/MunifTanjim/stremthru/internal/stremio/cinemeta"
+	"github.com/MunifTanjim/stremthru/internal/sync/stremio_simkl"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	"github.com/MunifTanjim/stremthru/stremio"
+	stremio_watched_bitfield "github.com/MunifTanjim/stremthru/stremio/watched_bitfield"
+)
+
+func isSimklWatchedMovie(item *simkl.AllItemsItem) bool {
+	return item.Movie != nil && item.Movie.Ids.IMDB != "" && (item.Status == simkl.ListStatusCompleted || item.LastWatchedAt != nil)
+}
+
+// collectSimklWatchedItems returns the watched movies and the shows with
+// watched episodes, that have imdb id.
+func collectSimklWatchedItems(data *simkl.GetAllItemsData) (movies, shows []simkl.AllItemsItem) {
+	for _, item := range data.Movies {
+		if !isSimklWatchedMovie(&item) {
+			continue
+		}
+		movies = append(movies, item)
+	}
+	for _, item := range data.Shows {
+		if item.Show == nil || item.Show.Ids.IMDB == "" || len(item.Seasons) == 0 {
+			continue
+		}
+		shows = append(shows, item)
+	}
+	return movies, shows
+}
+
+func setSimklWatchedMovie(libraryItem *stremio_api.LibraryItem, item *simkl.AllItemsItem) {
+	libraryItem.State.TimesWatched = 1
+	if item.LastWatchedAt != nil && item.LastWatchedAt.After(libraryItem.State.LastWatched) {
+		libraryItem.State.LastWatched = *item.LastWatchedAt
+	}
+}
+
+// setSimklWatchedEpisodes marks the watched episodes in the bitfield, as
+// `{imdbId}:{season}:{episode}` videos. It returns whether anything changed,
+// and the latest watched time of the newly marked episodes.
+func setSimklWatchedEpisodes(wbf *stremio_watched_bitfield.WatchedBitField, imdbId string, seasons []simkl.WatchedSeason) (changed bool, lastWatched time.Time) {
+	for _, season := range seasons {
+		for _, episode := range season.Episodes {
+			videoId := fmt.Sprintf("%s:%d:%d", imdbId, season.Number, episode.Number)
+			if !wbf.GetVideo(videoId) {
+				wbf.SetVideo(videoId, true)
+				changed = true
+				if episode.WatchedAt != nil && episode.WatchedAt.After(lastWatched) {
+					lastWatched = *episode.WatchedAt
+				}
+			}
+		}
+	}
+	return changed, lastWatched
+}
+
+// getStremioWatchedEpisodesForSimkl returns the watched episodes by season,
+// skipping the ones already watched on simkl (as `{season}:{episode}`).
+func getStremioWatchedEpisodesForSimkl(wbf *stremio_watched_bitfield.WatchedBitField, videoIds []string, simklWatched *util.Set[string]) map[int][]simkl.WatchedEpisode {
+	episodesBySeason := map[int][]simkl.WatchedEpisode{}
+	for _, videoId := range videoIds {
+		if !wbf.GetVideo(videoId) {
+			continue
+		}
+		parts := strings.Split(videoId, ":")
+		if len(parts) < 3 {
+			continue
+		}
+
+		season, episode := util.SafeParseInt(parts[1], 0), util.SafeParseInt(parts[2], 0)
+		if season < 1 || episode < 1 {
+			continue
+		}
+
+		if simklWatched != nil && simklWatched.Has(fmt.Sprintf("%d:%d", season, episode)) {
+			continue
+		}
+
+		episodesBySeason[season] = append(episodesBySeason[season], simkl.WatchedEpisode{
+			Number: episode,
+		})
+	}
+	return episodesBySeason
+}
+
+func InitSyncStremioSimklWorker(conf *WorkerConfig) *Worker {
+	type Ctx struct {
+		now        time.Time
+		log        *logger.Logger
+		link       *sync_stremio_simkl.SyncStremioSimklLink
+		isFullSync bool
+
+		stremioAccount *stremio_account.StremioAccount
+		stremioClient  *stremio_api.Client
+		stremioToken   string
+		stremioMovies  []stremio_api.LibraryItem
+		stremioSeries  []stremio_api.LibraryItem
+
+		simklAccount *simkl_account.SimklAccount
+		simklClient  *simkl.APIClient
+		simklMovies  []simkl.AllItemsItem
+		simklShows   []simkl.AllItemsItem
+
+		// all watched items on simkl, only populated when syncing to simkl
+		simklWatchedMovieImdbIds *util.Set[string]
+		simklWatchedByImdbId     map[string]*util.Set[string]
+	}
+
+	createLibraryItem := func(ctx *Ctx, meta stremio.Meta, state stremio_api.LibraryItemState) stremio_api.LibraryItem {
+		return stremio_api.LibraryItem{
+			Id:          meta.Id,
+			Type:        string(meta.Type),
+			Name:        meta.Name,
+			Poster:      meta.Poster,
+			PosterShape: meta.PosterShape,
+			Background:  meta.Background,
+			Logo:        meta.Logo,
+			Year:        meta.ReleaseInfo,
+			State:       state,
+			Removed:     false,
+			Temp:        false,
+			CTime:       stremio_api.JSONTime{Time: ctx.now},
+			MTime:       stremio_api.JSONTime{Time: ctx.now},
+		}
+	}
+
+	loadSimklWatched := func(ctx *Ctx) error {
+		if ctx.simklWatchedByImdbId != nil {
+			return nil
+		}
+
+		movies, shows := ctx.simklMovies, ctx.simklShows
+		if !ctx.isFullSync {
+			res, err := ctx.simklClient.GetAllItems(&simkl.GetAllItemsParams{})
+			if err != nil {
+				return err
+			}
+			movies, shows = collectSimklWatchedItems(&res.Data)
+		}
+
+		ctx.simklWatchedMovieImdbIds = util.NewSet[string]()
+		for _, item := range movies {
+			ctx.simklWatchedMovieImdbIds.Add(item.Movie.Ids.IMDB)
+		}
+
+		ctx.simklWatchedByImdbId = map[string]*util.Set[string]{}
+		for _, item := range shows {
+			imdbId := item.Show.Ids.IMDB
+			for _, season := range item.Seasons {
+				for _, episode := range season.Episodes {
+					if ctx.simklWatchedByImdbId[imdbId] == nil {
+						ctx.simklWatchedByImdbId[imdbId] = util.NewSet[string]()
+					}
+					ctx.simklWatchedByImdbId[imdbId].Add(
+						fmt.Sprintf("%d:%d", season.Number, episode.Number),
+					)
+				}
+			}
+		}
+		return nil
+	}
+
+	syncMovieFromStremioToSimkl := func(ctx *Ctx) error {
+		var moviesToAdd []simkl.SyncHistoryParamsItem
+		for _, item := range ctx.stremioMovies {
+			if item.State.TimesWatched == 0 || ctx.simklWatchedMovieImdbIds.Has(item.Id) {
+				continue
+			}
+
+			moviesToAdd = append(moviesToAdd, simkl.SyncHistoryParamsItem{
+				Ids:       simkl.Ids{IMDB: item.Id},
+				WatchedAt: &item.State.LastWatched,
+			})
+		}
+
+		if len(moviesToAdd) == 0 {
+			return nil
+		}
+
+		_, err := ctx.simklClient.AddToHistory(&simkl.AddToHistoryParams{
+			Movies: moviesToAdd,
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx.log.Debug("synced movies from stremio to simkl", "count", len(moviesToAdd))
+		return nil
+	}
+
+	syncSeriesFromStremioToSimkl := func(ctx *Ctx) error {
+		var showsToAdd []simkl.SyncHistoryShow
+		for _, item := range ctx.stremioSeries {
+			if item.State.Watched == "" {
+				continue
+			}
+
+			meta, err := cinemeta.FetchMeta("series", item.Id)
+			if err != nil {
+				return err
+			}
+			var videoIds []string
+			for _, video := range meta.Videos {
+				videoIds = append(videoIds, video.Id)
+			}
+			wbf, err := stremio_watched_bitfield.NewWatchedBitFieldFromString(item.State.Watched, videoIds)
+			if err != nil {
+				continue
+			}
+
+			episodesBySeason := getStremioWatchedEpisodesForSimkl(wbf, videoIds, ctx.simklWatchedByImdbId[item.Id])
+
+			if len(episodesBySeason) == 0 {
+				continue
+			}
+
+			seasons := make([]simkl.WatchedSeason, 0, len(episodesBySeason))
+			for season, episodes := range episodesBySeason {
+				seasons = append(seasons, simkl.WatchedSeason{
+					Number:   season,
+					Episodes: episodes,
+				})
+			}
+			showsToAdd = append(showsToAdd, simkl.SyncHistoryShow{
+				SyncHistoryParamsItem: simkl.SyncHistoryParamsItem{
+					Ids: simkl.Ids{IMDB: item.Id},
+				},
+				Seasons: seasons,
+			})
+		}
+
+		if len(showsToAdd) == 0 {
+			return nil
+		}
+
+		_, err := ctx.simklClient.AddToHistory(&simkl.AddToHistoryParams{
+			Shows: showsToAdd,
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx.log.Debug("synced series from stremio to simkl", "count", len(showsToAdd))
+		return nil
+	}
+
+	syncMovieFromSimklToStremio := func(ctx *Ctx) error {
+		var itemsToUpdate []stremio_api.LibraryItem
+
+		stremioItemByImdbId := map[string]stremio_api.LibraryItem{}
+		for _, item := range ctx.stremioMovies {
+			stremioItemByImdbId[item.Id] = item
+		}
+
+		if !ctx.isFullSync {
+			var idsToFetch []string
+			for _, item := range ctx.simklMovies {
+				if _, exists := stremioItemByImdbId[item.Movie.Ids.IMDB]; !exists {
+					idsToFetch = append(idsToFetch, item.Movie.Ids.IMDB)
+				}
+			}
+			if len(idsToFetch) > 0 {
+				res, err := ctx.stremioClient.GetAllLibraryItems(&stremio_api.GetAllLibraryItemsParams{
+					Ctx: stremio_api.Ctx{APIKey: ctx.stremioToken},
+					Ids: idsToFetch,
+				})
+				if err != nil {
+					return err
+				}
+				for _, item := range res.Data {
+					if item.Type == "movie" {
+						stremioItemByImdbId[item.Id] = item
+					}
+				}
+			}
+		}
+
+		for _, item := range ctx.simklMovies {
+			imdbId := item.Movie.Ids.IMDB
+			libraryItem, ok := stremioItemByImdbId[imdbId]
+			if ok {
+				if libraryItem.State.TimesWatched > 0 {
+					continue
+				}
+				libraryItem.MTime = stremio_api.JSONTime{Time: ctx.now}
+			} else {
+				meta, err := cinemeta.FetchMeta("movie", imdbId)
+				if err != nil {
+					return err
+				}
+				libraryItem = createLibraryItem(ctx, meta, stremio_api.LibraryItemState{})
+			}
+			setSimklWatchedMovie(&libraryItem, &item)
+			itemsToUpdate = append(itemsToUpdate, libraryItem)
+		}
+
+		if len(itemsToUpdate) == 0 {
+			return nil
+		}
+
+		_, err := ctx.stremioClient.UpdateLibraryItems(&stremio_api.UpdateLibraryItemsParams{
+			Ctx:     stremio_api.Ctx{APIKey: ctx.stremioToken},
+			Changes: itemsToUpdate,
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx.log.Debug("synced movies from simkl to stremio", "count", len(itemsToUpdate))
+		return nil
+	}
+
+	syncSeriesFromSimklToStremio := func(ctx *Ctx) error {
+		var itemsToUpdate []stremio_api.LibraryItem
+
+		stremioItemByImdbId := map[string]stremio_api.LibraryItem{}
+		for _, item := range ctx.stremioSeries {
+			stremioItemByImdbId[item.Id] = item
+		}
+
+		if !ctx.isFullSync {
+			var idsToFetch []string
+			for _, item := range ctx.simklShows {
+				if _, exists := stremioItemByImdbId[item.Show.Ids.IMDB]; !exists {
+					idsToFetch = append(idsToFetch, item.Show.Ids.IMDB)
+				}
+			}
+			if len(idsToFetch) > 0 {
+				res, err := ctx.stremioClient.GetAllLibraryItems(&stremio_api.GetAllLibraryItemsParams{
+					Ctx: stremio_api.Ctx{APIKey: ctx.stremioToken},
+					Ids: idsToFetch,
+				})
+				if err != nil {
+					return err
+				}
+				for _, item := range res.Data {
+					if item.Type == "series" {
+						stremioItemByImdbId[item.Id] = item
+					}
+				}
+			}
+		}
+
+		for _, simklItem := range ctx.simklShows {
+			imdbId := simklItem.Show.Ids.IMDB
+
+			meta, err := cinemeta.FetchMeta("series", imdbId)
+			if err != nil {
+				return err
+			}
+			var videoIds []string
+			for _, video := range meta.Videos {
+				videoIds = append(videoIds, video.Id)
+			}
+
+			libraryItem, exists := stremioItemByImdbId[imdbId]
+			var wbf *stremio_watched_bitfield.WatchedBitField
+			if exists && libraryItem.State.Watched != "" {
+				if wbf, err = stremio_watched_bitfield.NewWatchedBitFieldFromString(libraryItem.State.Watched, videoIds); err != nil {
+					return err
+				}
+			} else {
+				wbf = stremio_watched_bitfield.NewWatchedBitField(stremio_watched_bitfield.NewBitField8(len(videoIds)), videoIds)
+			}
+
+			needsUpdate, lastWatched := setSimklWatchedEpisodes(wbf, imdbId, simklItem.Seasons)
+
+			if needsUpdate {
+				watchedStr, err := wbf.String()
+				if err != nil {
+					return err
+				}
+
+				if exists {
+					libraryItem.MTime = stremio_api.JSONTime{Time: ctx.now}
+				} else {
+					libraryItem = createLibraryItem(ctx, meta, stremio_api.LibraryItemState{})
+				}
+				libraryItem.State.Watched = watchedStr
+				if lastWatched.IsZero() && simklItem.LastWatchedAt != nil {
+					lastWatched = *simklItem.LastWatchedAt
+				}
+				if lastWatched.After(libraryItem.State.LastWatched) {
+					libraryItem.State.LastWatched = lastWatched
+				}
+				if videoId := wbf.GetNextUnwatchedVideoId(); videoId != libraryItem.State.VideoId {
+					libraryItem.State.VideoId = videoId
+					libraryItem.State.TimeOffset = 0
+				}
+				itemsToUpdate = append(itemsToUpdate, libraryItem)
+			}
+		}
+
+		if len(itemsToUpdate) == 0 {
+			return nil
+		}
+
+		_, err := ctx.stremioClient.UpdateLibraryItems(&stremio_api.UpdateLibraryItemsParams{
+			Ctx:     stremio_api.Ctx{APIKey: ctx.stremioToken},
+			Changes: itemsToUpdate,
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx.log.Debug("synced series from simkl to stremio", "count", len(itemsToUpdate))
+		return nil
+	}
+
+	syncWatched := func(link *sync_stremio_simkl.SyncStremioSimklLink, log *logger.Logger) error {
+		log = log.With(
+			"stremio_account_id", link.StremioAccountId,
+			"simkl_account_id", link.SimklAccountId,
+		)
+
+		ctx := &Ctx{
+			log:  log,
+			link: link,
+		}
+
+		stremioAccount, err := stremio_account.GetById(link.StremioAccountId)
+		if err != nil || stremioAccount == nil {
+			return fmt.Errorf("stremio account not found: %w", err)
+		}
+		ctx.stremioAccount = stremioAccount
+
+		simklAccount, err := simkl_account.GetById(link.SimklAccountId)
+		if err != nil || simklAccount == nil {
+			return fmt.Errorf("simkl account not found: %w", err)
+		}
+		ctx.simklAccount = simklAccount
+
+		stremioToken, err := stremioAccount.GetValidToken()
+		if err != nil {
+			return err
+		}
+		ctx.stremioToken = stremioToken
+
+		ctx.stremioClient = stremio_api.NewClient(&stremio_api.ClientConfig{})
+
+		ctx.simklClient = simkl.GetAPIClient(simklAccount.OAuthTokenId)
+
+		ctx.now = time.Now()
+
+		var startAt time.Time
+		if link.SyncState.Watched.LastSyncedAt != nil {
+			startAt = *link.SyncState.Watched.LastSyncedAt
+		}
+
+		ctx.isFullSync = startAt.IsZero()
+
+		log.Debug("starting watched sync", "is_full_sync", ctx.isFullSync, "start_at", startAt)
+
+		var stremioItemIds []string
+		if !ctx.isFullSync {
+			tsRes, err := ctx.stremioClient.GetAllLibraryItemTimestamps(&stremio_api.GetAllLibraryItemTimestampsParams{Ctx: stremio_api.Ctx{APIKey: stremioToken}})
+			if err != nil {
+				return err
+			}
+			for _, ts := range tsRes.Data {
+				if !strings.HasPrefix(ts.Id, "tt") {
+					continue
+				}
+				if ts.ModifiedAt.After(startAt) {
+					stremioItemIds = append(stremioItemIds, ts.Id)
+				}
+			}
+		}
+
+		if ctx.isFullSync || len(stremioItemIds) > 0 {
+			stremioLibItemsRes, err := ctx.stremioClient.GetAllLibraryItems(&stremio_api.GetAllLibraryItemsParams{
+				Ctx: stremio_api.Ctx{APIKey: stremioToken},
+				Ids: stremioItemIds,
+			})
+			if err != nil {
+				return err
+			}
+			for _, item := range stremioLibItemsRes.Data {
+				if !strings.HasPrefix(item.Id, "tt") {
+					continue
+				}
+				switch item.Type {
+				case "movie":
+					ctx.stremioMovies = append(ctx.stremioMovies, item)
+				case "series":
+					ctx.stremioSeries = append(ctx.stremioSeries, item)
+				}
+			}
+		}
+
+		log.Debug("fetched stremio items", "movies", len(ctx.stremioMovies), "series", len(ctx.stremioSeries))
+
+		simklParams := simkl.GetAllItemsParams{EpisodeWatchedAt: true}
+		if !ctx.isFullSync {
+			simklParams.DateFrom = &startAt
+		}
+		simklRes, err := ctx.simklClient.GetAllItems(&simklParams)
+		if err != nil {
+			return err
+		}
+		ctx.simklMovies, ctx.simklShows = collectSimklWatchedItems(&simklRes.Data)
+
+		log.Debug("fetched simkl items", "simkl_movies", len(ctx.simklMovies), "simkl_shows", len(ctx.simklShows))
+
+		if link.SyncConfig.Watched.Direction.ShouldSyncToSimkl() && (len(ctx.stremioMovies) > 0 || len(ctx.stremioSeries) > 0) {
+			if err := loadSimklWatched(ctx); err != nil {
+				log.Error("failed to fetch watched items from simkl", "error", err)
+				return err
+			}
+			if err := syncMovieFromStremioToSimkl(ctx); err != nil {
+				log.Error("failed to sync movies from stremio to simkl", "error", err)
+				return err
+			}
+			if err := syncSeriesFromStremioToSimkl(ctx); err != nil {
+				log.Error("failed to sync series from stremio to simkl", "error", err)
+				return err
+			}
+		}
+
+		if link.SyncConfig.Watched.Direction.ShouldSyncToStremio() {
+			if err := syncMovieFromSimklToStremio(ctx); err != nil {
+				log.Error("failed to sync movies from simkl to stremio", "error", err)
+				return err
+			}
+			if err := syncSeriesFromSimklToStremio(ctx); err != nil {
+				log.Error("failed to sync series from simkl to stremio", "error", err)
+				return err
+			}
+		}
+
+		link.SyncState.Watched.LastSyncedAt = &ctx.now
+		sync_stremio_simkl.SetSyncState(link.StremioAccountId, link.SimklAccountId, link.SyncState)
+		return nil
+	}
+
+	conf.Executor = func(w *Worker) error {
+		log := w.Log
+
+		links, err := sync_stremio_simkl.GetAll()
+		if err != nil {
+			return err
+		}
+
+		for _, link := range links {
+			if err := w.Err(); err != nil {
+				return err
+			}
+			if !link.SyncConfig.Watched.Direction.IsDisabled() {
+				err := syncWatched(&link, log)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+	return NewWorker(conf)
+}
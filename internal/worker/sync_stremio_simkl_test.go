@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/internal/simkl"
+	stremio_api "github.com/MunifTanjim/stremthru/internal/stremio/api"
+	"github.com/MunifTanjim/stremthru/internal/util"
+	stremio_watched_bitfield "github.com/MunifTanjim/stremthru/stremio/watched_bitfield"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectSimklWatchedItems(t *testing.T) {
+	lastWatchedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	data := &simkl.GetAllItemsData{
+		Movies: []simkl.AllItemsItem{
+			{Status: simkl.ListStatusCompleted, Movie: &simkl.Media{Ids: simkl.Ids{IMDB: "tt0000001"}}},
+			{Status: simkl.ListStatusWatching, LastWatchedAt: &lastWatchedAt, Movie: &simkl.Media{Ids: simkl.Ids{IMDB: "tt0000002"}}},
+			{Status: simkl.ListStatusPlanToWatch, Movie: &simkl.Media{Ids: simkl.Ids{IMDB: "tt0000003"}}},
+			{Status: simkl.ListStatusCompleted, Movie: &simkl.Media{Ids: simkl.Ids{Simkl: 4}}},
+			{Status: simkl.ListStatusCompleted},
+		},
+		Shows: []simkl.AllItemsItem{
+			{Show: &simkl.Media{Ids: simkl.Ids{IMDB: "tt0000011"}}, Seasons: []simkl.WatchedSeason{{Number: 1}}},
+			{Show: &simkl.Media{Ids: simkl.Ids{IMDB: "tt0000012"}}},
+			{Show: &simkl.Media{Ids: simkl.Ids{Simkl: 13}}, Seasons: []simkl.WatchedSeason{{Number: 1}}},
+		},
+	}
+
+	movies, shows := collectSimklWatchedItems(data)
+	movieIds := []string{}
+	for _, item := range movies {
+		movieIds = append(movieIds, item.Movie.Ids.IMDB)
+	}
+	assert.Equal(t, []string{"tt0000001", "tt0000002"}, movieIds)
+	showIds := []string{}
+	for _, item := range shows {
+		showIds = append(showIds, item.Show.Ids.IMDB)
+	}
+	assert.Equal(t, []string{"tt0000011"}, showIds)
+}
+
+func TestSetSimklWatchedMovie(t *testing.T) {
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name          string
+		lastWatched   time.Time
+		lastWatchedAt *time.Time
+		expected      time.Time
+	}{
+		{"completed without last watched at", time.Time{}, nil, time.Time{}},
+		{"newer last watched at", older, &newer, newer},
+		{"older last watched at", newer, &older, newer},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			libraryItem := stremio_api.LibraryItem{State: stremio_api.LibraryItemState{LastWatched: tc.lastWatched}}
+			setSimklWatchedMovie(&libraryItem, &simkl.AllItemsItem{
+				Status:        simkl.ListStatusCompleted,
+				LastWatchedAt: tc.lastWatchedAt,
+			})
+			assert.Equal(t, 1, libraryItem.State.TimesWatched)
+			assert.Equal(t, tc.expected, libraryItem.State.LastWatched)
+		})
+	}
+}
+
+func TestSimklWatchedEpisodes(t *testing.T) {
+	imdbId := "tt0903747"
+	videoIds := []string{
+		imdbId + ":0:1",
+		imdbId + ":1:1",
+		imdbId + ":1:2",
+		imdbId + ":2:1",
+	}
+
+	t.Run("simkl to stremio", func(t *testing.T) {
+		watchedAt1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		watchedAt2 := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+		wbf := stremio_watched_bitfield.NewWatchedBitField(stremio_watched_bitfield.NewBitField8(len(videoIds)), videoIds)
+		wbf.SetVideo(imdbId+":1:1", true)
+
+		changed, lastWatched := setSimklWatchedEpisodes(wbf, imdbId, []simkl.WatchedSeason{
+			{Number: 1, Episodes: []simkl.WatchedEpisode{
+				{Number: 1, WatchedAt: &watchedAt2},
+				{Number: 2, WatchedAt: &watchedAt1},
+			}},
+			{Number: 2, Episodes: []simkl.WatchedEpisode{
+				{Number: 1},
+			}},
+		})
+		assert.True(t, changed)
+		// already watched episode does not count
+		assert.Equal(t, watchedAt1, lastWatched)
+		assert.False(t, wbf.GetVideo(imdbId+":0:1"))
+		assert.True(t, wbf.GetVideo(imdbId+":1:1"))
+		assert.True(t, wbf.GetVideo(imdbId+":1:2"))
+		assert.True(t, wbf.GetVideo(imdbId+":2:1"))
+
+		changed, lastWatched = setSimklWatchedEpisodes(wbf, imdbId, []simkl.WatchedSeason{
+			{Number: 1, Episodes: []simkl.WatchedEpisode{{Number: 2, WatchedAt: &watchedAt2}}},
+		})
+		assert.False(t, changed)
+		assert.True(t, lastWatched.IsZero())
+	})
+
+	t.Run("stremio to simkl", func(t *testing.T) {
+		wbf := stremio_watched_bitfield.NewWatchedBitField(stremio_watched_bitfield.NewBitField8(len(videoIds)), videoIds)
+		for _, videoId := range videoIds {
+			wbf.SetVideo(videoId, true)
+		}
+
+		episodesBySeason := getStremioWatchedEpisodesForSimkl(wbf, videoIds, nil)
+		assert.Equal(t, map[int][]simkl.WatchedEpisode{
+			1: {{Number: 1}, {Number: 2}},
+			2: {{Number: 1}},
+		}, episodesBySeason)
+
+		simklWatched := util.NewSet[string]()
+		simklWatched.Add("1:1")
+		simklWatched.Add("2:1")
+		episodesBySeason = getStremioWatchedEpisodesForSimkl(wbf, videoIds, simklWatched)
+		assert.Equal(t, map[int][]simkl.WatchedEpisode{
+			1: {{Number: 2}},
+		}, episodesBySeason)
+
+		wbf.SetVideo(imdbId+":1:2", false)
+		episodesBySeason = getStremioWatchedEpisodesForSimkl(wbf, videoIds, simklWatched)
+		assert.Empty(t, episodesBySeason)
+	})
+}
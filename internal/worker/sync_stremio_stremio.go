@@ -14,6 +14,36 @@ import (
 	stremio_watched_bitfield "github.com/MunifTanjim/stremthru/stremio/watched_bitfield"
 )
 
+func getStremioStremioGroupMembers(group *sync_stremio_stremio.SyncStremioStremioGroup) ([]sync_stremio_stremio.GroupMember, error) {
+	members := make([]sync_stremio_stremio.GroupMember, len(group.AccountIds))
+	for i, accountId := range group.AccountIds {
+		account, err := stremio_account.GetById(accountId)
+		if err != nil || account == nil {
+			return nil, fmt.Errorf("account %s not found: %w", accountId, err)
+		}
+		token, err := account.GetValidToken()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get valid token for account %s: %w", accountId, err)
+		}
+		members[i] = sync_stremio_stremio.GroupMember{AccountId: accountId, Token: token}
+	}
+	return members, nil
+}
+
+// PlanStremioStremioGroupSync returns the changes the next sync of the group
+// would make, without applying them.
+func PlanStremioStremioGroupSync(group *sync_stremio_stremio.SyncStremioStremioGroup) (*sync_stremio_stremio.GroupSyncPlan, error) {
+	members, err := getStremioStremioGroupMembers(group)
+	if err != nil {
+		return nil, err
+	}
+	var startAt time.Time
+	if group.SyncState.Watched.LastSyncedAt != nil {
+		startAt = *group.SyncState.Watched.LastSyncedAt
+	}
+	return sync_stremio_stremio.PlanGroupSync(members, group.SyncConfig.Watched.Ids, startAt, time.Now())
+}
+
 func InitSyncStremioStremioWorker(conf *WorkerConfig) *Worker {
 	type Ctx struct {
 		now        time.Time
@@ -345,6 +375,38 @@ func InitSyncStremioStremioWorker(conf *WorkerConfig) *Worker {
 		return nil
 	}
 
+	syncGroupWatched := func(group *sync_stremio_stremio.SyncStremioStremioGroup, log *logger.Logger) error {
+		log = log.With("group_id", group.Id)
+
+		members, err := getStremioStremioGroupMembers(group)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		var startAt time.Time
+		if group.SyncState.Watched.LastSyncedAt != nil {
+			startAt = *group.SyncState.Watched.LastSyncedAt
+		}
+
+		log.Debug("starting group watched sync", "is_full_sync", startAt.IsZero(), "start_at", startAt, "account_count", len(members))
+
+		plan, err := sync_stremio_stremio.PlanGroupSync(members, group.SyncConfig.Watched.Ids, startAt, now)
+		if err != nil {
+			return err
+		}
+
+		if err := sync_stremio_stremio.ApplyGroupSyncPlan(members, plan); err != nil {
+			return err
+		}
+
+		log.Debug("synced group", "count", plan.ChangeCount())
+
+		group.SyncState.Watched.LastSyncedAt = &now
+		return sync_stremio_stremio.SetGroupSyncState(group.Id, group.SyncState)
+	}
+
 	conf.Executor = func(w *Worker) error {
 		log := w.Log
 
@@ -354,6 +416,9 @@ func InitSyncStremioStremioWorker(conf *WorkerConfig) *Worker {
 		}
 
 		for _, link := range links {
+			if err := w.Err(); err != nil {
+				return err
+			}
 			if !link.SyncConfig.Watched.Direction.IsDisabled() {
 				if err := syncWatched(&link, log); err != nil {
 					log.Error("failed to sync link", "error", err,
@@ -364,6 +429,22 @@ func InitSyncStremioStremioWorker(conf *WorkerConfig) *Worker {
 			}
 		}
 
+		groups, err := sync_stremio_stremio.GetAllGroups()
+		if err != nil {
+			return err
+		}
+
+		for _, group := range groups {
+			if err := w.Err(); err != nil {
+				return err
+			}
+			if group.SyncConfig.Watched.Enabled {
+				if err := syncGroupWatched(&group, log); err != nil {
+					log.Error("failed to sync group", "error", err, "group_id", group.Id)
+				}
+			}
+		}
+
 		return nil
 	}
 	return NewWorker(conf)
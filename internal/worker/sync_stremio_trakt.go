@@ -2,6 +2,7 @@ package worker
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -19,6 +20,37 @@ import (
 	stremio_watched_bitfield "github.com/MunifTanjim/stremthru/stremio/watched_bitfield"
 )
 
+// loved maps to the highest rating, liked to a positive one
+func likeStatusToTraktRating(status stremio_api.LikeStatus) int {
+	switch status {
+	case stremio_api.LikeStatusLoved:
+		return 10
+	case stremio_api.LikeStatusLiked:
+		return 8
+	default:
+		return 0
+	}
+}
+
+func traktRatingToLikeStatus(rating int) stremio_api.LikeStatus {
+	switch {
+	case rating >= 9:
+		return stremio_api.LikeStatusLoved
+	case rating >= 7:
+		return stremio_api.LikeStatusLiked
+	default:
+		return stremio_api.LikeStatusNone
+	}
+}
+
+// stremio likes do not bump the library item, so the like status of the whole
+// library is rescanned at this interval.
+const stremioLikesRescanInterval = 24 * time.Hour
+
+func shouldRescanStremioLikes(lastScannedAt *time.Time, now time.Time) bool {
+	return lastScannedAt == nil || !now.Before(lastScannedAt.Add(stremioLikesRescanInterval))
+}
+
 func InitSyncStremioTraktWorker(conf *WorkerConfig) *Worker {
 	type Ctx struct {
 		now        time.Time
@@ -508,12 +540,7 @@ func InitSyncStremioTraktWorker(conf *WorkerConfig) *Worker {
 		return nil
 	}
 
-	syncWatched := func(link *sync_stremio_trakt.SyncStremioTraktLink, log *logger.Logger) error {
-		log = log.With(
-			"stremio_account_id", link.StremioAccountId,
-			"trakt_account_id", link.TraktAccountId,
-		)
-
+	initCtx := func(link *sync_stremio_trakt.SyncStremioTraktLink, log *logger.Logger) (*Ctx, error) {
 		ctx := &Ctx{
 			log:  log,
 			link: link,
@@ -521,19 +548,19 @@ func InitSyncStremioTraktWorker(conf *WorkerConfig) *Worker {
 
 		stremioAccount, err := stremio_account.GetById(link.StremioAccountId)
 		if err != nil || stremioAccount == nil {
-			return fmt.Errorf("stremio account not found: %w", err)
+			return nil, fmt.Errorf("stremio account not found: %w", err)
 		}
 		ctx.stremioAccount = stremioAccount
 
 		traktAccount, err := trakt_account.GetById(link.TraktAccountId)
 		if err != nil || traktAccount == nil {
-			return fmt.Errorf("trakt account not found: %w", err)
+			return nil, fmt.Errorf("trakt account not found: %w", err)
 		}
 		ctx.traktAccount = traktAccount
 
 		stremioToken, err := stremioAccount.GetValidToken()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		ctx.stremioToken = stremioToken
 
@@ -543,20 +570,17 @@ func InitSyncStremioTraktWorker(conf *WorkerConfig) *Worker {
 
 		ctx.now = time.Now()
 
-		var startAt time.Time
-		if link.SyncState.Watched.LastSyncedAt != nil {
-			startAt = *link.SyncState.Watched.LastSyncedAt
-		}
-
-		ctx.isFullSync = startAt.IsZero()
-
-		log.Debug("starting watched sync", "is_full_sync", ctx.isFullSync, "start_at", startAt)
+		return ctx, nil
+	}
 
+	// returns library items (imdb ids only) modified after startAt, or all for zero startAt
+	getStremioLibraryItems := func(ctx *Ctx, startAt time.Time) ([]stremio_api.LibraryItem, error) {
+		isFullSync := startAt.IsZero()
 		var stremioItemIds []string
-		if !ctx.isFullSync {
-			tsRes, err := ctx.stremioClient.GetAllLibraryItemTimestamps(&stremio_api.GetAllLibraryItemTimestampsParams{Ctx: stremio_api.Ctx{APIKey: stremioToken}})
+		if !isFullSync {
+			tsRes, err := ctx.stremioClient.GetAllLibraryItemTimestamps(&stremio_api.GetAllLibraryItemTimestampsParams{Ctx: stremio_api.Ctx{APIKey: ctx.stremioToken}})
 			if err != nil {
-				return err
+				return nil, err
 			}
 			for _, ts := range tsRes.Data {
 				if !strings.HasPrefix(ts.Id, "tt") {
@@ -568,24 +592,79 @@ func InitSyncStremioTraktWorker(conf *WorkerConfig) *Worker {
 			}
 		}
 
-		if ctx.isFullSync || len(stremioItemIds) > 0 {
+		items := []stremio_api.LibraryItem{}
+		if isFullSync || len(stremioItemIds) > 0 {
 			stremioLibItemsRes, err := ctx.stremioClient.GetAllLibraryItems(&stremio_api.GetAllLibraryItemsParams{
-				Ctx: stremio_api.Ctx{APIKey: stremioToken},
+				Ctx: stremio_api.Ctx{APIKey: ctx.stremioToken},
 				Ids: stremioItemIds,
 			})
 			if err != nil {
-				return err
+				return nil, err
 			}
 			for _, item := range stremioLibItemsRes.Data {
 				if !strings.HasPrefix(item.Id, "tt") {
 					continue
 				}
-				switch item.Type {
-				case "movie":
-					ctx.stremioMovies = append(ctx.stremioMovies, item)
-				case "series":
-					ctx.stremioSeries = append(ctx.stremioSeries, item)
-				}
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	}
+
+	// fetches library items missing in itemById, i.e. not modified since last sync
+	fetchMissingStremioLibraryItems := func(ctx *Ctx, itemById map[string]stremio_api.LibraryItem, ids []string) error {
+		var idsToFetch []string
+		for _, id := range ids {
+			if _, exists := itemById[id]; !exists {
+				idsToFetch = append(idsToFetch, id)
+			}
+		}
+		if len(idsToFetch) == 0 {
+			return nil
+		}
+		res, err := ctx.stremioClient.GetAllLibraryItems(&stremio_api.GetAllLibraryItemsParams{
+			Ctx: stremio_api.Ctx{APIKey: ctx.stremioToken},
+			Ids: idsToFetch,
+		})
+		if err != nil {
+			return err
+		}
+		for _, item := range res.Data {
+			itemById[item.Id] = item
+		}
+		return nil
+	}
+
+	syncWatched := func(link *sync_stremio_trakt.SyncStremioTraktLink, log *logger.Logger) error {
+		log = log.With(
+			"stremio_account_id", link.StremioAccountId,
+			"trakt_account_id", link.TraktAccountId,
+		)
+
+		ctx, err := initCtx(link, log)
+		if err != nil {
+			return err
+		}
+
+		var startAt time.Time
+		if link.SyncState.Watched.LastSyncedAt != nil {
+			startAt = *link.SyncState.Watched.LastSyncedAt
+		}
+
+		ctx.isFullSync = startAt.IsZero()
+
+		log.Debug("starting watched sync", "is_full_sync", ctx.isFullSync, "start_at", startAt)
+
+		stremioItems, err := getStremioLibraryItems(ctx, startAt)
+		if err != nil {
+			return err
+		}
+		for _, item := range stremioItems {
+			switch item.Type {
+			case "movie":
+				ctx.stremioMovies = append(ctx.stremioMovies, item)
+			case "series":
+				ctx.stremioSeries = append(ctx.stremioSeries, item)
 			}
 		}
 
@@ -643,6 +722,480 @@ func InitSyncStremioTraktWorker(conf *WorkerConfig) *Worker {
 		return nil
 	}
 
+	syncWatchlist := func(link *sync_stremio_trakt.SyncStremioTraktLink, log *logger.Logger) error {
+		log = log.With(
+			"stremio_account_id", link.StremioAccountId,
+			"trakt_account_id", link.TraktAccountId,
+		)
+
+		ctx, err := initCtx(link, log)
+		if err != nil {
+			return err
+		}
+
+		var startAt time.Time
+		if link.SyncState.Watchlist.LastSyncedAt != nil {
+			startAt = *link.SyncState.Watchlist.LastSyncedAt
+		}
+
+		ctx.isFullSync = startAt.IsZero()
+
+		log.Debug("starting watchlist sync", "is_full_sync", ctx.isFullSync, "start_at", startAt)
+
+		stremioItems, err := getStremioLibraryItems(ctx, startAt)
+		if err != nil {
+			return err
+		}
+
+		traktRes, err := ctx.traktClient.GetWatchlist(&trakt.GetWatchlistParams{})
+		if err != nil {
+			return err
+		}
+		traktItemByImdbId := map[string]trakt.ListItem{}
+		for _, item := range traktRes.Data {
+			switch item.Type {
+			case trakt.ItemTypeMovie:
+				if item.Movie != nil && item.Movie.Ids.IMDB != "" {
+					traktItemByImdbId[item.Movie.Ids.IMDB] = item
+				}
+			case trakt.ItemTypeShow:
+				if item.Show != nil && item.Show.Ids.IMDB != "" {
+					traktItemByImdbId[item.Show.Ids.IMDB] = item
+				}
+			}
+		}
+
+		log.Debug("fetched items", "stremio_items", len(stremioItems), "trakt_items", len(traktItemByImdbId))
+
+		// stremio library items not yet watched are considered watchlisted
+		isWatchlisted := func(item *stremio_api.LibraryItem) bool {
+			return !item.Removed && !item.Temp && item.State.TimesWatched == 0 && item.State.Watched == ""
+		}
+
+		if link.SyncConfig.Watchlist.Direction.ShouldSyncToTrakt() {
+			params := &trakt.AddToWatchlistParams{}
+			for i := range stremioItems {
+				item := &stremioItems[i]
+				if !isWatchlisted(item) {
+					continue
+				}
+				if _, ok := traktItemByImdbId[item.Id]; ok {
+					continue
+				}
+				switch item.Type {
+				case "movie":
+					params.Movies = append(params.Movies, trakt.SyncWatchlistParamsItem{Ids: trakt.ListItemIds{IMDB: item.Id}})
+				case "series":
+					params.Shows = append(params.Shows, trakt.SyncWatchlistParamsItem{Ids: trakt.ListItemIds{IMDB: item.Id}})
+				}
+			}
+			if len(params.Movies) > 0 || len(params.Shows) > 0 {
+				if _, err := ctx.traktClient.AddToWatchlist(params); err != nil {
+					log.Error("failed to sync watchlist from stremio to trakt", "error", err)
+					return err
+				}
+				log.Debug("synced watchlist from stremio to trakt", "movies", len(params.Movies), "shows", len(params.Shows))
+			}
+		}
+
+		if link.SyncConfig.Watchlist.Direction.ShouldSyncToStremio() {
+			stremioItemById := map[string]stremio_api.LibraryItem{}
+			for _, item := range stremioItems {
+				stremioItemById[item.Id] = item
+			}
+
+			traktImdbIds := []string{}
+			for imdbId, item := range traktItemByImdbId {
+				if ctx.isFullSync || item.ListedAt.After(startAt) {
+					traktImdbIds = append(traktImdbIds, imdbId)
+				}
+			}
+
+			if !ctx.isFullSync {
+				if err := fetchMissingStremioLibraryItems(ctx, stremioItemById, traktImdbIds); err != nil {
+					return err
+				}
+			}
+
+			var itemsToUpdate []stremio_api.LibraryItem
+			for _, imdbId := range traktImdbIds {
+				libraryItem, exists := stremioItemById[imdbId]
+				if exists {
+					if !libraryItem.Removed {
+						continue
+					}
+					libraryItem.Removed = false
+					libraryItem.MTime = stremio_api.JSONTime{Time: ctx.now}
+				} else {
+					metaType := "movie"
+					if traktItemByImdbId[imdbId].Type == trakt.ItemTypeShow {
+						metaType = "series"
+					}
+					meta, err := cinemeta.FetchMeta(metaType, imdbId)
+					if err != nil {
+						log.Warn("failed to fetch meta", "error", err, "id", imdbId)
+						continue
+					}
+					libraryItem = createLibraryItem(ctx, meta, stremio_api.LibraryItemState{})
+				}
+				itemsToUpdate = append(itemsToUpdate, libraryItem)
+			}
+
+			if len(itemsToUpdate) > 0 {
+				if _, err := ctx.stremioClient.UpdateLibraryItems(&stremio_api.UpdateLibraryItemsParams{
+					Ctx:     stremio_api.Ctx{APIKey: ctx.stremioToken},
+					Changes: itemsToUpdate,
+				}); err != nil {
+					log.Error("failed to sync watchlist from trakt to stremio", "error", err)
+					return err
+				}
+				log.Debug("synced watchlist from trakt to stremio", "count", len(itemsToUpdate))
+			}
+		}
+
+		link.SyncState.Watchlist.LastSyncedAt = &ctx.now
+		sync_stremio_trakt.SetSyncState(link.StremioAccountId, link.TraktAccountId, link.SyncState)
+		return nil
+	}
+
+	syncRatings := func(link *sync_stremio_trakt.SyncStremioTraktLink, log *logger.Logger) error {
+		log = log.With(
+			"stremio_account_id", link.StremioAccountId,
+			"trakt_account_id", link.TraktAccountId,
+		)
+
+		ctx, err := initCtx(link, log)
+		if err != nil {
+			return err
+		}
+
+		var startAt time.Time
+		if link.SyncState.Ratings.LastSyncedAt != nil {
+			startAt = *link.SyncState.Ratings.LastSyncedAt
+		}
+
+		ctx.isFullSync = startAt.IsZero()
+
+		log.Debug("starting ratings sync", "is_full_sync", ctx.isFullSync, "start_at", startAt)
+
+		traktRes, err := ctx.traktClient.GetRatings(&trakt.GetRatingsParams{})
+		if err != nil {
+			return err
+		}
+		traktItemByImdbId := map[string]trakt.RatingItem{}
+		for _, item := range traktRes.Data {
+			switch item.Type {
+			case trakt.ItemTypeMovie:
+				if item.Movie != nil && item.Movie.Ids.IMDB != "" {
+					traktItemByImdbId[item.Movie.Ids.IMDB] = item
+				}
+			case trakt.ItemTypeShow:
+				if item.Show != nil && item.Show.Ids.IMDB != "" {
+					traktItemByImdbId[item.Show.Ids.IMDB] = item
+				}
+			}
+		}
+
+		if link.SyncConfig.Ratings.Direction.ShouldSyncToTrakt() {
+			// likes do not bump the library item, so in between the periodic
+			// rescan of the whole library, only items modified since last sync
+			// are picked up.
+			itemsStartAt := startAt
+			isLikesRescan := shouldRescanStremioLikes(link.SyncState.Ratings.LastLikesScannedAt, ctx.now)
+			if isLikesRescan {
+				itemsStartAt = time.Time{}
+			}
+			log.Debug("checking stremio likes", "is_rescan", isLikesRescan, "start_at", itemsStartAt)
+			stremioItems, err := getStremioLibraryItems(ctx, itemsStartAt)
+			if err != nil {
+				return err
+			}
+
+			params := &trakt.AddRatingsParams{}
+			for _, item := range stremioItems {
+				if item.Temp {
+					continue
+				}
+				if _, ok := traktItemByImdbId[item.Id]; ok {
+					continue
+				}
+				res, err := ctx.stremioClient.GetLikeStatus(&stremio_api.GetLikeStatusParams{
+					Ctx:       stremio_api.Ctx{APIKey: ctx.stremioToken},
+					MediaId:   item.Id,
+					MediaType: item.Type,
+				})
+				if err != nil {
+					log.Warn("failed to get like status", "error", err, "id", item.Id)
+					continue
+				}
+				rating := likeStatusToTraktRating(res.Data.Status)
+				if rating == 0 {
+					continue
+				}
+				ratingItem := trakt.SyncRatingsParamsItem{
+					Rating: rating,
+					Ids:    trakt.ListItemIds{IMDB: item.Id},
+				}
+				switch item.Type {
+				case "movie":
+					params.Movies = append(params.Movies, ratingItem)
+				case "series":
+					params.Shows = append(params.Shows, ratingItem)
+				}
+			}
+			if len(params.Movies) > 0 || len(params.Shows) > 0 {
+				if _, err := ctx.traktClient.AddRatings(params); err != nil {
+					log.Error("failed to sync ratings from stremio to trakt", "error", err)
+					return err
+				}
+				log.Debug("synced ratings from stremio to trakt", "movies", len(params.Movies), "shows", len(params.Shows))
+			}
+			if isLikesRescan {
+				link.SyncState.Ratings.LastLikesScannedAt = &ctx.now
+			}
+		}
+
+		if link.SyncConfig.Ratings.Direction.ShouldSyncToStremio() {
+			count := 0
+			for imdbId, item := range traktItemByImdbId {
+				if !ctx.isFullSync && !item.RatedAt.After(startAt) {
+					continue
+				}
+				status := traktRatingToLikeStatus(item.Rating)
+				if status == stremio_api.LikeStatusNone {
+					continue
+				}
+				mediaType := "movie"
+				if item.Type == trakt.ItemTypeShow {
+					mediaType = "series"
+				}
+				res, err := ctx.stremioClient.GetLikeStatus(&stremio_api.GetLikeStatusParams{
+					Ctx:       stremio_api.Ctx{APIKey: ctx.stremioToken},
+					MediaId:   imdbId,
+					MediaType: mediaType,
+				})
+				if err != nil {
+					log.Warn("failed to get like status", "error", err, "id", imdbId)
+					continue
+				}
+				if res.Data.Status != stremio_api.LikeStatusNone {
+					continue
+				}
+				if _, err := ctx.stremioClient.SendLikeStatus(&stremio_api.SendLikeStatusParams{
+					Ctx:       stremio_api.Ctx{APIKey: ctx.stremioToken},
+					MediaId:   imdbId,
+					MediaType: mediaType,
+					Status:    status,
+				}); err != nil {
+					log.Error("failed to sync rating from trakt to stremio", "error", err, "id", imdbId)
+					return err
+				}
+				count++
+			}
+			if count > 0 {
+				log.Debug("synced ratings from trakt to stremio", "count", count)
+			}
+		}
+
+		link.SyncState.Ratings.LastSyncedAt = &ctx.now
+		sync_stremio_trakt.SetSyncState(link.StremioAccountId, link.TraktAccountId, link.SyncState)
+		return nil
+	}
+
+	syncProgress := func(link *sync_stremio_trakt.SyncStremioTraktLink, log *logger.Logger) error {
+		log = log.With(
+			"stremio_account_id", link.StremioAccountId,
+			"trakt_account_id", link.TraktAccountId,
+		)
+
+		ctx, err := initCtx(link, log)
+		if err != nil {
+			return err
+		}
+
+		var startAt time.Time
+		if link.SyncState.Progress.LastSyncedAt != nil {
+			startAt = *link.SyncState.Progress.LastSyncedAt
+		}
+
+		ctx.isFullSync = startAt.IsZero()
+
+		log.Debug("starting progress sync", "is_full_sync", ctx.isFullSync, "start_at", startAt)
+
+		stremioItems, err := getStremioLibraryItems(ctx, startAt)
+		if err != nil {
+			return err
+		}
+
+		traktParams := &trakt.GetPlaybackProgressParams{Extended: "full"}
+		if !ctx.isFullSync {
+			traktParams.StartAt = &startAt
+		}
+		traktRes, err := ctx.traktClient.GetPlaybackProgress(traktParams)
+		if err != nil {
+			return err
+		}
+		// keyed by stremio video id, i.e. `tt1234567` or `tt1234567:1:2`
+		traktItemByVideoId := map[string]trakt.PlaybackItem{}
+		for _, item := range traktRes.Data {
+			switch item.Type {
+			case trakt.ItemTypeMovie:
+				if item.Movie != nil && item.Movie.Ids.IMDB != "" {
+					traktItemByVideoId[item.Movie.Ids.IMDB] = item
+				}
+			case trakt.ItemTypeEpisode:
+				if item.Show != nil && item.Episode != nil && item.Show.Ids.IMDB != "" {
+					traktItemByVideoId[fmt.Sprintf("%s:%d:%d", item.Show.Ids.IMDB, item.Episode.Season, item.Episode.Number)] = item
+				}
+			}
+		}
+
+		log.Debug("fetched items", "stremio_items", len(stremioItems), "trakt_items", len(traktItemByVideoId))
+
+		if link.SyncConfig.Progress.Direction.ShouldSyncToTrakt() {
+			count := 0
+			for _, item := range stremioItems {
+				if item.Removed || item.Temp || item.State.TimeOffset <= 0 || item.State.Duration <= 0 {
+					continue
+				}
+				videoId := item.Id
+				if item.Type == "series" {
+					videoId = item.State.VideoId
+				}
+				params := &trakt.ScrobbleParams{Action: trakt.ScrobbleActionPause}
+				parts := strings.Split(videoId, ":")
+				switch {
+				case item.Type == "movie" && len(parts) == 1:
+					params.Movie = &trakt.ScrobbleItem{Ids: trakt.ScrobbleIds{IMDB: parts[0]}}
+				case item.Type == "series" && len(parts) == 3:
+					season, episode := util.SafeParseInt(parts[1], -1), util.SafeParseInt(parts[2], -1)
+					if season < 0 || episode < 0 {
+						continue
+					}
+					params.Show = &trakt.ScrobbleItem{Ids: trakt.ScrobbleIds{IMDB: parts[0]}}
+					params.Episode = &trakt.ScrobbleEpisode{Season: season, Number: episode}
+				default:
+					continue
+				}
+				params.Progress = min(100, float64(item.State.TimeOffset)*100/float64(item.State.Duration))
+				if traktItem, ok := traktItemByVideoId[videoId]; ok {
+					if traktItem.PausedAt.After(item.MTime.Time) || math.Abs(traktItem.Progress-params.Progress) < 1 {
+						continue
+					}
+				}
+				if _, err := ctx.traktClient.Scrobble(params); err != nil {
+					log.Error("failed to sync progress from stremio to trakt", "error", err, "id", videoId)
+					return err
+				}
+				count++
+			}
+			if count > 0 {
+				log.Debug("synced progress from stremio to trakt", "count", count)
+			}
+		}
+
+		if link.SyncConfig.Progress.Direction.ShouldSyncToStremio() {
+			stremioItemById := map[string]stremio_api.LibraryItem{}
+			for _, item := range stremioItems {
+				stremioItemById[item.Id] = item
+			}
+
+			// playback progress is only kept for the latest video of a series
+			traktItemByImdbId := map[string]trakt.PlaybackItem{}
+			for videoId, item := range traktItemByVideoId {
+				imdbId, _, _ := strings.Cut(videoId, ":")
+				if existing, ok := traktItemByImdbId[imdbId]; !ok || item.PausedAt.After(existing.PausedAt) {
+					traktItemByImdbId[imdbId] = item
+				}
+			}
+
+			if !ctx.isFullSync {
+				imdbIds := make([]string, 0, len(traktItemByImdbId))
+				for imdbId := range traktItemByImdbId {
+					imdbIds = append(imdbIds, imdbId)
+				}
+				if err := fetchMissingStremioLibraryItems(ctx, stremioItemById, imdbIds); err != nil {
+					return err
+				}
+			}
+
+			var itemsToUpdate []stremio_api.LibraryItem
+			for imdbId, traktItem := range traktItemByImdbId {
+				videoId := imdbId
+				runtime := 0
+				if traktItem.Type == trakt.ItemTypeEpisode {
+					videoId = fmt.Sprintf("%s:%d:%d", imdbId, traktItem.Episode.Season, traktItem.Episode.Number)
+					runtime = traktItem.Episode.Runtime
+				} else {
+					runtime = traktItem.Movie.Runtime
+				}
+
+				libraryItem, exists := stremioItemById[imdbId]
+				if exists {
+					if libraryItem.MTime.After(traktItem.PausedAt) {
+						continue
+					}
+					libraryItem.Removed = false
+					libraryItem.MTime = stremio_api.JSONTime{Time: ctx.now}
+				} else {
+					metaType := "movie"
+					if traktItem.Type == trakt.ItemTypeEpisode {
+						metaType = "series"
+					}
+					meta, err := cinemeta.FetchMeta(metaType, imdbId)
+					if err != nil {
+						log.Warn("failed to fetch meta", "error", err, "id", imdbId)
+						continue
+					}
+					libraryItem = createLibraryItem(ctx, meta, stremio_api.LibraryItemState{})
+				}
+
+				duration := 0
+				if libraryItem.Type == "movie" || libraryItem.State.VideoId == videoId {
+					duration = libraryItem.State.Duration
+				}
+				if duration <= 0 {
+					duration = runtime * 60 * 1000
+				}
+				if duration <= 0 {
+					continue
+				}
+
+				timeOffset := int(traktItem.Progress * float64(duration) / 100)
+				if libraryItem.State.VideoId == videoId && libraryItem.State.TimeOffset == timeOffset {
+					continue
+				}
+
+				libraryItem.State.VideoId = videoId
+				if traktItem.Type == trakt.ItemTypeEpisode {
+					libraryItem.State.Season = traktItem.Episode.Season
+					libraryItem.State.Episode = traktItem.Episode.Number
+				}
+				libraryItem.State.Duration = duration
+				libraryItem.State.TimeOffset = timeOffset
+				if traktItem.PausedAt.After(libraryItem.State.LastWatched) {
+					libraryItem.State.LastWatched = traktItem.PausedAt
+				}
+				itemsToUpdate = append(itemsToUpdate, libraryItem)
+			}
+
+			if len(itemsToUpdate) > 0 {
+				if _, err := ctx.stremioClient.UpdateLibraryItems(&stremio_api.UpdateLibraryItemsParams{
+					Ctx:     stremio_api.Ctx{APIKey: ctx.stremioToken},
+					Changes: itemsToUpdate,
+				}); err != nil {
+					log.Error("failed to sync progress from trakt to stremio", "error", err)
+					return err
+				}
+				log.Debug("synced progress from trakt to stremio", "count", len(itemsToUpdate))
+			}
+		}
+
+		link.SyncState.Progress.LastSyncedAt = &ctx.now
+		sync_stremio_trakt.SetSyncState(link.StremioAccountId, link.TraktAccountId, link.SyncState)
+		return nil
+	}
+
 	conf.Executor = func(w *Worker) error {
 		log := w.Log
 
@@ -652,12 +1205,33 @@ func InitSyncStremioTraktWorker(conf *WorkerConfig) *Worker {
 		}
 
 		for _, link := range links {
+			if err := w.Err(); err != nil {
+				return err
+			}
 			if !link.SyncConfig.Watched.Direction.IsDisabled() {
 				err := syncWatched(&link, log)
 				if err != nil {
 					return err
 				}
 			}
+			if !link.SyncConfig.Watchlist.Direction.IsDisabled() {
+				err := syncWatchlist(&link, log)
+				if err != nil {
+					return err
+				}
+			}
+			if !link.SyncConfig.Ratings.Direction.IsDisabled() {
+				err := syncRatings(&link, log)
+				if err != nil {
+					return err
+				}
+			}
+			if !link.SyncConfig.Progress.Direction.IsDisabled() {
+				err := syncProgress(&link, log)
+				if err != nil {
+					return err
+				}
+			}
 		}
 
 		return nil
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	stremio_api "github.com/MunifTanjim/stremthru/internal/stremio/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraktRatingLikeStatus(t *testing.T) {
+	for _, tc := range []struct {
+		rating int
+		status stremio_api.LikeStatus
+	}{
+		{10, stremio_api.LikeStatusLoved},
+		{9, stremio_api.LikeStatusLoved},
+		{8, stremio_api.LikeStatusLiked},
+		{7, stremio_api.LikeStatusLiked},
+		{6, stremio_api.LikeStatusNone},
+		{1, stremio_api.LikeStatusNone},
+	} {
+		assert.Equal(t, tc.status, traktRatingToLikeStatus(tc.rating), "rating %d", tc.rating)
+	}
+
+	for _, status := range []stremio_api.LikeStatus{stremio_api.LikeStatusLoved, stremio_api.LikeStatusLiked} {
+		assert.Equal(t, status, traktRatingToLikeStatus(likeStatusToTraktRating(status)))
+	}
+	assert.Equal(t, 0, likeStatusToTraktRating(stremio_api.LikeStatusNone))
+}
+
+func TestShouldRescanStremioLikes(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	assert.True(t, shouldRescanStremioLikes(nil, now))
+	assert.False(t, shouldRescanStremioLikes(at(0), now))
+	assert.False(t, shouldRescanStremioLikes(at(stremioLikesRescanInterval-time.Minute), now))
+	assert.True(t, shouldRescanStremioLikes(at(stremioLikesRescanInterval), now))
+	assert.True(t, shouldRescanStremioLikes(at(2*stremioLikesRescanInterval), now))
+}
@@ -39,6 +39,9 @@ func InitParseTorrentWorker(conf *WorkerConfig) *Worker {
 			}
 
 			for cTInfos := range slices.Chunk(tInfos, 500) {
+				if err := w.Err(); err != nil {
+					return err
+				}
 				parsedTInfos := []*ti.TorrentInfo{}
 				for i := range cTInfos {
 					if t := parseTorrentInfo(w, &cTInfos[i]); t != nil {
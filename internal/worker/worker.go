@@ -1,13 +1,16 @@
 package worker
 
 import (
+	"context"
 	"sync"
 	"time"
 
 	"github.com/MunifTanjim/stremthru/internal/config"
 	"github.com/MunifTanjim/stremthru/internal/db"
 	"github.com/MunifTanjim/stremthru/internal/job_log"
+	"github.com/MunifTanjim/stremthru/internal/lease"
 	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/internal/metrics"
 	"github.com/MunifTanjim/stremthru/internal/util"
 	"github.com/MunifTanjim/stremthru/internal/worker/worker_queue"
 	"github.com/madflojo/tasks"
@@ -35,6 +38,22 @@ type Worker struct {
 	onEnd      func()
 	Log        *logger.Logger
 	jobTracker *JobTracker[struct{}]
+	ctx        context.Context
+}
+
+// Context is cancelled if the lease for an exclusive worker is lost, i.e.
+// another instance may take over. Executor should stop as soon as possible
+// after that.
+func (w *Worker) Context() context.Context {
+	if w.ctx == nil {
+		return context.Background()
+	}
+	return w.ctx
+}
+
+// Err is non-nil once Context is cancelled.
+func (w *Worker) Err() error {
+	return context.Cause(w.Context())
 }
 
 type WorkerConfig struct {
@@ -113,9 +132,15 @@ var WorkerDetailsById = map[string]*WorkerDetail{
 	"sync-stremio-trakt": {
 		Title: "Sync Stremio-Trakt",
 	},
+	"sync-stremio-simkl": {
+		Title: "Sync Stremio-Simkl",
+	},
 	"sync-stremio-stremio": {
 		Title: "Sync Stremio-Stremio",
 	},
+	"crawl-dht": {
+		Title: "Crawl DHT",
+	},
 }
 
 func NewWorker(conf *WorkerConfig) *Worker {
@@ -210,6 +235,21 @@ func NewWorker(conf *WorkerConfig) *Worker {
 				return nil
 			}
 
+			worker.ctx = context.Background()
+			if conf.RunExclusive {
+				l, err := lease.Acquire("worker:"+conf.Name, lease.GetTTL(conf.HeartbeatInterval), conf.HeartbeatInterval)
+				if err != nil {
+					log.Error("failed to acquire lease", "error", err)
+					return nil
+				}
+				if l == nil {
+					log.Debug("skipping, lease is held by another instance")
+					return nil
+				}
+				defer l.Release()
+				worker.ctx = l.Context()
+			}
+
 			lock := db.NewAdvisoryLock("worker", conf.Name)
 			if lock == nil {
 				log.Error("failed to create advisory lock", "name", conf.Name)
@@ -272,6 +312,7 @@ func NewWorker(conf *WorkerConfig) *Worker {
 			heartbeat := time.NewTicker(conf.HeartbeatInterval)
 			heartbeat_done := make(chan struct{})
 			defer close(heartbeat_done)
+			ctx := worker.Context()
 			go func() {
 				for {
 					select {
@@ -282,6 +323,9 @@ func NewWorker(conf *WorkerConfig) *Worker {
 						if err := jobTracker.Set(jobId, "started", "", nil); err != nil {
 							log.Error("failed to set job status heartbeat", "error", err, "jobId", jobId)
 						}
+					case <-ctx.Done():
+						heartbeat.Stop()
+						return
 					case <-heartbeat_done:
 						heartbeat.Stop()
 						return
@@ -289,7 +333,13 @@ func NewWorker(conf *WorkerConfig) *Worker {
 				}
 			}()
 
-			if err = conf.Executor(worker); err != nil {
+			startedAt := time.Now()
+			err = conf.Executor(worker)
+			if err == nil {
+				err = worker.Err()
+			}
+			metrics.ObserveWorkerRun(conf.Name, err, time.Since(startedAt))
+			if err != nil {
 				return err
 			}
 
@@ -773,6 +823,30 @@ func InitWorkers() func() {
 		workers = append(workers, worker)
 	}
 
+	if worker := InitCrawlDHTWorker(&WorkerConfig{
+		Disabled:          !config.Torz.DHTCrawlerEnabled || !config.Feature.HasTorrentInfo(),
+		Name:              "crawl-dht",
+		Interval:          60 * time.Minute,
+		RunAtStartupAfter: 90 * time.Second,
+		RunExclusive:      true,
+		ShouldWait: func() (bool, string) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if running_worker.sync_imdb {
+				return true, "sync_imdb is running"
+			}
+
+			if running_worker.sync_dmm_hashlist {
+				return true, "sync_dmm_hashlist is running"
+			}
+
+			return false, ""
+		},
+	}); worker != nil {
+		workers = append(workers, worker)
+	}
+
 	if worker := InitSyncAnimeToshoWorker(&WorkerConfig{
 		Disabled:          !config.Feature.IsEnabled("anime"),
 		Name:              "sync-animetosho",
@@ -841,6 +915,21 @@ func InitWorkers() func() {
 		workers = append(workers, worker)
 	}
 
+	if worker := InitSyncStremioSimklWorker(&WorkerConfig{
+		Disabled:          !config.Feature.HasVault() || !config.Integration.Simkl.IsEnabled(),
+		Name:              "sync-stremio-simkl",
+		Interval:          30 * time.Minute,
+		RunAtStartupAfter: 5 * time.Minute,
+		RunExclusive:      true,
+		ShouldWait: func() (bool, string) {
+			return false, ""
+		},
+		OnStart: func() {},
+		OnEnd:   func() {},
+	}); worker != nil {
+		workers = append(workers, worker)
+	}
+
 	if worker := InitSyncStremioStremioWorker(&WorkerConfig{
 		Disabled:          !config.Feature.HasVault(),
 		Name:              "sync-stremio-stremio",
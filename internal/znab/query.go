@@ -15,6 +15,7 @@ const (
 	SearchParamSeason   SearchParam = "season"
 	SearchParamYear     SearchParam = "year"
 	SearchParamIMDBId   SearchParam = "imdbid"
+	SearchParamTMDBId   SearchParam = "tmdbid"
 	SearchParamTVDBId   SearchParam = "tvdbid"
 	SearchParamTVMazeId SearchParam = "tvmazeid"
 	SearchParamTraktId  SearchParam = "traktid"
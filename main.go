@@ -53,6 +53,7 @@ func main() {
 	endpoint.AddDashEndpoint(mux)
 	endpoint.AddAuthEndpoints(mux)
 	endpoint.AddHealthEndpoints(mux)
+	endpoint.AddMetricsEndpoints(mux)
 	endpoint.AddMetaEndpoints(mux)
 	endpoint.AddProxyEndpoints(mux)
 	endpoint.AddStoreEndpoints(mux)
@@ -60,6 +61,8 @@ func main() {
 	endpoint.AddTorrentEndpoints(mux)
 	endpoint.AddTorznabEndpoints(mux)
 	endpoint.AddNewznabEndpoints(mux)
+	endpoint.AddSabnzbdEndpoints(mux)
+	endpoint.AddWebDAVEndpoints(mux)
 	endpoint.AddExperimentEndpoints(mux)
 	endpoint.AddEndpoints(mux)
 
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "public"."nzb_info" ADD COLUMN "category" text NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "public"."nzb_info" DROP COLUMN IF EXISTS "category";
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "public"."usenet_server"
+  ADD COLUMN "compression" boolean NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "public"."usenet_server"
+  DROP COLUMN IF EXISTS "compression";
+-- +goose StatementEnd
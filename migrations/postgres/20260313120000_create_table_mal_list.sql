@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "public"."mal_list" (
+    "id" text NOT NULL,
+    "user_name" text NOT NULL,
+    "status" text NOT NULL,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "public"."mal_item" (
+    "id" int NOT NULL,
+    "type" text NOT NULL,
+    "title" text NOT NULL,
+    "description" text NOT NULL,
+    "poster" text NOT NULL,
+    "start_year" int NOT NULL,
+    "is_adult" bool NOT NULL,
+    "genres" text NOT NULL,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "public"."mal_list_item" (
+  "list_id" text NOT NULL,
+  "item_id" int NOT NULL,
+  "idx" int NOT NULL,
+
+  PRIMARY KEY ("list_id", "item_id")
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "public"."mal_list_item";
+DROP TABLE IF EXISTS "public"."mal_item";
+DROP TABLE IF EXISTS "public"."mal_list";
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "public"."kitsu_list" (
+    "id" text NOT NULL,
+    "user_id" text NOT NULL,
+    "user_name" text NOT NULL,
+    "status" text NOT NULL,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "public"."kitsu_item" (
+    "id" int NOT NULL,
+    "type" text NOT NULL,
+    "title" text NOT NULL,
+    "description" text NOT NULL,
+    "poster" text NOT NULL,
+    "background" text NOT NULL,
+    "start_year" int NOT NULL,
+    "is_adult" bool NOT NULL,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "public"."kitsu_list_item" (
+  "list_id" text NOT NULL,
+  "item_id" int NOT NULL,
+  "idx" int NOT NULL,
+
+  PRIMARY KEY ("list_id", "item_id")
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "public"."kitsu_list_item";
+DROP TABLE IF EXISTS "public"."kitsu_item";
+DROP TABLE IF EXISTS "public"."kitsu_list";
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "public"."trakt_account"
+  ADD COLUMN "scrobble_config" jsonb NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "public"."trakt_account"
+  DROP COLUMN IF EXISTS "scrobble_config";
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "public"."sync_stremio_stremio_group" (
+  "id" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "account_ids" jsonb NOT NULL DEFAULT '[]',
+  "sync_config" jsonb NOT NULL DEFAULT '{"watched":{"enabled":false,"ids":[]}}',
+  "sync_state" jsonb NOT NULL DEFAULT '{}',
+  "cat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY ("id")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "public"."sync_stremio_stremio_group";
+-- +goose StatementEnd
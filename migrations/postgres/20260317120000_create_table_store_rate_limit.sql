@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "public"."store_rate_limit" (
+    "store_name" text NOT NULL,
+    "rate_limit_config_id" text NOT NULL,
+    "mode" text NOT NULL DEFAULT 'wait',
+    "max_wait" text NOT NULL DEFAULT '10s',
+    "cat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY ("store_name")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "public"."store_rate_limit";
+-- +goose StatementEnd
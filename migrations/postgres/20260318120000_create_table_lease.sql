@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "public"."lease" (
+    "name" text NOT NULL,
+    "holder" text NOT NULL,
+    "acquired_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "renewed_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "expires_at" timestamptz NOT NULL,
+
+    PRIMARY KEY ("name")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "public"."lease";
+-- +goose StatementEnd
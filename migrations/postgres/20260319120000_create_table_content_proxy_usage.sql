@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "public"."content_proxy_usage" (
+    "user_name" text NOT NULL,
+    "date" text NOT NULL,
+    "bytes" bigint NOT NULL DEFAULT 0,
+    "uat" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY ("user_name", "date")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "public"."content_proxy_usage";
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS "nzb_info_idx_user" ON "public"."nzb_info" ("user");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS "public"."nzb_info_idx_user";
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `nzb_info` ADD COLUMN `category` varchar NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `nzb_info` DROP COLUMN `category`;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `usenet_server`
+  ADD COLUMN `compression` bool NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `usenet_server`
+  DROP COLUMN `compression`;
+-- +goose StatementEnd
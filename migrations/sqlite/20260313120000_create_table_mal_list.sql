@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS `mal_list` (
+    `id` varchar NOT NULL,
+    `user_name` varchar NOT NULL,
+    `status` varchar NOT NULL,
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `mal_item` (
+    `id` int NOT NULL,
+    `type` varchar NOT NULL,
+    `title` varchar NOT NULL,
+    `description` varchar NOT NULL,
+    `poster` varchar NOT NULL,
+    `start_year` int NOT NULL,
+    `is_adult` bool NOT NULL,
+    `genres` varchar NOT NULL,
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `mal_list_item` (
+  `list_id` varchar NOT NULL,
+  `item_id` int NOT NULL,
+  `idx` int NOT NULL,
+
+  PRIMARY KEY (`list_id`, `item_id`)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `mal_list_item`;
+DROP TABLE IF EXISTS `mal_item`;
+DROP TABLE IF EXISTS `mal_list`;
+-- +goose StatementEnd
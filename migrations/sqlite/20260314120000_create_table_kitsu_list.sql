@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS `kitsu_list` (
+    `id` varchar NOT NULL,
+    `user_id` varchar NOT NULL,
+    `user_name` varchar NOT NULL,
+    `status` varchar NOT NULL,
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `kitsu_item` (
+    `id` int NOT NULL,
+    `type` varchar NOT NULL,
+    `title` varchar NOT NULL,
+    `description` varchar NOT NULL,
+    `poster` varchar NOT NULL,
+    `background` varchar NOT NULL,
+    `start_year` int NOT NULL,
+    `is_adult` bool NOT NULL,
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `kitsu_list_item` (
+  `list_id` varchar NOT NULL,
+  `item_id` int NOT NULL,
+  `idx` int NOT NULL,
+
+  PRIMARY KEY (`list_id`, `item_id`)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `kitsu_list_item`;
+DROP TABLE IF EXISTS `kitsu_item`;
+DROP TABLE IF EXISTS `kitsu_list`;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `trakt_account`
+  ADD COLUMN `scrobble_config` json NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `trakt_account`
+  DROP COLUMN `scrobble_config`;
+-- +goose StatementEnd
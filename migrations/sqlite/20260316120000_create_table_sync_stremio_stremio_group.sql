@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS `sync_stremio_stremio_group` (
+  `id` varchar NOT NULL,
+  `name` varchar NOT NULL,
+  `account_ids` json NOT NULL DEFAULT '[]',
+  `sync_config` json NOT NULL DEFAULT '{"watched":{"enabled":false,"ids":[]}}',
+  `sync_state` json NOT NULL DEFAULT '{}',
+  `cat` datetime NOT NULL DEFAULT (unixepoch()),
+  `uat` datetime NOT NULL DEFAULT (unixepoch()),
+
+  PRIMARY KEY (`id`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `sync_stremio_stremio_group`;
+-- +goose StatementEnd
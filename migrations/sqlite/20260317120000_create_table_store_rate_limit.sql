@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS `store_rate_limit` (
+    `store_name` varchar NOT NULL,
+    `rate_limit_config_id` varchar NOT NULL,
+    `mode` varchar NOT NULL DEFAULT 'wait',
+    `max_wait` varchar NOT NULL DEFAULT '10s',
+    `cat` datetime NOT NULL DEFAULT (unixepoch()),
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+
+    PRIMARY KEY (`store_name`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `store_rate_limit`;
+-- +goose StatementEnd
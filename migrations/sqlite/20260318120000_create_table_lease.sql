@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS `lease` (
+    `name` varchar NOT NULL,
+    `holder` varchar NOT NULL,
+    `acquired_at` datetime NOT NULL DEFAULT (unixepoch()),
+    `renewed_at` datetime NOT NULL DEFAULT (unixepoch()),
+    `expires_at` datetime NOT NULL,
+
+    PRIMARY KEY (`name`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `lease`;
+-- +goose StatementEnd
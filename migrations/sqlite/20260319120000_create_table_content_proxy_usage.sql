@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS `content_proxy_usage` (
+    `user_name` varchar NOT NULL,
+    `date` varchar NOT NULL,
+    `bytes` int NOT NULL DEFAULT 0,
+    `uat` datetime NOT NULL DEFAULT (unixepoch()),
+
+    PRIMARY KEY (`user_name`, `date`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `content_proxy_usage`;
+-- +goose StatementEnd
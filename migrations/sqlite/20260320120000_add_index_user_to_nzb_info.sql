@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS `nzb_info_idx_user` ON `nzb_info` (`user`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS `nzb_info_idx_user`;
+-- +goose StatementEnd
@@ -18,6 +18,7 @@ generate "429" "Too Many Requests" --indicator "!!!|"
 generate "451" "Unavailable For Legal Reasons" --indicator "!!!|"
 generate "500" "Something Went Wrong" --indicator "!!!|"
 generate "content_proxy_limit_reached" "Too Many Active Connections" --indicator "!!!|"
+generate "content_proxy_quota_exceeded" "Bandwidth Quota Exceeded" --indicator "!!!|"
 generate "download_failed" "Failed to Download" --indicator "!!!|"
 generate "downloading" "Downloading to Store" --indicator ".|..|..."
 generate "no_matching_file" "No Matching File" --indicator "!!!|"
@@ -1,5 +1,9 @@
 import { ErrorCode, ErrorType, StremThruError } from "./error";
-import { StoreMagnetStatus, StoreUserSubscriptionStatus } from "./types";
+import {
+  StoreMagnetStatus,
+  StoreUserSubscriptionStatus,
+  StoreWebDLStatus,
+} from "./types";
 import { VERSION } from "./version";
 
 const USER_AGENT = `stremthru:sdk:js/${VERSION}`;
@@ -75,6 +79,36 @@ class StremThruStore {
     });
   }
 
+  async addWebDL({
+    clientIp = this.#clientIp,
+    link,
+    password,
+  }: {
+    clientIp?: string;
+    link: string;
+    password?: string;
+  }) {
+    return await this.#client.request<{
+      added_at: string;
+      files: Array<{
+        index: number;
+        link?: string;
+        name: string;
+        path: string;
+        size: number;
+      }>;
+      id: string;
+      link: string;
+      name: string;
+      size: number;
+      status: StoreWebDLStatus;
+    }>("/v0/store/webdl", {
+      body: password ? { link, password } : { link },
+      method: "POST",
+      params: clientIp ? { client_ip: clientIp } : {},
+    });
+  }
+
   async checkMagnet(params: { magnet: string[]; sid?: string }) {
     return await this.#client.request<{
       items: Array<{
@@ -95,6 +129,20 @@ class StremThruStore {
     });
   }
 
+  async checkWebDL({ link }: { link: string[] }) {
+    return await this.#client.request<{
+      items: Array<{
+        link: string;
+        name?: string;
+        size?: number;
+        status: StoreWebDLStatus;
+      }>;
+    }>("/v0/store/webdl/check", {
+      method: "GET",
+      params: new URLSearchParams(link.map((l) => ["link", l])),
+    });
+  }
+
   async generateLink({
     clientIp = this.#clientIp,
     link,
@@ -111,6 +159,22 @@ class StremThruStore {
     });
   }
 
+  async generateWebDLLink({
+    clientIp = this.#clientIp,
+    link,
+  }: {
+    clientIp?: string;
+    link: string;
+  }) {
+    return await this.#client.request<{
+      link: string;
+    }>(`/v0/store/webdl/link/generate`, {
+      body: { link },
+      method: "POST",
+      params: clientIp ? { client_ip: clientIp } : {},
+    });
+  }
+
   async getMagnet(magnetId: string) {
     return await this.#client.request<{
       added_at: string;
@@ -138,6 +202,24 @@ class StremThruStore {
     }>("/v0/store/user", { method: "GET" });
   }
 
+  async getWebDL(webdlId: string) {
+    return await this.#client.request<{
+      added_at: string;
+      files: Array<{
+        index: number;
+        link?: string;
+        name: string;
+        path: string;
+        size: number;
+      }>;
+      id: string;
+      link: string;
+      name: string;
+      size: number;
+      status: StoreWebDLStatus;
+    }>(`/v0/store/webdl/${encodeURIComponent(webdlId)}`, { method: "GET" });
+  }
+
   async listMagnets({
     limit,
     offset,
@@ -167,11 +249,48 @@ class StremThruStore {
     }>("/v0/store/magnets", { method: "GET", params });
   }
 
+  async listWebDLs({
+    limit,
+    offset,
+  }: {
+    // min `1`, max `500`, default `100`
+    limit?: number;
+    // min `0`, default `0`
+    offset?: number;
+  }) {
+    const params: Record<string, string> = {};
+    if (limit) {
+      params["limit"] = String(limit);
+    }
+    if (offset) {
+      params["offset"] = String(offset);
+    }
+    return await this.#client.request<{
+      items: Array<{
+        added_at: string;
+        id: string;
+        link: string;
+        name: string;
+        size: number;
+        status: StoreWebDLStatus;
+      }>;
+      total_items: number;
+    }>("/v0/store/webdl", { method: "GET", params });
+  }
+
   async removeMagnet(magnetId: string) {
     return await this.#client.request<null>(`/v0/store/magnets/${magnetId}`, {
       method: "DELETE",
     });
   }
+
+  async removeWebDL(webdlId: string) {
+    return await this.#client.request<{
+      id: string;
+    }>(`/v0/store/webdl/${encodeURIComponent(webdlId)}`, {
+      method: "DELETE",
+    });
+  }
 }
 
 export class StremThru {
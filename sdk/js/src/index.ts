@@ -1,3 +1,7 @@
 export { StremThru, type StremThruConfig } from "./client";
 export { type ErrorCode, type ErrorType, StremThruError } from "./error";
-export type { StoreMagnetStatus, StoreUserSubscriptionStatus } from "./types";
+export type {
+  StoreMagnetStatus,
+  StoreUserSubscriptionStatus,
+  StoreWebDLStatus,
+} from "./types";
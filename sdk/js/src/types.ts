@@ -10,3 +10,13 @@ export type StoreMagnetStatus =
   | "uploading";
 
 export type StoreUserSubscriptionStatus = "expired" | "premium" | "trial";
+
+export type StoreWebDLStatus =
+  | "cached"
+  | "downloaded"
+  | "downloading"
+  | "failed"
+  | "invalid"
+  | "processing"
+  | "queued"
+  | "unknown";
@@ -1,10 +1,16 @@
-from stremthru.client import StoreMagnetStatus, StoreUserSubscriptionStatus, StremThru
+from stremthru.client import (
+    StoreMagnetStatus,
+    StoreUserSubscriptionStatus,
+    StoreWebDLStatus,
+    StremThru,
+)
 from stremthru.error import ErrorCode, ErrorType, StremThruError
 
 __all__ = [
     "StremThru",
     "StoreMagnetStatus",
     "StoreUserSubscriptionStatus",
+    "StoreWebDLStatus",
     "ErrorCode",
     "ErrorType",
     "StremThruError",
@@ -12,6 +12,7 @@ from typing import (
     Union,
     cast,
 )
+from urllib.parse import quote
 
 import aiohttp
 from multidict import CIMultiDict
@@ -238,6 +239,79 @@ class ListMagnetsData(TypedDict):
     total_items: int
 
 
+StoreWebDLStatus = Literal[
+    "cached",
+    "downloaded",
+    "downloading",
+    "failed",
+    "invalid",
+    "processing",
+    "queued",
+    "unknown",
+]
+
+
+class WebDLFile(TypedDict):
+    index: int
+    link: Optional[str]
+    name: str
+    path: str
+    size: int
+
+
+class AddWebDLData(TypedDict):
+    added_at: str
+    files: list[WebDLFile]
+    id: str
+    link: str
+    name: str
+    size: int
+    status: StoreWebDLStatus
+
+
+class CheckWebDLDataItem(TypedDict):
+    link: str
+    name: Optional[str]
+    size: Optional[int]
+    status: StoreWebDLStatus
+
+
+class CheckWebDLData(TypedDict):
+    items: list[CheckWebDLDataItem]
+
+
+class GenerateWebDLLinkData(TypedDict):
+    link: str
+
+
+class GetWebDLData(TypedDict):
+    added_at: str
+    files: list[WebDLFile]
+    id: str
+    link: str
+    name: str
+    size: int
+    status: StoreWebDLStatus
+
+
+class ListWebDLsDataItem(TypedDict):
+    added_at: str
+    id: str
+    link: str
+    name: str
+    size: int
+    status: StoreWebDLStatus
+
+
+class ListWebDLsData(TypedDict):
+    items: list[ListWebDLsDataItem]
+    total_items: int
+
+
+class RemoveWebDLData(TypedDict):
+    id: str
+
+
 class StremThruStore:
     _client_ip: str | None = None
 
@@ -280,6 +354,25 @@ class StremThruStore:
             params={"client_ip": client_ip} if client_ip else None,
         )
 
+    async def add_webdl(
+        self,
+        link: str,
+        password: Optional[str] = None,
+        client_ip: str | None = None,
+    ) -> Response[AddWebDLData]:
+        if not client_ip:
+            client_ip = self._client_ip
+
+        json: dict[str, Any] = {"link": link}
+        if password:
+            json["password"] = password
+        return await self.client.request(
+            "/v0/store/webdl",
+            "POST",
+            json=json,
+            params={"client_ip": client_ip} if client_ip else None,
+        )
+
     async def check_magnet(
         self, magnet: list[str], sid: Optional[str] = None
     ) -> Response[CheckMagnetData]:
@@ -288,6 +381,9 @@ class StremThruStore:
             params["sid"] = sid
         return await self.client.request("/v0/store/magnets/check", params=params)
 
+    async def check_webdl(self, link: list[str]) -> Response[CheckWebDLData]:
+        return await self.client.request("/v0/store/webdl/check", params={"link": link})
+
     async def generate_link(
         self, link: str, client_ip: str | None = None
     ) -> Response[GenerateLinkData]:
@@ -301,12 +397,30 @@ class StremThruStore:
             params={"client_ip": client_ip} if client_ip else None,
         )
 
+    async def generate_webdl_link(
+        self, link: str, client_ip: str | None = None
+    ) -> Response[GenerateWebDLLinkData]:
+        if not client_ip:
+            client_ip = self._client_ip
+
+        return await self.client.request(
+            "/v0/store/webdl/link/generate",
+            "POST",
+            json={"link": link},
+            params={"client_ip": client_ip} if client_ip else None,
+        )
+
     async def get_magnet(self, magnet_id: str) -> Response[GetMagnetData]:
         return await self.client.request(f"/v0/store/magnets/{magnet_id}")
 
     async def get_user(self) -> Response[GetUserData]:
         return await self.client.request("/v0/store/user")
 
+    async def get_webdl(self, webdl_id: str) -> Response[GetWebDLData]:
+        return await self.client.request(
+            f"/v0/store/webdl/{quote(webdl_id, safe='')}"
+        )
+
     async def list_magnets(
         self, limit: int | None = None, offset: int | None = None
     ) -> Response[ListMagnetsData]:
@@ -317,5 +431,20 @@ class StremThruStore:
             params["offset"] = offset
         return await self.client.request("/v0/store/magnets", params=params)
 
+    async def list_webdls(
+        self, limit: int | None = None, offset: int | None = None
+    ) -> Response[ListWebDLsData]:
+        params = {}
+        if limit:
+            params["limit"] = limit
+        if offset:
+            params["offset"] = offset
+        return await self.client.request("/v0/store/webdl", params=params)
+
     async def remove_magnet(self, magnet_id: str) -> Response[None]:
         return await self.client.request(f"/v0/store/magnets/{magnet_id}", "DELETE")
+
+    async def remove_webdl(self, webdl_id: str) -> Response[RemoveWebDLData]:
+        return await self.client.request(
+            f"/v0/store/webdl/{quote(webdl_id, safe='')}", "DELETE"
+        )
@@ -130,3 +130,34 @@ func (c APIClient) GetDelayedLink(params *GetDelayedLinkParams) (APIResponse[Get
 	res, err := c.Request("GET", "/v4/link/delayed", params, response)
 	return newAPIResponse(res, response.Data), err
 }
+
+type GetLinkInfosDataInfo struct {
+	Link       string         `json:"link"`
+	Filename   string         `json:"filename"`
+	Size       int64          `json:"size"`
+	Host       string         `json:"host"`
+	HostDomain string         `json:"hostDomain"`
+	Error      *ResponseError `json:"error,omitempty"`
+}
+
+type GetLinkInfosData struct {
+	Infos []GetLinkInfosDataInfo `json:"infos"`
+}
+
+type GetLinkInfosParams struct {
+	Ctx
+	Links    []string
+	Password string
+}
+
+func (c APIClient) GetLinkInfos(params *GetLinkInfosParams) (APIResponse[[]GetLinkInfosDataInfo], error) {
+	form := &url.Values{"link[]": params.Links}
+	if len(params.Password) > 0 {
+		form.Add("password", params.Password)
+	}
+	params.Form = form
+
+	response := &Response[GetLinkInfosData]{}
+	res, err := c.Request("GET", "/v4/link/infos", params, response)
+	return newAPIResponse(res, response.Data.Infos), err
+}
@@ -14,6 +14,11 @@ import (
 	"github.com/MunifTanjim/stremthru/store"
 )
 
+var (
+	_ store.Store      = (*StoreClient)(nil)
+	_ store.WebDLStore = (*StoreClient)(nil)
+)
+
 type StoreClientConfig struct {
 	HTTPClient *http.Client
 	UserAgent  string
@@ -373,3 +378,217 @@ func (c *StoreClient) GenerateLink(params *store.GenerateLinkParams) (*store.Gen
 
 	return data, nil
 }
+
+func getWebDLStatusForLinkInfo(info *GetLinkInfosDataInfo) store.WebDLStatus {
+	if info.Error != nil {
+		return store.WebDLStatusInvalid
+	}
+	return store.WebDLStatusCached
+}
+
+func (c *StoreClient) CheckWebDL(params *store.CheckWebDLParams) (*store.CheckWebDLData, error) {
+	res, err := c.client.GetLinkInfos(&GetLinkInfosParams{
+		Ctx:   params.Ctx,
+		Links: params.Links,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	infoByLink := map[string]GetLinkInfosDataInfo{}
+	for _, info := range res.Data {
+		infoByLink[info.Link] = info
+	}
+
+	data := &store.CheckWebDLData{
+		Items: []store.CheckWebDLDataItem{},
+	}
+	for _, link := range params.Links {
+		item := store.CheckWebDLDataItem{
+			Link:   link,
+			Size:   -1,
+			Status: store.WebDLStatusUnknown,
+		}
+		if info, ok := infoByLink[link]; ok {
+			item.Status = getWebDLStatusForLinkInfo(&info)
+			if item.Status == store.WebDLStatusCached {
+				item.Name = info.Filename
+				item.Size = info.Size
+			}
+		}
+		data.Items = append(data.Items, item)
+	}
+	return data, nil
+}
+
+func (c *StoreClient) AddWebDL(params *store.AddWebDLParams) (*store.AddWebDLData, error) {
+	ul, err := c.client.UnlockLink(&UnlockLinkParams{
+		Ctx:      params.Ctx,
+		Link:     params.Link,
+		Password: params.Password,
+		UserIP:   params.ClientIP,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.client.SaveUserLinks(&SaveUserLinksParams{
+		Ctx:   params.Ctx,
+		Links: []string{params.Link},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := &store.AddWebDLData{
+		Id:      params.Link,
+		Link:    params.Link,
+		Name:    ul.Data.Filename,
+		Size:    int64(ul.Data.Filesize),
+		Status:  store.WebDLStatusDownloaded,
+		Files:   []store.WebDLFile{},
+		AddedAt: time.Now().UTC(),
+	}
+	data.Files = append(data.Files, store.WebDLFile{
+		Idx:  0,
+		Link: params.Link,
+		Path: "/" + ul.Data.Filename,
+		Name: ul.Data.Filename,
+		Size: data.Size,
+	})
+	return data, nil
+}
+
+func (c *StoreClient) listSavedWebDLs(ctx store.Ctx) ([]store.ListWebDLsDataItem, error) {
+	res, err := c.client.GetSavedUserLinks(&GetSavedUserLinksParams{
+		Ctx: ctx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []store.ListWebDLsDataItem{}
+	for _, link := range res.Data {
+		if link.Host == "magnet" {
+			continue
+		}
+		item := store.ListWebDLsDataItem{
+			Id:      link.Link,
+			Link:    link.Link,
+			Name:    link.Filename,
+			Size:    link.GetSize(),
+			Status:  store.WebDLStatusDownloaded,
+			AddedAt: link.GetDate(),
+		}
+		if link.Host == "error" {
+			item.Status = store.WebDLStatusFailed
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (c *StoreClient) GetWebDL(params *store.GetWebDLParams) (*store.GetWebDLData, error) {
+	items, err := c.listSavedWebDLs(params.Ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if item.Id != params.Id {
+			continue
+		}
+		data := &store.GetWebDLData{
+			Id:      item.Id,
+			Link:    item.Link,
+			Name:    item.Name,
+			Size:    item.Size,
+			Status:  item.Status,
+			Files:   []store.WebDLFile{},
+			AddedAt: item.AddedAt,
+		}
+		if item.Status == store.WebDLStatusDownloaded {
+			data.Files = append(data.Files, store.WebDLFile{
+				Idx:  0,
+				Link: item.Link,
+				Path: "/" + item.Name,
+				Name: item.Name,
+				Size: item.Size,
+			})
+		}
+		return data, nil
+	}
+
+	error := core.NewAPIError("not found")
+	error.StatusCode = http.StatusNotFound
+	error.StoreName = string(store.StoreNameAlldebrid)
+	return nil, error
+}
+
+func (c *StoreClient) ListWebDLs(params *store.ListWebDLsParams) (*store.ListWebDLsData, error) {
+	items, err := c.listSavedWebDLs(params.Ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	totalItems := len(items)
+	startIdx := min(params.Offset, totalItems)
+	endIdx := min(startIdx+params.Limit, totalItems)
+
+	data := &store.ListWebDLsData{
+		Items:      items[startIdx:endIdx],
+		TotalItems: totalItems,
+	}
+	return data, nil
+}
+
+func (c *StoreClient) RemoveWebDL(params *store.RemoveWebDLParams) (*store.RemoveWebDLData, error) {
+	_, err := c.client.DeleteSavedUserLinks(&DeleteSavedUserLinksParams{
+		Ctx:   params.Ctx,
+		Links: []string{params.Id},
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := &store.RemoveWebDLData{
+		Id: params.Id,
+	}
+	return data, nil
+}
+
+func (c *StoreClient) GenerateWebDLLink(params *store.GenerateWebDLLinkParams) (*store.GenerateWebDLLinkData, error) {
+	ul, err := c.client.UnlockLink(&UnlockLinkParams{
+		Ctx:    params.Ctx,
+		Link:   params.Link,
+		UserIP: params.ClientIP,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link := ul.Data.Link
+	delayed := ul.Data.Delayed
+	if link == "" && delayed == 0 && len(ul.Data.Streams) > 0 {
+		sl, err := c.client.GetStreamingLink(&GetStreamingLinkParams{
+			Ctx:    params.Ctx,
+			Id:     ul.Data.Id,
+			Stream: ul.Data.Streams[len(ul.Data.Streams)-1].Id,
+		})
+		if err != nil {
+			return nil, err
+		}
+		link = sl.Data.Link
+		delayed = sl.Data.Delayed
+	}
+
+	if delayed != 0 || link == "" {
+		error := core.NewStoreError("link generation delayed, try later")
+		error.StatusCode = http.StatusTeapot
+		return nil, error
+	}
+
+	data := &store.GenerateWebDLLinkData{
+		Link: link,
+	}
+	return data, nil
+}
@@ -0,0 +1,23 @@
+package alldebrid
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWebDLStatusForLinkInfo(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		info   GetLinkInfosDataInfo
+		status store.WebDLStatus
+	}{
+		{"available", GetLinkInfosDataInfo{Filename: "file.mkv", Size: 100}, store.WebDLStatusCached},
+		{"error", GetLinkInfosDataInfo{Error: &ResponseError{}}, store.WebDLStatusInvalid},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.status, getWebDLStatusForLinkInfo(&tc.info))
+		})
+	}
+}
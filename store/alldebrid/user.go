@@ -2,6 +2,7 @@ package alldebrid
 
 import (
 	"encoding/json"
+	"net/url"
 	"time"
 )
 
@@ -79,3 +80,37 @@ func (c APIClient) GetSavedUserLinks(params *GetSavedUserLinksParams) (APIRespon
 	res, err := c.Request("GET", "/v4/user/links", params, response)
 	return newAPIResponse(res, response.Data.Links), err
 }
+
+type SaveUserLinksData struct {
+	Message string `json:"message"`
+}
+
+type SaveUserLinksParams struct {
+	Ctx
+	Links []string
+}
+
+func (c APIClient) SaveUserLinks(params *SaveUserLinksParams) (APIResponse[SaveUserLinksData], error) {
+	params.Form = &url.Values{"links[]": params.Links}
+
+	response := &Response[SaveUserLinksData]{}
+	res, err := c.Request("GET", "/v4/user/links/save", params, response)
+	return newAPIResponse(res, response.Data), err
+}
+
+type DeleteSavedUserLinksData struct {
+	Message string `json:"message"`
+}
+
+type DeleteSavedUserLinksParams struct {
+	Ctx
+	Links []string
+}
+
+func (c APIClient) DeleteSavedUserLinks(params *DeleteSavedUserLinksParams) (APIResponse[DeleteSavedUserLinksData], error) {
+	params.Form = &url.Values{"links[]": params.Links}
+
+	response := &Response[DeleteSavedUserLinksData]{}
+	res, err := c.Request("GET", "/v4/user/links/delete", params, response)
+	return newAPIResponse(res, response.Data), err
+}
@@ -0,0 +1,102 @@
+package debridlink
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type DownloaderLink struct {
+	Id          string `json:"id"`
+	Expired     bool   `json:"expired"`
+	Chunk       int    `json:"chunk"`
+	Host        string `json:"host"`
+	Size        int64  `json:"size"`
+	Created     int64  `json:"created"`
+	Url         string `json:"url"`
+	DownloadUrl string `json:"downloadUrl"`
+	Name        string `json:"name"`
+}
+
+func (l DownloaderLink) GetAddedAt() time.Time {
+	return time.Unix(l.Created, 0).UTC()
+}
+
+const LIST_DOWNLOADER_LINKS_PER_PAGE_MIN = 20
+const LIST_DOWNLOADER_LINKS_PER_PAGE_MAX = 50
+
+type ListDownloaderLinksParams struct {
+	Ctx
+	Ids     []string
+	Page    int // start at 0
+	PerPage int // min 20, max 50
+}
+
+type ListDownloaderLinksData struct {
+	Value      []DownloaderLink
+	Pagination ResponsePagination
+}
+
+func (c APIClient) ListDownloaderLinks(params *ListDownloaderLinksParams) (APIResponse[ListDownloaderLinksData], error) {
+	form := &url.Values{}
+	if len(params.Ids) > 0 {
+		form.Add("ids", strings.Join(params.Ids, ","))
+	}
+	if params.Page != 0 {
+		form.Add("page", strconv.Itoa(params.Page))
+	}
+	if params.PerPage != 0 {
+		form.Add("perPage", strconv.Itoa(params.PerPage))
+	}
+	params.Form = form
+
+	response := &PaginatedResponse[DownloaderLink]{}
+	res, err := c.Request("GET", "/v2/downloader/list", params, response)
+	return newAPIResponse(res, ListDownloaderLinksData{
+		Value:      response.Value,
+		Pagination: response.Pagination,
+	}), err
+}
+
+type AddDownloaderLinkData = DownloaderLink
+
+type AddDownloaderLinkParams struct {
+	Ctx
+	Url      string `json:"url"`
+	Password string `json:"password,omitempty"`
+	IP       string `json:"ip,omitempty"`
+}
+
+func (c APIClient) AddDownloaderLink(params *AddDownloaderLinkParams) (APIResponse[AddDownloaderLinkData], error) {
+	params.JSON = params
+
+	response := &Response[AddDownloaderLinkData]{}
+	res, err := c.Request("POST", "/v2/downloader/add", params, response)
+	return newAPIResponse(res, response.Value), err
+}
+
+type RemoveDownloaderLinksData = []string
+
+type RemoveDownloaderLinksParams struct {
+	Ctx
+	Ids []string
+}
+
+func (c APIClient) RemoveDownloaderLinks(params *RemoveDownloaderLinksParams) (APIResponse[RemoveDownloaderLinksData], error) {
+	response := &Response[RemoveDownloaderLinksData]{}
+	res, err := c.Request("DELETE", "/v2/downloader/"+strings.Join(params.Ids, ",")+"/remove", params, response)
+	return newAPIResponse(res, response.Value), err
+}
+
+type ListDownloaderDomainsData = []string
+
+type ListDownloaderDomainsParams struct {
+	Ctx
+}
+
+func (c APIClient) ListDownloaderDomains(params *ListDownloaderDomainsParams) (APIResponse[ListDownloaderDomainsData], error) {
+	response := &Response[ListDownloaderDomainsData]{}
+	res, err := c.Request("GET", "/v2/downloader/domains", params, response)
+	return newAPIResponse(res, response.Value), err
+}
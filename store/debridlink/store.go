@@ -13,6 +13,11 @@ import (
 	"github.com/MunifTanjim/stremthru/store"
 )
 
+var (
+	_ store.Store      = (*StoreClient)(nil)
+	_ store.WebDLStore = (*StoreClient)(nil)
+)
+
 type StoreClientConfig struct {
 	HTTPClient *http.Client
 	UserAgent  string
@@ -315,3 +320,182 @@ func (c *StoreClient) GenerateLink(params *store.GenerateLinkParams) (*store.Gen
 	data := &store.GenerateLinkData{Link: params.Link}
 	return data, nil
 }
+
+func getWebDLStatus(l *DownloaderLink) store.WebDLStatus {
+	if l.Expired {
+		return store.WebDLStatusFailed
+	}
+	return store.WebDLStatusDownloaded
+}
+
+func (c *StoreClient) CheckWebDL(params *store.CheckWebDLParams) (*store.CheckWebDLData, error) {
+	res, err := c.client.ListDownloaderDomains(&ListDownloaderDomainsParams{
+		Ctx: params.Ctx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := &store.CheckWebDLData{
+		Items: []store.CheckWebDLDataItem{},
+	}
+	for _, link := range params.Links {
+		item := store.CheckWebDLDataItem{
+			Link:   link,
+			Size:   -1,
+			Status: store.WebDLStatusInvalid,
+		}
+		// debrid-link does not expose availability of the file, only the supported domains
+		if u, err := url.Parse(link); err == nil && u.Hostname() != "" {
+			hostname := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+			for _, domain := range res.Data {
+				if hostname == domain || strings.HasSuffix(hostname, "."+domain) {
+					item.Status = store.WebDLStatusUnknown
+					break
+				}
+			}
+		}
+		data.Items = append(data.Items, item)
+	}
+	return data, nil
+}
+
+func (c *StoreClient) AddWebDL(params *store.AddWebDLParams) (*store.AddWebDLData, error) {
+	res, err := c.client.AddDownloaderLink(&AddDownloaderLinkParams{
+		Ctx:      params.Ctx,
+		Url:      params.Link,
+		Password: params.Password,
+		IP:       params.ClientIP,
+	})
+	if err != nil {
+		return nil, err
+	}
+	l := res.Data
+	data := &store.AddWebDLData{
+		Id:      l.Id,
+		Link:    l.Url,
+		Name:    l.Name,
+		Size:    l.Size,
+		Status:  getWebDLStatus(&l),
+		Files:   []store.WebDLFile{},
+		AddedAt: l.GetAddedAt(),
+	}
+	if data.Status == store.WebDLStatusDownloaded {
+		data.Files = append(data.Files, store.WebDLFile{
+			Idx:  0,
+			Link: l.DownloadUrl,
+			Path: "/" + l.Name,
+			Name: l.Name,
+			Size: l.Size,
+		})
+	}
+	return data, nil
+}
+
+func (c *StoreClient) GetWebDL(params *store.GetWebDLParams) (*store.GetWebDLData, error) {
+	res, err := c.client.ListDownloaderLinks(&ListDownloaderLinksParams{
+		Ctx: params.Ctx,
+		Ids: []string{params.Id},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Data.Value) != 1 || res.Data.Value[0].Id != params.Id {
+		err := core.NewAPIError("not found")
+		err.StatusCode = http.StatusNotFound
+		err.StoreName = string(store.StoreNameDebridLink)
+		return nil, err
+	}
+	l := res.Data.Value[0]
+	data := &store.GetWebDLData{
+		Id:      l.Id,
+		Link:    l.Url,
+		Name:    l.Name,
+		Size:    l.Size,
+		Status:  getWebDLStatus(&l),
+		Files:   []store.WebDLFile{},
+		AddedAt: l.GetAddedAt(),
+	}
+	if data.Status == store.WebDLStatusDownloaded {
+		data.Files = append(data.Files, store.WebDLFile{
+			Idx:  0,
+			Link: l.DownloadUrl,
+			Path: "/" + l.Name,
+			Name: l.Name,
+			Size: l.Size,
+		})
+	}
+	return data, nil
+}
+
+func (c *StoreClient) ListWebDLs(params *store.ListWebDLsParams) (*store.ListWebDLsData, error) {
+	data := &store.ListWebDLsData{
+		Items:      []store.ListWebDLsDataItem{},
+		TotalItems: 0,
+	}
+	totalPages := 0
+
+	limit := LIST_DOWNLOADER_LINKS_PER_PAGE_MAX
+	page := params.Offset / limit
+	offsetInPage := params.Offset % limit
+	remainingItems := params.Limit
+	hasMore := true
+	for hasMore {
+		res, err := c.client.ListDownloaderLinks(&ListDownloaderLinksParams{
+			Ctx:     params.Ctx,
+			PerPage: limit,
+			Page:    page,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resItems := res.Data.Value
+		totalPages = res.Data.Pagination.Pages
+		if len(resItems) == 0 {
+			break
+		}
+
+		if offsetInPage != 0 {
+			resItems = resItems[min(offsetInPage, len(resItems)):]
+			offsetInPage = 0
+		}
+		totalResItems := len(resItems)
+
+		for _, l := range resItems[:min(totalResItems, remainingItems)] {
+			data.Items = append(data.Items, store.ListWebDLsDataItem{
+				Id:      l.Id,
+				Link:    l.Url,
+				Name:    l.Name,
+				Size:    l.Size,
+				Status:  getWebDLStatus(&l),
+				AddedAt: l.GetAddedAt(),
+			})
+		}
+
+		page++
+		remainingItems -= totalResItems
+		hasMore = page < totalPages && remainingItems > 0
+	}
+
+	data.TotalItems = totalPages * limit
+
+	return data, nil
+}
+
+func (c *StoreClient) RemoveWebDL(params *store.RemoveWebDLParams) (*store.RemoveWebDLData, error) {
+	_, err := c.client.RemoveDownloaderLinks(&RemoveDownloaderLinksParams{
+		Ctx: params.Ctx,
+		Ids: []string{params.Id},
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := &store.RemoveWebDLData{Id: params.Id}
+	return data, nil
+}
+
+func (c *StoreClient) GenerateWebDLLink(params *store.GenerateWebDLLinkParams) (*store.GenerateWebDLLinkData, error) {
+	data := &store.GenerateWebDLLinkData{Link: params.Link}
+	return data, nil
+}
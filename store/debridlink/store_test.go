@@ -0,0 +1,23 @@
+package debridlink
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWebDLStatus(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		link   DownloaderLink
+		status store.WebDLStatus
+	}{
+		{"available", DownloaderLink{}, store.WebDLStatusDownloaded},
+		{"expired", DownloaderLink{Expired: true}, store.WebDLStatusFailed},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.status, getWebDLStatus(&tc.link))
+		})
+	}
+}
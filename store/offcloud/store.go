@@ -13,6 +13,11 @@ import (
 	"github.com/MunifTanjim/stremthru/store"
 )
 
+var (
+	_ store.Store      = (*StoreClient)(nil)
+	_ store.WebDLStore = (*StoreClient)(nil)
+)
+
 type StoreClientConfig struct {
 	HTTPClient *http.Client
 	UserAgent  string
@@ -22,6 +27,7 @@ type StoreClient struct {
 	Name             store.StoreName
 	client           *APIClient
 	listMagnetsCache cache.Cache[[]store.ListMagnetsDataItem]
+	listWebDLsCache  cache.Cache[[]store.ListWebDLsDataItem]
 }
 
 func NewStoreClient(config *StoreClientConfig) *StoreClient {
@@ -36,6 +42,10 @@ func NewStoreClient(config *StoreClientConfig) *StoreClient {
 		Name:     "store:offcloud:listMagnets",
 		Lifetime: 5 * time.Minute,
 	})
+	c.listWebDLsCache = cache.NewCache[[]store.ListWebDLsDataItem](&cache.CacheConfig{
+		Name:     "store:offcloud:listWebDLs",
+		Lifetime: 5 * time.Minute,
+	})
 
 	return c
 }
@@ -373,3 +383,198 @@ func (s *StoreClient) RemoveMagnet(params *store.RemoveMagnetParams) (*store.Rem
 	data := &store.RemoveMagnetData{Id: params.Id}
 	return data, nil
 }
+
+func getWebDLStatus(status CloudDownloadStatus) store.WebDLStatus {
+	switch status {
+	case CloudDownloadStatusCreated:
+		return store.WebDLStatusQueued
+	case CloudDownloadStatusDownloading:
+		return store.WebDLStatusDownloading
+	case CloudDownloadStatusDownloaded:
+		return store.WebDLStatusDownloaded
+	case CloudDownloadStatusError:
+		return store.WebDLStatusFailed
+	default:
+		return store.WebDLStatusUnknown
+	}
+}
+
+func isWebDLLink(link string) bool {
+	return strings.HasPrefix(link, "https://") || strings.HasPrefix(link, "http://")
+}
+
+func (s *StoreClient) getWebDLFiles(ctx Ctx, requestId string, server string) ([]store.WebDLFile, string, error) {
+	mFiles, name, err := s.getMagnetFiles(ctx, requestId, server)
+	if err != nil {
+		return nil, "", err
+	}
+	files := []store.WebDLFile{}
+	for _, f := range mFiles {
+		file := store.WebDLFile{
+			Idx:  f.Idx,
+			Link: f.Link,
+			Path: f.Path,
+			Name: f.Name,
+			Size: f.Size,
+		}
+		if file.Path == "" || file.Path == "/" {
+			file.Path = "/" + file.Name
+		}
+		files = append(files, file)
+	}
+	return files, name, nil
+}
+
+func (s *StoreClient) CheckWebDL(params *store.CheckWebDLParams) (*store.CheckWebDLData, error) {
+	data := &store.CheckWebDLData{
+		Items: []store.CheckWebDLDataItem{},
+	}
+	// offcloud does not expose availability of the file
+	for _, link := range params.Links {
+		item := store.CheckWebDLDataItem{
+			Link:   link,
+			Size:   -1,
+			Status: store.WebDLStatusInvalid,
+		}
+		if isWebDLLink(link) {
+			item.Status = store.WebDLStatusUnknown
+		}
+		data.Items = append(data.Items, item)
+	}
+	return data, nil
+}
+
+func (s *StoreClient) AddWebDL(params *store.AddWebDLParams) (*store.AddWebDLData, error) {
+	if !isWebDLLink(params.Link) {
+		error := core.NewAPIError("invalid link")
+		error.StatusCode = http.StatusBadRequest
+		error.StoreName = string(store.StoreNameOffcloud)
+		return nil, error
+	}
+
+	res, err := s.client.AddCloudDownload(&AddCloudDownloadParams{
+		Ctx: params.Ctx,
+		URL: params.Link,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.listWebDLsCache.Remove(s.getCacheKey(params, ""))
+
+	data := &store.AddWebDLData{
+		Id:      res.Data.RequestId,
+		Link:    params.Link,
+		Name:    res.Data.FileName,
+		Size:    -1,
+		Status:  getWebDLStatus(res.Data.Status),
+		Files:   []store.WebDLFile{},
+		AddedAt: res.Data.CreatedOn,
+	}
+	if data.Status == store.WebDLStatusDownloaded {
+		files, _, err := s.getWebDLFiles(params.Ctx, data.Id, res.Data.GetServer())
+		if err != nil {
+			return nil, err
+		}
+		data.Files = files
+	}
+	return data, nil
+}
+
+func (s *StoreClient) GetWebDL(params *store.GetWebDLParams) (*store.GetWebDLData, error) {
+	res, err := s.client.GetCloudDownloadStatus(&GetCloudDownloadStatusParams{
+		Ctx:       params.Ctx,
+		RequestId: params.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dl := res.Data.Status
+	data := &store.GetWebDLData{
+		Id:      params.Id,
+		Name:    dl.FileName,
+		Size:    dl.FileSize,
+		Status:  getWebDLStatus(dl.Status),
+		Files:   []store.WebDLFile{},
+		AddedAt: time.Unix(0, 0),
+	}
+	if data.Status == store.WebDLStatusDownloaded {
+		files, _, err := s.getWebDLFiles(params.Ctx, data.Id, dl.Server)
+		if err != nil {
+			return nil, err
+		}
+		data.Files = files
+	}
+	return data, nil
+}
+
+func (s *StoreClient) ListWebDLs(params *store.ListWebDLsParams) (*store.ListWebDLsData, error) {
+	lw := []store.ListWebDLsDataItem{}
+
+	if !s.listWebDLsCache.Get(s.getCacheKey(params, ""), &lw) {
+		items := []store.ListWebDLsDataItem{}
+		page := 0
+
+		for {
+			res, err := s.client.ListCloudDownloads(&ListCloudDownloadsParams{
+				Ctx:  params.Ctx,
+				Page: page,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, dl := range res.Data.History {
+				if !isWebDLLink(dl.OriginalLink) {
+					continue
+				}
+				item := store.ListWebDLsDataItem{
+					Id:      dl.RequestId,
+					Link:    dl.OriginalLink,
+					Name:    dl.FileName,
+					Size:    dl.FileSize,
+					Status:  getWebDLStatus(dl.Status),
+					AddedAt: dl.CreatedOn,
+				}
+				items = append(items, item)
+			}
+
+			if res.Data.IsEnd {
+				break
+			}
+			page += 1
+		}
+
+		lw = items
+		s.listWebDLsCache.Add(s.getCacheKey(params, ""), items)
+	}
+
+	totalItems := len(lw)
+	startIdx := min(params.Offset, totalItems)
+	endIdx := min(startIdx+params.Limit, totalItems)
+
+	data := &store.ListWebDLsData{
+		Items:      lw[startIdx:endIdx],
+		TotalItems: totalItems,
+	}
+	return data, nil
+}
+
+func (s *StoreClient) RemoveWebDL(params *store.RemoveWebDLParams) (*store.RemoveWebDLData, error) {
+	_, err := s.client.RemoveCloudDownload(&RemoveCloudDownloadParams{
+		Ctx:       params.Ctx,
+		RequestId: params.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.listWebDLsCache.Remove(s.getCacheKey(params, ""))
+
+	data := &store.RemoveWebDLData{Id: params.Id}
+	return data, nil
+}
+
+func (s *StoreClient) GenerateWebDLLink(params *store.GenerateWebDLLinkParams) (*store.GenerateWebDLLinkData, error) {
+	data := &store.GenerateWebDLLinkData{Link: params.Link}
+	return data, nil
+}
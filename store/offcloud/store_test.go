@@ -0,0 +1,41 @@
+package offcloud
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWebDLStatus(t *testing.T) {
+	for _, tc := range []struct {
+		status   CloudDownloadStatus
+		expected store.WebDLStatus
+	}{
+		{CloudDownloadStatusCreated, store.WebDLStatusQueued},
+		{CloudDownloadStatusDownloading, store.WebDLStatusDownloading},
+		{CloudDownloadStatusDownloaded, store.WebDLStatusDownloaded},
+		{CloudDownloadStatusError, store.WebDLStatusFailed},
+		{"other", store.WebDLStatusUnknown},
+	} {
+		t.Run(string(tc.status), func(t *testing.T) {
+			assert.Equal(t, tc.expected, getWebDLStatus(tc.status))
+		})
+	}
+}
+
+func TestIsWebDLLink(t *testing.T) {
+	for _, tc := range []struct {
+		link     string
+		expected bool
+	}{
+		{"https://hoster.example/f/1", true},
+		{"http://hoster.example/f/1", true},
+		{"magnet:?xt=urn:btih:0000000000000000000000000000000000000000", false},
+		{"0000000000000000000000000000000000000000", false},
+	} {
+		t.Run(tc.link, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isWebDLLink(tc.link))
+		})
+	}
+}
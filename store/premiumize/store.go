@@ -20,6 +20,11 @@ import (
 	"github.com/MunifTanjim/stremthru/store"
 )
 
+var (
+	_ store.Store      = (*StoreClient)(nil)
+	_ store.WebDLStore = (*StoreClient)(nil)
+)
+
 type StoreClientConfig struct {
 	HTTPClient       *http.Client
 	UserAgent        string
@@ -865,3 +870,230 @@ func (c *StoreClient) GenerateLink(params *store.GenerateLinkParams) (*store.Gen
 	data := &store.GenerateLinkData{Link: params.Link}
 	return data, nil
 }
+
+func getWebDLStatusForTransfer(transfer *ListTransfersDataItem) store.WebDLStatus {
+	switch transfer.Status {
+	case TransferStatusFinished, TransferStatusSeeding:
+		return store.WebDLStatusDownloaded
+	case TransferStatusQueued, TransferStatusWaiting:
+		return store.WebDLStatusQueued
+	case TransferStatusRunning:
+		if transfer.Progress > 0 {
+			return store.WebDLStatusDownloading
+		}
+		return store.WebDLStatusQueued
+	case TransferStatusBanned, TransferStatusDeleted, TransferStatusError, TransferStatusTimeout:
+		return store.WebDLStatusFailed
+	default:
+		return store.WebDLStatusUnknown
+	}
+}
+
+func (c *StoreClient) CheckWebDL(params *store.CheckWebDLParams) (*store.CheckWebDLData, error) {
+	res, err := c.client.CheckCache(&CheckCacheParams{
+		Ctx:   params.Ctx,
+		Items: params.Links,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := &store.CheckWebDLData{
+		Items: []store.CheckWebDLDataItem{},
+	}
+	for i, link := range params.Links {
+		item := store.CheckWebDLDataItem{
+			Link:   link,
+			Size:   -1,
+			Status: store.WebDLStatusUnknown,
+		}
+		if i < len(res.Data.Response) && res.Data.Response[i] {
+			item.Status = store.WebDLStatusCached
+			if i < len(res.Data.Filename) {
+				item.Name = res.Data.Filename[i]
+			}
+			if i < len(res.Data.Filesize) {
+				if size, err := res.Data.Filesize[i].Int64(); err == nil {
+					item.Size = size
+				}
+			}
+		}
+		data.Items = append(data.Items, item)
+	}
+	return data, nil
+}
+
+func (c *StoreClient) getWebDLFiles(apiKey string, transfer *ListTransfersDataItem) ([]store.WebDLFile, error) {
+	files := []store.WebDLFile{}
+	if transfer.FileId != "" {
+		params := &GetItemParams{Id: transfer.FileId}
+		params.APIKey = apiKey
+		res, err := c.client.GetItem(params)
+		if err != nil {
+			return nil, err
+		}
+		file := store.WebDLFile{
+			Idx:  0,
+			Link: res.Data.Link,
+			Path: "/" + res.Data.Name,
+			Name: res.Data.Name,
+			Size: res.Data.Size,
+		}
+		if res.Data.StreamLink != "" {
+			file.Link = res.Data.StreamLink
+		}
+		return append(files, file), nil
+	}
+
+	if transfer.FolderId != "" {
+		mFiles, err := listFolderFlat(c, apiKey, transfer.FolderId, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range mFiles {
+			files = append(files, store.WebDLFile{
+				Idx:  f.Idx,
+				Link: f.Link,
+				Path: f.Path,
+				Name: f.Name,
+				Size: f.Size,
+			})
+		}
+	}
+	return files, nil
+}
+
+func (c *StoreClient) AddWebDL(params *store.AddWebDLParams) (*store.AddWebDLData, error) {
+	// dedicated folder, deleting the transfer also deletes the folder
+	folder, err := c.ensureFolder(params.APIKey, "webdl:"+util.MD5Hash(params.Link))
+	if err != nil {
+		return nil, err
+	}
+
+	ct_res, err := c.client.CreateTransfer(&CreateTransferParams{
+		Ctx:      params.Ctx,
+		Src:      params.Link,
+		FolderId: folder.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := &store.AddWebDLData{
+		Id:      ct_res.Data.Id,
+		Link:    params.Link,
+		Name:    ct_res.Data.Name,
+		Size:    -1,
+		Status:  store.WebDLStatusQueued,
+		Files:   []store.WebDLFile{},
+		AddedAt: time.Now().UTC(),
+	}
+
+	transfer, err := getTransferById(c, params.APIKey, data.Id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer == nil {
+		return data, nil
+	}
+
+	data.Status = getWebDLStatusForTransfer(transfer)
+	if data.Status == store.WebDLStatusDownloaded {
+		files, err := c.getWebDLFiles(params.APIKey, transfer)
+		if err != nil {
+			return nil, err
+		}
+		data.Files = files
+		data.Size = 0
+		for i := range files {
+			data.Size += files[i].Size
+		}
+	}
+
+	return data, nil
+}
+
+func (c *StoreClient) GetWebDL(params *store.GetWebDLParams) (*store.GetWebDLData, error) {
+	transfer, err := getTransferById(c, params.APIKey, params.Id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer == nil || strings.HasPrefix(transfer.Src, "magnet:") {
+		err := core.NewAPIError("not found")
+		err.StatusCode = http.StatusNotFound
+		err.StoreName = string(store.StoreNamePremiumize)
+		return nil, err
+	}
+
+	data := &store.GetWebDLData{
+		Id:      transfer.Id,
+		Link:    transfer.Src,
+		Name:    transfer.Name,
+		Size:    -1,
+		Status:  getWebDLStatusForTransfer(transfer),
+		Files:   []store.WebDLFile{},
+		AddedAt: transfer.GetAddedAt(),
+	}
+	if data.Status == store.WebDLStatusDownloaded {
+		files, err := c.getWebDLFiles(params.APIKey, transfer)
+		if err != nil {
+			return nil, err
+		}
+		data.Files = files
+		data.Size = 0
+		for i := range files {
+			data.Size += files[i].Size
+		}
+	}
+
+	return data, nil
+}
+
+func (c *StoreClient) ListWebDLs(params *store.ListWebDLsParams) (*store.ListWebDLsData, error) {
+	res, err := c.client.ListTransfers(&ListTransfersParams{
+		Ctx: params.Ctx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []store.ListWebDLsDataItem{}
+	for _, t := range res.Data.Transfers {
+		if strings.HasPrefix(t.Src, "magnet:") {
+			continue
+		}
+		items = append(items, store.ListWebDLsDataItem{
+			Id:      t.Id,
+			Link:    t.Src,
+			Name:    t.Name,
+			Size:    -1,
+			Status:  getWebDLStatusForTransfer(&t),
+			AddedAt: t.GetAddedAt(),
+		})
+	}
+
+	totalItems := len(items)
+	startIdx := min(params.Offset, totalItems)
+	endIdx := min(startIdx+params.Limit, totalItems)
+
+	data := &store.ListWebDLsData{
+		Items:      items[startIdx:endIdx],
+		TotalItems: totalItems,
+	}
+	return data, nil
+}
+
+func (c *StoreClient) RemoveWebDL(params *store.RemoveWebDLParams) (*store.RemoveWebDLData, error) {
+	err := c.deleteTransferById(params.APIKey, params.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &store.RemoveWebDLData{Id: params.Id}
+	return data, nil
+}
+
+func (c *StoreClient) GenerateWebDLLink(params *store.GenerateWebDLLinkParams) (*store.GenerateWebDLLinkData, error) {
+	data := &store.GenerateWebDLLinkData{Link: params.Link}
+	return data, nil
+}
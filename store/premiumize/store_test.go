@@ -0,0 +1,32 @@
+package premiumize
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWebDLStatusForTransfer(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		transfer ListTransfersDataItem
+		status   store.WebDLStatus
+	}{
+		{"finished", ListTransfersDataItem{Status: TransferStatusFinished}, store.WebDLStatusDownloaded},
+		{"seeding", ListTransfersDataItem{Status: TransferStatusSeeding}, store.WebDLStatusDownloaded},
+		{"queued", ListTransfersDataItem{Status: TransferStatusQueued}, store.WebDLStatusQueued},
+		{"waiting", ListTransfersDataItem{Status: TransferStatusWaiting}, store.WebDLStatusQueued},
+		{"running without progress", ListTransfersDataItem{Status: TransferStatusRunning}, store.WebDLStatusQueued},
+		{"running", ListTransfersDataItem{Status: TransferStatusRunning, Progress: 0.5}, store.WebDLStatusDownloading},
+		{"banned", ListTransfersDataItem{Status: TransferStatusBanned}, store.WebDLStatusFailed},
+		{"deleted", ListTransfersDataItem{Status: TransferStatusDeleted}, store.WebDLStatusFailed},
+		{"error", ListTransfersDataItem{Status: TransferStatusError}, store.WebDLStatusFailed},
+		{"timeout", ListTransfersDataItem{Status: TransferStatusTimeout}, store.WebDLStatusFailed},
+		{"other", ListTransfersDataItem{Status: "other"}, store.WebDLStatusUnknown},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.status, getWebDLStatusForTransfer(&tc.transfer))
+		})
+	}
+}
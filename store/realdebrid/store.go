@@ -15,6 +15,11 @@ import (
 	"github.com/MunifTanjim/stremthru/store"
 )
 
+var (
+	_ store.Store      = (*StoreClient)(nil)
+	_ store.WebDLStore = (*StoreClient)(nil)
+)
+
 func torrentStatusToMagnetStatus(status TorrentStatus) store.MagnetStatus {
 	switch status {
 	case TorrentStatusMagnetError:
@@ -509,3 +514,198 @@ func (c *StoreClient) RemoveMagnet(params *store.RemoveMagnetParams) (*store.Rem
 	}
 	return data, nil
 }
+
+// getWebDLStatusForCheckLinkError returns false if the error is not about
+// the link itself.
+func getWebDLStatusForCheckLinkError(err error) (store.WebDLStatus, bool) {
+	uerr, ok := err.(*core.UpstreamError)
+	if !ok {
+		return "", false
+	}
+	rerr, ok := uerr.UpstreamCause.(*ResponseError)
+	if !ok {
+		return "", false
+	}
+	switch rerr.ErrCode {
+	case ErrorCodeUnsupportedHoster, ErrorCodeFileUnavailable, ErrorCodeInfringingFile, ErrorCodeBadParameterValue:
+		return store.WebDLStatusInvalid, true
+	case ErrorCodeHosterInMaintenance, ErrorCodeHosterTemporarilyUnavailable:
+		return store.WebDLStatusUnknown, true
+	default:
+		return "", false
+	}
+}
+
+func (c *StoreClient) CheckWebDL(params *store.CheckWebDLParams) (*store.CheckWebDLData, error) {
+	data := &store.CheckWebDLData{
+		Items: []store.CheckWebDLDataItem{},
+	}
+	for _, link := range params.Links {
+		item := store.CheckWebDLDataItem{
+			Link:   link,
+			Status: store.WebDLStatusUnknown,
+		}
+		res, err := c.client.CheckLink(&CheckLinkParams{
+			Ctx:  params.Ctx,
+			Link: link,
+		})
+		if err != nil {
+			status, ok := getWebDLStatusForCheckLinkError(err)
+			if !ok {
+				return nil, err
+			}
+			item.Status = status
+		} else if res.Data.Supported == 1 {
+			item.Name = res.Data.Filename
+			item.Size = res.Data.Filesize
+			item.Status = store.WebDLStatusCached
+		} else {
+			item.Status = store.WebDLStatusInvalid
+		}
+		data.Items = append(data.Items, item)
+	}
+	return data, nil
+}
+
+func (c *StoreClient) AddWebDL(params *store.AddWebDLParams) (*store.AddWebDLData, error) {
+	res, err := c.client.UnrestrictLink(&UnrestrictLinkParams{
+		Ctx:      params.Ctx,
+		Link:     params.Link,
+		Password: params.Password,
+		IP:       params.ClientIP,
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := &store.AddWebDLData{
+		Id:     res.Data.Id,
+		Link:   res.Data.Link,
+		Name:   res.Data.Filename,
+		Size:   int64(res.Data.Filesize),
+		Status: store.WebDLStatusDownloaded,
+		Files: []store.WebDLFile{
+			{
+				Idx:  0,
+				Link: res.Data.Link,
+				Path: "/" + res.Data.Filename,
+				Name: res.Data.Filename,
+				Size: int64(res.Data.Filesize),
+			},
+		},
+		AddedAt: time.Now().UTC(),
+	}
+	return data, nil
+}
+
+// max downloads looked up by GetWebDL, realdebrid does not have endpoint to
+// get single download
+const getWebDLMaxListItems = 5000
+
+// GetWebDL looks up the download in the latest 5000 downloads only, older
+// ones are reported as not found.
+func (c *StoreClient) GetWebDL(params *store.GetWebDLParams) (*store.GetWebDLData, error) {
+	res, err := c.client.ListDownloads(&ListDownloadsParams{
+		Ctx:   params.Ctx,
+		Limit: getWebDLMaxListItems,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range res.Data {
+		dl := &res.Data[i]
+		if dl.Id != params.Id {
+			continue
+		}
+		data := &store.GetWebDLData{
+			Id:     dl.Id,
+			Link:   dl.Link,
+			Name:   dl.Filename,
+			Size:   dl.Filesize,
+			Status: store.WebDLStatusDownloaded,
+			Files: []store.WebDLFile{
+				{
+					Idx:  0,
+					Link: dl.Link,
+					Path: "/" + dl.Filename,
+					Name: dl.Filename,
+					Size: dl.Filesize,
+				},
+			},
+			AddedAt: dl.Generated.UTC(),
+		}
+		return data, nil
+	}
+	msg := "not found"
+	if total, err := strconv.Atoi(res.Header.Get("X-Total-Count")); err == nil && total > getWebDLMaxListItems {
+		msg += ", only latest " + strconv.Itoa(getWebDLMaxListItems) + " downloads are looked up"
+	}
+	error := core.NewAPIError(msg)
+	error.StatusCode = http.StatusNotFound
+	error.StoreName = string(store.StoreNameRealDebrid)
+	return nil, error
+}
+
+func (c *StoreClient) ListWebDLs(params *store.ListWebDLsParams) (*store.ListWebDLsData, error) {
+	res, err := c.client.ListDownloads(&ListDownloadsParams{
+		Ctx:    params.Ctx,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sTotal := res.Header.Get("X-Total-Count")
+	if sTotal == "" {
+		sTotal = "0"
+	}
+	total, err := strconv.Atoi(sTotal)
+	if err != nil {
+		return nil, err
+	}
+	data := &store.ListWebDLsData{
+		Items:      []store.ListWebDLsDataItem{},
+		TotalItems: total,
+	}
+	for _, dl := range res.Data {
+		item := store.ListWebDLsDataItem{
+			Id:      dl.Id,
+			Link:    dl.Link,
+			Name:    dl.Filename,
+			Size:    dl.Filesize,
+			Status:  store.WebDLStatusDownloaded,
+			AddedAt: dl.Generated.UTC(),
+		}
+		data.Items = append(data.Items, item)
+	}
+	return data, nil
+}
+
+func (c *StoreClient) RemoveWebDL(params *store.RemoveWebDLParams) (*store.RemoveWebDLData, error) {
+	_, err := c.client.DeleteDownload(&DeleteDownloadParams{
+		Ctx: params.Ctx,
+		Id:  params.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := &store.RemoveWebDLData{
+		Id: params.Id,
+	}
+	return data, nil
+}
+
+func (c *StoreClient) GenerateWebDLLink(params *store.GenerateWebDLLinkParams) (*store.GenerateWebDLLinkData, error) {
+	res, err := c.client.UnrestrictLink(&UnrestrictLinkParams{
+		Ctx:  params.Ctx,
+		Link: params.Link,
+		IP:   params.ClientIP,
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := &store.GenerateWebDLLinkData{
+		Link: res.Data.Download,
+	}
+	return data, nil
+}
@@ -0,0 +1,89 @@
+package realdebrid
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWebDLStatusForCheckLinkError(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		err    error
+		status store.WebDLStatus
+		ok     bool
+	}{
+		{"unsupported hoster", UpstreamErrorWithCause(&ResponseError{ErrCode: ErrorCodeUnsupportedHoster}), store.WebDLStatusInvalid, true},
+		{"file unavailable", UpstreamErrorWithCause(&ResponseError{ErrCode: ErrorCodeFileUnavailable}), store.WebDLStatusInvalid, true},
+		{"infringing file", UpstreamErrorWithCause(&ResponseError{ErrCode: ErrorCodeInfringingFile}), store.WebDLStatusInvalid, true},
+		{"bad parameter value", UpstreamErrorWithCause(&ResponseError{ErrCode: ErrorCodeBadParameterValue}), store.WebDLStatusInvalid, true},
+		{"hoster in maintenance", UpstreamErrorWithCause(&ResponseError{ErrCode: ErrorCodeHosterInMaintenance}), store.WebDLStatusUnknown, true},
+		{"hoster temporarily unavailable", UpstreamErrorWithCause(&ResponseError{ErrCode: ErrorCodeHosterTemporarilyUnavailable}), store.WebDLStatusUnknown, true},
+		{"bad token", UpstreamErrorWithCause(&ResponseError{ErrCode: ErrorCodeBadToken}), "", false},
+		{"upstream without cause", UpstreamErrorWithCause(errors.New("timeout")), "", false},
+		{"other error", errors.New("timeout"), "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			status, ok := getWebDLStatusForCheckLinkError(tc.err)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.status, status)
+		})
+	}
+}
+
+func TestGetWebDL(t *testing.T) {
+	totalCount := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/1.0/downloads", r.URL.Path)
+		assert.Equal(t, strconv.Itoa(getWebDLMaxListItems), r.URL.Query().Get("limit"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Count", strconv.Itoa(totalCount))
+		json.NewEncoder(w).Encode(ListDownloadsData{
+			{Id: "dl1", Filename: "file.mkv", Filesize: 100, Link: "https://hoster.example/f/1"},
+		})
+	}))
+	defer upstream.Close()
+
+	c := &StoreClient{
+		client: NewAPIClient(&APIClientConfig{BaseURL: upstream.URL}),
+	}
+	getWebDL := func(id string) (*store.GetWebDLData, error) {
+		params := &store.GetWebDLParams{Id: id}
+		params.APIKey = "token"
+		return c.GetWebDL(params)
+	}
+
+	t.Run("found", func(t *testing.T) {
+		totalCount = 1
+		data, err := getWebDL("dl1")
+		assert.NoError(t, err)
+		assert.Equal(t, "dl1", data.Id)
+		assert.Equal(t, store.WebDLStatusDownloaded, data.Status)
+		assert.Len(t, data.Files, 1)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		totalCount = 1
+		_, err := getWebDL("dl2")
+		var aerr *core.APIError
+		assert.ErrorAs(t, err, &aerr)
+		assert.Equal(t, http.StatusNotFound, aerr.StatusCode)
+		assert.Equal(t, "not found", aerr.Msg)
+	})
+
+	t.Run("not found beyond max list items", func(t *testing.T) {
+		totalCount = getWebDLMaxListItems + 1
+		_, err := getWebDL("dl2")
+		var aerr *core.APIError
+		assert.ErrorAs(t, err, &aerr)
+		assert.Equal(t, http.StatusNotFound, aerr.StatusCode)
+		assert.Contains(t, aerr.Msg, "only latest 5000 downloads")
+	})
+}
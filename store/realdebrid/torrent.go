@@ -370,6 +370,33 @@ func (c APIClient) UnrestrictLink(params *UnrestrictLinkParams) (APIResponse[Unr
 
 }
 
+type CheckLinkData struct {
+	*ResponseError
+	Host      string `json:"host"`      // Host main domain
+	Link      string `json:"link"`      // Original link
+	Filename  string `json:"filename"`  //
+	Filesize  int64  `json:"filesize"`  // Filesize in bytes, 0 if unknown
+	Supported int    `json:"supported"` // 0 or 1
+}
+
+type CheckLinkParams struct {
+	Ctx
+	Link     string // The original hoster link
+	Password string // Password to unlock the file access hoster side
+}
+
+func (c APIClient) CheckLink(params *CheckLinkParams) (APIResponse[CheckLinkData], error) {
+	form := &url.Values{}
+	form.Add("link", params.Link)
+	if len(params.Password) > 0 {
+		form.Add("password", params.Password)
+	}
+	params.Form = form
+	response := &CheckLinkData{}
+	res, err := c.Request("POST", "/rest/1.0/unrestrict/check", params, response)
+	return newAPIResponse(res, *response), err
+}
+
 type DeleteTorrentData struct {
 	*ResponseError
 }
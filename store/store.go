@@ -97,6 +97,7 @@ type Ctx = request.Ctx
 var (
 	_ File = (*MagnetFile)(nil)
 	_ File = (*NewzFile)(nil)
+	_ File = (*WebDLFile)(nil)
 )
 
 type File interface {
@@ -453,3 +454,145 @@ type NewzStore interface {
 	RemoveNewz(params *RemoveNewzParams) (*RemoveNewzData, error)
 	GenerateNewzLink(params *GenerateNewzLinkParams) (*GenerateNewzLinkData, error)
 }
+
+type WebDLStatus string
+
+const (
+	WebDLStatusCached      WebDLStatus = "cached" // available in store, ready to download instantly
+	WebDLStatusQueued      WebDLStatus = "queued"
+	WebDLStatusDownloading WebDLStatus = "downloading"
+	WebDLStatusProcessing  WebDLStatus = "processing"
+	WebDLStatusDownloaded  WebDLStatus = "downloaded"
+	WebDLStatusFailed      WebDLStatus = "failed"
+	WebDLStatusInvalid     WebDLStatus = "invalid" // not supported by store
+	WebDLStatusUnknown     WebDLStatus = "unknown"
+)
+
+type WebDLFile struct {
+	Idx  int    `json:"index"`
+	Link string `json:"link,omitempty"`
+	Path string `json:"path"`
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+func (f *WebDLFile) GetIdx() int {
+	return f.Idx
+}
+
+func (f *WebDLFile) GetPath() string {
+	return f.Path
+}
+
+func (f *WebDLFile) GetName() string {
+	return f.Name
+}
+
+func (f *WebDLFile) GetSize() int64 {
+	return f.Size
+}
+
+func (f *WebDLFile) GetLink() string {
+	return f.Link
+}
+
+type CheckWebDLParams struct {
+	Ctx
+	Links    []string
+	ClientIP string
+}
+
+type CheckWebDLDataItem struct {
+	Link   string      `json:"link"`
+	Name   string      `json:"name,omitempty"`
+	Size   int64       `json:"size,omitempty"`
+	Status WebDLStatus `json:"status"`
+}
+
+type CheckWebDLData struct {
+	Items []CheckWebDLDataItem `json:"items"`
+}
+
+type AddWebDLParams struct {
+	Ctx
+	Link     string
+	Password string
+	ClientIP string
+}
+
+type AddWebDLData struct {
+	Id      string      `json:"id"`
+	Link    string      `json:"link"`
+	Name    string      `json:"name"`
+	Size    int64       `json:"size"`
+	Status  WebDLStatus `json:"status"`
+	Files   []WebDLFile `json:"files"`
+	AddedAt time.Time   `json:"added_at"`
+}
+
+type GetWebDLParams struct {
+	Ctx
+	Id       string
+	ClientIP string
+}
+
+type GetWebDLData struct {
+	Id      string      `json:"id"`
+	Link    string      `json:"link"`
+	Name    string      `json:"name"`
+	Size    int64       `json:"size"`
+	Status  WebDLStatus `json:"status"`
+	Files   []WebDLFile `json:"files"`
+	AddedAt time.Time   `json:"added_at"`
+}
+
+type ListWebDLsParams struct {
+	Ctx
+	Limit    int // min 1, max 500, default 100
+	Offset   int // default 0
+	ClientIP string
+}
+
+type ListWebDLsDataItem struct {
+	Id      string      `json:"id"`
+	Link    string      `json:"link"`
+	Name    string      `json:"name"`
+	Size    int64       `json:"size"`
+	Status  WebDLStatus `json:"status"`
+	AddedAt time.Time   `json:"added_at"`
+}
+
+type ListWebDLsData struct {
+	Items      []ListWebDLsDataItem `json:"items"`
+	TotalItems int                  `json:"total_items"`
+}
+
+type RemoveWebDLParams struct {
+	Ctx
+	Id string
+}
+
+type RemoveWebDLData struct {
+	Id string `json:"id"`
+}
+
+type GenerateWebDLLinkParams struct {
+	Ctx
+	Link     string
+	ClientIP string
+}
+
+type GenerateWebDLLinkData struct {
+	Link string `json:"link"`
+}
+
+type WebDLStore interface {
+	GetName() StoreName
+	GetUser(params *GetUserParams) (*User, error)
+	CheckWebDL(params *CheckWebDLParams) (*CheckWebDLData, error)
+	AddWebDL(params *AddWebDLParams) (*AddWebDLData, error)
+	GetWebDL(params *GetWebDLParams) (*GetWebDLData, error)
+	ListWebDLs(params *ListWebDLsParams) (*ListWebDLsData, error)
+	RemoveWebDL(params *RemoveWebDLParams) (*RemoveWebDLData, error)
+	GenerateWebDLLink(params *GenerateWebDLLinkParams) (*GenerateWebDLLinkData, error)
+}
@@ -11,6 +11,7 @@ import (
 	usenet_pool "github.com/MunifTanjim/stremthru/internal/usenet/pool"
 	"github.com/MunifTanjim/stremthru/internal/util"
 	"github.com/MunifTanjim/stremthru/store"
+	"github.com/anacrolix/torrent"
 	"github.com/golang-jwt/jwt/v5"
 	"net/http"
 	"strings"
@@ -35,13 +36,6 @@ func NewStoreClient(config *StoreClientConfig) *StoreClient {
 	return c
 }
 
-func notImplementedError() error {
-	err := core.NewStoreError("not implemented")
-	err.StoreName = string(store.StoreNameStremThru)
-	err.StatusCode = http.StatusNotImplemented
-	return err
-}
-
 func notFoundError() error {
 	err := core.NewStoreError("not found")
 	err.StoreName = string(store.StoreNameStremThru)
@@ -87,27 +81,220 @@ func (c *StoreClient) GetUser(params *store.GetUserParams) (*store.User, error)
 }
 
 func (c *StoreClient) AddMagnet(params *store.AddMagnetParams) (*store.AddMagnetData, error) {
-	return nil, notImplementedError()
+	_, err := c.ensureAuthed(&params.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	m, err := getTorrentManager()
+	if err != nil {
+		return nil, err
+	}
+
+	var t *torrent.Torrent
+	var magnet core.MagnetLink
+	if params.Torrent != nil {
+		mi, _, err := params.GetTorrentMeta()
+		if err != nil {
+			return nil, err
+		}
+		t, err = m.addTorrent(mi)
+		if err != nil {
+			return nil, err
+		}
+		magnet, err = core.ParseMagnetLink(t.InfoHash().HexString())
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		magnet, err = core.ParseMagnetLink(params.Magnet)
+		if err != nil {
+			return nil, err
+		}
+		if magnet.Hash == "" {
+			err := core.NewStoreError("invalid magnet")
+			err.StoreName = string(store.StoreNameStremThru)
+			err.StatusCode = http.StatusBadRequest
+			return nil, err
+		}
+		t, err = m.addMagnet(&magnet)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	hash := t.InfoHash().HexString()
+	data := &store.AddMagnetData{
+		Id:      hash,
+		Hash:    hash,
+		Magnet:  magnet.Link,
+		Name:    getTorrentName(t, magnet.Name),
+		Size:    getTorrentSize(t),
+		Status:  getTorrentStatus(t),
+		Files:   getTorrentFiles(t, true),
+		Private: isTorrentPrivate(t),
+		AddedAt: m.getAddedAt(hash),
+	}
+	return data, nil
 }
 
 func (c *StoreClient) CheckMagnet(params *store.CheckMagnetParams) (*store.CheckMagnetData, error) {
-	return nil, notImplementedError()
+	_, err := c.ensureAuthed(&params.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	m, err := getTorrentManager()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]store.CheckMagnetDataItem, 0, len(params.Magnets))
+	for _, link := range params.Magnets {
+		magnet, err := core.ParseMagnetLink(link)
+		if err != nil || magnet.Hash == "" {
+			continue
+		}
+		item := store.CheckMagnetDataItem{
+			Hash:   magnet.Hash,
+			Magnet: magnet.Link,
+			Status: store.MagnetStatusUnknown,
+			Files:  []store.MagnetFile{},
+		}
+		if t := m.get(magnet.Hash); t != nil {
+			item.Name = getTorrentName(t, magnet.Name)
+			item.Size = getTorrentSize(t)
+			if status := getTorrentStatus(t); status == store.MagnetStatusDownloaded {
+				item.Status = store.MagnetStatusCached
+				item.Files = getTorrentFiles(t, false)
+			} else {
+				item.Status = status
+			}
+		}
+		items = append(items, item)
+	}
+	return &store.CheckMagnetData{Items: items}, nil
 }
 
 func (c *StoreClient) GenerateLink(params *store.GenerateLinkParams) (*store.GenerateLinkData, error) {
-	return nil, notImplementedError()
+	ba, err := c.ensureAuthed(&params.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	hash, path, err := LockedFileLink(params.Link).Parse()
+	if err != nil {
+		return nil, err
+	}
+	m, err := getTorrentManager()
+	if err != nil {
+		return nil, err
+	}
+	t := m.get(hash)
+	if t == nil {
+		return nil, notFoundError()
+	}
+
+	var file *store.MagnetFile
+	files := getTorrentFiles(t, false)
+	for i := range files {
+		f := &files[i]
+		if f.Path == path {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return nil, notFoundError()
+	}
+
+	link, err := createStreamLink(ba, params.Link, "/v0/store/torz/stream", file.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &store.GenerateLinkData{Link: link}, nil
 }
 
 func (c *StoreClient) GetMagnet(params *store.GetMagnetParams) (*store.GetMagnetData, error) {
-	return nil, notImplementedError()
+	_, err := c.ensureAuthed(&params.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	m, err := getTorrentManager()
+	if err != nil {
+		return nil, err
+	}
+	t := m.get(params.Id)
+	if t == nil {
+		return nil, notFoundError()
+	}
+
+	hash := t.InfoHash().HexString()
+	data := &store.GetMagnetData{
+		Id:      hash,
+		Name:    getTorrentName(t, ""),
+		Hash:    hash,
+		Size:    getTorrentSize(t),
+		Status:  getTorrentStatus(t),
+		Files:   getTorrentFiles(t, true),
+		Private: isTorrentPrivate(t),
+		AddedAt: m.getAddedAt(hash),
+	}
+	return data, nil
 }
 
 func (c *StoreClient) ListMagnets(params *store.ListMagnetsParams) (*store.ListMagnetsData, error) {
-	return nil, notImplementedError()
+	_, err := c.ensureAuthed(&params.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	m, err := getTorrentManager()
+	if err != nil {
+		return nil, err
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	offset := max(params.Offset, 0)
+
+	torrents := m.list()
+	total := len(torrents)
+	torrents = torrents[min(offset, total):min(offset+limit, total)]
+
+	items := make([]store.ListMagnetsDataItem, len(torrents))
+	for i, t := range torrents {
+		hash := t.InfoHash().HexString()
+		items[i] = store.ListMagnetsDataItem{
+			Id:      hash,
+			Hash:    hash,
+			Name:    getTorrentName(t, ""),
+			Size:    getTorrentSize(t),
+			Status:  getTorrentStatus(t),
+			Private: isTorrentPrivate(t),
+			AddedAt: m.getAddedAt(hash),
+		}
+	}
+
+	return &store.ListMagnetsData{
+		Items:      items,
+		TotalItems: total,
+	}, nil
 }
 
 func (c *StoreClient) RemoveMagnet(params *store.RemoveMagnetParams) (*store.RemoveMagnetData, error) {
-	return nil, notImplementedError()
+	_, err := c.ensureAuthed(&params.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	m, err := getTorrentManager()
+	if err != nil {
+		return nil, err
+	}
+	if t := m.get(params.Id); t != nil {
+		if err := m.remove(t); err != nil {
+			return nil, err
+		}
+	}
+	return &store.RemoveMagnetData{Id: params.Id}, nil
 }
 
 func (c *StoreClient) CheckNewz(params *store.CheckNewzParams) (*store.CheckNewzData, error) {
@@ -410,11 +597,36 @@ func (c *StoreClient) RemoveNewz(params *store.RemoveNewzParams) (*store.RemoveN
 	return &store.RemoveNewzData{Id: params.Id}, nil
 }
 
-type newzStreamTokenData struct {
+type streamTokenData struct {
 	EncLink   string `json:"enc_link"`
 	EncFormat string `json:"enc_format"`
 }
 
+func createStreamLink(ba *util.BasicAuth, lockedLink, basePath, filename string) (string, error) {
+	encLink, err := core.Encrypt(ba.Password, lockedLink)
+	if err != nil {
+		return "", err
+	}
+
+	claims := core.JWTClaims[streamTokenData]{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "stremthru",
+			Subject:   ba.Username,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(12 * time.Hour)),
+		},
+		Data: &streamTokenData{
+			EncLink:   encLink,
+			EncFormat: core.EncryptionFormat,
+		},
+	}
+	token, err := core.CreateJWT(ba.Password, claims)
+	if err != nil {
+		return "", err
+	}
+
+	return config.BaseURL.JoinPath(basePath, token, filename).String(), nil
+}
+
 func (c *StoreClient) GenerateNewzLink(params *store.GenerateNewzLinkParams) (*store.GenerateNewzLinkData, error) {
 	ba, err := c.ensureAuthed(&params.Ctx)
 	if err != nil {
@@ -447,43 +659,30 @@ func (c *StoreClient) GenerateNewzLink(params *store.GenerateNewzLinkParams) (*s
 		return nil, notFoundError()
 	}
 
-	encLink, err := core.Encrypt(ba.Password, params.Link)
+	link, err := createStreamLink(ba, params.Link, "/v0/store/newz/stream", file.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	claims := core.JWTClaims[newzStreamTokenData]{
-		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    "stremthru",
-			Subject:   ba.Username,
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(12 * time.Hour)),
-		},
-		Data: &newzStreamTokenData{
-			EncLink:   encLink,
-			EncFormat: core.EncryptionFormat,
-		},
-	}
-	token, err := core.CreateJWT(ba.Password, claims)
-	if err != nil {
-		return nil, err
-	}
-
-	link := config.BaseURL.JoinPath("/v0/store/newz/stream", token, file.Name).String()
-
 	return &store.GenerateNewzLinkData{Link: link}, nil
 }
 
-type unwrappedNewzStreamTokenData struct {
+type unwrappedStreamTokenData struct {
 	User     string `json:"u"`
 	ID       string `json:"id"`
 	FilePath string `json:"fp"`
 }
 
-var newzStreamTokenDataCache = cache.NewCache[unwrappedNewzStreamTokenData](&cache.CacheConfig{
+var newzStreamTokenDataCache = cache.NewCache[unwrappedStreamTokenData](&cache.CacheConfig{
 	Name:     "store:stremthru:newz-stream-token",
 	Lifetime: 30 * time.Minute,
 })
 
+var torzStreamTokenDataCache = cache.NewCache[unwrappedStreamTokenData](&cache.CacheConfig{
+	Name:     "store:stremthru:torz-stream-token",
+	Lifetime: 30 * time.Minute,
+})
+
 func getUserCredsFromJWT(t *jwt.Token) (user, password string, err error) {
 	user, err = t.Claims.GetSubject()
 	if err != nil {
@@ -493,13 +692,13 @@ func getUserCredsFromJWT(t *jwt.Token) (user, password string, err error) {
 	return user, password, nil
 }
 
-func UnwrapNewzStreamToken(encodedToken string) (user, id, path string, err error) {
-	linkData := &unwrappedNewzStreamTokenData{}
-	if found := newzStreamTokenDataCache.Get(encodedToken, linkData); found {
+func unwrapStreamToken(tokenCache cache.Cache[unwrappedStreamTokenData], encodedToken string) (user, id, path string, err error) {
+	linkData := &unwrappedStreamTokenData{}
+	if found := tokenCache.Get(encodedToken, linkData); found {
 		return linkData.User, linkData.ID, linkData.FilePath, nil
 	}
 
-	claims := &core.JWTClaims[newzStreamTokenData]{}
+	claims := &core.JWTClaims[streamTokenData]{}
 	password := ""
 	_, err = core.ParseJWT(func(t *jwt.Token) (any, error) {
 		user, password, err = getUserCredsFromJWT(t)
@@ -531,7 +730,15 @@ func UnwrapNewzStreamToken(encodedToken string) (user, id, path string, err erro
 	linkData.ID = id
 	linkData.FilePath = path
 
-	newzStreamTokenDataCache.Add(encodedToken, *linkData)
+	tokenCache.Add(encodedToken, *linkData)
 
 	return linkData.User, linkData.ID, linkData.FilePath, nil
 }
+
+func UnwrapNewzStreamToken(encodedToken string) (user, id, path string, err error) {
+	return unwrapStreamToken(newzStreamTokenDataCache, encodedToken)
+}
+
+func UnwrapTorzStreamToken(encodedToken string) (user, hash, path string, err error) {
+	return unwrapStreamToken(torzStreamTokenDataCache, encodedToken)
+}
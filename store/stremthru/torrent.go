@@ -0,0 +1,365 @@
+package stremthru
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/internal/config"
+	"github.com/MunifTanjim/stremthru/internal/logger"
+	"github.com/MunifTanjim/stremthru/store"
+	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/anacrolix/torrent/storage"
+)
+
+var torrentLog = logger.Scoped("store/stremthru/torrent")
+
+const (
+	torrentFileExt = ".torrent"
+	magnetFileExt  = ".magnet"
+)
+
+type torrentManager struct {
+	client      *torrent.Client
+	metaDir     string
+	storageDir  string
+	addedAt     map[string]time.Time
+	addedAtLock sync.RWMutex
+}
+
+func (m *torrentManager) metaFilePath(hash, ext string) string {
+	return filepath.Join(m.metaDir, hash+ext)
+}
+
+func (m *torrentManager) getAddedAt(hash string) time.Time {
+	m.addedAtLock.RLock()
+	defer m.addedAtLock.RUnlock()
+	return m.addedAt[hash]
+}
+
+func (m *torrentManager) setAddedAt(hash string, t time.Time) {
+	m.addedAtLock.Lock()
+	defer m.addedAtLock.Unlock()
+	if _, ok := m.addedAt[hash]; !ok {
+		m.addedAt[hash] = t
+	}
+}
+
+func (m *torrentManager) removeAddedAt(hash string) {
+	m.addedAtLock.Lock()
+	defer m.addedAtLock.Unlock()
+	delete(m.addedAt, hash)
+}
+
+// persistOnInfo writes the metainfo to disk once it is available, so that the
+// torrent can be restored after restart without going through the DHT again.
+func (m *torrentManager) persistOnInfo(t *torrent.Torrent) {
+	hash := t.InfoHash().HexString()
+	select {
+	case <-t.GotInfo():
+	case <-t.Closed():
+		// dropped before the metainfo arrived
+		return
+	}
+	if t.Info() == nil {
+		return
+	}
+	mi := t.Metainfo()
+	f, err := os.Create(m.metaFilePath(hash, torrentFileExt))
+	if err != nil {
+		torrentLog.Error("failed to create torrent file", "error", err, "hash", hash)
+		return
+	}
+	defer f.Close()
+	if err := mi.Write(f); err != nil {
+		torrentLog.Error("failed to write torrent file", "error", err, "hash", hash)
+		return
+	}
+	if err := os.Remove(m.metaFilePath(hash, magnetFileExt)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		torrentLog.Warn("failed to remove magnet file", "error", err, "hash", hash)
+	}
+	t.DownloadAll()
+}
+
+func (m *torrentManager) track(t *torrent.Torrent, addedAt time.Time) {
+	m.setAddedAt(t.InfoHash().HexString(), addedAt)
+	go m.persistOnInfo(t)
+}
+
+func (m *torrentManager) restore() error {
+	entries, err := os.ReadDir(m.metaDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		if ext != torrentFileExt && ext != magnetFileExt {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(m.metaDir, name)
+		var t *torrent.Torrent
+		switch ext {
+		case torrentFileExt:
+			mi, err := metainfo.LoadFromFile(path)
+			if err != nil {
+				torrentLog.Warn("failed to load torrent file", "error", err, "path", path)
+				continue
+			}
+			t, err = m.client.AddTorrent(mi)
+			if err != nil {
+				torrentLog.Warn("failed to restore torrent", "error", err, "path", path)
+				continue
+			}
+		case magnetFileExt:
+			link, err := os.ReadFile(path)
+			if err != nil {
+				torrentLog.Warn("failed to read magnet file", "error", err, "path", path)
+				continue
+			}
+			t, err = m.client.AddMagnet(strings.TrimSpace(string(link)))
+			if err != nil {
+				torrentLog.Warn("failed to restore magnet", "error", err, "path", path)
+				continue
+			}
+		}
+		m.track(t, info.ModTime())
+	}
+	return nil
+}
+
+func newTorrentManager(baseDir string, cfg *torrent.ClientConfig) (*torrentManager, error) {
+	m := &torrentManager{
+		metaDir:    filepath.Join(baseDir, "meta"),
+		storageDir: filepath.Join(baseDir, "data"),
+		addedAt:    map[string]time.Time{},
+	}
+	for _, dir := range []string{m.metaDir, m.storageDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.DataDir = m.storageDir
+	cfg.DefaultStorage = storage.NewFileByInfoHash(m.storageDir)
+
+	client, err := torrent.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	m.client = client
+
+	if err := m.restore(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return m, nil
+}
+
+var getTorrentManager = sync.OnceValues(func() (*torrentManager, error) {
+	cfg := torrent.NewDefaultClientConfig()
+	cfg.ListenPort = config.Torz.TorrentClientListenPort
+	cfg.Seed = config.Torz.TorrentClientSeed
+
+	m, err := newTorrentManager(filepath.Join(config.DataDir, "torrent"), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	torrentLog.Info("torrent client started", "port", cfg.ListenPort, "torrents", len(m.client.Torrents()))
+
+	return m, nil
+})
+
+func (m *torrentManager) get(hash string) *torrent.Torrent {
+	var ih metainfo.Hash
+	if err := ih.FromHexString(hash); err != nil {
+		return nil
+	}
+	t, ok := m.client.Torrent(ih)
+	if !ok {
+		return nil
+	}
+	return t
+}
+
+func (m *torrentManager) addMagnet(magnet *core.MagnetLink) (*torrent.Torrent, error) {
+	if t := m.get(magnet.Hash); t != nil {
+		return t, nil
+	}
+	t, err := m.client.AddMagnet(magnet.RawLink)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(m.metaFilePath(magnet.Hash, magnetFileExt), []byte(magnet.RawLink), 0644); err != nil {
+		t.Drop()
+		return nil, err
+	}
+	m.track(t, time.Now())
+	return t, nil
+}
+
+func (m *torrentManager) addTorrent(mi *metainfo.MetaInfo) (*torrent.Torrent, error) {
+	if t := m.get(mi.HashInfoBytes().HexString()); t != nil {
+		return t, nil
+	}
+	t, err := m.client.AddTorrent(mi)
+	if err != nil {
+		return nil, err
+	}
+	m.track(t, time.Now())
+	return t, nil
+}
+
+func (m *torrentManager) remove(t *torrent.Torrent) error {
+	hash := t.InfoHash().HexString()
+	t.Drop()
+	m.removeAddedAt(hash)
+	for _, ext := range []string{torrentFileExt, magnetFileExt} {
+		if err := os.Remove(m.metaFilePath(hash, ext)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.RemoveAll(filepath.Join(m.storageDir, hash))
+}
+
+func (m *torrentManager) list() []*torrent.Torrent {
+	torrents := m.client.Torrents()
+	slices.SortFunc(torrents, func(a, b *torrent.Torrent) int {
+		return m.getAddedAt(b.InfoHash().HexString()).Compare(m.getAddedAt(a.InfoHash().HexString()))
+	})
+	return torrents
+}
+
+func hasTorrentInfo(t *torrent.Torrent) bool {
+	select {
+	case <-t.GotInfo():
+		return t.Info() != nil
+	default:
+		return false
+	}
+}
+
+func getTorrentStatus(t *torrent.Torrent) store.MagnetStatus {
+	if !hasTorrentInfo(t) {
+		return store.MagnetStatusQueued
+	}
+	completed := t.BytesCompleted()
+	if completed >= t.Length() {
+		return store.MagnetStatusDownloaded
+	}
+	return store.MagnetStatusDownloading
+}
+
+func isTorrentPrivate(t *torrent.Torrent) bool {
+	if !hasTorrentInfo(t) {
+		return false
+	}
+	private := t.Info().Private
+	return private != nil && *private
+}
+
+func getTorrentFiles(t *torrent.Torrent, withLink bool) []store.MagnetFile {
+	files := []store.MagnetFile{}
+	if !hasTorrentInfo(t) {
+		return files
+	}
+	hash := t.InfoHash().HexString()
+	source := string(store.StoreCodeStremThru)
+	for idx, f := range t.Files() {
+		path := "/" + f.Path()
+		file := store.MagnetFile{
+			Idx:    idx,
+			Path:   path,
+			Name:   filepath.Base(path),
+			Size:   f.Length(),
+			Source: source,
+		}
+		if withLink {
+			file.Link = LockedFileLink("").Create(hash, path)
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+func getTorrentName(t *torrent.Torrent, fallback string) string {
+	if hasTorrentInfo(t) {
+		return t.Name()
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return t.InfoHash().HexString()
+}
+
+func getTorrentSize(t *torrent.Torrent) int64 {
+	if !hasTorrentInfo(t) {
+		return -1
+	}
+	return t.Length()
+}
+
+type TorrentFileStream struct {
+	torrent.Reader
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+func OpenTorrentFile(ctx context.Context, hash, path string) (*TorrentFileStream, error) {
+	m, err := getTorrentManager()
+	if err != nil {
+		return nil, err
+	}
+	t := m.get(hash)
+	if t == nil || !hasTorrentInfo(t) {
+		return nil, notFoundError()
+	}
+	for _, f := range t.Files() {
+		if "/"+f.Path() != path {
+			continue
+		}
+		r := f.NewReader()
+		r.SetContext(ctx)
+		r.SetResponsive()
+		return &TorrentFileStream{
+			Reader:  r,
+			Name:    filepath.Base(path),
+			Size:    f.Length(),
+			ModTime: m.getAddedAt(hash),
+		}, nil
+	}
+	return nil, notFoundError()
+}
+
+// ServeTorrentFile streams the file from the torrent, with support for range
+// requests.
+func ServeTorrentFile(w http.ResponseWriter, r *http.Request, hash, path string) error {
+	stream, err := OpenTorrentFile(r.Context(), hash, path)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	w.Header().Set("Accept-Ranges", "bytes")
+
+	http.ServeContent(w, r, stream.Name, stream.ModTime, stream)
+	return nil
+}
@@ -0,0 +1,271 @@
+package stremthru
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MunifTanjim/stremthru/core"
+	"github.com/MunifTanjim/stremthru/store"
+	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTorrentManager(t *testing.T, baseDir string) *torrentManager {
+	t.Helper()
+
+	cfg := torrent.NewDefaultClientConfig()
+	cfg.ListenPort = 0
+	cfg.NoDHT = true
+	cfg.DisableTrackers = true
+	cfg.NoDefaultPortForwarding = true
+	cfg.Seed = false
+
+	m, err := newTorrentManager(baseDir, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { m.client.Close() })
+
+	getTorrentManager = func() (*torrentManager, error) {
+		return m, nil
+	}
+	return m
+}
+
+// createTestTorrent builds a torrent from files, and places the content in the
+// manager's storage so that it is complete once verified.
+func createTestTorrent(t *testing.T, m *torrentManager, name string, files map[string][]byte) *metainfo.MetaInfo {
+	t.Helper()
+
+	srcDir := filepath.Join(t.TempDir(), name)
+	for path, content := range files {
+		path = filepath.Join(srcDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, content, 0644))
+	}
+
+	info := metainfo.Info{PieceLength: 16 * 1024}
+	require.NoError(t, info.BuildFromFilePath(srcDir))
+	infoBytes, err := bencode.Marshal(info)
+	require.NoError(t, err)
+	mi := &metainfo.MetaInfo{InfoBytes: infoBytes}
+
+	torrentDir := filepath.Join(m.storageDir, mi.HashInfoBytes().HexString())
+	require.NoError(t, os.MkdirAll(torrentDir, 0755))
+	require.NoError(t, os.Rename(srcDir, filepath.Join(torrentDir, name)))
+
+	return mi
+}
+
+func addTestTorrent(t *testing.T, m *torrentManager, mi *metainfo.MetaInfo) *torrent.Torrent {
+	t.Helper()
+
+	tor, err := m.addTorrent(mi)
+	require.NoError(t, err)
+	<-tor.GotInfo()
+	require.NoError(t, tor.VerifyData())
+	require.Eventually(t, func() bool {
+		return getTorrentStatus(tor) == store.MagnetStatusDownloaded
+	}, 5*time.Second, 10*time.Millisecond)
+	return tor
+}
+
+func TestTorrentManager(t *testing.T) {
+	baseDir := t.TempDir()
+	m := newTestTorrentManager(t, baseDir)
+
+	content := bytes.Repeat([]byte("0123456789"), 5000)
+	mi := createTestTorrent(t, m, "Show", map[string][]byte{
+		"S01E01.mkv":        content,
+		"extras/sample.txt": []byte("sample"),
+	})
+	hash := mi.HashInfoBytes().HexString()
+
+	t.Run("add torrent", func(t *testing.T) {
+		tor := addTestTorrent(t, m, mi)
+		assert.Equal(t, hash, tor.InfoHash().HexString())
+		assert.Equal(t, "Show", getTorrentName(tor, ""))
+		assert.Equal(t, int64(len(content)+len("sample")), getTorrentSize(tor))
+		assert.False(t, m.getAddedAt(hash).IsZero())
+
+		files := getTorrentFiles(tor, true)
+		assert.Len(t, files, 2)
+		paths := []string{}
+		for _, f := range files {
+			paths = append(paths, f.Path)
+			assert.Equal(t, string(store.StoreCodeStremThru), f.Source)
+			id, path, err := LockedFileLink(f.Link).Parse()
+			assert.NoError(t, err)
+			assert.Equal(t, hash, id)
+			assert.Equal(t, f.Path, path)
+		}
+		assert.ElementsMatch(t, []string{"/Show/S01E01.mkv", "/Show/extras/sample.txt"}, paths)
+
+		assert.Eventually(t, func() bool {
+			_, err := os.Stat(m.metaFilePath(hash, torrentFileExt))
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("add torrent again", func(t *testing.T) {
+		addedAt := m.getAddedAt(hash)
+		tor, err := m.addTorrent(mi)
+		assert.NoError(t, err)
+		assert.Equal(t, hash, tor.InfoHash().HexString())
+		assert.Equal(t, addedAt, m.getAddedAt(hash))
+	})
+
+	magnetHash := "0123456789abcdef0123456789abcdef01234567"
+
+	t.Run("add magnet", func(t *testing.T) {
+		magnet, err := core.ParseMagnetLink("magnet:?xt=urn:btih:" + magnetHash + "&dn=Movie")
+		require.NoError(t, err)
+
+		tor, err := m.addMagnet(&magnet)
+		assert.NoError(t, err)
+		assert.Equal(t, magnetHash, tor.InfoHash().HexString())
+		assert.Equal(t, store.MagnetStatusQueued, getTorrentStatus(tor))
+		assert.Equal(t, "Movie", getTorrentName(tor, magnet.Name))
+		assert.Equal(t, int64(-1), getTorrentSize(tor))
+		assert.Empty(t, getTorrentFiles(tor, true))
+
+		link, err := os.ReadFile(m.metaFilePath(magnetHash, magnetFileExt))
+		assert.NoError(t, err)
+		assert.Equal(t, magnet.RawLink, string(link))
+	})
+
+	t.Run("get", func(t *testing.T) {
+		assert.NotNil(t, m.get(hash))
+		assert.NotNil(t, m.get(magnetHash))
+		assert.Nil(t, m.get("fedcba9876543210fedcba9876543210fedcba98"))
+		assert.Nil(t, m.get("invalid"))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		torrents := m.list()
+		assert.Len(t, torrents, 2)
+		// latest first
+		assert.Equal(t, magnetHash, torrents[0].InfoHash().HexString())
+		assert.Equal(t, hash, torrents[1].InfoHash().HexString())
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		restored := newTestTorrentManager(t, filepath.Join(t.TempDir(), "restored"))
+		for _, name := range []string{hash + torrentFileExt, magnetHash + magnetFileExt} {
+			data, err := os.ReadFile(filepath.Join(m.metaDir, name))
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(restored.metaDir, name), data, 0644))
+		}
+		assert.NoError(t, restored.restore())
+		assert.Len(t, restored.list(), 2)
+		tor := restored.get(hash)
+		assert.NotNil(t, tor)
+		assert.True(t, hasTorrentInfo(tor))
+		assert.NotNil(t, restored.get(magnetHash))
+		getTorrentManager = func() (*torrentManager, error) {
+			return m, nil
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		for _, h := range []string{hash, magnetHash} {
+			tor := m.get(h)
+			require.NotNil(t, tor)
+			assert.NoError(t, m.remove(tor))
+			assert.Nil(t, m.get(h))
+			assert.True(t, m.getAddedAt(h).IsZero())
+			for _, ext := range []string{torrentFileExt, magnetFileExt} {
+				_, err := os.Stat(m.metaFilePath(h, ext))
+				assert.ErrorIs(t, err, os.ErrNotExist)
+			}
+			_, err := os.Stat(filepath.Join(m.storageDir, h))
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		}
+		assert.Empty(t, m.list())
+	})
+}
+
+func TestServeTorrentFile(t *testing.T) {
+	m := newTestTorrentManager(t, t.TempDir())
+
+	content := make([]byte, 100*1024)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	mi := createTestTorrent(t, m, "Movie", map[string][]byte{
+		"Movie.mkv": content,
+	})
+	tor := addTestTorrent(t, m, mi)
+	hash := tor.InfoHash().HexString()
+
+	t.Run("open", func(t *testing.T) {
+		stream, err := OpenTorrentFile(context.Background(), hash, "/Movie/Movie.mkv")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Movie.mkv", stream.Name)
+			assert.Equal(t, int64(len(content)), stream.Size)
+			stream.Close()
+		}
+
+		_, err = OpenTorrentFile(context.Background(), hash, "/Movie/missing.mkv")
+		var serr *core.StoreError
+		assert.ErrorAs(t, err, &serr)
+		assert.Equal(t, http.StatusNotFound, serr.StatusCode)
+
+		_, err = OpenTorrentFile(context.Background(), "fedcba9876543210fedcba9876543210fedcba98", "/Movie/Movie.mkv")
+		assert.ErrorAs(t, err, &serr)
+	})
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := ServeTorrentFile(w, r, hash, "/Movie/Movie.mkv"); !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer upstream.Close()
+
+	for _, tc := range []struct {
+		name       string
+		rangeValue string
+		statusCode int
+		start, end int
+	}{
+		{"full", "", http.StatusOK, 0, len(content)},
+		{"start", "bytes=0-99", http.StatusPartialContent, 0, 100},
+		{"across pieces", "bytes=16000-40000", http.StatusPartialContent, 16000, 40001},
+		{"open ended", "bytes=90000-", http.StatusPartialContent, 90000, len(content)},
+		{"suffix", "bytes=-1024", http.StatusPartialContent, len(content) - 1024, len(content)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, upstream.URL, nil)
+			require.NoError(t, err)
+			if tc.rangeValue != "" {
+				req.Header.Set("Range", tc.rangeValue)
+			}
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.statusCode, res.StatusCode)
+			assert.Equal(t, "bytes", res.Header.Get("Accept-Ranges"))
+			assert.Equal(t, content[tc.start:tc.end], body)
+		})
+	}
+
+	t.Run("unsatisfiable", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, upstream.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Range", "bytes=200000-")
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, res.StatusCode)
+	})
+}
@@ -15,8 +15,9 @@ import (
 )
 
 var (
-	_ store.Store     = (*StoreClient)(nil)
-	_ store.NewzStore = (*StoreClient)(nil)
+	_ store.Store      = (*StoreClient)(nil)
+	_ store.NewzStore  = (*StoreClient)(nil)
+	_ store.WebDLStore = (*StoreClient)(nil)
 )
 
 type StoreClientConfig struct {
@@ -689,3 +690,189 @@ func (c *StoreClient) GenerateNewzLink(params *store.GenerateNewzLinkParams) (*s
 	data := &store.GenerateNewzLinkData{Link: res.Data.Link}
 	return data, nil
 }
+
+func getWebDLStatus(dl *WebDLDownload) store.WebDLStatus {
+	if dl.DownloadFinished && dl.DownloadPresent {
+		return store.WebDLStatusDownloaded
+	}
+	if dl.DownloadState == TorrentDownloadStateDownloading || dl.Progress > 0 {
+		return store.WebDLStatusDownloading
+	}
+	return store.WebDLStatusUnknown
+}
+
+func (c *StoreClient) CheckWebDL(params *store.CheckWebDLParams) (*store.CheckWebDLData, error) {
+	hashes := make([]string, len(params.Links))
+	for i, link := range params.Links {
+		hashes[i] = util.MD5Hash(link)
+	}
+	cwcParams := &CheckWebDLCachedParams{
+		Hashes: hashes,
+	}
+	cwcParams.APIKey = params.APIKey
+	res, err := c.client.CheckWebDLCached(cwcParams)
+	if err != nil {
+		return nil, err
+	}
+
+	itemByHash := map[string]CheckWebDLCachedDataItem{}
+	for _, item := range res.Data {
+		itemByHash[item.Hash] = item
+	}
+
+	data := &store.CheckWebDLData{
+		Items: []store.CheckWebDLDataItem{},
+	}
+	for i, link := range params.Links {
+		item := store.CheckWebDLDataItem{
+			Link:   link,
+			Status: store.WebDLStatusUnknown,
+		}
+		if w, ok := itemByHash[hashes[i]]; ok {
+			item.Name = w.Name
+			item.Size = w.Size
+			item.Status = store.WebDLStatusCached
+		}
+		data.Items = append(data.Items, item)
+	}
+	return data, nil
+}
+
+func (c *StoreClient) AddWebDL(params *store.AddWebDLParams) (*store.AddWebDLData, error) {
+	res, err := c.client.CreateWebDLDownload(&CreateWebDLDownloadParams{
+		Ctx:      params.Ctx,
+		Link:     params.Link,
+		Password: params.Password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := &store.AddWebDLData{
+		Id:      strconv.Itoa(res.Data.UsenetDownloadId),
+		Link:    params.Link,
+		Status:  store.WebDLStatusQueued,
+		Files:   []store.WebDLFile{},
+		AddedAt: time.Now().UTC(),
+	}
+	return data, nil
+}
+
+func (c *StoreClient) GetWebDL(params *store.GetWebDLParams) (*store.GetWebDLData, error) {
+	id, err := strconv.Atoi(params.Id)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.client.GetWebDLDownload(&GetWebDLDownloadParams{
+		Ctx:         params.Ctx,
+		Id:          id,
+		BypassCache: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.Data.Id == 0 {
+		error := core.NewAPIError("not found")
+		error.StatusCode = http.StatusNotFound
+		error.StoreName = string(store.StoreNameTorBox)
+		return nil, error
+	}
+	dl := &res.Data
+	data := &store.GetWebDLData{
+		Id:      strconv.Itoa(dl.Id),
+		Link:    dl.OriginalUrl,
+		Name:    dl.Name,
+		Size:    dl.Size,
+		Status:  getWebDLStatus(dl),
+		Files:   []store.WebDLFile{},
+		AddedAt: dl.GetAddedAt(),
+	}
+	for i := range dl.Files {
+		f := &dl.Files[i]
+		file := store.WebDLFile{
+			Idx:  f.Id,
+			Link: LockedFileLink("").Create(dl.Id, f.Id),
+			Path: "/" + f.Name,
+			Name: f.ShortName,
+			Size: f.Size,
+		}
+		data.Files = append(data.Files, file)
+	}
+	return data, nil
+}
+
+func (c *StoreClient) ListWebDLs(params *store.ListWebDLsParams) (*store.ListWebDLsData, error) {
+	res, err := c.client.ListWebDLDownload(&ListWebDLDownloadParams{
+		Ctx:         params.Ctx,
+		BypassCache: true,
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := &store.ListWebDLsData{
+		Items:      []store.ListWebDLsDataItem{},
+		TotalItems: 0,
+	}
+	for i := range res.Data {
+		dl := &res.Data[i]
+		item := store.ListWebDLsDataItem{
+			Id:      strconv.Itoa(dl.Id),
+			Link:    dl.OriginalUrl,
+			Name:    dl.Name,
+			Size:    dl.Size,
+			Status:  getWebDLStatus(dl),
+			AddedAt: dl.GetAddedAt(),
+		}
+		data.Items = append(data.Items, item)
+	}
+	count := len(data.Items)
+	// torbox returns 1 extra item
+	if count > params.Limit {
+		data.Items = data.Items[0:params.Limit]
+		count = params.Limit
+	}
+	data.TotalItems = params.Offset + count
+	if count == params.Limit {
+		data.TotalItems += 1
+	}
+	return data, nil
+}
+
+func (c *StoreClient) RemoveWebDL(params *store.RemoveWebDLParams) (*store.RemoveWebDLData, error) {
+	id, err := strconv.Atoi(params.Id)
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.client.ControlWebDLDownload(&ControlWebDLDownloadParams{
+		Ctx:       params.Ctx,
+		WebDLId:   id,
+		Operation: ControlWebDLDownloadOperationDelete,
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := &store.RemoveWebDLData{Id: params.Id}
+	return data, nil
+}
+
+func (c *StoreClient) GenerateWebDLLink(params *store.GenerateWebDLLinkParams) (*store.GenerateWebDLLinkData, error) {
+	webdlId, fileId, err := LockedFileLink(params.Link).Parse()
+	if err != nil {
+		error := core.NewAPIError("invalid link")
+		error.StatusCode = http.StatusBadRequest
+		error.Cause = err
+		return nil, error
+	}
+	res, err := c.client.RequestWebDLDownloadLink(&RequestWebDLDownloadLinkParams{
+		Ctx:     params.Ctx,
+		WebDLId: webdlId,
+		FileId:  fileId,
+		UserIP:  params.ClientIP,
+	})
+	if err != nil {
+		return nil, err
+	}
+	data := &store.GenerateWebDLLinkData{Link: res.Data.Link}
+	return data, nil
+}
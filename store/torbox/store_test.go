@@ -0,0 +1,26 @@
+package torbox
+
+import (
+	"testing"
+
+	"github.com/MunifTanjim/stremthru/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWebDLStatus(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		dl     WebDLDownload
+		status store.WebDLStatus
+	}{
+		{"downloaded", WebDLDownload{DownloadFinished: true, DownloadPresent: true}, store.WebDLStatusDownloaded},
+		{"finished but not present", WebDLDownload{DownloadFinished: true}, store.WebDLStatusUnknown},
+		{"downloading", WebDLDownload{DownloadState: TorrentDownloadStateDownloading}, store.WebDLStatusDownloading},
+		{"progressing", WebDLDownload{DownloadState: TorrentDownloadStatePaused, Progress: 0.5}, store.WebDLStatusDownloading},
+		{"stalled", WebDLDownload{DownloadState: TorrentDownloadStateStalled}, store.WebDLStatusUnknown},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.status, getWebDLStatus(&tc.dl))
+		})
+	}
+}